@@ -19,6 +19,9 @@ var (
 		Usage: "Manage Gitea Actions",
 		Subcommands: []*cli.Command{
 			subcmdActionsGenRunnerToken,
+			subcmdActionsBackfill,
+			subcmdActionsReplay,
+			subcmdActionsLoadTest,
 		},
 	}
 
@@ -36,6 +39,104 @@ var (
 			},
 		},
 	}
+
+	subcmdActionsBackfill = &cli.Command{
+		Name:   "backfill",
+		Usage:  "Synthesize push events for existing tags and commits of a repository to trigger their workflows",
+		Action: runBackfillActionRuns,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "repo",
+				Usage:    "{owner}/{repo} - the repository to backfill",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "user",
+				Usage:    "the name of the user who pushes the tags and commits",
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "tag",
+				Usage: "glob pattern of the tags to backfill, like \"v1.*\", could be given multiple times",
+			},
+			&cli.StringFlag{
+				Name:  "branch",
+				Usage: "the branch whose commits are backfilled",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "the commits of the branch after this commit are backfilled, required if --branch is given",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "the last commit of the branch to backfill, the head of the branch by default",
+			},
+		},
+	}
+
+	subcmdActionsReplay = &cli.Command{
+		Name:   "replay",
+		Usage:  "Replay the stored events of the runs of a repository through the current workflow parsers and diff their jobs, without creating any runs",
+		Action: runReplayActionRuns,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "repo",
+				Usage:    "{owner}/{repo} - the repository whose runs are replayed",
+				Required: true,
+			},
+			&cli.Int64Flag{
+				Name:  "run",
+				Usage: "the number of the run to replay, the latest runs are replayed if it isn't given",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Value: 20,
+				Usage: "how many of the latest runs are replayed",
+			},
+		},
+	}
+
+	subcmdActionsLoadTest = &cli.Command{
+		Name:   "load-test",
+		Usage:  "Benchmark the scheduler, the database and the log ingestion with synthetic runs picked by fake runners, without running anything",
+		Action: runActionsLoadTest,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "repo",
+				Usage:    "{owner}/{repo} - the repository of the synthetic runs, a dedicated one is recommended",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "user",
+				Usage:    "the name of the user who triggers the synthetic runs",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  "runs",
+				Value: 100,
+				Usage: "how many synthetic runs are created",
+			},
+			&cli.IntFlag{
+				Name:  "jobs",
+				Value: 5,
+				Usage: "how many jobs each synthetic run has",
+			},
+			&cli.IntFlag{
+				Name:  "runners",
+				Value: 10,
+				Usage: "how many fake runners pick the jobs concurrently",
+			},
+			&cli.IntFlag{
+				Name:  "log-lines",
+				Value: 100,
+				Usage: "how many log lines each task uploads",
+			},
+			&cli.BoolFlag{
+				Name:  "keep",
+				Usage: "keep the synthetic runs and the fake runners to inspect them, instead of deleting them at the end",
+			},
+		},
+	}
 )
 
 func runGenerateActionsRunnerToken(c *cli.Context) error {
@@ -53,3 +154,64 @@ func runGenerateActionsRunnerToken(c *cli.Context) error {
 	_, _ = fmt.Printf("%s\n", respText.Text)
 	return nil
 }
+
+func runBackfillActionRuns(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setting.MustInstalled()
+
+	respText, extra := private.BackfillActionRuns(ctx, &private.BackfillActionRunsRequest{
+		Repo:   c.String("repo"),
+		Doer:   c.String("user"),
+		Tags:   c.StringSlice("tag"),
+		Branch: c.String("branch"),
+		Since:  c.String("since"),
+		Until:  c.String("until"),
+	})
+	if extra.HasError() {
+		return handleCliResponseExtra(extra)
+	}
+	_, _ = fmt.Printf("%s\n", respText.Text)
+	return nil
+}
+
+func runReplayActionRuns(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setting.MustInstalled()
+
+	respText, extra := private.ReplayActionRuns(ctx, &private.ReplayActionRunsRequest{
+		Repo:  c.String("repo"),
+		Run:   c.Int64("run"),
+		Limit: c.Int("limit"),
+	})
+	if extra.HasError() {
+		return handleCliResponseExtra(extra)
+	}
+	_, _ = fmt.Printf("%s\n", respText.Text)
+	return nil
+}
+
+func runActionsLoadTest(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setting.MustInstalled()
+
+	respText, extra := private.RunActionsLoadTest(ctx, &private.ActionsLoadTestRequest{
+		Repo:       c.String("repo"),
+		Doer:       c.String("user"),
+		Runs:       c.Int("runs"),
+		JobsPerRun: c.Int("jobs"),
+		Runners:    c.Int("runners"),
+		LogLines:   c.Int("log-lines"),
+		Keep:       c.Bool("keep"),
+	})
+	if extra.HasError() {
+		return handleCliResponseExtra(extra)
+	}
+	_, _ = fmt.Printf("%s\n", respText.Text)
+	return nil
+}
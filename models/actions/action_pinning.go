@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strconv"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// IsPinnedActionsRequiredByOwner returns whether the owner requires its workflows to pin third-party actions to full commit SHAs,
+// it doesn't take the instance setting into account.
+func IsPinnedActionsRequiredByOwner(ctx context.Context, ownerID int64) (bool, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRequirePinnedActions)
+	if err != nil {
+		return false, err
+	}
+	required, _ := strconv.ParseBool(value)
+	return required, nil
+}
+
+// IsPinnedActionsRequired returns whether the workflows of the owner must pin third-party actions to full commit SHAs,
+// it's required if either the instance or the owner requires it.
+func IsPinnedActionsRequired(ctx context.Context, ownerID int64) (bool, error) {
+	if setting.Actions.RequirePinnedActions {
+		return true, nil
+	}
+	return IsPinnedActionsRequiredByOwner(ctx, ownerID)
+}
+
+// SetPinnedActionsRequired sets whether the owner requires its workflows to pin third-party actions to full commit SHAs
+func SetPinnedActionsRequired(ctx context.Context, ownerID int64, required bool) error {
+	if !required {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRequirePinnedActions)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRequirePinnedActions, strconv.FormatBool(required))
+}
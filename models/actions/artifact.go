@@ -81,6 +81,34 @@ func CreateArtifact(ctx context.Context, t *ActionTask, artifactName, artifactPa
 	return artifact, nil
 }
 
+// IsSingleZip returns whether the artifact is uploaded by the v4 backend as a single zip file, which could be downloaded as it is,
+// the artifacts of the v1-v3 backend are stored as multiple individual files, which need to be zipped for download
+func (a *ActionArtifact) IsSingleZip() bool {
+	return a.ArtifactName+".zip" == a.ArtifactPath && a.ContentEncoding == "application/zip"
+}
+
+// GetUploadedArtifactFiles returns the files of the artifact of the run,
+// the error is util.ErrNotExist if the artifact doesn't exist or hasn't been uploaded completely
+func GetUploadedArtifactFiles(ctx context.Context, runID int64, name string) ([]*ActionArtifact, error) {
+	artifacts, err := db.Find[ActionArtifact](ctx, FindArtifactsOptions{
+		RunID:        runID,
+		ArtifactName: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts) == 0 {
+		return nil, util.NewNotExistErrorf("artifact not found")
+	}
+	// if artifacts status is not uploaded-confirmed, treat it as not found
+	for _, art := range artifacts {
+		if art.Status != int64(ArtifactStatusUploadConfirmed) {
+			return nil, util.NewNotExistErrorf("artifact not found")
+		}
+	}
+	return artifacts, nil
+}
+
 func getArtifactByNameAndPath(ctx context.Context, runID int64, name, fpath string) (*ActionArtifact, error) {
 	var art ActionArtifact
 	has, err := db.GetEngine(ctx).Where("run_id = ? AND artifact_name = ? AND artifact_path = ?", runID, name, fpath).Get(&art)
@@ -130,6 +158,7 @@ type ActionArtifactMeta struct {
 	ArtifactName string
 	FileSize     int64
 	Status       ArtifactStatus
+	ExpiredUnix  timeutil.TimeStamp
 }
 
 // ListUploadedArtifactsMeta returns all uploaded artifacts meta of a run
@@ -138,7 +167,7 @@ func ListUploadedArtifactsMeta(ctx context.Context, runID int64) ([]*ActionArtif
 	return arts, db.GetEngine(ctx).Table("action_artifact").
 		Where("run_id=? AND (status=? OR status=?)", runID, ArtifactStatusUploadConfirmed, ArtifactStatusExpired).
 		GroupBy("artifact_name").
-		Select("artifact_name, sum(file_size) as file_size, max(status) as status").
+		Select("artifact_name, sum(file_size) as file_size, max(status) as status, max(expired_unix) as expired_unix").
 		Find(&arts)
 }
 
@@ -174,3 +203,18 @@ func SetArtifactDeleted(ctx context.Context, artifactID int64) error {
 	_, err := db.GetEngine(ctx).ID(artifactID).Cols("status").Update(&ActionArtifact{Status: int64(ArtifactStatusDeleted)})
 	return err
 }
+
+// FindStoredArtifacts returns the artifacts whose files are supposed to be in the storage, with only their ids, storage paths and statuses
+func FindStoredArtifacts(ctx context.Context) ([]*ActionArtifact, error) {
+	var arts []*ActionArtifact
+	return arts, db.GetEngine(ctx).Cols("id", "storage_path", "status").
+		Where("storage_path <> ''").
+		In("status", ArtifactStatusUploadPending, ArtifactStatusUploadConfirmed, ArtifactStatusPendingDeletion).
+		Find(&arts)
+}
+
+// SetArtifactsExpired sets the uploaded artifacts to expired by their ids
+func SetArtifactsExpired(ctx context.Context, artifactIDs []int64) error {
+	_, err := db.GetEngine(ctx).In("id", artifactIDs).And("status = ?", ArtifactStatusUploadConfirmed).Cols("status").Update(&ActionArtifact{Status: int64(ArtifactStatusExpired)})
+	return err
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// GetOwnerBadgeTheme returns the badge theme of the owner, or nil if the default badges are rendered
+func GetOwnerBadgeTheme(ctx context.Context, ownerID int64) (*repo_model.ActionsBadgeTheme, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsBadgeTheme)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	theme := &repo_model.ActionsBadgeTheme{}
+	if err := json.Unmarshal([]byte(value), theme); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// SetOwnerBadgeTheme replaces the badge theme of the owner, nil to render the default badges
+func SetOwnerBadgeTheme(ctx context.Context, ownerID int64, theme *repo_model.ActionsBadgeTheme) error {
+	if theme == nil {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsBadgeTheme)
+	}
+	value, err := json.Marshal(theme)
+	if err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsBadgeTheme, string(value))
+}
+
+// GetBadgeTheme returns the badge theme of the repository, the one of the repository takes precedence over the one of the owner,
+// it's nil if neither is configured.
+func GetBadgeTheme(ctx context.Context, repo *repo_model.Repository) (*repo_model.ActionsBadgeTheme, error) {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil && !repo_model.IsErrUnitTypeNotExist(err) {
+		return nil, err
+	}
+	if cfgUnit != nil {
+		if theme := cfgUnit.ActionsConfig().BadgeTheme; theme != nil {
+			return theme, nil
+		}
+	}
+	return GetOwnerBadgeTheme(ctx, repo.OwnerID)
+}
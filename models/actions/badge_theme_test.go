@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBadgeTheme(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// repo 1 is owned by user 2 and has Actions enabled
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	theme, err := GetBadgeTheme(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.Nil(t, theme)
+
+	assert.NoError(t, SetOwnerBadgeTheme(db.DefaultContext, repo.OwnerID, &repo_model.ActionsBadgeTheme{Style: "flat", Colors: map[string]string{"success": "#00ff00"}}))
+	defer func() {
+		assert.NoError(t, SetOwnerBadgeTheme(db.DefaultContext, repo.OwnerID, nil))
+	}()
+	theme, err = GetBadgeTheme(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.Equal(t, "flat", theme.Style)
+	assert.Equal(t, "#00ff00", theme.Colors["success"])
+
+	// the theme of the repository takes precedence
+	cfgUnit, err := repo.GetUnit(db.DefaultContext, unit.TypeActions)
+	assert.NoError(t, err)
+	cfgUnit.ActionsConfig().BadgeTheme = &repo_model.ActionsBadgeTheme{Style: "flat-square", LabelColor: "#333"}
+	assert.NoError(t, repo_model.UpdateRepoUnit(db.DefaultContext, cfgUnit))
+	repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	theme, err = GetBadgeTheme(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.Equal(t, "flat-square", theme.Style)
+	assert.Equal(t, "#333", theme.LabelColor)
+	assert.Empty(t, theme.Colors)
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ActionBlockedRef represents a ref of an action which is blocked by admins, e.g. because it's compromised.
+// New runs using the action at the ref are refused.
+type ActionBlockedRef struct {
+	ID        int64              `xorm:"pk autoincr"`
+	Action    string             `xorm:"VARCHAR(255) UNIQUE(action_ref) NOT NULL"` // the action name without ref, in lower case
+	Ref       string             `xorm:"VARCHAR(255) UNIQUE(action_ref) NOT NULL"` // empty means all refs of the action
+	Reason    string             `xorm:"TEXT"`
+	CreatorID int64              `xorm:"INDEX"`
+	Created   timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionBlockedRef))
+}
+
+// Matches returns whether the action at the ref is blocked by the blocked ref
+func (b *ActionBlockedRef) Matches(action, ref string) bool {
+	return strings.EqualFold(b.Action, action) && (b.Ref == "" || b.Ref == ref)
+}
+
+// ToUsagesOptions returns the options to find the usages of the blocked ref
+func (b *ActionBlockedRef) ToUsagesOptions(listOptions db.ListOptions) FindActionUsagesOptions {
+	return FindActionUsagesOptions{
+		ListOptions: listOptions,
+		Action:      b.Action,
+		Ref:         b.Ref,
+	}
+}
+
+// FindBlockedRefsOptions represents the options to find the blocked refs of actions
+type FindBlockedRefsOptions struct {
+	db.ListOptions
+	Action string
+}
+
+func (opts FindBlockedRefsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"action": strings.ToLower(opts.Action)})
+	}
+	return cond
+}
+
+func (opts FindBlockedRefsOptions) ToOrders() string {
+	return "action, ref"
+}
+
+// GetBlockedRefByID returns the blocked ref by id
+func GetBlockedRefByID(ctx context.Context, id int64) (*ActionBlockedRef, error) {
+	b, has, err := db.GetByID[ActionBlockedRef](ctx, id)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("blocked ref with id %d: %w", id, util.ErrNotExist)
+	}
+	return b, nil
+}
+
+// CreateBlockedRef blocks the action at the ref, or all refs of the action if the ref is empty
+func CreateBlockedRef(ctx context.Context, b *ActionBlockedRef) error {
+	b.Action = strings.ToLower(strings.TrimSpace(b.Action))
+	b.Ref = strings.TrimSpace(b.Ref)
+	if b.Action == "" {
+		return util.NewInvalidArgumentErrorf("action is required")
+	}
+	exist, err := db.GetEngine(ctx).Where("action=? AND ref=?", b.Action, b.Ref).Exist(new(ActionBlockedRef))
+	if err != nil {
+		return err
+	} else if exist {
+		return fmt.Errorf("blocked ref %s@%s: %w", b.Action, b.Ref, util.ErrAlreadyExist)
+	}
+	return db.Insert(ctx, b)
+}
+
+// DeleteBlockedRef unblocks the blocked ref
+func DeleteBlockedRef(ctx context.Context, id int64) error {
+	n, err := db.DeleteByID[ActionBlockedRef](ctx, id)
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("blocked ref with id %d: %w", id, util.ErrNotExist)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionBlockedRef(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	all := &ActionBlockedRef{Action: "Evil/Action"}
+	require.NoError(t, CreateBlockedRef(db.DefaultContext, all))
+	assert.Equal(t, "evil/action", all.Action)
+	tag := &ActionBlockedRef{Action: "actions/cache", Ref: "v9"}
+	require.NoError(t, CreateBlockedRef(db.DefaultContext, tag))
+
+	assert.ErrorIs(t, CreateBlockedRef(db.DefaultContext, &ActionBlockedRef{Action: "actions/cache", Ref: "v9"}), util.ErrAlreadyExist)
+	assert.ErrorIs(t, CreateBlockedRef(db.DefaultContext, &ActionBlockedRef{Action: " "}), util.ErrInvalidArgument)
+
+	assert.True(t, all.Matches("evil/action", "v1"))
+	assert.True(t, all.Matches("EVIL/action", ""))
+	assert.True(t, tag.Matches("actions/cache", "v9"))
+	assert.False(t, tag.Matches("actions/cache", "v4"))
+	assert.False(t, tag.Matches("actions/checkout", "v9"))
+
+	require.NoError(t, DeleteBlockedRef(db.DefaultContext, tag.ID))
+	assert.ErrorIs(t, DeleteBlockedRef(db.DefaultContext, tag.ID), util.ErrNotExist)
+	_, err := GetBlockedRefByID(db.DefaultContext, tag.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// GetOwnerBotIdentity returns the bot identity of the owner, or nil if the global actions user is used
+func GetOwnerBotIdentity(ctx context.Context, ownerID int64) (*repo_model.ActionsBotIdentity, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsBotIdentity)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	identity := &repo_model.ActionsBotIdentity{}
+	if err := json.Unmarshal([]byte(value), identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// SetOwnerBotIdentity replaces the bot identity of the owner, nil to use the global actions user
+func SetOwnerBotIdentity(ctx context.Context, ownerID int64, identity *repo_model.ActionsBotIdentity) error {
+	if identity == nil {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsBotIdentity)
+	}
+	value, err := json.Marshal(identity)
+	if err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsBotIdentity, string(value))
+}
+
+// GetBotIdentity returns the bot identity of the repository, the one of the repository takes precedence over the one of the owner,
+// it's nil if neither is configured.
+func GetBotIdentity(ctx context.Context, repo *repo_model.Repository) (*repo_model.ActionsBotIdentity, error) {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil && !repo_model.IsErrUnitTypeNotExist(err) {
+		return nil, err
+	}
+	if cfgUnit != nil {
+		if identity := cfgUnit.ActionsConfig().BotIdentity; identity != nil {
+			return identity, nil
+		}
+	}
+	return GetOwnerBotIdentity(ctx, repo.OwnerID)
+}
+
+// ApplyBotIdentity returns the actions user with the bot identity of the repository, other users are returned as they are.
+// The errors are logged and the global actions user is returned, so the callers could always use the result.
+func ApplyBotIdentity(ctx context.Context, repo *repo_model.Repository, u *user_model.User) *user_model.User {
+	if u == nil || repo == nil || !u.IsActions() {
+		return u
+	}
+	identity, err := GetBotIdentity(ctx, repo)
+	if err != nil {
+		log.Error("GetBotIdentity of repo %d: %v", repo.ID, err)
+		return u
+	}
+	return identity.ApplyTo(u)
+}
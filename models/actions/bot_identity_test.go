@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyBotIdentity(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// repo 1 is owned by user 2 and has Actions enabled
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	bot := ApplyBotIdentity(db.DefaultContext, repo, user_model.NewActionsUser())
+	assert.Equal(t, user_model.ActionsFullName, bot.FullName)
+	assert.True(t, bot.KeepEmailPrivate)
+
+	assert.NoError(t, SetOwnerBotIdentity(db.DefaultContext, repo.OwnerID, &repo_model.ActionsBotIdentity{Name: "Owner Bot", Email: "bot@example.com"}))
+	defer func() {
+		assert.NoError(t, SetOwnerBotIdentity(db.DefaultContext, repo.OwnerID, nil))
+	}()
+	bot = ApplyBotIdentity(db.DefaultContext, repo, user_model.NewActionsUser())
+	assert.True(t, bot.IsActions())
+	assert.Equal(t, user_model.ActionsUserName, bot.Name)
+	assert.Equal(t, "Owner Bot", bot.FullName)
+	assert.Equal(t, "bot@example.com", bot.GetEmail())
+	assert.Equal(t, "bot@example.com", bot.AvatarEmail)
+
+	// the other users are kept
+	assert.Same(t, user, ApplyBotIdentity(db.DefaultContext, repo, user))
+
+	// the identity of the repository takes precedence
+	cfgUnit, err := repo.GetUnit(db.DefaultContext, unit.TypeActions)
+	assert.NoError(t, err)
+	cfgUnit.ActionsConfig().BotIdentity = &repo_model.ActionsBotIdentity{Name: "Repo Bot", AvatarEmail: "avatar@example.com"}
+	assert.NoError(t, repo_model.UpdateRepoUnit(db.DefaultContext, cfgUnit))
+	repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	bot = ApplyBotIdentity(db.DefaultContext, repo, user_model.NewActionsUser())
+	assert.Equal(t, "Repo Bot", bot.FullName)
+	assert.True(t, bot.KeepEmailPrivate)
+	assert.Equal(t, "avatar@example.com", bot.AvatarEmail)
+}
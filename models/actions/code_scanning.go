@@ -0,0 +1,237 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// The severities of the code scanning alerts, they are the levels of the SARIF results
+const (
+	CodeScanningSeverityError   = "error"
+	CodeScanningSeverityWarning = "warning"
+	CodeScanningSeverityNote    = "note"
+)
+
+// The states of the code scanning alerts
+const (
+	CodeScanningStateOpen  = "open"  // reported by the latest upload of the tool on the ref
+	CodeScanningStateFixed = "fixed" // not reported by the latest upload of the tool on the ref any more
+)
+
+// CodeScanningResult is a finding of a code scanning tool in an upload of a run, like a result of a SARIF log
+type CodeScanningResult struct {
+	Tool        string
+	RuleID      string
+	Severity    string
+	Message     string
+	Path        string
+	StartLine   int
+	EndLine     int
+	Fingerprint string // identifies the same finding across the runs
+}
+
+// CodeScanningUpload are the results of the tools uploaded by a run, the tools without any results are included,
+// so their alerts could be fixed
+type CodeScanningUpload struct {
+	Tools   []string
+	Results []*CodeScanningResult
+}
+
+// ActionCodeScanningAlert is a finding of a code scanning tool on a ref of a repository, deduplicated across the runs by its fingerprint.
+// It's open while the latest upload of the tool on the ref reports it, or fixed since the first upload which doesn't.
+type ActionCodeScanningAlert struct {
+	ID            int64
+	RepoID        int64              `xorm:"UNIQUE(repo_ref_fingerprint) NOT NULL"`
+	Ref           string             `xorm:"VARCHAR(255) UNIQUE(repo_ref_fingerprint) NOT NULL"`
+	Fingerprint   string             `xorm:"VARCHAR(64) UNIQUE(repo_ref_fingerprint) NOT NULL"`
+	Tool          string             `xorm:"VARCHAR(255) NOT NULL"`
+	RuleID        string             `xorm:"VARCHAR(255)"`
+	Severity      string             `xorm:"VARCHAR(16) NOT NULL"`
+	Message       string             `xorm:"TEXT"`
+	Path          string             `xorm:"VARCHAR(255)"`
+	StartLine     int                `xorm:"NOT NULL DEFAULT 0"`
+	EndLine       int                `xorm:"NOT NULL DEFAULT 0"`
+	State         string             `xorm:"VARCHAR(16) INDEX NOT NULL"`
+	FirstRunIndex int64              `xorm:"NOT NULL DEFAULT 0"` // the run which reported it first
+	LastRunIndex  int64              `xorm:"NOT NULL DEFAULT 0"` // the latest run which reported it
+	FixedRunIndex int64              `xorm:"NOT NULL DEFAULT 0"` // the run which found it fixed, 0 if it's open
+	Fixed         timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	Created       timeutil.TimeStamp `xorm:"created"`
+	Updated       timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionCodeScanningAlert))
+}
+
+// IsOpen returns whether the latest upload of the tool on the ref still reports the alert
+func (alert *ActionCodeScanningAlert) IsOpen() bool {
+	return alert.State == CodeScanningStateOpen
+}
+
+// CodeScanningUploadStats are the changes of the alerts made by an upload of the results of a run
+type CodeScanningUploadStats struct {
+	Results  int // the results after the deduplication
+	New      int // the alerts never reported on the ref before
+	Reopened int // the fixed alerts reported again
+	Fixed    int // the open alerts of the tools of the upload which aren't reported any more
+}
+
+// UploadCodeScanningResults updates the alerts of the ref of the run with the results of the tools uploaded by the run.
+// Each upload replaces the previous ones of its tools on the ref, so the open alerts of the tools missing from it are fixed,
+// while the alerts of the other tools are kept.
+func UploadCodeScanningResults(ctx context.Context, run *ActionRun, upload *CodeScanningUpload) (*CodeScanningUploadStats, error) {
+	stats := &CodeScanningUploadStats{Results: len(upload.Results)}
+	if len(upload.Tools) == 0 {
+		return stats, nil
+	}
+	reported := make(map[string]*CodeScanningResult, len(upload.Results))
+	for _, result := range upload.Results {
+		reported[result.Fingerprint] = result
+	}
+
+	return stats, db.WithTx(ctx, func(ctx context.Context) error {
+		// the fingerprints include the tools, so the alerts of the other tools are never reported by the upload
+		var existing []*ActionCodeScanningAlert
+		if err := db.GetEngine(ctx).Where(builder.Eq{"repo_id": run.RepoID, "ref": run.Ref}).
+			And(builder.In("tool", upload.Tools)).
+			Find(&existing); err != nil {
+			return err
+		}
+
+		now := timeutil.TimeStampNow()
+		known := make(map[string]bool, len(existing))
+		for _, alert := range existing {
+			known[alert.Fingerprint] = true
+			result, ok := reported[alert.Fingerprint]
+			if !ok {
+				if !alert.IsOpen() {
+					continue
+				}
+				alert.State = CodeScanningStateFixed
+				alert.FixedRunIndex = run.Index
+				alert.Fixed = now
+				stats.Fixed++
+			} else {
+				if !alert.IsOpen() {
+					stats.Reopened++
+				}
+				fillCodeScanningAlert(alert, result)
+				alert.State = CodeScanningStateOpen
+				alert.LastRunIndex = run.Index
+				alert.FixedRunIndex = 0
+				alert.Fixed = 0
+			}
+			if _, err := db.GetEngine(ctx).ID(alert.ID).AllCols().Update(alert); err != nil {
+				return err
+			}
+		}
+
+		for _, result := range upload.Results {
+			if known[result.Fingerprint] {
+				continue
+			}
+			alert := &ActionCodeScanningAlert{
+				RepoID:        run.RepoID,
+				Ref:           run.Ref,
+				Fingerprint:   result.Fingerprint,
+				State:         CodeScanningStateOpen,
+				FirstRunIndex: run.Index,
+				LastRunIndex:  run.Index,
+			}
+			fillCodeScanningAlert(alert, result)
+			if err := db.Insert(ctx, alert); err != nil {
+				return err
+			}
+			stats.New++
+		}
+		return nil
+	})
+}
+
+func fillCodeScanningAlert(alert *ActionCodeScanningAlert, result *CodeScanningResult) {
+	alert.Tool = base.TruncateString(result.Tool, 255)
+	alert.RuleID = base.TruncateString(result.RuleID, 255)
+	alert.Severity = result.Severity
+	alert.Message = result.Message
+	alert.Path = base.TruncateString(result.Path, 255)
+	alert.StartLine = result.StartLine
+	alert.EndLine = result.EndLine
+}
+
+type FindCodeScanningAlertsOptions struct {
+	db.ListOptions
+	RepoID   int64
+	Ref      string
+	State    string
+	Tool     string
+	Severity string
+}
+
+func (opts FindCodeScanningAlertsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Ref != "" {
+		cond = cond.And(builder.Eq{"ref": opts.Ref})
+	}
+	if opts.State != "" {
+		cond = cond.And(builder.Eq{"state": opts.State})
+	}
+	if opts.Tool != "" {
+		cond = cond.And(builder.Eq{"tool": opts.Tool})
+	}
+	if opts.Severity != "" {
+		cond = cond.And(builder.Eq{"severity": opts.Severity})
+	}
+	return cond
+}
+
+func (opts FindCodeScanningAlertsOptions) ToOrders() string {
+	return "`id` ASC"
+}
+
+// CompareCodeScanningAlerts compares the alerts of the head ref of a pull request to the ones of its base ref for the gating.
+// The new alerts are open on the head ref but not on the base ref. The fixed alerts are open on the base ref
+// but not on the head ref, only the tools which have uploaded to the head ref are compared, so the ones which haven't run aren't taken as fixed.
+func CompareCodeScanningAlerts(ctx context.Context, repoID int64, baseRef, headRef string) (newAlerts, fixedAlerts []*ActionCodeScanningAlert, err error) {
+	var base, head []*ActionCodeScanningAlert
+	if err := db.GetEngine(ctx).Where(builder.Eq{"repo_id": repoID, "ref": baseRef, "state": CodeScanningStateOpen}).Asc("id").Find(&base); err != nil {
+		return nil, nil, err
+	}
+	if err := db.GetEngine(ctx).Where(builder.Eq{"repo_id": repoID, "ref": headRef}).Asc("id").Find(&head); err != nil {
+		return nil, nil, err
+	}
+
+	baseOpen := make(map[string]bool, len(base))
+	for _, alert := range base {
+		baseOpen[alert.Fingerprint] = true
+	}
+	headOpen := make(map[string]bool, len(head))
+	headTools := make(map[string]bool)
+	for _, alert := range head {
+		headTools[alert.Tool] = true
+		if !alert.IsOpen() {
+			continue
+		}
+		headOpen[alert.Fingerprint] = true
+		if !baseOpen[alert.Fingerprint] {
+			newAlerts = append(newAlerts, alert)
+		}
+	}
+	for _, alert := range base {
+		if headTools[alert.Tool] && !headOpen[alert.Fingerprint] {
+			fixedAlerts = append(fixedAlerts, alert)
+		}
+	}
+	return newAlerts, fixedAlerts, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadCodeScanningResults(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	run1 := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	run2 := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 792})
+	result := func(tool, fingerprint string) *CodeScanningResult {
+		return &CodeScanningResult{Tool: tool, RuleID: "R1", Severity: CodeScanningSeverityError, Fingerprint: fingerprint}
+	}
+
+	stats, err := UploadCodeScanningResults(db.DefaultContext, run1, &CodeScanningUpload{
+		Tools:   []string{"gosec", "semgrep"},
+		Results: []*CodeScanningResult{result("gosec", "a"), result("gosec", "b"), result("semgrep", "c")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CodeScanningUploadStats{Results: 3, New: 3}, stats)
+
+	// the upload of gosec fixes "a" and keeps the alert of semgrep
+	stats, err = UploadCodeScanningResults(db.DefaultContext, run2, &CodeScanningUpload{
+		Tools:   []string{"gosec"},
+		Results: []*CodeScanningResult{result("gosec", "b"), result("gosec", "d")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CodeScanningUploadStats{Results: 2, New: 1, Fixed: 1}, stats)
+
+	a := unittest.AssertExistsAndLoadBean(t, &ActionCodeScanningAlert{RepoID: 4, Fingerprint: "a"})
+	assert.Equal(t, CodeScanningStateFixed, a.State)
+	assert.EqualValues(t, run2.Index, a.FixedRunIndex)
+	b := unittest.AssertExistsAndLoadBean(t, &ActionCodeScanningAlert{RepoID: 4, Fingerprint: "b"})
+	assert.True(t, b.IsOpen())
+	assert.EqualValues(t, run1.Index, b.FirstRunIndex)
+	assert.EqualValues(t, run2.Index, b.LastRunIndex)
+	c := unittest.AssertExistsAndLoadBean(t, &ActionCodeScanningAlert{RepoID: 4, Fingerprint: "c"})
+	assert.True(t, c.IsOpen())
+
+	// reporting "a" again reopens it
+	stats, err = UploadCodeScanningResults(db.DefaultContext, run2, &CodeScanningUpload{
+		Tools:   []string{"gosec"},
+		Results: []*CodeScanningResult{result("gosec", "a"), result("gosec", "b"), result("gosec", "d")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CodeScanningUploadStats{Results: 3, Reopened: 1}, stats)
+
+	alerts, err := db.Find[ActionCodeScanningAlert](db.DefaultContext, FindCodeScanningAlertsOptions{RepoID: 4, Ref: "refs/heads/master", State: CodeScanningStateOpen})
+	require.NoError(t, err)
+	assert.Len(t, alerts, 4)
+}
+
+func TestCompareCodeScanningAlerts(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	base := &ActionRun{RepoID: 4, Index: 187, Ref: "refs/heads/master"}
+	head := &ActionRun{RepoID: 4, Index: 188, Ref: "refs/pull/1/head"}
+	result := func(tool, fingerprint string) *CodeScanningResult {
+		return &CodeScanningResult{Tool: tool, Severity: CodeScanningSeverityWarning, Fingerprint: fingerprint}
+	}
+	_, err := UploadCodeScanningResults(db.DefaultContext, base, &CodeScanningUpload{
+		Tools:   []string{"gosec", "semgrep"},
+		Results: []*CodeScanningResult{result("gosec", "a"), result("gosec", "b"), result("semgrep", "c")},
+	})
+	require.NoError(t, err)
+	_, err = UploadCodeScanningResults(db.DefaultContext, head, &CodeScanningUpload{
+		Tools:   []string{"gosec"},
+		Results: []*CodeScanningResult{result("gosec", "b"), result("gosec", "d")},
+	})
+	require.NoError(t, err)
+
+	newAlerts, fixedAlerts, err := CompareCodeScanningAlerts(db.DefaultContext, 4, "refs/heads/master", "refs/pull/1/head")
+	require.NoError(t, err)
+	require.Len(t, newAlerts, 1)
+	assert.Equal(t, "d", newAlerts[0].Fingerprint)
+	// semgrep hasn't run on the head, so "c" isn't taken as fixed
+	require.Len(t, fixedAlerts, 1)
+	assert.Equal(t, "a", fixedAlerts[0].Fingerprint)
+}
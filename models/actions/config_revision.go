@@ -0,0 +1,89 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ActionConfigRevision is a revision of the Actions settings of a repository, it's recorded whenever they change,
+// so the settings could be reconstructed as of a time, like when a bad deployment ran.
+type ActionConfigRevision struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX(repo_created) NOT NULL"`
+	Enabled     bool               `xorm:"NOT NULL DEFAULT false"` // whether Actions were enabled
+	Config      string             `xorm:"TEXT"`                   // the repo_model.ActionsConfig in JSON, empty if Actions were disabled
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX(repo_created) NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionConfigRevision))
+}
+
+// ActionsConfig returns the Actions settings of the revision, or nil if Actions were disabled
+func (r *ActionConfigRevision) ActionsConfig() *repo_model.ActionsConfig {
+	if !r.Enabled {
+		return nil
+	}
+	cfg := &repo_model.ActionsConfig{}
+	if r.Config != "" {
+		if err := cfg.FromDB([]byte(r.Config)); err != nil {
+			log.Error("Invalid config of Actions config revision %d: %v", r.ID, err)
+		}
+	}
+	return cfg
+}
+
+// RecordConfigRevision records the current Actions settings of the repository, cfg is nil if Actions are disabled.
+// Nothing is recorded if they are the same as the latest revision.
+func RecordConfigRevision(ctx context.Context, repoID int64, cfg *repo_model.ActionsConfig) error {
+	r := &ActionConfigRevision{
+		RepoID:      repoID,
+		Enabled:     cfg != nil,
+		CreatedUnix: timeutil.TimeStampNow(),
+	}
+	if cfg != nil {
+		bs, err := cfg.ToDB()
+		if err != nil {
+			return err
+		}
+		r.Config = string(bs)
+	}
+
+	latest := &ActionConfigRevision{}
+	has, err := db.GetEngine(ctx).Where("repo_id=?", repoID).Desc("created_unix", "id").Get(latest)
+	if err != nil {
+		return err
+	}
+	if has && latest.Enabled == r.Enabled && latest.Config == r.Config {
+		return nil
+	}
+	return db.Insert(ctx, r)
+}
+
+// GetConfigRevisionAt returns the revision of the Actions settings of the repository in effect at the time,
+// it's nil if they weren't recorded yet at the time.
+func GetConfigRevisionAt(ctx context.Context, repoID int64, at timeutil.TimeStamp) (*ActionConfigRevision, error) {
+	r := &ActionConfigRevision{}
+	has, err := db.GetEngine(ctx).Where("repo_id=? AND created_unix<=?", repoID, at).Desc("created_unix", "id").Get(r)
+	if err != nil || !has {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetFirstConfigRevision returns the first recorded revision of the Actions settings of the repository, or nil if none is
+func GetFirstConfigRevision(ctx context.Context, repoID int64) (*ActionConfigRevision, error) {
+	r := &ActionConfigRevision{}
+	has, err := db.GetEngine(ctx).Where("repo_id=?", repoID).Asc("created_unix", "id").Get(r)
+	if err != nil || !has {
+		return nil, err
+	}
+	return r, nil
+}
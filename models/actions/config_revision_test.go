@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigRevisions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) timeutil.TimeStamp {
+		return timeutil.TimeStamp(start.Add(time.Duration(hours) * time.Hour).Unix())
+	}
+	defer timeutil.MockUnset()
+
+	timeutil.MockSet(at(0).AsTime())
+	assert.NoError(t, RecordConfigRevision(ctx, 4, &repo_model.ActionsConfig{}))
+	timeutil.MockSet(at(1).AsTime())
+	assert.NoError(t, RecordConfigRevision(ctx, 4, &repo_model.ActionsConfig{})) // unchanged
+	timeutil.MockSet(at(2).AsTime())
+	assert.NoError(t, RecordConfigRevision(ctx, 4, &repo_model.ActionsConfig{DisabledWorkflows: []string{"test.yaml"}}))
+	timeutil.MockSet(at(3).AsTime())
+	assert.NoError(t, RecordConfigRevision(ctx, 4, nil))
+	unittest.AssertCount(t, &ActionConfigRevision{RepoID: 4}, 3)
+
+	first, err := GetFirstConfigRevision(ctx, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, at(0), first.CreatedUnix)
+
+	r, err := GetConfigRevisionAt(ctx, 4, at(0)-1)
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	r, err = GetConfigRevisionAt(ctx, 4, at(1))
+	assert.NoError(t, err)
+	assert.Equal(t, at(0), r.CreatedUnix)
+	assert.False(t, r.ActionsConfig().IsWorkflowDisabled("test.yaml"))
+
+	r, err = GetConfigRevisionAt(ctx, 4, at(2))
+	assert.NoError(t, err)
+	assert.True(t, r.ActionsConfig().IsWorkflowDisabled("test.yaml"))
+
+	r, err = GetConfigRevisionAt(ctx, 4, at(5))
+	assert.NoError(t, err)
+	assert.False(t, r.Enabled)
+	assert.Nil(t, r.ActionsConfig())
+
+	first, err = GetFirstConfigRevision(ctx, 5)
+	assert.NoError(t, err)
+	assert.Nil(t, first)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"xorm.io/builder"
+)
+
+// ActionDeadLetter is an event whose workflows couldn't be triggered, like its payload couldn't be restored or the trigger processing panicked.
+// It's kept with the error so the admins could inspect and retry it, instead of losing the event.
+type ActionDeadLetter struct {
+	ID        int64
+	RepoID    int64                        `xorm:"INDEX NOT NULL"`
+	Method    string                       `xorm:"VARCHAR(255)"` // the notifier method which handled the event
+	Event     webhook_module.HookEventType `xorm:"VARCHAR(255)"`
+	Ref       string                       `xorm:"VARCHAR(255)"`
+	CommitSHA string                       `xorm:"VARCHAR(64)"`
+	Item      string                       `xorm:"LONGTEXT"` // the serialized event to retry
+	Error     string                       `xorm:"LONGTEXT"` // the error of the latest attempt, with the stack if it panicked
+	Attempts  int64                        `xorm:"NOT NULL DEFAULT 1"`
+	Created   timeutil.TimeStamp           `xorm:"created INDEX"`
+	Updated   timeutil.TimeStamp           `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionDeadLetter))
+}
+
+type FindDeadLettersOptions struct {
+	db.ListOptions
+	RepoID int64
+	Event  webhook_module.HookEventType
+}
+
+func (opts FindDeadLettersOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Event != "" {
+		cond = cond.And(builder.Eq{"event": opts.Event})
+	}
+	return cond
+}
+
+func (opts FindDeadLettersOptions) ToOrders() string {
+	return "id DESC"
+}
+
+// InsertDeadLetter stores an event whose workflows couldn't be triggered
+func InsertDeadLetter(ctx context.Context, letter *ActionDeadLetter) error {
+	letter.Attempts = 1
+	return db.Insert(ctx, letter)
+}
+
+// GetDeadLetterByID returns the dead letter
+func GetDeadLetterByID(ctx context.Context, id int64) (*ActionDeadLetter, error) {
+	letter, has, err := db.GetByID[ActionDeadLetter](ctx, id)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("dead letter %d does not exist", id)
+	}
+	return letter, nil
+}
+
+// UpdateDeadLetterFailure records the error of another failed attempt of the dead letter
+func UpdateDeadLetterFailure(ctx context.Context, letter *ActionDeadLetter, errMsg string) error {
+	letter.Error = errMsg
+	letter.Attempts++
+	_, err := db.GetEngine(ctx).ID(letter.ID).Cols("error", "attempts").Update(letter)
+	return err
+}
+
+// DeleteDeadLetter deletes the dead letter, after it's been retried successfully or discarded
+func DeleteDeadLetter(ctx context.Context, id int64) error {
+	n, err := db.DeleteByID[ActionDeadLetter](ctx, id)
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return util.NewNotExistErrorf("dead letter %d does not exist", id)
+	}
+	return nil
+}
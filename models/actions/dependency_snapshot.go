@@ -0,0 +1,177 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// The types of the changes of the dependencies between two snapshots
+const (
+	DependencyChangeAdded   = "added"
+	DependencyChangeRemoved = "removed"
+	DependencyChangeUpdated = "updated"
+)
+
+// DependencyManifest is a manifest file of the dependencies of a repository, like go.mod or package-lock.json
+type DependencyManifest struct {
+	Path         string        `json:"path"`
+	Ecosystem    string        `json:"ecosystem"`
+	Dependencies []*Dependency `json:"dependencies"`
+}
+
+// Dependency is a package which a manifest depends on
+type Dependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Scope   string `json:"scope,omitempty"` // like "runtime" or "development", empty if the ecosystem doesn't tell
+}
+
+// ActionDependencySnapshot is the latest snapshot of the dependency manifests of a ref submitted by the workflows.
+// The submissions of the same commit are merged by the paths of the manifests, so the jobs of different ecosystems could submit their own,
+// while a submission of a new commit replaces the snapshot.
+type ActionDependencySnapshot struct {
+	ID        int64
+	RepoID    int64                 `xorm:"UNIQUE(repo_ref) NOT NULL"`
+	Ref       string                `xorm:"VARCHAR(255) UNIQUE(repo_ref) NOT NULL"`
+	CommitSHA string                `xorm:"VARCHAR(64) NOT NULL"`
+	RunIndex  int64                 `xorm:"NOT NULL DEFAULT 0"` // the latest run which submitted to the snapshot
+	Manifests []*DependencyManifest `xorm:"JSON TEXT"`
+	Created   timeutil.TimeStamp    `xorm:"created"`
+	Updated   timeutil.TimeStamp    `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionDependencySnapshot))
+}
+
+// GetDependencySnapshot returns the latest dependency snapshot of the ref of the repository
+func GetDependencySnapshot(ctx context.Context, repoID int64, ref string) (*ActionDependencySnapshot, error) {
+	snapshot := &ActionDependencySnapshot{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND ref = ?", repoID, ref).Get(snapshot)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("dependency snapshot of ref %s of repo %d", ref, repoID)
+	}
+	return snapshot, nil
+}
+
+// SubmitDependencySnapshot submits the dependency manifests of the commit of the run to the snapshot of the ref of the run
+func SubmitDependencySnapshot(ctx context.Context, run *ActionRun, manifests []*DependencyManifest) (*ActionDependencySnapshot, error) {
+	var snapshot *ActionDependencySnapshot
+	err := db.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		snapshot, err = GetDependencySnapshot(ctx, run.RepoID, run.Ref)
+		if errors.Is(err, util.ErrNotExist) {
+			snapshot = &ActionDependencySnapshot{
+				RepoID:    run.RepoID,
+				Ref:       run.Ref,
+				CommitSHA: run.CommitSHA,
+				RunIndex:  run.Index,
+				Manifests: manifests,
+			}
+			return db.Insert(ctx, snapshot)
+		} else if err != nil {
+			return err
+		}
+
+		if snapshot.CommitSHA == run.CommitSHA {
+			snapshot.Manifests = mergeDependencyManifests(snapshot.Manifests, manifests)
+		} else {
+			snapshot.CommitSHA = run.CommitSHA
+			snapshot.Manifests = manifests
+		}
+		snapshot.RunIndex = run.Index
+		_, err = db.GetEngine(ctx).ID(snapshot.ID).Cols("commit_sha", "run_index", "manifests").Update(snapshot)
+		return err
+	})
+	return snapshot, err
+}
+
+// mergeDependencyManifests replaces the manifests of the same paths and adds the new ones, the manifests are ordered by the paths
+func mergeDependencyManifests(old, submitted []*DependencyManifest) []*DependencyManifest {
+	byPath := make(map[string]*DependencyManifest, len(old)+len(submitted))
+	for _, m := range old {
+		byPath[m.Path] = m
+	}
+	for _, m := range submitted {
+		byPath[m.Path] = m
+	}
+	ret := make([]*DependencyManifest, 0, len(byPath))
+	for _, m := range byPath {
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Path < ret[j].Path })
+	return ret
+}
+
+// DependencyChange is a dependency added, removed or updated between two snapshots
+type DependencyChange struct {
+	ChangeType      string
+	Manifest        string
+	Ecosystem       string
+	Name            string
+	Version         string // the version of the head, or the removed version
+	PreviousVersion string // the version of the base, empty unless it's updated
+	Scope           string
+}
+
+// DiffDependencySnapshots returns the changes of the dependencies from the base snapshot to the head one,
+// the dependencies are matched by the manifests, the ecosystems and the names. The changes are ordered by the manifests and the names.
+func DiffDependencySnapshots(base, head *ActionDependencySnapshot) []*DependencyChange {
+	type key struct{ manifest, ecosystem, name string }
+	index := func(snapshot *ActionDependencySnapshot) map[key]*Dependency {
+		deps := make(map[key]*Dependency)
+		for _, m := range snapshot.Manifests {
+			for _, dep := range m.Dependencies {
+				deps[key{m.Path, m.Ecosystem, dep.Name}] = dep
+			}
+		}
+		return deps
+	}
+	baseDeps, headDeps := index(base), index(head)
+
+	var changes []*DependencyChange
+	for k, dep := range headDeps {
+		change := &DependencyChange{Manifest: k.manifest, Ecosystem: k.ecosystem, Name: k.name, Version: dep.Version, Scope: dep.Scope}
+		if baseDep, ok := baseDeps[k]; !ok {
+			change.ChangeType = DependencyChangeAdded
+		} else if baseDep.Version != dep.Version {
+			change.ChangeType = DependencyChangeUpdated
+			change.PreviousVersion = baseDep.Version
+		} else {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	for k, dep := range baseDeps {
+		if _, ok := headDeps[k]; !ok {
+			changes = append(changes, &DependencyChange{
+				ChangeType: DependencyChangeRemoved,
+				Manifest:   k.manifest,
+				Ecosystem:  k.ecosystem,
+				Name:       k.name,
+				Version:    dep.Version,
+				Scope:      dep.Scope,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Manifest != changes[j].Manifest {
+			return changes[i].Manifest < changes[j].Manifest
+		}
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Ecosystem < changes[j].Ecosystem
+	})
+	return changes
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitDependencySnapshot(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	run := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	goMod := &DependencyManifest{Path: "go.mod", Ecosystem: "go", Dependencies: []*Dependency{{Name: "golang.org/x/net", Version: "v0.1.0"}}}
+	npm := &DependencyManifest{Path: "web/package-lock.json", Ecosystem: "npm", Dependencies: []*Dependency{{Name: "vue", Version: "3.4.0"}}}
+
+	snapshot, err := SubmitDependencySnapshot(db.DefaultContext, run, []*DependencyManifest{npm})
+	require.NoError(t, err)
+	assert.EqualValues(t, run.Index, snapshot.RunIndex)
+
+	// the submissions of the same commit are merged
+	_, err = SubmitDependencySnapshot(db.DefaultContext, run, []*DependencyManifest{goMod})
+	require.NoError(t, err)
+	snapshot, err = GetDependencySnapshot(db.DefaultContext, run.RepoID, run.Ref)
+	require.NoError(t, err)
+	require.Len(t, snapshot.Manifests, 2)
+	assert.Equal(t, "go.mod", snapshot.Manifests[0].Path)
+	assert.Equal(t, "web/package-lock.json", snapshot.Manifests[1].Path)
+
+	// a submission of a new commit replaces the snapshot
+	newRun := &ActionRun{RepoID: run.RepoID, Index: run.Index + 1, Ref: run.Ref, CommitSHA: "0000000000000000000000000000000000000001"}
+	_, err = SubmitDependencySnapshot(db.DefaultContext, newRun, []*DependencyManifest{goMod})
+	require.NoError(t, err)
+	snapshot, err = GetDependencySnapshot(db.DefaultContext, run.RepoID, run.Ref)
+	require.NoError(t, err)
+	assert.Equal(t, newRun.CommitSHA, snapshot.CommitSHA)
+	assert.Len(t, snapshot.Manifests, 1)
+
+	_, err = GetDependencySnapshot(db.DefaultContext, run.RepoID, "refs/heads/not-exist")
+	assert.Error(t, err)
+}
+
+func TestDiffDependencySnapshots(t *testing.T) {
+	base := &ActionDependencySnapshot{Manifests: []*DependencyManifest{
+		{Path: "go.mod", Ecosystem: "go", Dependencies: []*Dependency{
+			{Name: "a", Version: "v1.0.0"},
+			{Name: "b", Version: "v1.0.0"},
+			{Name: "c", Version: "v1.0.0"},
+		}},
+	}}
+	head := &ActionDependencySnapshot{Manifests: []*DependencyManifest{
+		{Path: "go.mod", Ecosystem: "go", Dependencies: []*Dependency{
+			{Name: "a", Version: "v1.0.0"},
+			{Name: "b", Version: "v1.1.0"},
+			{Name: "d", Version: "v0.1.0", Scope: "development"},
+		}},
+	}}
+
+	assert.Equal(t, []*DependencyChange{
+		{ChangeType: DependencyChangeUpdated, Manifest: "go.mod", Ecosystem: "go", Name: "b", Version: "v1.1.0", PreviousVersion: "v1.0.0"},
+		{ChangeType: DependencyChangeRemoved, Manifest: "go.mod", Ecosystem: "go", Name: "c", Version: "v1.0.0"},
+		{ChangeType: DependencyChangeAdded, Manifest: "go.mod", Ecosystem: "go", Name: "d", Version: "v0.1.0", Scope: "development"},
+	}, DiffDependencySnapshots(base, head))
+	assert.Empty(t, DiffDependencySnapshots(base, base))
+}
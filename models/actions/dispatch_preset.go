@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ActionDispatchPreset is a named set of the inputs to dispatch a workflow of a repository,
+// so recurring manual operations like "deploy prod eu-west" don't require typing the inputs again
+type ActionDispatchPreset struct {
+	ID         int64              `xorm:"pk autoincr"`
+	RepoID     int64              `xorm:"UNIQUE(repo_name) NOT NULL"`
+	Name       string             `xorm:"VARCHAR(255) UNIQUE(repo_name) NOT NULL"`
+	WorkflowID string             `xorm:"VARCHAR(255) NOT NULL"` // the file name of the workflow
+	Ref        string             `xorm:"VARCHAR(255)"`          // the branch or tag to dispatch the workflow on, the default branch if empty
+	Inputs     map[string]string  `xorm:"JSON TEXT"`
+	CreatorID  int64              `xorm:"INDEX"`
+	Created    timeutil.TimeStamp `xorm:"created"`
+	Updated    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionDispatchPreset))
+}
+
+// GetDispatchPresets returns the dispatch presets of the repository ordered by their names
+func GetDispatchPresets(ctx context.Context, repoID int64) ([]*ActionDispatchPreset, error) {
+	var presets []*ActionDispatchPreset
+	return presets, db.GetEngine(ctx).Where("repo_id=?", repoID).OrderBy("name").Find(&presets)
+}
+
+// GetDispatchPresetByName returns the dispatch preset of the repository by its name
+func GetDispatchPresetByName(ctx context.Context, repoID int64, name string) (*ActionDispatchPreset, error) {
+	var preset ActionDispatchPreset
+	has, err := db.GetEngine(ctx).Where("repo_id=? AND name=?", repoID, name).Get(&preset)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("dispatch preset %q: %w", name, util.ErrNotExist)
+	}
+	return &preset, nil
+}
+
+// SaveDispatchPreset creates the dispatch preset, or replaces the one with the same name of the repository.
+// It returns whether the preset is created.
+func SaveDispatchPreset(ctx context.Context, preset *ActionDispatchPreset) (created bool, err error) {
+	if preset.Name == "" || preset.WorkflowID == "" {
+		return false, util.NewInvalidArgumentErrorf("the name and the workflow of a dispatch preset are required")
+	}
+	err = db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetDispatchPresetByName(ctx, preset.RepoID, preset.Name)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			return err
+		}
+		if existing == nil {
+			created = true
+			return db.Insert(ctx, preset)
+		}
+		preset.ID = existing.ID
+		preset.CreatorID = existing.CreatorID
+		preset.Created = existing.Created
+		_, err = db.GetEngine(ctx).ID(preset.ID).Cols("workflow_id", "ref", "inputs").Update(preset)
+		return err
+	})
+	return created, err
+}
+
+// DeleteDispatchPreset deletes the dispatch preset of the repository by its name
+func DeleteDispatchPreset(ctx context.Context, repoID int64, name string) error {
+	n, err := db.GetEngine(ctx).Where("repo_id=? AND name=?", repoID, name).Delete(new(ActionDispatchPreset))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("dispatch preset %q: %w", name, util.ErrNotExist)
+	}
+	return nil
+}
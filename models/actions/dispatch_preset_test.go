@@ -0,0 +1,65 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveDispatchPreset(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	created, err := SaveDispatchPreset(ctx, &ActionDispatchPreset{
+		RepoID:     1,
+		Name:       "deploy prod eu-west",
+		WorkflowID: "deploy.yml",
+		Inputs:     map[string]string{"env": "prod", "region": "eu-west"},
+		CreatorID:  2,
+	})
+	require.NoError(t, err)
+	assert.True(t, created)
+	_, err = SaveDispatchPreset(ctx, &ActionDispatchPreset{RepoID: 1, Name: "deploy staging", WorkflowID: "deploy.yml", Ref: "release"})
+	require.NoError(t, err)
+
+	// the preset with the same name is replaced, but its creator is kept
+	created, err = SaveDispatchPreset(ctx, &ActionDispatchPreset{
+		RepoID:     1,
+		Name:       "deploy prod eu-west",
+		WorkflowID: "deploy.yml",
+		Inputs:     map[string]string{"env": "prod", "region": "eu-west-1"},
+		CreatorID:  1,
+	})
+	require.NoError(t, err)
+	assert.False(t, created)
+	preset, err := GetDispatchPresetByName(ctx, 1, "deploy prod eu-west")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "region": "eu-west-1"}, preset.Inputs)
+	assert.EqualValues(t, 2, preset.CreatorID)
+
+	presets, err := GetDispatchPresets(ctx, 1)
+	require.NoError(t, err)
+	if assert.Len(t, presets, 2) {
+		assert.Equal(t, "deploy prod eu-west", presets[0].Name)
+		assert.Equal(t, "deploy staging", presets[1].Name)
+	}
+	presets, err = GetDispatchPresets(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+
+	_, err = SaveDispatchPreset(ctx, &ActionDispatchPreset{RepoID: 1, Name: "no workflow"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	require.NoError(t, DeleteDispatchPreset(ctx, 1, "deploy staging"))
+	assert.ErrorIs(t, DeleteDispatchPreset(ctx, 1, "deploy staging"), util.ErrNotExist)
+	_, err = GetDispatchPresetByName(ctx, 1, "deploy staging")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
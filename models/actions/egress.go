@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RunEgress is the egress policy of the jobs of a run, it's passed to the runners which could enforce it.
+// It's declared by the workflow and restricted by the egress allowlist of the owner, see actions_module.ParseWorkflowEgress
+type RunEgress struct {
+	Allow []string `json:"allow"` // the domains the jobs could connect to, the jobs couldn't connect to anywhere if it's empty
+}
+
+// GetEgressAllowlist returns the domains the jobs of the repositories of the owner could connect to,
+// it's nil if the owner hasn't configured one
+func GetEgressAllowlist(ctx context.Context, ownerID int64) ([]string, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsEgressAllowlist)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	var domains []string
+	if err := json.Unmarshal([]byte(value), &domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// SetEgressAllowlist replaces the egress allowlist of the owner, an empty list removes it
+func SetEgressAllowlist(ctx context.Context, ownerID int64, domains []string) error {
+	if len(domains) == 0 {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsEgressAllowlist)
+	}
+	value, err := json.Marshal(domains)
+	if err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsEgressAllowlist, string(value))
+}
+
+// ActionEgressViolation is a connection of a task blocked by the egress policy of its run, reported by the runner.
+// The same connections of a task are recorded once with how many times they were blocked.
+type ActionEgressViolation struct {
+	ID      int64
+	RepoID  int64              `xorm:"INDEX NOT NULL"`
+	RunID   int64              `xorm:"INDEX NOT NULL"`
+	TaskID  int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Host    string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	Port    int                `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"` // 0 if it's unknown
+	Count   int64              `xorm:"NOT NULL DEFAULT 1"`
+	Created timeutil.TimeStamp `xorm:"created"`
+	Updated timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionEgressViolation))
+}
+
+// RecordEgressViolation records a blocked connection of a task, or increases the count if it has been recorded
+func RecordEgressViolation(ctx context.Context, violation *ActionEgressViolation) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing := &ActionEgressViolation{}
+		has, err := db.GetEngine(ctx).Where("task_id = ? AND host = ? AND port = ?", violation.TaskID, violation.Host, violation.Port).Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			if violation.Count <= 0 {
+				violation.Count = 1
+			}
+			return db.Insert(ctx, violation)
+		}
+		existing.Count += max(violation.Count, 1)
+		if _, err := db.GetEngine(ctx).ID(existing.ID).Cols("count", "updated").Update(existing); err != nil {
+			return err
+		}
+		*violation = *existing
+		return nil
+	})
+}
+
+// FindEgressViolationsByRunID returns the blocked connections of the tasks of the run, the latest ones go first
+func FindEgressViolationsByRunID(ctx context.Context, runID int64) ([]*ActionEgressViolation, error) {
+	var violations []*ActionEgressViolation
+	return violations, db.GetEngine(ctx).Where("run_id = ?", runID).Desc("updated", "id").Find(&violations)
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/json"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+)
+
+// EventPayloadVersion is the version of the format of the event payloads stored on runs and schedules.
+// It should be increased with a converter registered by RegisterEventPayloadConverter
+// when an upgrade changes the structures of the webhook payloads,
+// so the old runs could still be rendered and rerun with the payloads in the current format.
+const EventPayloadVersion = 1
+
+// EventPayloadConverter converts the decoded payload of the event from a version to the next version in place
+type EventPayloadConverter func(event webhook_module.HookEventType, payload map[string]any) error
+
+// eventPayloadConverters are the converters indexed by the versions they convert from
+var eventPayloadConverters = map[int]EventPayloadConverter{}
+
+// RegisterEventPayloadConverter registers the converter of the event payloads from the version to the next version
+func RegisterEventPayloadConverter(from int, converter EventPayloadConverter) {
+	eventPayloadConverters[from] = converter
+}
+
+// ConvertEventPayload converts the payload of the event from the version to EventPayloadVersion
+func ConvertEventPayload(event webhook_module.HookEventType, version int, payload string) (string, error) {
+	return convertEventPayload(event, version, EventPayloadVersion, payload)
+}
+
+func convertEventPayload(event webhook_module.HookEventType, from, to int, payload string) (string, error) {
+	// the payloads stored before they were versioned are in the first version
+	from = max(from, 1)
+	if from > to {
+		return "", fmt.Errorf("event payload version %d is newer than %d", from, to)
+	}
+	if from == to || payload == "" {
+		return payload, nil
+	}
+
+	decoded := map[string]any{}
+	if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+		return "", err
+	}
+	for version := from; version < to; version++ {
+		converter, ok := eventPayloadConverters[version]
+		if !ok {
+			continue
+		}
+		if err := converter(event, decoded); err != nil {
+			return "", fmt.Errorf("convert event payload from version %d: %w", version, err)
+		}
+	}
+	converted, err := json.Marshal(decoded)
+	if err != nil {
+		return "", err
+	}
+	return string(converted), nil
+}
+
+// GetEventPayload returns the event payload of the run in the current format
+func (run *ActionRun) GetEventPayload() (string, error) {
+	return ConvertEventPayload(run.Event, run.EventPayloadVersion, run.EventPayload)
+}
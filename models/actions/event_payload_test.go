@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"maps"
+	"testing"
+
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertEventPayload(t *testing.T) {
+	defer func(converters map[int]EventPayloadConverter) { eventPayloadConverters = converters }(maps.Clone(eventPayloadConverters))
+	// version 2 renames "ref" to "git_ref", and version 3 moves it into "head"
+	RegisterEventPayloadConverter(1, func(_ webhook_module.HookEventType, payload map[string]any) error {
+		payload["git_ref"] = payload["ref"]
+		delete(payload, "ref")
+		return nil
+	})
+	RegisterEventPayloadConverter(2, func(event webhook_module.HookEventType, payload map[string]any) error {
+		if event == webhook_module.HookEventPush {
+			payload["head"] = map[string]any{"ref": payload["git_ref"]}
+			delete(payload, "git_ref")
+		}
+		return nil
+	})
+
+	payload, err := convertEventPayload(webhook_module.HookEventPush, 1, 3, `{"ref":"refs/heads/main","before":"abc"}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"head":{"ref":"refs/heads/main"},"before":"abc"}`, payload)
+
+	// the payloads stored before they were versioned are in the first version
+	payload, err = convertEventPayload(webhook_module.HookEventPullRequest, 0, 3, `{"ref":"refs/heads/main"}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"git_ref":"refs/heads/main"}`, payload)
+
+	payload, err = convertEventPayload(webhook_module.HookEventPush, 3, 3, `{"ref":"refs/heads/main"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ref":"refs/heads/main"}`, payload)
+
+	_, err = convertEventPayload(webhook_module.HookEventPush, 4, 3, `{}`)
+	assert.Error(t, err)
+
+	run := &ActionRun{Event: webhook_module.HookEventPush, EventPayload: `{"ref":"refs/heads/main"}`, EventPayloadVersion: EventPayloadVersion}
+	pushPayload, err := run.GetPushEventPayload()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/main", pushPayload.Ref)
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+// ActionFreezeWindow represents a period defined by the admins of an organization or of the instance,
+// like a release freeze, during which the jobs deploying to the environments or requiring the runner labels it freezes aren't picked by the runners.
+// The jobs keep waiting and are released when the window ends.
+type ActionFreezeWindow struct {
+	ID           int64              `xorm:"pk autoincr"`
+	OwnerID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"` // the owner whose jobs are frozen, 0 for all the jobs of the instance
+	Name         string             `xorm:"VARCHAR(255) NOT NULL"`
+	Reason       string             `xorm:"TEXT"`
+	Environments []string           `xorm:"JSON TEXT"` // the names of the environments in lower case
+	Labels       []string           `xorm:"JSON TEXT"` // the glob patterns of the runs-on labels, like "deploy-*"
+	StartUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	EndUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	Released     bool               `xorm:"INDEX NOT NULL DEFAULT false"` // whether the waiting jobs have been signaled to the runners since the window ended
+	CreatorID    int64
+	Created      timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionFreezeWindow))
+}
+
+// IsActive returns whether the window freezes the jobs at the time
+func (w *ActionFreezeWindow) IsActive(now timeutil.TimeStamp) bool {
+	return w.StartUnix <= now && now < w.EndUnix
+}
+
+// Freezes returns whether the job is in the scope of the window and deploys to one of its environments or requires one of its labels
+func (w *ActionFreezeWindow) Freezes(job *ActionRunJob) bool {
+	if w.OwnerID != 0 && w.OwnerID != job.OwnerID {
+		return false
+	}
+	if job.Environment != "" && util.SliceContainsString(w.Environments, job.Environment, true) {
+		return true
+	}
+	for _, pattern := range w.Labels {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		for _, label := range job.RunsOn {
+			if g.Match(label) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FreezeWindowOfJob returns the first of the windows which freezes the job, or nil if none does
+func FreezeWindowOfJob(windows []*ActionFreezeWindow, job *ActionRunJob) *ActionFreezeWindow {
+	for _, w := range windows {
+		if w.Freezes(job) {
+			return w
+		}
+	}
+	return nil
+}
+
+// GetFreezeWindowOfJob returns the active window which freezes the waiting job, or nil if it isn't frozen
+func GetFreezeWindowOfJob(ctx context.Context, job *ActionRunJob) (*ActionFreezeWindow, error) {
+	if job.Status != StatusWaiting {
+		return nil, nil
+	}
+	windows, err := GetActiveFreezeWindows(ctx, timeutil.TimeStampNow())
+	if err != nil {
+		return nil, err
+	}
+	return FreezeWindowOfJob(windows, job), nil
+}
+
+// FindFreezeWindowsOptions represents the options to find the freeze windows
+type FindFreezeWindowsOptions struct {
+	db.ListOptions
+	OwnerID  int64              // 0 for the windows of the instance
+	AllScope bool               // find the windows of all the owners and the instance, OwnerID is ignored
+	ActiveAt timeutil.TimeStamp // only the windows active at the time if it's not zero
+	Released optional.Option[bool]
+	EndedAt  timeutil.TimeStamp // only the windows which have ended at the time if it's not zero
+}
+
+func (opts FindFreezeWindowsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if !opts.AllScope {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.ActiveAt > 0 {
+		cond = cond.And(builder.Lte{"start_unix": opts.ActiveAt}, builder.Gt{"end_unix": opts.ActiveAt})
+	}
+	if opts.EndedAt > 0 {
+		cond = cond.And(builder.Lte{"end_unix": opts.EndedAt})
+	}
+	if opts.Released.Has() {
+		cond = cond.And(builder.Eq{"released": opts.Released.Value()})
+	}
+	return cond
+}
+
+func (opts FindFreezeWindowsOptions) ToOrders() string {
+	return "start_unix, id"
+}
+
+// GetActiveFreezeWindows returns the windows of all the scopes which are active at the time
+func GetActiveFreezeWindows(ctx context.Context, now timeutil.TimeStamp) ([]*ActionFreezeWindow, error) {
+	return db.Find[ActionFreezeWindow](ctx, FindFreezeWindowsOptions{AllScope: true, ActiveAt: now})
+}
+
+// GetFreezeWindowByID returns the freeze window of the owner by id, the owner is 0 for the windows of the instance
+func GetFreezeWindowByID(ctx context.Context, ownerID, id int64) (*ActionFreezeWindow, error) {
+	w := &ActionFreezeWindow{}
+	has, err := db.GetEngine(ctx).Where("id=? AND owner_id=?", id, ownerID).Get(w)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("freeze window with id %d: %w", id, util.ErrNotExist)
+	}
+	return w, nil
+}
+
+// CreateFreezeWindow creates the freeze window, it must freeze some environments or labels and end in the future
+func CreateFreezeWindow(ctx context.Context, w *ActionFreezeWindow) error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return util.NewInvalidArgumentErrorf("name is required")
+	}
+	if w.EndUnix <= w.StartUnix {
+		return util.NewInvalidArgumentErrorf("the freeze window must end after it starts")
+	}
+	if w.EndUnix <= timeutil.TimeStampNow() {
+		return util.NewInvalidArgumentErrorf("the freeze window has already ended")
+	}
+
+	environments := make([]string, 0, len(w.Environments))
+	for _, env := range w.Environments {
+		if env = strings.ToLower(strings.TrimSpace(env)); env != "" {
+			environments = append(environments, env)
+		}
+	}
+	labels := make([]string, 0, len(w.Labels))
+	for _, pattern := range w.Labels {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := glob.Compile(pattern); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid label pattern %q: %v", pattern, err)
+		}
+		labels = append(labels, pattern)
+	}
+	if len(environments) == 0 && len(labels) == 0 {
+		return util.NewInvalidArgumentErrorf("the freeze window must freeze some environments or labels")
+	}
+	w.Environments = environments
+	w.Labels = labels
+	w.Released = false
+	return db.Insert(ctx, w)
+}
+
+// DeleteFreezeWindow deletes the freeze window of the owner, the owner is 0 for the windows of the instance
+func DeleteFreezeWindow(ctx context.Context, ownerID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id=? AND owner_id=?", id, ownerID).Delete(new(ActionFreezeWindow))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("freeze window with id %d: %w", id, util.ErrNotExist)
+	}
+	return nil
+}
+
+// ReleaseFreezeWindow marks the window released and signals the runners which could pick the waiting jobs in its scope,
+// they aren't signaled otherwise since the tasks versions haven't changed while the jobs were frozen.
+func ReleaseFreezeWindow(ctx context.Context, w *ActionFreezeWindow) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).ID(w.ID).Cols("released").Update(&ActionFreezeWindow{Released: true}); err != nil {
+			return err
+		}
+
+		cond := builder.Eq{"status": StatusWaiting, "task_id": 0}
+		if w.OwnerID != 0 {
+			cond["owner_id"] = w.OwnerID
+		}
+		var scopes []struct {
+			OwnerID int64
+			RepoID  int64
+		}
+		if err := db.GetEngine(ctx).Table("action_run_job").Where(cond).Distinct("owner_id", "repo_id").Find(&scopes); err != nil {
+			return err
+		}
+		for _, scope := range scopes {
+			if err := IncreaseTaskVersion(ctx, scope.OwnerID, scope.RepoID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
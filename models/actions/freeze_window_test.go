@@ -0,0 +1,96 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionFreezeWindowFreezes(t *testing.T) {
+	w := &ActionFreezeWindow{OwnerID: 3, Environments: []string{"production"}, Labels: []string{"deploy-*"}}
+	assert.True(t, w.Freezes(&ActionRunJob{OwnerID: 3, Environment: "production", RunsOn: []string{"ubuntu-latest"}}))
+	assert.True(t, w.Freezes(&ActionRunJob{OwnerID: 3, RunsOn: []string{"linux", "deploy-eu"}}))
+	assert.False(t, w.Freezes(&ActionRunJob{OwnerID: 3, Environment: "staging", RunsOn: []string{"ubuntu-latest"}}))
+	// the windows of an owner don't freeze the jobs of the others
+	assert.False(t, w.Freezes(&ActionRunJob{OwnerID: 2, Environment: "production"}))
+
+	// the windows of the instance freeze the jobs of all the owners
+	w.OwnerID = 0
+	assert.True(t, w.Freezes(&ActionRunJob{OwnerID: 2, Environment: "production"}))
+
+	now := timeutil.TimeStamp(1000)
+	w.StartUnix, w.EndUnix = 900, 1000
+	assert.False(t, w.IsActive(now))
+	w.EndUnix = 1001
+	assert.True(t, w.IsActive(now))
+}
+
+func TestCreateFreezeWindow(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	now := timeutil.TimeStampNow()
+
+	for _, w := range []*ActionFreezeWindow{
+		{Name: " ", Environments: []string{"production"}, StartUnix: now, EndUnix: now + 3600},
+		{Name: "release", Environments: []string{"production"}, StartUnix: now, EndUnix: now},
+		{Name: "release", Environments: []string{"production"}, StartUnix: now - 7200, EndUnix: now - 3600},
+		{Name: "release", Environments: []string{" "}, StartUnix: now, EndUnix: now + 3600},
+		{Name: "release", Labels: []string{"deploy-["}, StartUnix: now, EndUnix: now + 3600},
+	} {
+		assert.ErrorIs(t, CreateFreezeWindow(ctx, w), util.ErrInvalidArgument)
+	}
+
+	active := &ActionFreezeWindow{OwnerID: 3, Name: "release", Environments: []string{" Production "}, StartUnix: now - 60, EndUnix: now + 3600}
+	require.NoError(t, CreateFreezeWindow(ctx, active))
+	assert.Equal(t, []string{"production"}, active.Environments)
+	upcoming := &ActionFreezeWindow{Name: "holidays", Labels: []string{"deploy-*"}, StartUnix: now + 3600, EndUnix: now + 7200}
+	require.NoError(t, CreateFreezeWindow(ctx, upcoming))
+
+	windows, err := GetActiveFreezeWindows(ctx, now)
+	require.NoError(t, err)
+	if assert.Len(t, windows, 1) {
+		assert.Equal(t, active.ID, windows[0].ID)
+	}
+
+	// the windows are scoped to their owners
+	_, err = GetFreezeWindowByID(ctx, 0, active.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+	assert.ErrorIs(t, DeleteFreezeWindow(ctx, 0, active.ID), util.ErrNotExist)
+	require.NoError(t, DeleteFreezeWindow(ctx, 3, active.ID))
+	unittest.AssertNotExistsBean(t, &ActionFreezeWindow{ID: active.ID})
+}
+
+func TestReleaseFreezeWindow(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	job := &ActionRunJob{RunID: 791, RepoID: 4, OwnerID: 1, JobID: "deploy", Status: StatusWaiting, Environment: "production"}
+	require.NoError(t, db.Insert(ctx, job))
+	w := &ActionFreezeWindow{
+		OwnerID:      1,
+		Name:         "release",
+		Environments: []string{"production"},
+		StartUnix:    timeutil.TimeStampNow(),
+		EndUnix:      timeutil.TimeStamp(time.Now().Add(time.Hour).Unix()),
+	}
+	require.NoError(t, CreateFreezeWindow(ctx, w))
+
+	before, err := GetTasksVersionByScope(ctx, 0, 4)
+	require.NoError(t, err)
+	require.NoError(t, ReleaseFreezeWindow(ctx, w))
+	after, err := GetTasksVersionByScope(ctx, 0, 4)
+	require.NoError(t, err)
+	// the runners of the repository are signaled to pick the waiting job
+	assert.Greater(t, after, before)
+	unittest.AssertExistsAndLoadBean(t, &ActionFreezeWindow{ID: w.ID, Released: true})
+}
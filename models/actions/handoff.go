@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ActionHandoffBlob is a transient file passed between the jobs of a run.
+// Unlike artifacts, it's never visible to users. It's kept as long as the artifacts after the run is done,
+// so the jobs which are re-run can still download the blobs uploaded by the jobs they need.
+type ActionHandoffBlob struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RunID       int64              `xorm:"index unique(run_name)"`
+	RepoID      int64              `xorm:"index"`
+	Name        string             `xorm:"VARCHAR(255) unique(run_name)"`
+	JobID       int64              // the job which uploaded the blob
+	StoragePath string             // the path of the encrypted blob in the storage
+	Size        int64              // the size of the blob before encryption
+	Created     timeutil.TimeStamp `xorm:"created"`
+	Updated     timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionHandoffBlob))
+}
+
+// GetHandoffBlob returns the blob of the run with the name
+func GetHandoffBlob(ctx context.Context, runID int64, name string) (*ActionHandoffBlob, error) {
+	blob := &ActionHandoffBlob{}
+	has, err := db.GetEngine(ctx).Where("run_id=? AND name=?", runID, name).Get(blob)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("handoff blob %q of run %d: %w", name, runID, util.ErrNotExist)
+	}
+	return blob, nil
+}
+
+// UpsertHandoffBlob inserts the blob, or replaces the existing one of the run with the same name.
+// It returns the storage path of the replaced blob, which should be deleted by the caller.
+func UpsertHandoffBlob(ctx context.Context, blob *ActionHandoffBlob) (string, error) {
+	var replaced string
+	err := db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetHandoffBlob(ctx, blob.RunID, blob.Name)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, blob)
+		}
+		replaced = existing.StoragePath
+		blob.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(blob.ID).Cols("job_id", "storage_path", "size").Update(blob)
+		return err
+	})
+	return replaced, err
+}
+
+// FindHandoffBlobOptions are the options to find handoff blobs
+type FindHandoffBlobOptions struct {
+	db.ListOptions
+	RunID  int64
+	RepoID int64
+	// RunDoneBefore means only the blobs of the runs which were done before the time
+	RunDoneBefore timeutil.TimeStamp
+}
+
+func (opts FindHandoffBlobOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RunID > 0 {
+		cond = cond.And(builder.Eq{"run_id": opts.RunID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.RunDoneBefore > 0 {
+		cond = cond.And(builder.In("run_id", builder.Select("id").From("action_run").
+			Where(builder.In("status", StatusSuccess, StatusFailure, StatusCancelled, StatusSkipped).
+				And(builder.Lt{"stopped": opts.RunDoneBefore}))))
+	}
+	return cond
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindHandoffBlobOptions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// run 791 was done at 1683636626, run 1000 doesn't exist
+	assert.NoError(t, db.Insert(db.DefaultContext, &ActionHandoffBlob{RunID: 791, RepoID: 4, Name: "done", StoragePath: "handoff/done"}))
+	assert.NoError(t, db.Insert(db.DefaultContext, &ActionHandoffBlob{RunID: 1000, RepoID: 5, Name: "running", StoragePath: "handoff/running"}))
+
+	count := func(opts FindHandoffBlobOptions) int64 {
+		n, err := db.Count[ActionHandoffBlob](db.DefaultContext, opts)
+		assert.NoError(t, err)
+		return n
+	}
+	assert.EqualValues(t, 2, count(FindHandoffBlobOptions{}))
+	assert.EqualValues(t, 1, count(FindHandoffBlobOptions{RepoID: 4}))
+	assert.EqualValues(t, 1, count(FindHandoffBlobOptions{RunID: 1000}))
+	// the blobs are kept for a while after the run is done, so the re-run jobs can still download them
+	assert.EqualValues(t, 0, count(FindHandoffBlobOptions{RunDoneBefore: 1683636626}))
+	assert.EqualValues(t, 1, count(FindHandoffBlobOptions{RunDoneBefore: timeutil.TimeStampNow()}))
+}
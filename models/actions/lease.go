@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ActionLease is a lease held by one of the Gitea instances sharing the database,
+// it's used to elect the instance which runs the Actions services that must not run on multiple instances at the same time.
+type ActionLease struct {
+	ID      int64              `xorm:"pk autoincr"`
+	Name    string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+	Holder  string             `xorm:"VARCHAR(255) NOT NULL"`
+	Expires timeutil.TimeStamp `xorm:"NOT NULL"`
+	Updated timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionLease))
+}
+
+// AcquireLease acquires or renews the lease for the holder until the ttl expires.
+// It returns false if the lease is held by another holder and hasn't expired.
+func AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := timeutil.TimeStampNow()
+	lease := &ActionLease{Name: name, Holder: holder, Expires: now.AddDuration(ttl)}
+	n, err := db.GetEngine(ctx).Where("name = ? AND (holder = ? OR expires < ?)", name, holder, now).
+		Cols("holder", "expires").Update(lease)
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return true, nil
+	}
+
+	if exist, err := db.GetEngine(ctx).Exist(&ActionLease{Name: name}); err != nil || exist {
+		return false, err
+	}
+	if _, err := db.GetEngine(ctx).Insert(lease); err != nil {
+		// another holder has inserted it in the meantime
+		if exist, existErr := db.GetEngine(ctx).Exist(&ActionLease{Name: name}); existErr == nil && exist {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseLease releases the lease if it's held by the holder, so another holder can acquire it without waiting for it to expire
+func ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := db.GetEngine(ctx).Where("name = ? AND holder = ?", name, holder).
+		Cols("expires").Update(&ActionLease{Expires: 0})
+	return err
+}
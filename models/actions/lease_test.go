@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLease(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	acquire := func(holder string) bool {
+		acquired, err := AcquireLease(ctx, "test", holder, time.Minute)
+		require.NoError(t, err)
+		return acquired
+	}
+
+	// the first holder acquires and renews it, the others can't until it's released
+	assert.True(t, acquire("a"))
+	assert.True(t, acquire("a"))
+	assert.False(t, acquire("b"))
+	require.NoError(t, ReleaseLease(ctx, "test", "b"))
+	assert.False(t, acquire("b"))
+	require.NoError(t, ReleaseLease(ctx, "test", "a"))
+	assert.True(t, acquire("b"))
+	assert.False(t, acquire("a"))
+
+	// the expired lease is taken over
+	_, err := db.GetEngine(ctx).Where("name = ?", "test").Cols("expires").
+		Update(&ActionLease{Expires: timeutil.TimeStampNow().AddDuration(-time.Second)})
+	require.NoError(t, err)
+	assert.True(t, acquire("a"))
+	unittest.AssertExistsAndLoadBean(t, &ActionLease{Name: "test", Holder: "a"})
+}
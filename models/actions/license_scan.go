@@ -0,0 +1,180 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// The reasons of the license violations
+const (
+	LicenseViolationDenied     = "denied"      // the license is in the deny list
+	LicenseViolationNotAllowed = "not_allowed" // the license isn't in the allow list
+	LicenseViolationUnknown    = "unknown"     // the license is missing while there is an allow list
+)
+
+// LicensePolicy is the licenses the dependencies of the repositories of an owner could have, they're SPDX license identifiers
+// matched case-insensitively. A license in the deny list is always a violation, and if the allow list isn't empty,
+// the licenses which aren't in it are violations too.
+type LicensePolicy struct {
+	Allowed []string `json:",omitempty"`
+	Denied  []string `json:",omitempty"`
+}
+
+// IsEmpty returns whether the policy allows any license
+func (p *LicensePolicy) IsEmpty() bool {
+	return p == nil || len(p.Allowed) == 0 && len(p.Denied) == 0
+}
+
+var (
+	spdxOrPattern  = regexp.MustCompile(`(?i)\s+OR\s+`)
+	spdxAndPattern = regexp.MustCompile(`(?i)\s+AND\s+`)
+	spdxWithSuffix = regexp.MustCompile(`(?i)\s+WITH\s+.*$`)
+)
+
+// Check returns the reason of the violation of the SPDX license expression, or empty if it's allowed.
+// An "OR" expression is allowed if any of its alternatives is, and an "AND" expression is allowed if all of its licenses are.
+func (p *LicensePolicy) Check(expression string) string {
+	if p.IsEmpty() {
+		return ""
+	}
+	expression = strings.TrimSpace(strings.NewReplacer("(", " ", ")", " ").Replace(expression))
+	if expression == "" || strings.EqualFold(expression, "NOASSERTION") {
+		if len(p.Allowed) > 0 {
+			return LicenseViolationUnknown
+		}
+		return ""
+	}
+
+	reason := ""
+	for _, alternative := range spdxOrPattern.Split(expression, -1) {
+		altReason := ""
+		for _, license := range spdxAndPattern.Split(alternative, -1) {
+			if altReason = p.checkLicense(strings.TrimSpace(spdxWithSuffix.ReplaceAllString(license, ""))); altReason != "" {
+				break
+			}
+		}
+		if altReason == "" {
+			return ""
+		}
+		// a denied alternative is the more relevant reason
+		if reason != LicenseViolationDenied {
+			reason = altReason
+		}
+	}
+	return reason
+}
+
+func (p *LicensePolicy) checkLicense(license string) string {
+	for _, denied := range p.Denied {
+		if strings.EqualFold(denied, license) {
+			return LicenseViolationDenied
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return ""
+	}
+	for _, allowed := range p.Allowed {
+		if strings.EqualFold(allowed, license) {
+			return ""
+		}
+	}
+	return LicenseViolationNotAllowed
+}
+
+// GetLicensePolicy returns the license policy of the owner, it's nil if the owner hasn't configured one
+func GetLicensePolicy(ctx context.Context, ownerID int64) (*LicensePolicy, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsLicensePolicy)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	policy := &LicensePolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// SetLicensePolicy replaces the license policy of the owner, an empty policy removes it
+func SetLicensePolicy(ctx context.Context, ownerID int64, policy *LicensePolicy) error {
+	if policy.IsEmpty() {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsLicensePolicy)
+	}
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsLicensePolicy, string(value))
+}
+
+// LicensedPackage is a dependency with its license reported by a license scan
+type LicensedPackage struct {
+	Name    string
+	Version string
+	License string // the SPDX license expression, empty if it's unknown
+}
+
+// LicenseViolation is a dependency whose license violates the license policy
+type LicenseViolation struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	License string `json:"license,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// ActionLicenseScan is the result of the license scan submitted by a task, it's checked against the license policy of the owner
+// when it's submitted. A task which has submitted a failed scan fails even if all its steps succeed.
+type ActionLicenseScan struct {
+	ID         int64
+	RepoID     int64               `xorm:"INDEX NOT NULL"`
+	RunID      int64               `xorm:"INDEX NOT NULL"`
+	TaskID     int64               `xorm:"INDEX NOT NULL"`
+	Passed     bool                `xorm:"NOT NULL DEFAULT false"`
+	Packages   int                 `xorm:"NOT NULL DEFAULT 0"` // how many packages have been scanned
+	Violations []*LicenseViolation `xorm:"JSON TEXT"`
+	Created    timeutil.TimeStamp  `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionLicenseScan))
+}
+
+// CheckLicenses returns the packages violating the policy
+func CheckLicenses(policy *LicensePolicy, packages []*LicensedPackage) []*LicenseViolation {
+	var violations []*LicenseViolation
+	for _, pkg := range packages {
+		if reason := policy.Check(pkg.License); reason != "" {
+			violations = append(violations, &LicenseViolation{
+				Name:    pkg.Name,
+				Version: pkg.Version,
+				License: pkg.License,
+				Reason:  reason,
+			})
+		}
+	}
+	return violations
+}
+
+// InsertLicenseScan records the result of a license scan of a task
+func InsertLicenseScan(ctx context.Context, scan *ActionLicenseScan) error {
+	return db.Insert(ctx, scan)
+}
+
+// FindLicenseScansByRunID returns the license scans submitted by the tasks of the run, the latest ones go first
+func FindLicenseScansByRunID(ctx context.Context, runID int64) ([]*ActionLicenseScan, error) {
+	var scans []*ActionLicenseScan
+	return scans, db.GetEngine(ctx).Where("run_id = ?", runID).Desc("id").Find(&scans)
+}
+
+// hasFailedLicenseScan returns whether the task has submitted a license scan which violates the license policy
+func hasFailedLicenseScan(ctx context.Context, taskID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("task_id = ? AND passed = ?", taskID, false).Exist(new(ActionLicenseScan))
+}
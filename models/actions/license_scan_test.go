@@ -0,0 +1,102 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicensePolicy_Check(t *testing.T) {
+	policy := &LicensePolicy{Allowed: []string{"MIT", "Apache-2.0", "BSD-3-Clause"}, Denied: []string{"GPL-3.0-only"}}
+	cases := []struct {
+		expression string
+		reason     string
+	}{
+		{"MIT", ""},
+		{"mit", ""},
+		{"GPL-3.0-only", LicenseViolationDenied},
+		{"MPL-2.0", LicenseViolationNotAllowed},
+		{"", LicenseViolationUnknown},
+		{"NOASSERTION", LicenseViolationUnknown},
+		{"GPL-3.0-only OR MIT", ""},
+		{"MIT AND Apache-2.0", ""},
+		{"MIT AND MPL-2.0", LicenseViolationNotAllowed},
+		{"MPL-2.0 OR GPL-3.0-only", LicenseViolationDenied},
+		{"GPL-3.0-only OR MPL-2.0", LicenseViolationDenied},
+		{"(MIT OR GPL-3.0-only) AND Apache-2.0", ""},
+		{"Apache-2.0 WITH LLVM-exception", ""},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.reason, policy.Check(c.expression), c.expression)
+	}
+
+	denyOnly := &LicensePolicy{Denied: []string{"AGPL-3.0-only"}}
+	assert.Empty(t, denyOnly.Check(""))
+	assert.Empty(t, denyOnly.Check("MPL-2.0"))
+	assert.Equal(t, LicenseViolationDenied, denyOnly.Check("AGPL-3.0-only"))
+
+	var empty *LicensePolicy
+	assert.Empty(t, empty.Check("AGPL-3.0-only"))
+}
+
+func TestLicensePolicy_Setting(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	policy, err := GetLicensePolicy(db.DefaultContext, 3)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	require.NoError(t, SetLicensePolicy(db.DefaultContext, 3, &LicensePolicy{Denied: []string{"GPL-3.0-only"}}))
+	policy, err = GetLicensePolicy(db.DefaultContext, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GPL-3.0-only"}, policy.Denied)
+
+	require.NoError(t, SetLicensePolicy(db.DefaultContext, 3, &LicensePolicy{}))
+	policy, err = GetLicensePolicy(db.DefaultContext, 3)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
+
+func TestLicenseScan_FailsTask(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	task := unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 47})
+	require.NoError(t, task.LoadJob(db.DefaultContext))
+
+	policy := &LicensePolicy{Denied: []string{"GPL-3.0-only"}}
+	violations := CheckLicenses(policy, []*LicensedPackage{
+		{Name: "a", Version: "1.0.0", License: "MIT"},
+		{Name: "b", Version: "2.0.0", License: "GPL-3.0-only"},
+	})
+	require.Len(t, violations, 1)
+	assert.Equal(t, "b", violations[0].Name)
+	assert.Equal(t, LicenseViolationDenied, violations[0].Reason)
+
+	require.NoError(t, InsertLicenseScan(db.DefaultContext, &ActionLicenseScan{
+		RepoID:     task.RepoID,
+		RunID:      task.Job.RunID,
+		TaskID:     task.ID,
+		Packages:   2,
+		Violations: violations,
+	}))
+	scans, err := FindLicenseScansByRunID(db.DefaultContext, task.Job.RunID)
+	require.NoError(t, err)
+	require.Len(t, scans, 1)
+	assert.Len(t, scans[0].Violations, 1)
+
+	// the task fails even if all its steps succeed
+	task, err = UpdateTaskByState(db.DefaultContext, &runnerv1.TaskState{
+		Id:     task.ID,
+		Result: runnerv1.Result_RESULT_SUCCESS,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailure, task.Status)
+}
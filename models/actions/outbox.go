@@ -0,0 +1,188 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// OutboxEventType represents the type of side effect recorded in ActionOutboxEvent
+type OutboxEventType int
+
+const (
+	OutboxEventRunCreated OutboxEventType = iota + 1 // 1, a run and its jobs have been inserted
+	OutboxEventRunDone                               // 2, all jobs of a run are done
+)
+
+// String returns the name of the type used by the API
+func (t OutboxEventType) String() string {
+	switch t {
+	case OutboxEventRunCreated:
+		return "run_created"
+	case OutboxEventRunDone:
+		return "run_done"
+	}
+	return "unknown"
+}
+
+// OutboxStep is a side effect of an outbox event, the steps done are recorded so the retries of the event don't repeat them
+type OutboxStep int64
+
+const (
+	OutboxStepCommitStatuses   OutboxStep = 1 << iota // the commit statuses of the jobs are reported
+	OutboxStepLifecycleEvent                          // the lifecycle event of the run is published
+	OutboxStepEmitJobs                                // the jobs which need the skipped jobs are emitted
+	OutboxStepRunIssues                               // the issues of the previous failures of the workflow are closed
+	OutboxStepRelease                                 // the release of the tag of the run is created
+	OutboxStepScratchImages                           // the scratch images of the run are removed from the container registry
+	OutboxStepPostRunHook                             // the post-run hook of the external system is called
+	OutboxStepNotification                            // the notifiers are notified that the run is completed
+	OutboxStepConcurrencyGroup                        // the next run of the concurrency group is started
+)
+
+var outboxStepNames = []string{
+	"commit_statuses", "lifecycle_event", "emit_jobs", "run_issues", "release",
+	"scratch_images", "post_run_hook", "notification", "concurrency_group",
+}
+
+// Names returns the names of the steps used by the API
+func (s OutboxStep) Names() []string {
+	names := make([]string, 0, len(outboxStepNames))
+	for i, name := range outboxStepNames {
+		if s&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ActionOutboxEvent represents a side effect (commit statuses, webhooks, notifications) of a change to a run.
+// It's inserted in the same transaction as the change, and deleted once the side effect has been processed,
+// so a crash between the commit of the change and the processing won't drop the side effect silently.
+// A failed event is retried with a backoff, and it's kept as dead after too many attempts to be inspected and retried by the admins.
+type ActionOutboxEvent struct {
+	ID              int64
+	RunID           int64 `xorm:"index"`
+	RepoID          int64 `xorm:"index"`
+	Type            OutboxEventType
+	Attempts        int
+	DoneSteps       OutboxStep         `xorm:"NOT NULL DEFAULT 0"`           // the steps done by the previous attempts
+	NextAttemptUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`     // the failed event isn't retried before it
+	IsDead          bool               `xorm:"INDEX NOT NULL DEFAULT false"` // the event has failed too many times, it's only retried by the admins
+	LastError       string             `xorm:"TEXT"`
+	Created         timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionOutboxEvent))
+}
+
+// InsertOutboxEvent records a side effect of the run, it should be called in the transaction changing the run
+func InsertOutboxEvent(ctx context.Context, run *ActionRun, typ OutboxEventType) error {
+	return db.Insert(ctx, &ActionOutboxEvent{
+		RunID:  run.ID,
+		RepoID: run.RepoID,
+		Type:   typ,
+	})
+}
+
+// GetOutboxEventByID returns the outbox event, or nil if it has been processed
+func GetOutboxEventByID(ctx context.Context, id int64) (*ActionOutboxEvent, error) {
+	event, has, err := db.GetByID[ActionOutboxEvent](ctx, id)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return event, nil
+}
+
+// GetDeadOutboxEventByID returns the outbox event which has failed too many times
+func GetDeadOutboxEventByID(ctx context.Context, id int64) (*ActionOutboxEvent, error) {
+	event, err := GetOutboxEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if event == nil || !event.IsDead {
+		return nil, util.NewNotExistErrorf("dead outbox event %d does not exist", id)
+	}
+	return event, nil
+}
+
+// MarkOutboxEventStepDone records the step of the outbox event is done, so it isn't repeated by the retries
+func MarkOutboxEventStepDone(ctx context.Context, event *ActionOutboxEvent, step OutboxStep) error {
+	event.DoneSteps |= step
+	_, err := db.GetEngine(ctx).ID(event.ID).Cols("done_steps").Update(event)
+	return err
+}
+
+// UpdateOutboxEventFailure records the error of a failed attempt of the outbox event,
+// it's retried after nextAttempt, or only by the admins if it's dead
+func UpdateOutboxEventFailure(ctx context.Context, event *ActionOutboxEvent, errMsg string, nextAttempt timeutil.TimeStamp, dead bool) error {
+	event.LastError = errMsg
+	event.NextAttemptUnix = nextAttempt
+	event.IsDead = dead
+	_, err := db.GetEngine(ctx).ID(event.ID).Cols("last_error", "next_attempt_unix", "is_dead").Update(event)
+	return err
+}
+
+// ResetOutboxEvent makes the dead outbox event pending again with its attempts reset, the steps done are kept
+func ResetOutboxEvent(ctx context.Context, event *ActionOutboxEvent) error {
+	event.Attempts = 0
+	event.NextAttemptUnix = 0
+	event.IsDead = false
+	_, err := db.GetEngine(ctx).ID(event.ID).Cols("attempts", "next_attempt_unix", "is_dead").Update(event)
+	return err
+}
+
+// IncreaseOutboxEventAttempts increases the attempts of processing the outbox event
+func IncreaseOutboxEventAttempts(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Incr("attempts").Update(new(ActionOutboxEvent))
+	return err
+}
+
+// DeleteOutboxEvent deletes the outbox event after it has been processed
+func DeleteOutboxEvent(ctx context.Context, id int64) error {
+	_, err := db.DeleteByID[ActionOutboxEvent](ctx, id)
+	return err
+}
+
+type FindOutboxEventOptions struct {
+	db.ListOptions
+	RunID         int64
+	RepoID        int64
+	CreatedBefore timeutil.TimeStamp
+	DueBefore     timeutil.TimeStamp // the events which could be attempted before the time
+	IsDead        optional.Option[bool]
+}
+
+func (opts FindOutboxEventOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.CreatedBefore > 0 {
+		cond = cond.And(builder.Lt{"created": opts.CreatedBefore})
+	}
+	if opts.DueBefore > 0 {
+		cond = cond.And(builder.Lte{"next_attempt_unix": opts.DueBefore})
+	}
+	if opts.IsDead.Has() {
+		cond = cond.And(builder.Eq{"is_dead": opts.IsDead.Value()})
+	}
+	if opts.RunID > 0 {
+		cond = cond.And(builder.Eq{"run_id": opts.RunID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	return cond
+}
+
+func (opts FindOutboxEventOptions) ToOrders() string {
+	return "`id` ASC"
+}
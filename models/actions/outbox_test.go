@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxEventOfRunDone(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	job := unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: 192})
+	run := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: job.RunID})
+	run.Status = StatusRunning
+	run.Stopped = 0
+	assert.NoError(t, UpdateRun(db.DefaultContext, run, "status", "stopped"))
+
+	job.Status = StatusSuccess
+	_, err := UpdateRunJob(db.DefaultContext, job, nil, "status")
+	assert.NoError(t, err)
+
+	events, err := db.Find[ActionOutboxEvent](db.DefaultContext, FindOutboxEventOptions{RunID: run.ID})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, OutboxEventRunDone, events[0].Type)
+		assert.Equal(t, run.RepoID, events[0].RepoID)
+	}
+
+	// the event is recorded only when the run becomes done
+	_, err = UpdateRunJob(db.DefaultContext, job, nil, "status")
+	assert.NoError(t, err)
+	unittest.AssertCount(t, &ActionOutboxEvent{RunID: run.ID}, 1)
+
+	// the sweep skips the events which are probably being emitted
+	events, err = db.Find[ActionOutboxEvent](db.DefaultContext, FindOutboxEventOptions{CreatedBefore: events[0].Created})
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
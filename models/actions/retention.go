@@ -0,0 +1,109 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RetentionOptions selects the old records of a retention, they are ordered by id and start after AfterID,
+// so the callers could iterate them in batches.
+type RetentionOptions struct {
+	OlderThan      timeutil.TimeStamp
+	RepoID         int64   // 0 means all repositories
+	ExcludeRepoIDs []int64 // the repositories which have their own retentions
+	AfterID        int64
+	Limit          int
+}
+
+func (opts RetentionOptions) toConds() builder.Cond {
+	var cond builder.Cond = builder.Gt{"id": opts.AfterID}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if len(opts.ExcludeRepoIDs) > 0 {
+		cond = cond.And(builder.NotIn("repo_id", opts.ExcludeRepoIDs))
+	}
+	return cond
+}
+
+// FindOldTasksToExpire returns the tasks stopped before opts.OlderThan whose logs haven't expired
+func FindOldTasksToExpire(ctx context.Context, opts RetentionOptions) ([]*ActionTask, error) {
+	tasks := make([]*ActionTask, 0, opts.Limit)
+	return tasks, db.GetEngine(ctx).
+		Where(opts.toConds()).
+		And("stopped > 0 AND stopped < ? AND log_expired = ?", opts.OlderThan, false).
+		OrderBy("id").
+		Limit(opts.Limit).
+		Find(&tasks)
+}
+
+// FindOldRunsToDelete returns the done runs stopped before opts.OlderThan,
+// the runs done without starting are judged by their last update.
+func FindOldRunsToDelete(ctx context.Context, opts RetentionOptions) ([]*ActionRun, error) {
+	runs := make([]*ActionRun, 0, opts.Limit)
+	return runs, db.GetEngine(ctx).
+		Where(opts.toConds()).
+		And(builder.In("status", StatusSuccess, StatusFailure, StatusCancelled, StatusSkipped)).
+		And(builder.Or(
+			builder.Gt{"stopped": 0}.And(builder.Lt{"stopped": opts.OlderThan}),
+			builder.Eq{"stopped": 0}.And(builder.Lt{"updated": opts.OlderThan}),
+		)).
+		OrderBy("id").
+		Limit(opts.Limit).
+		Find(&runs)
+}
+
+// FindExpiredRunsToDelete returns the done runs which expired before opts.OlderThan, see ActionRun.ExpiredUnix
+func FindExpiredRunsToDelete(ctx context.Context, opts RetentionOptions) ([]*ActionRun, error) {
+	runs := make([]*ActionRun, 0, opts.Limit)
+	return runs, db.GetEngine(ctx).
+		Where(opts.toConds()).
+		And(builder.In("status", StatusSuccess, StatusFailure, StatusCancelled, StatusSkipped)).
+		And(builder.Gt{"expired_unix": 0}.And(builder.Lt{"expired_unix": opts.OlderThan})).
+		OrderBy("id").
+		Limit(opts.Limit).
+		Find(&runs)
+}
+
+// DeleteRun deletes the run with its jobs, tasks, steps, outputs, summaries, artifacts, handoff blobs, comments, events and token activities.
+// The files in the storages aren't removed, the callers should find and remove them before.
+// The usages are kept for the statistics.
+func DeleteRun(ctx context.Context, run *ActionRun) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+
+		jobIDs := builder.Select("id").From("action_run_job").Where(builder.Eq{"run_id": run.ID})
+		var taskIDs []int64
+		if err := e.Table("action_task").In("job_id", jobIDs).Cols("id").Find(&taskIDs); err != nil {
+			return err
+		}
+		if len(taskIDs) > 0 {
+			for _, bean := range []any{&ActionTaskStep{}, &ActionTaskOutput{}, &ActionTaskSummary{}} {
+				if _, err := e.In("task_id", taskIDs).Delete(bean); err != nil {
+					return err
+				}
+			}
+			if _, err := e.In("id", taskIDs).Delete(&ActionTask{}); err != nil {
+				return err
+			}
+		}
+
+		for _, bean := range []any{
+			&ActionRunJob{}, &ActionArtifact{}, &ActionHandoffBlob{},
+			&ActionRunComment{}, &ActionRunIssue{}, &ActionOutboxEvent{}, &ActionTokenActivity{},
+		} {
+			if _, err := e.Where("run_id = ?", run.ID).Delete(bean); err != nil {
+				return err
+			}
+		}
+		_, err := e.ID(run.ID).NoAutoCondition().Delete(&ActionRun{})
+		return err
+	})
+}
@@ -6,6 +6,7 @@ package actions
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
 	"slices"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -26,33 +28,58 @@ import (
 
 // ActionRun represents a run of a workflow file
 type ActionRun struct {
-	ID                int64
-	Title             string
-	RepoID            int64                  `xorm:"index unique(repo_index)"`
-	Repo              *repo_model.Repository `xorm:"-"`
-	OwnerID           int64                  `xorm:"index"`
-	WorkflowID        string                 `xorm:"index"`                    // the name of workflow file
-	Index             int64                  `xorm:"index unique(repo_index)"` // a unique number for each run of a repository
-	TriggerUserID     int64                  `xorm:"index"`
-	TriggerUser       *user_model.User       `xorm:"-"`
-	ScheduleID        int64
-	Ref               string `xorm:"index"` // the commit/tag/… that caused the run
-	CommitSHA         string
-	IsForkPullRequest bool                         // If this is triggered by a PR from a forked repository or an untrusted user, we need to check if it is approved and limit permissions when running the workflow.
-	NeedApproval      bool                         // may need approval if it's a fork pull request
-	ApprovedBy        int64                        `xorm:"index"` // who approved
-	Event             webhook_module.HookEventType // the webhook event that causes the workflow to run
-	EventPayload      string                       `xorm:"LONGTEXT"`
-	TriggerEvent      string                       // the trigger event defined in the `on` configuration of the triggered workflow
-	Status            Status                       `xorm:"index"`
-	Version           int                          `xorm:"version default 0"` // Status could be updated concomitantly, so an optimistic lock is needed
+	ID                  int64
+	Title               string
+	RepoID              int64                  `xorm:"index unique(repo_index)"`
+	Repo                *repo_model.Repository `xorm:"-"`
+	OwnerID             int64                  `xorm:"index"`
+	WorkflowID          string                 `xorm:"index"`                    // the name of workflow file
+	Index               int64                  `xorm:"index unique(repo_index)"` // a unique number for each run of a repository
+	TriggerUserID       int64                  `xorm:"index"`
+	TriggerUser         *user_model.User       `xorm:"-"`
+	ScheduleID          int64
+	Ref                 string `xorm:"index"` // the commit/tag/… that caused the run
+	CommitSHA           string
+	IsForkPullRequest   bool                         // If this is triggered by a PR from a forked repository or an untrusted user, we need to check if it is approved and limit permissions when running the workflow.
+	NeedApproval        bool                         // may need approval if it's a fork pull request
+	ApprovedBy          int64                        `xorm:"index"` // who approved
+	Event               webhook_module.HookEventType // the webhook event that causes the workflow to run
+	EventPayload        string                       `xorm:"LONGTEXT"`
+	EventPayloadVersion int                          `xorm:"NOT NULL DEFAULT 1"` // the format version of EventPayload, see GetEventPayload
+	TriggerEvent        string                       // the trigger event defined in the `on` configuration of the triggered workflow
+	Status              Status                       `xorm:"index"`
+	Version             int                          `xorm:"version default 0"` // Status could be updated concomitantly, so an optimistic lock is needed
 	// Started and Stopped is used for recording last run time, if rerun happened, they will be reset to 0
 	Started timeutil.TimeStamp
 	Stopped timeutil.TimeStamp
 	// PreviousDuration is used for recording previous duration
 	PreviousDuration time.Duration
-	Created          timeutil.TimeStamp `xorm:"created"`
-	Updated          timeutil.TimeStamp `xorm:"updated"`
+	// ExternalSystem is the name of the external CI system which reported the run, it's empty for runs of Gitea Actions
+	ExternalSystem string
+	ExternalURL    string `xorm:"TEXT"` // the link to the run in the external CI system
+	// MissingRequirements are the secrets and variables required by the workflow but not defined, like "secrets.NAME" and "vars.NAME".
+	// The run needs an approval to start, and it can't be approved until they are defined.
+	MissingRequirements []string `xorm:"JSON TEXT"`
+	// AcknowledgedBy is who acknowledged the failure of the run as known or won't fix, see IsAcknowledged.
+	// It's reset when the run is rerun.
+	AcknowledgedBy      int64              `xorm:"index"`
+	AcknowledgedComment string             `xorm:"TEXT"`
+	Acknowledged        timeutil.TimeStamp // when the failure was acknowledged
+	// ConcurrencyGroup is the evaluated workflow-level concurrency group, the runs in the same group of a repository run one by one.
+	// ConcurrencyCancel is whether the run cancels the runs in the group which are in progress, see CancelConcurrentRuns.
+	ConcurrencyGroup  string `xorm:"index"`
+	ConcurrencyCancel bool
+	// DuplicateDeliveries is how many times the event of the run was delivered again and skipped, see MarkDuplicateRun
+	DuplicateDeliveries int64 `xorm:"NOT NULL DEFAULT 0"`
+	// Component is the component of a monorepo which the run belongs to, empty if none, see actions_module.ParseWorkflowComponent
+	Component string       `xorm:"VARCHAR(255) index"`
+	Checkout  *RunCheckout `xorm:"JSON TEXT"` // the checkout hints declared by the workflow, nil if none
+	Egress    *RunEgress   `xorm:"JSON TEXT"` // the egress policy of the jobs, nil if they could connect to anywhere
+	// ExpiredUnix is when the done run is deleted with its logs and artifacts regardless of the retentions, 0 if it isn't,
+	// it's set when the pull request of the run is closed without merging, see SetPullRequestRunsExpired
+	ExpiredUnix timeutil.TimeStamp `xorm:"index NOT NULL DEFAULT 0"`
+	Created     timeutil.TimeStamp `xorm:"created"`
+	Updated     timeutil.TimeStamp `xorm:"updated"`
 }
 
 func init() {
@@ -60,25 +87,38 @@ func init() {
 	db.RegisterModel(new(ActionRunIndex))
 }
 
+// IsExternal returns whether the run is reported by an external CI system rather than executed by Gitea Actions
+func (run *ActionRun) IsExternal() bool {
+	return run.ExternalSystem != ""
+}
+
 func (run *ActionRun) HTMLURL() string {
 	if run.Repo == nil {
 		return ""
 	}
-	return fmt.Sprintf("%s/actions/runs/%d", run.Repo.HTMLURL(), run.Index)
+	return run.Repo.HTMLURL() + RunPath(run.Index)
+}
+
+// APIURL returns the url of the run in the API
+func (run *ActionRun) APIURL() string {
+	if run.Repo == nil {
+		return ""
+	}
+	return run.Repo.APIURL() + RunPath(run.Index)
 }
 
 func (run *ActionRun) Link() string {
 	if run.Repo == nil {
 		return ""
 	}
-	return fmt.Sprintf("%s/actions/runs/%d", run.Repo.Link(), run.Index)
+	return run.Repo.Link() + RunPath(run.Index)
 }
 
 func (run *ActionRun) WorkflowLink() string {
 	if run.Repo == nil {
 		return ""
 	}
-	return fmt.Sprintf("%s/actions/?workflow=%s", run.Repo.Link(), run.WorkflowID)
+	return run.Repo.Link() + WorkflowPath(run.WorkflowID)
 }
 
 // RefLink return the url of run's ref
@@ -143,8 +183,12 @@ func (run *ActionRun) Duration() time.Duration {
 
 func (run *ActionRun) GetPushEventPayload() (*api.PushPayload, error) {
 	if run.Event == webhook_module.HookEventPush {
+		eventPayload, err := run.GetEventPayload()
+		if err != nil {
+			return nil, err
+		}
 		var payload api.PushPayload
-		if err := json.Unmarshal([]byte(run.EventPayload), &payload); err != nil {
+		if err := json.Unmarshal([]byte(eventPayload), &payload); err != nil {
 			return nil, err
 		}
 		return &payload, nil
@@ -154,8 +198,12 @@ func (run *ActionRun) GetPushEventPayload() (*api.PushPayload, error) {
 
 func (run *ActionRun) GetPullRequestEventPayload() (*api.PullRequestPayload, error) {
 	if run.Event == webhook_module.HookEventPullRequest || run.Event == webhook_module.HookEventPullRequestSync {
+		eventPayload, err := run.GetEventPayload()
+		if err != nil {
+			return nil, err
+		}
 		var payload api.PullRequestPayload
-		if err := json.Unmarshal([]byte(run.EventPayload), &payload); err != nil {
+		if err := json.Unmarshal([]byte(eventPayload), &payload); err != nil {
 			return nil, err
 		}
 		return &payload, nil
@@ -163,10 +211,31 @@ func (run *ActionRun) GetPullRequestEventPayload() (*api.PullRequestPayload, err
 	return nil, fmt.Errorf("event %s is not a pull request event", run.Event)
 }
 
+func (run *ActionRun) GetWorkflowDispatchEventPayload() (*api.WorkflowDispatchPayload, error) {
+	if run.Event == webhook_module.HookEventWorkflowDispatch {
+		eventPayload, err := run.GetEventPayload()
+		if err != nil {
+			return nil, err
+		}
+		var payload api.WorkflowDispatchPayload
+		if err := json.Unmarshal([]byte(eventPayload), &payload); err != nil {
+			return nil, err
+		}
+		return &payload, nil
+	}
+	return nil, fmt.Errorf("event %s is not a workflow dispatch event", run.Event)
+}
+
 func (run *ActionRun) IsSchedule() bool {
 	return run.ScheduleID > 0
 }
 
+// CanRedeliverEvent returns whether the event which triggered the run could be delivered again to trigger new runs,
+// the runs of schedules and external CI systems aren't triggered by their stored events
+func (run *ActionRun) CanRedeliverEvent() bool {
+	return !run.IsSchedule() && !run.IsExternal() && run.EventPayload != ""
+}
+
 func updateRepoRunsNumbers(ctx context.Context, repo *repo_model.Repository) error {
 	_, err := db.GetEngine(ctx).ID(repo.ID).
 		SetExpr("num_action_runs",
@@ -213,67 +282,362 @@ func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID strin
 
 	// Iterate over each found run and cancel its associated jobs.
 	for _, run := range runs {
-		// Find all jobs associated with the current run.
-		jobs, err := db.Find[ActionRunJob](ctx, FindRunJobOptions{
-			RunID: run.ID,
-		})
-		if err != nil {
+		if err := cancelJobsOfRun(ctx, run.ID); err != nil {
 			return err
 		}
+	}
 
-		// Iterate over each job and attempt to cancel it.
-		for _, job := range jobs {
-			// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
-			status := job.Status
-			if status.IsDone() {
-				continue
-			}
+	// Return nil to indicate successful cancellation of all running and waiting jobs.
+	return nil
+}
+
+// MarkDuplicateRun claims the delivery of the event of the run to be created, see ClaimRunDelivery.
+// If the same delivery has been claimed recently, it returns true and counts the duplicate delivery on the latest run
+// of the same commit, ref, workflow and event, then the run shouldn't be created.
+// It's for the events delivered twice, like the pushes of a mirror racing with another sync.
+func MarkDuplicateRun(ctx context.Context, run *ActionRun, before string) (bool, error) {
+	claimed, err := ClaimRunDelivery(ctx, run, before)
+	if err != nil || claimed {
+		return false, err
+	}
+	existing := new(ActionRun)
+	has, err := db.GetEngine(ctx).
+		Where(builder.Eq{
+			"repo_id":     run.RepoID,
+			"ref":         run.Ref,
+			"commit_sha":  run.CommitSHA,
+			"workflow_id": run.WorkflowID,
+			"event":       run.Event,
+		}).
+		Desc("id").
+		Cols("id").
+		Get(existing)
+	if err != nil {
+		return false, err
+	}
+	if !has {
+		// the run of the first delivery is still being created, or it couldn't be created
+		return true, nil
+	}
+	// not updated by the bean, which would bump the version of the optimistic lock and fail the concurrent updates of the status
+	if _, err := db.GetEngine(ctx).Exec("UPDATE `action_run` SET duplicate_deliveries = duplicate_deliveries + 1 WHERE id = ?", existing.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CancelOutdatedPullRequestRuns cancels the runs of the pull request events on the ref of a pull request
+// which were triggered by other commits than the head commit, the running runs are only cancelled if includeRunning is true.
+// It returns the jobs cancelled, so their commit statuses could be updated.
+func CancelOutdatedPullRequestRuns(ctx context.Context, repoID int64, ref, headCommitSHA string, includeRunning bool) ([]*ActionRunJob, error) {
+	status := []Status{StatusWaiting, StatusBlocked}
+	if includeRunning {
+		status = append(status, StatusRunning)
+	}
+	var runs []*ActionRun
+	if err := db.GetEngine(ctx).
+		Where("repo_id = ? AND ref = ? AND commit_sha <> ?", repoID, ref, headCommitSHA).
+		In("event", webhook_module.HookEventPullRequest, webhook_module.HookEventPullRequestSync).
+		In("status", status).
+		Find(&runs); err != nil {
+		return nil, err
+	}
 
-			// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
-			if job.TaskID == 0 {
-				job.Status = StatusCancelled
-				job.Stopped = timeutil.TimeStampNow()
+	return cancelRuns(ctx, runs)
+}
 
-				// Update the job's status and stopped time in the database.
-				n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
-				if err != nil {
-					return err
-				}
+// CancelPullRequestRuns cancels the runs on the ref of a pull request, like when it's closed without merging,
+// the running runs are only cancelled if includeRunning is true.
+// It returns the jobs cancelled, so their commit statuses could be updated.
+func CancelPullRequestRuns(ctx context.Context, repoID int64, ref string, includeRunning bool) ([]*ActionRunJob, error) {
+	status := []Status{StatusWaiting, StatusBlocked}
+	if includeRunning {
+		status = append(status, StatusRunning)
+	}
+	var runs []*ActionRun
+	if err := db.GetEngine(ctx).
+		Where("repo_id = ? AND ref = ?", repoID, ref).
+		In("status", status).
+		Find(&runs); err != nil {
+		return nil, err
+	}
 
-				// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
-				if n == 0 {
-					return fmt.Errorf("job has changed, try again")
-				}
+	return cancelRuns(ctx, runs)
+}
 
-				// Continue with the next job.
-				continue
-			}
+// SetPullRequestRunsExpired sets when the runs on the ref of a pull request are deleted regardless of the retentions,
+// 0 keeps them by the retentions again, like when the pull request is reopened
+func SetPullRequestRunsExpired(ctx context.Context, repoID int64, ref string, expired timeutil.TimeStamp) error {
+	// not updated by the beans, which would bump the versions of the optimistic lock and fail the concurrent updates of the statuses
+	_, err := db.GetEngine(ctx).Exec("UPDATE `action_run` SET expired_unix = ? WHERE repo_id = ? AND ref = ?", expired, repoID, ref)
+	return err
+}
+
+// CancelExcessQueuedRuns cancels the oldest queued runs of the repository which aren't of the default branch,
+// until at most limit runs are queued, and returns the jobs of the cancelled runs. The queued runs are waiting or blocked.
+func CancelExcessQueuedRuns(ctx context.Context, repoID int64, defaultBranchRef string, limit int64) ([]*ActionRunJob, error) {
+	queued := []Status{StatusWaiting, StatusBlocked}
+	count, err := db.Count[ActionRun](ctx, FindRunOptions{RepoID: repoID, Status: queued})
+	if err != nil {
+		return nil, err
+	}
+	if count <= limit {
+		return nil, nil
+	}
 
-			// If the job has an associated task, try to stop the task, effectively cancelling the job.
-			if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
+	var runs []*ActionRun
+	if err := db.GetEngine(ctx).
+		Where("repo_id = ? AND ref <> ?", repoID, defaultBranchRef).
+		In("status", queued).
+		OrderBy("id").
+		Limit(int(count - limit)).
+		Find(&runs); err != nil {
+		return nil, err
+	}
+
+	return cancelRuns(ctx, runs)
+}
+
+// cancelRuns cancels the jobs of the runs in a transaction, and returns the jobs of the runs
+func cancelRuns(ctx context.Context, runs []*ActionRun) ([]*ActionRunJob, error) {
+	var jobs []*ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		for _, run := range runs {
+			if err := cancelJobsOfRun(ctx, run.ID); err != nil {
 				return err
 			}
+			runJobs, err := GetRunJobsByRunID(ctx, run.ID)
+			if err != nil {
+				return err
+			}
+			jobs = append(jobs, runJobs...)
 		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
+	return jobs, nil
+}
 
-	// Return nil to indicate successful cancellation of all running and waiting jobs.
+// CancelRun cancels the jobs of the run which aren't done
+func CancelRun(ctx context.Context, runID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		return cancelJobsOfRun(ctx, runID)
+	})
+}
+
+// cancelJobsOfRun cancels the jobs of the run which aren't done, the tasks of the running jobs are stopped
+func cancelJobsOfRun(ctx context.Context, runID int64) error {
+	// Find all jobs associated with the current run.
+	jobs, err := db.Find[ActionRunJob](ctx, FindRunJobOptions{
+		RunID: runID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Iterate over each job and attempt to cancel it.
+	for _, job := range jobs {
+		// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
+		status := job.Status
+		if status.IsDone() {
+			continue
+		}
+
+		// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
+		if job.TaskID == 0 {
+			job.Status = StatusCancelled
+			job.Stopped = timeutil.TimeStampNow()
+
+			// Update the job's status and stopped time in the database.
+			n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
+			if err != nil {
+				return err
+			}
+
+			// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
+			if n == 0 {
+				return fmt.Errorf("job has changed, try again")
+			}
+
+			// Continue with the next job.
+			continue
+		}
+
+		// If the job has an associated task, try to stop the task, effectively cancelling the job.
+		if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// RunWithJobs is a run to be inserted with the parsed workflows of its jobs
+type RunWithJobs struct {
+	Run  *ActionRun
+	Jobs []*jobparser.SingleWorkflow
+	// PreflightErrors are the errors of the jobs which don't pass the preflight checks, keyed by job ids.
+	// If there are any, the run fails immediately: the failed jobs keep their errors and the other jobs are cancelled.
+	PreflightErrors map[string]string
+	// TokenPermissions are the `permissions` declared by the jobs, keyed by job ids, see ActionRunJob.TokenPermissions
+	TokenPermissions map[string]map[string]string
+	// Environments are the environments the jobs deploy to, keyed by job ids, see ActionRunJob.Environment
+	Environments map[string]string
+}
+
+var (
+	// runIndexMaxAttempts is the max number of attempts to allocate the run index when it's contended
+	runIndexMaxAttempts = 5
+	// runIndexRetryBackoff is the initial backoff between the attempts, it doubles after each attempt
+	runIndexRetryBackoff = 20 * time.Millisecond
+)
+
 // InsertRun inserts a run
 func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWorkflow) error {
-	ctx, committer, err := db.TxContext(ctx)
+	return InsertRunWithJobs(ctx, &RunWithJobs{Run: run, Jobs: jobs})
+}
+
+// InsertRunWithJobs inserts a run with its jobs in a transaction of its own,
+// so a failed run doesn't affect the other runs triggered by the same event.
+// The run is inserted with its index if it has been reserved by ReserveRunIndexes, otherwise a new index is allocated,
+// and if the transaction fails because of a conflict on the run index, it will be retried with backoff.
+func InsertRunWithJobs(ctx context.Context, r *RunWithJobs) error {
+	cfg, err := getActionsConfigOfRun(ctx, r.Run)
 	if err != nil {
 		return err
 	}
-	defer committer.Close()
-
-	index, err := db.GetNextResourceIndex(ctx, "action_run_index", run.RepoID)
+	overrides, err := getRunsOnOverridesOfRun(ctx, r.Run, cfg)
 	if err != nil {
 		return err
 	}
-	run.Index = index
+	jobs, err := newRunJobs(r.Run, r.Jobs, overrides)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		job.TokenPermissions = r.TokenPermissions[job.JobID]
+		job.Environment = strings.ToLower(r.Environments[job.JobID])
+	}
+	if len(r.PreflightErrors) > 0 {
+		failPreflight(r.Run, jobs, r.PreflightErrors)
+	} else if cfg != nil {
+		skipDisabledJobs(r.Run, jobs, cfg)
+	}
+	return insertRunWithRetry(ctx, r.Run, jobs)
+}
+
+// skipDisabledJobs skips the jobs disabled in the Actions settings of the repository,
+// the jobs which need them are skipped by the job emitter unless they always run.
+// The run is done immediately if all of its jobs are disabled.
+func skipDisabledJobs(run *ActionRun, jobs []*ActionRunJob, cfg *repo_model.ActionsConfig) {
+	if len(cfg.DisabledJobs) == 0 {
+		return
+	}
+
+	allSkipped := len(jobs) > 0
+	for _, job := range jobs {
+		if cfg.IsJobDisabled(run.WorkflowID, job.JobID, job.Name) {
+			job.Status = StatusSkipped
+			job.Queued = 0
+		} else {
+			allSkipped = false
+		}
+	}
+	if allSkipped {
+		now := timeutil.TimeStampNow()
+		run.Status = aggregateJobStatus(jobs)
+		run.Started = now
+		run.Stopped = now
+	}
+}
+
+// isErrRunIndexConflict returns whether the error is caused by concurrent transactions competing for the index of runs
+func isErrRunIndexConflict(err error) bool {
+	return db.IsErrResourceIndexConflict(err, "UQE_action_run_repo_index", "action_run.repo_id, action_run.index")
+}
+
+// jitterBackoff returns a random delay between a half and one and a half of the backoff,
+// so the transactions which conflicted on the run index don't retry in lockstep
+func jitterBackoff(backoff time.Duration) time.Duration {
+	return backoff/2 + rand.N(backoff)
+}
+
+// retryRunIndexConflict calls fn until it doesn't fail because of a conflict on the run index of the repository,
+// with an exponential backoff and jitter between the attempts. It gives up after runIndexMaxAttempts attempts.
+func retryRunIndexConflict(ctx context.Context, repoID int64, fn func() error) error {
+	if db.InTransaction(ctx) {
+		// a failed statement may abort the outer transaction, so it's impossible to retry here
+		return fn()
+	}
+
+	backoff := runIndexRetryBackoff
+	for i := 1; ; i++ {
+		err := fn()
+		if err == nil || !isErrRunIndexConflict(err) {
+			return err
+		}
+		if i >= runIndexMaxAttempts {
+			return fmt.Errorf("conflict on the run index of repo %d after %d attempts: %w", repoID, i, err)
+		}
+		delay := jitterBackoff(backoff)
+		log.Debug("conflict on the run index of repo %d, retry %d/%d after %v: %v", repoID, i, runIndexMaxAttempts-1, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
+
+// ReserveRunIndexes reserves count consecutive indexes of the runs of the repository and returns the first one,
+// so the runs triggered by the same event contend the index record only once. The reservation is committed at once,
+// then the runs are inserted with the reserved indexes by InsertRunWithJobs, the indexes of the runs which fail are skipped.
+func ReserveRunIndexes(ctx context.Context, repoID, count int64) (int64, error) {
+	var first int64
+	err := retryRunIndexConflict(ctx, repoID, func() error {
+		return db.WithTx(ctx, func(ctx context.Context) error {
+			index, err := db.GetNextResourceIndexes(ctx, "action_run_index", repoID, count)
+			first = index
+			return err
+		})
+	})
+	return first, err
+}
+
+// insertRunWithRetry inserts the run with its jobs, and retries if there is a conflict on the run index,
+// the run keeps its index if it has been reserved by ReserveRunIndexes
+func insertRunWithRetry(ctx context.Context, run *ActionRun, runJobs []*ActionRunJob) error {
+	reserved := run.Index
+	return retryRunIndexConflict(ctx, run.RepoID, func() error {
+		// reset what the failed attempt has set
+		run.ID = 0
+		run.Index = reserved
+		for _, job := range runJobs {
+			job.ID = 0
+			job.RunID = 0
+		}
+		return insertRunWithIndex(ctx, run, runJobs)
+	})
+}
+
+func insertRunWithIndex(ctx context.Context, run *ActionRun, runJobs []*ActionRunJob) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if run.Index == 0 {
+			index, err := db.GetNextResourceIndex(ctx, "action_run_index", run.RepoID)
+			if err != nil {
+				return err
+			}
+			run.Index = index
+		}
+		return insertRun(ctx, run, runJobs)
+	})
+}
 
+func insertRun(ctx context.Context, run *ActionRun, runJobs []*ActionRunJob) error {
+	if err := holdRunByConcurrency(ctx, run, runJobs); err != nil {
+		return err
+	}
 	if err := db.Insert(ctx, run); err != nil {
 		return err
 	}
@@ -290,48 +654,107 @@ func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWork
 		return err
 	}
 
-	runJobs := make([]*ActionRunJob, 0, len(jobs))
 	var hasWaiting bool
+	for _, job := range runJobs {
+		job.RunID = run.ID
+		if job.Status == StatusWaiting {
+			hasWaiting = true
+		}
+	}
+	if err := db.Insert(ctx, runJobs); err != nil {
+		return err
+	}
+
+	// if there is a job in the waiting status, increase tasks version.
+	if hasWaiting {
+		if err := IncreaseTaskVersion(ctx, run.OwnerID, run.RepoID); err != nil {
+			return err
+		}
+	}
+
+	// the side effects of the new run are processed after the transaction has been committed
+	if err := InsertOutboxEvent(ctx, run, OutboxEventRunCreated); err != nil {
+		return err
+	}
+	if run.Status.IsDone() {
+		// e.g. the run failed the preflight checks, or it's an external run reported when it's done
+		return InsertOutboxEvent(ctx, run, OutboxEventRunDone)
+	}
+	return nil
+}
+
+// failPreflight marks the run and its jobs as done since some jobs don't pass the preflight checks
+func failPreflight(run *ActionRun, jobs []*ActionRunJob, errs map[string]string) {
+	now := timeutil.TimeStampNow()
+	run.Status = StatusFailure
+	run.Started = now
+	run.Stopped = now
+	for _, job := range jobs {
+		if msg, ok := errs[job.JobID]; ok {
+			job.Status = StatusFailure
+			job.PreflightError = msg
+		} else {
+			job.Status = StatusCancelled
+		}
+		job.Started = now
+		job.Stopped = now
+	}
+}
+
+// newRunJobs converts the parsed workflows to the jobs of the run, they are inserted after the run.
+// The runs-on labels of the jobs are overridden by the matching overrides in order.
+func newRunJobs(run *ActionRun, jobs []*jobparser.SingleWorkflow, overrides []*repo_model.ActionsRunsOnOverride) ([]*ActionRunJob, error) {
+	runJobs := make([]*ActionRunJob, 0, len(jobs))
 	for _, v := range jobs {
 		id, job := v.Job()
 		needs := job.Needs()
+		if err := overrideRunsOn(id, job, overrides); err != nil {
+			return nil, err
+		}
 		if err := v.SetJob(id, job.EraseNeeds()); err != nil {
-			return err
+			return nil, err
 		}
 		payload, _ := v.Marshal()
 		status := StatusWaiting
+		queued := timeutil.TimeStampNow()
 		if len(needs) > 0 || run.NeedApproval {
 			status = StatusBlocked
-		} else {
-			hasWaiting = true
+			queued = 0
 		}
 		job.Name, _ = util.SplitStringAtByteN(job.Name, 255)
 		runJobs = append(runJobs, &ActionRunJob{
-			RunID:             run.ID,
 			RepoID:            run.RepoID,
 			OwnerID:           run.OwnerID,
 			CommitSHA:         run.CommitSHA,
 			IsForkPullRequest: run.IsForkPullRequest,
 			Name:              job.Name,
+			Component:         run.Component,
 			WorkflowPayload:   payload,
 			JobID:             id,
 			Needs:             needs,
 			RunsOn:            job.RunsOn(),
 			Status:            status,
+			Queued:            queued,
 		})
 	}
-	if err := db.Insert(ctx, runJobs); err != nil {
-		return err
-	}
+	return runJobs, nil
+}
 
-	// if there is a job in the waiting status, increase tasks version.
-	if hasWaiting {
-		if err := IncreaseTaskVersion(ctx, run.OwnerID, run.RepoID); err != nil {
-			return err
+// overrideRunsOn overrides the runs-on labels of the job in its workflow payload,
+// so the runners see the same labels as the ones used to pick the job
+func overrideRunsOn(id string, job *jobparser.Job, overrides []*repo_model.ActionsRunsOnOverride) error {
+	runsOn := job.RunsOn()
+	overridden := false
+	for _, o := range overrides {
+		if o.Match(id, job.Name) {
+			runsOn = o.Apply(runsOn)
+			overridden = true
 		}
 	}
-
-	return committer.Commit()
+	if !overridden {
+		return nil
+	}
+	return job.RawRunsOn.Encode(runsOn)
 }
 
 func GetRunByID(ctx context.Context, id int64) (*ActionRun, error) {
@@ -378,23 +801,98 @@ func GetWorkflowLatestRun(ctx context.Context, repoID int64, workflowFile, branc
 	return &run, nil
 }
 
+// GetWorkflowLatestJobStatus returns the status of the job of the latest run of the workflow on the branch which has the job,
+// the job is matched by its id in the workflow or its name, the statuses of the jobs of a matrix are aggregated like the ones of a run.
+func GetWorkflowLatestJobStatus(ctx context.Context, repoID int64, workflowFile, branch, event, jobName string) (Status, error) {
+	cond := builder.Eq{
+		"`action_run`.repo_id":     repoID,
+		"`action_run`.ref":         branch,
+		"`action_run`.workflow_id": workflowFile,
+	}.And(builder.Or(builder.Eq{"`action_run_job`.job_id": jobName}, builder.Eq{"`action_run_job`.name": jobName}))
+	if event != "" {
+		cond = cond.And(builder.Eq{"`action_run`.event": event})
+	}
+
+	var runID int64
+	has, err := db.GetEngine(ctx).Table("action_run_job").
+		Join("INNER", "action_run", "`action_run`.id = `action_run_job`.run_id").
+		Where(cond).
+		Select("`action_run_job`.run_id").
+		Desc("`action_run_job`.run_id").
+		Get(&runID)
+	if err != nil {
+		return StatusUnknown, err
+	} else if !has {
+		return StatusUnknown, util.NewNotExistErrorf("job %s of workflow %s on ref %s of repo %d", jobName, workflowFile, branch, repoID)
+	}
+
+	var jobs []*ActionRunJob
+	if err := db.GetEngine(ctx).Where(builder.Eq{"run_id": runID}.
+		And(builder.Or(builder.Eq{"job_id": jobName}, builder.Eq{"name": jobName}))).
+		Find(&jobs); err != nil {
+		return StatusUnknown, err
+	}
+	if len(jobs) == 1 {
+		return jobs[0].Status, nil
+	}
+	return aggregateJobStatus(jobs), nil
+}
+
+// ErrConcurrentUpdate represents an error that a run or a job has been updated by others after it was loaded,
+// so the update is rejected instead of overwriting the changes, it could be retried with the reloaded one.
+type ErrConcurrentUpdate struct {
+	Kind string // "run" or "job"
+	ID   int64
+}
+
+func (err ErrConcurrentUpdate) Error() string {
+	return fmt.Sprintf("%s %d has been updated concurrently", err.Kind, err.ID)
+}
+
 // UpdateRun updates a run.
 // It requires the inputted run has Version set.
-// It will return error if the version is not matched (it means the run has been changed after loaded).
+// It will return ErrConcurrentUpdate if the version is not matched (it means the run has been changed after loaded).
+// If the status is updated, the transition is validated, ErrIllegalStatusTransition is returned if the run can't become the status.
 func UpdateRun(ctx context.Context, run *ActionRun, cols ...string) error {
+	// all the columns with values are updated if the columns aren't given
+	updatesStatus := slices.Contains(cols, "status") || len(cols) == 0 && run.Status != StatusUnknown
+	var from Status
+	if updatesStatus {
+		current, err := GetRunByID(ctx, run.ID)
+		if err != nil {
+			return err
+		}
+		if current.Version != run.Version {
+			return ErrConcurrentUpdate{Kind: "run", ID: run.ID}
+		}
+		if !current.Status.CanRunTransitTo(run.Status) {
+			return ErrIllegalStatusTransition{Kind: "run", ID: run.ID, From: current.Status, To: run.Status}
+		}
+		from = current.Status
+	}
+
 	sess := db.GetEngine(ctx).ID(run.ID)
 	if len(cols) > 0 {
 		sess.Cols(cols...)
 	}
+	version := run.Version
 	affected, err := sess.Update(run)
 	if err != nil {
 		return err
 	}
 	if affected == 0 {
-		return fmt.Errorf("run has changed")
+		// the version is increased even if nothing is updated, keep it to tell the run has been changed
+		run.Version = version
+		return ErrConcurrentUpdate{Kind: "run", ID: run.ID}
 		// It's impossible that the run is not found, since Gitea never deletes runs.
 	}
 
+	if updatesStatus {
+		if err := fireStatusTransition(ctx, &StatusTransition{Run: run, From: from, To: run.Status}); err != nil {
+			return err
+		}
+	}
+
 	if run.Status != 0 || slices.Contains(cols, "status") {
 		if run.RepoID == 0 {
 			run, err = GetRunByID(ctx, run.ID)
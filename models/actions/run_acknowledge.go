@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// IsAcknowledged returns whether the failure of the run has been acknowledged as known or won't fix,
+// so it shouldn't be counted as a failure which needs attention, like in the failure notifications and stats.
+func (run *ActionRun) IsAcknowledged() bool {
+	return run.AcknowledgedBy > 0
+}
+
+// CanBeAcknowledged returns whether the run has failed, only failed runs can be acknowledged
+func (run *ActionRun) CanBeAcknowledged() bool {
+	return run.Status == StatusFailure
+}
+
+// AcknowledgeRun acknowledges the failure of the run with a comment
+func AcknowledgeRun(ctx context.Context, run *ActionRun, doerID int64, comment string) error {
+	if !run.CanBeAcknowledged() {
+		return util.NewInvalidArgumentErrorf("run %d hasn't failed", run.ID)
+	}
+	run.AcknowledgedBy = doerID
+	run.AcknowledgedComment = comment
+	run.Acknowledged = timeutil.TimeStampNow()
+	return UpdateRun(ctx, run, "acknowledged_by", "acknowledged_comment", "acknowledged")
+}
+
+// UnacknowledgeRun withdraws the acknowledgement of the run, it's also used when the run is rerun
+func UnacknowledgeRun(ctx context.Context, run *ActionRun) error {
+	if !run.IsAcknowledged() {
+		return nil
+	}
+	run.AcknowledgedBy = 0
+	run.AcknowledgedComment = ""
+	run.Acknowledged = 0
+	return UpdateRun(ctx, run, "acknowledged_by", "acknowledged_comment", "acknowledged")
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcknowledgeRun(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	run := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	assert.ErrorIs(t, AcknowledgeRun(db.DefaultContext, run, 1, "flaky"), util.ErrInvalidArgument)
+
+	// the fixture has succeeded, and a result can't be replaced by UpdateRun
+	_, err := db.GetEngine(db.DefaultContext).ID(run.ID).Cols("status").Update(&ActionRun{Status: StatusFailure})
+	require.NoError(t, err)
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	require.NoError(t, AcknowledgeRun(db.DefaultContext, run, 1, "flaky"))
+
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	assert.True(t, run.IsAcknowledged())
+	assert.Equal(t, "flaky", run.AcknowledgedComment)
+	assert.NotZero(t, run.Acknowledged)
+
+	runs, err := db.Find[ActionRun](db.DefaultContext, FindRunOptions{RepoID: 4, Acknowledged: optional.Some(false)})
+	require.NoError(t, err)
+	for _, r := range runs {
+		assert.NotEqual(t, run.ID, r.ID)
+	}
+
+	require.NoError(t, UnacknowledgeRun(db.DefaultContext, run))
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	assert.False(t, run.IsAcknowledged())
+	assert.Empty(t, run.AcknowledgedComment)
+}
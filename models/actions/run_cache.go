@@ -0,0 +1,107 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// runCacheTTL is how long the jobs of runs and the steps of tasks are cached in seconds,
+// they're usually invalidated before that, it bounds how long a missed invalidation lasts
+const runCacheTTL = 60
+
+func runJobsCacheKey(runID int64) string {
+	return fmt.Sprintf("actions:run_jobs:%d", runID)
+}
+
+func taskStepsCacheKey(taskID int64) string {
+	return fmt.Sprintf("actions:task_steps:%d", taskID)
+}
+
+// cachedRunJobs are the jobs of a run cached with the version and the updated time of the run when they were loaded
+type cachedRunJobs struct {
+	Version int
+	Updated timeutil.TimeStamp
+	Jobs    []*ActionRunJob
+}
+
+// cachedTaskSteps are the steps of a task cached with the state of the task when they were loaded
+type cachedTaskSteps struct {
+	Status    Status
+	Updated   timeutil.TimeStamp
+	LogLength int64
+	Steps     []*ActionTaskStep
+}
+
+// GetRunJobsByRunIDCached returns the jobs of the run like GetRunJobsByRunID, from the cache if the run hasn't changed since they were cached.
+// Every change of the statuses of the jobs updates the run, so it's for the pages of the runs which are watched and reloaded frequently.
+func GetRunJobsByRunIDCached(ctx context.Context, run *ActionRun) ([]*ActionRunJob, error) {
+	c := cache.GetCache()
+	if c == nil {
+		return GetRunJobsByRunID(ctx, run.ID)
+	}
+
+	key := runJobsCacheKey(run.ID)
+	var cached cachedRunJobs
+	if exist, err := c.GetJSON(key, &cached); err != nil {
+		log.Warn("GetJSON %s: %v", key, err.ToError())
+	} else if exist && cached.Version == run.Version && cached.Updated == run.Updated {
+		return cached.Jobs, nil
+	}
+
+	jobs, err := GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.PutJSON(key, &cachedRunJobs{Version: run.Version, Updated: run.Updated, Jobs: jobs}, runCacheTTL); err != nil {
+		log.Warn("PutJSON %s: %v", key, err)
+	}
+	return jobs, nil
+}
+
+// GetTaskStepsCached returns the steps of the task like GetTaskStepsByTaskID, from the cache if the task hasn't changed since they were cached.
+// The runners update the task whenever they report the steps.
+func GetTaskStepsCached(ctx context.Context, task *ActionTask) ([]*ActionTaskStep, error) {
+	c := cache.GetCache()
+	if c == nil {
+		return GetTaskStepsByTaskID(ctx, task.ID)
+	}
+
+	key := taskStepsCacheKey(task.ID)
+	var cached cachedTaskSteps
+	if exist, err := c.GetJSON(key, &cached); err != nil {
+		log.Warn("GetJSON %s: %v", key, err.ToError())
+	} else if exist && cached.Status == task.Status && cached.Updated == task.Updated && cached.LogLength == task.LogLength {
+		return cached.Steps, nil
+	}
+
+	steps, err := GetTaskStepsByTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.PutJSON(key, &cachedTaskSteps{Status: task.Status, Updated: task.Updated, LogLength: task.LogLength, Steps: steps}, runCacheTTL); err != nil {
+		log.Warn("PutJSON %s: %v", key, err)
+	}
+	return steps, nil
+}
+
+func init() {
+	// the cached data is removed when the statuses change, in addition to being checked by the timestamps
+	OnStatusTransition(func(ctx context.Context, transition *StatusTransition) error {
+		switch {
+		case transition.Run != nil:
+			cache.Remove(runJobsCacheKey(transition.Run.ID))
+		case transition.Job != nil:
+			cache.Remove(runJobsCacheKey(transition.Job.RunID))
+		case transition.Task != nil:
+			cache.Remove(taskStepsCacheKey(transition.Task.ID))
+		}
+		return nil
+	})
+}
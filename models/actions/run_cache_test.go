@@ -0,0 +1,66 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunJobsByRunIDCached(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	run := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	jobs, err := GetRunJobsByRunIDCached(ctx, run)
+	require.NoError(t, err)
+	require.NotEmpty(t, jobs)
+	name := jobs[0].Name
+
+	// the changes which don't update the run aren't noticed
+	_, err = db.GetEngine(ctx).ID(jobs[0].ID).Cols("name").NoAutoTime().Update(&ActionRunJob{Name: "renamed"})
+	require.NoError(t, err)
+	jobs, err = GetRunJobsByRunIDCached(ctx, run)
+	require.NoError(t, err)
+	assert.Equal(t, name, jobs[0].Name)
+
+	// the run is updated when the statuses of its jobs change
+	run.Title = "updated"
+	require.NoError(t, UpdateRun(ctx, run, "title"))
+	jobs, err = GetRunJobsByRunIDCached(ctx, run)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", jobs[0].Name)
+}
+
+func TestGetTaskStepsCached(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	require.NoError(t, db.Insert(ctx, []*ActionTaskStep{
+		{TaskID: 47, Index: 0, Name: "Set up job", Status: StatusSuccess},
+		{TaskID: 47, Index: 1, Name: "Build", Status: StatusRunning},
+	}))
+	task := unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 47})
+	steps, err := GetTaskStepsCached(ctx, task)
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Equal(t, StatusRunning, steps[1].Status)
+
+	_, err = db.GetEngine(ctx).ID(steps[1].ID).Cols("status").Update(&ActionTaskStep{Status: StatusSuccess})
+	require.NoError(t, err)
+	steps, err = GetTaskStepsCached(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, steps[1].Status)
+
+	// the runners update the task when they report the steps
+	task.LogLength++
+	steps, err = GetTaskStepsCached(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSuccess, steps[1].Status)
+}
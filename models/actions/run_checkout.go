@@ -0,0 +1,12 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// RunCheckout are the hints of how the runners check out the repository for the jobs of a run,
+// they're declared by the workflow, see actions_module.WorkflowCheckout
+type RunCheckout struct {
+	Depth  int      `json:"depth,omitempty"`  // how many commits of the history to fetch, 0 for all
+	Sparse []string `json:"sparse,omitempty"` // the directories and files to check out, empty for all
+	Filter string   `json:"filter,omitempty"` // the partial clone filter of the objects to fetch, like "blob:none"
+}
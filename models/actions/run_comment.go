@@ -0,0 +1,145 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ActionRunComment is a comment for discussing a run, or a job of the run, like triaging the failure
+type ActionRunComment struct {
+	ID       int64
+	RepoID   int64            `xorm:"index"`
+	RunID    int64            `xorm:"index"`
+	JobID    int64            // the job which the comment is about, 0 if it's about the whole run
+	PosterID int64            `xorm:"index"`
+	Poster   *user_model.User `xorm:"-"`
+	Content  string           `xorm:"LONGTEXT"`
+
+	RenderedContent string `xorm:"-"`
+
+	Created timeutil.TimeStamp `xorm:"created"`
+	Updated timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionRunComment))
+}
+
+// LoadPoster loads the poster of the comment, it will be a ghost user if the poster has been deleted
+func (c *ActionRunComment) LoadPoster(ctx context.Context) error {
+	if c.Poster != nil {
+		return nil
+	}
+	var err error
+	c.Poster, err = user_model.GetPossibleUserByID(ctx, c.PosterID)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			return err
+		}
+		c.Poster = user_model.NewGhostUser()
+	}
+	return nil
+}
+
+type RunCommentList []*ActionRunComment
+
+// LoadPosters loads the posters of the comments
+func (comments RunCommentList) LoadPosters(ctx context.Context) error {
+	ids := container.FilterSlice(comments, func(c *ActionRunComment) (int64, bool) {
+		return c.PosterID, c.Poster == nil
+	})
+	users := make(map[int64]*user_model.User, len(ids))
+	if err := db.GetEngine(ctx).In("id", ids).Find(&users); err != nil {
+		return err
+	}
+	for _, c := range comments {
+		if c.Poster != nil {
+			continue
+		}
+		if c.Poster = users[c.PosterID]; c.Poster == nil {
+			c.Poster = user_model.NewGhostUser()
+		}
+	}
+	return nil
+}
+
+type FindRunCommentsOptions struct {
+	db.ListOptions
+	RunID    int64
+	JobID    optional.Option[int64] // 0 for the comments about the whole run
+	PosterID int64
+}
+
+func (opts FindRunCommentsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RunID > 0 {
+		cond = cond.And(builder.Eq{"run_id": opts.RunID})
+	}
+	if opts.JobID.Has() {
+		cond = cond.And(builder.Eq{"job_id": opts.JobID.Value()})
+	}
+	if opts.PosterID > 0 {
+		cond = cond.And(builder.Eq{"poster_id": opts.PosterID})
+	}
+	return cond
+}
+
+func (opts FindRunCommentsOptions) ToOrders() string {
+	return "`id` ASC"
+}
+
+// FindRunCommentsPosterIDs returns the ids of the users having commented on the run
+func FindRunCommentsPosterIDs(ctx context.Context, runID int64) ([]int64, error) {
+	var ids []int64
+	return ids, db.GetEngine(ctx).Table("action_run_comment").
+		Where("run_id=?", runID).Distinct("poster_id").Find(&ids)
+}
+
+// GetRunCommentByID gets a comment of the run
+func GetRunCommentByID(ctx context.Context, runID, id int64) (*ActionRunComment, error) {
+	var c ActionRunComment
+	has, err := db.GetEngine(ctx).Where("id=? AND run_id=?", id, runID).Get(&c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("run comment with id %d: %w", id, util.ErrNotExist)
+	}
+	return &c, nil
+}
+
+// CreateRunComment creates a comment, the job of it must belong to the run
+func CreateRunComment(ctx context.Context, c *ActionRunComment) error {
+	if c.JobID > 0 {
+		has, err := db.GetEngine(ctx).Where("id=? AND run_id=?", c.JobID, c.RunID).Exist(&ActionRunJob{})
+		if err != nil {
+			return err
+		} else if !has {
+			return util.NewInvalidArgumentErrorf("job %d doesn't belong to run %d", c.JobID, c.RunID)
+		}
+	}
+	return db.Insert(ctx, c)
+}
+
+// UpdateRunCommentContent updates the content of the comment
+func UpdateRunCommentContent(ctx context.Context, c *ActionRunComment) error {
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("content").Update(c)
+	return err
+}
+
+// DeleteRunComment deletes the comment
+func DeleteRunComment(ctx context.Context, id int64) error {
+	_, err := db.DeleteByID[ActionRunComment](ctx, id)
+	return err
+}
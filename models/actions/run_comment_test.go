@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunComment(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.ErrorIs(t, CreateRunComment(db.DefaultContext, &ActionRunComment{RepoID: 4, RunID: 791, JobID: 193, PosterID: 1, Content: "wrong job"}), util.ErrInvalidArgument)
+
+	onRun := &ActionRunComment{RepoID: 4, RunID: 791, PosterID: 1, Content: "flaky network"}
+	require.NoError(t, CreateRunComment(db.DefaultContext, onRun))
+	onJob := &ActionRunComment{RepoID: 4, RunID: 791, JobID: 192, PosterID: 2, Content: "see line 42"}
+	require.NoError(t, CreateRunComment(db.DefaultContext, onJob))
+
+	comments, err := db.Find[ActionRunComment](db.DefaultContext, FindRunCommentsOptions{RunID: 791})
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	require.NoError(t, RunCommentList(comments).LoadPosters(db.DefaultContext))
+	assert.EqualValues(t, 1, comments[0].Poster.ID)
+	assert.EqualValues(t, 2, comments[1].Poster.ID)
+
+	comments, err = db.Find[ActionRunComment](db.DefaultContext, FindRunCommentsOptions{RunID: 791, JobID: optional.Some[int64](192)})
+	require.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.Equal(t, onJob.ID, comments[0].ID)
+	}
+
+	onRun.Content = "not flaky, a real bug"
+	require.NoError(t, UpdateRunCommentContent(db.DefaultContext, onRun))
+	got, err := GetRunCommentByID(db.DefaultContext, 791, onRun.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "not flaky, a real bug", got.Content)
+	_, err = GetRunCommentByID(db.DefaultContext, 792, onRun.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	require.NoError(t, DeleteRunComment(db.DefaultContext, onRun.ID))
+	_, err = GetRunCommentByID(db.DefaultContext, 791, onRun.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ComponentStats are the statistics of the runs of a component of a monorepo
+type ComponentStats struct {
+	Component      string
+	TotalRuns      int64
+	SuccessfulRuns int64
+	FailedRuns     int64
+	CancelledRuns  int64
+	LatestRunID    int64
+	LatestRun      *ActionRun `xorm:"-"`
+}
+
+// GetComponentStats returns the statistics of the runs of the components of the repository ordered by the components,
+// the runs which don't belong to any component are omitted
+func GetComponentStats(ctx context.Context, repoID int64) ([]*ComponentStats, error) {
+	var stats []*ComponentStats
+	if err := db.GetEngine(ctx).Table("action_run").
+		Select(fmt.Sprintf("component, COUNT(*) AS total_runs, "+
+			"SUM(CASE WHEN status = %d THEN 1 ELSE 0 END) AS successful_runs, "+
+			"SUM(CASE WHEN status = %d THEN 1 ELSE 0 END) AS failed_runs, "+
+			"SUM(CASE WHEN status = %d THEN 1 ELSE 0 END) AS cancelled_runs, "+
+			"MAX(id) AS latest_run_id", StatusSuccess, StatusFailure, StatusCancelled)).
+		Where(builder.Eq{"repo_id": repoID}.And(builder.Neq{"component": ""})).
+		GroupBy("component").
+		Asc("component").
+		Find(&stats); err != nil {
+		return nil, err
+	} else if len(stats) == 0 {
+		return stats, nil
+	}
+
+	ids := make([]int64, 0, len(stats))
+	for _, s := range stats {
+		ids = append(ids, s.LatestRunID)
+	}
+	runs := make(map[int64]*ActionRun, len(ids))
+	if err := db.GetEngine(ctx).In("id", ids).Find(&runs); err != nil {
+		return nil, err
+	}
+	for _, s := range stats {
+		s.LatestRun = runs[s.LatestRunID]
+	}
+	return stats, nil
+}
+
+// GetComponentLatestStatus returns the status of the component on the branch, which aggregates the statuses of the latest runs
+// of the workflows of the component like the ones of the jobs of a run, so a component is failing if any of its workflows is failing.
+func GetComponentLatestStatus(ctx context.Context, repoID int64, component, branch, event string) (Status, error) {
+	cond := builder.Eq{
+		"repo_id":   repoID,
+		"component": component,
+		"ref":       branch,
+	}
+	if event != "" {
+		cond["event"] = event
+	}
+
+	var ids []int64
+	if err := db.GetEngine(ctx).Table("action_run").
+		Select("MAX(id)").
+		Where(cond).
+		GroupBy("workflow_id").
+		Find(&ids); err != nil {
+		return StatusUnknown, err
+	}
+	if len(ids) == 0 {
+		return StatusUnknown, util.NewNotExistErrorf("run of component %s on ref %s of repo %d", component, branch, repoID)
+	}
+
+	var runs []*ActionRun
+	if err := db.GetEngine(ctx).In("id", ids).Find(&runs); err != nil {
+		return StatusUnknown, err
+	}
+	if len(runs) == 1 {
+		return runs[0].Status, nil
+	}
+	jobs := make([]*ActionRunJob, 0, len(runs))
+	for _, run := range runs {
+		jobs = append(jobs, &ActionRunJob{Status: run.Status})
+	}
+	return aggregateJobStatus(jobs), nil
+}
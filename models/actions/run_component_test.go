@@ -0,0 +1,54 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetComponentStats(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	stats, err := GetComponentStats(db.DefaultContext, 4)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+
+	for id, status := range map[int64]Status{791: StatusFailure, 792: StatusSuccess} {
+		run := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: id})
+		run.Component = "services/api"
+		run.Status = status
+		_, err := db.GetEngine(db.DefaultContext).ID(id).Cols("component", "status").Update(run)
+		require.NoError(t, err)
+	}
+
+	stats, err = GetComponentStats(db.DefaultContext, 4)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "services/api", stats[0].Component)
+	assert.EqualValues(t, 2, stats[0].TotalRuns)
+	assert.EqualValues(t, 1, stats[0].SuccessfulRuns)
+	assert.EqualValues(t, 1, stats[0].FailedRuns)
+	assert.EqualValues(t, 792, stats[0].LatestRunID)
+	require.NotNil(t, stats[0].LatestRun)
+	assert.Equal(t, StatusSuccess, stats[0].LatestRun.Status)
+
+	status, err := GetComponentLatestStatus(db.DefaultContext, 4, "services/api", "refs/heads/master", "")
+	require.NoError(t, err)
+	assert.Equal(t, StatusSuccess, status)
+
+	_, err = GetComponentLatestStatus(db.DefaultContext, 4, "services/web", "refs/heads/master", "")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	runs, total, err := db.FindAndCount[ActionRun](db.DefaultContext, FindRunOptions{RepoID: 4, Component: "services/api"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Len(t, runs, 2)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// concurrencyActiveStatuses are the statuses of the runs which occupy their concurrency groups
+var concurrencyActiveStatuses = []Status{StatusRunning, StatusWaiting, StatusBlocked}
+
+// holdRunByConcurrency blocks the waiting jobs of the new run if its concurrency group is occupied by other runs,
+// the jobs are unblocked by the job emitter once the earlier runs of the group are done, see IsRunHeldByConcurrency.
+func holdRunByConcurrency(ctx context.Context, run *ActionRun, jobs []*ActionRunJob) error {
+	if run.ConcurrencyGroup == "" || run.Status.IsDone() {
+		return nil
+	}
+	occupied, err := db.GetEngine(ctx).Where(builder.Eq{"repo_id": run.RepoID, "concurrency_group": run.ConcurrencyGroup}.
+		And(builder.In("status", concurrencyActiveStatuses))).
+		Exist(&ActionRun{})
+	if err != nil || !occupied {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Status == StatusWaiting {
+			job.Status = StatusBlocked
+			job.Queued = 0
+		}
+	}
+	run.Status = StatusBlocked
+	return nil
+}
+
+// IsRunHeldByConcurrency returns whether the run waits for the earlier runs of its concurrency group to be done
+func IsRunHeldByConcurrency(ctx context.Context, run *ActionRun) (bool, error) {
+	if run.ConcurrencyGroup == "" || run.Status.IsDone() {
+		return false, nil
+	}
+	return db.GetEngine(ctx).Where(builder.Eq{"repo_id": run.RepoID, "concurrency_group": run.ConcurrencyGroup}.
+		And(builder.In("status", concurrencyActiveStatuses)).
+		And(builder.Lt{"id": run.ID})).
+		Exist(&ActionRun{})
+}
+
+// GetNextRunOfConcurrencyGroup returns the earliest run of the concurrency group which isn't done, nil if there isn't any
+func GetNextRunOfConcurrencyGroup(ctx context.Context, repoID int64, group string) (*ActionRun, error) {
+	var run ActionRun
+	has, err := db.GetEngine(ctx).Where(builder.Eq{"repo_id": repoID, "concurrency_group": group}.
+		And(builder.In("status", concurrencyActiveStatuses))).
+		OrderBy("id").
+		Get(&run)
+	if err != nil || !has {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CancelConcurrentRuns cancels the runs of the concurrency group of the new run before it's inserted.
+// All the runs of the group which aren't done are cancelled if the new run cancels in progress,
+// otherwise only the pending runs are cancelled, the run in progress is kept and the new run waits for it.
+func CancelConcurrentRuns(ctx context.Context, run *ActionRun) error {
+	if run.ConcurrencyGroup == "" {
+		return nil
+	}
+	runs, err := db.Find[ActionRun](ctx, FindRunOptions{
+		ListOptions:      db.ListOptionsAll,
+		RepoID:           run.RepoID,
+		ConcurrencyGroup: run.ConcurrencyGroup,
+		Status:           concurrencyActiveStatuses,
+	})
+	if err != nil {
+		return err
+	}
+	for _, r := range runs {
+		if !run.ConcurrencyCancel {
+			if held, err := IsRunHeldByConcurrency(ctx, r); err != nil {
+				return err
+			} else if !held {
+				continue
+			}
+		}
+		if err := cancelJobsOfRun(ctx, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
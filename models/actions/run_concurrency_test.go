@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConcurrency(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	jobs, err := jobparser.Parse([]byte(`
+name: deploy
+on: push
+jobs:
+  deploy:
+    runs-on: linux
+    steps:
+      - run: echo deploy
+`))
+	require.NoError(t, err)
+	insertRun := func() *ActionRun {
+		run := &ActionRun{RepoID: 1, OwnerID: 2, WorkflowID: "deploy.yaml", TriggerUserID: 2, Ref: "refs/heads/master", Status: StatusWaiting, ConcurrencyGroup: "deploy"}
+		require.NoError(t, CancelConcurrentRuns(ctx, run))
+		require.NoError(t, InsertRun(ctx, run, jobs))
+		return run
+	}
+
+	// the group is free
+	first := insertRun()
+	assert.Equal(t, StatusWaiting, first.Status)
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: first.ID, Status: StatusWaiting})
+
+	// the new run waits for the run in progress
+	second := insertRun()
+	assert.Equal(t, StatusBlocked, second.Status)
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: second.ID, Status: StatusBlocked, Queued: 0})
+	held, err := IsRunHeldByConcurrency(ctx, second)
+	require.NoError(t, err)
+	assert.True(t, held)
+	next, err := GetNextRunOfConcurrencyGroup(ctx, 1, "deploy")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, next.ID)
+
+	// the pending run is replaced by the newer one, the run in progress is kept
+	third := insertRun()
+	assert.Equal(t, StatusBlocked, third.Status)
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: first.ID, Status: StatusWaiting})
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: second.ID, Status: StatusCancelled})
+
+	// the run cancelling in progress cancels all of them
+	fourth := &ActionRun{RepoID: 1, OwnerID: 2, WorkflowID: "deploy.yaml", TriggerUserID: 2, Ref: "refs/heads/master", Status: StatusWaiting, ConcurrencyGroup: "deploy", ConcurrencyCancel: true}
+	require.NoError(t, CancelConcurrentRuns(ctx, fourth))
+	require.NoError(t, InsertRun(ctx, fourth, jobs))
+	assert.Equal(t, StatusWaiting, fourth.Status)
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: first.ID, Status: StatusCancelled})
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: third.ID, Status: StatusCancelled})
+	held, err = IsRunHeldByConcurrency(ctx, fourth)
+	require.NoError(t, err)
+	assert.False(t, held)
+}
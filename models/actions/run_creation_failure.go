@@ -0,0 +1,104 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"xorm.io/builder"
+)
+
+// RunCreationFailureReason is why the run of a workflow triggered by an event wasn't created
+type RunCreationFailureReason string
+
+const (
+	RunCreationFailureInvalidWorkflow RunCreationFailureReason = "invalid_workflow" // the workflow or the inputs of the dispatch are invalid
+	RunCreationFailureQuotaExceeded   RunCreationFailureReason = "quota_exceeded"   // the repository has created too many runs, see [actions] MAX_RUNS_PER_MINUTE
+	RunCreationFailureActorBlocked    RunCreationFailureReason = "actor_blocked"    // the user triggering the event is blocked by the owner
+	RunCreationFailurePolicyRejected  RunCreationFailureReason = "policy_rejected"  // the event is blocked by the instance, see [actions] BLOCKED_EVENTS
+	RunCreationFailureInternalError   RunCreationFailureReason = "internal_error"   // the details are only in the logs
+)
+
+// IsValid returns whether the reason is a known one
+func (r RunCreationFailureReason) IsValid() bool {
+	switch r {
+	case RunCreationFailureInvalidWorkflow, RunCreationFailureQuotaExceeded, RunCreationFailureActorBlocked,
+		RunCreationFailurePolicyRejected, RunCreationFailureInternalError:
+		return true
+	}
+	return false
+}
+
+// ActionRunCreationFailure is a run which wasn't created though its workflow was expected to be triggered by an event,
+// it's visible to the users so they could learn why the run never appeared.
+type ActionRunCreationFailure struct {
+	ID            int64
+	RepoID        int64                        `xorm:"INDEX(repo_created) NOT NULL"`
+	WorkflowID    string                       `xorm:"VARCHAR(255)"` // empty if the failure isn't of a single workflow, like the actor is blocked
+	Event         webhook_module.HookEventType `xorm:"VARCHAR(255)"`
+	Ref           string                       `xorm:"VARCHAR(255)"`
+	CommitSHA     string                       `xorm:"VARCHAR(64)"`
+	TriggerUserID int64
+	Reason        RunCreationFailureReason `xorm:"VARCHAR(32) INDEX"`
+	Message       string                   `xorm:"TEXT"`
+	Created       timeutil.TimeStamp       `xorm:"created INDEX(repo_created)"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionRunCreationFailure))
+}
+
+// InsertRunCreationFailure records a run which wasn't created, the same failure of the workflow of the event of the commit is recorded once
+func InsertRunCreationFailure(ctx context.Context, f *ActionRunCreationFailure) error {
+	has, err := db.GetEngine(ctx).Where(builder.Eq{
+		"repo_id":     f.RepoID,
+		"workflow_id": f.WorkflowID,
+		"event":       f.Event,
+		"ref":         f.Ref,
+		"commit_sha":  f.CommitSHA,
+		"reason":      f.Reason,
+	}).Exist(new(ActionRunCreationFailure))
+	if err != nil || has {
+		return err
+	}
+	return db.Insert(ctx, f)
+}
+
+type FindRunCreationFailuresOptions struct {
+	db.ListOptions
+	RepoID     int64
+	WorkflowID string
+	CommitSHA  string
+	Reason     RunCreationFailureReason
+}
+
+func (opts FindRunCreationFailuresOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.WorkflowID != "" {
+		cond = cond.And(builder.Eq{"workflow_id": opts.WorkflowID})
+	}
+	if opts.CommitSHA != "" {
+		cond = cond.And(builder.Eq{"commit_sha": opts.CommitSHA})
+	}
+	if opts.Reason != "" {
+		cond = cond.And(builder.Eq{"reason": opts.Reason})
+	}
+	return cond
+}
+
+func (opts FindRunCreationFailuresOptions) ToOrders() string {
+	return "id DESC"
+}
+
+// DeleteRunCreationFailuresBefore deletes the failures recorded before the time
+func DeleteRunCreationFailuresBefore(ctx context.Context, before timeutil.TimeStamp) (int64, error) {
+	return db.GetEngine(ctx).Where(builder.Lt{"created": before}).Delete(new(ActionRunCreationFailure))
+}
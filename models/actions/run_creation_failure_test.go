@@ -0,0 +1,57 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCreationFailures(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	newFailure := func(workflowID string, reason RunCreationFailureReason) *ActionRunCreationFailure {
+		return &ActionRunCreationFailure{
+			RepoID:     4,
+			WorkflowID: workflowID,
+			Event:      webhook_module.HookEventPush,
+			Ref:        "refs/heads/master",
+			CommitSHA:  "c2d72f548424103f01ee1dc02889c1e2bff816b0",
+			Reason:     reason,
+			Message:    "test",
+		}
+	}
+	assert.NoError(t, InsertRunCreationFailure(ctx, newFailure("test.yaml", RunCreationFailureInvalidWorkflow)))
+	assert.NoError(t, InsertRunCreationFailure(ctx, newFailure("test.yaml", RunCreationFailureInvalidWorkflow))) // the same
+	assert.NoError(t, InsertRunCreationFailure(ctx, newFailure("test.yaml", RunCreationFailureQuotaExceeded)))
+	assert.NoError(t, InsertRunCreationFailure(ctx, newFailure("", RunCreationFailureActorBlocked)))
+	unittest.AssertCount(t, &ActionRunCreationFailure{RepoID: 4}, 3)
+
+	failures, err := db.Find[ActionRunCreationFailure](ctx, FindRunCreationFailuresOptions{RepoID: 4, WorkflowID: "test.yaml"})
+	assert.NoError(t, err)
+	if assert.Len(t, failures, 2) {
+		assert.Equal(t, RunCreationFailureQuotaExceeded, failures[0].Reason)
+		assert.Equal(t, RunCreationFailureInvalidWorkflow, failures[1].Reason)
+	}
+	failures, err = db.Find[ActionRunCreationFailure](ctx, FindRunCreationFailuresOptions{RepoID: 4, Reason: RunCreationFailureActorBlocked})
+	assert.NoError(t, err)
+	assert.Len(t, failures, 1)
+
+	assert.True(t, RunCreationFailurePolicyRejected.IsValid())
+	assert.False(t, RunCreationFailureReason("unknown").IsValid())
+
+	n, err := DeleteRunCreationFailuresBefore(ctx, timeutil.TimeStampNow()-3600)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+	n, err = DeleteRunCreationFailuresBefore(ctx, timeutil.TimeStampNow()+1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, n)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RunDeliveryWindow is how long the same delivery of an event is considered a duplicate,
+// the racing syncs of a mirror deliver the same pushes within seconds, while pushing the same commits again takes longer.
+const RunDeliveryWindow = 2 * time.Minute
+
+// ActionRunDelivery is the last delivery of an event which triggered a workflow, see ClaimRunDelivery.
+// The unique key makes sure only one of the concurrent deliveries of the same event could create the run.
+type ActionRunDelivery struct {
+	ID          int64
+	RepoID      int64              `xorm:"UNIQUE(repo_key) NOT NULL"`
+	DeliveryKey string             `xorm:"UNIQUE(repo_key) VARCHAR(64) NOT NULL"`
+	Delivered   timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionRunDelivery))
+}
+
+// runDeliveryKey identifies the delivery of the event of the run to be created by the ref, the workflow, the event
+// and the commits before and after the event, so pushing a commit again after a revert or a force-push is another delivery.
+func runDeliveryKey(run *ActionRun, before string) string {
+	h := sha256.New()
+	for _, s := range []string{run.Ref, run.WorkflowID, string(run.Event), before, run.CommitSHA} {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ClaimRunDelivery claims the delivery of the event of the run to be created, before is the commit of the ref before the event
+// if it's known. It returns false if the same delivery has been claimed in the last RunDeliveryWindow, then the run shouldn't be created.
+func ClaimRunDelivery(ctx context.Context, run *ActionRun, before string) (bool, error) {
+	key := runDeliveryKey(run, before)
+	now := timeutil.TimeStampNow()
+	insertErr := db.Insert(ctx, &ActionRunDelivery{RepoID: run.RepoID, DeliveryKey: key, Delivered: now})
+	if insertErr == nil {
+		return true, nil
+	}
+	has, err := db.GetEngine(ctx).Exist(&ActionRunDelivery{RepoID: run.RepoID, DeliveryKey: key})
+	if err != nil {
+		return false, err
+	}
+	if !has {
+		return false, insertErr
+	}
+	// the delivery has been claimed before, it's claimed again only if the window has passed,
+	// the conditional update makes sure only one of the concurrent deliveries claims it
+	n, err := db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": run.RepoID, "delivery_key": key}).
+		And(builder.Lt{"delivered": now.AddDuration(-RunDeliveryWindow)}).
+		Cols("delivered").
+		Update(&ActionRunDelivery{Delivered: now})
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// DeleteRunDeliveriesBefore deletes the deliveries claimed before the time, they can't be duplicated anymore
+func DeleteRunDeliveriesBefore(ctx context.Context, before timeutil.TimeStamp) (int64, error) {
+	return db.GetEngine(ctx).Where(builder.Lt{"delivered": before}).Delete(new(ActionRunDelivery))
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// InsertExternalRun inserts a run reported by an external CI system with its jobs
+func InsertExternalRun(ctx context.Context, run *ActionRun, jobs []*ActionRunJob) error {
+	if !run.IsExternal() {
+		return fmt.Errorf("run %q isn't an external run", run.Title)
+	}
+	for _, job := range jobs {
+		job.RepoID = run.RepoID
+		job.OwnerID = run.OwnerID
+		job.CommitSHA = run.CommitSHA
+		job.IsExternal = true
+		job.Attempt = 1
+		if job.JobID == "" {
+			job.JobID = job.Name
+		}
+		SetExternalJobTimes(job)
+	}
+	run.Status = aggregateJobStatus(jobs)
+	if !run.Status.IsWaiting() {
+		run.Started = timeutil.TimeStampNow()
+	}
+	if run.Status.IsDone() {
+		run.Stopped = timeutil.TimeStampNow()
+	}
+	return insertRunWithRetry(ctx, run, jobs)
+}
+
+// InsertExternalRunJob adds a new job to an external run
+func InsertExternalRunJob(ctx context.Context, run *ActionRun, job *ActionRunJob) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		job.RunID = run.ID
+		job.RepoID = run.RepoID
+		job.OwnerID = run.OwnerID
+		job.CommitSHA = run.CommitSHA
+		job.IsExternal = true
+		job.Attempt = 1
+		if job.JobID == "" {
+			job.JobID = job.Name
+		}
+		SetExternalJobTimes(job)
+		if err := db.Insert(ctx, job); err != nil {
+			return err
+		}
+		// the new job restarts the run which has been done, so its result isn't replaced by another one directly
+		current, err := GetRunByID(ctx, run.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status.IsDone() {
+			current.Status = StatusRunning
+			current.Stopped = 0
+			if err := UpdateRun(ctx, current, "status", "stopped"); err != nil {
+				return err
+			}
+		}
+		return updateRunStatusByJobs(ctx, run.ID)
+	})
+}
+
+// SetExternalJobTimes sets the started and stopped time of an external job according to its status
+func SetExternalJobTimes(job *ActionRunJob) {
+	now := timeutil.TimeStampNow()
+	if job.Started.IsZero() && !job.Status.In(StatusWaiting, StatusBlocked) {
+		job.Started = now
+	}
+	if job.Stopped.IsZero() && job.Status.IsDone() {
+		job.Stopped = now
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// WorkflowFailures is the failures of a workflow of a repository in a period
+type WorkflowFailures struct {
+	RepoID     int64
+	WorkflowID string
+	// Failed is the number of the runs which failed
+	Failed int64
+	// Flaky is the number of the jobs which failed at first but passed after being rerun
+	Flaky int64
+	// LatestFailedRunIndex is the index of the latest failed run, 0 if no run failed
+	LatestFailedRunIndex int64
+}
+
+// GetWorkflowFailures returns the failures of the workflows since the time, ordered by repository and workflow
+func GetWorkflowFailures(ctx context.Context, since timeutil.TimeStamp) ([]*WorkflowFailures, error) {
+	var failed []*WorkflowFailures
+	if err := db.GetEngine(ctx).Table("action_run").
+		Select("repo_id, workflow_id, COUNT(*) AS failed, MAX(`index`) AS latest_failed_run_index").
+		Where("status = ? AND stopped >= ?", StatusFailure, since).
+		GroupBy("repo_id, workflow_id").
+		Find(&failed); err != nil {
+		return nil, err
+	}
+
+	var flaky []*WorkflowFailures
+	if err := db.GetEngine(ctx).Table("action_task").
+		Join("INNER", "action_run_job", "action_run_job.id = action_task.job_id").
+		Join("INNER", "action_run", "action_run.id = action_run_job.run_id").
+		Select("action_run.repo_id, action_run.workflow_id, COUNT(DISTINCT action_run_job.id) AS flaky").
+		Where("action_task.status = ? AND action_task.stopped >= ? AND action_run_job.status = ?", StatusFailure, since, StatusSuccess).
+		GroupBy("action_run.repo_id, action_run.workflow_id").
+		Find(&flaky); err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		repoID     int64
+		workflowID string
+	}
+	failures := make(map[key]*WorkflowFailures, len(failed)+len(flaky))
+	for _, f := range failed {
+		failures[key{f.RepoID, f.WorkflowID}] = f
+	}
+	for _, f := range flaky {
+		if v, ok := failures[key{f.RepoID, f.WorkflowID}]; ok {
+			v.Flaky = f.Flaky
+		} else {
+			failures[key{f.RepoID, f.WorkflowID}] = f
+		}
+	}
+
+	result := make([]*WorkflowFailures, 0, len(failures))
+	for _, f := range failures {
+		result = append(result, f)
+	}
+	slices.SortFunc(result, func(a, b *WorkflowFailures) int {
+		return cmp.Or(cmp.Compare(a.RepoID, b.RepoID), cmp.Compare(a.WorkflowID, b.WorkflowID))
+	})
+	return result, nil
+}
+
+// GetJobIDsWithFailedTasks returns the ids of the jobs which have failed attempts,
+// the successful ones of them failed at first but passed after being rerun
+func GetJobIDsWithFailedTasks(ctx context.Context, jobIDs []int64) (container.Set[int64], error) {
+	ids := make([]int64, 0, len(jobIDs))
+	if len(jobIDs) > 0 {
+		if err := db.GetEngine(ctx).Table("action_task").
+			Where("status = ?", StatusFailure).In("job_id", jobIDs).
+			Distinct("job_id").Find(&ids); err != nil {
+			return nil, err
+		}
+	}
+	return container.SetOf(ids...), nil
+}
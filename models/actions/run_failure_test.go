@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWorkflowFailures(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	failures, err := GetWorkflowFailures(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	// run 791 failed, and its job 192 failed at first but passed after being rerun
+	_, err = db.GetEngine(ctx).ID(791).Cols("status").Update(&ActionRun{Status: StatusFailure})
+	require.NoError(t, err)
+	_, err = db.GetEngine(ctx).ID(47).Cols("status").Update(&ActionTask{Status: StatusFailure})
+	require.NoError(t, err)
+
+	failures, err = GetWorkflowFailures(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.EqualValues(t, 4, failures[0].RepoID)
+	assert.Equal(t, "artifact.yaml", failures[0].WorkflowID)
+	assert.EqualValues(t, 1, failures[0].Failed)
+	assert.EqualValues(t, 1, failures[0].Flaky)
+	assert.EqualValues(t, 187, failures[0].LatestFailedRunIndex)
+
+	jobIDs, err := GetJobIDsWithFailedTasks(ctx, []int64{192, 193})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{192}, jobIDs.Values())
+
+	// the failures before the period are excluded
+	failures, err = GetWorkflowFailures(ctx, 1683636627)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ActionRunFilter is a named set of the filters of the runs list of a repository saved by a user,
+// so the users monitoring the runs of some workflows or branches don't build the query again and again
+type ActionRunFilter struct {
+	ID         int64              `xorm:"pk autoincr"`
+	UserID     int64              `xorm:"UNIQUE(user_repo_name) NOT NULL"`
+	RepoID     int64              `xorm:"UNIQUE(user_repo_name) INDEX NOT NULL"`
+	Name       string             `xorm:"VARCHAR(255) UNIQUE(user_repo_name) NOT NULL"`
+	WorkflowID string             `xorm:"VARCHAR(255)"` // the file name of the workflow, all workflows if empty
+	Branch     string             `xorm:"VARCHAR(255)"` // the branch of the runs, all refs if empty
+	ActorID    int64              // the user who triggered the runs, all users if 0
+	Status     Status             // the status of the runs, all statuses if unknown
+	Created    timeutil.TimeStamp `xorm:"created"`
+	Updated    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionRunFilter))
+}
+
+// GetRunFilters returns the run filters saved by the user for the repository ordered by their names
+func GetRunFilters(ctx context.Context, userID, repoID int64) ([]*ActionRunFilter, error) {
+	var filters []*ActionRunFilter
+	return filters, db.GetEngine(ctx).Where("user_id=? AND repo_id=?", userID, repoID).OrderBy("name").Find(&filters)
+}
+
+// GetRunFilterByName returns the run filter saved by the user for the repository by its name
+func GetRunFilterByName(ctx context.Context, userID, repoID int64, name string) (*ActionRunFilter, error) {
+	var filter ActionRunFilter
+	has, err := db.GetEngine(ctx).Where("user_id=? AND repo_id=? AND name=?", userID, repoID, name).Get(&filter)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("run filter %q: %w", name, util.ErrNotExist)
+	}
+	return &filter, nil
+}
+
+// SaveRunFilter creates the run filter, or replaces the one with the same name saved by the user for the repository.
+// It returns whether the filter is created.
+func SaveRunFilter(ctx context.Context, filter *ActionRunFilter) (created bool, err error) {
+	if filter.Name == "" {
+		return false, util.NewInvalidArgumentErrorf("the name of a run filter is required")
+	}
+	err = db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetRunFilterByName(ctx, filter.UserID, filter.RepoID, filter.Name)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			return err
+		}
+		if existing == nil {
+			created = true
+			return db.Insert(ctx, filter)
+		}
+		filter.ID = existing.ID
+		filter.Created = existing.Created
+		_, err = db.GetEngine(ctx).ID(filter.ID).Cols("workflow_id", "branch", "actor_id", "status").Update(filter)
+		return err
+	})
+	return created, err
+}
+
+// DeleteRunFilter deletes the run filter saved by the user for the repository by its name
+func DeleteRunFilter(ctx context.Context, userID, repoID int64, name string) error {
+	n, err := db.GetEngine(ctx).Where("user_id=? AND repo_id=? AND name=?", userID, repoID, name).Delete(new(ActionRunFilter))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("run filter %q: %w", name, util.ErrNotExist)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveRunFilter(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	created, err := SaveRunFilter(ctx, &ActionRunFilter{UserID: 2, RepoID: 1, Name: "release failures", Branch: "release", Status: StatusFailure})
+	require.NoError(t, err)
+	assert.True(t, created)
+	_, err = SaveRunFilter(ctx, &ActionRunFilter{UserID: 2, RepoID: 1, Name: "deploy", WorkflowID: "deploy.yml"})
+	require.NoError(t, err)
+	// the filters are saved per user, so the other users could use the same names
+	_, err = SaveRunFilter(ctx, &ActionRunFilter{UserID: 4, RepoID: 1, Name: "deploy", WorkflowID: "deploy.yml", ActorID: 4})
+	require.NoError(t, err)
+
+	// the filter with the same name is replaced
+	created, err = SaveRunFilter(ctx, &ActionRunFilter{UserID: 2, RepoID: 1, Name: "release failures", Branch: "release/v1", Status: StatusFailure})
+	require.NoError(t, err)
+	assert.False(t, created)
+	filter, err := GetRunFilterByName(ctx, 2, 1, "release failures")
+	require.NoError(t, err)
+	assert.Equal(t, "release/v1", filter.Branch)
+	assert.Equal(t, StatusFailure, filter.Status)
+
+	filters, err := GetRunFilters(ctx, 2, 1)
+	require.NoError(t, err)
+	if assert.Len(t, filters, 2) {
+		assert.Equal(t, "deploy", filters[0].Name)
+		assert.Equal(t, "release failures", filters[1].Name)
+	}
+	filters, err = GetRunFilters(ctx, 4, 1)
+	require.NoError(t, err)
+	if assert.Len(t, filters, 1) {
+		assert.EqualValues(t, 4, filters[0].ActorID)
+	}
+
+	_, err = SaveRunFilter(ctx, &ActionRunFilter{UserID: 2, RepoID: 1})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	require.NoError(t, DeleteRunFilter(ctx, 2, 1, "deploy"))
+	assert.ErrorIs(t, DeleteRunFilter(ctx, 2, 1, "deploy"), util.ErrNotExist)
+	_, err = GetRunFilterByName(ctx, 4, 1, "deploy")
+	assert.NoError(t, err)
+}
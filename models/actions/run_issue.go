@@ -0,0 +1,66 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ActionRunIssue records an issue opened to track the failure of a run,
+// so the issue can be closed automatically when the workflow passes later on the same ref.
+type ActionRunIssue struct {
+	ID            int64
+	RepoID        int64  `xorm:"index(workflow_ref)"`
+	WorkflowID    string `xorm:"index(workflow_ref)"`
+	Ref           string `xorm:"index(workflow_ref)"`
+	RunID         int64  `xorm:"UNIQUE"` // the failed run
+	IssueID       int64  `xorm:"index"`
+	AutoClose     bool
+	ClosedByRunID int64 // the run which passed and closed the issue, 0 if it hasn't been closed automatically
+
+	Created timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionRunIssue))
+}
+
+// GetRunIssueByRunID returns the issue record opened for the failure of the run
+func GetRunIssueByRunID(ctx context.Context, runID int64) (*ActionRunIssue, error) {
+	var ri ActionRunIssue
+	has, err := db.GetEngine(ctx).Where("run_id=?", runID).Get(&ri)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("issue of run %d does not exist", runID)
+	}
+	return &ri, nil
+}
+
+// InsertRunIssue records the issue opened for the failure of a run
+func InsertRunIssue(ctx context.Context, ri *ActionRunIssue) error {
+	return db.Insert(ctx, ri)
+}
+
+// FindRunIssuesToAutoClose returns the records of the issues which should be closed
+// when the workflow passes on the ref, and haven't been closed automatically yet.
+func FindRunIssuesToAutoClose(ctx context.Context, repoID int64, workflowID, ref string) ([]*ActionRunIssue, error) {
+	var ris []*ActionRunIssue
+	return ris, db.GetEngine(ctx).
+		Where("repo_id=? AND workflow_id=? AND ref=?", repoID, workflowID, ref).
+		And("auto_close=? AND closed_by_run_id=0", true).
+		OrderBy("id").
+		Find(&ris)
+}
+
+// SetRunIssueClosedBy records the run which passed and closed the issue
+func SetRunIssueClosedBy(ctx context.Context, ri *ActionRunIssue, runID int64) error {
+	ri.ClosedByRunID = runID
+	_, err := db.GetEngine(ctx).ID(ri.ID).Cols("closed_by_run_id").Update(ri)
+	return err
+}
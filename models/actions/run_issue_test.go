@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunIssues(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := GetRunIssueByRunID(db.DefaultContext, 791)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	require.NoError(t, InsertRunIssue(db.DefaultContext, &ActionRunIssue{RepoID: 4, WorkflowID: "test.yaml", Ref: "refs/heads/main", RunID: 791, IssueID: 1, AutoClose: true}))
+	require.NoError(t, InsertRunIssue(db.DefaultContext, &ActionRunIssue{RepoID: 4, WorkflowID: "test.yaml", Ref: "refs/heads/main", RunID: 792, IssueID: 2}))
+	require.NoError(t, InsertRunIssue(db.DefaultContext, &ActionRunIssue{RepoID: 4, WorkflowID: "test.yaml", Ref: "refs/heads/dev", RunID: 793, IssueID: 3, AutoClose: true}))
+
+	ri, err := GetRunIssueByRunID(db.DefaultContext, 791)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, ri.IssueID)
+
+	ris, err := FindRunIssuesToAutoClose(db.DefaultContext, 4, "test.yaml", "refs/heads/main")
+	require.NoError(t, err)
+	if assert.Len(t, ris, 1) {
+		assert.EqualValues(t, 791, ris[0].RunID)
+		require.NoError(t, SetRunIssueClosedBy(db.DefaultContext, ris[0], 800))
+	}
+
+	ris, err = FindRunIssuesToAutoClose(db.DefaultContext, 4, "test.yaml", "refs/heads/main")
+	require.NoError(t, err)
+	assert.Empty(t, ris)
+	unittest.AssertExistsAndLoadBean(t, &ActionRunIssue{RunID: 791, ClosedByRunID: 800})
+}
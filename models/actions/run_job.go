@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
@@ -26,15 +27,27 @@ type ActionRunJob struct {
 	CommitSHA         string     `xorm:"index"`
 	IsForkPullRequest bool
 	Name              string `xorm:"VARCHAR(255)"`
+	Component         string `xorm:"VARCHAR(255) index"` // the component of the run, see ActionRun.Component
 	Attempt           int64
-	WorkflowPayload   []byte
-	JobID             string   `xorm:"VARCHAR(255)"` // job id in workflow, not job's id
-	Needs             []string `xorm:"JSON TEXT"`
-	RunsOn            []string `xorm:"JSON TEXT"`
-	TaskID            int64    // the latest task of the job
-	Status            Status   `xorm:"index"`
+	WorkflowPayload   WorkflowPayload `xorm:"BLOB"`
+	JobID             string          `xorm:"VARCHAR(255)"` // job id in workflow, not job's id
+	Needs             []string        `xorm:"JSON TEXT"`
+	RunsOn            []string        `xorm:"JSON TEXT"`
+	Environment       string          `xorm:"VARCHAR(255)"` // the environment the job deploys to in lower case, as written in the workflow
+	TaskID            int64           // the latest task of the job
+	Status            Status          `xorm:"index"`
 	Started           timeutil.TimeStamp
 	Stopped           timeutil.TimeStamp
+	IsExternal        bool   // the job is reported by an external CI system, so it will never be picked by runners
+	ExternalURL       string `xorm:"TEXT"` // the link to the job in the external CI system
+	PinnedRunnerID    int64  // the runner which executed the previous attempt, see RunnerPinning
+	RunnerPinning     RunnerPinning
+	AvoidSpotRunners  bool               // the job is retried since its spot runner was lost, see ActionRunner.IsSpot
+	PreflightError    string             `xorm:"TEXT"`      // why the job didn't pass the preflight checks
+	Overrides         *RunOverrides      `xorm:"JSON TEXT"` // the overrides of the re-run, they are recorded by the task of the next attempt
+	TokenPermissions  map[string]string  `xorm:"JSON TEXT"` // the `permissions` of the job, scopes to "read", "write" or "none", nil if not declared
+	Queued            timeutil.TimeStamp // when the job became waiting for a runner, zero if it's still blocked
+	Version           int                `xorm:"version default 0"` // Status could be updated concomitantly, so an optimistic lock is needed
 	Created           timeutil.TimeStamp `xorm:"created"`
 	Updated           timeutil.TimeStamp `xorm:"updated index"`
 }
@@ -58,6 +71,15 @@ func (job *ActionRunJob) LoadRun(ctx context.Context) error {
 	return nil
 }
 
+// LogsFilename returns the name of the file to download the logs of the task of the job, the run must be loaded
+func (job *ActionRunJob) LogsFilename(taskID int64) string {
+	workflowName := job.Run.WorkflowID
+	if p := strings.Index(workflowName, "."); p > 0 {
+		workflowName = workflowName[0:p]
+	}
+	return fmt.Sprintf("%v-%v-%v.log", workflowName, job.Name, taskID)
+}
+
 // LoadAttributes load Run if not loaded
 func (job *ActionRunJob) LoadAttributes(ctx context.Context) error {
 	if job == nil {
@@ -91,9 +113,70 @@ func GetRunJobsByRunID(ctx context.Context, runID int64) ([]*ActionRunJob, error
 	return jobs, nil
 }
 
+// GetQueuedRunJobs returns the jobs which are waiting for runners, only their labels and queue times are loaded
+func GetQueuedRunJobs(ctx context.Context) ([]*ActionRunJob, error) {
+	var jobs []*ActionRunJob
+	if err := db.GetEngine(ctx).Cols("id", "runs_on", "queued", "created").
+		Where("status=? AND is_external=?", StatusWaiting, false).
+		Find(&jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CountRunningJobs returns the number of the jobs being executed by the runners
+func CountRunningJobs(ctx context.Context) (int64, error) {
+	return db.GetEngine(ctx).Where("status=? AND is_external=?", StatusRunning, false).Count(new(ActionRunJob))
+}
+
+// UpdateRunJob updates the columns of the job which match the condition, and the status of its run.
+// If the status is updated, the transition is validated and the started and stopped times are normalized,
+// ErrIllegalStatusTransition is returned if the job can't become the status.
+// It requires the inputted job has Version set, ErrConcurrentUpdate is returned if the job has been changed after loaded.
 func UpdateRunJob(ctx context.Context, job *ActionRunJob, cond builder.Cond, cols ...string) (int64, error) {
 	e := db.GetEngine(ctx)
 
+	// all the columns with values are updated if the columns aren't given
+	updatesStatus := slices.Contains(cols, "status") || len(cols) == 0 && job.Status != StatusUnknown
+	var current *ActionRunJob
+	if updatesStatus {
+		var err error
+		if current, err = GetRunJobByID(ctx, job.ID); err != nil {
+			return 0, err
+		}
+		if current.Version != job.Version {
+			return 0, ErrConcurrentUpdate{Kind: "job", ID: job.ID}
+		}
+		if !current.Status.CanTransitTo(job.Status) {
+			return 0, ErrIllegalStatusTransition{Kind: "job", ID: job.ID, From: current.Status, To: job.Status}
+		}
+	}
+
+	if updatesStatus && len(cols) > 0 {
+		// the times which aren't updated are kept, they are normalized with the new status
+		if !slices.Contains(cols, "started") {
+			job.Started = current.Started
+			cols = append(cols, "started")
+		}
+		if !slices.Contains(cols, "stopped") {
+			job.Stopped = current.Stopped
+			cols = append(cols, "stopped")
+		}
+		normalizeStatusTimes(job.Status, &job.Started, &job.Stopped)
+
+		if !slices.Contains(cols, "queued") {
+			// record when the job starts to wait for a runner, to tell the queue time from the execution time
+			switch {
+			case job.Status.IsWaiting():
+				job.Queued = timeutil.TimeStampNow()
+				cols = append(cols, "queued")
+			case job.Status.IsBlocked():
+				job.Queued = 0
+				cols = append(cols, "queued")
+			}
+		}
+	}
+
 	sess := e.ID(job.ID)
 	if len(cols) > 0 {
 		sess.Cols(cols...)
@@ -103,55 +186,77 @@ func UpdateRunJob(ctx context.Context, job *ActionRunJob, cond builder.Cond, col
 		sess.Where(cond)
 	}
 
+	// the version of the job is checked too, see ActionRunJob.Version
+	version := job.Version
 	affected, err := sess.Update(job)
 	if err != nil {
 		return 0, err
 	}
 
-	if affected == 0 || (!slices.Contains(cols, "status") && job.Status == 0) {
-		return affected, nil
-	}
-
-	if affected != 0 && slices.Contains(cols, "status") && job.Status.IsWaiting() {
-		// if the status of job changes to waiting again, increase tasks version.
-		if err := IncreaseTaskVersion(ctx, job.OwnerID, job.RepoID); err != nil {
+	if affected == 0 {
+		// the version is increased even if nothing is updated
+		job.Version = version
+		// tell the job which doesn't match the condition from the job which has been changed after loaded
+		if has, err := e.Where("id = ? AND version = ?", job.ID, job.Version).Exist(&ActionRunJob{}); err != nil {
 			return 0, err
+		} else if !has {
+			return 0, ErrConcurrentUpdate{Kind: "job", ID: job.ID}
 		}
+		return 0, nil
 	}
 
-	if job.RunID == 0 {
-		var err error
-		if job, err = GetRunJobByID(ctx, job.ID); err != nil {
-			return 0, err
-		}
+	if !updatesStatus {
+		return affected, nil
 	}
 
-	{
-		// Other goroutines may aggregate the status of the run and update it too.
-		// So we need load the run and its jobs before updating the run.
-		run, err := GetRunByID(ctx, job.RunID)
-		if err != nil {
-			return 0, err
-		}
-		jobs, err := GetRunJobsByRunID(ctx, job.RunID)
-		if err != nil {
-			return 0, err
-		}
-		run.Status = aggregateJobStatus(jobs)
-		if run.Started.IsZero() && run.Status.IsRunning() {
-			run.Started = timeutil.TimeStampNow()
-		}
-		if run.Stopped.IsZero() && run.Status.IsDone() {
-			run.Stopped = timeutil.TimeStampNow()
-		}
-		if err := UpdateRun(ctx, run, "status", "started", "stopped"); err != nil {
-			return 0, fmt.Errorf("update run %d: %w", run.ID, err)
-		}
+	from := current.Status
+	current.Status = job.Status
+	if err := fireStatusTransition(ctx, &StatusTransition{Job: current, From: from, To: job.Status}); err != nil {
+		return 0, err
+	}
+
+	if err := updateRunStatusByJobs(ctx, current.RunID); err != nil {
+		return 0, err
 	}
 
 	return affected, nil
 }
 
+// updateRunStatusByJobs updates the status of the run by aggregating the statuses of its jobs
+func updateRunStatusByJobs(ctx context.Context, runID int64) error {
+	// Other goroutines may aggregate the status of the run and update it too.
+	// So we need load the run and its jobs before updating the run.
+	run, err := GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	jobs, err := GetRunJobsByRunID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	run.Status = aggregateJobStatus(jobs)
+	if run.Started.IsZero() && run.Status.IsRunning() {
+		run.Started = timeutil.TimeStampNow()
+	}
+	if !run.Status.IsDone() {
+		// the run isn't stopped anymore if some jobs are rerun or restarted
+		run.Stopped = 0
+	}
+	isDoneNow := run.Stopped.IsZero() && run.Status.IsDone()
+	if isDoneNow {
+		run.Stopped = timeutil.TimeStampNow()
+	}
+	if err := UpdateRun(ctx, run, "status", "started", "stopped"); err != nil {
+		return fmt.Errorf("update run %d: %w", run.ID, err)
+	}
+	if isDoneNow {
+		if err := InsertOutboxEvent(ctx, run, OutboxEventRunDone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func aggregateJobStatus(jobs []*ActionRunJob) Status {
 	allDone := true
 	allWaiting := true
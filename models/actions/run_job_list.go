@@ -49,6 +49,7 @@ func (jobs ActionJobList) LoadAttributes(ctx context.Context, withRepo bool) err
 type FindRunJobOptions struct {
 	db.ListOptions
 	RunID         int64
+	RunIDs        []int64
 	RepoID        int64
 	OwnerID       int64
 	CommitSHA     string
@@ -61,6 +62,9 @@ func (opts FindRunJobOptions) ToConds() builder.Cond {
 	if opts.RunID > 0 {
 		cond = cond.And(builder.Eq{"run_id": opts.RunID})
 	}
+	if len(opts.RunIDs) > 0 {
+		cond = cond.And(builder.In("run_id", opts.RunIDs))
+	}
 	if opts.RepoID > 0 {
 		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
 	}
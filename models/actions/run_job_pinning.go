@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// RunnerPinning is how a re-run job is pinned to the runner which executed its previous attempt
+type RunnerPinning int
+
+const (
+	// RunnerPinningNone means any matching runner can pick the job
+	RunnerPinningNone RunnerPinning = iota
+	// RunnerPinningPrefer means the pinned runner is preferred,
+	// other runners can pick the job once the pinned runner is offline or the job has waited for PreferredRunnerWaitTime
+	RunnerPinningPrefer
+	// RunnerPinningRequire means only the pinned runner can pick the job
+	RunnerPinningRequire
+)
+
+// PreferredRunnerWaitTime is how long a job waits for its preferred runner before other runners can pick it
+const PreferredRunnerWaitTime = 5 * time.Minute
+
+// ParseRunnerPinning parses the pinning option of re-running jobs, it returns RunnerPinningNone for unknown values
+func ParseRunnerPinning(s string) RunnerPinning {
+	switch s {
+	case "prefer":
+		return RunnerPinningPrefer
+	case "require":
+		return RunnerPinningRequire
+	}
+	return RunnerPinningNone
+}
+
+// canBePickedBy reports whether the pinning of the job allows the runner to pick it
+func (job *ActionRunJob) canBePickedBy(ctx context.Context, runner *ActionRunner) bool {
+	if job.RunnerPinning == RunnerPinningNone || job.PinnedRunnerID == 0 || job.PinnedRunnerID == runner.ID {
+		return true
+	}
+	if job.RunnerPinning == RunnerPinningRequire {
+		// the job would wait forever if the pinned runner has been deleted, so fall back to the other runners
+		_, err := GetRunnerByID(ctx, job.PinnedRunnerID)
+		if errors.Is(err, util.ErrNotExist) {
+			log.Trace("The pinned runner %d of job %d has been deleted, other runners can pick it", job.PinnedRunnerID, job.ID)
+			return true
+		}
+		return false
+	}
+
+	if time.Since(job.Updated.AsTime()) >= PreferredRunnerWaitTime {
+		return true
+	}
+	pinned, err := GetRunnerByID(ctx, job.PinnedRunnerID)
+	if err != nil {
+		// the pinned runner has been deleted, or it's unknown whether it's available
+		log.Trace("Failed to get the pinned runner %d of job %d: %v", job.PinnedRunnerID, job.ID, err)
+		return true
+	}
+	return !pinned.IsOnline()
+}
+
+// IsPreviousRunnerAvailable reports whether the runner which executed the previous attempt of the job exists and is online,
+// re-running a job with RunnerPinningRequire should fail fast if it isn't, or the job would keep waiting for it.
+func IsPreviousRunnerAvailable(ctx context.Context, job *ActionRunJob) (bool, error) {
+	if job.TaskID == 0 {
+		// the job hasn't been picked, so it won't be pinned
+		return true, nil
+	}
+	task, err := GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		return false, err
+	}
+	runner, err := GetRunnerByID(ctx, task.RunnerID)
+	if errors.Is(err, util.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return runner.IsOnline(), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRunnerPinning(t *testing.T) {
+	assert.Equal(t, RunnerPinningPrefer, ParseRunnerPinning("prefer"))
+	assert.Equal(t, RunnerPinningRequire, ParseRunnerPinning("require"))
+	assert.Equal(t, RunnerPinningNone, ParseRunnerPinning(""))
+	assert.Equal(t, RunnerPinningNone, ParseRunnerPinning("any"))
+}
+
+func TestActionRunJob_canBePickedBy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	pinned := &ActionRunner{UUID: "pinned-runner", Name: "pinned", TokenHash: "pinned-runner"}
+	assert.NoError(t, db.Insert(ctx, pinned))
+	other := &ActionRunner{ID: pinned.ID + 1}
+
+	job := &ActionRunJob{PinnedRunnerID: pinned.ID, RunnerPinning: RunnerPinningNone}
+	assert.True(t, job.canBePickedBy(ctx, other))
+
+	job = &ActionRunJob{PinnedRunnerID: pinned.ID, RunnerPinning: RunnerPinningRequire}
+	assert.True(t, job.canBePickedBy(ctx, pinned))
+	assert.False(t, job.canBePickedBy(ctx, other))
+
+	// fall back to the other runners once the required runner has been deleted
+	assert.NoError(t, DeleteRunner(ctx, pinned.ID))
+	assert.True(t, job.canBePickedBy(ctx, other))
+
+	job = &ActionRunJob{
+		PinnedRunnerID: pinned.ID,
+		RunnerPinning:  RunnerPinningPrefer,
+		Updated:        timeutil.TimeStamp(time.Now().Add(-PreferredRunnerWaitTime).Unix()),
+	}
+	assert.True(t, job.canBePickedBy(ctx, pinned))
+	assert.True(t, job.canBePickedBy(ctx, other))
+}
+
+func TestIsPreviousRunnerAvailable(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// the job hasn't been picked yet
+	ok, err := IsPreviousRunnerAvailable(db.DefaultContext, &ActionRunJob{})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the runner of task 47 doesn't exist
+	ok, err = IsPreviousRunnerAvailable(db.DefaultContext, &ActionRunJob{TaskID: 47})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestUpdateRunJobConcurrently(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	job := unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: 192})
+	stale := unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: 192})
+
+	job.PreflightError = "updated by the runner"
+	n, err := UpdateRunJob(ctx, job, nil, "preflight_error")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	// the changes can't be overwritten by the job loaded before them
+	stale.PreflightError = "updated by the user"
+	_, err = UpdateRunJob(ctx, stale, nil, "preflight_error")
+	assert.ErrorAs(t, err, &ErrConcurrentUpdate{})
+	stale.Status = StatusCancelled
+	_, err = UpdateRunJob(ctx, stale, nil, "status")
+	assert.ErrorAs(t, err, &ErrConcurrentUpdate{})
+	job = unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: 192})
+	assert.Equal(t, "updated by the runner", job.PreflightError)
+
+	// the job which doesn't match the condition isn't a concurrent update
+	job.PreflightError = ""
+	n, err = UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "preflight_error")
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+}
+
+func TestUpdateRunConcurrently(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	run := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	stale := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+
+	run.Title = "updated by the runner"
+	require.NoError(t, UpdateRun(ctx, run, "title"))
+
+	stale.Title = "updated by the user"
+	assert.ErrorAs(t, UpdateRun(ctx, stale, "title"), &ErrConcurrentUpdate{})
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	assert.Equal(t, "updated by the runner", run.Title)
+}
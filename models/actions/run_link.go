@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// The links of the runs and their jobs in the web UI, the API, the emails, the commit statuses and the webhooks
+// are all built with the paths here, so they follow the routes if the routes are renamed.
+
+// RunPath returns the path of the run relative to its repository, both in the web UI and in the API
+func RunPath(index int64) string {
+	return fmt.Sprintf("/actions/runs/%d", index)
+}
+
+// JobPath returns the path of the job relative to its run, the index is the index of the job in the jobs of the run
+func JobPath(index int) string {
+	return fmt.Sprintf("/jobs/%d", index)
+}
+
+// WorkflowPath returns the path of the runs of the workflow relative to its repository
+func WorkflowPath(workflowID string) string {
+	return "/actions?workflow=" + url.QueryEscape(workflowID)
+}
+
+// JobHTMLURL returns the url of the job of the run in the web UI by the index of the job in the jobs of the run
+func (run *ActionRun) JobHTMLURL(index int) string {
+	if run.Repo == nil {
+		return ""
+	}
+	return run.HTMLURL() + JobPath(index)
+}
+
+// JobLink returns the link of the job of the run by the index of the job in the jobs of the run
+func (run *ActionRun) JobLink(index int) string {
+	if run.Repo == nil {
+		return ""
+	}
+	return run.Link() + JobPath(index)
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLinks(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	run := &ActionRun{Index: 3, WorkflowID: "test 1.yaml"}
+	assert.Empty(t, run.HTMLURL())
+	assert.Empty(t, run.JobLink(1))
+
+	run.Repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 4})
+	run.Repo.OwnerName = "user5"
+	assert.Equal(t, "/user5/repo4/actions/runs/3", run.Link())
+	assert.Equal(t, "/user5/repo4/actions/runs/3/jobs/1", run.JobLink(1))
+	assert.Equal(t, run.Repo.HTMLURL()+"/actions/runs/3/jobs/1", run.JobHTMLURL(1))
+	assert.Equal(t, run.Repo.APIURL()+"/actions/runs/3", run.APIURL())
+	assert.Equal(t, "/user5/repo4/actions?workflow=test+1.yaml", run.WorkflowLink())
+}
@@ -8,8 +8,11 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 
 	"xorm.io/builder"
@@ -63,14 +66,20 @@ func (runs RunList) LoadRepos(ctx context.Context) error {
 
 type FindRunOptions struct {
 	db.ListOptions
-	RepoID        int64
-	OwnerID       int64
-	WorkflowID    string
-	Ref           string // the commit/tag/… that caused this workflow
-	TriggerUserID int64
-	TriggerEvent  webhook_module.HookEventType
-	Approved      bool // not util.OptionalBool, it works only when it's true
-	Status        []Status
+	RepoID           int64
+	OwnerID          int64
+	WorkflowID       string
+	Ref              string // the commit/tag/… that caused this workflow
+	CommitSHA        string
+	TriggerUserID    int64
+	TriggerEvent     webhook_module.HookEventType
+	Approved         bool // not util.OptionalBool, it works only when it's true
+	Status           []Status
+	Acknowledged     optional.Option[bool] // whether the failures of the runs have been acknowledged
+	Keyword          string                // matches the title or the workflow of the runs
+	ConcurrencyGroup string
+	Component        string
+	RepoCond         builder.Cond // limits the runs to the repositories matching the condition and enabling Actions, used when searching across repositories
 }
 
 func (opts FindRunOptions) ToConds() builder.Cond {
@@ -96,9 +105,36 @@ func (opts FindRunOptions) ToConds() builder.Cond {
 	if opts.Ref != "" {
 		cond = cond.And(builder.Eq{"ref": opts.Ref})
 	}
+	if opts.CommitSHA != "" {
+		cond = cond.And(builder.Eq{"commit_sha": opts.CommitSHA})
+	}
 	if opts.TriggerEvent != "" {
 		cond = cond.And(builder.Eq{"trigger_event": opts.TriggerEvent})
 	}
+	if opts.ConcurrencyGroup != "" {
+		cond = cond.And(builder.Eq{"concurrency_group": opts.ConcurrencyGroup})
+	}
+	if opts.Component != "" {
+		cond = cond.And(builder.Eq{"component": opts.Component})
+	}
+	if opts.Acknowledged.Has() {
+		if opts.Acknowledged.Value() {
+			cond = cond.And(builder.Gt{"acknowledged_by": 0})
+		} else {
+			cond = cond.And(builder.Eq{"acknowledged_by": 0})
+		}
+	}
+	if opts.Keyword != "" {
+		cond = cond.And(builder.Or(
+			db.BuildCaseInsensitiveLike("title", opts.Keyword),
+			db.BuildCaseInsensitiveLike("workflow_id", opts.Keyword),
+		))
+	}
+	if opts.RepoCond != nil && opts.RepoCond.IsValid() {
+		cond = cond.And(builder.In("repo_id", builder.Select("`repository`.id").From("repository").
+			Join("INNER", "repo_unit", "`repository`.id = `repo_unit`.repo_id").
+			Where(builder.And(opts.RepoCond, builder.Eq{"`repo_unit`.type": unit.TypeActions}))))
+	}
 	return cond
 }
 
@@ -136,3 +172,8 @@ func GetActors(ctx context.Context, repoID int64) ([]*user_model.User, error) {
 		OrderBy(user_model.GetOrderByName()).
 		Find(&actors)
 }
+
+// CountRunsCreatedSince counts the runs of the repository created since the time
+func CountRunsCreatedSince(ctx context.Context, repoID int64, since timeutil.TimeStamp) (int64, error) {
+	return db.GetEngine(ctx).Where("repo_id = ? AND created >= ?", repoID, since).Count(new(ActionRun))
+}
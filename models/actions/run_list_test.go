@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRunsByKeyword(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	require.NoError(t, db.Insert(db.DefaultContext, &ActionRun{
+		ID:         1001,
+		Title:      "Publish the nightly release",
+		RepoID:     1,
+		OwnerID:    2,
+		WorkflowID: "nightly.yml",
+		Index:      1001,
+		Ref:        "refs/heads/master",
+		Status:     StatusSuccess,
+	}))
+
+	find := func(keyword string) []int64 {
+		runs, err := db.Find[ActionRun](db.DefaultContext, FindRunOptions{
+			Keyword:  keyword,
+			RepoCond: repo_model.AccessibleRepositoryCondition(nil, unit.TypeActions),
+		})
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(runs))
+		for _, run := range runs {
+			ids = append(ids, run.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int64{1001}, find("NIGHTLY RELEASE"))
+	assert.Equal(t, []int64{1001}, find("nightly.yml"))
+	assert.Empty(t, find("deploy"))
+	// the fixture runs match the keyword, but their repository doesn't enable Actions
+	assert.Empty(t, find("artifact"))
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// RunOverrides are the variables and inputs overridden when re-running jobs.
+// They are kept by the jobs until they are picked, then recorded by the tasks of the new attempts,
+// so the overrides only apply to the attempts of the re-run and never change the variables of the repository.
+type RunOverrides struct {
+	Vars   map[string]string `json:"vars,omitempty"`
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// IsEmpty reports whether nothing is overridden
+func (o *RunOverrides) IsEmpty() bool {
+	return o == nil || len(o.Vars) == 0 && len(o.Inputs) == 0
+}
+
+// ApplyVars returns the variables with the overridden ones replaced
+func (o *RunOverrides) ApplyVars(vars map[string]string) map[string]string {
+	if o == nil || len(o.Vars) == 0 {
+		return vars
+	}
+	ret := make(map[string]string, len(vars)+len(o.Vars))
+	for k, v := range vars {
+		ret[k] = v
+	}
+	for k, v := range o.Vars {
+		ret[k] = v
+	}
+	return ret
+}
+
+// ApplyInputs replaces the overridden inputs in the event payload, which is decoded from JSON
+func (o *RunOverrides) ApplyInputs(event map[string]any) {
+	if o == nil || len(o.Inputs) == 0 {
+		return
+	}
+	inputs, ok := event["inputs"].(map[string]any)
+	if !ok {
+		inputs = map[string]any{}
+		event["inputs"] = inputs
+	}
+	for k, v := range o.Inputs {
+		inputs[k] = v
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunOverrides(t *testing.T) {
+	var none *RunOverrides
+	assert.True(t, none.IsEmpty())
+	assert.True(t, (&RunOverrides{Vars: map[string]string{}}).IsEmpty())
+
+	vars := map[string]string{"REGION": "us-east", "FLAG": "off"}
+	assert.Equal(t, vars, none.ApplyVars(vars))
+
+	overrides := &RunOverrides{
+		Vars:   map[string]string{"FLAG": "on"},
+		Inputs: map[string]string{"dry_run": "true"},
+	}
+	assert.False(t, overrides.IsEmpty())
+	assert.Equal(t, map[string]string{"REGION": "us-east", "FLAG": "on"}, overrides.ApplyVars(vars))
+	// the variables of the run are not changed
+	assert.Equal(t, "off", vars["FLAG"])
+
+	event := map[string]any{"inputs": map[string]any{"env": "prod", "dry_run": "false"}}
+	overrides.ApplyInputs(event)
+	assert.Equal(t, map[string]any{"env": "prod", "dry_run": "true"}, event["inputs"])
+	event = map[string]any{}
+	overrides.ApplyInputs(event)
+	assert.Equal(t, map[string]any{"dry_run": "true"}, event["inputs"])
+}
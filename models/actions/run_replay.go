@@ -0,0 +1,93 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// RunJobChange is how a job of a run changes when the event of the run is replayed
+type RunJobChange string
+
+const (
+	RunJobAdded   RunJobChange = "added"   // the job would be created by the replayed event but the run hasn't it
+	RunJobRemoved RunJobChange = "removed" // the run has the job but it wouldn't be created by the replayed event
+	RunJobChanged RunJobChange = "changed"
+)
+
+// the fields of the jobs compared by DiffRunJobs
+const (
+	RunJobFieldNeeds      = "needs"
+	RunJobFieldRunsOn     = "runs_on"
+	RunJobFieldDefinition = "definition" // the workflow payload the runners receive
+)
+
+// RunJobDiff is a job which differs between the jobs of a run and the ones planned by replaying its event
+type RunJobDiff struct {
+	JobID  string
+	Name   string
+	Change RunJobChange
+	Fields []string // the changed fields, only for RunJobChanged
+}
+
+// PlanRunJobs returns the jobs which would be inserted for the run with the parsed workflows, without inserting them,
+// the runs-on overrides of the repository and its owner are applied like InsertRunWithJobs.
+func PlanRunJobs(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWorkflow) ([]*ActionRunJob, error) {
+	cfg, err := getActionsConfigOfRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := getRunsOnOverridesOfRun(ctx, run, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newRunJobs(run, jobs, overrides)
+}
+
+// DiffRunJobs compares the jobs of a run with the planned jobs, the jobs are matched by their job ids and names
+// since the jobs of a matrix share the job id. The diffs are in the order of the planned jobs, followed by the removed ones.
+func DiffRunJobs(jobs, planned []*ActionRunJob) []*RunJobDiff {
+	type key struct{ jobID, name string }
+	existing := make(map[key][]*ActionRunJob, len(jobs))
+	for _, job := range jobs {
+		k := key{job.JobID, job.Name}
+		existing[k] = append(existing[k], job)
+	}
+
+	diffs := make([]*RunJobDiff, 0)
+	for _, job := range planned {
+		k := key{job.JobID, job.Name}
+		if len(existing[k]) == 0 {
+			diffs = append(diffs, &RunJobDiff{JobID: job.JobID, Name: job.Name, Change: RunJobAdded})
+			continue
+		}
+		old := existing[k][0]
+		existing[k] = existing[k][1:]
+
+		var fields []string
+		if !slices.Equal(old.Needs, job.Needs) {
+			fields = append(fields, RunJobFieldNeeds)
+		}
+		if !slices.Equal(old.RunsOn, job.RunsOn) {
+			fields = append(fields, RunJobFieldRunsOn)
+		}
+		if !bytes.Equal(old.WorkflowPayload, job.WorkflowPayload) {
+			fields = append(fields, RunJobFieldDefinition)
+		}
+		if len(fields) > 0 {
+			diffs = append(diffs, &RunJobDiff{JobID: job.JobID, Name: job.Name, Change: RunJobChanged, Fields: fields})
+		}
+	}
+	for _, job := range jobs {
+		k := key{job.JobID, job.Name}
+		if slices.Contains(existing[k], job) {
+			diffs = append(diffs, &RunJobDiff{JobID: job.JobID, Name: job.Name, Change: RunJobRemoved})
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRunJobs(t *testing.T) {
+	jobs := []*ActionRunJob{
+		{JobID: "build", Name: "build (linux)", RunsOn: []string{"ubuntu-latest"}, WorkflowPayload: []byte("linux")},
+		{JobID: "build", Name: "build (windows)", RunsOn: []string{"windows-latest"}, WorkflowPayload: []byte("windows")},
+		{JobID: "test", Name: "test", Needs: []string{"build"}, RunsOn: []string{"ubuntu-latest"}, WorkflowPayload: []byte("test")},
+		{JobID: "lint", Name: "lint", RunsOn: []string{"ubuntu-latest"}, WorkflowPayload: []byte("lint")},
+	}
+	planned := []*ActionRunJob{
+		{JobID: "build", Name: "build (linux)", RunsOn: []string{"ubuntu-latest"}, WorkflowPayload: []byte("linux")},
+		{JobID: "build", Name: "build (windows)", RunsOn: []string{"windows-2022"}, WorkflowPayload: []byte("windows-2022")},
+		{JobID: "test", Name: "test", RunsOn: []string{"ubuntu-latest"}, WorkflowPayload: []byte("test")},
+		{JobID: "build", Name: "build (macos)", RunsOn: []string{"macos-latest"}, WorkflowPayload: []byte("macos")},
+	}
+
+	assert.Empty(t, DiffRunJobs(jobs, jobs))
+	assert.Equal(t, []*RunJobDiff{
+		{JobID: "build", Name: "build (windows)", Change: RunJobChanged, Fields: []string{RunJobFieldRunsOn, RunJobFieldDefinition}},
+		{JobID: "test", Name: "test", Change: RunJobChanged, Fields: []string{RunJobFieldNeeds}},
+		{JobID: "build", Name: "build (macos)", Change: RunJobAdded},
+		{JobID: "lint", Name: "lint", Change: RunJobRemoved},
+	}, DiffRunJobs(jobs, planned))
+
+	// all the jobs are removed if the workflow isn't triggered anymore
+	diffs := DiffRunJobs(jobs, nil)
+	assert.Len(t, diffs, len(jobs))
+	for _, diff := range diffs {
+		assert.Equal(t, RunJobRemoved, diff.Change)
+	}
+}
@@ -0,0 +1,538 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertRunWithDisabledJobs(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	require.NoError(t, err)
+	actionsUnit.ActionsConfig().DisabledJobs = []*repo_model.ActionsDisabledJob{
+		{WorkflowID: "test.yaml", Job: "test (windows)"},
+		{WorkflowID: "lint.yaml", Job: "lint"},
+	}
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  test:
+    strategy:
+      matrix:
+        os: [linux, windows]
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: echo test
+  lint:
+    runs-on: linux
+    steps:
+      - run: echo lint
+`))
+	require.NoError(t, err)
+
+	// one of the matrix is skipped, the job with the same id of another workflow isn't
+	run := &ActionRun{RepoID: 1, OwnerID: 2, WorkflowID: "test.yaml", TriggerUserID: 2, Ref: "refs/heads/master", Status: StatusWaiting}
+	require.NoError(t, InsertRunWithJobs(ctx, &RunWithJobs{Run: run, Jobs: jobs}))
+	runJobs, err := GetRunJobsByRunID(ctx, run.ID)
+	require.NoError(t, err)
+	statuses := map[string]Status{}
+	for _, job := range runJobs {
+		statuses[job.Name] = job.Status
+	}
+	assert.Equal(t, map[string]Status{
+		"test (linux)":   StatusWaiting,
+		"test (windows)": StatusSkipped,
+		"lint":           StatusWaiting,
+	}, statuses)
+	assert.Equal(t, StatusWaiting, run.Status)
+
+	// the run is done if all of its jobs are disabled
+	lintJobs, err := jobparser.Parse([]byte(`
+name: lint
+on: push
+jobs:
+  lint:
+    runs-on: linux
+    steps:
+      - run: echo lint
+`))
+	require.NoError(t, err)
+	run = &ActionRun{RepoID: 1, OwnerID: 2, WorkflowID: "lint.yaml", TriggerUserID: 2, Ref: "refs/heads/master", Status: StatusWaiting}
+	require.NoError(t, InsertRunWithJobs(ctx, &RunWithJobs{Run: run, Jobs: lintJobs}))
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID})
+	assert.Equal(t, StatusSuccess, run.Status)
+	assert.False(t, run.Stopped.IsZero())
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: run.ID, JobID: "lint", Status: StatusSkipped})
+}
+
+func TestInsertRunWithRunsOnOverrides(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	require.NoError(t, err)
+	actionsUnit.ActionsConfig().RunsOnOverrides = []*repo_model.ActionsRunsOnOverride{
+		{Job: "deploy-*", RunsOn: []string{"repo-runner"}},
+		{Job: "build", Labels: map[string]string{"ubuntu-latest": "fast-ubuntu"}},
+	}
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+	// the overrides of the owner take precedence
+	require.NoError(t, SetRunsOnOverrides(ctx, repo.OwnerID, []*repo_model.ActionsRunsOnOverride{
+		{Job: "deploy-prod", RunsOn: []string{"locked-down"}},
+	}))
+	defer func() {
+		assert.NoError(t, user_model.DeleteUserSetting(ctx, repo.OwnerID, user_model.SettingsKeyActionsRunsOnOverrides))
+	}()
+
+	jobs, err := jobparser.Parse([]byte(`
+name: deploy
+on: push
+jobs:
+  build:
+    runs-on: [ubuntu-latest, x64]
+    steps:
+      - run: echo build
+  deploy-staging:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo staging
+  deploy-prod:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo prod
+`))
+	require.NoError(t, err)
+
+	run := &ActionRun{RepoID: 1, OwnerID: repo.OwnerID, WorkflowID: "deploy.yaml", TriggerUserID: 2, Ref: "refs/heads/master", Status: StatusWaiting}
+	require.NoError(t, InsertRunWithJobs(ctx, &RunWithJobs{Run: run, Jobs: jobs}))
+	runJobs, err := GetRunJobsByRunID(ctx, run.ID)
+	require.NoError(t, err)
+	runsOn := map[string][]string{}
+	for _, job := range runJobs {
+		runsOn[job.JobID] = job.RunsOn
+		// the runners see the overridden labels too
+		workflows, err := jobparser.Parse(job.WorkflowPayload)
+		require.NoError(t, err)
+		_, wfJob := workflows[0].Job()
+		assert.Equal(t, job.RunsOn, wfJob.RunsOn())
+	}
+	assert.Equal(t, map[string][]string{
+		"build":          {"fast-ubuntu", "x64"},
+		"deploy-staging": {"repo-runner"},
+		"deploy-prod":    {"locked-down"},
+	}, runsOn)
+}
+
+func TestCancelRun(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  test:
+    runs-on: linux
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	run := &ActionRun{RepoID: 1, OwnerID: 2, WorkflowID: "test.yaml", TriggerUserID: 2, Ref: "refs/heads/master", Status: StatusWaiting}
+	require.NoError(t, InsertRun(ctx, run, jobs))
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: run.ID, Status: StatusWaiting})
+
+	require.NoError(t, CancelRun(ctx, run.ID))
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: run.ID, Status: StatusCancelled})
+
+	// the jobs which are done are left as they are
+	require.NoError(t, CancelRun(ctx, run.ID))
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: run.ID, Status: StatusCancelled})
+}
+
+func TestCancelOutdatedPullRequestRuns(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: pull_request
+jobs:
+  test:
+    runs-on: linux
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	insertRun := func(event webhook_module.HookEventType, commitSHA string, status Status) *ActionRun {
+		run := &ActionRun{
+			RepoID: 1, OwnerID: 2, WorkflowID: "test.yaml", TriggerUserID: 2, Ref: "refs/pull/2/head",
+			Event: event, CommitSHA: commitSHA, Status: StatusWaiting,
+		}
+		require.NoError(t, InsertRun(ctx, run, jobs))
+		if status != StatusWaiting {
+			run.Status = status
+			_, err := db.GetEngine(ctx).ID(run.ID).Cols("status").Update(run)
+			require.NoError(t, err)
+		}
+		return run
+	}
+	queued := insertRun(webhook_module.HookEventPullRequest, "old", StatusWaiting)
+	running := insertRun(webhook_module.HookEventPullRequestSync, "old", StatusRunning)
+	head := insertRun(webhook_module.HookEventPullRequestSync, "new", StatusWaiting)
+
+	cancelled, err := CancelOutdatedPullRequestRuns(ctx, 1, "refs/pull/2/head", "new", false)
+	require.NoError(t, err)
+	if assert.Len(t, cancelled, 1) {
+		assert.Equal(t, queued.ID, cancelled[0].RunID)
+	}
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: queued.ID, Status: StatusCancelled})
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: running.ID, Status: StatusWaiting})
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: head.ID, Status: StatusWaiting})
+
+	cancelled, err = CancelOutdatedPullRequestRuns(ctx, 1, "refs/pull/2/head", "new", true)
+	require.NoError(t, err)
+	if assert.Len(t, cancelled, 1) {
+		assert.Equal(t, running.ID, cancelled[0].RunID)
+	}
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: running.ID, Status: StatusCancelled})
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: head.ID, Status: StatusWaiting})
+}
+
+func TestCancelPullRequestRuns(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: pull_request
+jobs:
+  test:
+    runs-on: linux
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	insertRun := func(ref string, status Status) *ActionRun {
+		run := &ActionRun{
+			RepoID: 1, OwnerID: 2, WorkflowID: "test.yaml", TriggerUserID: 2, Ref: ref,
+			Event: webhook_module.HookEventPullRequestSync, CommitSHA: "head", Status: StatusWaiting,
+		}
+		require.NoError(t, InsertRun(ctx, run, jobs))
+		if status != StatusWaiting {
+			run.Status = status
+			_, err := db.GetEngine(ctx).ID(run.ID).Cols("status").Update(run)
+			require.NoError(t, err)
+		}
+		return run
+	}
+	queued := insertRun("refs/pull/2/head", StatusWaiting)
+	running := insertRun("refs/pull/2/head", StatusRunning)
+	other := insertRun("refs/pull/3/head", StatusWaiting)
+
+	cancelled, err := CancelPullRequestRuns(ctx, 1, "refs/pull/2/head", false)
+	require.NoError(t, err)
+	if assert.Len(t, cancelled, 1) {
+		assert.Equal(t, queued.ID, cancelled[0].RunID)
+	}
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: running.ID, Status: StatusWaiting})
+
+	cancelled, err = CancelPullRequestRuns(ctx, 1, "refs/pull/2/head", true)
+	require.NoError(t, err)
+	if assert.Len(t, cancelled, 1) {
+		assert.Equal(t, running.ID, cancelled[0].RunID)
+	}
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: running.ID, Status: StatusCancelled})
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: other.ID, Status: StatusWaiting})
+
+	require.NoError(t, SetPullRequestRunsExpired(ctx, 1, "refs/pull/2/head", 100))
+	assert.EqualValues(t, 100, unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: queued.ID}).ExpiredUnix)
+	assert.EqualValues(t, 0, unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: other.ID}).ExpiredUnix)
+
+	runs, err := FindExpiredRunsToDelete(ctx, RetentionOptions{OlderThan: 101, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, runs, 2)
+	runs, err = FindExpiredRunsToDelete(ctx, RetentionOptions{OlderThan: 100, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	// reopened
+	require.NoError(t, SetPullRequestRunsExpired(ctx, 1, "refs/pull/2/head", 0))
+	runs, err = FindExpiredRunsToDelete(ctx, RetentionOptions{OlderThan: 101, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestCancelExcessQueuedRuns(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  test:
+    runs-on: linux
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	insertRun := func(ref string, status Status) *ActionRun {
+		run := &ActionRun{
+			RepoID: 1, OwnerID: 2, WorkflowID: "test.yaml", TriggerUserID: 2, Ref: ref,
+			Event: webhook_module.HookEventPush, CommitSHA: "sha", Status: StatusWaiting,
+		}
+		require.NoError(t, InsertRun(ctx, run, jobs))
+		if status != StatusWaiting {
+			run.Status = status
+			_, err := db.GetEngine(ctx).ID(run.ID).Cols("status").Update(run)
+			require.NoError(t, err)
+		}
+		return run
+	}
+	defaultBranch := insertRun("refs/heads/master", StatusWaiting)
+	oldest := insertRun("refs/heads/feature", StatusBlocked)
+	running := insertRun("refs/heads/feature", StatusRunning)
+	older := insertRun("refs/tags/v1.0.0", StatusWaiting)
+	newest := insertRun("refs/heads/feature", StatusWaiting)
+
+	cancelled, err := CancelExcessQueuedRuns(ctx, 1, "refs/heads/master", 4)
+	require.NoError(t, err)
+	assert.Empty(t, cancelled)
+
+	cancelled, err = CancelExcessQueuedRuns(ctx, 1, "refs/heads/master", 2)
+	require.NoError(t, err)
+	if assert.Len(t, cancelled, 2) {
+		assert.Equal(t, oldest.ID, cancelled[0].RunID)
+		assert.Equal(t, older.ID, cancelled[1].RunID)
+	}
+	for _, run := range []*ActionRun{defaultBranch, running, newest} {
+		unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: run.ID, Status: StatusWaiting})
+	}
+
+	// the runs of the default branch are never cancelled
+	cancelled, err = CancelExcessQueuedRuns(ctx, 1, "refs/heads/master", 0)
+	require.NoError(t, err)
+	if assert.Len(t, cancelled, 1) {
+		assert.Equal(t, newest.ID, cancelled[0].RunID)
+	}
+	unittest.AssertExistsAndLoadBean(t, &ActionRunJob{RunID: defaultBranch.ID, Status: StatusWaiting})
+}
+
+func TestMarkDuplicateRun(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  test:
+    runs-on: linux
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	newRun := func() *ActionRun {
+		return &ActionRun{
+			RepoID: 1, OwnerID: 2, WorkflowID: "test.yaml", TriggerUserID: 2, Ref: "refs/heads/dedup",
+			Event: webhook_module.HookEventPush, CommitSHA: "abc", Status: StatusWaiting,
+		}
+	}
+
+	duplicate, err := MarkDuplicateRun(ctx, newRun(), "000")
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+
+	run := newRun()
+	require.NoError(t, InsertRun(ctx, run, jobs))
+	version := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID}).Version
+
+	duplicate, err = MarkDuplicateRun(ctx, newRun(), "000")
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID})
+	assert.EqualValues(t, 1, run.DuplicateDeliveries)
+	assert.Equal(t, version, run.Version)
+
+	// another workflow of the same commit isn't a duplicate
+	other := newRun()
+	other.WorkflowID = "other.yaml"
+	duplicate, err = MarkDuplicateRun(ctx, other, "000")
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+
+	// the same commit pushed again after a revert or a force-push is another delivery
+	duplicate, err = MarkDuplicateRun(ctx, newRun(), "def")
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+
+	// the same delivery after the window, like a branch deleted and created again on the same commit
+	_, err = db.GetEngine(ctx).Exec("UPDATE action_run_delivery SET delivered = delivered - ?", int64(RunDeliveryWindow.Seconds())+1)
+	require.NoError(t, err)
+	duplicate, err = MarkDuplicateRun(ctx, newRun(), "000")
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+	duplicate, err = MarkDuplicateRun(ctx, newRun(), "000")
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+}
+
+func TestClaimRunDeliveryConcurrently(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	run := &ActionRun{RepoID: 1, WorkflowID: "test.yaml", Ref: "refs/heads/concurrent", Event: webhook_module.HookEventPush, CommitSHA: "abc"}
+	var claimed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := ClaimRunDelivery(db.DefaultContext, run, "000")
+			assert.NoError(t, err)
+			if ok {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, claimed.Load())
+	assert.Equal(t, 1, unittest.GetCount(t, &ActionRunDelivery{RepoID: 1}))
+}
+
+func TestGetWorkflowLatestJobStatus(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	status, err := GetWorkflowLatestJobStatus(db.DefaultContext, 4, "artifact.yaml", "refs/heads/master", "", "job_2")
+	require.NoError(t, err)
+	assert.Equal(t, StatusSuccess, status)
+
+	_, err = GetWorkflowLatestJobStatus(db.DefaultContext, 4, "artifact.yaml", "refs/heads/master", "", "missing")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	_, err = GetWorkflowLatestJobStatus(db.DefaultContext, 4, "artifact.yaml", "refs/heads/other", "", "job_2")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
+
+func TestReserveRunIndexes(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  test:
+    runs-on: linux
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	newRun := func() *ActionRun {
+		return &ActionRun{RepoID: 1, OwnerID: 2, WorkflowID: "test.yaml", TriggerUserID: 2, Ref: "refs/heads/master", Status: StatusWaiting}
+	}
+
+	first, err := ReserveRunIndexes(ctx, 1, 3)
+	require.NoError(t, err)
+
+	// the runs are inserted with the reserved indexes, even if some of them are skipped
+	run := newRun()
+	run.Index = first + 2
+	require.NoError(t, InsertRunWithJobs(ctx, &RunWithJobs{Run: run, Jobs: jobs}))
+	assert.Equal(t, first+2, unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID}).Index)
+	run = newRun()
+	run.Index = first
+	require.NoError(t, InsertRunWithJobs(ctx, &RunWithJobs{Run: run, Jobs: jobs}))
+	assert.Equal(t, first, unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID}).Index)
+
+	// the runs without a reserved index follow the reserved ones
+	run = newRun()
+	require.NoError(t, InsertRunWithJobs(ctx, &RunWithJobs{Run: run, Jobs: jobs}))
+	assert.Equal(t, first+3, run.Index)
+}
+
+func TestRetryRunIndexConflict(t *testing.T) {
+	defer test.MockVariableValue(&runIndexRetryBackoff, time.Millisecond)()
+	ctx := db.DefaultContext
+
+	t.Run("Resolved", func(t *testing.T) {
+		attempts := 0
+		err := retryRunIndexConflict(ctx, 1, func() error {
+			attempts++
+			if attempts < 3 {
+				return db.ErrGetResourceIndexFailed
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		attempts := 0
+		err := retryRunIndexConflict(ctx, 1, func() error {
+			attempts++
+			return db.ErrGetResourceIndexFailed
+		})
+		assert.ErrorIs(t, err, db.ErrGetResourceIndexFailed)
+		assert.ErrorContains(t, err, "after 5 attempts")
+		assert.Equal(t, runIndexMaxAttempts, attempts)
+	})
+
+	t.Run("OtherError", func(t *testing.T) {
+		attempts := 0
+		err := retryRunIndexConflict(ctx, 1, func() error {
+			attempts++
+			return errors.New("no such table: action_run")
+		})
+		assert.EqualError(t, err, "no such table: action_run")
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		attempts := 0
+		err := retryRunIndexConflict(ctx, 1, func() error {
+			attempts++
+			cancel()
+			return db.ErrGetResourceIndexFailed
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestJitterBackoff(t *testing.T) {
+	backoff := 20 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		delay := jitterBackoff(backoff)
+		assert.GreaterOrEqual(t, delay, backoff/2)
+		assert.Less(t, delay, backoff*3/2)
+	}
+}
@@ -47,6 +47,16 @@ type ActionRunner struct {
 	// Store labels defined in state file (default: .runner file) of `act_runner`
 	AgentLabels []string `xorm:"TEXT"`
 
+	// Executor is the name of the executor which runs the tasks of this runner,
+	// empty means the runner is an act_runner which fetches tasks by itself
+	Executor string `xorm:"VARCHAR(64)"`
+
+	// UpdateVersion is the target version the runner has been signaled to update itself to, see UpdateStatus
+	UpdateVersion  string             `xorm:"VARCHAR(64)"`
+	UpdateSignaled timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	// UpdateFailed is true if the runner has declared another version after being signaled
+	UpdateFailed bool `xorm:"NOT NULL DEFAULT false"`
+
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated"`
 	Deleted timeutil.TimeStamp `xorm:"deleted"`
@@ -162,6 +172,7 @@ type FindRunnerOptions struct {
 	Filter        string
 	IsOnline      optional.Option[bool]
 	WithAvailable bool // not only runners belong to, but also runners can be used
+	WithExecutor  bool // only runners driven by executors
 }
 
 func (opts FindRunnerOptions) ToConds() builder.Cond {
@@ -170,15 +181,24 @@ func (opts FindRunnerOptions) ToConds() builder.Cond {
 	if opts.RepoID > 0 {
 		c := builder.NewCond().And(builder.Eq{"repo_id": opts.RepoID})
 		if opts.WithAvailable {
-			c = c.Or(builder.Eq{"owner_id": builder.Select("owner_id").From("repository").Where(builder.Eq{"id": opts.RepoID})})
-			c = c.Or(builder.Eq{"repo_id": 0, "owner_id": 0})
+			// the runner sharing policy of the owner decides which runners are available
+			repoOwner := builder.Select("owner_id").From("repository").Where(builder.Eq{"id": opts.RepoID})
+			repoOwnerCond := builder.In("user_id", repoOwner)
+			c = c.And(builder.NotExists(ownersWithRunnerSharingPolicy(repoOwnerCond, RunnerSharingPolicyOwnerOnly)))
+			c = c.Or(builder.Eq{"owner_id": repoOwner})
+			c = c.Or(builder.Eq{"repo_id": 0, "owner_id": 0}.And(
+				builder.NotExists(ownersWithRunnerSharingPolicy(repoOwnerCond, RunnerSharingPolicyNoShared, RunnerSharingPolicyOwnerOnly)),
+				builder.NotExists(ownersWithRunnerSharingPolicy(repoOwnerCond, RunnerSharingPolicySharedPublicOnly)).
+					Or(builder.Exists(builder.Select("id").From("repository").Where(builder.Eq{"id": opts.RepoID, "is_private": false}))),
+			))
 		}
 		cond = cond.And(c)
 	}
 	if opts.OwnerID > 0 {
 		c := builder.NewCond().And(builder.Eq{"owner_id": opts.OwnerID})
 		if opts.WithAvailable {
-			c = c.Or(builder.Eq{"repo_id": 0, "owner_id": 0})
+			c = c.Or(builder.Eq{"repo_id": 0, "owner_id": 0}.And(
+				builder.NotExists(ownersWithRunnerSharingPolicy(builder.Eq{"user_id": opts.OwnerID}, RunnerSharingPolicyNoShared, RunnerSharingPolicyOwnerOnly))))
 		}
 		cond = cond.And(c)
 	}
@@ -187,6 +207,10 @@ func (opts FindRunnerOptions) ToConds() builder.Cond {
 		cond = cond.And(builder.Like{"name", opts.Filter})
 	}
 
+	if opts.WithExecutor {
+		cond = cond.And(builder.Neq{"executor": ""})
+	}
+
 	if opts.IsOnline.Has() {
 		if opts.IsOnline.Value() {
 			cond = cond.And(builder.Gt{"last_online": time.Now().Add(-RunnerOfflineTime).Unix()})
@@ -266,6 +290,11 @@ func CreateRunner(ctx context.Context, t *ActionRunner) error {
 	return db.Insert(ctx, t)
 }
 
+// CountOnlineRunners returns the number of the runners which have been online within RunnerOfflineTime
+func CountOnlineRunners(ctx context.Context) (int64, error) {
+	return db.GetEngine(ctx).Where("last_online > ?", timeutil.TimeStampNow().AddDuration(-RunnerOfflineTime)).Count(new(ActionRunner))
+}
+
 func CountRunnersWithoutBelongingOwner(ctx context.Context) (int64, error) {
 	// Only affect action runners were a owner ID is set, as actions runners
 	// could also be created on a repository.
@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// IsInActiveHours returns whether the runner could pick jobs at the time according to the active hours of its labels,
+// a runner with several labels having active hours is only active when all the windows contain the time.
+// Out of the windows, the jobs requiring the labels wait, or they're picked by the other runners which could run them.
+func (r *ActionRunner) IsInActiveHours(t time.Time) bool {
+	for _, label := range r.AgentLabels {
+		if w, ok := setting.Actions.RunnerActiveHours[label]; ok && !w.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
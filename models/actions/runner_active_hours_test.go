@@ -0,0 +1,37 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerIsInActiveHours(t *testing.T) {
+	defer test.MockVariableValue(&setting.DefaultUILocation, time.UTC)()
+	defer test.MockVariableValue(&setting.Actions.RunnerActiveHours, map[string]setting.TimeWindow{
+		"office": {Start: 22 * time.Hour, End: 6 * time.Hour},
+		"gpu":    {Start: 0, End: 4 * time.Hour},
+	})()
+
+	at := func(hour int) time.Time {
+		return time.Date(2024, 5, 1, hour, 30, 0, 0, time.UTC)
+	}
+	office := &ActionRunner{AgentLabels: []string{"office", "linux"}}
+	officeGPU := &ActionRunner{AgentLabels: []string{"office", "gpu"}}
+	other := &ActionRunner{AgentLabels: []string{"linux"}}
+
+	assert.True(t, office.IsInActiveHours(at(23)))
+	assert.True(t, office.IsInActiveHours(at(3)))
+	assert.False(t, office.IsInActiveHours(at(12)))
+	// all the windows of the labels must contain the time
+	assert.True(t, officeGPU.IsInActiveHours(at(3)))
+	assert.False(t, officeGPU.IsInActiveHours(at(23)))
+	assert.True(t, other.IsInActiveHours(at(12)))
+}
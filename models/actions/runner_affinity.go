@@ -0,0 +1,74 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// runnerAffinity decides whether a runner should pick the jobs of a repository,
+// it prefers the runners which have recently run jobs of the same repository, since they have warm caches.
+// The results are cached per repository, so it should be used in a single scheduling pass.
+type runnerAffinity struct {
+	runner *ActionRunner
+	// repoID -> whether the runner is allowed to pick the jobs of the repository before the affinity timeout
+	allowed map[int64]bool
+}
+
+func newRunnerAffinity(runner *ActionRunner) *runnerAffinity {
+	return &runnerAffinity{
+		runner:  runner,
+		allowed: map[int64]bool{},
+	}
+}
+
+// allows reports whether the runner could pick the job according to the affinity
+func (a *runnerAffinity) allows(ctx context.Context, job *ActionRunJob) (bool, error) {
+	if setting.Actions.RunnerAffinityTimeout <= 0 || time.Since(job.Updated.AsTime()) >= setting.Actions.RunnerAffinityTimeout {
+		return true, nil
+	}
+	if allowed, ok := a.allowed[job.RepoID]; ok {
+		return allowed, nil
+	}
+
+	allowed, err := a.allowsRepo(ctx, job.RepoID)
+	if err != nil {
+		return false, err
+	}
+	a.allowed[job.RepoID] = allowed
+	return allowed, nil
+}
+
+func (a *runnerAffinity) allowsRepo(ctx context.Context, repoID int64) (bool, error) {
+	since := timeutil.TimeStamp(time.Now().Add(-setting.Actions.RunnerAffinityWindow).Unix())
+	var recentRunnerIDs []int64
+	if err := db.GetEngine(ctx).Table("action_task").
+		Where(builder.Eq{"repo_id": repoID}.And(builder.Gte{"started": since})).
+		Distinct("runner_id").
+		Find(&recentRunnerIDs); err != nil {
+		return false, err
+	}
+	if len(recentRunnerIDs) == 0 || slices.Contains(recentRunnerIDs, a.runner.ID) {
+		return true, nil
+	}
+
+	// fall back to the runner if all the runners with warm caches are busy or offline
+	idle, err := db.GetEngine(ctx).Table("action_runner").
+		Where(builder.In("id", recentRunnerIDs)).
+		And(builder.Gt{"last_online": time.Now().Add(-RunnerOfflineTime).Unix()}).
+		And(builder.NotIn("id", builder.Select("runner_id").From("action_task").Where(builder.Eq{"status": StatusRunning}))).
+		Exist()
+	if err != nil {
+		return false, err
+	}
+	return !idle, nil
+}
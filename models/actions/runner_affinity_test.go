@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerAffinity(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	// the runners aren't fixtures, remove the ones inserted by the other tests
+	assert.NoError(t, db.DeleteAllRecords("action_runner"))
+	defer test.MockVariableValue(&setting.Actions.RunnerAffinityTimeout, time.Hour)()
+	defer test.MockVariableValue(&setting.Actions.RunnerAffinityWindow, 24*time.Hour)()
+
+	now := timeutil.TimeStampNow()
+	// the fixture tasks are running on runner 1
+	recent := &ActionRunner{ID: 1001, UUID: "affinity-recent", Name: "recent", TokenHash: "affinity-recent", LastOnline: now}
+	other := &ActionRunner{ID: 1002, UUID: "affinity-other", Name: "other", TokenHash: "affinity-other", LastOnline: now}
+	assert.NoError(t, db.Insert(db.DefaultContext, recent))
+	assert.NoError(t, db.Insert(db.DefaultContext, other))
+
+	// the recent runner ran a job of repo 1 two hours ago
+	task := &ActionTask{RepoID: 1, RunnerID: recent.ID, Status: StatusSuccess, TokenHash: "affinity-task", Started: now.AddDuration(-2 * time.Hour)}
+	assert.NoError(t, db.Insert(db.DefaultContext, task))
+
+	job := &ActionRunJob{RepoID: 1, Updated: now}
+	allows := func(runner *ActionRunner) bool {
+		allowed, err := newRunnerAffinity(runner).allows(db.DefaultContext, job)
+		assert.NoError(t, err)
+		return allowed
+	}
+
+	t.Run("prefer the recent runner within the window", func(t *testing.T) {
+		assert.True(t, allows(recent))
+		assert.False(t, allows(other))
+		// the jobs of the other repositories aren't affected
+		otherRepoJob := &ActionRunJob{RepoID: 2, Updated: now}
+		allowed, err := newRunnerAffinity(other).allows(db.DefaultContext, otherRepoJob)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("no preference after the window", func(t *testing.T) {
+		defer test.MockVariableValue(&setting.Actions.RunnerAffinityWindow, time.Hour)()
+		assert.True(t, allows(recent))
+		assert.True(t, allows(other))
+	})
+
+	t.Run("no preference once the job has waited for the timeout", func(t *testing.T) {
+		defer test.MockVariableValue(&job.Updated, now.AddDuration(-2*time.Hour))()
+		assert.True(t, allows(other))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		defer test.MockVariableValue(&setting.Actions.RunnerAffinityTimeout, 0)()
+		assert.True(t, allows(other))
+	})
+
+	t.Run("fall back when the recent runner is busy or offline", func(t *testing.T) {
+		running := &ActionTask{RepoID: 2, RunnerID: recent.ID, Status: StatusRunning, TokenHash: "affinity-running", Started: now}
+		assert.NoError(t, db.Insert(db.DefaultContext, running))
+		assert.True(t, allows(other))
+		_, err := db.DeleteByID[ActionTask](db.DefaultContext, running.ID)
+		assert.NoError(t, err)
+		assert.False(t, allows(other))
+
+		recent.LastOnline = now.AddDuration(-2 * RunnerOfflineTime)
+		assert.NoError(t, UpdateRunner(db.DefaultContext, recent, "last_online"))
+		assert.True(t, allows(other))
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// LabelNamespace returns the namespace of a namespaced label like "my-org/gpu", which is the name of the owner
+// whose runners could have the label, or empty if the label isn't namespaced.
+// Only the name before ":" is checked, since the labels of the old runners could be like "ubuntu:docker://node:16".
+func LabelNamespace(label string) string {
+	name, _, _ := strings.Cut(label, ":")
+	namespace, _, ok := strings.Cut(name, "/")
+	if !ok {
+		return ""
+	}
+	return namespace
+}
+
+// CheckLabelNamespaces checks the namespaced labels belong to the owner by its name,
+// the owner name is empty for the runners of the instance, which can't have any namespaced labels
+func CheckLabelNamespaces(labels []string, ownerName string) error {
+	for _, label := range labels {
+		namespace := LabelNamespace(label)
+		if namespace == "" || strings.EqualFold(namespace, ownerName) {
+			continue
+		}
+		if ownerName == "" {
+			return util.NewInvalidArgumentErrorf("label %q is namespaced, the runners of the instance can't have it", label)
+		}
+		return util.NewInvalidArgumentErrorf("label %q belongs to %q, not %q", label, namespace, ownerName)
+	}
+	return nil
+}
+
+// CheckRunnerLabelNamespaces checks the namespaced labels declared by the runner belong to the owner of the runner or its repository
+func CheckRunnerLabelNamespaces(ctx context.Context, runner *ActionRunner, labels []string) error {
+	var ownerName string
+	if runner.RepoID != 0 {
+		repo, err := repo_model.GetRepositoryByID(ctx, runner.RepoID)
+		if err != nil {
+			return err
+		}
+		ownerName = repo.OwnerName
+	} else if runner.OwnerID != 0 {
+		owner, err := user_model.GetUserByID(ctx, runner.OwnerID)
+		if err != nil {
+			return err
+		}
+		ownerName = owner.Name
+	}
+	return CheckLabelNamespaces(labels, ownerName)
+}
+
+// allowsRunnerByLabelNamespaces returns whether the runner belongs to the owner of the job or its repository if the job requires any namespaced labels,
+// so the job never runs on the runners of the other owners, even if the owners were renamed after the runners declared their labels
+func (job *ActionRunJob) allowsRunnerByLabelNamespaces(runner *ActionRunner) bool {
+	if !slices.ContainsFunc(job.RunsOn, func(label string) bool { return LabelNamespace(label) != "" }) {
+		return true
+	}
+	return (runner.OwnerID != 0 && runner.OwnerID == job.OwnerID) || (runner.RepoID != 0 && runner.RepoID == job.RepoID)
+}
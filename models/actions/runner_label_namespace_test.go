@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelNamespace(t *testing.T) {
+	assert.Equal(t, "my-org", LabelNamespace("my-org/gpu"))
+	assert.Equal(t, "my-org", LabelNamespace("my-org/gpu:host"))
+	assert.Empty(t, LabelNamespace("gpu"))
+	assert.Empty(t, LabelNamespace("ubuntu:docker://node:16"))
+
+	assert.NoError(t, CheckLabelNamespaces([]string{"linux", "My-Org/gpu"}, "my-org"))
+	assert.ErrorContains(t, CheckLabelNamespaces([]string{"linux", "other-org/gpu"}, "my-org"), `belongs to "other-org"`)
+	assert.ErrorContains(t, CheckLabelNamespaces([]string{"my-org/gpu"}, ""), "runners of the instance")
+}
+
+func TestCheckRunnerLabelNamespaces(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// user 3 is an organization, and repo 4 is owned by user5
+	assert.NoError(t, CheckRunnerLabelNamespaces(ctx, &ActionRunner{OwnerID: 3}, []string{"linux", "org3/gpu"}))
+	assert.Error(t, CheckRunnerLabelNamespaces(ctx, &ActionRunner{OwnerID: 3}, []string{"user5/gpu"}))
+	assert.NoError(t, CheckRunnerLabelNamespaces(ctx, &ActionRunner{RepoID: 4}, []string{"user5/gpu"}))
+	assert.Error(t, CheckRunnerLabelNamespaces(ctx, &ActionRunner{}, []string{"user5/gpu"}))
+	assert.NoError(t, CheckRunnerLabelNamespaces(ctx, &ActionRunner{}, []string{"linux"}))
+}
+
+func TestAllowsRunnerByLabelNamespaces(t *testing.T) {
+	job := &ActionRunJob{OwnerID: 3, RepoID: 3, RunsOn: []string{"linux", "org3/gpu"}}
+	assert.True(t, job.allowsRunnerByLabelNamespaces(&ActionRunner{OwnerID: 3}))
+	assert.True(t, job.allowsRunnerByLabelNamespaces(&ActionRunner{RepoID: 3}))
+	assert.False(t, job.allowsRunnerByLabelNamespaces(&ActionRunner{OwnerID: 2}))
+	assert.False(t, job.allowsRunnerByLabelNamespaces(&ActionRunner{}))
+
+	// the jobs without namespaced labels could run on any runners
+	job.RunsOn = []string{"linux"}
+	assert.True(t, job.allowsRunnerByLabelNamespaces(&ActionRunner{}))
+}
@@ -63,3 +63,45 @@ func (runners RunnerList) LoadAttributes(ctx context.Context) error {
 
 	return runners.LoadRepos(ctx)
 }
+
+// GetRunnersByTaskIDs returns the runners which executed the tasks with their owners loaded, keyed by the task id.
+// The runners which have been deleted are included, the tasks whose runners don't exist anymore are left out.
+func GetRunnersByTaskIDs(ctx context.Context, taskIDs []int64) (map[int64]*ActionRunner, error) {
+	runnersMap := make(map[int64]*ActionRunner, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return runnersMap, nil
+	}
+	var tasks []*ActionTask
+	if err := db.GetEngine(ctx).In("id", taskIDs).Cols("id", "runner_id").Find(&tasks); err != nil {
+		return nil, err
+	}
+	runnerIDs := container.FilterSlice(tasks, func(task *ActionTask) (int64, bool) {
+		return task.RunnerID, task.RunnerID > 0
+	})
+	var runners RunnerList
+	if err := db.GetEngine(ctx).In("id", runnerIDs).Unscoped().Find(&runners); err != nil {
+		return nil, err
+	}
+	if err := runners.LoadOwners(ctx); err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*ActionRunner, len(runners))
+	for _, runner := range runners {
+		byID[runner.ID] = runner
+	}
+	for _, task := range tasks {
+		if runner, ok := byID[task.RunnerID]; ok {
+			runnersMap[task.ID] = runner
+		}
+	}
+	return runnersMap, nil
+}
+
+// GetRunnersByIDs returns the runners with the ids keyed by their ids, the runners which have been deleted are included
+func GetRunnersByIDs(ctx context.Context, ids []int64) (map[int64]*ActionRunner, error) {
+	runners := make(map[int64]*ActionRunner, len(ids))
+	if len(ids) == 0 {
+		return runners, nil
+	}
+	return runners, db.GetEngine(ctx).In("id", ids).Unscoped().Find(&runners)
+}
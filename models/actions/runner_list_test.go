@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunnersByTaskIDs(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	// the fixture tasks 47 and 48 have been executed by the runner 1
+	require.NoError(t, CreateRunner(db.DefaultContext, &ActionRunner{
+		ID:          1,
+		UUID:        "c8bd46ff-7e1f-4a3b-9f0c-3c4c5a8f0b01",
+		Name:        "host-a",
+		TokenHash:   "runner-list-test",
+		AgentLabels: []string{"ubuntu-latest", "gpu"},
+	}))
+
+	runners, err := GetRunnersByTaskIDs(db.DefaultContext, []int64{47, 48, 1001})
+	require.NoError(t, err)
+	require.Len(t, runners, 2)
+	assert.Equal(t, "host-a", runners[47].Name)
+	assert.Equal(t, []string{"ubuntu-latest", "gpu"}, runners[48].AgentLabels)
+
+	// the deleted runners are still told
+	require.NoError(t, DeleteRunner(db.DefaultContext, 1))
+	runners, err = GetRunnersByTaskIDs(db.DefaultContext, []int64{47})
+	require.NoError(t, err)
+	require.Contains(t, runners, int64(47))
+	assert.NotZero(t, runners[47].Deleted)
+}
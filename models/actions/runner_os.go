@@ -0,0 +1,132 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// Operating systems which could be implied by runner labels.
+// Runners don't report their operating systems explicitly, so the labels follow the conventions of GitHub hosted runners,
+// e.g. "windows", "windows-latest" and "windows-2022" imply a windows runner.
+const (
+	RunnerOSLinux   = "linux"
+	RunnerOSWindows = "windows"
+	RunnerOSMacOS   = "macos"
+)
+
+var runnerOSLabelPrefixes = []struct {
+	prefix string
+	os     string
+}{
+	{"windows", RunnerOSWindows},
+	{"macos", RunnerOSMacOS},
+	{"linux", RunnerOSLinux},
+	{"ubuntu", RunnerOSLinux},
+}
+
+// LabelOS returns the operating system implied by the label, or empty if the label doesn't follow the conventions
+func LabelOS(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	for _, v := range runnerOSLabelPrefixes {
+		if label == v.prefix || strings.HasPrefix(label, v.prefix+"-") {
+			return v.os
+		}
+	}
+	return ""
+}
+
+// LabelsOS returns the sorted operating systems implied by the labels
+func LabelsOS(labels []string) []string {
+	set := make(container.Set[string])
+	for _, label := range labels {
+		if os := LabelOS(label); os != "" {
+			set.Add(os)
+		}
+	}
+	oses := set.Values()
+	sort.Strings(oses)
+	return oses
+}
+
+// JobOS returns the operating system required by the runs-on labels of a job, or empty if there's no requirement
+func JobOS(runsOn []string) (string, error) {
+	oses := LabelsOS(runsOn)
+	switch len(oses) {
+	case 0:
+		return "", nil
+	case 1:
+		return oses[0], nil
+	default:
+		return "", fmt.Errorf("runs-on %v requires conflicting operating systems: %s", runsOn, strings.Join(oses, ", "))
+	}
+}
+
+// CheckRunnerOS checks whether the runner is able to run a job which runs on the labels,
+// it only fails when both the job and the runner have implied operating systems and they don't match.
+func CheckRunnerOS(runnerLabels, runsOn []string) error {
+	jobOS, err := JobOS(runsOn)
+	if err != nil {
+		return err
+	}
+	if jobOS == "" {
+		return nil
+	}
+	runnerOSes := LabelsOS(runnerLabels)
+	if len(runnerOSes) == 0 {
+		return nil
+	}
+	for _, os := range runnerOSes {
+		if os == jobOS {
+			return nil
+		}
+	}
+	return fmt.Errorf("job requires a %s runner, but the runner reports %s", jobOS, strings.Join(runnerOSes, ", "))
+}
+
+// DefaultShell returns the default shell of the operating system, it's the same as the one used by GitHub hosted runners
+func DefaultShell(os string) string {
+	switch os {
+	case RunnerOSWindows:
+		return "pwsh"
+	case RunnerOSMacOS:
+		return "bash"
+	}
+	return ""
+}
+
+// TranslateShellDefaults sets the default shell of the job in the workflow payload according to its operating system,
+// unless the workflow or the job has set the default shell, or the job runs in a container.
+func TranslateShellDefaults(payload []byte) ([]byte, error) {
+	workflows, err := jobparser.Parse(payload)
+	if err != nil {
+		return nil, err
+	} else if len(workflows) != 1 {
+		return nil, fmt.Errorf("not single workflow")
+	}
+	workflow := workflows[0]
+	id, job := workflow.Job()
+	if job == nil || workflow.Defaults.Run.Shell != "" || job.Defaults.Run.Shell != "" || !job.RawContainer.IsZero() {
+		return payload, nil
+	}
+	os, err := JobOS(job.RunsOn())
+	if err != nil {
+		return nil, err
+	}
+	shell := DefaultShell(os)
+	if shell == "" {
+		return payload, nil
+	}
+	job.Defaults.Run.Shell = shell
+	if err := workflow.SetJob(id, job); err != nil {
+		return nil, err
+	}
+	return workflow.Marshal()
+}
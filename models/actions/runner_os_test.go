@@ -0,0 +1,76 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelOS(t *testing.T) {
+	assert.Equal(t, RunnerOSWindows, LabelOS("windows"))
+	assert.Equal(t, RunnerOSWindows, LabelOS("Windows-2022"))
+	assert.Equal(t, RunnerOSMacOS, LabelOS("macos-latest"))
+	assert.Equal(t, RunnerOSLinux, LabelOS("ubuntu-22.04"))
+	assert.Equal(t, RunnerOSLinux, LabelOS("linux"))
+	assert.Equal(t, "", LabelOS("self-hosted"))
+	assert.Equal(t, "", LabelOS("windowsfoo"))
+}
+
+func TestCheckRunnerOS(t *testing.T) {
+	tests := []struct {
+		name         string
+		runnerLabels []string
+		runsOn       []string
+		wantErr      bool
+	}{
+		{"no os", []string{"self-hosted"}, []string{"self-hosted"}, false},
+		{"same os", []string{"windows-latest", "windows"}, []string{"windows"}, false},
+		{"runner without os", []string{"self-hosted", "gpu"}, []string{"windows-latest"}, false},
+		{"multiple runner oses", []string{"windows", "ubuntu-latest"}, []string{"ubuntu-latest"}, false},
+		{"wrong os", []string{"ubuntu-latest", "macos"}, []string{"windows"}, true},
+		{"conflicting runs-on", []string{"windows", "macos"}, []string{"windows", "macos"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRunnerOS(tt.runnerLabels, tt.runsOn)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTranslateShellDefaults(t *testing.T) {
+	payload := []byte(`
+name: test
+jobs:
+  job1:
+    runs-on: windows-latest
+    steps:
+      - run: echo hello
+`)
+	got, err := TranslateShellDefaults(payload)
+	require.NoError(t, err)
+	assert.Contains(t, string(got), "shell: pwsh")
+
+	payload = []byte(`
+name: test
+defaults:
+  run:
+    shell: bash
+jobs:
+  job1:
+    runs-on: windows-latest
+    steps:
+      - run: echo hello
+`)
+	got, err = TranslateShellDefaults(payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, got)
+}
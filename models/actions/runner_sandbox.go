@@ -0,0 +1,104 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// SandboxPolicy returns the sorted restrictions of the sandbox policies of the labels of the runner,
+// see setting.Actions.RunnerSandboxPolicies
+func (r *ActionRunner) SandboxPolicy() []string {
+	policy := make(container.Set[string])
+	for _, label := range r.AgentLabels {
+		policy.AddMultiple(setting.Actions.RunnerSandboxPolicies[label]...)
+	}
+	restrictions := policy.Values()
+	sort.Strings(restrictions)
+	return restrictions
+}
+
+// CheckSandboxPolicy returns an error if the job of the workflow payload declares requirements violating the restrictions,
+// the restrictions which couldn't be declared by the workflows, like SandboxReadOnlyNetwork, are only enforced by the runners
+func CheckSandboxPolicy(restrictions []string, workflowPayload []byte) error {
+	if len(restrictions) == 0 {
+		return nil
+	}
+	jobs, err := jobparser.Parse(workflowPayload)
+	if err != nil {
+		return fmt.Errorf("parse workflow: %w", err)
+	}
+	for _, swf := range jobs {
+		_, job := swf.Job()
+		if job == nil {
+			continue
+		}
+		containers := make(map[string]*jobparser.ContainerSpec, len(job.Services)+1)
+		if c := (&model.Job{RawContainer: job.RawContainer}).Container(); c != nil {
+			containers["job container"] = &jobparser.ContainerSpec{Image: c.Image, Volumes: c.Volumes, Options: c.Options}
+		}
+		for name, c := range job.Services {
+			if c != nil {
+				containers[fmt.Sprintf("service %q", name)] = c
+			}
+		}
+
+		for _, restriction := range restrictions {
+			if restriction == setting.SandboxNoServices && len(job.Services) > 0 {
+				return fmt.Errorf("the job has services, but the runner doesn't allow them")
+			}
+			for name, c := range containers {
+				if violated := sandboxRestrictionViolated(restriction, c); violated != "" {
+					return fmt.Errorf("the %s %s, but the runner doesn't allow it", name, violated)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sandboxRestrictionViolated returns how the container violates the restriction, or empty if it doesn't
+func sandboxRestrictionViolated(restriction string, c *jobparser.ContainerSpec) string {
+	options := strings.Fields(c.Options)
+	switch restriction {
+	case setting.SandboxNoPrivileged:
+		for _, option := range options {
+			if option == "--privileged" || option == "--privileged=true" {
+				return "is privileged"
+			}
+		}
+	case setting.SandboxNoDockerInDocker:
+		if strings.Contains(c.Image, "dind") {
+			return "runs Docker-in-Docker"
+		}
+		if strings.Contains(c.Options, "docker.sock") {
+			return "mounts the Docker socket"
+		}
+		for _, volume := range c.Volumes {
+			if strings.Contains(volume, "docker.sock") {
+				return "mounts the Docker socket"
+			}
+		}
+	case setting.SandboxNoHostNetwork:
+		for i, option := range options {
+			switch option {
+			case "--network=host", "--net=host":
+				return "uses the network of the host"
+			case "--network", "--net":
+				if i+1 < len(options) && options[i+1] == "host" {
+					return "uses the network of the host"
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerSandboxPolicy(t *testing.T) {
+	defer test.MockVariableValue(&setting.Actions.RunnerSandboxPolicies, map[string][]string{
+		"office": {setting.SandboxNoDockerInDocker, setting.SandboxReadOnlyNetwork},
+		"shared": {setting.SandboxNoPrivileged, setting.SandboxNoDockerInDocker},
+	})()
+
+	runner := &ActionRunner{AgentLabels: []string{"linux", "office", "shared"}}
+	assert.Equal(t, []string{setting.SandboxNoDockerInDocker, setting.SandboxNoPrivileged, setting.SandboxReadOnlyNetwork}, runner.SandboxPolicy())
+	assert.Empty(t, (&ActionRunner{AgentLabels: []string{"linux"}}).SandboxPolicy())
+}
+
+func TestCheckSandboxPolicy(t *testing.T) {
+	kases := []struct {
+		name        string
+		job         string
+		restriction string
+		wantErr     bool
+	}{
+		{
+			name:        "plain job",
+			job:         "    runs-on: linux\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxNoServices,
+		},
+		{
+			name:        "services",
+			job:         "    runs-on: linux\n    services:\n      db:\n        image: postgres\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxNoServices,
+			wantErr:     true,
+		},
+		{
+			name:        "privileged container",
+			job:         "    runs-on: linux\n    container:\n      image: node:20\n      options: --cpus 2 --privileged\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxNoPrivileged,
+			wantErr:     true,
+		},
+		{
+			name:        "unprivileged container",
+			job:         "    runs-on: linux\n    container: node:20\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxNoPrivileged,
+		},
+		{
+			name:        "dind service",
+			job:         "    runs-on: linux\n    services:\n      docker:\n        image: docker:24-dind\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxNoDockerInDocker,
+			wantErr:     true,
+		},
+		{
+			name:        "docker socket",
+			job:         "    runs-on: linux\n    container:\n      image: node:20\n      volumes:\n        - /var/run/docker.sock:/var/run/docker.sock\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxNoDockerInDocker,
+			wantErr:     true,
+		},
+		{
+			name:        "host network",
+			job:         "    runs-on: linux\n    container:\n      image: node:20\n      options: --network host\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxNoHostNetwork,
+			wantErr:     true,
+		},
+		{
+			name:        "read-only network is enforced by the runners",
+			job:         "    runs-on: linux\n    container:\n      image: node:20\n      options: --network host\n    steps:\n      - run: echo\n",
+			restriction: setting.SandboxReadOnlyNetwork,
+		},
+	}
+	for _, kase := range kases {
+		t.Run(kase.name, func(t *testing.T) {
+			payload := []byte("name: test\non: push\njobs:\n  job:\n" + kase.job)
+			err := CheckSandboxPolicy([]string{kase.restriction}, payload)
+			if kase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.NoError(t, CheckSandboxPolicy(nil, []byte("invalid")))
+}
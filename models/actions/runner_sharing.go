@@ -0,0 +1,93 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"xorm.io/builder"
+)
+
+// RunnerSharingPolicy decides which runners the repositories of an owner could use
+type RunnerSharingPolicy string
+
+const (
+	// RunnerSharingPolicyAll allows the runners of the repositories, the owner and the instance, it's the default
+	RunnerSharingPolicyAll RunnerSharingPolicy = "all"
+	// RunnerSharingPolicySharedPublicOnly allows the runners of the repositories and the owner, and the runners of the instance only for the public repositories
+	RunnerSharingPolicySharedPublicOnly RunnerSharingPolicy = "shared_public_only"
+	// RunnerSharingPolicyNoShared allows the runners of the repositories and the owner
+	RunnerSharingPolicyNoShared RunnerSharingPolicy = "no_shared"
+	// RunnerSharingPolicyOwnerOnly allows only the runners of the owner, the repositories can't register their own runners
+	RunnerSharingPolicyOwnerOnly RunnerSharingPolicy = "owner_only"
+)
+
+// RunnerSharingPolicies are all the runner sharing policies
+var RunnerSharingPolicies = []RunnerSharingPolicy{RunnerSharingPolicyAll, RunnerSharingPolicySharedPublicOnly, RunnerSharingPolicyNoShared, RunnerSharingPolicyOwnerOnly}
+
+// ParseRunnerSharingPolicy parses a runner sharing policy, an empty string means the default one
+func ParseRunnerSharingPolicy(s string) (RunnerSharingPolicy, bool) {
+	if s == "" {
+		return RunnerSharingPolicyAll, true
+	}
+	for _, p := range RunnerSharingPolicies {
+		if string(p) == s {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// AllowsRepoRunners returns whether the repositories could register and use their own runners
+func (p RunnerSharingPolicy) AllowsRepoRunners() bool {
+	return p != RunnerSharingPolicyOwnerOnly
+}
+
+// AllowsSharedRunners returns whether the public or private repositories could use the runners of the instance
+func (p RunnerSharingPolicy) AllowsSharedRunners(isPrivate bool) bool {
+	return p == RunnerSharingPolicyAll || p == RunnerSharingPolicySharedPublicOnly && !isPrivate
+}
+
+// GetRunnerSharingPolicy returns the runner sharing policy of the owner
+func GetRunnerSharingPolicy(ctx context.Context, ownerID int64) (RunnerSharingPolicy, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRunnerSharingPolicy)
+	if err != nil {
+		return "", err
+	}
+	if p, ok := ParseRunnerSharingPolicy(value); ok {
+		return p, nil
+	}
+	return RunnerSharingPolicyAll, nil
+}
+
+// GetRunnerSharingPolicyOfRepo returns the runner sharing policy of the owner of the repository
+func GetRunnerSharingPolicyOfRepo(ctx context.Context, repoID int64) (RunnerSharingPolicy, error) {
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if err != nil {
+		return "", err
+	}
+	return GetRunnerSharingPolicy(ctx, repo.OwnerID)
+}
+
+// SetRunnerSharingPolicy sets the runner sharing policy of the owner
+func SetRunnerSharingPolicy(ctx context.Context, ownerID int64, policy RunnerSharingPolicy) error {
+	if policy == RunnerSharingPolicyAll {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRunnerSharingPolicy)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRunnerSharingPolicy, string(policy))
+}
+
+// ownersWithRunnerSharingPolicy returns the sub query of the owners matched by ownerCond with one of the runner sharing policies
+func ownersWithRunnerSharingPolicy(ownerCond builder.Cond, policies ...RunnerSharingPolicy) *builder.Builder {
+	values := make([]any, 0, len(policies))
+	for _, p := range policies {
+		values = append(values, string(p))
+	}
+	return builder.Select("user_id").From("user_setting").Where(builder.Eq{
+		"setting_key": user_model.SettingsKeyActionsRunnerSharingPolicy,
+	}.And(builder.In("setting_value", values...), ownerCond))
+}
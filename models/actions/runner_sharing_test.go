@@ -0,0 +1,155 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRunnerSharingPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		value                string
+		policy               RunnerSharingPolicy
+		ok                   bool
+		repoRunners          bool
+		sharedRunners        bool
+		privateSharedRunners bool
+	}{
+		{value: "", policy: RunnerSharingPolicyAll, ok: true, repoRunners: true, sharedRunners: true, privateSharedRunners: true},
+		{value: "all", policy: RunnerSharingPolicyAll, ok: true, repoRunners: true, sharedRunners: true, privateSharedRunners: true},
+		{value: "shared_public_only", policy: RunnerSharingPolicySharedPublicOnly, ok: true, repoRunners: true, sharedRunners: true, privateSharedRunners: false},
+		{value: "no_shared", policy: RunnerSharingPolicyNoShared, ok: true, repoRunners: true, sharedRunners: false, privateSharedRunners: false},
+		{value: "owner_only", policy: RunnerSharingPolicyOwnerOnly, ok: true, repoRunners: false, sharedRunners: false, privateSharedRunners: false},
+		{value: "unknown", ok: false},
+	} {
+		policy, ok := ParseRunnerSharingPolicy(tc.value)
+		assert.Equal(t, tc.ok, ok, tc.value)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, tc.policy, policy)
+		assert.Equal(t, tc.repoRunners, policy.AllowsRepoRunners(), tc.value)
+		assert.Equal(t, tc.sharedRunners, policy.AllowsSharedRunners(false), tc.value)
+		assert.Equal(t, tc.privateSharedRunners, policy.AllowsSharedRunners(true), tc.value)
+	}
+}
+
+func TestFindRunnerOptionsWithSharingPolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	// the runners aren't fixtures, remove the ones inserted by the other tests
+	assert.NoError(t, db.DeleteAllRecords("action_runner"))
+
+	// repo 1 is public and repo 2 is private, both are owned by user 2
+	instance := &ActionRunner{ID: 1001, UUID: "sharing-instance", Name: "instance", TokenHash: "sharing-instance"}
+	owner := &ActionRunner{ID: 1002, UUID: "sharing-owner", Name: "owner", TokenHash: "sharing-owner", OwnerID: 2}
+	repo := &ActionRunner{ID: 1003, UUID: "sharing-repo", Name: "repo", TokenHash: "sharing-repo", RepoID: 1}
+	other := &ActionRunner{ID: 1004, UUID: "sharing-other", Name: "other", TokenHash: "sharing-other", OwnerID: 5}
+	privateRepo := &ActionRunner{ID: 1005, UUID: "sharing-private-repo", Name: "private-repo", TokenHash: "sharing-private-repo", RepoID: 2}
+	assert.NoError(t, db.Insert(db.DefaultContext, instance, owner, repo, other, privateRepo))
+
+	findRunnerIDs := func(opts FindRunnerOptions) []int64 {
+		runners, err := db.Find[ActionRunner](db.DefaultContext, opts)
+		assert.NoError(t, err)
+		ids := make([]int64, 0, len(runners))
+		for _, r := range runners {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	for _, tc := range []struct {
+		policy             RunnerSharingPolicy
+		repoRunners        []int64
+		privateRepoRunners []int64
+		ownerRunner        []int64
+	}{
+		{policy: RunnerSharingPolicyAll, repoRunners: []int64{1001, 1002, 1003}, privateRepoRunners: []int64{1001, 1002, 1005}, ownerRunner: []int64{1001, 1002}},
+		{policy: RunnerSharingPolicySharedPublicOnly, repoRunners: []int64{1001, 1002, 1003}, privateRepoRunners: []int64{1002, 1005}, ownerRunner: []int64{1001, 1002}},
+		{policy: RunnerSharingPolicyNoShared, repoRunners: []int64{1002, 1003}, privateRepoRunners: []int64{1002, 1005}, ownerRunner: []int64{1002}},
+		{policy: RunnerSharingPolicyOwnerOnly, repoRunners: []int64{1002}, privateRepoRunners: []int64{1002}, ownerRunner: []int64{1002}},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			assert.NoError(t, SetRunnerSharingPolicy(db.DefaultContext, 2, tc.policy))
+			defer func() {
+				assert.NoError(t, SetRunnerSharingPolicy(db.DefaultContext, 2, RunnerSharingPolicyAll))
+			}()
+
+			assert.ElementsMatch(t, tc.repoRunners, findRunnerIDs(FindRunnerOptions{RepoID: 1, WithAvailable: true}))
+			assert.ElementsMatch(t, tc.privateRepoRunners, findRunnerIDs(FindRunnerOptions{RepoID: 2, WithAvailable: true}))
+			assert.ElementsMatch(t, tc.ownerRunner, findRunnerIDs(FindRunnerOptions{OwnerID: 2, WithAvailable: true}))
+			// the policy doesn't hide the runners belonging to the repository or the owner
+			assert.ElementsMatch(t, []int64{1003}, findRunnerIDs(FindRunnerOptions{RepoID: 1}))
+			assert.ElementsMatch(t, []int64{1002}, findRunnerIDs(FindRunnerOptions{OwnerID: 2}))
+			// the other owners aren't affected
+			assert.ElementsMatch(t, []int64{1001, 1004}, findRunnerIDs(FindRunnerOptions{OwnerID: 5, WithAvailable: true}))
+		})
+	}
+}
+
+func TestRunnerJobCondWithSharingPolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// repo 1 is owned by user 2 and has Actions enabled, the fixture runs of repo 4 are owned by user 1
+	run := &ActionRun{ID: 1001, RepoID: 1, OwnerID: 2, Index: 1, Status: StatusWaiting}
+	assert.NoError(t, db.Insert(db.DefaultContext, run))
+	job := &ActionRunJob{ID: 1001, RunID: run.ID, RepoID: 1, OwnerID: 2, Status: StatusWaiting}
+	assert.NoError(t, db.Insert(db.DefaultContext, job))
+	// repo 2 is a private repository of user 2
+	privateRun := &ActionRun{ID: 1002, RepoID: 2, OwnerID: 2, Index: 1, Status: StatusWaiting}
+	assert.NoError(t, db.Insert(db.DefaultContext, privateRun))
+	privateJob := &ActionRunJob{ID: 1002, RunID: privateRun.ID, RepoID: 2, OwnerID: 2, Status: StatusWaiting}
+	assert.NoError(t, db.Insert(db.DefaultContext, privateJob))
+
+	findJobIDs := func(runner *ActionRunner) ([]int64, bool) {
+		cond, allowed, err := runnerJobCond(db.DefaultContext, runner)
+		assert.NoError(t, err)
+		if !allowed {
+			return nil, false
+		}
+		var ids []int64
+		assert.NoError(t, db.GetEngine(db.DefaultContext).Table("action_run_job").Where(cond).Cols("id").Find(&ids))
+		return ids, true
+	}
+
+	instance := &ActionRunner{ID: 1001}
+	owner := &ActionRunner{ID: 1002, OwnerID: 2}
+	repo := &ActionRunner{ID: 1003, RepoID: 1}
+
+	for _, tc := range []struct {
+		policy       RunnerSharingPolicy
+		instanceJobs []int64
+		repoAllowed  bool
+	}{
+		{policy: RunnerSharingPolicyAll, instanceJobs: []int64{192, 193, 1001, 1002}, repoAllowed: true},
+		{policy: RunnerSharingPolicySharedPublicOnly, instanceJobs: []int64{192, 193, 1001}, repoAllowed: true},
+		{policy: RunnerSharingPolicyNoShared, instanceJobs: []int64{192, 193}, repoAllowed: true},
+		{policy: RunnerSharingPolicyOwnerOnly, instanceJobs: []int64{192, 193}, repoAllowed: false},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			assert.NoError(t, SetRunnerSharingPolicy(db.DefaultContext, 2, tc.policy))
+			defer func() {
+				assert.NoError(t, SetRunnerSharingPolicy(db.DefaultContext, 2, RunnerSharingPolicyAll))
+			}()
+
+			ids, allowed := findJobIDs(instance)
+			assert.True(t, allowed)
+			assert.ElementsMatch(t, tc.instanceJobs, ids)
+
+			ids, allowed = findJobIDs(owner)
+			assert.True(t, allowed)
+			assert.ElementsMatch(t, []int64{1001}, ids)
+
+			ids, allowed = findJobIDs(repo)
+			assert.Equal(t, tc.repoAllowed, allowed)
+			if allowed {
+				assert.ElementsMatch(t, []int64{1001}, ids)
+			}
+		})
+	}
+}
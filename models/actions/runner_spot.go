@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// IsSpot returns whether the runner may disappear at any time like a spot or preemptible instance,
+// i.e. it has the label of setting.Actions.SpotRunnerLabel
+func (r *ActionRunner) IsSpot() bool {
+	return setting.Actions.SpotRunnerLabel != "" && slices.Contains(r.AgentLabels, setting.Actions.SpotRunnerLabel)
+}
+
+// RequiresSpotRunners returns whether the job requires the spot runners by its runs-on,
+// such a job opts out of being retried on the other runners when its runner is lost, since they couldn't pick it
+func (job *ActionRunJob) RequiresSpotRunners() bool {
+	return setting.Actions.SpotRunnerLabel != "" && slices.Contains(job.RunsOn, setting.Actions.SpotRunnerLabel)
+}
+
+// IsTaskRunBySpotRunner returns whether the task was run by a spot runner,
+// the runner is found even if it has been deleted, since the spot runners could be removed once they disappear
+func IsTaskRunBySpotRunner(ctx context.Context, task *ActionTask) (bool, error) {
+	runner := &ActionRunner{}
+	has, err := db.GetEngine(ctx).ID(task.RunnerID).Unscoped().Get(runner)
+	if err != nil || !has {
+		return false, err
+	}
+	return runner.IsSpot(), nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpotRunners(t *testing.T) {
+	spot := &ActionRunner{AgentLabels: []string{"linux", "spot"}}
+	other := &ActionRunner{AgentLabels: []string{"linux"}}
+	job := &ActionRunJob{RunsOn: []string{"linux"}}
+	spotJob := &ActionRunJob{RunsOn: []string{"linux", "spot"}}
+
+	assert.True(t, spot.IsSpot())
+	assert.False(t, other.IsSpot())
+	assert.False(t, job.RequiresSpotRunners())
+	assert.True(t, spotJob.RequiresSpotRunners())
+
+	defer test.MockVariableValue(&setting.Actions.SpotRunnerLabel, "")()
+	assert.False(t, spot.IsSpot())
+	assert.False(t, spotJob.RequiresSpotRunners())
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/hashicorp/go-version"
+	"xorm.io/builder"
+)
+
+// The statuses of the runners in the rollout of the target version
+const (
+	RunnerUpdateUpToDate    = "up_to_date"
+	RunnerUpdatePending     = "pending"     // it will be signaled once it's idle
+	RunnerUpdateSignaled    = "signaled"    // it has been signaled and hasn't declared the target version yet
+	RunnerUpdateFailed      = "failed"      // it has declared another version after being signaled
+	RunnerUpdateUnsupported = "unsupported" // it's driven by an executor, which can't be signaled
+)
+
+const runnerTargetVersionSettingKey = "actions.runner_target_version"
+
+// GetRunnerTargetVersion returns the version the runners should update themselves to, it's empty if there is no rollout
+func GetRunnerTargetVersion(ctx context.Context) (string, error) {
+	s, has, err := db.Get[system_model.Setting](ctx, builder.Eq{"setting_key": runnerTargetVersionSettingKey})
+	if err != nil || !has {
+		return "", err
+	}
+	return s.SettingValue, nil
+}
+
+// SetRunnerTargetVersion starts the rollout of the version to the runners, an empty version stops it
+func SetRunnerTargetVersion(ctx context.Context, version string) error {
+	return system_model.SetSettings(ctx, map[string]string{runnerTargetVersionSettingKey: version})
+}
+
+// IsRunnerVersionAtLeast returns whether the version isn't older than the target one,
+// the versions which couldn't be parsed, like "dev", are only equal to themselves
+func IsRunnerVersionAtLeast(v, target string) bool {
+	current, err1 := version.NewVersion(v)
+	wanted, err2 := version.NewVersion(target)
+	if err1 != nil || err2 != nil {
+		return strings.TrimPrefix(v, "v") == strings.TrimPrefix(target, "v")
+	}
+	return !current.LessThan(wanted)
+}
+
+// UpdateStatus returns the status of the runner in the rollout of the target version
+func (r *ActionRunner) UpdateStatus(target string) string {
+	switch {
+	case r.Executor != "":
+		return RunnerUpdateUnsupported
+	case IsRunnerVersionAtLeast(r.Version, target):
+		return RunnerUpdateUpToDate
+	case r.UpdateVersion != target:
+		return RunnerUpdatePending
+	case r.UpdateFailed:
+		return RunnerUpdateFailed
+	}
+	return RunnerUpdateSignaled
+}
+
+// DeclareVersion sets the version declared by the runner when it starts,
+// it marks the update as failed if the runner has been signaled but still runs an older version
+func (r *ActionRunner) DeclareVersion(v string) {
+	r.Version = v
+	r.UpdateFailed = r.UpdateVersion != "" && !IsRunnerVersionAtLeast(v, r.UpdateVersion)
+}
+
+// SignalRunnerUpdate records the runner has been signaled to update itself to the target version
+func SignalRunnerUpdate(ctx context.Context, r *ActionRunner, target string) error {
+	r.UpdateVersion = target
+	r.UpdateSignaled = timeutil.TimeStampNow()
+	r.UpdateFailed = false
+	return UpdateRunner(ctx, r, "update_version", "update_signaled", "update_failed")
+}
+
+// IsRunnerBusy returns whether the runner is running any tasks
+func IsRunnerBusy(ctx context.Context, runnerID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("runner_id = ? AND status = ?", runnerID, StatusRunning).Exist(&ActionTask{})
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRunnerVersionAtLeast(t *testing.T) {
+	assert.True(t, IsRunnerVersionAtLeast("v0.2.11", "v0.2.11"))
+	assert.True(t, IsRunnerVersionAtLeast("0.2.11", "v0.2.11"))
+	assert.True(t, IsRunnerVersionAtLeast("v0.3.0", "v0.2.11"))
+	assert.False(t, IsRunnerVersionAtLeast("v0.2.9", "v0.2.11"))
+	assert.False(t, IsRunnerVersionAtLeast("", "v0.2.11"))
+	assert.False(t, IsRunnerVersionAtLeast("dev", "v0.2.11"))
+	assert.True(t, IsRunnerVersionAtLeast("dev", "dev"))
+}
+
+func TestRunnerUpdateStatus(t *testing.T) {
+	const target = "v0.2.11"
+
+	runner := &ActionRunner{Version: "v0.2.10"}
+	assert.Equal(t, RunnerUpdatePending, runner.UpdateStatus(target))
+
+	runner.UpdateVersion = target
+	assert.Equal(t, RunnerUpdateSignaled, runner.UpdateStatus(target))
+	// it restarts with the old version
+	runner.DeclareVersion("v0.2.10")
+	assert.Equal(t, RunnerUpdateFailed, runner.UpdateStatus(target))
+	// it restarts with the target version
+	runner.DeclareVersion("v0.2.11")
+	assert.Equal(t, RunnerUpdateUpToDate, runner.UpdateStatus(target))
+	// a newer target is pending again
+	assert.Equal(t, RunnerUpdatePending, runner.UpdateStatus("v0.3.0"))
+
+	executor := &ActionRunner{Version: "v0.2.10", Executor: "kubernetes"}
+	assert.Equal(t, RunnerUpdateUnsupported, executor.UpdateStatus(target))
+}
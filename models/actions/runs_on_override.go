@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// GetRunsOnOverrides returns the runs-on overrides of the owner, they apply to the jobs of all repositories of the owner
+func GetRunsOnOverrides(ctx context.Context, ownerID int64) ([]*repo_model.ActionsRunsOnOverride, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRunsOnOverrides)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	var overrides []*repo_model.ActionsRunsOnOverride
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// SetRunsOnOverrides replaces the runs-on overrides of the owner
+func SetRunsOnOverrides(ctx context.Context, ownerID int64, overrides []*repo_model.ActionsRunsOnOverride) error {
+	if len(overrides) == 0 {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRunsOnOverrides)
+	}
+	value, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsRunsOnOverrides, string(value))
+}
+
+// getRunsOnOverridesOfRun returns the runs-on overrides applying to the jobs of the run,
+// the ones of the repository go first so the ones of the owner take precedence.
+func getRunsOnOverridesOfRun(ctx context.Context, run *ActionRun, cfg *repo_model.ActionsConfig) ([]*repo_model.ActionsRunsOnOverride, error) {
+	ownerOverrides, err := GetRunsOnOverrides(ctx, run.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	var overrides []*repo_model.ActionsRunsOnOverride
+	if cfg != nil {
+		overrides = append(overrides, cfg.RunsOnOverrides...)
+	}
+	return append(overrides, ownerOverrides...), nil
+}
+
+// getActionsConfigOfRun returns the Actions config of the repository of the run, or nil if Actions are disabled
+func getActionsConfigOfRun(ctx context.Context, run *ActionRun) (*repo_model.ActionsConfig, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	cfgUnit, err := run.Repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cfgUnit.ActionsConfig(), nil
+}
@@ -40,11 +40,14 @@ func init() {
 	db.RegisterModel(new(ActionScheduleSpec))
 }
 
-func UpdateScheduleSpec(ctx context.Context, spec *ActionScheduleSpec, cols ...string) error {
-	sess := db.GetEngine(ctx).ID(spec.ID)
-	if len(cols) > 0 {
-		sess.Cols(cols...)
+// ClaimScheduleSpec moves the due spec to its next run time, it returns false if the spec has been claimed by another instance,
+// so the schedule is triggered only once when multiple instances are starting the due schedules.
+func ClaimScheduleSpec(ctx context.Context, spec *ActionScheduleSpec, next timeutil.TimeStamp) (bool, error) {
+	n, err := db.GetEngine(ctx).ID(spec.ID).Where("next = ?", spec.Next).Cols("prev", "next").
+		Update(&ActionScheduleSpec{Prev: spec.Next, Next: next})
+	if err != nil || n == 0 {
+		return false, err
 	}
-	_, err := sess.Update(spec)
-	return err
+	spec.Prev, spec.Next = spec.Next, next
+	return true, nil
 }
@@ -63,8 +63,9 @@ func (specs SpecList) LoadRepos(ctx context.Context) error {
 
 type FindSpecOptions struct {
 	db.ListOptions
-	RepoID int64
-	Next   int64
+	RepoID  int64
+	OwnerID int64 // the specs of all repositories of the owner
+	Next    int64
 }
 
 func (opts FindSpecOptions) ToConds() builder.Cond {
@@ -72,6 +73,9 @@ func (opts FindSpecOptions) ToConds() builder.Cond {
 	if opts.RepoID > 0 {
 		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
 	}
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": opts.OwnerID})))
+	}
 
 	if opts.Next > 0 {
 		cond = cond.And(builder.Lte{"next": opts.Next})
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimScheduleSpec(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	spec := &ActionScheduleSpec{RepoID: 1, ScheduleID: 1, Spec: "* * * * *", Next: 100}
+	require.NoError(t, db.Insert(ctx, spec))
+
+	// another instance has loaded the same due spec
+	other := *spec
+	claimed, err := ClaimScheduleSpec(ctx, spec, 160)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	assert.EqualValues(t, 100, spec.Prev)
+	assert.EqualValues(t, 160, spec.Next)
+
+	claimed, err = ClaimScheduleSpec(ctx, &other, 160)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	unittest.AssertExistsAndLoadBean(t, &ActionScheduleSpec{ID: spec.ID, Prev: 100, Next: 160})
+}
@@ -39,6 +39,16 @@ func (s Status) String() string {
 	return statusNames[s]
 }
 
+// StatusFromString returns the Status of the name, and false if there is no such Status
+func StatusFromString(name string) (Status, bool) {
+	for s, n := range statusNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return StatusUnknown, false
+}
+
 // LocaleString returns the locale string name of the Status
 func (s Status) LocaleString(lang translation.Locale) string {
 	return lang.TrString("actions.status." + s.String())
@@ -0,0 +1,127 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var doneStatuses = []Status{StatusSuccess, StatusFailure, StatusCancelled, StatusSkipped}
+
+// statusTransitions are the statuses a job or a task could become from its current status.
+// A status could always stay the same, and a new job or task could start with any status.
+var statusTransitions = map[Status][]Status{
+	StatusBlocked: append([]Status{StatusWaiting, StatusRunning}, doneStatuses...),
+	StatusWaiting: append([]Status{StatusRunning}, doneStatuses...),
+	StatusRunning: doneStatuses,
+	// the jobs which have stopped could be rerun, or restarted in the external CI systems
+	StatusSuccess:   {StatusBlocked, StatusWaiting, StatusRunning},
+	StatusFailure:   {StatusBlocked, StatusWaiting, StatusRunning},
+	StatusCancelled: {StatusBlocked, StatusWaiting, StatusRunning},
+	StatusSkipped:   {StatusBlocked, StatusWaiting, StatusRunning},
+}
+
+// CanTransitTo returns whether a job or a task with the status could become the other status
+func (s Status) CanTransitTo(to Status) bool {
+	return s == to || s == StatusUnknown || slices.Contains(statusTransitions[s], to)
+}
+
+// runStatusTransitions are the statuses a run could become from its current status, the status of a run is aggregated from its jobs.
+// A status could always stay the same, and a new run could start with any status.
+var runStatusTransitions = map[Status][]Status{
+	StatusBlocked: append([]Status{StatusWaiting, StatusRunning}, doneStatuses...),
+	// a run goes back to waiting when its running jobs are done and the others wait for the runners
+	StatusWaiting: append([]Status{StatusBlocked, StatusRunning}, doneStatuses...),
+	StatusRunning: append([]Status{StatusBlocked, StatusWaiting}, doneStatuses...),
+	// the runs which have stopped could be rerun, or restarted in the external CI systems, but a result is never replaced by another one
+	StatusSuccess:   {StatusBlocked, StatusWaiting, StatusRunning},
+	StatusFailure:   {StatusBlocked, StatusWaiting, StatusRunning},
+	StatusCancelled: {StatusBlocked, StatusWaiting, StatusRunning},
+	StatusSkipped:   {StatusBlocked, StatusWaiting, StatusRunning},
+}
+
+// CanRunTransitTo returns whether a run with the status could become the other status
+func (s Status) CanRunTransitTo(to Status) bool {
+	return s == to || s == StatusUnknown || slices.Contains(runStatusTransitions[s], to)
+}
+
+// ErrIllegalStatusTransition represents an error that a run, job or task can't become the status from its current status
+type ErrIllegalStatusTransition struct {
+	Kind string // "run", "job" or "task"
+	ID   int64
+	From Status
+	To   Status
+}
+
+func (err ErrIllegalStatusTransition) Error() string {
+	return fmt.Sprintf("%s %d can't become %s from %s", err.Kind, err.ID, err.To, err.From)
+}
+
+func (err ErrIllegalStatusTransition) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// StatusTransition represents a change of the status of a run, a job or a task
+type StatusTransition struct {
+	Run  *ActionRun    // set if a run changes its status
+	Job  *ActionRunJob // set if a job changes its status
+	Task *ActionTask   // set if a task changes its status
+	From Status
+	To   Status
+}
+
+var statusTransitionHooks []func(ctx context.Context, transition *StatusTransition) error
+
+// OnStatusTransition registers a hook which is called after a run, a job or a task changes its status,
+// with the context of the change, so the change fails if the hook fails.
+func OnStatusTransition(hook func(ctx context.Context, transition *StatusTransition) error) {
+	statusTransitionHooks = append(statusTransitionHooks, hook)
+}
+
+func fireStatusTransition(ctx context.Context, transition *StatusTransition) error {
+	if transition.From == transition.To {
+		return nil
+	}
+	for _, hook := range statusTransitionHooks {
+		if err := hook(ctx, transition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeStatusTimes makes the started and stopped times consistent with the status,
+// so there are no stopped but running runs or jobs: only the runs and jobs which have stopped have stopped times,
+// and the ones which are still waiting don't have started times.
+func normalizeStatusTimes(status Status, started, stopped *timeutil.TimeStamp) {
+	switch {
+	case status.In(StatusBlocked, StatusWaiting):
+		*started, *stopped = 0, 0
+	case status.IsRunning():
+		if started.IsZero() {
+			*started = timeutil.TimeStampNow()
+		}
+		*stopped = 0
+	case status.IsDone():
+		// the jobs which have been skipped or cancelled before starting have no started times
+		if stopped.IsZero() {
+			*stopped = timeutil.TimeStampNow()
+		}
+	}
+}
+
+func init() {
+	// the runners need to fetch tasks again when a job starts to wait for them
+	OnStatusTransition(func(ctx context.Context, transition *StatusTransition) error {
+		if transition.Job == nil || !transition.To.IsWaiting() {
+			return nil
+		}
+		return IncreaseTaskVersion(ctx, transition.Job.OwnerID, transition.Job.RepoID)
+	})
+}
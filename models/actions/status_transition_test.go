@@ -0,0 +1,140 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCanTransitTo(t *testing.T) {
+	assert.True(t, StatusBlocked.CanTransitTo(StatusWaiting))
+	assert.True(t, StatusWaiting.CanTransitTo(StatusRunning))
+	assert.True(t, StatusRunning.CanTransitTo(StatusSuccess))
+	assert.True(t, StatusFailure.CanTransitTo(StatusWaiting))
+	assert.True(t, StatusRunning.CanTransitTo(StatusRunning))
+	assert.True(t, StatusUnknown.CanTransitTo(StatusSuccess))
+
+	assert.False(t, StatusRunning.CanTransitTo(StatusWaiting))
+	assert.False(t, StatusWaiting.CanTransitTo(StatusBlocked))
+	assert.False(t, StatusSuccess.CanTransitTo(StatusFailure))
+}
+
+func TestStatusCanRunTransitTo(t *testing.T) {
+	assert.True(t, StatusBlocked.CanRunTransitTo(StatusRunning))
+	assert.True(t, StatusRunning.CanRunTransitTo(StatusWaiting))
+	assert.True(t, StatusRunning.CanRunTransitTo(StatusFailure))
+	assert.True(t, StatusSuccess.CanRunTransitTo(StatusWaiting))
+	assert.True(t, StatusUnknown.CanRunTransitTo(StatusSuccess))
+
+	assert.False(t, StatusSuccess.CanRunTransitTo(StatusFailure))
+	assert.False(t, StatusFailure.CanRunTransitTo(StatusCancelled))
+}
+
+func TestUpdateRunStatusTransition(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	run := &ActionRun{
+		Index:         1002,
+		Title:         "run transition",
+		RepoID:        4,
+		OwnerID:       1,
+		WorkflowID:    "test.yml",
+		TriggerUserID: 1,
+		Ref:           "refs/heads/master",
+		CommitSHA:     "c2d72f548424103f01ee1dc02889c1e2bff816b0",
+		Event:         "push",
+		Status:        StatusSuccess,
+		Started:       100,
+		Stopped:       200,
+	}
+	require.NoError(t, db.Insert(ctx, run))
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID})
+
+	// the result of a run can't be replaced by another one
+	run.Status = StatusFailure
+	err := UpdateRun(ctx, run, "status")
+	require.ErrorIs(t, err, util.ErrInvalidArgument)
+	assert.ErrorAs(t, err, &ErrIllegalStatusTransition{})
+	assert.Equal(t, StatusSuccess, unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID}).Status)
+
+	// but it could be rerun
+	run.Status = StatusWaiting
+	require.NoError(t, UpdateRun(ctx, run, "status"))
+	assert.Equal(t, StatusWaiting, unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID}).Status)
+}
+
+func TestUpdateRunJobStatusTransition(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	var transitions []StatusTransition
+	defer func(hooks []func(context.Context, *StatusTransition) error) { statusTransitionHooks = hooks }(statusTransitionHooks)
+	OnStatusTransition(func(_ context.Context, transition *StatusTransition) error {
+		transitions = append(transitions, *transition)
+		return nil
+	})
+
+	run := &ActionRun{
+		Index:         1001,
+		Title:         "transition",
+		RepoID:        4,
+		OwnerID:       1,
+		WorkflowID:    "test.yml",
+		TriggerUserID: 1,
+		Ref:           "refs/heads/master",
+		CommitSHA:     "c2d72f548424103f01ee1dc02889c1e2bff816b0",
+		Event:         "push",
+		Status:        StatusRunning,
+		Started:       100,
+	}
+	require.NoError(t, db.Insert(ctx, run))
+	job := &ActionRunJob{RunID: run.ID, RepoID: 4, OwnerID: 1, Name: "test", JobID: "test", Status: StatusRunning, Started: 100}
+	require.NoError(t, db.Insert(ctx, job))
+
+	// a running job can't wait for a runner again
+	job.Status = StatusWaiting
+	_, err := UpdateRunJob(ctx, job, nil, "status")
+	require.ErrorIs(t, err, util.ErrInvalidArgument)
+	assert.ErrorAs(t, err, &ErrIllegalStatusTransition{})
+
+	// the stopped time is set when the job stops, and the started time is kept
+	job.Status = StatusSuccess
+	_, err = UpdateRunJob(ctx, job, nil, "status")
+	require.NoError(t, err)
+	job = unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: job.ID})
+	assert.EqualValues(t, 100, job.Started)
+	assert.NotZero(t, job.Stopped)
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID})
+	assert.Equal(t, StatusSuccess, run.Status)
+	assert.NotZero(t, run.Stopped)
+
+	// the run isn't stopped anymore when the job is rerun
+	job.Status = StatusWaiting
+	_, err = UpdateRunJob(ctx, job, nil, "status")
+	require.NoError(t, err)
+	job = unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: job.ID})
+	assert.Zero(t, job.Started)
+	assert.Zero(t, job.Stopped)
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID})
+	assert.Equal(t, StatusWaiting, run.Status)
+	assert.Zero(t, run.Stopped)
+
+	require.Len(t, transitions, 4)
+	assert.Equal(t, job.ID, transitions[0].Job.ID)
+	assert.Equal(t, StatusRunning, transitions[0].From)
+	assert.Equal(t, StatusSuccess, transitions[0].To)
+	assert.Equal(t, run.ID, transitions[1].Run.ID)
+	assert.Equal(t, StatusSuccess, transitions[1].To)
+	assert.Equal(t, StatusWaiting, transitions[2].To)
+	assert.Equal(t, StatusWaiting, transitions[3].To)
+}
@@ -6,15 +6,21 @@ package actions
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"fmt"
 	"time"
 
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
@@ -42,6 +48,15 @@ type ActionTask struct {
 	CommitSHA         string `xorm:"index"`
 	IsForkPullRequest bool
 
+	// Environment is reported by the runner to describe where the task runs, see TaskEnvironmentOS and other keys
+	Environment map[string]string `xorm:"JSON TEXT"`
+	// ResourceUsage summarizes the resource usage samples reported by the runner, nil if the runner doesn't report them
+	ResourceUsage *TaskResourceUsage `xorm:"JSON TEXT"`
+	// ErrorClass tells why the task didn't succeed, see TaskErrorClassInfrastructure and other classes
+	ErrorClass string `xorm:"VARCHAR(32) index"`
+	// Overrides are the variables and inputs overridden by the re-run which created this attempt
+	Overrides *RunOverrides `xorm:"JSON TEXT"`
+
 	Token          string `xorm:"-"`
 	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
 	TokenSalt      string
@@ -146,6 +161,59 @@ func (task *ActionTask) LoadAttributes(ctx context.Context) error {
 	return nil
 }
 
+// TokenAccessMode returns the access mode of the token of the task to its repository,
+// it's read for the pull requests from forks, otherwise it follows the default token permissions of the repository.
+func (task *ActionTask) TokenAccessMode(ctx context.Context) (perm.AccessMode, error) {
+	if task.IsForkPullRequest {
+		return perm.AccessModeRead, nil
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, task.RepoID)
+	if err != nil {
+		return perm.AccessModeNone, err
+	}
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return perm.AccessModeWrite, nil
+		}
+		return perm.AccessModeNone, err
+	}
+	if cfgUnit.ActionsConfig().GetDefaultTokenPermissions() == repo_model.ActionsTokenPermissionsRead {
+		return perm.AccessModeRead, nil
+	}
+	return perm.AccessModeWrite, nil
+}
+
+// PullRequestScope returns the index of the pull request which the token of the task could label, comment on and review,
+// it's granted by `permissions: pull-requests: write` of the job to the runs triggered by the pull request,
+// except the ones from forks. It's 0 if the token isn't granted.
+func (task *ActionTask) PullRequestScope(ctx context.Context) (int64, error) {
+	if task.IsForkPullRequest {
+		return 0, nil
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		return 0, err
+	}
+	if task.Job.TokenPermissions["pull-requests"] != "write" {
+		return 0, nil
+	}
+	if err := task.Job.LoadRun(ctx); err != nil {
+		return 0, err
+	}
+	if task.Job.Run.Event.Event() != "pull_request" {
+		return 0, nil
+	}
+	eventPayload, err := task.Job.Run.GetEventPayload()
+	if err != nil {
+		return 0, err
+	}
+	var payload api.PullRequestPayload
+	if err := json.Unmarshal([]byte(eventPayload), &payload); err != nil {
+		return 0, err
+	}
+	return payload.Index, nil
+}
+
 func (task *ActionTask) GenerateToken() (err error) {
 	task.Token, task.TokenSalt, task.TokenHash, task.TokenLastEight, err = generateSaltedToken()
 	return err
@@ -215,40 +283,105 @@ func GetRunningTaskByToken(ctx context.Context, token string) (*ActionTask, erro
 	return nil, errNotExist
 }
 
-func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask, bool, error) {
-	ctx, committer, err := db.TxContext(ctx)
-	if err != nil {
-		return nil, false, err
-	}
-	defer committer.Close()
-
-	e := db.GetEngine(ctx)
-
+// runnerJobCond returns the condition of the jobs which the runner could pick according to its scope,
+// and false if the runner isn't allowed to pick any job by the runner sharing policy of its owner.
+func runnerJobCond(ctx context.Context, runner *ActionRunner) (builder.Cond, bool, error) {
 	jobCond := builder.NewCond()
 	if runner.RepoID != 0 {
+		policy, err := GetRunnerSharingPolicyOfRepo(ctx, runner.RepoID)
+		if err != nil {
+			return nil, false, err
+		}
+		if !policy.AllowsRepoRunners() {
+			log.Trace("runner %d of repo %d is not allowed by the runner sharing policy of the owner", runner.ID, runner.RepoID)
+			return nil, false, nil
+		}
 		jobCond = builder.Eq{"repo_id": runner.RepoID}
 	} else if runner.OwnerID != 0 {
 		jobCond = builder.In("repo_id", builder.Select("`repository`.id").From("repository").
 			Join("INNER", "repo_unit", "`repository`.id = `repo_unit`.repo_id").
 			Where(builder.Eq{"`repository`.owner_id": runner.OwnerID, "`repo_unit`.type": unit.TypeActions}))
+	} else {
+		// the runners of the instance can't run the jobs of the owners which don't allow them,
+		// or the jobs of the private repositories of the owners which only allow them for the public ones
+		jobCond = builder.NotIn("owner_id", ownersWithRunnerSharingPolicy(builder.NewCond(), RunnerSharingPolicyNoShared, RunnerSharingPolicyOwnerOnly)).And(
+			builder.NotIn("owner_id", ownersWithRunnerSharingPolicy(builder.NewCond(), RunnerSharingPolicySharedPublicOnly)).
+				Or(builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"is_private": false}))))
 	}
 	if jobCond.IsValid() {
 		jobCond = builder.In("run_id", builder.Select("id").From("action_run").Where(jobCond))
 	}
+	return jobCond, true, nil
+}
+
+func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask, bool, error) {
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer committer.Close()
+
+	e := db.GetEngine(ctx)
+
+	if !runner.IsInActiveHours(time.Now()) {
+		log.Trace("runner %d is out of its active hours", runner.ID)
+		return nil, false, nil
+	}
+
+	jobCond, allowed, err := runnerJobCond(ctx, runner)
+	if err != nil {
+		return nil, false, err
+	} else if !allowed {
+		return nil, false, nil
+	}
 
 	var jobs []*ActionRunJob
-	if err := e.Where("task_id=? AND status=?", 0, StatusWaiting).And(jobCond).Asc("updated", "id").Find(&jobs); err != nil {
+	if err := e.Where("task_id=? AND status=? AND is_external=?", 0, StatusWaiting, false).And(jobCond).Asc("updated", "id").Find(&jobs); err != nil {
+		return nil, false, err
+	}
+
+	// the frozen jobs keep waiting until their freeze windows end
+	freezeWindows, err := GetActiveFreezeWindows(ctx, timeutil.TimeStampNow())
+	if err != nil {
 		return nil, false, err
 	}
 
 	// TODO: a more efficient way to filter labels
 	var job *ActionRunJob
 	log.Trace("runner labels: %v", runner.AgentLabels)
+	affinity := newRunnerAffinity(runner)
+	sandboxPolicy := runner.SandboxPolicy()
 	for _, v := range jobs {
-		if isSubset(runner.AgentLabels, v.RunsOn) {
-			job = v
-			break
+		// check the operating systems before the labels, since a runner with all the labels could still imply another one,
+		// the jobs whose runs-on conflict have been failed by the preflight checks when they were created
+		if err := CheckRunnerOS(runner.AgentLabels, v.RunsOn); err != nil {
+			log.Trace("Runner %d can't run job %d: %v", runner.ID, v.ID, err)
+			continue
+		}
+		if !isSubset(runner.AgentLabels, v.RunsOn) || !v.allowsRunnerByLabelNamespaces(runner) {
+			continue
 		}
+		if v.AvoidSpotRunners && runner.IsSpot() {
+			continue
+		}
+		if w := FreezeWindowOfJob(freezeWindows, v); w != nil {
+			log.Trace("Job %d is frozen by freeze window %d", v.ID, w.ID)
+			continue
+		}
+		if err := CheckSandboxPolicy(sandboxPolicy, v.WorkflowPayload); err != nil {
+			log.Trace("Runner %d can't run job %d: %v", runner.ID, v.ID, err)
+			continue
+		}
+		if !v.canBePickedBy(ctx, runner) {
+			continue
+		}
+		if ok, err := affinity.allows(ctx, v); err != nil {
+			return nil, false, err
+		} else if !ok {
+			continue
+		}
+		job = v
+		break
 	}
 	if job == nil {
 		return nil, false, nil
@@ -272,6 +405,10 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 		OwnerID:           job.OwnerID,
 		CommitSHA:         job.CommitSHA,
 		IsForkPullRequest: job.IsForkPullRequest,
+		Overrides:         job.Overrides,
+	}
+	if runner.Version != "" {
+		task.Environment = map[string]string{TaskEnvironmentRunnerVersion: runner.Version}
 	}
 	if err := task.GenerateToken(); err != nil {
 		return nil, false, err
@@ -314,7 +451,10 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 	}
 
 	job.TaskID = task.ID
-	if n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}); err != nil {
+	if n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}); errors.As(err, &ErrConcurrentUpdate{}) {
+		// the job has been picked by another runner or cancelled
+		return nil, false, nil
+	} else if err != nil {
 		return nil, false, err
 	} else if n != 1 {
 		return nil, false, nil
@@ -338,6 +478,20 @@ func UpdateTask(ctx context.Context, task *ActionTask, cols ...string) error {
 	return err
 }
 
+// FindTasksWithStoredLogs returns the tasks whose logs are in the storage and haven't expired, with only their ids and log file names
+func FindTasksWithStoredLogs(ctx context.Context) ([]*ActionTask, error) {
+	var tasks []*ActionTask
+	return tasks, db.GetEngine(ctx).Cols("id", "log_filename").
+		Where(builder.Eq{"log_in_storage": true, "log_expired": false}).
+		Find(&tasks)
+}
+
+// SetTasksLogExpired marks the logs of the tasks as expired by their ids, e.g. when the files have been lost
+func SetTasksLogExpired(ctx context.Context, taskIDs []int64) error {
+	_, err := db.GetEngine(ctx).In("id", taskIDs).Cols("log_expired").Update(&ActionTask{LogExpired: true})
+	return err
+}
+
 // UpdateTaskByState updates the task by the state.
 // It will always update the task if the state is not final, even there is no change.
 // So it will update ActionTask.Updated to avoid the task being judged as a zombie task.
@@ -369,16 +523,28 @@ func UpdateTaskByState(ctx context.Context, state *runnerv1.TaskState) (*ActionT
 
 	// state.Result is not unspecified means the task is finished
 	if state.Result != runnerv1.Result_RESULT_UNSPECIFIED {
+		from := task.Status
+		if !from.CanTransitTo(Status(state.Result)) {
+			return nil, ErrIllegalStatusTransition{Kind: "task", ID: task.ID, From: from, To: Status(state.Result)}
+		}
 		task.Status = Status(state.Result)
+		if task.Status == StatusSuccess {
+			// the license scan submitted by the task fails it, even if the steps ignore the result
+			if failed, err := hasFailedLicenseScan(ctx, task.ID); err != nil {
+				return nil, err
+			} else if failed {
+				task.Status = StatusFailure
+			}
+		}
 		task.Stopped = timeutil.TimeStamp(state.StoppedAt.AsTime().Unix())
-		if err := UpdateTask(ctx, task, "status", "stopped"); err != nil {
+		task.ErrorClass = classifyTaskError(task.Status, task.ErrorClass)
+		if err := UpdateTask(ctx, task, "status", "stopped", "error_class"); err != nil {
 			return nil, err
 		}
-		if _, err := UpdateRunJob(ctx, &ActionRunJob{
-			ID:      task.JobID,
-			Status:  task.Status,
-			Stopped: task.Stopped,
-		}, nil); err != nil {
+		if err := fireStatusTransition(ctx, &StatusTransition{Task: task, From: from, To: task.Status}); err != nil {
+			return nil, err
+		}
+		if err := updateJobByStoppedTask(ctx, task); err != nil {
 			return nil, err
 		}
 	} else {
@@ -415,11 +581,34 @@ func UpdateTaskByState(ctx context.Context, state *runnerv1.TaskState) (*ActionT
 	if err := committer.Commit(); err != nil {
 		return nil, err
 	}
+	cache.Remove(taskStepsCacheKey(task.ID))
 
 	return task, nil
 }
 
+// updateJobByStoppedTask makes the job of the task stop with the status of the task,
+// the job is kept if it has stopped before, e.g. it was cancelled while its task was running
+func updateJobByStoppedTask(ctx context.Context, task *ActionTask) error {
+	job, err := GetRunJobByID(ctx, task.JobID)
+	if err != nil {
+		return err
+	}
+	if job.Status.IsDone() {
+		return nil
+	}
+	job.Status = task.Status
+	job.Stopped = task.Stopped
+	_, err = UpdateRunJob(ctx, job, nil, "status", "stopped")
+	return err
+}
+
 func StopTask(ctx context.Context, taskID int64, status Status) error {
+	return StopTaskWithErrorClass(ctx, taskID, status, "")
+}
+
+// StopTaskWithErrorClass stops the task like StopTask, the error class is used if the task fails,
+// e.g. TaskErrorClassInfrastructure when the runner of the task is lost
+func StopTaskWithErrorClass(ctx context.Context, taskID int64, status Status, errorClass string) error {
 	if !status.IsDone() {
 		return fmt.Errorf("cannot stop task with status %v", status)
 	}
@@ -435,18 +624,26 @@ func StopTask(ctx context.Context, taskID int64, status Status) error {
 		return nil
 	}
 
+	if !task.Status.CanTransitTo(status) {
+		return ErrIllegalStatusTransition{Kind: "task", ID: task.ID, From: task.Status, To: status}
+	}
+
 	now := timeutil.TimeStampNow()
+	from := task.Status
 	task.Status = status
 	task.Stopped = now
-	if _, err := UpdateRunJob(ctx, &ActionRunJob{
-		ID:      task.JobID,
-		Status:  task.Status,
-		Stopped: task.Stopped,
-	}, nil); err != nil {
+	if errorClass == "" {
+		errorClass = task.ErrorClass
+	}
+	task.ErrorClass = classifyTaskError(status, errorClass)
+	if err := updateJobByStoppedTask(ctx, task); err != nil {
 		return err
 	}
 
-	if err := UpdateTask(ctx, task, "status", "stopped"); err != nil {
+	if err := UpdateTask(ctx, task, "status", "stopped", "error_class"); err != nil {
+		return err
+	}
+	if err := fireStatusTransition(ctx, &StatusTransition{Task: task, From: from, To: task.Status}); err != nil {
 		return err
 	}
 
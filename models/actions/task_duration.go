@@ -0,0 +1,57 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// TaskDuration is the total duration of the tasks of a repository which ran on a runner
+type TaskDuration struct {
+	RepoID   int64
+	RunnerID int64
+	Seconds  int64
+}
+
+// FindTaskDurationsOptions represents the options to sum the durations of the tasks,
+// only the tasks which have stopped in the period are counted
+type FindTaskDurationsOptions struct {
+	RepoID        int64
+	OwnerID       int64
+	StoppedSince  timeutil.TimeStamp
+	StoppedBefore timeutil.TimeStamp
+}
+
+func (opts FindTaskDurationsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond().And(builder.Gt{"started": 0}, builder.Gt{"stopped": 0})
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.StoppedSince > 0 {
+		cond = cond.And(builder.Gte{"stopped": opts.StoppedSince})
+	}
+	if opts.StoppedBefore > 0 {
+		cond = cond.And(builder.Lt{"stopped": opts.StoppedBefore})
+	}
+	return cond
+}
+
+// SumTaskDurations sums the durations of the tasks grouped by their repositories and runners
+func SumTaskDurations(ctx context.Context, opts FindTaskDurationsOptions) ([]*TaskDuration, error) {
+	var durations []*TaskDuration
+	return durations, db.GetEngine(ctx).Table("action_task").
+		Select("repo_id, runner_id, SUM(stopped - started) AS seconds").
+		Where(opts.ToConds()).
+		GroupBy("repo_id, runner_id").
+		OrderBy("repo_id, runner_id").
+		Find(&durations)
+}
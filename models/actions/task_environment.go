@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Well-known keys of the environment reported by runners,
+// the versions of tools are reported with the key TaskEnvironmentToolPrefix + tool name, e.g. "tool.node".
+const (
+	TaskEnvironmentOS                   = "os"
+	TaskEnvironmentOSBuild              = "os_build"
+	TaskEnvironmentArch                 = "arch"
+	TaskEnvironmentContainerImage       = "container_image"
+	TaskEnvironmentContainerImageDigest = "container_image_digest"
+	TaskEnvironmentRunnerVersion        = "runner_version"
+	TaskEnvironmentToolPrefix           = "tool."
+)
+
+const (
+	maxTaskEnvironmentEntries  = 64
+	maxTaskEnvironmentKeyLen   = 64
+	maxTaskEnvironmentValueLen = 255
+)
+
+// ValidateTaskEnvironment checks the environment reported by a runner is not too large to record
+func ValidateTaskEnvironment(env map[string]string) error {
+	if len(env) > maxTaskEnvironmentEntries {
+		return fmt.Errorf("too many entries: %d > %d", len(env), maxTaskEnvironmentEntries)
+	}
+	for k, v := range env {
+		if k == "" || len(k) > maxTaskEnvironmentKeyLen || strings.ContainsAny(k, " \t\r\n") {
+			return fmt.Errorf("invalid key %q", k)
+		}
+		if len(v) > maxTaskEnvironmentValueLen {
+			return fmt.Errorf("value of %q is too long: %d > %d", k, len(v), maxTaskEnvironmentValueLen)
+		}
+	}
+	return nil
+}
+
+// UpdateTaskEnvironment merges the environment reported by the runner into the recorded one of the task
+func UpdateTaskEnvironment(ctx context.Context, task *ActionTask, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+	if err := ValidateTaskEnvironment(env); err != nil {
+		return err
+	}
+
+	merged := make(map[string]string, len(task.Environment)+len(env))
+	maps.Copy(merged, task.Environment)
+	maps.Copy(merged, env)
+	if maps.Equal(merged, task.Environment) {
+		return nil
+	}
+	if len(merged) > maxTaskEnvironmentEntries {
+		return fmt.Errorf("too many entries: %d > %d", len(merged), maxTaskEnvironmentEntries)
+	}
+	task.Environment = merged
+	return UpdateTask(ctx, task, "environment")
+}
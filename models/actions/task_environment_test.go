@@ -0,0 +1,28 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTaskEnvironment(t *testing.T) {
+	assert.NoError(t, ValidateTaskEnvironment(map[string]string{
+		TaskEnvironmentOS:                   "linux",
+		TaskEnvironmentContainerImageDigest: "sha256:0123456789abcdef",
+		TaskEnvironmentToolPrefix + "node":  "20.11.0",
+	}))
+	assert.Error(t, ValidateTaskEnvironment(map[string]string{"": "linux"}))
+	assert.Error(t, ValidateTaskEnvironment(map[string]string{"os build": "22631"}))
+	assert.Error(t, ValidateTaskEnvironment(map[string]string{TaskEnvironmentOSBuild: strings.Repeat("a", 256)}))
+
+	tooMany := map[string]string{}
+	for i := 0; i <= maxTaskEnvironmentEntries; i++ {
+		tooMany[TaskEnvironmentToolPrefix+strings.Repeat("a", i+1)] = "1.0"
+	}
+	assert.Error(t, ValidateTaskEnvironment(tooMany))
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// Classes of the errors which made the tasks not succeed
+const (
+	TaskErrorClassInfrastructure = "infrastructure" // the runner or its machine failed, e.g. the disk is full or the runner is lost
+	TaskErrorClassUser           = "user"           // the workflow failed, e.g. a test failed or the task timed out
+	TaskErrorClassCancellation   = "cancellation"   // the task was cancelled
+)
+
+// IsValidReportedTaskErrorClass returns whether the class could be reported by runners, only the failures could be told apart by them
+func IsValidReportedTaskErrorClass(class string) bool {
+	return class == TaskErrorClassInfrastructure || class == TaskErrorClassUser
+}
+
+// classifyTaskError returns the error class of a task which has stopped with the status,
+// hint is the class known before the task stopped, e.g. reported by the runner, it's used for the failures
+func classifyTaskError(status Status, hint string) string {
+	switch status {
+	case StatusCancelled:
+		return TaskErrorClassCancellation
+	case StatusFailure:
+		if hint != "" {
+			return hint
+		}
+		return TaskErrorClassUser
+	default:
+		return ""
+	}
+}
+
+// SetTaskErrorClassHint records the error class known before the task stops, e.g. reported by the runner.
+// It's used when the task fails, and the error class of a stopped task is only changed if it failed.
+func SetTaskErrorClassHint(ctx context.Context, task *ActionTask, class string) error {
+	if task.ErrorClass == class {
+		return nil
+	}
+	if task.Status.IsDone() {
+		if task.Status != StatusFailure {
+			return nil
+		}
+		class = classifyTaskError(task.Status, class)
+	}
+	task.ErrorClass = class
+	return UpdateTask(ctx, task, "error_class")
+}
+
+// TaskErrorClassCount is the number of tasks with the error class, the class is empty for the tasks which didn't fail
+type TaskErrorClassCount struct {
+	ErrorClass string
+	Count      int64
+}
+
+// CountTasksByErrorClass counts the tasks which have stopped in the period by their error classes
+func CountTasksByErrorClass(ctx context.Context, opts FindTaskDurationsOptions) ([]*TaskErrorClassCount, error) {
+	var counts []*TaskErrorClassCount
+	return counts, db.GetEngine(ctx).Table("action_task").
+		Select("error_class, COUNT(*) AS count").
+		Where(opts.ToConds()).
+		GroupBy("error_class").
+		Find(&counts)
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskErrorClass(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// the hint of a running task is used when it fails
+	task := unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 47})
+	require.NoError(t, SetTaskErrorClassHint(ctx, task, TaskErrorClassInfrastructure))
+	require.NoError(t, StopTask(ctx, 47, StatusFailure))
+	task = unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 47})
+	assert.Equal(t, TaskErrorClassInfrastructure, task.ErrorClass)
+
+	require.NoError(t, db.Insert(ctx, []*ActionTask{
+		{ID: 1001, JobID: 192, RepoID: 1, OwnerID: 1001, Status: StatusRunning, Started: 1000, TokenHash: "error-class-test-1"},
+		{ID: 1002, JobID: 193, RepoID: 1, OwnerID: 1001, Status: StatusRunning, Started: 1000, TokenHash: "error-class-test-2"},
+		{ID: 1003, RepoID: 1, OwnerID: 1001, Status: StatusSuccess, Started: 1000, Stopped: 1100, TokenHash: "error-class-test-3"},
+	}))
+	require.NoError(t, StopTask(ctx, 1001, StatusFailure))
+	require.NoError(t, StopTaskWithErrorClass(ctx, 1002, StatusCancelled, TaskErrorClassInfrastructure))
+	assert.Equal(t, TaskErrorClassUser, unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 1001}).ErrorClass)
+	assert.Equal(t, TaskErrorClassCancellation, unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 1002}).ErrorClass)
+
+	// the hint doesn't change the tasks which didn't fail
+	task = unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 1003})
+	require.NoError(t, SetTaskErrorClassHint(ctx, task, TaskErrorClassInfrastructure))
+	assert.Empty(t, unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 1003}).ErrorClass)
+
+	counts, err := CountTasksByErrorClass(ctx, FindTaskDurationsOptions{OwnerID: 1001})
+	require.NoError(t, err)
+	got := map[string]int64{}
+	for _, count := range counts {
+		got[count.ErrorClass] = count.Count
+	}
+	assert.Equal(t, map[string]int64{"": 1, TaskErrorClassUser: 1, TaskErrorClassCancellation: 1}, got)
+}
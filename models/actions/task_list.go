@@ -45,6 +45,15 @@ func (tasks TaskList) LoadAttributes(ctx context.Context) error {
 	return tasks.LoadJobs(ctx)
 }
 
+// GetTasksByIDs returns the tasks with the ids keyed by their ids
+func GetTasksByIDs(ctx context.Context, ids []int64) (map[int64]*ActionTask, error) {
+	tasks := make(map[int64]*ActionTask, len(ids))
+	if len(ids) == 0 {
+		return tasks, nil
+	}
+	return tasks, db.GetEngine(ctx).In("id", ids).Find(&tasks)
+}
+
 type FindTaskOptions struct {
 	db.ListOptions
 	RepoID        int64
@@ -0,0 +1,77 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// TaskResourceSample is the resource usage of a task at a moment, reported by the runner
+type TaskResourceSample struct {
+	// the CPU usage in percent of one core, it could be over 100 if multiple cores are used
+	CPUPercent  float64 `json:"cpu_percent"`
+	MemoryBytes int64   `json:"memory_bytes"`
+	DiskBytes   int64   `json:"disk_bytes"`
+}
+
+// Validate checks the sample could be recorded
+func (s *TaskResourceSample) Validate() error {
+	if math.IsNaN(s.CPUPercent) || math.IsInf(s.CPUPercent, 0) || s.CPUPercent < 0 {
+		return errors.New("invalid cpu_percent")
+	}
+	if s.MemoryBytes < 0 || s.DiskBytes < 0 {
+		return errors.New("negative memory_bytes or disk_bytes")
+	}
+	return nil
+}
+
+// TaskResourceUsage summarizes the resource usage samples of a task, the sums are kept for the averages
+type TaskResourceUsage struct {
+	Samples        int64   `json:"samples"`
+	CPUPercentSum  float64 `json:"cpu_percent_sum"`
+	CPUPercentMax  float64 `json:"cpu_percent_max"`
+	MemoryBytesSum int64   `json:"memory_bytes_sum"`
+	MemoryBytesMax int64   `json:"memory_bytes_max"`
+	DiskBytesMax   int64   `json:"disk_bytes_max"`
+}
+
+// Add adds a sample to the summary
+func (u *TaskResourceUsage) Add(s *TaskResourceSample) {
+	u.Samples++
+	u.CPUPercentSum += s.CPUPercent
+	u.CPUPercentMax = max(u.CPUPercentMax, s.CPUPercent)
+	u.MemoryBytesSum += s.MemoryBytes
+	u.MemoryBytesMax = max(u.MemoryBytesMax, s.MemoryBytes)
+	u.DiskBytesMax = max(u.DiskBytesMax, s.DiskBytes)
+}
+
+// CPUPercentAvg returns the average CPU usage of the samples
+func (u *TaskResourceUsage) CPUPercentAvg() float64 {
+	if u.Samples == 0 {
+		return 0
+	}
+	return u.CPUPercentSum / float64(u.Samples)
+}
+
+// MemoryBytesAvg returns the average memory usage of the samples
+func (u *TaskResourceUsage) MemoryBytesAvg() int64 {
+	if u.Samples == 0 {
+		return 0
+	}
+	return u.MemoryBytesSum / u.Samples
+}
+
+// AddTaskResourceSample records a resource usage sample reported by the runner of the task
+func AddTaskResourceSample(ctx context.Context, task *ActionTask, sample *TaskResourceSample) error {
+	if err := sample.Validate(); err != nil {
+		return err
+	}
+	if task.ResourceUsage == nil {
+		task.ResourceUsage = &TaskResourceUsage{}
+	}
+	task.ResourceUsage.Add(sample)
+	return UpdateTask(ctx, task, "resource_usage")
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"math"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskResourceSampleValidate(t *testing.T) {
+	assert.NoError(t, (&TaskResourceSample{CPUPercent: 250, MemoryBytes: 1 << 30, DiskBytes: 1 << 32}).Validate())
+	assert.Error(t, (&TaskResourceSample{CPUPercent: math.NaN()}).Validate())
+	assert.Error(t, (&TaskResourceSample{CPUPercent: -1}).Validate())
+	assert.Error(t, (&TaskResourceSample{MemoryBytes: -1}).Validate())
+}
+
+func TestAddTaskResourceSample(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	task := unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 47})
+	require.NoError(t, AddTaskResourceSample(db.DefaultContext, task, &TaskResourceSample{CPUPercent: 50, MemoryBytes: 100, DiskBytes: 1000}))
+	require.NoError(t, AddTaskResourceSample(db.DefaultContext, task, &TaskResourceSample{CPUPercent: 150, MemoryBytes: 300, DiskBytes: 500}))
+	assert.Error(t, AddTaskResourceSample(db.DefaultContext, task, &TaskResourceSample{CPUPercent: math.Inf(1)}))
+
+	tasks, err := GetTasksByIDs(db.DefaultContext, []int64{47, 48})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Nil(t, tasks[48].ResourceUsage)
+	usage := tasks[47].ResourceUsage
+	require.NotNil(t, usage)
+	assert.EqualValues(t, 2, usage.Samples)
+	assert.InDelta(t, 100, usage.CPUPercentAvg(), 0)
+	assert.InDelta(t, 150, usage.CPUPercentMax, 0)
+	assert.EqualValues(t, 200, usage.MemoryBytesAvg())
+	assert.EqualValues(t, 300, usage.MemoryBytesMax)
+	assert.EqualValues(t, 1000, usage.DiskBytesMax)
+}
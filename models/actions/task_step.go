@@ -9,6 +9,8 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // ActionTaskStep represents a step of ActionTask
@@ -39,3 +41,19 @@ func GetTaskStepsByTaskID(ctx context.Context, taskID int64) ([]*ActionTaskStep,
 	var steps []*ActionTaskStep
 	return steps, db.GetEngine(ctx).Where("task_id=?", taskID).OrderBy("`index` ASC").Find(&steps)
 }
+
+// GetTaskStepsByTaskIDs returns the steps of the tasks, grouped by the task id
+func GetTaskStepsByTaskIDs(ctx context.Context, taskIDs []int64) (map[int64][]*ActionTaskStep, error) {
+	stepsMap := make(map[int64][]*ActionTaskStep, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return stepsMap, nil
+	}
+	var steps []*ActionTaskStep
+	if err := db.GetEngine(ctx).Where(builder.In("task_id", taskIDs)).OrderBy("task_id ASC, `index` ASC").Find(&steps); err != nil {
+		return nil, err
+	}
+	for _, step := range steps {
+		stepsMap[step.TaskID] = append(stepsMap[step.TaskID], step)
+	}
+	return stepsMap, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MaxTaskSummarySize is the max size of the summary of a task in bytes
+const MaxTaskSummarySize = 1024 * 1024
+
+// ActionTaskSummary is the markdown summary written by the steps of a task, like $GITHUB_STEP_SUMMARY of GitHub.
+// The summaries of the latest tasks of the jobs are aggregated as the summary of the run.
+type ActionTaskSummary struct {
+	ID      int64
+	TaskID  int64              `xorm:"UNIQUE"`
+	RunID   int64              `xorm:"index"`
+	RepoID  int64              `xorm:"index"`
+	Content string             `xorm:"LONGTEXT"`
+	Created timeutil.TimeStamp `xorm:"created"`
+	Updated timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionTaskSummary))
+}
+
+// AppendTaskSummary appends the content written by a step to the summary of the task
+func AppendTaskSummary(ctx context.Context, task *ActionTask, content string) error {
+	if err := task.LoadJob(ctx); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		summary := &ActionTaskSummary{}
+		has, err := db.GetEngine(ctx).Where("task_id=?", task.ID).Get(summary)
+		if err != nil {
+			return err
+		}
+		if len(summary.Content)+len(content) > MaxTaskSummarySize {
+			return util.NewInvalidArgumentErrorf("the summary of task %d is larger than %d bytes", task.ID, MaxTaskSummarySize)
+		}
+		if !has {
+			return db.Insert(ctx, &ActionTaskSummary{
+				TaskID:  task.ID,
+				RunID:   task.Job.RunID,
+				RepoID:  task.RepoID,
+				Content: content,
+			})
+		}
+		summary.Content += content
+		_, err = db.GetEngine(ctx).ID(summary.ID).Cols("content").Update(summary)
+		return err
+	})
+}
+
+// GetTaskSummariesByTaskIDs returns the summaries of the tasks, keyed by the task ids
+func GetTaskSummariesByTaskIDs(ctx context.Context, taskIDs []int64) (map[int64]*ActionTaskSummary, error) {
+	summaries := make(map[int64]*ActionTaskSummary, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return summaries, nil
+	}
+	var list []*ActionTaskSummary
+	if err := db.GetEngine(ctx).In("task_id", taskIDs).Find(&list); err != nil {
+		return nil, err
+	}
+	for _, summary := range list {
+		summaries[summary.TaskID] = summary
+	}
+	return summaries, nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendTaskSummary(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	task := unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 47})
+	require.NoError(t, AppendTaskSummary(db.DefaultContext, task, "### Tests\n"))
+	require.NoError(t, AppendTaskSummary(db.DefaultContext, task, "All passed\n"))
+
+	summaries, err := GetTaskSummariesByTaskIDs(db.DefaultContext, []int64{47, 48})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.EqualValues(t, 791, summaries[47].RunID)
+	assert.EqualValues(t, 4, summaries[47].RepoID)
+	assert.Equal(t, "### Tests\nAll passed\n", summaries[47].Content)
+
+	err = AppendTaskSummary(db.DefaultContext, task, strings.Repeat("a", MaxTaskSummarySize))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	unittest.AssertExistsAndLoadBean(t, &ActionTaskSummary{TaskID: 47, Content: "### Tests\nAll passed\n"})
+}
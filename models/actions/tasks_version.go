@@ -5,6 +5,7 @@ package actions
 
 import (
 	"context"
+	"sync"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/log"
@@ -101,5 +102,32 @@ func IncreaseTaskVersion(ctx context.Context, ownerID, repoID int64) error {
 		}
 	}
 
-	return committer.Commit()
+	if err := committer.Commit(); err != nil {
+		return err
+	}
+	notifyTasksVersionChanged()
+	return nil
+}
+
+// tasksVersionChanged is closed and replaced whenever a tasks version is increased in this process,
+// so the runners waiting for tasks are woken up at once instead of at their next poll
+var tasksVersionChanged = struct {
+	sync.Mutex
+	ch chan struct{}
+}{ch: make(chan struct{})}
+
+// TasksVersionChanged returns a channel closed on the next increase of any tasks version in this process.
+// The increase could be in a transaction which hasn't been committed yet when the channel is closed,
+// and the increases by the other instances are never notified, so the waiters should recheck the versions periodically.
+func TasksVersionChanged() <-chan struct{} {
+	tasksVersionChanged.Lock()
+	defer tasksVersionChanged.Unlock()
+	return tasksVersionChanged.ch
+}
+
+func notifyTasksVersionChanged() {
+	tasksVersionChanged.Lock()
+	defer tasksVersionChanged.Unlock()
+	close(tasksVersionChanged.ch)
+	tasksVersionChanged.ch = make(chan struct{})
 }
@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// The anomalies of the requests made with the tokens of the tasks, which may be the misuses of the tokens
+const (
+	TokenAnomalyRateLimited    = "rate_limited"     // the requests of the task exceed setting.Actions.TokenRateLimit
+	TokenAnomalyOtherRepo      = "other_repo"       // read a repository other than the one of the run
+	TokenAnomalyWriteOtherRepo = "write_other_repo" // wrote, or pushed to, a repository other than the one of the run
+	TokenAnomalyEnumeration    = "repo_enumeration" // listed repositories, or accessed many other repositories
+)
+
+// ActionTokenActivity is a request made with the token of a task, the activities of a run are its trail for audits
+type ActionTokenActivity struct {
+	ID           int64
+	TaskID       int64              `xorm:"INDEX NOT NULL"`
+	RunID        int64              `xorm:"INDEX NOT NULL"`
+	RepoID       int64              `xorm:"NOT NULL"`           // the repository of the run
+	TargetRepoID int64              `xorm:"NOT NULL DEFAULT 0"` // the repository requested, 0 if the request isn't about a repository
+	Method       string             `xorm:"VARCHAR(16) NOT NULL"`
+	Path         string             `xorm:"VARCHAR(255) NOT NULL"`
+	Status       int                `xorm:"NOT NULL DEFAULT 0"`
+	Anomaly      string             `xorm:"VARCHAR(32) NOT NULL DEFAULT ''"`
+	Created      timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionTokenActivity))
+}
+
+// InsertTokenActivity records a request made with the token of a task, the path is truncated to fit the column
+func InsertTokenActivity(ctx context.Context, activity *ActionTokenActivity) error {
+	activity.Path = base.TruncateString(activity.Path, 255)
+	return db.Insert(ctx, activity)
+}
+
+// CountTokenActivitiesSince counts the requests made with the token of the task since the time
+func CountTokenActivitiesSince(ctx context.Context, taskID int64, since timeutil.TimeStamp) (int64, error) {
+	return db.GetEngine(ctx).Where("task_id = ? AND created >= ?", taskID, since).Count(new(ActionTokenActivity))
+}
+
+// CountTokenOtherRepos counts the distinct repositories other than the one of the run which have been requested with the token of the task
+func CountTokenOtherRepos(ctx context.Context, taskID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("task_id = ? AND target_repo_id > 0 AND target_repo_id <> repo_id", taskID).
+		Distinct("target_repo_id").Count(new(ActionTokenActivity))
+}
+
+type FindTokenActivitiesOptions struct {
+	db.ListOptions
+	RunID         int64
+	TaskID        int64
+	AnomalousOnly bool
+}
+
+func (opts FindTokenActivitiesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RunID > 0 {
+		cond = cond.And(builder.Eq{"run_id": opts.RunID})
+	}
+	if opts.TaskID > 0 {
+		cond = cond.And(builder.Eq{"task_id": opts.TaskID})
+	}
+	if opts.AnomalousOnly {
+		cond = cond.And(builder.Neq{"anomaly": ""})
+	}
+	return cond
+}
+
+func (opts FindTokenActivitiesOptions) ToOrders() string {
+	return "`id` ASC"
+}
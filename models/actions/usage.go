@@ -0,0 +1,74 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ActionUsage represents an action used by a workflow on the default branch of a repository,
+// it's an index to find out which repositories use an action when the action is compromised.
+type ActionUsage struct {
+	ID         int64              `xorm:"pk autoincr"`
+	RepoID     int64              `xorm:"index"`
+	WorkflowID string             `xorm:"VARCHAR(255)"` // the name of the workflow file
+	Action     string             `xorm:"VARCHAR(255) index"`
+	Ref        string             `xorm:"VARCHAR(255)"` // the tag, branch or commit SHA the action is used at
+	Updated    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionUsage))
+}
+
+// FindActionUsagesOptions represents the options to find the usages of actions
+type FindActionUsagesOptions struct {
+	db.ListOptions
+	RepoID int64
+	// the action name without ref, e.g. "actions/checkout" or "https://gitea.com/actions/checkout", it's case-insensitive
+	Action string
+	Ref    string
+}
+
+func (opts FindActionUsagesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"action": strings.ToLower(opts.Action)})
+	}
+	if opts.Ref != "" {
+		cond = cond.And(builder.Eq{"ref": opts.Ref})
+	}
+	return cond
+}
+
+func (opts FindActionUsagesOptions) ToOrders() string {
+	return "repo_id, workflow_id, id"
+}
+
+// ReplaceActionUsages replaces the indexed usages of actions of the repository
+func ReplaceActionUsages(ctx context.Context, repoID int64, usages []*ActionUsage) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.DeleteByBean(ctx, &ActionUsage{RepoID: repoID}); err != nil {
+			return err
+		}
+		for _, usage := range usages {
+			usage.ID = 0
+			usage.RepoID = repoID
+			usage.Action = strings.ToLower(usage.Action)
+		}
+		if len(usages) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, usages)
+	})
+}
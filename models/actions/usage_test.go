@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceActionUsages(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	require.NoError(t, ReplaceActionUsages(db.DefaultContext, 1, []*ActionUsage{
+		{WorkflowID: "build.yml", Action: "Actions/Checkout", Ref: "v4"},
+		{WorkflowID: "build.yml", Action: "actions/setup-go", Ref: "v5"},
+	}))
+	require.NoError(t, ReplaceActionUsages(db.DefaultContext, 2, []*ActionUsage{
+		{WorkflowID: "test.yml", Action: "actions/checkout", Ref: "v3"},
+	}))
+
+	usages, err := db.Find[ActionUsage](db.DefaultContext, FindActionUsagesOptions{Action: "actions/checkout"})
+	require.NoError(t, err)
+	if assert.Len(t, usages, 2) {
+		assert.EqualValues(t, 1, usages[0].RepoID)
+		assert.Equal(t, "actions/checkout", usages[0].Action)
+		assert.EqualValues(t, 2, usages[1].RepoID)
+	}
+
+	usages, err = db.Find[ActionUsage](db.DefaultContext, FindActionUsagesOptions{Action: "actions/checkout", Ref: "v3"})
+	require.NoError(t, err)
+	assert.Len(t, usages, 1)
+
+	// replacing removes the usages which are gone
+	require.NoError(t, ReplaceActionUsages(db.DefaultContext, 1, nil))
+	usages, err = db.Find[ActionUsage](db.DefaultContext, FindActionUsagesOptions{RepoID: 1})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
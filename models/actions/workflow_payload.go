@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// workflowPayloadCompressMinSize is the smallest payload compressed when it's stored, the smaller ones aren't worth it
+const workflowPayloadCompressMinSize = 1024
+
+// workflowPayloadMaxDecodedSize is the most memory a stored payload could be decompressed into
+const workflowPayloadMaxDecodedSize = 256 << 20
+
+// zstdMagic is the magic number of the zstd frames, a YAML payload never starts with it
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	// the options are valid, so they never fail, and EncodeAll and DecodeAll could be called concurrently
+	workflowPayloadEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	workflowPayloadDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(workflowPayloadMaxDecodedSize))
+)
+
+// WorkflowPayload is the definition of a job sent to the runners, a single workflow with the job only.
+// It's compressed with zstd when it's stored if it isn't small, the generated workflows could be huge and very repetitive.
+// The payloads stored before they were compressed are read as they are.
+type WorkflowPayload []byte
+
+func (p *WorkflowPayload) FromDB(b []byte) error {
+	if !bytes.HasPrefix(b, zstdMagic) {
+		*p = bytes.Clone(b)
+		return nil
+	}
+	decoded, err := workflowPayloadDecoder.DecodeAll(b, nil)
+	if err != nil {
+		return fmt.Errorf("decompress workflow payload: %w", err)
+	}
+	*p = decoded
+	return nil
+}
+
+func (p *WorkflowPayload) ToDB() ([]byte, error) {
+	if len(*p) < workflowPayloadCompressMinSize {
+		return *p, nil
+	}
+	return workflowPayloadEncoder.EncodeAll(*p, make([]byte, 0, len(*p)/4)), nil
+}
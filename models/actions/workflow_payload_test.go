@@ -0,0 +1,45 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowPayload(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	small := []byte("name: test\njobs:\n  test:\n    runs-on: ubuntu-latest\n")
+	large := []byte("name: test\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n" + strings.Repeat("      - run: echo hello\n", 1000))
+
+	for _, payload := range [][]byte{small, large} {
+		job := unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: 192})
+		job.WorkflowPayload = payload
+		_, err := db.GetEngine(db.DefaultContext).ID(job.ID).Cols("workflow_payload").Update(job)
+		assert.NoError(t, err)
+
+		job, err = GetRunJobByID(db.DefaultContext, 192)
+		assert.NoError(t, err)
+		assert.Equal(t, payload, []byte(job.WorkflowPayload))
+
+		var stored struct {
+			WorkflowPayload []byte
+		}
+		_, err = db.GetEngine(db.DefaultContext).Table("action_run_job").Where("id=?", 192).Get(&stored)
+		assert.NoError(t, err)
+		if len(payload) < workflowPayloadCompressMinSize {
+			assert.Equal(t, payload, stored.WorkflowPayload)
+		} else {
+			assert.True(t, bytes.HasPrefix(stored.WorkflowPayload, zstdMagic))
+			assert.Less(t, len(stored.WorkflowPayload), len(payload)/10)
+		}
+	}
+}
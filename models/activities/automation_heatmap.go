@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// GetUserAutomationHeatmapData returns the automation activity of the user, which is the successful runs triggered by the user
+// of the events configured by setting.Actions.AutomationActivityEvents. For organizations, the runs of all owned repositories are counted.
+func GetUserAutomationHeatmapData(ctx context.Context, user, doer *user_model.User) ([]*UserHeatmapData, error) {
+	if !ActivityReadable(user, doer) {
+		return make([]*UserHeatmapData, 0), nil
+	}
+
+	cond := builder.NewCond()
+	if user.IsOrganization() {
+		cond = cond.And(builder.Eq{"owner_id": user.ID})
+	} else {
+		cond = cond.And(builder.Eq{"trigger_user_id": user.ID})
+	}
+	if doer == nil || !doer.IsAdmin {
+		cond = cond.And(builder.In("repo_id", builder.Select("id").From("repository").
+			Where(repo_model.AccessibleRepositoryCondition(doer, unit.TypeActions))))
+	}
+	return getAutomationHeatmapData(ctx, cond)
+}
+
+// GetRepoAutomationHeatmapData returns the automation activity of the repository, like GetUserAutomationHeatmapData
+func GetRepoAutomationHeatmapData(ctx context.Context, repo *repo_model.Repository) ([]*UserHeatmapData, error) {
+	return getAutomationHeatmapData(ctx, builder.Eq{"repo_id": repo.ID})
+}
+
+func getAutomationHeatmapData(ctx context.Context, cond builder.Cond) ([]*UserHeatmapData, error) {
+	hdata := make([]*UserHeatmapData, 0)
+	if len(setting.Actions.AutomationActivityEvents) == 0 {
+		return hdata, nil
+	}
+
+	// Group by 15 minute intervals like the heatmap of the contributions, see getUserHeatmapData
+	groupBy := "stopped / 900 * 900"
+	groupByName := "timestamp" // We need this extra case because mssql doesn't allow grouping by alias
+	switch {
+	case setting.Database.Type.IsMySQL():
+		groupBy = "stopped DIV 900 * 900"
+	case setting.Database.Type.IsMSSQL():
+		groupByName = groupBy
+	}
+
+	return hdata, db.GetEngine(ctx).
+		Select(groupBy+" AS timestamp, count(id) as contributions").
+		Table("action_run").
+		Where(cond).
+		And(builder.Eq{"status": actions_model.StatusSuccess}).
+		And(builder.In("event", setting.Actions.AutomationActivityEvents)).
+		And("stopped > ?", timeutil.TimeStampNow()-31536000).
+		GroupBy(groupByName).
+		OrderBy("timestamp").
+		Find(&hdata)
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities_test
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAutomationHeatmapData(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	publicRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	privateRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+	require.True(t, privateRepo.IsPrivate)
+
+	now := timeutil.TimeStampNow()
+	for i, run := range []*actions_model.ActionRun{
+		{RepoID: publicRepo.ID, Event: webhook_module.HookEventRelease, Status: actions_model.StatusSuccess},
+		{RepoID: privateRepo.ID, Event: webhook_module.HookEventRelease, Status: actions_model.StatusSuccess},
+		// failed runs and the runs of other events aren't counted
+		{RepoID: publicRepo.ID, Event: webhook_module.HookEventRelease, Status: actions_model.StatusFailure},
+		{RepoID: publicRepo.ID, Event: webhook_module.HookEventPush, Status: actions_model.StatusSuccess},
+		// nor the runs stopped more than a year ago
+		{RepoID: publicRepo.ID, Event: webhook_module.HookEventRelease, Status: actions_model.StatusSuccess, Stopped: now - 2*31536000},
+	} {
+		run.OwnerID = user.ID
+		run.TriggerUserID = user.ID
+		run.Index = int64(1000 + i)
+		if run.Stopped == 0 {
+			run.Stopped = now
+		}
+		require.NoError(t, db.Insert(ctx, run))
+	}
+
+	total := func(data []*activities_model.UserHeatmapData, err error) int64 {
+		require.NoError(t, err)
+		return activities_model.GetTotalContributionsInHeatmap(data)
+	}
+
+	// the automation activity is disabled by default
+	assert.EqualValues(t, 0, total(activities_model.GetUserAutomationHeatmapData(ctx, user, user)))
+
+	defer test.MockVariableValue(&setting.Actions.AutomationActivityEvents, []string{"release", "workflow_dispatch"})()
+	assert.EqualValues(t, 2, total(activities_model.GetUserAutomationHeatmapData(ctx, user, user)))
+	// the runs of the repositories the doer can't access aren't counted
+	assert.EqualValues(t, 1, total(activities_model.GetUserAutomationHeatmapData(ctx, user, other)))
+	assert.EqualValues(t, 1, total(activities_model.GetUserAutomationHeatmapData(ctx, user, nil)))
+	assert.EqualValues(t, 0, total(activities_model.GetUserAutomationHeatmapData(ctx, other, user)))
+
+	assert.EqualValues(t, 1, total(activities_model.GetRepoAutomationHeatmapData(ctx, privateRepo)))
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strconv"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/organization"
@@ -45,6 +46,8 @@ const (
 	NotificationSourceCommit
 	// NotificationSourceRepository is a notification for a repository
 	NotificationSourceRepository
+	// NotificationSourceActionRun is a notification of an actions run, e.g. a comment on it
+	NotificationSourceActionRun
 )
 
 // Notification represents a notification
@@ -59,13 +62,15 @@ type Notification struct {
 	IssueID   int64  `xorm:"INDEX NOT NULL"`
 	CommitID  string `xorm:"INDEX"`
 	CommentID int64
+	RunID     int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
 
 	UpdatedBy int64 `xorm:"INDEX NOT NULL"`
 
-	Issue      *issues_model.Issue    `xorm:"-"`
-	Repository *repo_model.Repository `xorm:"-"`
-	Comment    *issues_model.Comment  `xorm:"-"`
-	User       *user_model.User       `xorm:"-"`
+	Issue      *issues_model.Issue      `xorm:"-"`
+	Repository *repo_model.Repository   `xorm:"-"`
+	Comment    *issues_model.Comment    `xorm:"-"`
+	Run        *actions_model.ActionRun `xorm:"-"`
+	User       *user_model.User         `xorm:"-"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"updated INDEX NOT NULL"`
@@ -127,6 +132,24 @@ func createIssueNotification(ctx context.Context, userID int64, issue *issues_mo
 	return db.Insert(ctx, notification)
 }
 
+func createActionRunNotification(ctx context.Context, userID int64, run *actions_model.ActionRun, updatedByID int64) error {
+	return db.Insert(ctx, &Notification{
+		UserID:    userID,
+		RepoID:    run.RepoID,
+		Status:    NotificationStatusUnread,
+		Source:    NotificationSourceActionRun,
+		RunID:     run.ID,
+		UpdatedBy: updatedByID,
+	})
+}
+
+func updateActionRunNotification(ctx context.Context, notification *Notification, updatedByID int64) error {
+	notification.Status = NotificationStatusUnread
+	notification.UpdatedBy = updatedByID
+	_, err := db.GetEngine(ctx).ID(notification.ID).Cols("status", "updated_by").Update(notification)
+	return err
+}
+
 func updateIssueNotification(ctx context.Context, userID, issueID, commentID, updatedByID int64) error {
 	notification, err := GetIssueNotification(ctx, userID, issueID)
 	if err != nil {
@@ -173,9 +196,23 @@ func (n *Notification) LoadAttributes(ctx context.Context) (err error) {
 	if err = n.loadComment(ctx); err != nil {
 		return err
 	}
+	if err = n.loadRun(ctx); err != nil {
+		return err
+	}
 	return err
 }
 
+func (n *Notification) loadRun(ctx context.Context) (err error) {
+	if n.Run == nil && n.RunID != 0 {
+		n.Run, err = actions_model.GetRunByID(ctx, n.RunID)
+		if err != nil {
+			return fmt.Errorf("getRunByID [%d]: %w", n.RunID, err)
+		}
+		n.Run.Repo = n.Repository
+	}
+	return nil
+}
+
 func (n *Notification) loadRepo(ctx context.Context) (err error) {
 	if n.Repository == nil {
 		n.Repository, err = repo_model.GetRepositoryByID(ctx, n.RepoID)
@@ -245,6 +282,8 @@ func (n *Notification) HTMLURL(ctx context.Context) string {
 		return n.Repository.HTMLURL() + "/commit/" + url.PathEscape(n.CommitID)
 	case NotificationSourceRepository:
 		return n.Repository.HTMLURL()
+	case NotificationSourceActionRun:
+		return n.Run.HTMLURL()
 	}
 	return ""
 }
@@ -261,6 +300,8 @@ func (n *Notification) Link(ctx context.Context) string {
 		return n.Repository.Link() + "/commit/" + url.PathEscape(n.CommitID)
 	case NotificationSourceRepository:
 		return n.Repository.Link()
+	case NotificationSourceActionRun:
+		return n.Run.Link()
 	}
 	return ""
 }
@@ -321,6 +362,15 @@ func setIssueNotificationStatusReadIfUnread(ctx context.Context, userID, issueID
 	return err
 }
 
+// SetActionRunReadBy sets the notification of the actions run to be read by the user
+func SetActionRunReadBy(ctx context.Context, runID, userID int64) error {
+	_, err := db.GetEngine(ctx).
+		Where("user_id = ? AND run_id = ? AND status = ?", userID, runID, NotificationStatusUnread).
+		Cols("status").
+		Update(&Notification{Status: NotificationStatusRead})
+	return err
+}
+
 // SetRepoReadBy sets repo to be visited by given user.
 func SetRepoReadBy(ctx context.Context, userID, repoID int64) error {
 	_, err := db.GetEngine(ctx).Where(builder.Eq{
@@ -6,6 +6,7 @@ package activities
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	access_model "code.gitea.io/gitea/models/perm/access"
@@ -25,6 +26,7 @@ type FindNotificationOptions struct {
 	UserID            int64
 	RepoID            int64
 	IssueID           int64
+	RunID             int64
 	Status            []NotificationStatus
 	Source            []NotificationSource
 	UpdatedAfterUnix  int64
@@ -43,6 +45,9 @@ func (opts FindNotificationOptions) ToConds() builder.Cond {
 	if opts.IssueID != 0 {
 		cond = cond.And(builder.Eq{"notification.issue_id": opts.IssueID})
 	}
+	if opts.RunID != 0 {
+		cond = cond.And(builder.Eq{"notification.run_id": opts.RunID})
+	}
 	if len(opts.Status) > 0 {
 		if len(opts.Status) == 1 {
 			cond = cond.And(builder.Eq{"notification.status": opts.Status[0]})
@@ -169,6 +174,67 @@ func createOrUpdateIssueNotifications(ctx context.Context, issueID, commentID, n
 	return nil
 }
 
+// CreateOrUpdateActionRunNotifications creates an actions run notification for each participant of the run,
+// or updates it if already exists.
+// receiverID > 0 just send to receiver, else send to the participants: the user who triggered the run and the commenters.
+func CreateOrUpdateActionRunNotifications(ctx context.Context, runID, notificationAuthorID, receiverID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		run, err := actions_model.GetRunByID(ctx, runID)
+		if err != nil {
+			return err
+		}
+		if err := run.LoadRepo(ctx); err != nil {
+			return err
+		}
+
+		var toNotify container.Set[int64]
+		if receiverID > 0 {
+			toNotify = container.SetOf(receiverID)
+		} else {
+			toNotify = container.SetOf(run.TriggerUserID)
+			posterIDs, err := actions_model.FindRunCommentsPosterIDs(ctx, run.ID)
+			if err != nil {
+				return err
+			}
+			toNotify.AddMultiple(posterIDs...)
+			// dont notify user who cause notification
+			toNotify.Remove(notificationAuthorID)
+		}
+
+		notifications, err := db.Find[Notification](ctx, FindNotificationOptions{RunID: run.ID})
+		if err != nil {
+			return err
+		}
+		existing := make(map[int64]*Notification, len(notifications))
+		for _, n := range notifications {
+			existing[n.UserID] = n
+		}
+
+		for userID := range toNotify {
+			user, err := user_model.GetUserByID(ctx, userID)
+			if err != nil {
+				if user_model.IsErrUserNotExist(err) {
+					continue
+				}
+				return err
+			}
+			if !user.IsActive || !access_model.CheckRepoUnitUser(ctx, run.Repo, user, unit.TypeActions) {
+				continue
+			}
+			if n, ok := existing[userID]; ok {
+				if err := updateActionRunNotification(ctx, n, notificationAuthorID); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := createActionRunNotification(ctx, userID, run, notificationAuthorID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // NotificationList contains a list of notifications
 type NotificationList []*Notification
 
@@ -186,6 +252,9 @@ func (nl NotificationList) LoadAttributes(ctx context.Context) error {
 	if _, err := nl.LoadComments(ctx); err != nil {
 		return err
 	}
+	if _, err := nl.LoadRuns(ctx); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -264,7 +333,7 @@ func (nl NotificationList) LoadRepos(ctx context.Context) (repo_model.Repository
 func (nl NotificationList) getPendingIssueIDs() []int64 {
 	ids := make(container.Set[int64], len(nl))
 	for _, notification := range nl {
-		if notification.Issue != nil {
+		if notification.Issue != nil || notification.IssueID == 0 {
 			continue
 		}
 		ids.Add(notification.IssueID)
@@ -470,6 +539,36 @@ func (nl NotificationList) LoadComments(ctx context.Context) ([]int, error) {
 	return failures, nil
 }
 
+// LoadRuns loads the actions runs of the notifications from database
+func (nl NotificationList) LoadRuns(ctx context.Context) ([]int, error) {
+	runIDs := container.FilterSlice(nl, func(n *Notification) (int64, bool) {
+		return n.RunID, n.RunID != 0 && n.Run == nil
+	})
+	if len(runIDs) == 0 {
+		return []int{}, nil
+	}
+
+	runs := make(map[int64]*actions_model.ActionRun, len(runIDs))
+	if err := db.GetEngine(ctx).In("id", runIDs).Find(&runs); err != nil {
+		return nil, err
+	}
+
+	failures := []int{}
+	for i, notification := range nl {
+		if notification.RunID == 0 || notification.Run != nil {
+			continue
+		}
+		notification.Run = runs[notification.RunID]
+		if notification.Run == nil {
+			log.Error("Notification[%d]: RunID: %d Not Found", notification.ID, notification.RunID)
+			failures = append(failures, i)
+			continue
+		}
+		notification.Run.Repo = notification.Repository
+	}
+	return failures, nil
+}
+
 // LoadIssuePullRequests loads all issues' pull requests if possible
 func (nl NotificationList) LoadIssuePullRequests(ctx context.Context) error {
 	issues := make(map[int64]*issues_model.Issue, len(nl))
@@ -7,6 +7,7 @@ import (
 	"context"
 	"testing"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -31,6 +32,46 @@ func TestCreateOrUpdateIssueNotifications(t *testing.T) {
 	assert.Equal(t, activities_model.NotificationStatusUnread, notf.Status)
 }
 
+func TestCreateOrUpdateActionRunNotifications(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	// repo 1 is the only fixture repository with Actions enabled
+	run := &actions_model.ActionRun{RepoID: 1, OwnerID: 2, Index: 1, TriggerUserID: 8, Status: actions_model.StatusSuccess}
+	assert.NoError(t, db.Insert(db.DefaultContext, run))
+	for _, posterID := range []int64{2, 9, 10} {
+		assert.NoError(t, db.Insert(db.DefaultContext, &actions_model.ActionRunComment{RepoID: 1, RunID: run.ID, PosterID: posterID, Content: "comment"}))
+	}
+
+	assert.NoError(t, activities_model.CreateOrUpdateActionRunNotifications(db.DefaultContext, run.ID, 2, 0))
+
+	// User 2 is the author and user 9 is inactive, thus notifications for user 8 and 10 are created
+	for _, userID := range []int64{8, 10} {
+		notf := unittest.AssertExistsAndLoadBean(t, &activities_model.Notification{UserID: userID, RunID: run.ID})
+		assert.Equal(t, activities_model.NotificationStatusUnread, notf.Status)
+		assert.Equal(t, activities_model.NotificationSourceActionRun, notf.Source)
+		assert.EqualValues(t, 2, notf.UpdatedBy)
+	}
+	unittest.AssertNotExistsBean(t, &activities_model.Notification{UserID: 2, RunID: run.ID})
+	unittest.AssertNotExistsBean(t, &activities_model.Notification{UserID: 9, RunID: run.ID})
+
+	// a mentioned user is notified alone
+	assert.NoError(t, activities_model.CreateOrUpdateActionRunNotifications(db.DefaultContext, run.ID, 10, 4))
+	unittest.AssertExistsAndLoadBean(t, &activities_model.Notification{UserID: 4, RunID: run.ID, UpdatedBy: 10})
+	unittest.AssertExistsAndLoadBean(t, &activities_model.Notification{UserID: 8, RunID: run.ID, UpdatedBy: 2})
+
+	// visiting the run marks its notification as read, and a new comment marks it as unread again
+	assert.NoError(t, activities_model.SetActionRunReadBy(db.DefaultContext, run.ID, 8))
+	notf := unittest.AssertExistsAndLoadBean(t, &activities_model.Notification{UserID: 8, RunID: run.ID})
+	assert.Equal(t, activities_model.NotificationStatusRead, notf.Status)
+	notf = unittest.AssertExistsAndLoadBean(t, &activities_model.Notification{UserID: 10, RunID: run.ID})
+	assert.Equal(t, activities_model.NotificationStatusUnread, notf.Status)
+
+	assert.NoError(t, activities_model.CreateOrUpdateActionRunNotifications(db.DefaultContext, run.ID, 10, 0))
+	notf = unittest.AssertExistsAndLoadBean(t, &activities_model.Notification{UserID: 8, RunID: run.ID})
+	assert.Equal(t, activities_model.NotificationStatusUnread, notf.Status)
+	assert.EqualValues(t, 10, notf.UpdatedBy)
+	unittest.AssertCount(t, &activities_model.Notification{RunID: run.ID}, 4)
+}
+
 func TestNotificationsForUser(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
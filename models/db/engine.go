@@ -99,8 +99,14 @@ func newXORMEngine() (*xorm.Engine, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newXORMEngineWithConnStr(connStr)
+}
 
-	var engine *xorm.Engine
+func newXORMEngineWithConnStr(connStr string) (*xorm.Engine, error) {
+	var (
+		engine *xorm.Engine
+		err    error
+	)
 
 	if setting.Database.Type.IsPostgreSQL() && len(setting.Database.Schema) > 0 {
 		// OK whilst we sort out our schema issues - create a schema aware postgres
@@ -137,6 +143,18 @@ func InitEngine(ctx context.Context) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	configureXORMEngine(ctx, xormEngine)
+	SetDefaultEngine(ctx, xormEngine)
+
+	if setting.Database.ReplicaHost != "" {
+		if err := initReplicaEngine(ctx); err != nil {
+			return fmt.Errorf("failed to connect to the read replica: %w", err)
+		}
+	}
+	return nil
+}
+
+func configureXORMEngine(ctx context.Context, xormEngine *xorm.Engine) {
 	xormEngine.SetMapper(names.GonicMapper{})
 	// WARNING: for serv command, MUST remove the output to os.stdout,
 	// so use log file to instead print to stdout.
@@ -153,9 +171,6 @@ func InitEngine(ctx context.Context) error {
 			Logger:    log.GetLogger("xorm"),
 		})
 	}
-
-	SetDefaultEngine(ctx, xormEngine)
-	return nil
 }
 
 // SetDefaultEngine sets the default engine for db
@@ -176,6 +191,10 @@ func UnsetDefaultEngine() {
 		_ = x.Close()
 		x = nil
 	}
+	if replica != nil {
+		_ = replica.Close()
+		replica = nil
+	}
 	DefaultContext = nil
 }
 
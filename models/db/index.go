@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"code.gitea.io/gitea/modules/setting"
 )
@@ -26,6 +27,54 @@ var (
 	ErrGetResourceIndexFailed = errors.New("get resource index failed")
 )
 
+// resourceIndexLockMessages are the errors reported by the supported databases
+// when concurrent transactions compete for the lock of the same resource index record.
+var resourceIndexLockMessages = []string{
+	"deadlock",                     // MySQL, PostgreSQL, MSSQL
+	"lock wait timeout",            // MySQL
+	"could not serialize access",   // PostgreSQL
+	"database is locked",           // SQLite
+	"please retry the transaction", // TiDB
+}
+
+// uniqueViolationMessages are the errors reported by the supported databases when a unique constraint is violated
+var uniqueViolationMessages = []string{
+	"duplicate key",            // PostgreSQL, MSSQL
+	"duplicate entry",          // MySQL
+	"unique constraint failed", // SQLite
+	"violation of unique key",  // MSSQL
+}
+
+// IsErrResourceIndexConflict returns whether the error is caused by concurrent transactions competing for a resource index,
+// such errors are transient and the whole transaction could be retried.
+// A violation of a unique constraint is only treated as a conflict if it's on one of uniqueKeys,
+// which are the name of the unique index of the resources, and the "table.column" list SQLite reports instead.
+func IsErrResourceIndexConflict(err error, uniqueKeys ...string) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrGetResourceIndexFailed) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, v := range resourceIndexLockMessages {
+		if strings.Contains(msg, v) {
+			return true
+		}
+	}
+	for _, v := range uniqueViolationMessages {
+		if !strings.Contains(msg, v) {
+			continue
+		}
+		for _, key := range uniqueKeys {
+			if strings.Contains(msg, strings.ToLower(key)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // SyncMaxResourceIndex sync the max index with the resource
 func SyncMaxResourceIndex(ctx context.Context, tableName string, groupID, maxIndex int64) (err error) {
 	e := GetEngine(ctx)
@@ -58,10 +107,10 @@ func SyncMaxResourceIndex(ctx context.Context, tableName string, groupID, maxInd
 	return nil
 }
 
-func postgresGetNextResourceIndex(ctx context.Context, tableName string, groupID int64) (int64, error) {
+func postgresGetNextResourceIndex(ctx context.Context, tableName string, groupID, count int64) (int64, error) {
 	res, err := GetEngine(ctx).Query(fmt.Sprintf("INSERT INTO %s (group_id, max_index) "+
-		"VALUES (?,1) ON CONFLICT (group_id) DO UPDATE SET max_index = %s.max_index+1 RETURNING max_index",
-		tableName, tableName), groupID)
+		"VALUES (?,?) ON CONFLICT (group_id) DO UPDATE SET max_index = %s.max_index+? RETURNING max_index",
+		tableName, tableName), groupID, count, count)
 	if err != nil {
 		return 0, err
 	}
@@ -71,10 +120,10 @@ func postgresGetNextResourceIndex(ctx context.Context, tableName string, groupID
 	return strconv.ParseInt(string(res[0]["max_index"]), 10, 64)
 }
 
-func mysqlGetNextResourceIndex(ctx context.Context, tableName string, groupID int64) (int64, error) {
+func mysqlGetNextResourceIndex(ctx context.Context, tableName string, groupID, count int64) (int64, error) {
 	if _, err := GetEngine(ctx).Exec(fmt.Sprintf("INSERT INTO %s (group_id, max_index) "+
-		"VALUES (?,1) ON DUPLICATE KEY UPDATE max_index = max_index+1",
-		tableName), groupID); err != nil {
+		"VALUES (?,?) ON DUPLICATE KEY UPDATE max_index = max_index+?",
+		tableName), groupID, count, count); err != nil {
 		return 0, err
 	}
 
@@ -89,7 +138,7 @@ func mysqlGetNextResourceIndex(ctx context.Context, tableName string, groupID in
 	return idx, nil
 }
 
-func mssqlGetNextResourceIndex(ctx context.Context, tableName string, groupID int64) (int64, error) {
+func mssqlGetNextResourceIndex(ctx context.Context, tableName string, groupID, count int64) (int64, error) {
 	if _, err := GetEngine(ctx).Exec(fmt.Sprintf(`
 MERGE INTO %s WITH (HOLDLOCK) AS target
 USING (SELECT %d AS group_id) AS source
@@ -97,11 +146,11 @@ USING (SELECT %d AS group_id) AS source
 ON target.group_id = source.group_id
 WHEN MATCHED
 	THEN UPDATE
-			SET max_index = max_index + 1
+			SET max_index = max_index + %d
 WHEN NOT MATCHED
 	THEN INSERT (group_id, max_index)
-			VALUES (%d, 1);
-`, tableName, groupID, groupID)); err != nil {
+			VALUES (%d, %d);
+`, tableName, groupID, count, groupID, count)); err != nil {
 		return 0, err
 	}
 
@@ -118,19 +167,42 @@ WHEN NOT MATCHED
 
 // GetNextResourceIndex generates a resource index, it must run in the same transaction where the resource is created
 func GetNextResourceIndex(ctx context.Context, tableName string, groupID int64) (int64, error) {
+	return GetNextResourceIndexes(ctx, tableName, groupID, 1)
+}
+
+// GetNextResourceIndexes reserves count consecutive resource indexes with a single update and returns the first one.
+// It's useful when many resources of the same group are created together, since only one write-lock is acquired for them.
+// Like GetNextResourceIndex, it must run in the same transaction where the resources are created.
+func GetNextResourceIndexes(ctx context.Context, tableName string, groupID, count int64) (int64, error) {
+	if count <= 0 {
+		return 0, fmt.Errorf("invalid count %d of resource indexes", count)
+	}
+
+	var (
+		maxIdx int64
+		err    error
+	)
 	switch {
 	case setting.Database.Type.IsPostgreSQL():
-		return postgresGetNextResourceIndex(ctx, tableName, groupID)
+		maxIdx, err = postgresGetNextResourceIndex(ctx, tableName, groupID, count)
 	case setting.Database.Type.IsMySQL():
-		return mysqlGetNextResourceIndex(ctx, tableName, groupID)
+		maxIdx, err = mysqlGetNextResourceIndex(ctx, tableName, groupID, count)
 	case setting.Database.Type.IsMSSQL():
-		return mssqlGetNextResourceIndex(ctx, tableName, groupID)
+		maxIdx, err = mssqlGetNextResourceIndex(ctx, tableName, groupID, count)
+	default:
+		maxIdx, err = genericGetNextResourceIndex(ctx, tableName, groupID, count)
 	}
+	if err != nil {
+		return 0, err
+	}
+	return maxIdx - count + 1, nil
+}
 
+func genericGetNextResourceIndex(ctx context.Context, tableName string, groupID, count int64) (int64, error) {
 	e := GetEngine(ctx)
 
 	// try to update the max_index to next value, and acquire the write-lock for the record
-	res, err := e.Exec(fmt.Sprintf("UPDATE %s SET max_index=max_index+1 WHERE group_id=?", tableName), groupID)
+	res, err := e.Exec(fmt.Sprintf("UPDATE %s SET max_index=max_index+? WHERE group_id=?", tableName), count, groupID)
 	if err != nil {
 		return 0, err
 	}
@@ -141,7 +213,7 @@ func GetNextResourceIndex(ctx context.Context, tableName string, groupID int64)
 	if affected == 0 {
 		// this slow path is only for the first time of creating a resource index
 		_, errIns := e.Exec(fmt.Sprintf("INSERT INTO %s (group_id, max_index) VALUES (?, 0)", tableName), groupID)
-		res, err = e.Exec(fmt.Sprintf("UPDATE %s SET max_index=max_index+1 WHERE group_id=?", tableName), groupID)
+		res, err = e.Exec(fmt.Sprintf("UPDATE %s SET max_index=max_index+? WHERE group_id=?", tableName), count, groupID)
 		if err != nil {
 			return 0, err
 		}
@@ -124,3 +124,46 @@ func TestGetNextResourceIndex(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, 3, maxIndex) // the max index doesn't change because the transaction was rolled back
 }
+
+func TestGetNextResourceIndexes(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	xe := unittest.GetXORMEngine()
+	assert.NoError(t, xe.Sync(&TestIndex{}))
+
+	// reserve a range for a new record
+	firstIndex, err := db.GetNextResourceIndexes(db.DefaultContext, "test_index", 30, 5)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, firstIndex)
+	maxIndex, err := getCurrentResourceIndex(db.DefaultContext, "test_index", 30)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, maxIndex)
+
+	// the next index follows the reserved range
+	maxIndex, err = db.GetNextResourceIndex(db.DefaultContext, "test_index", 30)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 6, maxIndex)
+
+	// reserve a range for an existing record
+	firstIndex, err = db.GetNextResourceIndexes(db.DefaultContext, "test_index", 30, 3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, firstIndex)
+	maxIndex, err = getCurrentResourceIndex(db.DefaultContext, "test_index", 30)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 9, maxIndex)
+
+	_, err = db.GetNextResourceIndexes(db.DefaultContext, "test_index", 30, 0)
+	assert.Error(t, err)
+}
+
+func TestIsErrResourceIndexConflict(t *testing.T) {
+	assert.True(t, db.IsErrResourceIndexConflict(db.ErrGetResourceIndexFailed))
+	assert.True(t, db.IsErrResourceIndexConflict(fmt.Errorf("insert: %w", db.ErrGetResourceIndexFailed)))
+	assert.True(t, db.IsErrResourceIndexConflict(errors.New("Error 1213 (40001): Deadlock found when trying to get lock; try restarting transaction")))
+	assert.True(t, db.IsErrResourceIndexConflict(errors.New(`pq: duplicate key value violates unique constraint "UQE_action_run_repo_index"`), "UQE_action_run_repo_index"))
+	assert.True(t, db.IsErrResourceIndexConflict(errors.New("UNIQUE constraint failed: action_run.repo_id, action_run.index"), "UQE_action_run_repo_index", "action_run.repo_id, action_run.index"))
+	assert.False(t, db.IsErrResourceIndexConflict(errors.New(`pq: duplicate key value violates unique constraint "UQE_action_run_repo_index"`)))
+	assert.False(t, db.IsErrResourceIndexConflict(errors.New(`Error 1062 (23000): Duplicate entry '1' for key 'action_task.UQE_action_task_token_hash'`), "UQE_action_run_repo_index"))
+	assert.True(t, db.IsErrResourceIndexConflict(errors.New("database is locked")))
+	assert.False(t, db.IsErrResourceIndexConflict(errors.New("no such table: test_index")))
+	assert.False(t, db.IsErrResourceIndexConflict(nil))
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package db
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/xorm"
+)
+
+// replica is the engine of the read replica, nil if it isn't configured
+var replica *xorm.Engine
+
+func initReplicaEngine(ctx context.Context) error {
+	connStr, err := setting.DBReplicaConnStr()
+	if err != nil {
+		return err
+	}
+	engine, err := newXORMEngineWithConnStr(connStr)
+	if err != nil {
+		return err
+	}
+	configureXORMEngine(ctx, engine)
+	if replica != nil {
+		_ = replica.Close()
+	}
+	replica = engine
+	return nil
+}
+
+// WithReplica returns a context whose queries are run by the read replica if it's configured,
+// it's meant for the heavy read-only queries which can tolerate the replication lag, e.g. listing runs and counting stats.
+// The context must not be used to write, and the queries in a transaction keep running by the transaction.
+func WithReplica(ctx context.Context) context.Context {
+	if replica == nil || InTransaction(ctx) {
+		return ctx
+	}
+	return newContext(ctx, replica.Context(ctx), false)
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package db // it's not db_test, because the engine of the replica is private
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestWithReplica(t *testing.T) {
+	if !setting.Database.Type.IsSQLite3() {
+		t.Skip("the replica is mocked by an in-memory SQLite3 database")
+	}
+	ctx := DefaultContext
+
+	// the primary runs the queries if the replica isn't configured
+	assert.Equal(t, ctx, WithReplica(ctx))
+
+	engine, err := xorm.NewEngine("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer engine.Close()
+	defer test.MockVariableValue(&replica, engine)()
+
+	// the mocked replica is empty, so the table only exists on the primary
+	_, err = GetEngine(ctx).Count(new(replicaTestBean))
+	assert.Error(t, err)
+	require.NoError(t, GetEngine(ctx).Sync(new(replicaTestBean)))
+	_, err = GetEngine(ctx).Count(new(replicaTestBean))
+	assert.NoError(t, err)
+	_, err = GetEngine(WithReplica(ctx)).Count(new(replicaTestBean))
+	assert.Error(t, err)
+
+	// the queries in a transaction keep running by the transaction
+	require.NoError(t, WithTx(ctx, func(ctx context.Context) error {
+		assert.Equal(t, ctx, WithReplica(ctx))
+		return nil
+	}))
+}
+
+type replicaTestBean struct {
+	ID int64
+}
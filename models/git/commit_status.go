@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -184,6 +185,9 @@ func (status *CommitStatus) loadAttributes(ctx context.Context) (err error) {
 			return fmt.Errorf("getUserByID [%d]: %w", status.CreatorID, err)
 		}
 	}
+	if status.Creator == nil && status.CreatorID == user_model.ActionsUserID {
+		status.Creator = actions_model.ApplyBotIdentity(ctx, status.Repo, user_model.NewActionsUser())
+	}
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"sort"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/optional"
 
 	"github.com/gobwas/glob"
@@ -85,6 +86,18 @@ func GetFirstMatchProtectedBranchRule(ctx context.Context, repoID int64, branchN
 	return rules.GetFirstMatched(branchName), nil
 }
 
+// IsRefProtected checks if the ref is a protected branch or a protected tag
+func IsRefProtected(ctx context.Context, repoID int64, ref git.RefName) (bool, error) {
+	switch {
+	case ref.IsBranch():
+		return IsBranchProtected(ctx, repoID, ref.BranchName())
+	case ref.IsTag():
+		tags, err := GetMatchedProtectedTags(ctx, repoID, ref.TagName())
+		return len(tags) > 0, err
+	}
+	return false, nil
+}
+
 // IsBranchProtected checks if branch is protected
 func IsBranchProtected(ctx context.Context, repoID int64, branchName string) (bool, error) {
 	rule, err := GetFirstMatchProtectedBranchRule(ctx, repoID, branchName)
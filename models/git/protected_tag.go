@@ -97,6 +97,24 @@ func GetProtectedTags(ctx context.Context, repoID int64) ([]*ProtectedTag, error
 	return tags, db.GetEngine(ctx).Find(&tags, &ProtectedTag{RepoID: repoID})
 }
 
+// GetMatchedProtectedTags gets the protected tags of the repository which match the tag name
+func GetMatchedProtectedTags(ctx context.Context, repoID int64, tagName string) ([]*ProtectedTag, error) {
+	tags, err := GetProtectedTags(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]*ProtectedTag, 0, len(tags))
+	for _, tag := range tags {
+		if err := tag.EnsureCompiledPattern(); err != nil {
+			return nil, err
+		}
+		if tag.matchString(tagName) {
+			matched = append(matched, tag)
+		}
+	}
+	return matched, nil
+}
+
 // GetProtectedTagByID gets the protected tag with the specific id
 func GetProtectedTagByID(ctx context.Context, id int64) (*ProtectedTag, error) {
 	tag := new(ProtectedTag)
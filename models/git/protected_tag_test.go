@@ -9,6 +9,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -163,3 +164,42 @@ func TestIsUserAllowedToControlTag(t *testing.T) {
 		}
 	})
 }
+
+func TestGetMatchedProtectedTags(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	tags, err := git_model.GetMatchedProtectedTags(db.DefaultContext, 1, "v-1.1")
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 2) {
+		assert.EqualValues(t, 2, tags[0].ID)
+		assert.EqualValues(t, 3, tags[1].ID)
+	}
+
+	tags, err = git_model.GetMatchedProtectedTags(db.DefaultContext, 1, "v-2")
+	assert.NoError(t, err)
+	assert.Len(t, tags, 1)
+
+	tags, err = git_model.GetMatchedProtectedTags(db.DefaultContext, 1, "release")
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+}
+
+func TestIsRefProtected(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	protected, err := git_model.IsRefProtected(db.DefaultContext, 1, git.RefNameFromTag("v-1"))
+	assert.NoError(t, err)
+	assert.True(t, protected)
+
+	protected, err = git_model.IsRefProtected(db.DefaultContext, 1, git.RefNameFromTag("release"))
+	assert.NoError(t, err)
+	assert.False(t, protected)
+
+	protected, err = git_model.IsRefProtected(db.DefaultContext, 1, git.RefNameFromBranch("master"))
+	assert.NoError(t, err)
+	assert.False(t, protected)
+
+	protected, err = git_model.IsRefProtected(db.DefaultContext, 1, git.RefName("refs/pull/1/head"))
+	assert.NoError(t, err)
+	assert.False(t, protected)
+}
@@ -363,8 +363,15 @@ func (c *Comment) LoadPoster(ctx context.Context) (err error) {
 		} else {
 			log.Error("getUserByID[%d]: %v", c.ID, err)
 		}
+		return err
 	}
-	return err
+	if c.Poster.IsActions() {
+		if err := c.LoadIssue(ctx); err != nil {
+			return err
+		}
+		c.Poster = getActionsPoster(ctx, c.Issue.RepoID)
+	}
+	return nil
 }
 
 // AfterDelete is invoked from XORM after the object is deleted.
@@ -33,8 +33,19 @@ func (comments CommentList) LoadPosters(ctx context.Context) error {
 		return err
 	}
 
+	actionsPosters := make(map[int64]*user_model.User)
 	for _, comment := range comments {
 		comment.Poster = getPoster(comment.PosterID, posterMaps)
+		if comment.PosterID != user_model.ActionsUserID {
+			continue
+		}
+		if err := comment.LoadIssue(ctx); err != nil {
+			return err
+		}
+		if _, ok := actionsPosters[comment.Issue.RepoID]; !ok {
+			actionsPosters[comment.Issue.RepoID] = getActionsPoster(ctx, comment.Issue.RepoID)
+		}
+		comment.Poster = actionsPosters[comment.Issue.RepoID]
 	}
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"slices"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	project_model "code.gitea.io/gitea/models/project"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -205,10 +206,23 @@ func (issue *Issue) LoadPoster(ctx context.Context) (err error) {
 			}
 			return nil
 		}
+		if issue.Poster.IsActions() {
+			issue.Poster = getActionsPoster(ctx, issue.RepoID)
+		}
 	}
 	return err
 }
 
+// getActionsPoster returns the actions user with the bot identity of the repository
+func getActionsPoster(ctx context.Context, repoID int64) *user_model.User {
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if err != nil {
+		log.Error("GetRepositoryByID[%d]: %v", repoID, err)
+		return user_model.NewActionsUser()
+	}
+	return actions_model.ApplyBotIdentity(ctx, repo, user_model.NewActionsUser())
+}
+
 // LoadPullRequest loads pull request info
 func (issue *Issue) LoadPullRequest(ctx context.Context) (err error) {
 	if issue.IsPull {
@@ -88,8 +88,16 @@ func (issues IssueList) loadPosters(ctx context.Context) error {
 		return err
 	}
 
+	actionsPosters := make(map[int64]*user_model.User)
 	for _, issue := range issues {
 		issue.Poster = getPoster(issue.PosterID, posterMaps)
+		if issue.PosterID != user_model.ActionsUserID {
+			continue
+		}
+		if _, ok := actionsPosters[issue.RepoID]; !ok {
+			actionsPosters[issue.RepoID] = getActionsPoster(ctx, issue.RepoID)
+		}
+		issue.Poster = actionsPosters[issue.RepoID]
 	}
 	return nil
 }
@@ -220,3 +220,16 @@ func GetPullRequestByIssueIDs(ctx context.Context, issueIDs []int64) (PullReques
 		In("issue_id", issueIDs).
 		Find(&prs)
 }
+
+// GetMergedPullRequestsByMergedCommitIDs returns the pull requests of the repository merged by the commits, ordered by their merge times
+func GetMergedPullRequestsByMergedCommitIDs(ctx context.Context, repoID int64, commitIDs []string) (PullRequestList, error) {
+	prs := make([]*PullRequest, 0, len(commitIDs))
+	if len(commitIDs) == 0 {
+		return prs, nil
+	}
+	return prs, db.GetEngine(ctx).
+		Where("base_repo_id = ? AND has_merged = ?", repoID, true).
+		In("merged_commit_id", commitIDs).
+		Asc("merged_unix", "id").
+		Find(&prs)
+}
@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/models/migrations/v1_20"
 	"code.gitea.io/gitea/models/migrations/v1_21"
 	"code.gitea.io/gitea/models/migrations/v1_22"
+	"code.gitea.io/gitea/models/migrations/v1_23"
 	"code.gitea.io/gitea/models/migrations/v1_6"
 	"code.gitea.io/gitea/models/migrations/v1_7"
 	"code.gitea.io/gitea/models/migrations/v1_8"
@@ -587,6 +588,97 @@ var migrations = []Migration{
 	NewMigration("Drop wrongly created table o_auth2_application", v1_22.DropWronglyCreatedTable),
 
 	// Gitea 1.22.0-rc1 ends at 299
+
+	// v299 -> v300
+	NewMigration("Add ActionOutboxEvent table", v1_23.AddActionOutboxEventTable),
+	// v300 -> v301
+	NewMigration("Add external columns to action run and job", v1_23.AddExternalColumnsToActionRun),
+	// v301 -> v302
+	NewMigration("Add executor column to action runner", v1_23.AddExecutorColumnToActionRunner),
+	// v302 -> v303
+	NewMigration("Add environment column to action task", v1_23.AddEnvironmentColumnToActionTask),
+	// v303 -> v304
+	NewMigration("Add runner pinning columns to action run job", v1_23.AddRunnerPinningColumnsToActionRunJob),
+	// v304 -> v305
+	NewMigration("Add preflight error column to action run job", v1_23.AddPreflightErrorColumnToActionRunJob),
+	// v305 -> v306
+	NewMigration("Add missing requirements column to action run", v1_23.AddMissingRequirementsColumnToActionRun),
+	// v306 -> v307
+	NewMigration("Add ActionHandoffBlob table", v1_23.AddActionHandoffBlobTable),
+	// v307 -> v308
+	NewMigration("Add ActionUsage table", v1_23.AddActionUsageTable),
+	// v308 -> v309
+	NewMigration("Add ActionBlockedRef table", v1_23.AddActionBlockedRefTable),
+	// v309 -> v310
+	NewMigration("Add queued column to action run job", v1_23.AddQueuedColumnToActionRunJob),
+	// v310 -> v311
+	NewMigration("Add protected_refs_only column to secret", v1_23.AddProtectedRefsOnlyColumnToSecret),
+	// v311 -> v312
+	NewMigration("Add acknowledgement columns to action run", v1_23.AddAcknowledgementColumnsToActionRun),
+	// v312 -> v313
+	NewMigration("Add ActionRunComment table", v1_23.AddActionRunCommentTable),
+	// v313 -> v314
+	NewMigration("Add ActionRunIssue table", v1_23.AddActionRunIssueTable),
+	// v314 -> v315
+	NewMigration("Add RunID column to notification", v1_23.AddRunIDToNotification),
+	// v315 -> v316
+	NewMigration("Add ActionTaskSummary table", v1_23.AddActionTaskSummaryTable),
+	// v316 -> v317
+	NewMigration("Add ResourceUsage column to ActionTask", v1_23.AddResourceUsageColumnToActionTask),
+	// v317 -> v318
+	NewMigration("Add ErrorClass column to ActionTask", v1_23.AddErrorClassColumnToActionTask),
+	// v318 -> v319
+	NewMigration("Add Version column to ActionRunJob", v1_23.AddVersionColumnToActionRunJob),
+	// v319 -> v320
+	NewMigration("Add EventPayloadVersion column to ActionRun and ActionSchedule", v1_23.AddEventPayloadVersionColumnToActionRunAndSchedule),
+	// v320 -> v321
+	NewMigration("Add ActionDispatchPreset table", v1_23.AddActionDispatchPresetTable),
+	// v321 -> v322
+	NewMigration("Add Overrides column to ActionRunJob and ActionTask", v1_23.AddOverridesColumnToActionRunJobAndTask),
+	// v322 -> v323
+	NewMigration("Add ConcurrencyGroup and ConcurrencyCancel columns to ActionRun", v1_23.AddConcurrencyColumnsToActionRun),
+	// v323 -> v324
+	NewMigration("Add ActionLease table", v1_23.AddActionLeaseTable),
+	// v324 -> v325
+	NewMigration("Add DuplicateDeliveries column to ActionRun", v1_23.AddDuplicateDeliveriesToActionRun),
+	// v325 -> v326
+	NewMigration("Add SecretLeak table and LeakedUnix column to Secret", v1_23.AddSecretLeakTable),
+	// v326 -> v327
+	NewMigration("Add ActionTokenActivity table", v1_23.AddActionTokenActivityTable),
+	// v327 -> v328
+	NewMigration("Add TokenPermissions column to ActionRunJob", v1_23.AddTokenPermissionsToActionRunJob),
+	// v328 -> v329
+	NewMigration("Add ActionRunFilter table", v1_23.AddActionRunFilterTable),
+	// v329 -> v330
+	NewMigration("Add Component column to ActionRun and ActionRunJob", v1_23.AddComponentToActionRunAndJob),
+	// v330 -> v331
+	NewMigration("Add Checkout column to ActionRun", v1_23.AddCheckoutToActionRun),
+	// v331 -> v332
+	NewMigration("Add ActionCodeScanningAlert table", v1_23.AddActionCodeScanningAlertTable),
+	// v332 -> v333
+	NewMigration("Add ActionDependencySnapshot table", v1_23.AddActionDependencySnapshotTable),
+	// v333 -> v334
+	NewMigration("Add ActionLicenseScan table", v1_23.AddActionLicenseScanTable),
+	// v334 -> v335
+	NewMigration("Add AvoidSpotRunners column to ActionRunJob", v1_23.AddAvoidSpotRunnersColumnToActionRunJob),
+	// v335 -> v336
+	NewMigration("Add Egress column to ActionRun and ActionEgressViolation table", v1_23.AddActionEgressPolicy),
+	// v336 -> v337
+	NewMigration("Add update columns to ActionRunner", v1_23.AddUpdateColumnsToActionRunner),
+	// v337 -> v338
+	NewMigration("Add Environment column to ActionRunJob and ActionFreezeWindow table", v1_23.AddActionFreezeWindows),
+	// v338 -> v339
+	NewMigration("Add ExpiredUnix column to ActionRun", v1_23.AddExpiredUnixToActionRun),
+	// v339 -> v340
+	NewMigration("Add ActionConfigRevision table", v1_23.AddActionConfigRevisionTable),
+	// v340 -> v341
+	NewMigration("Add ActionDeadLetter table", v1_23.AddActionDeadLetterTable),
+	// v341 -> v342
+	NewMigration("Add ActionRunCreationFailure table", v1_23.AddActionRunCreationFailureTable),
+	// v342 -> v343
+	NewMigration("Add ActionRunDelivery table", v1_23.AddActionRunDeliveryTable),
+	// v343 -> v344
+	NewMigration("Add the retry state to ActionOutboxEvent", v1_23.AddRetryStateToActionOutboxEvent),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionOutboxEventTable(x *xorm.Engine) error {
+	type ActionOutboxEvent struct {
+		ID       int64
+		RunID    int64 `xorm:"index"`
+		RepoID   int64 `xorm:"index"`
+		Type     int
+		Attempts int
+		Created  timeutil.TimeStamp `xorm:"created"`
+	}
+	return x.Sync(new(ActionOutboxEvent))
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddExternalColumnsToActionRun(x *xorm.Engine) error {
+	type ActionRun struct {
+		ExternalSystem string
+		ExternalURL    string `xorm:"TEXT"`
+	}
+	type ActionRunJob struct {
+		IsExternal  bool
+		ExternalURL string `xorm:"TEXT"`
+	}
+	return x.Sync(new(ActionRun), new(ActionRunJob))
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddExecutorColumnToActionRunner(x *xorm.Engine) error {
+	type ActionRunner struct {
+		Executor string `xorm:"VARCHAR(64)"`
+	}
+	return x.Sync(new(ActionRunner))
+}
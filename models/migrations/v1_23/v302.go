@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddEnvironmentColumnToActionTask(x *xorm.Engine) error {
+	type ActionTask struct {
+		Environment map[string]string `xorm:"JSON TEXT"`
+	}
+	return x.Sync(new(ActionTask))
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddRunnerPinningColumnsToActionRunJob(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		PinnedRunnerID int64
+		RunnerPinning  int
+	}
+	return x.Sync(new(ActionRunJob))
+}
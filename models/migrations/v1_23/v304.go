@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddPreflightErrorColumnToActionRunJob(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		PreflightError string `xorm:"TEXT"`
+	}
+	return x.Sync(new(ActionRunJob))
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionHandoffBlobTable(x *xorm.Engine) error {
+	type ActionHandoffBlob struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RunID       int64  `xorm:"index unique(run_name)"`
+		RepoID      int64  `xorm:"index"`
+		Name        string `xorm:"VARCHAR(255) unique(run_name)"`
+		JobID       int64
+		StoragePath string
+		Size        int64
+		Created     timeutil.TimeStamp `xorm:"created"`
+		Updated     timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionHandoffBlob))
+}
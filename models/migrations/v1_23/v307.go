@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionUsageTable(x *xorm.Engine) error {
+	type ActionUsage struct {
+		ID         int64              `xorm:"pk autoincr"`
+		RepoID     int64              `xorm:"index"`
+		WorkflowID string             `xorm:"VARCHAR(255)"`
+		Action     string             `xorm:"VARCHAR(255) index"`
+		Ref        string             `xorm:"VARCHAR(255)"`
+		Updated    timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionUsage))
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionBlockedRefTable(x *xorm.Engine) error {
+	type ActionBlockedRef struct {
+		ID        int64              `xorm:"pk autoincr"`
+		Action    string             `xorm:"VARCHAR(255) UNIQUE(action_ref) NOT NULL"`
+		Ref       string             `xorm:"VARCHAR(255) UNIQUE(action_ref) NOT NULL"`
+		Reason    string             `xorm:"TEXT"`
+		CreatorID int64              `xorm:"INDEX"`
+		Created   timeutil.TimeStamp `xorm:"created"`
+	}
+	return x.Sync(new(ActionBlockedRef))
+}
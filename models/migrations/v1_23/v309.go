@@ -0,0 +1,17 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddQueuedColumnToActionRunJob(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		Queued timeutil.TimeStamp
+	}
+	return x.Sync(new(ActionRunJob))
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddAcknowledgementColumnsToActionRun(x *xorm.Engine) error {
+	type ActionRun struct {
+		AcknowledgedBy      int64  `xorm:"index"`
+		AcknowledgedComment string `xorm:"TEXT"`
+		Acknowledged        timeutil.TimeStamp
+	}
+	return x.Sync(new(ActionRun))
+}
@@ -0,0 +1,24 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunCommentTable(x *xorm.Engine) error {
+	type ActionRunComment struct {
+		ID       int64
+		RepoID   int64 `xorm:"index"`
+		RunID    int64 `xorm:"index"`
+		JobID    int64
+		PosterID int64              `xorm:"index"`
+		Content  string             `xorm:"LONGTEXT"`
+		Created  timeutil.TimeStamp `xorm:"created"`
+		Updated  timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionRunComment))
+}
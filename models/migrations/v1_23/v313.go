@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunIssueTable(x *xorm.Engine) error {
+	type ActionRunIssue struct {
+		ID            int64
+		RepoID        int64  `xorm:"index(workflow_ref)"`
+		WorkflowID    string `xorm:"index(workflow_ref)"`
+		Ref           string `xorm:"index(workflow_ref)"`
+		RunID         int64  `xorm:"UNIQUE"`
+		IssueID       int64  `xorm:"index"`
+		AutoClose     bool
+		ClosedByRunID int64
+		Created       timeutil.TimeStamp `xorm:"created"`
+	}
+	return x.Sync(new(ActionRunIssue))
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddRunIDToNotification(x *xorm.Engine) error {
+	type Notification struct {
+		RunID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+	return x.Sync(new(Notification))
+}
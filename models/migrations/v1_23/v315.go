@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionTaskSummaryTable(x *xorm.Engine) error {
+	type ActionTaskSummary struct {
+		ID      int64
+		TaskID  int64              `xorm:"UNIQUE"`
+		RunID   int64              `xorm:"index"`
+		RepoID  int64              `xorm:"index"`
+		Content string             `xorm:"LONGTEXT"`
+		Created timeutil.TimeStamp `xorm:"created"`
+		Updated timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionTaskSummary))
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddErrorClassColumnToActionTask(x *xorm.Engine) error {
+	type ActionTask struct {
+		ErrorClass string `xorm:"VARCHAR(32) index"`
+	}
+	return x.Sync(new(ActionTask))
+}
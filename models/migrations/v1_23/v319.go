@@ -0,0 +1,18 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddEventPayloadVersionColumnToActionRunAndSchedule(x *xorm.Engine) error {
+	type ActionRun struct {
+		EventPayloadVersion int `xorm:"NOT NULL DEFAULT 1"`
+	}
+	type ActionSchedule struct {
+		EventPayloadVersion int `xorm:"NOT NULL DEFAULT 1"`
+	}
+	return x.Sync(new(ActionRun), new(ActionSchedule))
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionDispatchPresetTable(x *xorm.Engine) error {
+	type ActionDispatchPreset struct {
+		ID         int64              `xorm:"pk autoincr"`
+		RepoID     int64              `xorm:"UNIQUE(repo_name) NOT NULL"`
+		Name       string             `xorm:"VARCHAR(255) UNIQUE(repo_name) NOT NULL"`
+		WorkflowID string             `xorm:"VARCHAR(255) NOT NULL"`
+		Ref        string             `xorm:"VARCHAR(255)"`
+		Inputs     map[string]string  `xorm:"JSON TEXT"`
+		CreatorID  int64              `xorm:"INDEX"`
+		Created    timeutil.TimeStamp `xorm:"created"`
+		Updated    timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionDispatchPreset))
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddOverridesColumnToActionRunJobAndTask(x *xorm.Engine) error {
+	type RunOverrides struct {
+		Vars   map[string]string `json:"vars,omitempty"`
+		Inputs map[string]string `json:"inputs,omitempty"`
+	}
+	type ActionRunJob struct {
+		Overrides *RunOverrides `xorm:"JSON TEXT"`
+	}
+	type ActionTask struct {
+		Overrides *RunOverrides `xorm:"JSON TEXT"`
+	}
+	return x.Sync(new(ActionRunJob), new(ActionTask))
+}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionLeaseTable(x *xorm.Engine) error {
+	type ActionLease struct {
+		ID      int64              `xorm:"pk autoincr"`
+		Name    string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+		Holder  string             `xorm:"VARCHAR(255) NOT NULL"`
+		Expires timeutil.TimeStamp `xorm:"NOT NULL"`
+		Updated timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionLease))
+}
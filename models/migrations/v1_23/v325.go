@@ -0,0 +1,31 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddSecretLeakTable(x *xorm.Engine) error {
+	type Secret struct {
+		LeakedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+	if err := x.Sync(new(Secret)); err != nil {
+		return err
+	}
+
+	type SecretLeak struct {
+		ID       int64
+		SecretID int64              `xorm:"UNIQUE(secret_task) NOT NULL"`
+		TaskID   int64              `xorm:"UNIQUE(secret_task) NOT NULL"`
+		Name     string             `xorm:"NOT NULL"`
+		OwnerID  int64              `xorm:"NOT NULL DEFAULT 0"`
+		RepoID   int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RunID    int64              `xorm:"NOT NULL DEFAULT 0"`
+		Created  timeutil.TimeStamp `xorm:"created NOT NULL"`
+	}
+	return x.Sync(new(SecretLeak))
+}
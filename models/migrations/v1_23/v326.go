@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionTokenActivityTable(x *xorm.Engine) error {
+	type ActionTokenActivity struct {
+		ID           int64
+		TaskID       int64              `xorm:"INDEX NOT NULL"`
+		RunID        int64              `xorm:"INDEX NOT NULL"`
+		RepoID       int64              `xorm:"NOT NULL"`
+		TargetRepoID int64              `xorm:"NOT NULL DEFAULT 0"`
+		Method       string             `xorm:"VARCHAR(16) NOT NULL"`
+		Path         string             `xorm:"VARCHAR(255) NOT NULL"`
+		Status       int                `xorm:"NOT NULL DEFAULT 0"`
+		Anomaly      string             `xorm:"VARCHAR(32) NOT NULL DEFAULT ''"`
+		Created      timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+	}
+	return x.Sync(new(ActionTokenActivity))
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunFilterTable(x *xorm.Engine) error {
+	type ActionRunFilter struct {
+		ID         int64  `xorm:"pk autoincr"`
+		UserID     int64  `xorm:"UNIQUE(user_repo_name) NOT NULL"`
+		RepoID     int64  `xorm:"UNIQUE(user_repo_name) INDEX NOT NULL"`
+		Name       string `xorm:"VARCHAR(255) UNIQUE(user_repo_name) NOT NULL"`
+		WorkflowID string `xorm:"VARCHAR(255)"`
+		Branch     string `xorm:"VARCHAR(255)"`
+		ActorID    int64
+		Status     int
+		Created    timeutil.TimeStamp `xorm:"created"`
+		Updated    timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionRunFilter))
+}
@@ -0,0 +1,18 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddComponentToActionRunAndJob(x *xorm.Engine) error {
+	type ActionRun struct {
+		Component string `xorm:"VARCHAR(255) index"`
+	}
+	type ActionRunJob struct {
+		Component string `xorm:"VARCHAR(255) index"`
+	}
+	return x.Sync(new(ActionRun), new(ActionRunJob))
+}
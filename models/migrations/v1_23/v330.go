@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddCheckoutToActionRun(x *xorm.Engine) error {
+	type RunCheckout struct {
+		Depth  int      `json:"depth,omitempty"`
+		Sparse []string `json:"sparse,omitempty"`
+		Filter string   `json:"filter,omitempty"`
+	}
+	type ActionRun struct {
+		Checkout *RunCheckout `xorm:"JSON TEXT"`
+	}
+	return x.Sync(new(ActionRun))
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionCodeScanningAlertTable(x *xorm.Engine) error {
+	type ActionCodeScanningAlert struct {
+		ID            int64
+		RepoID        int64              `xorm:"UNIQUE(repo_ref_fingerprint) NOT NULL"`
+		Ref           string             `xorm:"VARCHAR(255) UNIQUE(repo_ref_fingerprint) NOT NULL"`
+		Fingerprint   string             `xorm:"VARCHAR(64) UNIQUE(repo_ref_fingerprint) NOT NULL"`
+		Tool          string             `xorm:"VARCHAR(255) NOT NULL"`
+		RuleID        string             `xorm:"VARCHAR(255)"`
+		Severity      string             `xorm:"VARCHAR(16) NOT NULL"`
+		Message       string             `xorm:"TEXT"`
+		Path          string             `xorm:"VARCHAR(255)"`
+		StartLine     int                `xorm:"NOT NULL DEFAULT 0"`
+		EndLine       int                `xorm:"NOT NULL DEFAULT 0"`
+		State         string             `xorm:"VARCHAR(16) INDEX NOT NULL"`
+		FirstRunIndex int64              `xorm:"NOT NULL DEFAULT 0"`
+		LastRunIndex  int64              `xorm:"NOT NULL DEFAULT 0"`
+		FixedRunIndex int64              `xorm:"NOT NULL DEFAULT 0"`
+		Fixed         timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		Created       timeutil.TimeStamp `xorm:"created"`
+		Updated       timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionCodeScanningAlert))
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionDependencySnapshotTable(x *xorm.Engine) error {
+	type Dependency struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Scope   string `json:"scope,omitempty"`
+	}
+	type DependencyManifest struct {
+		Path         string        `json:"path"`
+		Ecosystem    string        `json:"ecosystem"`
+		Dependencies []*Dependency `json:"dependencies"`
+	}
+	type ActionDependencySnapshot struct {
+		ID        int64
+		RepoID    int64                 `xorm:"UNIQUE(repo_ref) NOT NULL"`
+		Ref       string                `xorm:"VARCHAR(255) UNIQUE(repo_ref) NOT NULL"`
+		CommitSHA string                `xorm:"VARCHAR(64) NOT NULL"`
+		RunIndex  int64                 `xorm:"NOT NULL DEFAULT 0"`
+		Manifests []*DependencyManifest `xorm:"JSON TEXT"`
+		Created   timeutil.TimeStamp    `xorm:"created"`
+		Updated   timeutil.TimeStamp    `xorm:"updated"`
+	}
+	return x.Sync(new(ActionDependencySnapshot))
+}
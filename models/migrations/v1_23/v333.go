@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionLicenseScanTable(x *xorm.Engine) error {
+	type LicenseViolation struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+		License string `json:"license,omitempty"`
+		Reason  string `json:"reason"`
+	}
+	type ActionLicenseScan struct {
+		ID         int64
+		RepoID     int64               `xorm:"INDEX NOT NULL"`
+		RunID      int64               `xorm:"INDEX NOT NULL"`
+		TaskID     int64               `xorm:"INDEX NOT NULL"`
+		Passed     bool                `xorm:"NOT NULL DEFAULT false"`
+		Packages   int                 `xorm:"NOT NULL DEFAULT 0"`
+		Violations []*LicenseViolation `xorm:"JSON TEXT"`
+		Created    timeutil.TimeStamp  `xorm:"created"`
+	}
+	return x.Sync(new(ActionLicenseScan))
+}
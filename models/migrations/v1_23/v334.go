@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddAvoidSpotRunnersColumnToActionRunJob(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		AvoidSpotRunners bool `xorm:"NOT NULL DEFAULT false"`
+	}
+	return x.Sync(new(ActionRunJob))
+}
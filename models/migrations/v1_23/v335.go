@@ -0,0 +1,31 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionEgressPolicy(x *xorm.Engine) error {
+	type RunEgress struct {
+		Allow []string `json:"allow"`
+	}
+	type ActionRun struct {
+		Egress *RunEgress `xorm:"JSON TEXT"`
+	}
+	type ActionEgressViolation struct {
+		ID      int64
+		RepoID  int64              `xorm:"INDEX NOT NULL"`
+		RunID   int64              `xorm:"INDEX NOT NULL"`
+		TaskID  int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Host    string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		Port    int                `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Count   int64              `xorm:"NOT NULL DEFAULT 1"`
+		Created timeutil.TimeStamp `xorm:"created"`
+		Updated timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionRun), new(ActionEgressViolation))
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddUpdateColumnsToActionRunner(x *xorm.Engine) error {
+	type ActionRunner struct {
+		UpdateVersion  string             `xorm:"VARCHAR(64)"`
+		UpdateSignaled timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		UpdateFailed   bool               `xorm:"NOT NULL DEFAULT false"`
+	}
+	return x.Sync(new(ActionRunner))
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionFreezeWindows(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		Environment string `xorm:"VARCHAR(255)"`
+	}
+	type ActionFreezeWindow struct {
+		ID           int64              `xorm:"pk autoincr"`
+		OwnerID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Name         string             `xorm:"VARCHAR(255) NOT NULL"`
+		Reason       string             `xorm:"TEXT"`
+		Environments []string           `xorm:"JSON TEXT"`
+		Labels       []string           `xorm:"JSON TEXT"`
+		StartUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		EndUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		Released     bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+		CreatorID    int64
+		Created      timeutil.TimeStamp `xorm:"created"`
+	}
+	return x.Sync(new(ActionRunJob), new(ActionFreezeWindow))
+}
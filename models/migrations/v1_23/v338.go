@@ -0,0 +1,17 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddExpiredUnixToActionRun(x *xorm.Engine) error {
+	type ActionRun struct {
+		ExpiredUnix timeutil.TimeStamp `xorm:"index NOT NULL DEFAULT 0"`
+	}
+	return x.Sync(new(ActionRun))
+}
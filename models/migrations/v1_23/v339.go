@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionConfigRevisionTable(x *xorm.Engine) error {
+	type ActionConfigRevision struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX(repo_created) NOT NULL"`
+		Enabled     bool               `xorm:"NOT NULL DEFAULT false"`
+		Config      string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX(repo_created) NOT NULL"`
+	}
+	if err := x.Sync(new(ActionConfigRevision)); err != nil {
+		return err
+	}
+
+	// the current settings of the repositories with Actions enabled are the first revisions,
+	// the ones before them are unknown
+	_, err := x.Exec("INSERT INTO action_config_revision (repo_id, enabled, config, created_unix) SELECT repo_id, ?, config, ? FROM repo_unit WHERE `type` = ?",
+		true, timeutil.TimeStampNow(), 10)
+	return err
+}
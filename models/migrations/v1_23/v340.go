@@ -0,0 +1,27 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionDeadLetterTable(x *xorm.Engine) error {
+	type ActionDeadLetter struct {
+		ID        int64
+		RepoID    int64              `xorm:"INDEX NOT NULL"`
+		Method    string             `xorm:"VARCHAR(255)"`
+		Event     string             `xorm:"VARCHAR(255)"`
+		Ref       string             `xorm:"VARCHAR(255)"`
+		CommitSHA string             `xorm:"VARCHAR(64)"`
+		Item      string             `xorm:"LONGTEXT"`
+		Error     string             `xorm:"LONGTEXT"`
+		Attempts  int64              `xorm:"NOT NULL DEFAULT 1"`
+		Created   timeutil.TimeStamp `xorm:"created INDEX"`
+		Updated   timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionDeadLetter))
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunCreationFailureTable(x *xorm.Engine) error {
+	type ActionRunCreationFailure struct {
+		ID            int64
+		RepoID        int64  `xorm:"INDEX(repo_created) NOT NULL"`
+		WorkflowID    string `xorm:"VARCHAR(255)"`
+		Event         string `xorm:"VARCHAR(255)"`
+		Ref           string `xorm:"VARCHAR(255)"`
+		CommitSHA     string `xorm:"VARCHAR(64)"`
+		TriggerUserID int64
+		Reason        string             `xorm:"VARCHAR(32) INDEX"`
+		Message       string             `xorm:"TEXT"`
+		Created       timeutil.TimeStamp `xorm:"created INDEX(repo_created)"`
+	}
+	return x.Sync(new(ActionRunCreationFailure))
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunDeliveryTable(x *xorm.Engine) error {
+	type ActionRunDelivery struct {
+		ID          int64
+		RepoID      int64              `xorm:"UNIQUE(repo_key) NOT NULL"`
+		DeliveryKey string             `xorm:"UNIQUE(repo_key) VARCHAR(64) NOT NULL"`
+		Delivered   timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+	return x.Sync(new(ActionRunDelivery))
+}
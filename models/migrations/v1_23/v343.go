@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRetryStateToActionOutboxEvent(x *xorm.Engine) error {
+	type ActionOutboxEvent struct {
+		DoneSteps       int64              `xorm:"NOT NULL DEFAULT 0"`
+		NextAttemptUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		IsDead          bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+		LastError       string             `xorm:"TEXT"`
+	}
+	return x.Sync(new(ActionOutboxEvent))
+}
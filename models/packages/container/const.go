@@ -3,7 +3,15 @@
 
 package container
 
+import "fmt"
+
 const (
 	ManifestFilename = "manifest.json"
 	UploadVersion    = "_upload"
 )
+
+// RunScratchImagePrefix returns the prefix of the images in the scratch namespace of an actions run,
+// they're pushed by the jobs of the run to the owner of its repository, and removed when the run is done
+func RunScratchImagePrefix(runID int64) string {
+	return fmt.Sprintf("actions-run-%d/", runID)
+}
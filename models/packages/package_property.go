@@ -26,6 +26,13 @@ const (
 	PropertyTypePackage // 2
 )
 
+// The properties of the package versions recorded as published by the runs of Actions, they're the provenance of the versions
+const (
+	PropertyActionsRepoID = "actions.repo_id"
+	PropertyActionsRunID  = "actions.run_id"
+	PropertyActionsRef    = "actions.ref"
+)
+
 // PackageProperty represents a property of a package, version or file
 type PackageProperty struct {
 	ID      int64        `xorm:"pk autoincr"`
@@ -38,6 +38,13 @@ func (p *Permission) IsAdmin() bool {
 	return p.AccessMode >= perm_model.AccessModeAdmin
 }
 
+// CanManageActions returns true if the user could administer the Actions of the repository, like deleting the runs
+// and managing the secrets, variables and runners. Besides the repository admins, it's granted to the teams with
+// the admin access to the Actions unit, which doesn't need the write access to the code.
+func (p *Permission) CanManageActions() bool {
+	return p.IsAdmin() || p.UnitAccessMode(unit.TypeActions) >= perm_model.AccessModeAdmin
+}
+
 // HasAnyUnitAccess returns true if the user might have at least one access mode to any unit of this repository.
 // It doesn't count the "everyone access mode".
 func (p *Permission) HasAnyUnitAccess() bool {
@@ -93,6 +100,13 @@ func (p *Permission) SetUnitsWithDefaultAccessMode(units []*repo_model.RepoUnit,
 	}
 }
 
+// SetUnitAccessMode sets the access mode of the unit, it does nothing if the repository doesn't have the unit
+func (p *Permission) SetUnitAccessMode(unitType unit.Type, mode perm_model.AccessMode) {
+	if _, ok := p.unitsMode[unitType]; ok {
+		p.unitsMode[unitType] = mode
+	}
+}
+
 // CanAccess returns true if user has mode access to the unit of the repository
 func (p *Permission) CanAccess(mode perm_model.AccessMode, unitType unit.Type) bool {
 	return p.UnitAccessMode(unitType) >= mode
@@ -6,12 +6,16 @@ package access
 import (
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
 	perm_model "code.gitea.io/gitea/models/perm"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHasAnyUnitAccess(t *testing.T) {
@@ -134,3 +138,67 @@ func TestUnitAccessMode(t *testing.T) {
 	}
 	assert.Equal(t, perm_model.AccessModeRead, perm.UnitAccessMode(unit.TypeWiki), "has unit, and map, use map")
 }
+
+func TestCanManageActions(t *testing.T) {
+	perm := Permission{AccessMode: perm_model.AccessModeAdmin}
+	assert.True(t, perm.CanManageActions(), "repo admin")
+
+	perm = Permission{
+		AccessMode: perm_model.AccessModeWrite,
+		unitsMode: map[unit.Type]perm_model.AccessMode{
+			unit.TypeCode:    perm_model.AccessModeWrite,
+			unit.TypeActions: perm_model.AccessModeWrite,
+		},
+	}
+	assert.False(t, perm.CanManageActions(), "code and actions writer")
+
+	perm = Permission{
+		AccessMode: perm_model.AccessModeRead,
+		unitsMode: map[unit.Type]perm_model.AccessMode{
+			unit.TypeCode:    perm_model.AccessModeRead,
+			unit.TypeActions: perm_model.AccessModeAdmin,
+		},
+	}
+	assert.True(t, perm.CanManageActions(), "actions admin without code write")
+	assert.False(t, perm.IsAdmin())
+	assert.False(t, perm.CanWrite(unit.TypeCode))
+	assert.True(t, perm.CanWrite(unit.TypeActions))
+}
+
+func TestGetUserRepoPermissionOfActionsTeams(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	require.NoError(t, db.Insert(ctx, &repo_model.RepoUnit{RepoID: repo.ID, Type: unit.TypeActions, Config: &repo_model.ActionsConfig{}}))
+	require.NoError(t, repo.LoadOwner(ctx))
+
+	// addTeam adds a team of the owner of the repository with the access to the code and the Actions, and the user to it
+	addTeam := func(name string, actionsMode perm_model.AccessMode, userID int64) {
+		team := &organization.Team{OrgID: repo.OwnerID, LowerName: name, Name: name, AccessMode: perm_model.AccessModeRead}
+		require.NoError(t, db.Insert(ctx, team))
+		require.NoError(t, db.Insert(ctx,
+			&organization.TeamUnit{OrgID: repo.OwnerID, TeamID: team.ID, Type: unit.TypeCode, AccessMode: perm_model.AccessModeRead},
+			&organization.TeamUnit{OrgID: repo.OwnerID, TeamID: team.ID, Type: unit.TypeActions, AccessMode: actionsMode},
+			&organization.TeamRepo{OrgID: repo.OwnerID, TeamID: team.ID, RepoID: repo.ID},
+			&organization.TeamUser{OrgID: repo.OwnerID, TeamID: team.ID, UID: userID},
+			&organization.OrgUser{OrgID: repo.OwnerID, UID: userID},
+		))
+	}
+	addTeam("actions-admins", perm_model.AccessModeAdmin, 5)
+	addTeam("actions-readers", perm_model.AccessModeRead, 8)
+	require.NoError(t, RecalculateAccesses(ctx, repo))
+
+	user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	perm, err := GetUserRepoPermission(ctx, repo, user5)
+	require.NoError(t, err)
+	assert.True(t, perm.CanManageActions())
+	assert.False(t, perm.IsAdmin())
+	assert.False(t, perm.CanWrite(unit.TypeCode))
+
+	user8 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 8})
+	perm, err = GetUserRepoPermission(ctx, repo, user8)
+	require.NoError(t, err)
+	assert.True(t, perm.CanRead(unit.TypeActions))
+	assert.False(t, perm.CanWrite(unit.TypeActions))
+	assert.False(t, perm.CanManageActions())
+}
@@ -5,18 +5,25 @@ package repo
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
+	"path"
 	"slices"
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 	"xorm.io/xorm/convert"
 )
@@ -168,8 +175,433 @@ func (cfg *PullRequestsConfig) GetDefaultMergeStyle() MergeStyle {
 	return MergeStyleMerge
 }
 
+// ActionsTokenPermissions represents the permissions of the tokens of the jobs
+type ActionsTokenPermissions string
+
+const (
+	ActionsTokenPermissionsWrite ActionsTokenPermissions = "write" // the default
+	ActionsTokenPermissionsRead  ActionsTokenPermissions = "read"
+)
+
+// ActionsForkPullRequestApproval represents which runs triggered by pull requests from forks need approval
+type ActionsForkPullRequestApproval string
+
+const (
+	// ActionsForkPullRequestApprovalFirstTime requires approval for the users who haven't been approved before, the default
+	ActionsForkPullRequestApprovalFirstTime ActionsForkPullRequestApproval = "first_time"
+	// ActionsForkPullRequestApprovalAlways requires approval for all the users who can't write to the repository
+	ActionsForkPullRequestApprovalAlways ActionsForkPullRequestApproval = "always"
+	// ActionsForkPullRequestApprovalNever requires approval only for the restricted users
+	ActionsForkPullRequestApprovalNever ActionsForkPullRequestApproval = "never"
+)
+
+// ActionsFeedRuns represents which completed runs are included in the RSS/Atom feed of the repository
+type ActionsFeedRuns string
+
+const (
+	ActionsFeedRunsNone    ActionsFeedRuns = "none" // the default
+	ActionsFeedRunsAll     ActionsFeedRuns = "all"
+	ActionsFeedRunsFailure ActionsFeedRuns = "failure"
+)
+
+// ActionsAnonymousRunAccess represents what anonymous users can see of the runs of a public repository
+type ActionsAnonymousRunAccess string
+
+const (
+	// ActionsAnonymousRunAccessFull shows the logs, artifacts and summaries of the runs, the default
+	ActionsAnonymousRunAccessFull ActionsAnonymousRunAccess = "full"
+	// ActionsAnonymousRunAccessMetadata only shows the titles and the statuses of the runs, jobs and steps
+	ActionsAnonymousRunAccessMetadata ActionsAnonymousRunAccess = "metadata"
+)
+
+// ActionsSupersedePullRequestRuns represents which runs of the previous head commit of a pull request are cancelled
+// when the pull request is synchronized, independent of the concurrency groups of the workflows
+type ActionsSupersedePullRequestRuns string
+
+const (
+	ActionsSupersedePullRequestRunsNone   ActionsSupersedePullRequestRuns = "none"   // the default
+	ActionsSupersedePullRequestRunsQueued ActionsSupersedePullRequestRuns = "queued" // the runs which haven't started
+	ActionsSupersedePullRequestRunsAll    ActionsSupersedePullRequestRuns = "all"    // the running runs too
+)
+
+// ActionsClosedPullRequestRuns represents which runs of a pull request are cancelled when it's closed without merging
+type ActionsClosedPullRequestRuns string
+
+const (
+	ActionsClosedPullRequestRunsNone   ActionsClosedPullRequestRuns = "none"   // the default
+	ActionsClosedPullRequestRunsQueued ActionsClosedPullRequestRuns = "queued" // the runs which haven't started
+	ActionsClosedPullRequestRunsAll    ActionsClosedPullRequestRuns = "all"    // the running runs too
+)
+
+// ActionsSubmoduleTokenScope represents which repositories of the submodules on the same instance the tokens of the jobs could read,
+// besides the repository of the run, so the recursive checkouts don't need extra secrets
+type ActionsSubmoduleTokenScope string
+
+const (
+	ActionsSubmoduleTokenScopeNone     ActionsSubmoduleTokenScope = "none"     // the default
+	ActionsSubmoduleTokenScopeOwner    ActionsSubmoduleTokenScope = "owner"    // the submodules owned by the owner of the repository
+	ActionsSubmoduleTokenScopeInstance ActionsSubmoduleTokenScope = "instance" // all the submodules on the instance
+)
+
 type ActionsConfig struct {
 	DisabledWorkflows []string
+	// DefaultTokenPermissions is the permissions of the tokens of the jobs which aren't triggered by pull requests from forks
+	DefaultTokenPermissions ActionsTokenPermissions        `json:",omitempty"`
+	ForkPullRequestApproval ActionsForkPullRequestApproval `json:",omitempty"`
+	// ArtifactRetentionDays overrides setting.Actions.ArtifactRetentionDays if it's positive
+	ArtifactRetentionDays int64 `json:",omitempty"`
+	// LogRetentionDays overrides setting.Actions.LogRetentionDays if it's positive
+	LogRetentionDays int64 `json:",omitempty"`
+	// RunRetentionDays overrides setting.Actions.RunRetentionDays if it's positive
+	RunRetentionDays int64 `json:",omitempty"`
+	// AllowedActions are the glob patterns of the actions the workflows could use, empty means all actions are allowed
+	AllowedActions []string `json:",omitempty"`
+	// ChatOpsCommands are the slash commands in the comments of pull requests which dispatch workflows
+	ChatOpsCommands []*ActionsChatOpsCommand `json:",omitempty"`
+	// StatusExcludedWorkflows are the workflows whose jobs don't publish commit statuses
+	StatusExcludedWorkflows []string `json:",omitempty"`
+	// DisabledJobs are the jobs of the workflows which are skipped in the new runs
+	DisabledJobs []*ActionsDisabledJob `json:",omitempty"`
+	// RunsOnOverrides replace or remap the runs-on labels of the jobs matching their patterns in the new runs
+	RunsOnOverrides []*ActionsRunsOnOverride `json:",omitempty"`
+	// FeedRuns is which completed runs are included in the RSS/Atom feed of the repository
+	FeedRuns ActionsFeedRuns `json:",omitempty"`
+	// FeedRunsDefaultBranchOnly only includes the runs of the default branch in the feed
+	FeedRunsDefaultBranchOnly bool `json:",omitempty"`
+	// AnonymousRunAccess is what anonymous users can see of the runs
+	AnonymousRunAccess ActionsAnonymousRunAccess `json:",omitempty"`
+	// SupersedePullRequestRuns is which runs of the previous head commit are cancelled when a pull request is synchronized
+	SupersedePullRequestRuns ActionsSupersedePullRequestRuns `json:",omitempty"`
+	// ClosedPullRequestRuns is which runs of a pull request are cancelled when it's closed without merging
+	ClosedPullRequestRuns ActionsClosedPullRequestRuns `json:",omitempty"`
+	// ClosedPullRequestRetentionDays deletes the runs of a pull request closed without merging with their logs and artifacts
+	// after the days since it's closed, if it's positive and the retentions don't delete them earlier
+	ClosedPullRequestRetentionDays int64 `json:",omitempty"`
+	// SubmoduleTokenScope is which repositories of the submodules declared by the commits of the runs the tokens of the jobs could read
+	SubmoduleTokenScope ActionsSubmoduleTokenScope `json:",omitempty"`
+	// BotIdentity overrides the bot identity of the owner, nil to use the one of the owner
+	BotIdentity *ActionsBotIdentity `json:",omitempty"`
+	// ReleaseAutomation creates the releases of the tags whose runs succeed, nil to disable it
+	ReleaseAutomation *ActionsReleaseAutomation `json:",omitempty"`
+	// PackageRetention removes the old versions of the packages published by the runs, nil to keep them
+	PackageRetention *ActionsPackageRetention `json:",omitempty"`
+	// BadgeTheme overrides the badge theme of the owner, nil to use the one of the owner
+	BadgeTheme *ActionsBadgeTheme `json:",omitempty"`
+	// BadgeLabels are the labels of the badges of the workflows instead of their names, by the file names of the workflows
+	BadgeLabels map[string]string `json:",omitempty"`
+	// FederationPeer pushes the statuses of the runs to the repository on a peer Gitea instance, nil to disable it
+	FederationPeer *ActionsFederationPeer `json:",omitempty"`
+	// FederationTokenHash is the SHA256 of the token which the peer instances push the statuses of their runs with,
+	// empty if the statuses of the peers aren't accepted, see VerifyFederationToken
+	FederationTokenHash string `json:",omitempty"`
+}
+
+// ActionsFederationPeer is the repository on a peer Gitea instance, like a mirror of the repository,
+// which shows the statuses of the runs of the repository as if they ran there
+type ActionsFederationPeer struct {
+	// URL is the URL of the repository on the peer instance, like "https://gitea.example.com/owner/repo"
+	URL string
+	// TokenEncrypted is the federation token accepted by the repository on the peer instance, encrypted by the secret key
+	TokenEncrypted string
+}
+
+// ActionsChatOpsCommand maps a slash command in the comments of pull requests to a workflow dispatch
+type ActionsChatOpsCommand struct {
+	// Name is the command without the slash, e.g. "deploy" for "/deploy staging"
+	Name string
+	// WorkflowID is the file name of the workflow to dispatch, it must be triggered by workflow_dispatch
+	WorkflowID string
+	// ArgsInput is the input of the workflow receiving the arguments of the command, they're ignored if it's empty
+	ArgsInput string `json:",omitempty"`
+	// RequireAdmin requires the commenter to be an administrator of the repository, instead of a writer of Actions
+	RequireAdmin bool `json:",omitempty"`
+}
+
+// ActionsDisabledJob is a job of a workflow disabled by the maintainers, it's skipped in the new runs without editing the workflow
+type ActionsDisabledJob struct {
+	// WorkflowID is the file name of the workflow
+	WorkflowID string
+	// Job is the id of the job, or the name of a job like "test (windows-latest)" to disable one of a matrix
+	Job string
+}
+
+// ActionsBotIdentity is the identity which the commits, comments and statuses made with the tokens of the jobs are attributed to,
+// instead of the global actions user. The actions user keeps its name, so only the display name, the email and the avatar change.
+type ActionsBotIdentity struct {
+	// Name is the display name, also the name of the commits
+	Name string
+	// Email is the email of the commits, the actions user keeps its email private if it's empty
+	Email string `json:",omitempty"`
+	// AvatarEmail is the email whose avatar is shown, like Gravatar, the Email is used if it's empty
+	AvatarEmail string `json:",omitempty"`
+}
+
+// ApplyTo returns a copy of the actions user with the identity, other users are returned as they are
+func (b *ActionsBotIdentity) ApplyTo(u *user_model.User) *user_model.User {
+	if b == nil || !u.IsActions() {
+		return u
+	}
+	bot := *u
+	bot.FullName = b.Name
+	if b.Email != "" {
+		bot.Email = b.Email
+		bot.AvatarEmail = b.Email
+		bot.KeepEmailPrivate = false
+	}
+	if b.AvatarEmail != "" {
+		bot.AvatarEmail = b.AvatarEmail
+	}
+	return &bot
+}
+
+// ActionsBadgeTheme is how the badges of the workflows are rendered, so the owners could brand them without a proxy service
+type ActionsBadgeTheme struct {
+	// Style is the style of the badges like "flat", the default style is used if it's empty
+	Style string `json:",omitempty"`
+	// LabelColor is the background color of the labels, like "#555"
+	LabelColor string `json:",omitempty"`
+	// Colors replace the colors of the statuses, by the names of the statuses like "success"
+	Colors map[string]string `json:",omitempty"`
+	// Logo is the base64 data URI of the SVG or PNG logo before the labels
+	Logo string `json:",omitempty"`
+}
+
+// ActionsReleaseAutomation creates a release when a run of the workflow for a tag succeeds, so the workflows don't script it
+type ActionsReleaseAutomation struct {
+	// WorkflowID is the file name of the workflow whose successful runs create the releases
+	WorkflowID string
+	// TagPattern is the glob pattern of the tags, like "v*", empty means all tags
+	TagPattern string `json:",omitempty"`
+	// Artifacts are the glob patterns of the names of the artifacts of the run attached to the release
+	Artifacts []string `json:",omitempty"`
+	// GenerateNotes generates the notes of the release from the pull requests merged since the previous tag
+	GenerateNotes bool `json:",omitempty"`
+	// Draft creates the releases as drafts, so they're reviewed before they're published
+	Draft bool `json:",omitempty"`
+}
+
+// MatchTag returns whether the automation creates the release of the tag
+func (a *ActionsReleaseAutomation) MatchTag(tagName string) bool {
+	if a.TagPattern == "" {
+		return true
+	}
+	g, err := glob.Compile(a.TagPattern)
+	return err == nil && g.Match(tagName)
+}
+
+// MatchArtifact returns whether the artifact is attached to the release
+func (a *ActionsReleaseAutomation) MatchArtifact(name string) bool {
+	for _, pattern := range a.Artifacts {
+		if g, err := glob.Compile(pattern); err == nil && g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionsPackageRetention removes the old package versions recorded as published by the runs of the repository by the cleanup task,
+// the versions matching none of the rules, and the ones not published by the runs, are kept
+type ActionsPackageRetention struct {
+	Rules []*ActionsPackageRetentionRule
+	// DryRun only logs the versions which would be removed, so the rules could be checked before they remove anything
+	DryRun bool `json:",omitempty"`
+}
+
+// ActionsPackageRetentionRule keeps the latest versions of each package published by the runs of each ref
+type ActionsPackageRetentionRule struct {
+	// Type is the type of the packages like "container", empty means all types
+	Type string `json:",omitempty"`
+	// PackagePattern is the glob pattern of the names of the packages, empty means all packages
+	PackagePattern string `json:",omitempty"`
+	// RefPattern is the glob pattern of the refs of the runs like "refs/heads/*", empty means all refs
+	RefPattern string `json:",omitempty"`
+	// KeepCount is how many latest versions of each package are kept for each ref
+	KeepCount int `json:",omitempty"`
+	// KeepDays keeps the versions published in the last days even if they're more than KeepCount, 0 means no versions are kept by age
+	KeepDays int `json:",omitempty"`
+}
+
+// Match returns whether the rule applies to the version of the package published by a run of the ref
+func (r *ActionsPackageRetentionRule) Match(packageType, packageName, ref string) bool {
+	if r.Type != "" && r.Type != packageType {
+		return false
+	}
+	match := func(pattern, s string) bool {
+		if pattern == "" {
+			return true
+		}
+		g, err := glob.Compile(pattern)
+		return err == nil && g.Match(s)
+	}
+	return match(r.PackagePattern, packageName) && match(r.RefPattern, ref)
+}
+
+// ActionsRunsOnOverride forces or remaps the runs-on labels of the jobs matching Job, e.g. to route all deploy jobs to locked-down runners
+type ActionsRunsOnOverride struct {
+	// Job is the glob pattern of the ids or names of the jobs, like "deploy-*"
+	Job string
+	// RunsOn replaces the runs-on labels of the jobs if it isn't empty
+	RunsOn []string `json:",omitempty"`
+	// Labels remaps the runs-on labels of the jobs, e.g. {"ubuntu-latest": "secure-ubuntu"}
+	Labels map[string]string `json:",omitempty"`
+}
+
+// Match returns whether the job matches the override by its id or its name
+func (o *ActionsRunsOnOverride) Match(jobID, jobName string) bool {
+	g, err := glob.Compile(o.Job)
+	if err != nil {
+		return false
+	}
+	return g.Match(jobID) || g.Match(jobName)
+}
+
+// Apply returns the runs-on labels overridden
+func (o *ActionsRunsOnOverride) Apply(runsOn []string) []string {
+	if len(o.RunsOn) > 0 {
+		return slices.Clone(o.RunsOn)
+	}
+	ret := make([]string, 0, len(runsOn))
+	for _, label := range runsOn {
+		if to, ok := o.Labels[label]; ok {
+			label = to
+		}
+		if !slices.Contains(ret, label) {
+			ret = append(ret, label)
+		}
+	}
+	return ret
+}
+
+// GetDefaultTokenPermissions returns the permissions of the tokens of the jobs, it defaults to write
+func (cfg *ActionsConfig) GetDefaultTokenPermissions() ActionsTokenPermissions {
+	if cfg.DefaultTokenPermissions == "" {
+		return ActionsTokenPermissionsWrite
+	}
+	return cfg.DefaultTokenPermissions
+}
+
+// GetForkPullRequestApproval returns which runs triggered by pull requests from forks need approval
+func (cfg *ActionsConfig) GetForkPullRequestApproval() ActionsForkPullRequestApproval {
+	if cfg.ForkPullRequestApproval == "" {
+		return ActionsForkPullRequestApprovalFirstTime
+	}
+	return cfg.ForkPullRequestApproval
+}
+
+// GetFeedRuns returns which completed runs are included in the RSS/Atom feed of the repository, it defaults to none
+func (cfg *ActionsConfig) GetFeedRuns() ActionsFeedRuns {
+	if cfg.FeedRuns == "" {
+		return ActionsFeedRunsNone
+	}
+	return cfg.FeedRuns
+}
+
+// GetAnonymousRunAccess returns what anonymous users can see of the runs, it defaults to full
+func (cfg *ActionsConfig) GetAnonymousRunAccess() ActionsAnonymousRunAccess {
+	if cfg.AnonymousRunAccess == "" {
+		return ActionsAnonymousRunAccessFull
+	}
+	return cfg.AnonymousRunAccess
+}
+
+// GetSupersedePullRequestRuns returns which runs of the previous head commit are cancelled when a pull request is synchronized,
+// it defaults to none
+func (cfg *ActionsConfig) GetSupersedePullRequestRuns() ActionsSupersedePullRequestRuns {
+	if cfg.SupersedePullRequestRuns == "" {
+		return ActionsSupersedePullRequestRunsNone
+	}
+	return cfg.SupersedePullRequestRuns
+}
+
+// GetClosedPullRequestRuns returns which runs of a pull request are cancelled when it's closed without merging, it defaults to none
+func (cfg *ActionsConfig) GetClosedPullRequestRuns() ActionsClosedPullRequestRuns {
+	if cfg.ClosedPullRequestRuns == "" {
+		return ActionsClosedPullRequestRunsNone
+	}
+	return cfg.ClosedPullRequestRuns
+}
+
+// GetSubmoduleTokenScope returns which repositories of the submodules the tokens of the jobs could read, it defaults to none
+func (cfg *ActionsConfig) GetSubmoduleTokenScope() ActionsSubmoduleTokenScope {
+	if cfg.SubmoduleTokenScope == "" {
+		return ActionsSubmoduleTokenScopeNone
+	}
+	return cfg.SubmoduleTokenScope
+}
+
+// GetBadgeLabel returns the label of the badge of the workflow, its name without the extension if it has no label
+func (cfg *ActionsConfig) GetBadgeLabel(workflowID string) string {
+	if label := cfg.BadgeLabels[workflowID]; label != "" {
+		return label
+	}
+	return strings.TrimSuffix(workflowID, path.Ext(workflowID))
+}
+
+// HashFederationToken returns the hash of the federation token stored in FederationTokenHash
+func HashFederationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyFederationToken returns whether the peer instances could push the statuses of their runs with the token
+func (cfg *ActionsConfig) VerifyFederationToken(token string) bool {
+	if cfg.FederationTokenHash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cfg.FederationTokenHash), []byte(HashFederationToken(token))) == 1
+}
+
+// GetArtifactRetentionDays returns the retention days of the artifacts of the repository
+func (cfg *ActionsConfig) GetArtifactRetentionDays() int64 {
+	if cfg.ArtifactRetentionDays > 0 {
+		return cfg.ArtifactRetentionDays
+	}
+	return setting.Actions.ArtifactRetentionDays
+}
+
+// IsActionAllowed returns whether the action referenced by `uses` of a step could be used.
+// Local actions are always allowed, the others are matched against AllowedActions without their refs,
+// e.g. "actions/*" allows "actions/checkout@v4" but not "actions/cache/save@v4".
+func (cfg *ActionsConfig) IsActionAllowed(uses string) bool {
+	if len(cfg.AllowedActions) == 0 || strings.HasPrefix(uses, "./") {
+		return true
+	}
+	name := uses
+	if !strings.HasPrefix(name, "docker://") {
+		name, _, _ = strings.Cut(name, "@")
+	}
+	for _, pattern := range cfg.AllowedActions {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			continue
+		}
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetChatOpsCommand returns the slash command with the name, or nil if it doesn't exist
+func (cfg *ActionsConfig) GetChatOpsCommand(name string) *ActionsChatOpsCommand {
+	for _, c := range cfg.ChatOpsCommands {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// IsWorkflowStatusExcluded returns whether the jobs of the workflow don't publish commit statuses
+func (cfg *ActionsConfig) IsWorkflowStatusExcluded(file string) bool {
+	return slices.Contains(cfg.StatusExcludedWorkflows, file)
+}
+
+// IsJobDisabled returns whether the job of the workflow is disabled by its id or its name
+func (cfg *ActionsConfig) IsJobDisabled(workflowID, jobID, jobName string) bool {
+	return slices.ContainsFunc(cfg.DisabledJobs, func(job *ActionsDisabledJob) bool {
+		return job.WorkflowID == workflowID && (job.Job == jobID || job.Job == jobName)
+	})
 }
 
 func (cfg *ActionsConfig) EnableWorkflow(file string) {
@@ -341,3 +773,43 @@ func UpdateRepoUnit(ctx context.Context, unit *RepoUnit) error {
 	_, err := db.GetEngine(ctx).ID(unit.ID).Update(unit)
 	return err
 }
+
+// GetActionsConfigsOverridingRetention returns the Actions configs of the repositories which override
+// the retention days of the logs or the runs, keyed by the ids of the repositories
+func GetActionsConfigsOverridingRetention(ctx context.Context) (map[int64]*ActionsConfig, error) {
+	var units []*RepoUnit
+	if err := db.GetEngine(ctx).
+		Where("`type` = ?", unit.TypeActions).
+		And(builder.Like{"config", `"LogRetentionDays"`}.Or(builder.Like{"config", `"RunRetentionDays"`})).
+		Find(&units); err != nil {
+		return nil, err
+	}
+
+	configs := make(map[int64]*ActionsConfig, len(units))
+	for _, u := range units {
+		cfg := u.ActionsConfig()
+		if cfg.LogRetentionDays > 0 || cfg.RunRetentionDays > 0 {
+			configs[u.RepoID] = cfg
+		}
+	}
+	return configs, nil
+}
+
+// GetActionsPackageRetentions returns the package retentions of the repositories by their IDs, see ActionsConfig.PackageRetention
+func GetActionsPackageRetentions(ctx context.Context) (map[int64]*ActionsPackageRetention, error) {
+	var units []*RepoUnit
+	if err := db.GetEngine(ctx).
+		Where("`type` = ?", unit.TypeActions).
+		And(builder.Like{"config", `"PackageRetention"`}).
+		Find(&units); err != nil {
+		return nil, err
+	}
+
+	retentions := make(map[int64]*ActionsPackageRetention, len(units))
+	for _, u := range units {
+		if retention := u.ActionsConfig().PackageRetention; retention != nil && len(retention.Rules) > 0 {
+			retentions[u.RepoID] = retention
+		}
+	}
+	return retentions, nil
+}
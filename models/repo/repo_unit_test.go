@@ -28,3 +28,82 @@ func TestActionsConfig(t *testing.T) {
 	cfg.DisableWorkflow("test3.yaml")
 	assert.EqualValues(t, "test1.yaml,test2.yaml,test3.yaml", cfg.ToString())
 }
+
+func TestActionsConfigIsActionAllowed(t *testing.T) {
+	cfg := &ActionsConfig{}
+	assert.True(t, cfg.IsActionAllowed("someone/something@v1"))
+
+	cfg.AllowedActions = []string{"actions/*", "docker://alpine:*", "my-org/tools/**"}
+	assert.True(t, cfg.IsActionAllowed("./.gitea/actions/build"))
+	assert.True(t, cfg.IsActionAllowed("actions/checkout@v4"))
+	assert.False(t, cfg.IsActionAllowed("actions/cache/save@v4"))
+	assert.True(t, cfg.IsActionAllowed("docker://alpine:3.19"))
+	assert.False(t, cfg.IsActionAllowed("docker://ubuntu:22.04"))
+	assert.True(t, cfg.IsActionAllowed("my-org/tools/lint@main"))
+	assert.False(t, cfg.IsActionAllowed("someone/something@v1"))
+}
+
+func TestActionsConfigIsJobDisabled(t *testing.T) {
+	cfg := &ActionsConfig{DisabledJobs: []*ActionsDisabledJob{
+		{WorkflowID: "test.yaml", Job: "build"},
+		{WorkflowID: "test.yaml", Job: "test (windows)"},
+	}}
+	assert.True(t, cfg.IsJobDisabled("test.yaml", "build", "Build"))
+	assert.True(t, cfg.IsJobDisabled("test.yaml", "test", "test (windows)"))
+	assert.False(t, cfg.IsJobDisabled("test.yaml", "test", "test (linux)"))
+	assert.False(t, cfg.IsJobDisabled("other.yaml", "build", "build"))
+}
+
+func TestActionsRunsOnOverride(t *testing.T) {
+	o := &ActionsRunsOnOverride{Job: "deploy-*", RunsOn: []string{"secure"}}
+	assert.True(t, o.Match("deploy-prod", "Deploy to production"))
+	assert.True(t, o.Match("release", "deploy-release"))
+	assert.False(t, o.Match("build", "build"))
+	assert.Equal(t, []string{"secure"}, o.Apply([]string{"ubuntu-latest", "x64"}))
+
+	o = &ActionsRunsOnOverride{Job: "*", Labels: map[string]string{"ubuntu-latest": "secure-ubuntu", "x64": "secure-ubuntu"}}
+	assert.Equal(t, []string{"secure-ubuntu", "arm64"}, o.Apply([]string{"ubuntu-latest", "x64", "arm64"}))
+}
+
+func TestActionsReleaseAutomation(t *testing.T) {
+	a := &ActionsReleaseAutomation{WorkflowID: "release.yaml", Artifacts: []string{"dist-*", "checksums"}}
+	assert.True(t, a.MatchTag("v1.0.0"))
+	assert.True(t, a.MatchArtifact("dist-linux"))
+	assert.True(t, a.MatchArtifact("checksums"))
+	assert.False(t, a.MatchArtifact("coverage"))
+
+	a.TagPattern = "v*"
+	assert.True(t, a.MatchTag("v1.0.0"))
+	assert.False(t, a.MatchTag("nightly"))
+}
+
+func TestActionsPackageRetentionRule(t *testing.T) {
+	r := &ActionsPackageRetentionRule{KeepCount: 10}
+	assert.True(t, r.Match("container", "app", "refs/heads/main"))
+
+	r = &ActionsPackageRetentionRule{Type: "container", PackagePattern: "app-*", RefPattern: "refs/heads/*"}
+	assert.True(t, r.Match("container", "app-web", "refs/heads/feature"))
+	assert.False(t, r.Match("npm", "app-web", "refs/heads/feature"))
+	assert.False(t, r.Match("container", "tool", "refs/heads/feature"))
+	assert.False(t, r.Match("container", "app-web", "refs/tags/v1.0.0"))
+}
+
+func TestActionsConfigVerifyFederationToken(t *testing.T) {
+	cfg := &ActionsConfig{}
+	assert.False(t, cfg.VerifyFederationToken(""))
+	assert.False(t, cfg.VerifyFederationToken("token"))
+
+	cfg.FederationTokenHash = HashFederationToken("token")
+	assert.True(t, cfg.VerifyFederationToken("token"))
+	assert.False(t, cfg.VerifyFederationToken("other"))
+	assert.False(t, cfg.VerifyFederationToken(""))
+}
+
+func TestActionsConfigGetBadgeLabel(t *testing.T) {
+	cfg := &ActionsConfig{}
+	assert.Equal(t, "build", cfg.GetBadgeLabel("build.yaml"))
+
+	cfg.BadgeLabels = map[string]string{"build.yaml": "CI"}
+	assert.Equal(t, "CI", cfg.GetBadgeLabel("build.yaml"))
+	assert.Equal(t, "release", cfg.GetBadgeLabel("release.yml"))
+}
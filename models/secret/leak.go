@@ -0,0 +1,58 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package secret
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// SecretLeak records a secret whose value was found verbatim in the logs of a task,
+// it's kept after the secret is updated or deleted, as a security event.
+type SecretLeak struct {
+	ID       int64
+	SecretID int64              `xorm:"UNIQUE(secret_task) NOT NULL"`
+	TaskID   int64              `xorm:"UNIQUE(secret_task) NOT NULL"`
+	Name     string             `xorm:"NOT NULL"`                 // the name of the secret, kept if the secret is deleted
+	OwnerID  int64              `xorm:"NOT NULL DEFAULT 0"`       // the owner of the secret
+	RepoID   int64              `xorm:"INDEX NOT NULL DEFAULT 0"` // the repository of the run whose logs leaked the secret
+	RunID    int64              `xorm:"NOT NULL DEFAULT 0"`
+	Created  timeutil.TimeStamp `xorm:"created NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(SecretLeak))
+}
+
+// RecordSecretLeak records the secret leaked by the logs of the task and flags it for rotation,
+// it returns false if the leak has been recorded for the task.
+func RecordSecretLeak(ctx context.Context, secret *Secret, task *actions_model.ActionTask) (bool, error) {
+	var recorded bool
+	err := db.WithTx(ctx, func(ctx context.Context) error {
+		exist, err := db.GetEngine(ctx).Exist(&SecretLeak{SecretID: secret.ID, TaskID: task.ID})
+		if err != nil || exist {
+			return err
+		}
+		if err := db.Insert(ctx, &SecretLeak{
+			SecretID: secret.ID,
+			TaskID:   task.ID,
+			Name:     secret.Name,
+			OwnerID:  secret.OwnerID,
+			RepoID:   task.RepoID,
+			RunID:    task.Job.RunID,
+		}); err != nil {
+			return err
+		}
+		secret.LeakedUnix = timeutil.TimeStampNow()
+		if _, err := db.GetEngine(ctx).ID(secret.ID).Cols("leaked_unix").Update(secret); err != nil {
+			return err
+		}
+		recorded = true
+		return nil
+	})
+	return recorded, err
+}
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
 	secret_module "code.gitea.io/gitea/modules/secret"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -23,12 +24,14 @@ import (
 
 // Secret represents a secret
 type Secret struct {
-	ID          int64
-	OwnerID     int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
-	RepoID      int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
-	Name        string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
-	Data        string             `xorm:"LONGTEXT"` // encrypted data
-	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	ID                int64
+	OwnerID           int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
+	RepoID            int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+	Name              string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+	Data              string             `xorm:"LONGTEXT"`               // encrypted data
+	ProtectedRefsOnly bool               `xorm:"NOT NULL DEFAULT false"` // only for the runs triggered by protected branches or tags
+	LeakedUnix        timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`     // when the value was found in the logs, it needs rotation until the data is updated
+	CreatedUnix       timeutil.TimeStamp `xorm:"created NOT NULL"`
 }
 
 // ErrSecretNotFound represents a "secret not found" error.
@@ -45,7 +48,7 @@ func (err ErrSecretNotFound) Unwrap() error {
 }
 
 // InsertEncryptedSecret Creates, encrypts, and validates a new secret with yet unencrypted data and insert into database
-func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, data string) (*Secret, error) {
+func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, data string, protectedRefsOnly bool) (*Secret, error) {
 	encrypted, err := secret_module.EncryptSecret(setting.SecretKey, data)
 	if err != nil {
 		return nil, err
@@ -55,6 +58,8 @@ func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, dat
 		RepoID:  repoID,
 		Name:    strings.ToUpper(name),
 		Data:    encrypted,
+
+		ProtectedRefsOnly: protectedRefsOnly,
 	}
 	if err := secret.Validate(); err != nil {
 		return secret, err
@@ -66,6 +71,11 @@ func init() {
 	db.RegisterModel(new(Secret))
 }
 
+// IsLeaked returns whether the value has been found in the logs and the secret needs rotation
+func (s *Secret) IsLeaked() bool {
+	return s.LeakedUnix > 0
+}
+
 func (s *Secret) Validate() error {
 	if s.OwnerID == 0 && s.RepoID == 0 {
 		return errors.New("the secret is not bound to any scope")
@@ -99,8 +109,9 @@ func (opts FindSecretsOptions) ToConds() builder.Cond {
 	return cond
 }
 
-// UpdateSecret changes org or user reop secret.
-func UpdateSecret(ctx context.Context, secretID int64, data string) error {
+// UpdateSecret changes org or user reop secret, the secret is no longer leaked since it has been rotated.
+// The restriction to protected refs is only changed if protectedRefsOnly has a value.
+func UpdateSecret(ctx context.Context, secretID int64, data string, protectedRefsOnly optional.Option[bool]) error {
 	encrypted, err := secret_module.EncryptSecret(setting.SecretKey, data)
 	if err != nil {
 		return err
@@ -109,39 +120,50 @@ func UpdateSecret(ctx context.Context, secretID int64, data string) error {
 	s := &Secret{
 		Data: encrypted,
 	}
-	affected, err := db.GetEngine(ctx).ID(secretID).Cols("data").Update(s)
+	cols := []string{"data", "leaked_unix"}
+	if protectedRefsOnly.Has() {
+		s.ProtectedRefsOnly = protectedRefsOnly.Value()
+		cols = append(cols, "protected_refs_only")
+	}
+	affected, err := db.GetEngine(ctx).ID(secretID).Cols(cols...).Update(s)
 	if affected != 1 {
 		return ErrSecretNotFound{}
 	}
 	return err
 }
 
-func GetSecretsOfTask(ctx context.Context, task *actions_model.ActionTask) (map[string]string, error) {
-	secrets := map[string]string{}
+// GetSecretsOfTask returns the secrets which can be provided to the task,
+// the secrets restricted to protected refs are only provided if isRefProtected is true.
+func GetSecretsOfTask(ctx context.Context, task *actions_model.ActionTask, isRefProtected bool) (map[string]string, error) {
+	secrets, err := GetSecretsOfRun(ctx, task.Job.Run, isRefProtected)
+	if err != nil {
+		return nil, err
+	}
 
 	secrets["GITHUB_TOKEN"] = task.Token
 	secrets["GITEA_TOKEN"] = task.Token
 
-	if task.Job.Run.IsForkPullRequest && task.Job.Run.TriggerEvent != actions_module.GithubEventPullRequestTarget {
+	return secrets, nil
+}
+
+// GetSecretsOfRun returns the secrets defined by the owner and the repository which can be provided to the jobs of the run,
+// like GetSecretsOfTask but without the tokens of the tasks.
+func GetSecretsOfRun(ctx context.Context, run *actions_model.ActionRun, isRefProtected bool) (map[string]string, error) {
+	secrets := map[string]string{}
+
+	if run.IsForkPullRequest && run.TriggerEvent != actions_module.GithubEventPullRequestTarget {
 		// ignore secrets for fork pull request, except GITHUB_TOKEN and GITEA_TOKEN which are automatically generated.
 		// for the tasks triggered by pull_request_target event, they could access the secrets because they will run in the context of the base branch
 		// see the documentation: https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#pull_request_target
 		return secrets, nil
 	}
 
-	ownerSecrets, err := db.Find[Secret](ctx, FindSecretsOptions{OwnerID: task.Job.Run.Repo.OwnerID})
+	available, err := FindSecretsOfRun(ctx, run, isRefProtected)
 	if err != nil {
-		log.Error("find secrets of owner %v: %v", task.Job.Run.Repo.OwnerID, err)
 		return nil, err
 	}
-	repoSecrets, err := db.Find[Secret](ctx, FindSecretsOptions{RepoID: task.Job.Run.RepoID})
-	if err != nil {
-		log.Error("find secrets of repo %v: %v", task.Job.Run.RepoID, err)
-		return nil, err
-	}
-
-	for _, secret := range append(ownerSecrets, repoSecrets...) {
-		v, err := secret_module.DecryptSecret(setting.SecretKey, secret.Data)
+	for _, secret := range available {
+		v, err := secret.Decrypt()
 		if err != nil {
 			log.Error("decrypt secret %v %q: %v", secret.ID, secret.Name, err)
 			return nil, err
@@ -151,3 +173,36 @@ func GetSecretsOfTask(ctx context.Context, task *actions_model.ActionTask) (map[
 
 	return secrets, nil
 }
+
+// FindSecretsOfRun returns the secrets of the owner and the repository which can be provided to the jobs of the run,
+// the repository ones come later so they take precedence over the owner ones with the same names.
+// It doesn't check whether the run could access the secrets, see GetSecretsOfRun.
+func FindSecretsOfRun(ctx context.Context, run *actions_model.ActionRun, isRefProtected bool) ([]*Secret, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	ownerSecrets, err := db.Find[Secret](ctx, FindSecretsOptions{OwnerID: run.Repo.OwnerID})
+	if err != nil {
+		log.Error("find secrets of owner %v: %v", run.Repo.OwnerID, err)
+		return nil, err
+	}
+	repoSecrets, err := db.Find[Secret](ctx, FindSecretsOptions{RepoID: run.RepoID})
+	if err != nil {
+		log.Error("find secrets of repo %v: %v", run.RepoID, err)
+		return nil, err
+	}
+
+	secrets := make([]*Secret, 0, len(ownerSecrets)+len(repoSecrets))
+	for _, secret := range append(ownerSecrets, repoSecrets...) {
+		if secret.ProtectedRefsOnly && !isRefProtected {
+			continue
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// Decrypt returns the value of the secret
+func (s *Secret) Decrypt() (string, error) {
+	return secret_module.DecryptSecret(setting.SecretKey, s.Data)
+}
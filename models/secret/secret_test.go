@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package secret
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/optional"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSecretsOfTask(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := InsertEncryptedSecret(db.DefaultContext, 2, 0, "OWNER_SECRET", "owner", false)
+	require.NoError(t, err)
+	_, err = InsertEncryptedSecret(db.DefaultContext, 0, 1, "REPO_SECRET", "repo", false)
+	require.NoError(t, err)
+	_, err = InsertEncryptedSecret(db.DefaultContext, 0, 1, "DEPLOY_KEY", "deploy", true)
+	require.NoError(t, err)
+
+	task := &actions_model.ActionTask{
+		Token: "token",
+		Job: &actions_model.ActionRunJob{
+			Run: &actions_model.ActionRun{
+				RepoID: 1,
+				Repo:   &repo_model.Repository{ID: 1, OwnerID: 2},
+				Ref:    "refs/heads/feature",
+			},
+		},
+	}
+
+	secrets, err := GetSecretsOfTask(db.DefaultContext, task, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"GITHUB_TOKEN": "token",
+		"GITEA_TOKEN":  "token",
+		"OWNER_SECRET": "owner",
+		"REPO_SECRET":  "repo",
+	}, secrets)
+
+	secrets, err = GetSecretsOfTask(db.DefaultContext, task, true)
+	require.NoError(t, err)
+	assert.Equal(t, "deploy", secrets["DEPLOY_KEY"])
+
+	task.Job.Run.IsForkPullRequest = true
+	secrets, err = GetSecretsOfTask(db.DefaultContext, task, true)
+	require.NoError(t, err)
+	assert.Len(t, secrets, 2)
+}
+
+func TestUpdateSecretKeepsRestriction(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	s, err := InsertEncryptedSecret(db.DefaultContext, 0, 1, "ROTATED_KEY", "deploy", true)
+	require.NoError(t, err)
+
+	require.NoError(t, UpdateSecret(db.DefaultContext, s.ID, "rotated", optional.None[bool]()))
+	unittest.AssertExistsAndLoadBean(t, &Secret{ID: s.ID, ProtectedRefsOnly: true})
+
+	require.NoError(t, UpdateSecret(db.DefaultContext, s.ID, "rotated", optional.Some(false)))
+	s = unittest.AssertExistsAndLoadBean(t, &Secret{ID: s.ID})
+	assert.False(t, s.ProtectedRefsOnly)
+}
@@ -63,6 +63,12 @@ func (u Type) String() string {
 	return fmt.Sprintf("Unknown Type %d", u)
 }
 
+// CanTeamAdminister returns whether a team without the admin access to the repositories could administer the unit,
+// only the Actions could be administered alone, see access.Permission.CanManageActions
+func (u Type) CanTeamAdminister() bool {
+	return u == TypeActions
+}
+
 func (u Type) LogString() string {
 	return fmt.Sprintf("<UnitType:%d:%s>", u, u.String())
 }
@@ -357,6 +363,11 @@ func MinUnitAccessMode(unitsMap map[Type]perm.AccessMode) perm.AccessMode {
 			continue
 		}
 
+		// administering a unit alone doesn't make the team administer the repositories
+		if t.CanTeamAdminister() {
+			mode = min(mode, perm.AccessModeWrite)
+		}
+
 		// get the minial permission great than AccessModeNone except all are AccessModeNone
 		if mode > perm.AccessModeNone && (res == perm.AccessModeNone || mode < res) {
 			res = mode
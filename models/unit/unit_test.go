@@ -6,6 +6,7 @@ package unit
 import (
 	"testing"
 
+	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
@@ -93,3 +94,18 @@ func TestLoadUnitConfig(t *testing.T) {
 		assert.Equal(t, []Type{TypeReleases}, DefaultForkRepoUnits)
 	})
 }
+
+func TestMinUnitAccessMode(t *testing.T) {
+	assert.Equal(t, perm.AccessModeWrite, MinUnitAccessMode(map[Type]perm.AccessMode{
+		TypeCode:            perm.AccessModeWrite,
+		TypeExternalTracker: perm.AccessModeRead,
+	}))
+	assert.Equal(t, perm.AccessModeRead, MinUnitAccessMode(map[Type]perm.AccessMode{
+		TypeCode:    perm.AccessModeRead,
+		TypeActions: perm.AccessModeAdmin,
+	}))
+	// administering the Actions alone doesn't administer the repositories
+	assert.Equal(t, perm.AccessModeWrite, MinUnitAccessMode(map[Type]perm.AccessMode{
+		TypeActions: perm.AccessModeAdmin,
+	}))
+}
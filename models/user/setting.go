@@ -112,6 +112,15 @@ func GetUserAllSettings(ctx context.Context, uid int64) (map[string]*Setting, er
 	return settingsMap, nil
 }
 
+// FindSettingsByKey returns the settings of the key of all users
+func FindSettingsByKey(ctx context.Context, key string) ([]*Setting, error) {
+	settings := make([]*Setting, 0, 10)
+	return settings, db.GetEngine(ctx).
+		Where("setting_key=?", key).
+		OrderBy("user_id").
+		Find(&settings)
+}
+
 func validateUserSettingKey(key string) error {
 	if len(key) == 0 {
 		return fmt.Errorf("setting key must be set")
@@ -10,6 +10,24 @@ const (
 	SettingsKeyDiffWhitespaceBehavior = "diff.whitespace_behaviour"
 	// SettingsKeyShowOutdatedComments is the setting key wether or not to show outdated comments in PRs
 	SettingsKeyShowOutdatedComments = "comment_code.show_outdated"
+	// SettingsKeyActionsRunnerSharingPolicy is the setting key for which runners the repositories of the owner could use
+	SettingsKeyActionsRunnerSharingPolicy = "actions.runner_sharing_policy"
+	// SettingsKeyActionsRequirePinnedActions is the setting key for whether the workflows of the owner must pin third-party actions to full commit SHAs
+	SettingsKeyActionsRequirePinnedActions = "actions.require_pinned_actions"
+	// SettingsKeyActionsRunsOnOverrides is the setting key for the runs-on overrides of the jobs of the repositories of the owner
+	SettingsKeyActionsRunsOnOverrides = "actions.runs_on_overrides"
+	// SettingsKeyActionsBotIdentity is the setting key for the bot identity of the jobs of the repositories of the owner
+	SettingsKeyActionsBotIdentity = "actions.bot_identity"
+	// SettingsKeyActionsLicensePolicy is the setting key for the licenses the dependencies of the repositories of the owner could have
+	SettingsKeyActionsLicensePolicy = "actions.license_policy"
+	// SettingsKeyActionsEgressAllowlist is the setting key for the domains the jobs of the repositories of the owner could connect to
+	SettingsKeyActionsEgressAllowlist = "actions.egress_allowlist"
+	// SettingsKeyActionsFailureDigest is the setting key for how often the user receives the digest of the failed and flaky workflows
+	SettingsKeyActionsFailureDigest = "actions.failure_digest"
+	// SettingsKeyActionsFailureDigestSent is the setting key for when the last digest of the failed and flaky workflows was sent to the user
+	SettingsKeyActionsFailureDigestSent = "actions.failure_digest_sent"
+	// SettingsKeyActionsBadgeTheme is the setting key for the theme of the badges of the workflows of the repositories of the owner
+	SettingsKeyActionsBadgeTheme = "actions.badge_theme"
 	// UserActivityPubPrivPem is user's private key
 	UserActivityPubPrivPem = "activitypub.priv_pem"
 	// UserActivityPubPubPem is user's public key
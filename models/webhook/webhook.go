@@ -299,6 +299,12 @@ func (w *Webhook) HasPackageEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Package)
 }
 
+// HasWorkflowRunEvent returns if hook enabled workflow run event.
+func (w *Webhook) HasWorkflowRunEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.WorkflowRun)
+}
+
 // HasPullRequestReviewRequestEvent returns true if hook enabled pull request review request event.
 func (w *Webhook) HasPullRequestReviewRequestEvent() bool {
 	return w.SendEverything ||
@@ -337,6 +343,7 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasReleaseEvent, webhook_module.HookEventRelease},
 		{w.HasPackageEvent, webhook_module.HookEventPackage},
 		{w.HasPullRequestReviewRequestEvent, webhook_module.HookEventPullRequestReviewRequest},
+		{w.HasWorkflowRunEvent, webhook_module.HookEventWorkflowRun},
 	}
 }
 
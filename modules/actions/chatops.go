@@ -0,0 +1,57 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+)
+
+// ChatOpsCommand is a slash command in a comment, e.g. "/deploy staging"
+type ChatOpsCommand struct {
+	Name string
+	Args string
+}
+
+var (
+	chatOpsCommandNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	chatOpsCommandLinePattern = regexp.MustCompile(`^/([a-z0-9][a-z0-9_-]*)(?:[ \t]+(.*))?$`)
+)
+
+// maxChatOpsCommandsPerComment limits the workflows dispatched by a single comment
+const maxChatOpsCommandsPerComment = 10
+
+// IsValidChatOpsCommandName returns whether the name could be used as a slash command, e.g. "retest"
+func IsValidChatOpsCommandName(name string) bool {
+	return len(name) <= 64 && chatOpsCommandNamePattern.MatchString(name)
+}
+
+// ParseChatOpsCommands returns the slash commands starting the lines of the comment, except those in code blocks or quotes.
+// A command is only returned once even if it's repeated.
+func ParseChatOpsCommands(content string) []*ChatOpsCommand {
+	var commands []*ChatOpsCommand
+	seen := make(container.Set[string])
+	inCodeBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		m := chatOpsCommandLinePattern.FindStringSubmatch(line)
+		if m == nil || !seen.Add(m[1]) {
+			continue
+		}
+		commands = append(commands, &ChatOpsCommand{Name: m[1], Args: strings.TrimSpace(m[2])})
+		if len(commands) == maxChatOpsCommandsPerComment {
+			break
+		}
+	}
+	return commands
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChatOpsCommands(t *testing.T) {
+	assert.Equal(t, []*ChatOpsCommand{
+		{Name: "retest"},
+		{Name: "deploy", Args: "staging --force"},
+	}, ParseChatOpsCommands("LGTM\n/retest\r\n  /deploy   staging --force  \n/retest again\n> /quoted\n"))
+
+	assert.Empty(t, ParseChatOpsCommands("please /retest it"))
+	assert.Empty(t, ParseChatOpsCommands("/Retest"))
+	assert.Empty(t, ParseChatOpsCommands("/"))
+	assert.Empty(t, ParseChatOpsCommands("/path/to/file"))
+	assert.Empty(t, ParseChatOpsCommands("```\n/retest\n```"))
+	assert.Equal(t, []*ChatOpsCommand{{Name: "retest"}}, ParseChatOpsCommands("~~~\n/deploy\n~~~\n/retest"))
+}
+
+func TestIsValidChatOpsCommandName(t *testing.T) {
+	assert.True(t, IsValidChatOpsCommandName("retest"))
+	assert.True(t, IsValidChatOpsCommandName("deploy-prod_2"))
+	assert.False(t, IsValidChatOpsCommandName(""))
+	assert.False(t, IsValidChatOpsCommandName("/retest"))
+	assert.False(t, IsValidChatOpsCommandName("Retest"))
+	assert.False(t, IsValidChatOpsCommandName("-retest"))
+	assert.False(t, IsValidChatOpsCommandName("re test"))
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MaxCheckoutDepth is the deepest history the workflows could ask the runners to fetch, deeper ones should fetch all
+	MaxCheckoutDepth = 10000
+	// maxCheckoutSparsePatterns is how many sparse checkout patterns a workflow could declare
+	maxCheckoutSparsePatterns = 100
+)
+
+// checkoutFilterPattern matches the partial clone filters which are useful for CI, see `git help rev-list`
+var checkoutFilterPattern = regexp.MustCompile(`^(?:blob:none|tree:0|blob:limit=\d+[kmg]?)$`)
+
+// WorkflowCheckout are the hints of how the runners check out the repository for the jobs of a workflow.
+// They are declared in a "checkout" block of the leading comments of the workflow file,
+// so the workflow is still valid for other platforms, e.g.
+//
+//	# checkout:
+//	#   depth: 1
+//	#   sparse: [services/api, go.mod]
+//	#   filter: blob:none
+type WorkflowCheckout struct {
+	// Depth is how many commits of the history to fetch, 0 for all
+	Depth int
+	// Sparse are the directories and files to check out, empty for all
+	Sparse []string
+	// Filter is the partial clone filter of the objects to fetch, like "blob:none"
+	Filter string
+}
+
+// ParseWorkflowCheckout parses and validates the checkout hints declared in the leading comments of the workflow content,
+// it returns nil if there is no checkout block.
+func ParseWorkflowCheckout(content []byte) (*WorkflowCheckout, error) {
+	block, ok, err := leadingCommentBlock(content, "checkout")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var raw struct {
+		Depth  int       `yaml:"depth"`
+		Sparse yaml.Node `yaml:"sparse"`
+		Filter string    `yaml:"filter"`
+	}
+	if err := yaml.Unmarshal(block, &raw); err != nil {
+		return nil, fmt.Errorf("invalid checkout block: %w", err)
+	}
+
+	checkout := &WorkflowCheckout{Depth: raw.Depth, Filter: strings.TrimSpace(raw.Filter)}
+	if checkout.Depth < 0 || checkout.Depth > MaxCheckoutDepth {
+		return nil, fmt.Errorf("invalid checkout depth %d, it should be between 0 and %d", checkout.Depth, MaxCheckoutDepth)
+	}
+	if err := ValidateCheckoutFilter(checkout.Filter); err != nil {
+		return nil, err
+	}
+	if checkout.Sparse, err = parseSparsePatterns(&raw.Sparse); err != nil {
+		return nil, err
+	}
+	return checkout, nil
+}
+
+// ValidateCheckoutFilter returns an error if the partial clone filter isn't supported, an empty filter fetches all the objects
+func ValidateCheckoutFilter(filter string) error {
+	if filter != "" && !checkoutFilterPattern.MatchString(filter) {
+		return fmt.Errorf("unsupported checkout filter %q, it should be blob:none, tree:0 or blob:limit=<size>", filter)
+	}
+	return nil
+}
+
+// parseSparsePatterns accepts both a sequence and a comma separated string of the paths relative to the root of the repository
+func parseSparsePatterns(node *yaml.Node) ([]string, error) {
+	var patterns []string
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.ScalarNode:
+		patterns = strings.Split(node.Value, ",")
+	case yaml.SequenceNode:
+		if err := node.Decode(&patterns); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("expect a list of sparse checkout paths at line %d", node.Line)
+	}
+
+	ret := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		cleaned := path.Clean(strings.TrimSuffix(pattern, "/"))
+		if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return nil, fmt.Errorf("invalid sparse checkout path %q, it should be relative to the root of the repository", pattern)
+		}
+		ret = append(ret, cleaned)
+	}
+	if len(ret) > maxCheckoutSparsePatterns {
+		return nil, fmt.Errorf("too many sparse checkout paths, at most %d are allowed", maxCheckoutSparsePatterns)
+	}
+	return ret, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkflowCheckout(t *testing.T) {
+	checkout, err := ParseWorkflowCheckout([]byte(`# Build the API
+#
+# requires:
+#   secrets: [NPM_TOKEN]
+# checkout:
+#   depth: 1
+#   sparse: [services/api/, go.mod]
+#   filter: blob:none
+name: api
+on: push
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &WorkflowCheckout{Depth: 1, Sparse: []string{"services/api", "go.mod"}, Filter: "blob:none"}, checkout)
+
+	// the other blocks are still parsed
+	requirements, err := ParseWorkflowRequirements([]byte(`# checkout:
+#   depth: 1
+# requires:
+#   secrets: NPM_TOKEN
+on: push
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NPM_TOKEN"}, requirements.Secrets)
+
+	checkout, err = ParseWorkflowCheckout([]byte("on: push\n"))
+	require.NoError(t, err)
+	assert.Nil(t, checkout)
+
+	for _, block := range []string{
+		"#   depth: -1",
+		"#   filter: sparse:oid=abc",
+		"#   sparse: ../other",
+		"#   sparse: [/etc]",
+		"#   sparse: {a: b}",
+	} {
+		_, err = ParseWorkflowCheckout([]byte("# checkout:\n" + block + "\non: push\n"))
+		assert.Error(t, err, block)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// maxComponentLength is the length of the component columns of runs and jobs
+const maxComponentLength = 255
+
+// ParseWorkflowComponent returns the component of a monorepo which the runs of the workflow belong to, it's empty if there is none.
+// The component is declared by a "component" line of the leading comments of the workflow file, so the workflow is still valid for other platforms, e.g.
+//
+//	# component: services/api
+//
+// Otherwise it's the common directory of the path filters of the trigger event, e.g. "services/api" for "services/api/**" and "services/api/go.mod".
+func ParseWorkflowComponent(content []byte, event *jobparser.Event) string {
+	component := parseDeclaredComponent(content)
+	if component == "" && event != nil {
+		component = componentFromPaths(event.Acts()["paths"])
+	}
+	component, _ = util.SplitStringAtByteN(component, maxComponentLength)
+	return component
+}
+
+func parseDeclaredComponent(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		if name, ok := strings.CutPrefix(strings.TrimSpace(strings.TrimPrefix(line, "#")), "component:"); ok {
+			return strings.Trim(strings.TrimSpace(name), `"'`)
+		}
+	}
+	return ""
+}
+
+// componentFromPaths returns the deepest directory containing all the paths matched by the path filters,
+// the negated filters are ignored since they only narrow the matched paths
+func componentFromPaths(patterns []string) string {
+	var common []string
+	matched := false
+	for _, pattern := range patterns {
+		if pattern == "" || strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		// the directory before the first wildcard, or the parent directory of a file
+		dir := pattern
+		if i := strings.IndexAny(pattern, "*?[{"); i >= 0 {
+			dir = pattern[:i]
+			if !strings.HasSuffix(dir, "/") {
+				dir = path.Dir(dir)
+			}
+		} else {
+			dir = path.Dir(pattern)
+		}
+		dir = strings.Trim(path.Clean("/"+dir), "/")
+		var parts []string
+		if dir != "" {
+			parts = strings.Split(dir, "/")
+		}
+
+		if !matched {
+			common, matched = parts, true
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	return strings.Join(common, "/")
+}
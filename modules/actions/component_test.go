@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkflowComponent(t *testing.T) {
+	content := []byte(`# Build the API
+#
+# component: services/api
+name: api
+on:
+  push:
+    paths: ["services/web/**"]
+`)
+	wf, err := model.ReadWorkflow(bytes.NewReader(content))
+	require.NoError(t, err)
+	events, err := jobparser.ParseRawOn(&wf.RawOn)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// the declared component takes precedence over the path filters
+	assert.Equal(t, "services/api", ParseWorkflowComponent(content, events[0]))
+	assert.Equal(t, "services/web", ParseWorkflowComponent([]byte("name: web\n"), events[0]))
+	assert.Empty(t, ParseWorkflowComponent([]byte("name: web\n# component: web\n"), nil))
+}
+
+func TestComponentFromPaths(t *testing.T) {
+	cases := []struct {
+		paths    []string
+		expected string
+	}{
+		{nil, ""},
+		{[]string{"services/api/**"}, "services/api"},
+		{[]string{"services/api/**", "services/api/go.mod", "!services/api/docs/**"}, "services/api"},
+		{[]string{"services/api/**", "services/web/**"}, "services"},
+		{[]string{"services/api*/**"}, "services"},
+		{[]string{"services/api/**", "go.mod"}, ""},
+		{[]string{"**.go"}, ""},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, componentFromPaths(c.paths), "paths: %v", c.paths)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowConcurrency is the workflow-level `concurrency` of a workflow, the runs in the same group run one by one.
+// Both fields could contain expressions, see Evaluate.
+type WorkflowConcurrency struct {
+	Group            string
+	CancelInProgress string
+}
+
+// ParseWorkflowConcurrency parses the workflow-level `concurrency`, which is either a group name or a mapping like
+//
+//	concurrency:
+//	  group: ${{ github.workflow }}-${{ github.ref }}
+//	  cancel-in-progress: true
+//
+// It returns nil if the workflow doesn't declare it.
+func ParseWorkflowConcurrency(content []byte) (*WorkflowConcurrency, error) {
+	var raw struct {
+		Concurrency yaml.Node `yaml:"concurrency"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	node := &raw.Concurrency
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.ScalarNode:
+		if node.Value == "" {
+			return nil, nil
+		}
+		return &WorkflowConcurrency{Group: node.Value}, nil
+	case yaml.MappingNode:
+		var concurrency struct {
+			Group            string `yaml:"group"`
+			CancelInProgress string `yaml:"cancel-in-progress"`
+		}
+		if err := node.Decode(&concurrency); err != nil {
+			return nil, fmt.Errorf("invalid concurrency at line %d: %w", node.Line, err)
+		}
+		if concurrency.Group == "" {
+			return nil, fmt.Errorf("concurrency at line %d requires a group", node.Line)
+		}
+		return &WorkflowConcurrency{Group: concurrency.Group, CancelInProgress: concurrency.CancelInProgress}, nil
+	default:
+		return nil, fmt.Errorf("invalid concurrency at line %d", node.Line)
+	}
+}
+
+// Evaluate evaluates the expressions of the concurrency with the github, vars and inputs contexts,
+// the other contexts aren't available to the workflow-level concurrency.
+func (c *WorkflowConcurrency) Evaluate(gitCtx *model.GithubContext, vars map[string]string, inputs map[string]any) (group string, cancelInProgress bool, err error) {
+	evaluator := jobparser.NewExpressionEvaluator(exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+		Github: gitCtx,
+		Vars:   vars,
+		Inputs: inputs,
+	}, exprparser.Config{Context: "workflow"}))
+
+	group = strings.TrimSpace(evaluator.Interpolate(c.Group))
+	if group == "" {
+		return "", false, fmt.Errorf("the concurrency group %q is evaluated to empty", c.Group)
+	}
+	if c.CancelInProgress == "" {
+		return group, false, nil
+	}
+	cancel := strings.TrimSpace(evaluator.Interpolate(c.CancelInProgress))
+	cancelInProgress, err = strconv.ParseBool(cancel)
+	if err != nil {
+		return "", false, fmt.Errorf("cancel-in-progress %q is evaluated to %q, which isn't a boolean", c.CancelInProgress, cancel)
+	}
+	return group, cancelInProgress, nil
+}
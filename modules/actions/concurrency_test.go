@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkflowConcurrency(t *testing.T) {
+	concurrency, err := ParseWorkflowConcurrency([]byte(`on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+`))
+	require.NoError(t, err)
+	assert.Nil(t, concurrency)
+
+	concurrency, err = ParseWorkflowConcurrency([]byte(`on: push
+concurrency: deploy
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &WorkflowConcurrency{Group: "deploy"}, concurrency)
+
+	concurrency, err = ParseWorkflowConcurrency([]byte(`on: push
+concurrency:
+  group: ${{ github.workflow }}-${{ github.ref }}
+  cancel-in-progress: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &WorkflowConcurrency{Group: "${{ github.workflow }}-${{ github.ref }}", CancelInProgress: "true"}, concurrency)
+
+	_, err = ParseWorkflowConcurrency([]byte(`on: push
+concurrency:
+  cancel-in-progress: true
+`))
+	assert.Error(t, err)
+
+	_, err = ParseWorkflowConcurrency([]byte(`on: push
+concurrency: [deploy]
+`))
+	assert.Error(t, err)
+}
+
+func TestWorkflowConcurrency_Evaluate(t *testing.T) {
+	gitCtx := &model.GithubContext{Workflow: "ci.yaml", Ref: "refs/heads/main"}
+
+	concurrency := &WorkflowConcurrency{
+		Group:            "${{ github.workflow }}-${{ github.ref }}-${{ vars.ENV }}-${{ inputs.target }}",
+		CancelInProgress: "${{ github.ref != 'refs/heads/main' }}",
+	}
+	group, cancel, err := concurrency.Evaluate(gitCtx, map[string]string{"ENV": "prod"}, map[string]any{"target": "eu"})
+	require.NoError(t, err)
+	assert.Equal(t, "ci.yaml-refs/heads/main-prod-eu", group)
+	assert.False(t, cancel)
+
+	group, cancel, err = (&WorkflowConcurrency{Group: "deploy", CancelInProgress: "true"}).Evaluate(gitCtx, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "deploy", group)
+	assert.True(t, cancel)
+
+	_, _, err = (&WorkflowConcurrency{Group: "deploy", CancelInProgress: "${{ github.ref }}"}).Evaluate(gitCtx, nil, nil)
+	assert.Error(t, err)
+}
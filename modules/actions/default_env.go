@@ -0,0 +1,143 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// Where the values of the default environment variables come from
+const (
+	DefaultEnvSourceGitea  = "gitea"  // Gitea sets it in the env of the workflow sent to the runner, unless the workflow sets it
+	DefaultEnvSourceRunner = "runner" // the runner sets it, since only it knows the value
+)
+
+// DefaultEnvVariable is an environment variable defined for every job
+type DefaultEnvVariable struct {
+	Name        string
+	Description string
+	Source      string
+	Value       string // the constant value set by Gitea
+	Context     string // the key of the github context whose value is set by Gitea
+}
+
+// Expression returns the value of the variable set by Gitea, or the expression of the github context which the value comes from
+func (v *DefaultEnvVariable) Expression() string {
+	if v.Context != "" {
+		return fmt.Sprintf("${{ github.%s }}", v.Context)
+	}
+	return v.Value
+}
+
+func giteaEnv(name, value, description string) *DefaultEnvVariable {
+	return &DefaultEnvVariable{Name: name, Description: description, Source: DefaultEnvSourceGitea, Value: value}
+}
+
+func contextEnv(name, key, description string) *DefaultEnvVariable {
+	return &DefaultEnvVariable{Name: name, Description: description, Source: DefaultEnvSourceGitea, Context: key}
+}
+
+func runnerEnv(name, description string) *DefaultEnvVariable {
+	return &DefaultEnvVariable{Name: name, Description: description, Source: DefaultEnvSourceRunner}
+}
+
+// DefaultEnvVariables are the default environment variables of the jobs, like the ones of GitHub Actions,
+// see https://docs.github.com/en/actions/learn-github-actions/variables#default-environment-variables.
+// The runners also set most GITHUB_* variables, the ones set by Gitea fill the gaps of the runners, and GITEA_* are the equivalents of GITHUB_*.
+var DefaultEnvVariables = []*DefaultEnvVariable{
+	giteaEnv("CI", "true", "Always true."),
+	giteaEnv("GITHUB_ACTIONS", "true", "Always true when the workflow runs on Actions."),
+	contextEnv("GITHUB_ACTOR", "actor", "The name of the user that triggered the run."),
+	contextEnv("GITHUB_ACTOR_ID", "actor_id", "The ID of the user that triggered the run."),
+	contextEnv("GITHUB_API_URL", "api_url", "The URL of the API."),
+	contextEnv("GITHUB_BASE_REF", "base_ref", "The target branch of the pull request, only set for the events of pull requests."),
+	contextEnv("GITHUB_EVENT_NAME", "event_name", "The name of the event that triggered the run."),
+	contextEnv("GITHUB_HEAD_REF", "head_ref", "The source branch of the pull request, only set for the events of pull requests."),
+	contextEnv("GITHUB_JOB", "job", "The ID of the job."),
+	contextEnv("GITHUB_REF", "ref", "The fully-formed ref of the branch or tag that triggered the run."),
+	contextEnv("GITHUB_REF_NAME", "ref_name", "The short name of the branch or tag that triggered the run."),
+	contextEnv("GITHUB_REF_PROTECTED", "ref_protected", "Whether the ref that triggered the run is protected."),
+	contextEnv("GITHUB_REF_TYPE", "ref_type", "The type of the ref that triggered the run, branch or tag."),
+	contextEnv("GITHUB_REPOSITORY", "repository", "The owner and name of the repository."),
+	contextEnv("GITHUB_REPOSITORY_ID", "repository_id", "The ID of the repository."),
+	contextEnv("GITHUB_REPOSITORY_OWNER", "repository_owner", "The name of the owner of the repository."),
+	contextEnv("GITHUB_REPOSITORY_OWNER_ID", "repository_owner_id", "The ID of the owner of the repository."),
+	contextEnv("GITHUB_RUN_ATTEMPT", "run_attempt", "The attempt of the job, it begins at 1 and increments with each rerun."),
+	contextEnv("GITHUB_RUN_ID", "run_id", "The ID of the run, it doesn't change when the run is rerun."),
+	contextEnv("GITHUB_RUN_NUMBER", "run_number", "The number of the run in the repository."),
+	contextEnv("GITHUB_SERVER_URL", "server_url", "The URL of the instance."),
+	contextEnv("GITHUB_SHA", "sha", "The commit SHA that triggered the run."),
+	contextEnv("GITHUB_TRIGGERING_ACTOR", "triggering_actor", "The name of the user that triggered the run."),
+	contextEnv("GITHUB_WORKFLOW", "workflow", "The file name of the workflow."),
+	runnerEnv("GITHUB_ACTION", "The name of the action currently running, or the ID of the step."),
+	runnerEnv("GITHUB_ACTION_PATH", "The path where the action is located, only set for composite actions."),
+	runnerEnv("GITHUB_ACTION_REPOSITORY", "The owner and name of the repository of the action currently running."),
+	runnerEnv("GITHUB_ENV", "The path of the file to set the environment variables of the next steps."),
+	runnerEnv("GITHUB_EVENT_PATH", "The path of the file with the payload of the event that triggered the run."),
+	runnerEnv("GITHUB_OUTPUT", "The path of the file to set the outputs of the step."),
+	runnerEnv("GITHUB_PATH", "The path of the file to add directories to the PATH of the next steps."),
+	runnerEnv("GITHUB_STATE", "The path of the file to save the state of the action."),
+	runnerEnv("GITHUB_STEP_SUMMARY", "The path of the file to write the summary of the step."),
+	runnerEnv("GITHUB_WORKSPACE", "The default working directory of the steps, where the repository is checked out."),
+	giteaEnv("GITEA_ACTIONS", "true", "Always true when the workflow runs on Gitea Actions."),
+	contextEnv("GITEA_ACTOR", "actor", "The equivalent of GITHUB_ACTOR."),
+	contextEnv("GITEA_API_URL", "api_url", "The equivalent of GITHUB_API_URL."),
+	contextEnv("GITEA_BASE_REF", "base_ref", "The equivalent of GITHUB_BASE_REF."),
+	contextEnv("GITEA_EVENT_NAME", "event_name", "The equivalent of GITHUB_EVENT_NAME."),
+	contextEnv("GITEA_HEAD_REF", "head_ref", "The equivalent of GITHUB_HEAD_REF."),
+	contextEnv("GITEA_JOB", "job", "The equivalent of GITHUB_JOB."),
+	contextEnv("GITEA_REF", "ref", "The equivalent of GITHUB_REF."),
+	contextEnv("GITEA_REF_NAME", "ref_name", "The equivalent of GITHUB_REF_NAME."),
+	contextEnv("GITEA_REF_TYPE", "ref_type", "The equivalent of GITHUB_REF_TYPE."),
+	contextEnv("GITEA_REPOSITORY", "repository", "The equivalent of GITHUB_REPOSITORY."),
+	contextEnv("GITEA_REPOSITORY_OWNER", "repository_owner", "The equivalent of GITHUB_REPOSITORY_OWNER."),
+	contextEnv("GITEA_RUN_ATTEMPT", "run_attempt", "The equivalent of GITHUB_RUN_ATTEMPT."),
+	contextEnv("GITEA_RUN_ID", "run_id", "The equivalent of GITHUB_RUN_ID."),
+	contextEnv("GITEA_RUN_NUMBER", "run_number", "The equivalent of GITHUB_RUN_NUMBER."),
+	contextEnv("GITEA_SERVER_URL", "server_url", "The equivalent of GITHUB_SERVER_URL."),
+	contextEnv("GITEA_SHA", "sha", "The equivalent of GITHUB_SHA."),
+	contextEnv("GITEA_WORKFLOW", "workflow", "The equivalent of GITHUB_WORKFLOW."),
+	contextEnv("GITEA_RUN_REGISTRY", "gitea_run_registry", "The container registry namespace of the run for the temporary images, which are removed when the run is done, empty if the packages are disabled."),
+	runnerEnv("RUNNER_ARCH", "The architecture of the runner, X86, X64, ARM or ARM64."),
+	runnerEnv("RUNNER_OS", "The operating system of the runner, Linux, Windows or macOS."),
+	runnerEnv("RUNNER_TEMP", "The path of a temporary directory which is emptied at the beginning and end of each job."),
+	runnerEnv("RUNNER_TOOL_CACHE", "The path of the directory of the tools preinstalled for the actions."),
+}
+
+// runnerGithubContextKeys are the keys of the github context known by the runners, see model.GithubContext of act
+var runnerGithubContextKeys = container.SetOf(
+	"actor", "api_url", "base_ref", "event_name", "head_ref", "job", "ref", "ref_name", "ref_type",
+	"repository", "repository_owner", "run_id", "run_number", "server_url", "sha", "workflow",
+)
+
+// ApplyDefaultEnv sets the default environment variables which Gitea is responsible for in the env of the single workflow of a job,
+// the variables already set by the workflow are kept. The values of the github context known by the runners are set as expressions,
+// so they are never interpolated twice, the others are resolved from the github context of the task.
+func ApplyDefaultEnv(payload []byte, githubContext map[string]any) ([]byte, error) {
+	workflows, err := jobparser.Parse(payload)
+	if err != nil {
+		return nil, err
+	} else if len(workflows) != 1 {
+		return nil, fmt.Errorf("not single workflow")
+	}
+	workflow := workflows[0]
+	if workflow.Env == nil {
+		workflow.Env = make(map[string]string, len(DefaultEnvVariables))
+	}
+	for _, v := range DefaultEnvVariables {
+		if _, ok := workflow.Env[v.Name]; ok || v.Source != DefaultEnvSourceGitea {
+			continue
+		}
+		if v.Context == "" || runnerGithubContextKeys.Contains(v.Context) {
+			workflow.Env[v.Name] = v.Expression()
+		} else {
+			workflow.Env[v.Name] = fmt.Sprint(githubContext[v.Context])
+		}
+	}
+	return workflow.Marshal()
+}
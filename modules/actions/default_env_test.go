@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDefaultEnv(t *testing.T) {
+	payload := []byte(`
+name: test
+on: push
+env:
+  CI: "false"
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo $GITEA_REPOSITORY
+`)
+	content, err := ApplyDefaultEnv(payload, map[string]any{
+		"repository":    "user2/repo1",
+		"run_attempt":   "2",
+		"ref_protected": true,
+		"repository_id": "1",
+	})
+	require.NoError(t, err)
+
+	workflows, err := jobparser.Parse(content)
+	require.NoError(t, err)
+	require.Len(t, workflows, 1)
+	env := workflows[0].Env
+
+	// the variables set by the workflow are kept
+	assert.Equal(t, "false", env["CI"])
+	assert.Equal(t, "true", env["GITEA_ACTIONS"])
+	// the runners evaluate the github context they know, the others are resolved by Gitea
+	assert.Equal(t, "${{ github.repository }}", env["GITEA_REPOSITORY"])
+	assert.Equal(t, "${{ github.repository }}", env["GITHUB_REPOSITORY"])
+	assert.Equal(t, "2", env["GITHUB_RUN_ATTEMPT"])
+	assert.Equal(t, "true", env["GITHUB_REF_PROTECTED"])
+	assert.Equal(t, "1", env["GITHUB_REPOSITORY_ID"])
+	// the variables set by the runners aren't set
+	assert.NotContains(t, env, "GITHUB_WORKSPACE")
+	assert.NotContains(t, env, "RUNNER_OS")
+
+	id, job := workflows[0].Job()
+	assert.Equal(t, "job1", id)
+	assert.Len(t, job.Steps, 1)
+
+	_, err = ApplyDefaultEnv([]byte(`on: push
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo 1
+  job2:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo 2
+`), nil)
+	assert.ErrorContains(t, err, "not single workflow")
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxEgressDomains is how many domains a workflow or an owner could allow the jobs to connect to
+const MaxEgressDomains = 100
+
+// egressDomainPattern matches the domains like "github.com" and the wildcards of their sub-domains like "*.npmjs.org"
+var egressDomainPattern = regexp.MustCompile(`^(?:\*\.)?(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)*[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$`)
+
+// ParseWorkflowEgress parses the domains which the jobs are allowed to connect to declared in an "egress" block
+// of the leading comments of the workflow content, so the workflow is still valid for other platforms, e.g.
+//
+//	# egress:
+//	#   allow: [github.com, "*.npmjs.org"]
+//
+// It returns nil if there is no egress block, and an empty list if the block allows no domains.
+func ParseWorkflowEgress(content []byte) ([]string, error) {
+	block, ok, err := leadingCommentBlock(content, "egress")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var raw struct {
+		Allow yaml.Node `yaml:"allow"`
+	}
+	if err := yaml.Unmarshal(block, &raw); err != nil {
+		return nil, fmt.Errorf("invalid egress block: %w", err)
+	}
+	var domains []string
+	switch raw.Allow.Kind {
+	case 0:
+	case yaml.ScalarNode:
+		domains = strings.Split(raw.Allow.Value, ",")
+	case yaml.SequenceNode:
+		if err := raw.Allow.Decode(&domains); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("expect a list of egress domains at line %d", raw.Allow.Line)
+	}
+	return NormalizeEgressDomains(domains)
+}
+
+// NormalizeEgressDomains validates and lower-cases the domains, the empty and duplicated ones are dropped
+func NormalizeEgressDomains(domains []string) ([]string, error) {
+	ret := make([]string, 0, len(domains))
+	seen := make(container.Set[string], len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain == "" || !seen.Add(domain) {
+			continue
+		}
+		if !egressDomainPattern.MatchString(domain) {
+			return nil, fmt.Errorf("invalid egress domain %q, it should be like example.com or *.example.com", domain)
+		}
+		ret = append(ret, domain)
+	}
+	if len(ret) > MaxEgressDomains {
+		return nil, fmt.Errorf("too many egress domains, at most %d are allowed", MaxEgressDomains)
+	}
+	return ret, nil
+}
+
+// MatchEgressDomain returns whether the host is allowed by the domains, a wildcard like "*.example.com"
+// allows the sub-domains of "example.com" but not itself. The host could be a wildcard too, then it's allowed
+// if all the sub-domains it stands for are allowed.
+func MatchEgressDomain(domains []string, host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	name := strings.TrimPrefix(host, "*.")
+	for _, domain := range domains {
+		if domain == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(domain, "*."); ok && strings.HasSuffix(name, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictEgressDomains returns the domains declared by a workflow which are also allowed by its owner
+func RestrictEgressDomains(declared, allowed []string) []string {
+	ret := make([]string, 0, len(declared))
+	for _, domain := range declared {
+		if MatchEgressDomain(allowed, domain) {
+			ret = append(ret, domain)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkflowEgress(t *testing.T) {
+	domains, err := ParseWorkflowEgress([]byte(`# egress:
+#   allow: [GitHub.com, "*.npmjs.org", github.com]
+name: build
+on: push
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github.com", "*.npmjs.org"}, domains)
+
+	domains, err = ParseWorkflowEgress([]byte("# egress:\n#   allow: proxy.golang.org, sum.golang.org\non: push\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"proxy.golang.org", "sum.golang.org"}, domains)
+
+	// no domains are allowed
+	domains, err = ParseWorkflowEgress([]byte("# egress:\n#   allow: []\non: push\n"))
+	require.NoError(t, err)
+	assert.NotNil(t, domains)
+	assert.Empty(t, domains)
+
+	domains, err = ParseWorkflowEgress([]byte("on: push\n"))
+	require.NoError(t, err)
+	assert.Nil(t, domains)
+
+	_, err = ParseWorkflowEgress([]byte("# egress:\n#   allow: [\"https://github.com\"]\non: push\n"))
+	assert.Error(t, err)
+	_, err = ParseWorkflowEgress([]byte("# egress:\n#   allow: [\"github.*\"]\non: push\n"))
+	assert.Error(t, err)
+}
+
+func TestMatchEgressDomain(t *testing.T) {
+	domains := []string{"github.com", "*.npmjs.org"}
+	assert.True(t, MatchEgressDomain(domains, "github.com"))
+	assert.True(t, MatchEgressDomain(domains, "GitHub.com."))
+	assert.False(t, MatchEgressDomain(domains, "api.github.com"))
+	assert.True(t, MatchEgressDomain(domains, "registry.npmjs.org"))
+	assert.True(t, MatchEgressDomain(domains, "*.registry.npmjs.org"))
+	assert.True(t, MatchEgressDomain(domains, "*.npmjs.org"))
+	assert.False(t, MatchEgressDomain(domains, "npmjs.org"))
+	assert.False(t, MatchEgressDomain(domains, "evilnpmjs.org"))
+
+	assert.Equal(t, []string{"registry.npmjs.org", "*.x.npmjs.org"}, RestrictEgressDomains([]string{"registry.npmjs.org", "example.com", "*.x.npmjs.org", "*.github.com"}, domains))
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// ParseJobEnvironments returns the names of the environments referenced by the jobs of the workflow, keyed by job ids.
+// An environment is referenced by "environment: name" or "environment: {name: name, url: url}",
+// the jobs without environments are omitted.
+func ParseJobEnvironments(content []byte) (map[string]string, error) {
+	var raw struct {
+		Jobs map[string]struct {
+			Environment yaml.Node `yaml:"environment"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	envs := make(map[string]string, len(raw.Jobs))
+	for id, job := range raw.Jobs {
+		var name string
+		switch job.Environment.Kind {
+		case yaml.ScalarNode:
+			name = job.Environment.Value
+		case yaml.MappingNode:
+			var v struct {
+				Name string `yaml:"name"`
+			}
+			if err := job.Environment.Decode(&v); err != nil {
+				return nil, err
+			}
+			name = v.Name
+		}
+		if name != "" {
+			envs[id] = name
+		}
+	}
+	return envs, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJobEnvironments(t *testing.T) {
+	envs, err := ParseJobEnvironments([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make
+  staging:
+    runs-on: ubuntu-latest
+    environment: staging
+    steps:
+      - run: make deploy
+  production:
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+      url: https://example.com
+    steps:
+      - run: make deploy
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"staging": "staging", "production": "production"}, envs)
+}
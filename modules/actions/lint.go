@@ -0,0 +1,183 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The rules of the workflow linter
+const (
+	LintRuleInvalidSyntax         = "invalid-syntax"
+	LintRuleUnsupportedKey        = "unsupported-key"
+	LintRuleUnsupportedEvent      = "unsupported-event"
+	LintRuleUnsupportedRunsOn     = "unsupported-runs-on"
+	LintRuleUnsupportedExpression = "unsupported-expression"
+	LintRuleDeprecatedCommand     = "deprecated-command"
+)
+
+// WorkflowLintFinding is a problem found in a workflow file
+type WorkflowLintFinding struct {
+	Line    int
+	Column  int
+	Rule    string
+	Message string
+}
+
+// keys which are accepted in the workflow syntax but ignored by Gitea Actions,
+// see docs/content/usage/actions/comparison.en-us.md
+var (
+	lintUnsupportedWorkflowKeys = []string{"run-name"}
+	lintUnsupportedJobKeys      = []string{"concurrency", "timeout-minutes", "continue-on-error", "environment"}
+	lintUnsupportedEvents       = []string{
+		"check_run", "check_suite", "deployment", "deployment_status", "discussion", "discussion_comment",
+		"merge_group", "page_build", "repository_dispatch", "workflow_run",
+	}
+)
+
+// the workflow commands which have been deprecated in favor of environment files
+var lintDeprecatedCommandRe = regexp.MustCompile(`::(set-output|save-state|set-env|add-path)\b`)
+
+var lintDeprecatedCommandReplacements = map[string]string{
+	"set-output": "$GITHUB_OUTPUT",
+	"save-state": "$GITHUB_STATE",
+	"set-env":    "$GITHUB_ENV",
+	"add-path":   "$GITHUB_PATH",
+}
+
+// LintWorkflow checks the content of a workflow for the syntax which is deprecated or not supported by Gitea Actions.
+// A workflow which can't be parsed results in a single finding of LintRuleInvalidSyntax.
+func LintWorkflow(content []byte) []*WorkflowLintFinding {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return []*WorkflowLintFinding{{Rule: LintRuleInvalidSyntax, Message: err.Error()}}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []*WorkflowLintFinding{{Line: root.Line, Column: root.Column, Rule: LintRuleInvalidSyntax, Message: "workflow must be a mapping"}}
+	}
+
+	var findings []*WorkflowLintFinding
+	add := func(node *yaml.Node, rule, format string, args ...any) {
+		findings = append(findings, &WorkflowLintFinding{
+			Line:    node.Line,
+			Column:  node.Column,
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch {
+		case slices.Contains(lintUnsupportedWorkflowKeys, key.Value):
+			add(key, LintRuleUnsupportedKey, "%q is not supported and will be ignored", key.Value)
+		case key.Value == "on":
+			for _, event := range lintEventNodes(value) {
+				if slices.Contains(lintUnsupportedEvents, event.Value) {
+					add(event, LintRuleUnsupportedEvent, "event %q is not supported and will never trigger the workflow", event.Value)
+				}
+			}
+		case key.Value == "jobs" && value.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				jobID, job := value.Content[j], value.Content[j+1]
+				if job.Kind != yaml.MappingNode {
+					continue
+				}
+				for k := 0; k+1 < len(job.Content); k += 2 {
+					jobKey, jobValue := job.Content[k], job.Content[k+1]
+					switch {
+					case slices.Contains(lintUnsupportedJobKeys, jobKey.Value):
+						add(jobKey, LintRuleUnsupportedKey, "\"jobs.%s.%s\" is not supported and will be ignored", jobID.Value, jobKey.Value)
+					case jobKey.Value == "runs-on" && jobValue.Kind == yaml.MappingNode:
+						add(jobValue, LintRuleUnsupportedRunsOn, "\"jobs.%s.runs-on\" only supports a label or a list of labels", jobID.Value)
+					case jobKey.Value == "steps" && jobValue.Kind == yaml.SequenceNode:
+						for _, step := range jobValue.Content {
+							if run := lintMappingValue(step, "run"); run != nil && run.Kind == yaml.ScalarNode {
+								findings = append(findings, lintDeprecatedCommands(run)...)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	lintWalkScalars(root, func(node *yaml.Node) {
+		if strings.Contains(node.Value, "hashFiles(") {
+			add(node, LintRuleUnsupportedExpression, "expression \"hashFiles\" is not supported and always returns an empty string")
+		}
+	})
+
+	return findings
+}
+
+// lintDeprecatedCommands finds the deprecated workflow commands in a run script
+func lintDeprecatedCommands(node *yaml.Node) []*WorkflowLintFinding {
+	var findings []*WorkflowLintFinding
+	line := node.Line
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		// the content of a block scalar starts at the line after the indicator
+		line++
+	}
+	for i, text := range strings.Split(node.Value, "\n") {
+		for _, match := range lintDeprecatedCommandRe.FindAllStringSubmatch(text, -1) {
+			findings = append(findings, &WorkflowLintFinding{
+				Line:    line + i,
+				Column:  node.Column,
+				Rule:    LintRuleDeprecatedCommand,
+				Message: fmt.Sprintf("command %q is deprecated, write to %s instead", match[1], lintDeprecatedCommandReplacements[match[1]]),
+			})
+		}
+	}
+	return findings
+}
+
+// lintEventNodes returns the nodes of the event names in the "on" section
+func lintEventNodes(node *yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []*yaml.Node{node}
+	case yaml.SequenceNode:
+		return node.Content
+	case yaml.MappingNode:
+		events := make([]*yaml.Node, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			events = append(events, node.Content[i])
+		}
+		return events
+	}
+	return nil
+}
+
+// lintMappingValue returns the value of the key in a mapping node, or nil if the key doesn't exist
+func lintMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func lintWalkScalars(node *yaml.Node, fn func(node *yaml.Node)) {
+	if node.Kind == yaml.ScalarNode {
+		fn(node)
+		return
+	}
+	for _, child := range node.Content {
+		lintWalkScalars(child, fn)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintWorkflow(t *testing.T) {
+	findings := LintWorkflow([]byte(`name: test
+run-name: test by ${{ gitea.actor }}
+on:
+  push:
+  workflow_run:
+jobs:
+  build:
+    runs-on:
+      group: large
+    timeout-minutes: 10
+    steps:
+      - uses: actions/cache@v4
+        with:
+          key: ${{ hashFiles('go.sum') }}
+      - run: |
+          echo "hello"
+          echo "::set-output name=foo::bar"
+  test:
+    runs-on: [ubuntu-latest]
+    steps:
+      - run: echo "::add-path::/opt/bin"
+`))
+
+	type finding struct {
+		Line int
+		Rule string
+	}
+	actual := make([]finding, 0, len(findings))
+	for _, f := range findings {
+		assert.NotEmpty(t, f.Message)
+		actual = append(actual, finding{Line: f.Line, Rule: f.Rule})
+	}
+	assert.Equal(t, []finding{
+		{Line: 2, Rule: LintRuleUnsupportedKey},
+		{Line: 5, Rule: LintRuleUnsupportedEvent},
+		{Line: 9, Rule: LintRuleUnsupportedRunsOn},
+		{Line: 10, Rule: LintRuleUnsupportedKey},
+		{Line: 17, Rule: LintRuleDeprecatedCommand},
+		{Line: 21, Rule: LintRuleDeprecatedCommand},
+		{Line: 14, Rule: LintRuleUnsupportedExpression},
+	}, actual)
+
+	// `permissions` is supported, see ParseJobPermissions
+	assert.Empty(t, LintWorkflow([]byte(`on: push
+permissions: read-all
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    permissions:
+      pull-requests: write
+    steps:
+      - run: echo "result=ok" >> $GITHUB_OUTPUT
+`)))
+
+	findings = LintWorkflow([]byte("on: [push\n"))
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, LintRuleInvalidSyntax, findings[0].Rule)
+	}
+}
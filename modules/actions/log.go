@@ -138,6 +138,10 @@ func RemoveLogs(ctx context.Context, inStorage bool, filename string) error {
 	if err != nil {
 		return fmt.Errorf("storage delete %q: %w", filename, err)
 	}
+	// the structured logs are only saved for the logs in the storage
+	if err := storage.Actions.Delete(filename + StructuredLogsSuffix); err != nil {
+		return fmt.Errorf("storage delete %q: %w", filename+StructuredLogsSuffix, err)
+	}
 	return nil
 }
 
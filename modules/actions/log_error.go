@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import "strings"
+
+// infrastructureErrorLogs are the messages in lower case which tell the runner or its machine failed, not the workflow
+var infrastructureErrorLogs = []string{
+	"no space left on device",
+	"cannot connect to the docker daemon",
+}
+
+// IsInfrastructureErrorLog returns whether the log line tells the runner or its machine failed, e.g. the disk is full
+func IsInfrastructureErrorLog(content string) bool {
+	content = strings.ToLower(content)
+	for _, msg := range infrastructureErrorLogs {
+		if strings.Contains(content, msg) {
+			return true
+		}
+	}
+	return false
+}
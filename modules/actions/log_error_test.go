@@ -0,0 +1,16 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInfrastructureErrorLog(t *testing.T) {
+	assert.True(t, IsInfrastructureErrorLog("write /tmp/cache/obj: No space left on device"))
+	assert.True(t, IsInfrastructureErrorLog("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"))
+	assert.False(t, IsInfrastructureErrorLog("--- FAIL: TestSomething (0.01s)"))
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+)
+
+const (
+	privateSectionStart = "::private::"
+	privateSectionEnd   = "::endprivate::"
+
+	// RedactedLogLine replaces the lines in the private sections of the logs, like the masked secrets
+	RedactedLogLine = "***"
+)
+
+// PrivateLogSections tracks the private sections of the logs of a step, which are only visible to the maintainers.
+// A section starts with a line ending with "::private::", and ends with a line ending with "::endprivate::" or at the end of the step.
+// The lines of the markers themselves are kept, so the readers could know something has been redacted.
+type PrivateLogSections struct {
+	inSection bool
+}
+
+// Redact returns the content of the line to show to the users who can't see the private sections,
+// the lines must be passed in order from the first line of the step.
+func (s *PrivateLogSections) Redact(content string) string {
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasSuffix(trimmed, privateSectionStart):
+		s.inSection = true
+	case strings.HasSuffix(trimmed, privateSectionEnd):
+		s.inSection = false
+	case s.inSection:
+		return RedactedLogLine
+	}
+	return content
+}
+
+// RedactPrivateLogs copies the logs of a task from r to w with the lines in the private sections redacted,
+// stepStarts are the indexes of the first lines of the steps, where the open sections end.
+func RedactPrivateLogs(w io.Writer, r io.Reader, stepStarts container.Set[int64]) error {
+	scanner := bufio.NewScanner(r)
+	maxLineSize := len(timeFormat) + MaxLineSize + 1
+	scanner.Buffer(make([]byte, maxLineSize), maxLineSize)
+
+	writer := bufio.NewWriterSize(w, defaultBufSize)
+	sections := &PrivateLogSections{}
+	for index := int64(0); scanner.Scan(); index++ {
+		if stepStarts.Contains(index) {
+			sections = &PrivateLogSections{}
+		}
+		t, c, err := ParseLog(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("parse log %q: %w", scanner.Text(), err)
+		}
+		if _, err := writer.WriteString(FormatLog(t, sections.Redact(c)) + "\n"); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("RedactPrivateLogs scan: %w", err)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateLogSections(t *testing.T) {
+	sections := &PrivateLogSections{}
+	var got []string
+	for _, line := range []string{
+		"build",
+		"::private::",
+		"host: 10.0.0.1",
+		"token scope: deploy",
+		"  ::endprivate::",
+		"done",
+		"❓  ::private::",
+		"still private",
+	} {
+		got = append(got, sections.Redact(line))
+	}
+	assert.Equal(t, []string{
+		"build",
+		"::private::",
+		"***",
+		"***",
+		"  ::endprivate::",
+		"done",
+		"❓  ::private::",
+		"***",
+	}, got)
+}
+
+func TestRedactPrivateLogs(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var logs strings.Builder
+	for _, line := range []string{
+		"::private::",
+		"secret of step 1",
+		"step 2",
+		"::private::",
+		"secret of step 2",
+		"::endprivate::",
+		"public",
+	} {
+		logs.WriteString(FormatLog(now, line) + "\n")
+	}
+
+	var out bytes.Buffer
+	// the private section of step 1 ends at the start of step 2
+	require.NoError(t, RedactPrivateLogs(&out, strings.NewReader(logs.String()), container.SetOf[int64](0, 2)))
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		_, content, err := ParseLog(line)
+		require.NoError(t, err)
+		got = append(got, content)
+	}
+	assert.Equal(t, []string{
+		"::private::",
+		"***",
+		"step 2",
+		"::private::",
+		"***",
+		"::endprivate::",
+		"public",
+	}, got)
+}
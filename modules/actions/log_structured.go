@@ -0,0 +1,354 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/storage"
+)
+
+// The types of the lines of the structured logs
+const (
+	StructuredLogLineNormal   = "line"
+	StructuredLogLineGroup    = "group"    // starts a group, its segments are the title
+	StructuredLogLineEndGroup = "endgroup" // ends the open group
+)
+
+// The levels of the lines of the structured logs set by the workflow commands, like "::error::message"
+const (
+	StructuredLogLevelDebug   = "debug"
+	StructuredLogLevelNotice  = "notice"
+	StructuredLogLevelWarning = "warning"
+	StructuredLogLevelError   = "error"
+)
+
+// StructuredLogsSuffix is the suffix of the file names of the structured logs, they're stored alongside the raw logs
+const StructuredLogsSuffix = ".structured.jsonl"
+
+// LogSegment is a part of a log line in the same style set by the ANSI escape sequences,
+// the colors are the names like "red" and "bright-red" of the 16 basic colors, or like "#ff8700" of the others.
+type LogSegment struct {
+	Text       string `json:"text"`
+	Foreground string `json:"fg,omitempty"`
+	Background string `json:"bg,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	Italic     bool   `json:"italic,omitempty"`
+	Underline  bool   `json:"underline,omitempty"`
+}
+
+// StructuredLogLine is a line of the logs parsed from the ANSI escape sequences and the workflow commands
+type StructuredLogLine struct {
+	// Index is the index of the line in the raw logs
+	Index int64     `json:"index"`
+	Time  time.Time `json:"time"`
+	Type  string    `json:"type"`
+	Level string    `json:"level,omitempty"`
+	// Group is the number of the group the line is in, starting from 1, 0 if it isn't in a group
+	Group int64 `json:"group,omitempty"`
+	// Private tells the line is in a private section, see PrivateLogSections
+	Private  bool          `json:"private,omitempty"`
+	Segments []*LogSegment `json:"segments"`
+}
+
+// StructuredLogParser parses the lines of the logs of a task in order into the structured logs.
+// Like the private sections, the groups don't span the steps, and a group ends the open one as the groups couldn't be nested.
+type StructuredLogParser struct {
+	groups  int64 // how many groups have been started
+	group   int64 // the open group, 0 if there isn't one
+	private PrivateLogSections
+}
+
+// StartStep ends the open group and private section, it's called before the first line of every step
+func (p *StructuredLogParser) StartStep() {
+	p.group = 0
+	p.private = PrivateLogSections{}
+}
+
+// Parse returns the structured line of the line of the raw logs
+func (p *StructuredLogParser) Parse(index int64, t time.Time, content string) *StructuredLogLine {
+	line := &StructuredLogLine{
+		Index:   index,
+		Time:    t,
+		Type:    StructuredLogLineNormal,
+		Private: p.private.Redact(content) != content,
+	}
+	text := content
+	if command, message, ok := parseWorkflowCommand(content); ok {
+		switch command {
+		case "group":
+			p.groups++
+			p.group = p.groups
+			line.Type, text = StructuredLogLineGroup, message
+		case "endgroup":
+			line.Type, text = StructuredLogLineEndGroup, ""
+			line.Group = p.group
+			p.group = 0
+		case StructuredLogLevelDebug, StructuredLogLevelNotice, StructuredLogLevelWarning, StructuredLogLevelError:
+			line.Level, text = command, message
+		}
+	}
+	if line.Type != StructuredLogLineEndGroup {
+		line.Group = p.group
+	}
+	line.Segments = ParseANSISegments(text)
+	return line
+}
+
+// parseWorkflowCommand parses the workflow commands like "::error file=main.go::message" and "##[group]title"
+func parseWorkflowCommand(content string) (command, message string, ok bool) {
+	var before string
+	if s, found := strings.CutPrefix(content, "::"); found {
+		before, message, ok = strings.Cut(s, "::")
+	} else if s, found := strings.CutPrefix(content, "##["); found {
+		before, message, ok = strings.Cut(s, "]")
+	}
+	if !ok {
+		return "", "", false
+	}
+	command, _, _ = strings.Cut(before, " ")
+	return strings.ToLower(command), message, command != ""
+}
+
+var ansiColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// ParseANSISegments splits the line into the segments by the SGR sequences of ANSI, like "\x1b[1;31m",
+// the other escape sequences are dropped. The style starts from the default one for every line.
+func ParseANSISegments(s string) []*LogSegment {
+	segments := []*LogSegment{}
+	style := LogSegment{}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			segment := style
+			segment.Text = text.String()
+			segments = append(segments, &segment)
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			text.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == ']' {
+			// drop the OSC sequences like the titles, which end with BEL or ST
+			rest := s[i+2:]
+			bel, st := strings.IndexByte(rest, '\a'), strings.Index(rest, "\x1b\\")
+			switch {
+			case bel >= 0 && (st < 0 || bel < st):
+				i += 2 + bel
+			case st >= 0:
+				i += 2 + st + 1
+			default:
+				i = len(s)
+			}
+			continue
+		}
+		if i+1 >= len(s) || s[i+1] != '[' {
+			// drop the other escape sequences like "\x1b(B" with their intermediate and final bytes
+			i++
+			for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+				i++
+			}
+			continue
+		}
+		end := i + 2
+		for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+			end++
+		}
+		if end >= len(s) {
+			break
+		}
+		if s[end] == 'm' {
+			flush()
+			applySGR(&style, s[i+2:end])
+		}
+		i = end
+	}
+	flush()
+	return segments
+}
+
+// applySGR applies the parameters of an SGR sequence to the style
+func applySGR(style *LogSegment, params string) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if codes[i] == "" {
+			code, err = 0, nil
+		}
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0:
+			*style = LogSegment{}
+		case code == 1:
+			style.Bold = true
+		case code == 3:
+			style.Italic = true
+		case code == 4:
+			style.Underline = true
+		case code == 22:
+			style.Bold = false
+		case code == 23:
+			style.Italic = false
+		case code == 24:
+			style.Underline = false
+		case code >= 30 && code <= 37:
+			style.Foreground = ansiColorNames[code-30]
+		case code >= 90 && code <= 97:
+			style.Foreground = "bright-" + ansiColorNames[code-90]
+		case code == 39:
+			style.Foreground = ""
+		case code >= 40 && code <= 47:
+			style.Background = ansiColorNames[code-40]
+		case code >= 100 && code <= 107:
+			style.Background = "bright-" + ansiColorNames[code-100]
+		case code == 49:
+			style.Background = ""
+		case code == 38 || code == 48:
+			color, n := parseExtendedColor(codes[i+1:])
+			i += n
+			if code == 38 {
+				style.Foreground = color
+			} else {
+				style.Background = color
+			}
+		}
+	}
+}
+
+// parseExtendedColor parses the 256 colors like "5;208" and the true colors like "2;255;135;0",
+// and returns how many parameters are used
+func parseExtendedColor(params []string) (string, int) {
+	nums := make([]int, 0, 4)
+	for _, p := range params {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 255 {
+			break
+		}
+		nums = append(nums, n)
+	}
+	switch {
+	case len(nums) >= 2 && nums[0] == 5:
+		return color256(nums[1]), 2
+	case len(nums) >= 4 && nums[0] == 2:
+		return fmt.Sprintf("#%02x%02x%02x", nums[1], nums[2], nums[3]), 4
+	}
+	return "", len(nums)
+}
+
+func color256(n int) string {
+	switch {
+	case n < 8:
+		return ansiColorNames[n]
+	case n < 16:
+		return "bright-" + ansiColorNames[n-8]
+	case n < 232:
+		levels := []int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	}
+	gray := 8 + (n-232)*10
+	return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+}
+
+// ParseStructuredLogs parses the raw logs of a task from r, and calls fn with the structured lines in order until it returns false,
+// stepStarts are the indexes of the first lines of the steps.
+func ParseStructuredLogs(r io.Reader, stepStarts container.Set[int64], fn func(*StructuredLogLine) bool) error {
+	scanner := bufio.NewScanner(r)
+	maxLineSize := len(timeFormat) + MaxLineSize + 1
+	scanner.Buffer(make([]byte, maxLineSize), maxLineSize)
+
+	parser := &StructuredLogParser{}
+	for index := int64(0); scanner.Scan(); index++ {
+		if stepStarts.Contains(index) {
+			parser.StartStep()
+		}
+		t, c, err := ParseLog(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("parse log %q: %w", scanner.Text(), err)
+		}
+		if !fn(parser.Parse(index, t, c)) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ParseStructuredLogs scan: %w", err)
+	}
+	return nil
+}
+
+// SaveStructuredLogs parses the raw logs of a task in the storage, and saves the structured logs alongside them as JSON lines
+func SaveStructuredLogs(filename string, stepStarts container.Set[int64]) error {
+	f, err := storage.Actions.Open(filename)
+	if err != nil {
+		return fmt.Errorf("storage open %q: %w", filename, err)
+	}
+	defer f.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		writer := bufio.NewWriterSize(pw, defaultBufSize)
+		encoder := json.NewEncoder(writer)
+		var encodeErr error
+		err := ParseStructuredLogs(f, stepStarts, func(line *StructuredLogLine) bool {
+			encodeErr = encoder.Encode(line)
+			return encodeErr == nil
+		})
+		if err == nil {
+			err = encodeErr
+		}
+		if err == nil {
+			err = writer.Flush()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	if _, err := storage.Actions.Save(filename+StructuredLogsSuffix, pr, -1); err != nil {
+		_ = pr.CloseWithError(err)
+		return fmt.Errorf("storage save %q: %w", filename+StructuredLogsSuffix, err)
+	}
+	return nil
+}
+
+// ReadStructuredLogs reads the structured logs saved by SaveStructuredLogs, and calls fn with the lines in order until it returns false,
+// it returns false if they haven't been saved, e.g. the logs were transferred to the storage before the structured logs were introduced.
+func ReadStructuredLogs(filename string, fn func(*StructuredLogLine) bool) (bool, error) {
+	name := filename + StructuredLogsSuffix
+	if _, err := storage.Actions.Stat(name); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("storage stat %q: %w", name, err)
+	}
+	f, err := storage.Actions.Open(name)
+	if err != nil {
+		return false, fmt.Errorf("storage open %q: %w", name, err)
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(bufio.NewReaderSize(f, defaultBufSize))
+	for {
+		line := &StructuredLogLine{}
+		if err := decoder.Decode(line); err == io.EOF {
+			return true, nil
+		} else if err != nil {
+			return true, fmt.Errorf("decode structured logs %q: %w", name, err)
+		}
+		if !fn(line) {
+			return true, nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseANSISegments(t *testing.T) {
+	assert.Equal(t, []*LogSegment{}, ParseANSISegments(""))
+	assert.Equal(t, []*LogSegment{{Text: "plain"}}, ParseANSISegments("plain"))
+	assert.Equal(t, []*LogSegment{
+		{Text: "ok "},
+		{Text: "PASS", Foreground: "green", Bold: true},
+		{Text: " done", Bold: true},
+		{Text: "!"},
+	}, ParseANSISegments("ok \x1b[1;32mPASS\x1b[39m done\x1b[0m!"))
+	assert.Equal(t, []*LogSegment{
+		{Text: "a", Foreground: "#ff8700", Background: "bright-blue"},
+		{Text: "b", Foreground: "#0a141e", Background: "bright-blue", Underline: true},
+	}, ParseANSISegments("\x1b[38;5;208;104ma\x1b[38;2;10;20;30;4mb\x1b[m"))
+	// the other escape sequences are dropped
+	assert.Equal(t, []*LogSegment{{Text: "progress"}}, ParseANSISegments("\x1b[2Kprog\x1b]0;title\aress\x1b(B\x1b]8;;https://example.com\x1b\\\x1b["))
+}
+
+func TestParseStructuredLogs(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	var raw strings.Builder
+	for _, content := range []string{
+		"::group::Install \x1b[1mdeps\x1b[0m",
+		"npm ci",
+		"::warning file=package.json::deprecated",
+		"::endgroup::",
+		"##[error]build failed",
+		"::private::",
+		"secret host",
+		"##[group]Next step",
+		"still private",
+	} {
+		raw.WriteString(FormatLog(now, content) + "\n")
+	}
+
+	var lines []*StructuredLogLine
+	require.NoError(t, ParseStructuredLogs(strings.NewReader(raw.String()), container.SetOf[int64](0, 7), func(line *StructuredLogLine) bool {
+		lines = append(lines, line)
+		return true
+	}))
+	require.Len(t, lines, 9)
+
+	assert.Equal(t, &StructuredLogLine{
+		Index:    0,
+		Time:     now,
+		Type:     StructuredLogLineGroup,
+		Group:    1,
+		Segments: []*LogSegment{{Text: "Install "}, {Text: "deps", Bold: true}},
+	}, lines[0])
+	assert.Equal(t, int64(1), lines[1].Group)
+	assert.Equal(t, StructuredLogLevelWarning, lines[2].Level)
+	assert.Equal(t, []*LogSegment{{Text: "deprecated"}}, lines[2].Segments)
+	assert.Equal(t, StructuredLogLineEndGroup, lines[3].Type)
+	assert.Equal(t, int64(1), lines[3].Group)
+	assert.Equal(t, StructuredLogLevelError, lines[4].Level)
+	assert.Zero(t, lines[4].Group)
+	assert.False(t, lines[5].Private)
+	assert.True(t, lines[6].Private)
+	// the groups and the private sections end at the steps
+	assert.Equal(t, int64(2), lines[7].Group)
+	assert.False(t, lines[8].Private)
+
+	// stop early
+	lines = nil
+	require.NoError(t, ParseStructuredLogs(strings.NewReader(raw.String()), nil, func(line *StructuredLogLine) bool {
+		lines = append(lines, line)
+		return len(lines) < 2
+	}))
+	assert.Len(t, lines, 2)
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tokenPermissionScopes are the scopes of the `permissions` of workflows and jobs
+var tokenPermissionScopes = []string{
+	"actions", "attestations", "checks", "contents", "deployments", "discussions", "id-token", "issues",
+	"packages", "pages", "pull-requests", "repository-projects", "security-events", "statuses",
+}
+
+// ParseJobPermissions returns the `permissions` of the jobs of the workflow, keyed by job ids,
+// the `permissions` of a job replaces the one of the workflow. They're either "read-all", "write-all",
+// or a mapping of scopes to "read", "write" or "none", which are expanded to mappings of all scopes,
+// the scopes not in a mapping are "none". The jobs without permissions are omitted.
+func ParseJobPermissions(content []byte) (map[string]map[string]string, error) {
+	var raw struct {
+		Permissions yaml.Node `yaml:"permissions"`
+		Jobs        map[string]struct {
+			Permissions yaml.Node `yaml:"permissions"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	workflowPermissions, err := parsePermissions(&raw.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make(map[string]map[string]string, len(raw.Jobs))
+	for id, job := range raw.Jobs {
+		jobPermissions, err := parsePermissions(&job.Permissions)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", id, err)
+		}
+		if jobPermissions == nil {
+			jobPermissions = workflowPermissions
+		}
+		if jobPermissions != nil {
+			permissions[id] = jobPermissions
+		}
+	}
+	return permissions, nil
+}
+
+// parsePermissions parses the `permissions` node, it returns nil if it's not declared
+func parsePermissions(node *yaml.Node) (map[string]string, error) {
+	permissions := make(map[string]string, len(tokenPermissionScopes))
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.ScalarNode:
+		var level string
+		switch node.Value {
+		case "read-all":
+			level = "read"
+		case "write-all":
+			level = "write"
+		default:
+			return nil, fmt.Errorf("invalid permissions %q at line %d", node.Value, node.Line)
+		}
+		for _, scope := range tokenPermissionScopes {
+			permissions[scope] = level
+		}
+	case yaml.MappingNode:
+		var levels map[string]string
+		if err := node.Decode(&levels); err != nil {
+			return nil, fmt.Errorf("invalid permissions at line %d: %w", node.Line, err)
+		}
+		for _, scope := range tokenPermissionScopes {
+			permissions[scope] = "none"
+		}
+		for scope, level := range levels {
+			if _, ok := permissions[scope]; !ok {
+				return nil, fmt.Errorf("unknown permission scope %q at line %d", scope, node.Line)
+			}
+			if level != "read" && level != "write" && level != "none" {
+				return nil, fmt.Errorf("invalid permission %q of scope %q at line %d", level, scope, node.Line)
+			}
+			permissions[scope] = level
+		}
+	default:
+		return nil, fmt.Errorf("invalid permissions at line %d", node.Line)
+	}
+	return permissions, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJobPermissions(t *testing.T) {
+	permissions, err := ParseJobPermissions([]byte(`
+on: pull_request
+permissions: read-all
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make
+  label:
+    runs-on: ubuntu-latest
+    permissions:
+      pull-requests: write
+      contents: read
+    steps:
+      - run: make label
+  none:
+    runs-on: ubuntu-latest
+    permissions: {}
+    steps:
+      - run: make
+`))
+	require.NoError(t, err)
+	assert.Len(t, permissions, 3)
+	assert.Equal(t, "read", permissions["build"]["pull-requests"])
+	assert.Equal(t, "read", permissions["build"]["contents"])
+	assert.Equal(t, "write", permissions["label"]["pull-requests"])
+	assert.Equal(t, "read", permissions["label"]["contents"])
+	assert.Equal(t, "none", permissions["label"]["issues"])
+	assert.Equal(t, "none", permissions["none"]["pull-requests"])
+
+	permissions, err = ParseJobPermissions([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make
+`))
+	require.NoError(t, err)
+	assert.Empty(t, permissions)
+
+	for _, content := range []string{
+		"on: push\npermissions: write\njobs: {}\n",
+		"on: push\njobs:\n  build:\n    permissions:\n      pull-requests: admin\n",
+		"on: push\njobs:\n  build:\n    permissions:\n      unknown: read\n",
+	} {
+		_, err = ParseJobPermissions([]byte(content))
+		assert.Error(t, err, content)
+	}
+}
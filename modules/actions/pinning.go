@@ -0,0 +1,54 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"regexp"
+	"strings"
+)
+
+// a full commit SHA of SHA1 or SHA256
+var fullCommitSHAPattern = regexp.MustCompile(`^(?:[0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// IsRemoteAction returns whether the action of "uses" is fetched from a repository,
+// rather than a local action in the workspace or a docker image.
+func IsRemoteAction(uses string) bool {
+	return uses != "" && !strings.HasPrefix(uses, "./") && !strings.HasPrefix(uses, "docker://")
+}
+
+// IsActionPinned returns whether the remote action of "uses" is pinned to a full commit SHA,
+// which can't be moved like tags and branches.
+func IsActionPinned(uses string) bool {
+	if !IsRemoteAction(uses) {
+		return true
+	}
+	_, ref := SplitActionUses(uses)
+	return fullCommitSHAPattern.MatchString(ref)
+}
+
+// SplitActionUses splits the "uses" of a remote action into the name and the ref,
+// e.g. "actions/checkout" and "v4" for "actions/checkout@v4".
+func SplitActionUses(uses string) (name, ref string) {
+	idx := strings.LastIndex(uses, "@")
+	if idx < 0 {
+		return uses, ""
+	}
+	return uses[:idx], uses[idx+1:]
+}
+
+// ActionOwner returns the owner of the remote action of "uses" relative to baseURL,
+// e.g. "actions" for "actions/checkout@v4" or "https://gitea.com/actions/checkout@v4" with baseURL "https://gitea.com".
+// It returns an empty string if the action is an absolute URL of another site.
+func ActionOwner(uses, baseURL string) string {
+	name, _ := SplitActionUses(uses)
+	if strings.Contains(name, "://") {
+		prefix := strings.TrimSuffix(baseURL, "/") + "/"
+		if !strings.HasPrefix(name, prefix) {
+			return ""
+		}
+		name = strings.TrimPrefix(name, prefix)
+	}
+	owner, _, _ := strings.Cut(name, "/")
+	return owner
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsActionPinned(t *testing.T) {
+	cases := map[string]bool{
+		"actions/checkout@v4":      false,
+		"actions/checkout@main":    false,
+		"actions/checkout":         false,
+		"actions/checkout@b4ffde6": false,
+		"actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11":                        true,
+		"https://gitea.com/actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11":      true,
+		"https://gitea.com/actions/checkout@v4":                                            false,
+		"actions/aws/ec2@a7dcd6e1a9b0e2e0f2f5e02b2bd2e9b7c40c1b0a1c1c3d2e9e5f7a9b3c4d5e6f": true,
+		"./.gitea/actions/build": true,
+		"docker://alpine:3.20":   true,
+	}
+	for uses, pinned := range cases {
+		assert.Equal(t, pinned, IsActionPinned(uses), uses)
+	}
+}
+
+func TestActionOwner(t *testing.T) {
+	assert.Equal(t, "actions", ActionOwner("actions/checkout@v4", "https://gitea.example.com"))
+	assert.Equal(t, "org", ActionOwner("org/tools/build@v1", "https://gitea.example.com"))
+	assert.Equal(t, "org", ActionOwner("https://gitea.example.com/org/tools@v1", "https://gitea.example.com/"))
+	assert.Equal(t, "", ActionOwner("https://github.com/org/tools@v1", "https://gitea.example.com/"))
+}
+
+func TestSplitActionUses(t *testing.T) {
+	name, ref := SplitActionUses("actions/checkout@v4")
+	assert.Equal(t, "actions/checkout", name)
+	assert.Equal(t, "v4", ref)
+
+	name, ref = SplitActionUses("https://gitea.com/actions/setup-go@b4ffde65f46336ab88eb53be808477a3936bae11")
+	assert.Equal(t, "https://gitea.com/actions/setup-go", name)
+	assert.Equal(t, "b4ffde65f46336ab88eb53be808477a3936bae11", ref)
+
+	name, ref = SplitActionUses("actions/checkout")
+	assert.Equal(t, "actions/checkout", name)
+	assert.Empty(t, ref)
+}
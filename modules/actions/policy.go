@@ -0,0 +1,84 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"github.com/rhysd/actionlint"
+	"gopkg.in/yaml.v3"
+)
+
+// policyJobContext is the context of the definition of the job in the expressions of the policies
+const policyJobContext = "job"
+
+// EvaluatePolicy evaluates the expression of a policy against the definition of a job, and returns whether the job violates it.
+// The expression has the syntax of the expressions of the workflows, with the github context of the run,
+// and the job context of the definition of the job as it's written in the workflow, like `!job['timeout-minutes']`.
+func EvaluatePolicy(expr string, gitCtx *model.GithubContext, job *jobparser.Job) (bool, error) {
+	expr, err := rewritePolicyJobContext(expr)
+	if err != nil {
+		return false, err
+	}
+	definition, err := policyJobDefinition(job)
+	if err != nil {
+		return false, err
+	}
+	// the job context of the interpreter is the status of the job, so the definition is provided as the inputs context
+	interpreter := exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+		Github: gitCtx,
+		Inputs: definition,
+	}, exprparser.Config{Context: "job"})
+	result, err := interpreter.Evaluate(expr, exprparser.DefaultStatusCheckNone)
+	if err != nil {
+		return false, err
+	}
+	return exprparser.IsTruthy(result), nil
+}
+
+// rewritePolicyJobContext replaces the job context in the expression with the inputs context,
+// it returns an error if the expression is invalid
+func rewritePolicyJobContext(expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(expr, "${{"), "}}"))
+	if expr == "" {
+		return "", errors.New("the expression is empty")
+	}
+	tokens, _, lexErr := actionlint.LexExpression(expr + "}}")
+	if lexErr != nil {
+		return "", fmt.Errorf("invalid expression: %s", lexErr.Message)
+	}
+	var sb strings.Builder
+	last := 0
+	for i, token := range tokens {
+		// the properties named "job" like `github.job` aren't the context
+		if token.Kind != actionlint.TokenKindIdent || !strings.EqualFold(token.Value, policyJobContext) ||
+			(i > 0 && tokens[i-1].Kind == actionlint.TokenKindDot) {
+			continue
+		}
+		sb.WriteString(expr[last:token.Offset])
+		sb.WriteString("inputs")
+		last = token.Offset + len(token.Value)
+	}
+	sb.WriteString(expr[last:])
+	return sb.String(), nil
+}
+
+// policyJobDefinition returns the definition of the job with the keys written in the workflow, like "runs-on" and "timeout-minutes"
+func policyJobDefinition(job *jobparser.Job) (map[string]any, error) {
+	content, err := yaml.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	definition := map[string]any{}
+	if err := yaml.Unmarshal(content, &definition); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
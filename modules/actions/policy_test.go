@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	workflows, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  build:
+    runs-on: [self-hosted, linux]
+    timeout-minutes: 30
+    container:
+      image: node:18
+      options: --privileged
+    steps:
+      - uses: actions/checkout@v4
+`))
+	require.NoError(t, err)
+	_, job := workflows[0].Job()
+	gitCtx := &model.GithubContext{EventName: "push", Repository: "user5/repo4"}
+
+	cases := []struct {
+		expr     string
+		violated bool
+	}{
+		{`job['timeout-minutes'] == ''`, false},
+		{`${{ contains(job.container.options, '--privileged') }}`, true},
+		{`contains(job['runs-on'], 'self-hosted') && github.event_name == 'pull_request'`, false},
+		{`contains(job.steps.*.uses, 'actions/checkout@v4')`, true},
+		{`github.job == 'build'`, false},
+	}
+	for _, c := range cases {
+		violated, err := EvaluatePolicy(c.expr, gitCtx, job)
+		require.NoError(t, err, c.expr)
+		assert.Equal(t, c.violated, violated, c.expr)
+	}
+
+	_, err = EvaluatePolicy(`job.name ==`, gitCtx, job)
+	assert.Error(t, err)
+	_, err = EvaluatePolicy(`  `, gitCtx, job)
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowRequirements are the secrets and variables a workflow requires to run.
+// They are declared in a "requires" block of the leading comments of the workflow file,
+// so the workflow is still valid for other platforms, e.g.
+//
+//	# requires:
+//	#   secrets: [DEPLOY_KEY, NPM_TOKEN]
+//	#   vars: REGISTRY_URL
+type WorkflowRequirements struct {
+	Secrets []string
+	Vars    []string
+}
+
+// IsEmpty returns whether the workflow requires nothing
+func (r *WorkflowRequirements) IsEmpty() bool {
+	return r == nil || len(r.Secrets) == 0 && len(r.Vars) == 0
+}
+
+// ParseWorkflowRequirements parses the requirements declared in the leading comments of the workflow content,
+// it returns nil if there is no requirements block.
+func ParseWorkflowRequirements(content []byte) (*WorkflowRequirements, error) {
+	block, ok, err := leadingCommentBlock(content, "requires")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var raw struct {
+		Secrets yaml.Node `yaml:"secrets"`
+		Vars    yaml.Node `yaml:"vars"`
+	}
+	if err := yaml.Unmarshal(block, &raw); err != nil {
+		return nil, fmt.Errorf("invalid requires block: %w", err)
+	}
+	secrets, err := parseRequiredNames(&raw.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid required secrets: %w", err)
+	}
+	vars, err := parseRequiredNames(&raw.Vars)
+	if err != nil {
+		return nil, fmt.Errorf("invalid required vars: %w", err)
+	}
+	return &WorkflowRequirements{Secrets: secrets, Vars: vars}, nil
+}
+
+// parseRequiredNames accepts both a sequence and a comma separated string, the names are case-insensitive
+func parseRequiredNames(node *yaml.Node) ([]string, error) {
+	var names []string
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.ScalarNode:
+		names = strings.Split(node.Value, ",")
+	case yaml.SequenceNode:
+		if err := node.Decode(&names); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("expect a list of names at line %d", node.Line)
+	}
+
+	ret := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.ToUpper(strings.TrimSpace(name)); name != "" {
+			ret = append(ret, name)
+		}
+	}
+	return ret, nil
+}
+
+// leadingCommentBlock returns the content of the block of the name in the leading comments of the workflow content,
+// the block is the indented comment lines following a "# name:" line, ok is false if there is no such block.
+func leadingCommentBlock(content []byte, name string) (block []byte, ok bool, err error) {
+	var lines []string
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" && !inBlock {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		text := strings.TrimPrefix(strings.TrimPrefix(line, "#"), " ")
+		if !inBlock {
+			inBlock = strings.TrimSpace(text) == name+":"
+			continue
+		}
+		if text == "" || !strings.HasPrefix(text, " ") {
+			break
+		}
+		lines = append(lines, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
+	return []byte(strings.Join(lines, "\n")), inBlock, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkflowRequirements(t *testing.T) {
+	requirements, err := ParseWorkflowRequirements([]byte(`
+# Deploy the site
+#
+# requires:
+#   secrets: [deploy_key, NPM_TOKEN]
+#   vars: REGISTRY_URL, region
+#
+# other comments
+name: deploy
+on: push
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &WorkflowRequirements{
+		Secrets: []string{"DEPLOY_KEY", "NPM_TOKEN"},
+		Vars:    []string{"REGISTRY_URL", "REGION"},
+	}, requirements)
+
+	requirements, err = ParseWorkflowRequirements([]byte(`name: deploy
+# requires:
+#   secrets: [DEPLOY_KEY]
+on: push
+`))
+	require.NoError(t, err)
+	assert.Nil(t, requirements)
+
+	_, err = ParseWorkflowRequirements([]byte(`# requires:
+#   secrets: {key: value}
+on: push
+`))
+	assert.Error(t, err)
+}
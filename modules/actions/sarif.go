@@ -0,0 +1,135 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// MaxSARIFResults is how many results a SARIF upload could have, the tools reporting more are likely misconfigured
+const MaxSARIFResults = 10000
+
+// sarifLog is the subset of a SARIF 2.1.0 log which the code scanning alerts are made of,
+// see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name string `json:"name"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine int `json:"startLine"`
+						EndLine   int `json:"endLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			PartialFingerprints map[string]string `json:"partialFingerprints"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// ParseSARIF parses the results of a SARIF 2.1.0 log into the code scanning results.
+// The results are deduplicated by their fingerprints, which are stable across the runs as long as the findings don't change,
+// they are made of the partial fingerprints reported by the tools, or the rules, the paths and the messages if there aren't any.
+func ParseSARIF(r io.Reader) (*actions_model.CodeScanningUpload, error) {
+	var doc sarifLog
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid SARIF: %w", err)
+	}
+	if doc.Version != "2.1.0" {
+		return nil, fmt.Errorf("unsupported SARIF version %q, it should be 2.1.0", doc.Version)
+	}
+
+	upload := &actions_model.CodeScanningUpload{}
+	seen := make(map[string]bool)
+	for _, run := range doc.Runs {
+		tool := strings.TrimSpace(run.Tool.Driver.Name)
+		if tool == "" {
+			return nil, errors.New("invalid SARIF: the name of the tool is missing")
+		} else if len(tool) > 255 {
+			return nil, fmt.Errorf("invalid SARIF: the name of the tool %q is too long", tool[:32])
+		}
+		if !slices.Contains(upload.Tools, tool) {
+			upload.Tools = append(upload.Tools, tool)
+		}
+		for _, res := range run.Results {
+			result := &actions_model.CodeScanningResult{
+				Tool:     tool,
+				RuleID:   res.RuleID,
+				Severity: sarifSeverity(res.Level),
+				Message:  res.Message.Text,
+			}
+			if len(res.Locations) > 0 {
+				loc := res.Locations[0].PhysicalLocation
+				result.Path = strings.TrimPrefix(loc.ArtifactLocation.URI, "file://")
+				result.StartLine = loc.Region.StartLine
+				result.EndLine = max(loc.Region.EndLine, loc.Region.StartLine)
+			}
+			result.Fingerprint = sarifFingerprint(result, res.PartialFingerprints)
+			if seen[result.Fingerprint] {
+				continue
+			}
+			seen[result.Fingerprint] = true
+			upload.Results = append(upload.Results, result)
+			if len(upload.Results) > MaxSARIFResults {
+				return nil, fmt.Errorf("too many SARIF results, at most %d are allowed", MaxSARIFResults)
+			}
+		}
+	}
+	return upload, nil
+}
+
+// sarifSeverity maps the level of a SARIF result to the severity of the alert, the default level of SARIF is warning
+func sarifSeverity(level string) string {
+	switch level {
+	case actions_model.CodeScanningSeverityError, actions_model.CodeScanningSeverityNote:
+		return level
+	case "none":
+		return actions_model.CodeScanningSeverityNote
+	default:
+		return actions_model.CodeScanningSeverityWarning
+	}
+}
+
+func sarifFingerprint(result *actions_model.CodeScanningResult, partialFingerprints map[string]string) string {
+	parts := []string{result.Tool, result.RuleID}
+	if len(partialFingerprints) > 0 {
+		keys := make([]string, 0, len(partialFingerprints))
+		for k := range partialFingerprints {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = append(parts, k+"="+partialFingerprints[k])
+		}
+	} else {
+		// the lines aren't included, so the findings are still the same ones when the code around them changes
+		parts = append(parts, result.Path, result.Message)
+	}
+	h := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(h[:])
+}
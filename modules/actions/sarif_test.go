@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSARIF(t *testing.T) {
+	upload, err := ParseSARIF(strings.NewReader(`{
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {"driver": {"name": "gosec"}},
+      "results": [
+        {
+          "ruleId": "G101",
+          "level": "error",
+          "message": {"text": "hardcoded credentials"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 10}}}]
+        },
+        {
+          "ruleId": "G101",
+          "level": "error",
+          "message": {"text": "hardcoded credentials"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 20, "endLine": 22}}}]
+        },
+        {
+          "ruleId": "G104",
+          "message": {"text": "errors unhandled"},
+          "partialFingerprints": {"primaryLocationLineHash": "abc"}
+        }
+      ]
+    },
+    {
+      "tool": {"driver": {"name": "semgrep"}},
+      "results": []
+    }
+  ]
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gosec", "semgrep"}, upload.Tools)
+	// the results of the same rule, path and message are the same finding
+	require.Len(t, upload.Results, 2)
+	assert.Equal(t, "G101", upload.Results[0].RuleID)
+	assert.Equal(t, actions_model.CodeScanningSeverityError, upload.Results[0].Severity)
+	assert.Equal(t, "main.go", upload.Results[0].Path)
+	assert.Equal(t, 10, upload.Results[0].StartLine)
+	assert.Equal(t, 10, upload.Results[0].EndLine)
+	assert.Len(t, upload.Results[0].Fingerprint, 64)
+	assert.Equal(t, actions_model.CodeScanningSeverityWarning, upload.Results[1].Severity)
+	assert.NotEqual(t, upload.Results[0].Fingerprint, upload.Results[1].Fingerprint)
+
+	_, err = ParseSARIF(strings.NewReader(`{"version": "2.0.0", "runs": []}`))
+	assert.Error(t, err)
+	_, err = ParseSARIF(strings.NewReader(`{"version": "2.1.0", "runs": [{"tool": {"driver": {}}}]}`))
+	assert.Error(t, err)
+	_, err = ParseSARIF(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
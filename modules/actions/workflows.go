@@ -5,11 +5,14 @@ package actions
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 
@@ -43,14 +46,25 @@ func IsWorkflow(path string) bool {
 	return strings.HasPrefix(path, ".gitea/workflows") || strings.HasPrefix(path, ".github/workflows")
 }
 
-func ListWorkflows(commit *git.Commit) (git.Entries, error) {
-	tree, err := commit.SubTree(".gitea/workflows")
-	if _, ok := err.(git.ErrNotExist); ok {
-		tree, err = commit.SubTree(".github/workflows")
+// GetWorkflowsDir returns the directory of the workflows in the commit, ".gitea/workflows" takes precedence over ".github/workflows".
+// It returns an empty string if there is no workflows directory.
+func GetWorkflowsDir(commit *git.Commit) (string, error) {
+	for _, dir := range []string{".gitea/workflows", ".github/workflows"} {
+		if _, err := commit.SubTree(dir); err == nil {
+			return dir, nil
+		} else if _, ok := err.(git.ErrNotExist); !ok {
+			return "", err
+		}
 	}
-	if _, ok := err.(git.ErrNotExist); ok {
-		return nil, nil
+	return "", nil
+}
+
+func ListWorkflows(commit *git.Commit) (git.Entries, error) {
+	dir, err := GetWorkflowsDir(commit)
+	if err != nil || dir == "" {
+		return nil, err
 	}
+	tree, err := commit.SubTree(dir)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +109,20 @@ func GetEventsFromContent(content []byte) ([]*jobparser.Event, error) {
 	return events, nil
 }
 
+// IsEventBlocked returns whether the event like "issue_comment" couldn't trigger the workflows of the instance, see [actions] BLOCKED_EVENTS
+func IsEventBlocked(name string) bool {
+	return slices.Contains(setting.Actions.BlockedEvents, strings.ToLower(name))
+}
+
+// RejectedWorkflow is a workflow which couldn't be triggered by the event
+type RejectedWorkflow struct {
+	EntryName string
+	// the workflow is invalid, so it's unknown whether it would be triggered,
+	// otherwise it matches the event but the event is blocked by the instance, see IsEventBlocked
+	Invalid bool
+	Err     error
+}
+
 func DetectWorkflows(
 	gitRepo *git.Repository,
 	commit *git.Commit,
@@ -102,28 +130,46 @@ func DetectWorkflows(
 	payload api.Payloader,
 	detectSchedule bool,
 ) ([]*DetectedWorkflow, []*DetectedWorkflow, error) {
+	workflows, schedules, _, err := DetectWorkflowsWithRejected(gitRepo, commit, triggedEvent, payload, detectSchedule)
+	return workflows, schedules, err
+}
+
+// DetectWorkflowsWithRejected is DetectWorkflows which also returns the workflows that couldn't be triggered by the event,
+// the invalid ones and the ones matching the events blocked by the instance
+func DetectWorkflowsWithRejected(
+	gitRepo *git.Repository,
+	commit *git.Commit,
+	triggedEvent webhook_module.HookEventType,
+	payload api.Payloader,
+	detectSchedule bool,
+) (workflows, schedules []*DetectedWorkflow, rejected []*RejectedWorkflow, err error) {
 	entries, err := ListWorkflows(commit)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	workflows := make([]*DetectedWorkflow, 0, len(entries))
-	schedules := make([]*DetectedWorkflow, 0, len(entries))
+	workflows = make([]*DetectedWorkflow, 0, len(entries))
+	schedules = make([]*DetectedWorkflow, 0, len(entries))
 	for _, entry := range entries {
 		content, err := GetContentFromEntry(entry)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		// one workflow may have multiple events
 		events, err := GetEventsFromContent(content)
 		if err != nil {
 			log.Warn("ignore invalid workflow %q: %v", entry.Name(), err)
+			rejected = append(rejected, &RejectedWorkflow{EntryName: entry.Name(), Invalid: true, Err: err})
 			continue
 		}
 		for _, evt := range events {
 			log.Trace("detect workflow %q for event %#v matching %q", entry.Name(), evt, triggedEvent)
 			if evt.IsSchedule() {
+				if IsEventBlocked(evt.Name) {
+					log.Trace("ignore workflow %q for blocked event %q", entry.Name(), evt.Name)
+					continue
+				}
 				if detectSchedule {
 					dwf := &DetectedWorkflow{
 						EntryName:    entry.Name(),
@@ -133,6 +179,11 @@ func DetectWorkflows(
 					schedules = append(schedules, dwf)
 				}
 			} else if detectMatched(gitRepo, commit, triggedEvent, payload, evt) {
+				if IsEventBlocked(evt.Name) {
+					log.Trace("ignore workflow %q for blocked event %q", entry.Name(), evt.Name)
+					rejected = append(rejected, &RejectedWorkflow{EntryName: entry.Name(), Err: fmt.Errorf("event %q is blocked by the instance", evt.Name)})
+					continue
+				}
 				dwf := &DetectedWorkflow{
 					EntryName:    entry.Name(),
 					TriggerEvent: evt,
@@ -143,10 +194,14 @@ func DetectWorkflows(
 		}
 	}
 
-	return workflows, schedules, nil
+	return workflows, schedules, rejected, nil
 }
 
 func DetectScheduledWorkflows(gitRepo *git.Repository, commit *git.Commit) ([]*DetectedWorkflow, error) {
+	if IsEventBlocked(string(webhook_module.HookEventSchedule)) {
+		return nil, nil
+	}
+
 	entries, err := ListWorkflows(commit)
 	if err != nil {
 		return nil, err
@@ -199,6 +254,11 @@ func detectMatched(gitRepo *git.Repository, commit *git.Commit, triggedEvent web
 		// no special filter parameters for these events, just return true if name matched
 		return true
 
+	case // workflow_dispatch
+		webhook_module.HookEventWorkflowDispatch:
+		// the inputs aren't filters, they're checked when the workflow is dispatched
+		return true
+
 	case // push
 		webhook_module.HookEventPush:
 		return matchPushEvent(commit, payload.(*api.PushPayload), evt)
@@ -249,7 +309,24 @@ func detectMatched(gitRepo *git.Repository, commit *git.Commit, triggedEvent web
 	}
 }
 
+// MatchPushEvent reports whether the push matches the push event of a workflow,
+// and whether it matches the branches and tags filters but not the paths filters if it doesn't match.
+func MatchPushEvent(commit *git.Commit, pushPayload *api.PushPayload, evt *jobparser.Event) (matched, filteredByPaths bool) {
+	if !canGithubEventMatch(evt.Name, webhook_module.HookEventPush) {
+		return false, false
+	}
+	if matchPushEvent(commit, pushPayload, evt) {
+		return true, false
+	}
+	return false, matchPushEventFilters(commit, pushPayload, evt, true)
+}
+
 func matchPushEvent(commit *git.Commit, pushPayload *api.PushPayload, evt *jobparser.Event) bool {
+	return matchPushEventFilters(commit, pushPayload, evt, false)
+}
+
+// matchPushEventFilters matches the push with the filters of the event, the paths filters are regarded as matched if ignorePaths
+func matchPushEventFilters(commit *git.Commit, pushPayload *api.PushPayload, evt *jobparser.Event, ignorePaths bool) bool {
 	// with no special filter parameters
 	if len(evt.Acts()) == 0 {
 		return true
@@ -311,6 +388,10 @@ func matchPushEvent(commit *git.Commit, pushPayload *api.PushPayload, evt *jobpa
 				matchTimes++
 			}
 		case "paths":
+			if ignorePaths {
+				matchTimes++
+				break
+			}
 			filesChanged, err := commit.GetFilesChangedSinceCommit(pushPayload.Before)
 			if err != nil {
 				log.Error("GetFilesChangedSinceCommit [commit_sha1: %s]: %v", commit.ID.String(), err)
@@ -324,6 +405,10 @@ func matchPushEvent(commit *git.Commit, pushPayload *api.PushPayload, evt *jobpa
 				}
 			}
 		case "paths-ignore":
+			if ignorePaths {
+				matchTimes++
+				break
+			}
 			filesChanged, err := commit.GetFilesChangedSinceCommit(pushPayload.Before)
 			if err != nil {
 				log.Error("GetFilesChangedSinceCommit [commit_sha1: %s]: %v", commit.ID.String(), err)
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 
 	"github.com/stretchr/testify/assert"
@@ -136,3 +138,12 @@ func TestDetectMatched(t *testing.T) {
 		})
 	}
 }
+
+func TestIsEventBlocked(t *testing.T) {
+	assert.False(t, IsEventBlocked("schedule"))
+
+	defer test.MockVariableValue(&setting.Actions.BlockedEvents, []string{"issue_comment", "schedule"})()
+	assert.True(t, IsEventBlocked("issue_comment"))
+	assert.True(t, IsEventBlocked("Schedule"))
+	assert.False(t, IsEventBlocked("push"))
+}
@@ -4,6 +4,10 @@
 package badge
 
 import (
+	"html/template"
+	"regexp"
+	"strings"
+
 	actions_model "code.gitea.io/gitea/models/actions"
 )
 
@@ -12,8 +16,9 @@ import (
 // Then scale down to normal size in tmpl file
 
 type Label struct {
-	text  string
-	width int
+	text   string
+	width  int
+	offset int // the width of the logo before the text
 }
 
 func (l Label) Text() string {
@@ -25,11 +30,11 @@ func (l Label) Width() int {
 }
 
 func (l Label) TextLength() int {
-	return int(float64(l.width-defaultOffset) * 9.5)
+	return int(float64(l.width-l.offset-defaultOffset) * 9.5)
 }
 
 func (l Label) X() int {
-	return l.width*5 + 10
+	return (l.width+l.offset)*5 + 10
 }
 
 type Message struct {
@@ -55,23 +60,119 @@ func (m Message) TextLength() int {
 }
 
 type Badge struct {
-	Color    string
-	FontSize int
-	Label    Label
-	Message  Message
+	Color      string
+	LabelColor string
+	Style      string
+	Logo       template.URL // the data URI of the logo before the label, empty if there is none
+	FontSize   int
+	Label      Label
+	Message    Message
 }
 
 func (b Badge) Width() int {
 	return b.Label.width + b.Message.width
 }
 
+func (b Badge) Height() int {
+	if b.Style == StylePlastic {
+		return 18
+	}
+	return 20
+}
+
+// Radius is the radius of the corners
+func (b Badge) Radius() int {
+	switch b.Style {
+	case StyleFlat:
+		return 3
+	case StyleFlatSquare:
+		return 0
+	}
+	return 4
+}
+
+// TextY is the y of the texts in 10x scale, the shadows are 10 lower
+func (b Badge) TextY() int {
+	return b.Height()*10/2 + 40
+}
+
+func (b Badge) ShadowY() int {
+	return b.TextY() + 10
+}
+
+func (b Badge) LogoY() int {
+	return (b.Height() - logoSize) / 2
+}
+
+const (
+	defaultOffset     = 9
+	defaultFontSize   = 11
+	DefaultColor      = "#9f9f9f" // Grey
+	DefaultLabelColor = "#555"
+	defaultFontWidth  = 7 // approximate speculation
+	logoSize          = 14
+	logoOffset        = logoSize + 3
+)
+
+// The styles of the badges, like the ones of shields.io
 const (
-	defaultOffset    = 9
-	defaultFontSize  = 11
-	DefaultColor     = "#9f9f9f" // Grey
-	defaultFontWidth = 7         // approximate speculation
+	StylePlastic    = "plastic" // the default
+	StyleFlat       = "flat"
+	StyleFlatSquare = "flat-square"
 )
 
+// IsValidStyle returns whether the style is supported
+func IsValidStyle(style string) bool {
+	return style == StylePlastic || style == StyleFlat || style == StyleFlatSquare
+}
+
+// namedColors are the named colors of shields.io
+var namedColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"yellowgreen": "#a4a61d",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"grey":        "#555",
+	"gray":        "#555",
+	"lightgrey":   "#9f9f9f",
+	"lightgray":   "#9f9f9f",
+}
+
+var hexColorPattern = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// NormalizeColor returns the color in "#rgb" or "#rrggbb" form,
+// the color could be a named color like "blue" or a hex color with or without "#"
+func NormalizeColor(color string) (string, bool) {
+	if c, ok := namedColors[strings.ToLower(color)]; ok {
+		return c, true
+	}
+	if !hexColorPattern.MatchString(color) {
+		return "", false
+	}
+	return "#" + strings.TrimPrefix(color, "#"), true
+}
+
+// MaxLogoSize is the max length of the data URI of a logo
+const MaxLogoSize = 32 * 1024
+
+var logoPattern = regexp.MustCompile(`^data:image/(svg\+xml|png);base64,[A-Za-z0-9+/]+={0,2}$`)
+
+// IsValidLogo returns whether the logo is the base64 data URI of a SVG or PNG image no longer than MaxLogoSize,
+// the badges are rendered as images so they couldn't load the logos from other URLs
+func IsValidLogo(logo string) bool {
+	return len(logo) <= MaxLogoSize && logoPattern.MatchString(logo)
+}
+
+// Options are the styles of a badge, the defaults are used for the empty ones
+type Options struct {
+	Style      string
+	LabelColor string
+	Logo       string // the data URI of the logo, it's ignored if it isn't valid
+}
+
 var StatusColorMap = map[actions_model.Status]string{
 	actions_model.StatusSuccess:   "#4c1",    // Green
 	actions_model.StatusSkipped:   "#dfb317", // Yellow
@@ -85,20 +186,40 @@ var StatusColorMap = map[actions_model.Status]string{
 
 // GenerateBadge generates badge with given template
 func GenerateBadge(label, message, color string) Badge {
-	lw := defaultFontWidth*len(label) + defaultOffset
+	return GenerateStyledBadge(label, message, color, Options{})
+}
+
+// GenerateStyledBadge generates badge with given template in the style
+func GenerateStyledBadge(label, message, color string, opts Options) Badge {
+	b := Badge{
+		Color:      color,
+		LabelColor: DefaultLabelColor,
+		Style:      StylePlastic,
+		FontSize:   defaultFontSize * 10,
+	}
+	if IsValidStyle(opts.Style) {
+		b.Style = opts.Style
+	}
+	if c, ok := NormalizeColor(opts.LabelColor); ok {
+		b.LabelColor = c
+	}
+	offset := 0
+	if opts.Logo != "" && IsValidLogo(opts.Logo) {
+		b.Logo = template.URL(opts.Logo) //nolint:gosec // it's a validated data URI of an image
+		offset = logoOffset
+	}
+
+	lw := defaultFontWidth*len(label) + defaultOffset + offset
 	mw := defaultFontWidth*len(message) + defaultOffset
-	x := lw*10 + mw*5 - 10
-	return Badge{
-		Label: Label{
-			text:  label,
-			width: lw,
-		},
-		Message: Message{
-			text:  message,
-			width: mw,
-			x:     x,
-		},
-		FontSize: defaultFontSize * 10,
-		Color:    color,
+	b.Label = Label{
+		text:   label,
+		width:  lw,
+		offset: offset,
+	}
+	b.Message = Message{
+		text:  message,
+		width: mw,
+		x:     lw*10 + mw*5 - 10,
 	}
+	return b
 }
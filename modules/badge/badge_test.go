@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package badge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeColor(t *testing.T) {
+	for color, expected := range map[string]string{
+		"blue":    "#007ec6",
+		"Green":   "#97ca00",
+		"#333":    "#333",
+		"00ff00":  "#00ff00",
+		"#AbCdEf": "#AbCdEf",
+	} {
+		c, ok := NormalizeColor(color)
+		assert.True(t, ok, color)
+		assert.Equal(t, expected, c, color)
+	}
+	for _, color := range []string{"", "purple", "#12", "#1234567", "red;fill:url(x)"} {
+		_, ok := NormalizeColor(color)
+		assert.False(t, ok, color)
+	}
+}
+
+func TestIsValidLogo(t *testing.T) {
+	assert.True(t, IsValidLogo("data:image/svg+xml;base64,PHN2Zz48L3N2Zz4="))
+	assert.True(t, IsValidLogo("data:image/png;base64,iVBORw0KGgo="))
+	assert.False(t, IsValidLogo("https://example.com/logo.svg"))
+	assert.False(t, IsValidLogo("data:image/gif;base64,R0lGODlh"))
+	assert.False(t, IsValidLogo(`data:image/png;base64,iVBO"/><script>`))
+}
+
+func TestGenerateStyledBadge(t *testing.T) {
+	b := GenerateBadge("build", "success", "#4c1")
+	assert.Equal(t, StylePlastic, b.Style)
+	assert.Equal(t, DefaultLabelColor, b.LabelColor)
+	assert.Equal(t, 18, b.Height())
+	assert.Empty(t, b.Logo)
+
+	styled := GenerateStyledBadge("build", "success", "#4c1", Options{
+		Style:      StyleFlatSquare,
+		LabelColor: "blue",
+		Logo:       "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=",
+	})
+	assert.Equal(t, StyleFlatSquare, styled.Style)
+	assert.Equal(t, "#007ec6", styled.LabelColor)
+	assert.Equal(t, 20, styled.Height())
+	assert.Equal(t, 0, styled.Radius())
+	assert.NotEmpty(t, styled.Logo)
+	assert.Equal(t, b.Width()+logoOffset, styled.Width())
+
+	// the invalid options are ignored
+	b = GenerateStyledBadge("build", "success", "#4c1", Options{Style: "3d", LabelColor: "invalid", Logo: "https://example.com/logo.svg"})
+	assert.Equal(t, StylePlastic, b.Style)
+	assert.Equal(t, DefaultLabelColor, b.LabelColor)
+	assert.Empty(t, b.Logo)
+}
@@ -316,3 +316,33 @@ func (repo *Repository) CreateBundle(ctx context.Context, commit string, out io.
 	_, err = io.Copy(out, fi)
 	return err
 }
+
+// CreateCommitPack writes a pack of the commit and its ancestors to the depth to the target, 0 for all the ancestors.
+// The objects are filtered by the partial clone filter if it's not empty, like "blob:none", which should be validated by the caller.
+func (repo *Repository) CreateCommitPack(ctx context.Context, commit string, depth int, filter string, out io.Writer) error {
+	revList := NewCommand(ctx, "rev-list", "--objects")
+	if depth > 0 {
+		revList.AddOptionFormat("--max-count=%d", depth)
+	}
+	if filter != "" {
+		revList.AddOptionFormat("--filter=%s", filter)
+	}
+	revList.AddDynamicArguments(commit)
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	go func() {
+		stderr := new(bytes.Buffer)
+		if err := revList.Run(&RunOpts{Dir: repo.Path, Stdout: writer, Stderr: stderr}); err != nil {
+			_ = writer.CloseWithError(ConcatenateError(err, stderr.String()))
+			return
+		}
+		_ = writer.Close()
+	}()
+
+	stderr := new(bytes.Buffer)
+	if err := NewCommand(ctx, "pack-objects", "--stdout", "-q").Run(&RunOpts{Dir: repo.Path, Stdin: reader, Stdout: out, Stderr: stderr}); err != nil {
+		return ConcatenateError(err, stderr.String())
+	}
+	return nil
+}
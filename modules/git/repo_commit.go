@@ -9,6 +9,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/setting"
@@ -52,6 +53,23 @@ func (repo *Repository) GetTagCommit(name string) (*Commit, error) {
 	return repo.GetCommit(commitID)
 }
 
+// GetCommitBefore returns the commit the ref pointed to at the time, following the first parents,
+// by the committer dates, so the rebased or cherry-picked commits are dated when they were committed.
+func (repo *Repository) GetCommitBefore(ref string, before time.Time) (*Commit, error) {
+	stdout, _, err := NewCommand(repo.Ctx, "rev-list", "--max-count=1", "--first-parent").
+		AddOptionFormat("--before=%s", before.UTC().Format(time.RFC3339)).
+		AddDynamicArguments(ref).AddDashesAndList().
+		RunStdString(&RunOpts{Dir: repo.Path})
+	if err != nil {
+		return nil, err
+	}
+	commitID := strings.TrimSpace(stdout)
+	if commitID == "" {
+		return nil, ErrNotExist{ID: ref}
+	}
+	return repo.GetCommit(commitID)
+}
+
 func (repo *Repository) getCommitByPathWithID(id ObjectID, relpath string) (*Commit, error) {
 	// File name starts with ':' must be escaped.
 	if relpath[0] == ':' {
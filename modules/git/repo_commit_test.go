@@ -6,6 +6,7 @@ package git
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -64,6 +65,24 @@ func TestGetCommitWithBadCommitID(t *testing.T) {
 	assert.True(t, IsErrNotExist(err))
 }
 
+func TestGetCommitBefore(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := openRepositoryWithDefaultContext(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	commit, err := bareRepo1.GetCommitBefore("master", time.Date(2018, 4, 19, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1", commit.ID.String())
+
+	commit, err = bareRepo1.GetCommitBefore("master", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "ce064814f4a0d337b333e646ece456cd39fab612", commit.ID.String())
+
+	_, err = bareRepo1.GetCommitBefore("master", time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, IsErrNotExist(err))
+}
+
 func TestIsCommitInBranch(t *testing.T) {
 	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
 	bareRepo1, err := openRepositoryWithDefaultContext(bareRepo1Path)
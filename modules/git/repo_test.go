@@ -4,11 +4,15 @@
 package git
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"io"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetLatestCommitTime(t *testing.T) {
@@ -54,3 +58,24 @@ func TestRepoGetDivergingCommits(t *testing.T) {
 		Behind: 2,
 	}, do)
 }
+
+func TestRepoCreateCommitPack(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := openRepositoryWithDefaultContext(bareRepo1Path)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// the number of the objects is in the header of the pack after the signature and the version
+	countObjects := func(depth int, filter string) uint32 {
+		var buf bytes.Buffer
+		require.NoError(t, repo.CreateCommitPack(DefaultContext, "ce064814f4a0d337b333e646ece456cd39fab612", depth, filter, &buf))
+		require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("PACK")))
+		return binary.BigEndian.Uint32(buf.Bytes()[8:12])
+	}
+	all := countObjects(0, "")
+	shallow := countObjects(1, "")
+	assert.Less(t, shallow, all)
+	assert.Less(t, countObjects(1, "blob:none"), shallow)
+
+	assert.Error(t, repo.CreateCommitPack(DefaultContext, "0000000000000000000000000000000000000000", 1, "", io.Discard))
+}
@@ -45,7 +45,7 @@ func ServeContentWithCacheControl(w http.ResponseWriter, req *http.Request, name
 func HandleGenericETagCache(req *http.Request, w http.ResponseWriter, etag string) (handled bool) {
 	if len(etag) > 0 {
 		w.Header().Set("Etag", etag)
-		if checkIfNoneMatchIsValid(req, etag) {
+		if CheckIfNoneMatchIsValid(req, etag) {
 			w.WriteHeader(http.StatusNotModified)
 			return true
 		}
@@ -54,8 +54,8 @@ func HandleGenericETagCache(req *http.Request, w http.ResponseWriter, etag strin
 	return false
 }
 
-// checkIfNoneMatchIsValid tests if the header If-None-Match matches the ETag
-func checkIfNoneMatchIsValid(req *http.Request, etag string) bool {
+// CheckIfNoneMatchIsValid tests if the header If-None-Match matches the ETag
+func CheckIfNoneMatchIsValid(req *http.Request, etag string) bool {
 	ifNoneMatch := req.Header.Get("If-None-Match")
 	if len(ifNoneMatch) > 0 {
 		for _, item := range strings.Split(ifNoneMatch, ",") {
@@ -79,7 +79,7 @@ func HandleGenericETagTimeCache(req *http.Request, w http.ResponseWriter, etag s
 	}
 
 	if len(etag) > 0 {
-		if checkIfNoneMatchIsValid(req, etag) {
+		if CheckIfNoneMatchIsValid(req, etag) {
 			w.WriteHeader(http.StatusNotModified)
 			return true
 		}
@@ -9,6 +9,7 @@ import (
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -41,6 +42,7 @@ type Collector struct {
 	Releases           *prometheus.Desc
 	Repositories       *prometheus.Desc
 	Stars              *prometheus.Desc
+	StorageHealthy     *prometheus.Desc
 	Teams              *prometheus.Desc
 	UpdateTasks        *prometheus.Desc
 	Users              *prometheus.Desc
@@ -171,6 +173,11 @@ func NewCollector() Collector {
 			"Number of Stars",
 			nil, nil,
 		),
+		StorageHealthy: prometheus.NewDesc(
+			namespace+"storage_healthy",
+			"Whether the latest health check of the storage passed",
+			[]string{"storage"}, nil,
+		),
 		Teams: prometheus.NewDesc(
 			namespace+"teams",
 			"Number of Teams",
@@ -224,6 +231,7 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Releases
 	ch <- c.Repositories
 	ch <- c.Stars
+	ch <- c.StorageHealthy
 	ch <- c.Teams
 	ch <- c.UpdateTasks
 	ch <- c.Users
@@ -360,6 +368,18 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Star),
 	)
+	for _, status := range storage.GetHealthStatuses() {
+		healthy := 0.0
+		if status.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.StorageHealthy,
+			prometheus.GaugeValue,
+			healthy,
+			status.Name,
+		)
+	}
 	ch <- prometheus.MustNewConstMetric(
 		c.Teams,
 		prometheus.GaugeValue,
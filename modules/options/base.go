@@ -36,6 +36,11 @@ func License(name string) ([]byte, error) {
 	return AssetFS().ReadFile("license", name)
 }
 
+// Workflow reads the content of a specific workflow template from static/bindata or custom path.
+func Workflow(name string) ([]byte, error) {
+	return AssetFS().ReadFile("workflow", name)
+}
+
 // Labels reads the content of a specific labels from static/bindata or custom path.
 func Labels(name string) ([]byte, error) {
 	return AssetFS().ReadFile("label", name)
@@ -5,6 +5,7 @@ package private
 
 import (
 	"context"
+	"time"
 
 	"code.gitea.io/gitea/modules/setting"
 )
@@ -23,3 +24,59 @@ func GenerateActionsRunnerToken(ctx context.Context, scope string) (*ResponseTex
 
 	return requestJSONResp(req, &ResponseText{})
 }
+
+// BackfillActionRunsRequest is the request to synthesize push events for the existing tags and commits of a repository
+type BackfillActionRunsRequest struct {
+	Repo   string // {owner}/{repo}
+	Doer   string // the name of the user who pushes the tags and commits
+	Tags   []string
+	Branch string
+	Since  string
+	Until  string
+}
+
+// BackfillActionRuns calls the internal BackfillActionRuns function
+func BackfillActionRuns(ctx context.Context, opts *BackfillActionRunsRequest) (*ResponseText, ResponseExtra) {
+	reqURL := setting.LocalURL + "api/internal/actions/backfill"
+
+	req := newInternalRequest(ctx, reqURL, "POST", opts)
+
+	return requestJSONResp(req, &ResponseText{})
+}
+
+// ReplayActionRunsRequest is the request to replay the events of the runs of a repository
+type ReplayActionRunsRequest struct {
+	Repo  string // {owner}/{repo}
+	Run   int64  // the number of the run to replay, the latest runs are replayed if it's 0
+	Limit int    // how many of the latest runs are replayed
+}
+
+// ReplayActionRuns calls the internal ReplayActionRuns function
+func ReplayActionRuns(ctx context.Context, opts *ReplayActionRunsRequest) (*ResponseText, ResponseExtra) {
+	reqURL := setting.LocalURL + "api/internal/actions/replay"
+
+	req := newInternalRequest(ctx, reqURL, "POST", opts)
+
+	return requestJSONResp(req, &ResponseText{})
+}
+
+// ActionsLoadTestRequest is the request to benchmark Actions with synthetic runs picked by fake runners
+type ActionsLoadTestRequest struct {
+	Repo       string // {owner}/{repo}
+	Doer       string // the name of the user who triggers the synthetic runs
+	Runs       int
+	JobsPerRun int
+	Runners    int
+	LogLines   int
+	Keep       bool
+}
+
+// RunActionsLoadTest calls the internal RunActionsLoadTest function
+func RunActionsLoadTest(ctx context.Context, opts *ActionsLoadTestRequest) (*ResponseText, ResponseExtra) {
+	reqURL := setting.LocalURL + "api/internal/actions/load-test"
+
+	req := newInternalRequest(ctx, reqURL, "POST", opts)
+	req.SetTimeout(10*time.Second, 0) // a load test could take a long time
+
+	return requestJSONResp(req, &ResponseText{})
+}
@@ -35,6 +35,9 @@ var (
 	// Readmes contains the readme files
 	Readmes []string
 
+	// Workflows contains the workflow template files
+	Workflows []string
+
 	// LabelTemplateFiles contains the label template files, each item has its DisplayName and Description
 	LabelTemplateFiles   []OptionFile
 	labelTemplateFileMap = map[string]string{} // DisplayName => FileName mapping
@@ -69,7 +72,7 @@ func mergeCustomLabelFiles(fl optionFileList) []string {
 
 // LoadRepoConfig loads the repository config
 func LoadRepoConfig() error {
-	types := []string{"gitignore", "license", "readme", "label"} // option file directories
+	types := []string{"gitignore", "license", "readme", "label", "workflow"} // option file directories
 	typeFiles := make([]optionFileList, len(types))
 	for i, t := range types {
 		var err error
@@ -90,6 +93,12 @@ func LoadRepoConfig() error {
 	Gitignores = typeFiles[0].all
 	Licenses = typeFiles[1].all
 	Readmes = typeFiles[2].all
+	Workflows = typeFiles[4].all
+	for _, name := range setting.Actions.DefaultWorkflows {
+		if !util.SliceContainsString(Workflows, name) {
+			log.Error("[actions] DEFAULT_WORKFLOWS: workflow template %q doesn't exist in options/workflow", name)
+		}
+	}
 
 	// Load label templates
 	LabelTemplateFiles = nil
@@ -5,6 +5,7 @@ package setting
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,22 +15,203 @@ import (
 // Actions settings
 var (
 	Actions = struct {
-		LogStorage            *Storage // how the created logs should be stored
-		ArtifactStorage       *Storage // how the created artifacts should be stored
-		ArtifactRetentionDays int64    `ini:"ARTIFACT_RETENTION_DAYS"`
+		LogStorage            *Storage      // how the created logs should be stored
+		ArtifactStorage       *Storage      // how the created artifacts should be stored
+		ArtifactRetentionDays int64         `ini:"ARTIFACT_RETENTION_DAYS"`
+		ArtifactNameCollision string        `ini:"ARTIFACT_NAME_COLLISION"` // what happens when an artifact is uploaded with the name of an existing one of the run
+		DownloadURLExpiry     time.Duration `ini:"DOWNLOAD_URL_EXPIRY"`     // how long the signed URLs to download the artifacts and logs are valid
+		LogRetentionDays      int64         `ini:"LOG_RETENTION_DAYS"`
+		RunRetentionDays      int64         `ini:"RUN_RETENTION_DAYS"` // 0 means the runs are kept forever
 		Enabled               bool
-		DefaultActionsURL     defaultActionsURL `ini:"DEFAULT_ACTIONS_URL"`
-		ZombieTaskTimeout     time.Duration     `ini:"ZOMBIE_TASK_TIMEOUT"`
-		EndlessTaskTimeout    time.Duration     `ini:"ENDLESS_TASK_TIMEOUT"`
-		AbandonedJobTimeout   time.Duration     `ini:"ABANDONED_JOB_TIMEOUT"`
-		SkipWorkflowStrings   []string          `ìni:"SKIP_WORKFLOW_STRINGS"`
+		DefaultActionsURL     defaultActionsURL  `ini:"DEFAULT_ACTIONS_URL"`
+		ZombieTaskTimeout     time.Duration      `ini:"ZOMBIE_TASK_TIMEOUT"`
+		EndlessTaskTimeout    time.Duration      `ini:"ENDLESS_TASK_TIMEOUT"`
+		AbandonedJobTimeout   time.Duration      `ini:"ABANDONED_JOB_TIMEOUT"`
+		StuckJobTimeout       time.Duration      `ini:"STUCK_JOB_TIMEOUT"`
+		RunnerAffinityTimeout time.Duration      `ini:"RUNNER_AFFINITY_TIMEOUT"`
+		RunnerAffinityWindow  time.Duration      `ini:"RUNNER_AFFINITY_WINDOW"`
+		PreflightChecks       []string           `ini:"PREFLIGHT_CHECKS"`
+		PlatformImages        map[string]string  `ini:"-"`
+		RunnerLabelWeights    map[string]float64 `ini:"-"`
+		DefaultWorkflows      []string           `ini:"DEFAULT_WORKFLOWS"`
+		DefaultWorkflowsMode  string             `ini:"DEFAULT_WORKFLOWS_MODE"`
+		SkipWorkflowStrings   []string           `ìni:"SKIP_WORKFLOW_STRINGS"`
+		RequirePinnedActions  bool               `ini:"REQUIRE_PINNED_ACTIONS"`
+		MaxRunsPerMinute      int64              `ini:"MAX_RUNS_PER_MINUTE"` // the most runs the events of a repository could create in a minute, 0 means unlimited
+		CoalescePushes        bool               `ini:"COALESCE_PUSHES"`     // skip the push events whose commits are no longer the heads of their branches
+		// the most runs of a repository which could be queued, the oldest queued runs not of the default branch beyond it are cancelled, 0 means unlimited
+		MaxQueuedRunsPerRepo int64 `ini:"MAX_QUEUED_RUNS_PER_REPO"`
+		// the events like "issue_comment" and "schedule" which couldn't trigger the workflows of the instance
+		BlockedEvents []string `ini:"BLOCKED_EVENTS"`
+		// the runners with the labels only pick jobs in the windows, the jobs requiring the labels wait or are picked by the other runners out of them
+		RunnerActiveHours map[string]TimeWindow `ini:"-"`
+		// the label of the runners which may disappear at any time, the jobs lost with them are retried on the other runners, empty to disable
+		SpotRunnerLabel string `ini:"SPOT_RUNNER_LABEL"`
+		// the restrictions of the sandboxes of the runners with the labels, they're sent with the tasks,
+		// and the runners don't pick the jobs which declare requirements violating them
+		RunnerSandboxPolicies map[string][]string `ini:"-"`
+		// the limits of the secrets and variables provided to every job, the runs whose jobs would exceed them fail when they are created, 0 means unlimited
+		MaxJobSecrets       int64  `ini:"MAX_JOB_SECRETS"`
+		MaxJobSecretsSize   int64  `ini:"MAX_JOB_SECRETS_SIZE"` // the total bytes of the names and values
+		MaxJobVariables     int64  `ini:"MAX_JOB_VARIABLES"`
+		MaxJobVariablesSize int64  `ini:"MAX_JOB_VARIABLES_SIZE"` // the total bytes of the names and values
+		LeakedSecrets       string `ini:"LEAKED_SECRETS"`         // what happens when the value of a secret is found in the logs
+		TokenRateLimit      int64  `ini:"TOKEN_RATE_LIMIT"`       // the most API requests the token of a task could make in a minute, 0 means unlimited
+		// the most bytes of the definition of a job sent to the runners, the runs whose jobs would exceed it fail when they are created, 0 means unlimited
+		MaxJobPayloadSize int64 `ini:"MAX_JOB_PAYLOAD_SIZE"`
+		// the events whose successful runs are counted as the automation activity on the heatmaps, empty to disable the automation activity
+		AutomationActivityEvents []string `ini:"AUTOMATION_ACTIVITY_EVENTS"`
+		StatusAccess             string   `ini:"STATUS_ACCESS"`       // who could see the status of Actions of the instance, one of the ActionsStatusAccess* values
+		MaintenanceMessage       string   `ini:"MAINTENANCE_MESSAGE"` // announced by the status of Actions, empty if there is no maintenance
+		// the longest a FetchTask request of a runner asking to wait is held open until a task could be assigned to it, 0 answers at once
+		FetchTaskMaxWait time.Duration `ini:"FETCH_TASK_MAX_WAIT"`
+		// the most log rows accepted by a request of a runner uploading logs, the rows beyond it are uploaded again by the runner, 0 means unlimited
+		LogUploadMaxRows int64 `ini:"LOG_UPLOAD_MAX_ROWS"`
+		// how many of the latest finished runs of the workflow on the same ref the hints of the jobs of a run are drawn from, 0 disables the hints
+		JobHintsSampleRuns int64 `ini:"JOB_HINTS_SAMPLE_RUNS"`
 	}{
 		Enabled:             true,
 		DefaultActionsURL:   defaultActionsURLGitHub,
 		SkipWorkflowStrings: []string{"[skip ci]", "[ci skip]", "[no ci]", "[skip actions]", "[actions skip]"},
+		MaxJobSecretsSize:   1024 * 1024,
+		MaxJobVariablesSize: 1024 * 1024,
+		MaxJobPayloadSize:   1024 * 1024,
+		LeakedSecrets:       LeakedSecretsFlag,
+		TokenRateLimit:      1000,
+		SpotRunnerLabel:     "spot",
+		LogUploadMaxRows:    5000,
 	}
 )
 
+// ActionsEvents settings of publishing the lifecycle events of runs and jobs to a message queue
+var ActionsEvents = struct {
+	Publisher string // empty to disable publishing, or one of the ActionsEventsPublisher* values
+	ConnStr   string
+	Subject   string // the prefix of the NATS subjects, or the key of the Redis stream
+}{
+	Subject: "gitea.actions",
+}
+
+// ActionsAlerts settings of alerting the admins when the jobs wait too long for runners
+var ActionsAlerts = struct {
+	QueueWaitThresholds  map[string]time.Duration `ini:"-"` // the longest a job with the label could wait, "*" for all jobs
+	QueueDepthThresholds map[string]int64         `ini:"-"` // the most jobs with the label which could be waiting, "*" for all jobs
+	WebhookURL           string                   // where the alerts are posted to besides the system notices, empty to disable
+}{}
+
+// ActionsHooks settings of the hooks of the external systems called before the runs start and after they're done
+var ActionsHooks = struct {
+	PreRunURL  string        `ini:"PRE_RUN_URL"`  // called before the jobs of a run are queued, it could veto the run, empty to disable
+	PostRunURL string        `ini:"POST_RUN_URL"` // called after a run is done, it could annotate the run, empty to disable
+	Secret     string        // the key of the HMAC-SHA256 signatures of the payloads, empty to not sign them
+	Timeout    time.Duration // how long a hook could take
+	FailOpen   bool          `ini:"FAIL_OPEN"` // whether the runs start if the pre-run hook fails, they fail the preflight checks otherwise
+}{
+	Timeout: 10 * time.Second,
+}
+
+// ActionsPolicy is a policy of the admins which the jobs must comply with when the runs are created, see actions.EvaluatePolicy.
+// It's configured by a section like [actions.policies.require-timeout].
+type ActionsPolicy struct {
+	Name    string
+	Deny    string // the expression on the definition of a job, the job violates the policy if it's true
+	Message string // why the jobs violating the policy fail
+}
+
+// ActionsPolicies are the policies of the admins, the jobs violating any of them fail the preflight checks
+var ActionsPolicies []*ActionsPolicy
+
+// QueueAlertsAllLabels is the label of the thresholds of all the waiting jobs
+const QueueAlertsAllLabels = "*"
+
+// Publishers of the lifecycle events of runs and jobs
+const (
+	ActionsEventsPublisherNATS  = "nats"
+	ActionsEventsPublisherRedis = "redis"
+)
+
+// Preflight checks which validate the jobs of a run before dispatching them
+const (
+	PreflightCheckSecrets      = "secrets"       // the secrets referenced by the jobs exist
+	PreflightCheckRunnerLabels = "runner_labels" // there are runners with the labels required by the jobs
+	PreflightCheckEnvironments = "environments"  // the environments referenced by the jobs exist
+)
+
+// Restrictions of the sandbox policies of the runners, see Actions.RunnerSandboxPolicies
+const (
+	SandboxNoPrivileged     = "no_privileged"       // the job container and services couldn't be privileged
+	SandboxNoDockerInDocker = "no_docker_in_docker" // the job container and services couldn't run Docker-in-Docker or mount the Docker socket
+	SandboxNoServices       = "no_services"         // the job couldn't have service containers
+	SandboxNoHostNetwork    = "no_host_network"     // the job container and services couldn't use the network of the host
+	SandboxReadOnlyNetwork  = "read_only_network"   // the network is read-only, it's only enforced by the runners
+)
+
+// Policies of uploading an artifact with the name of an existing artifact of the run
+const (
+	ArtifactNameCollisionReject    = "reject"    // reject the upload, like GitHub Actions
+	ArtifactNameCollisionOverwrite = "overwrite" // replace the existing artifact
+	ArtifactNameCollisionSuffix    = "suffix"    // upload the artifact with a numeric suffix, like "name-2"
+)
+
+// Policies of the secrets whose values are found verbatim in the logs, the repository admins are alerted unless they are ignored
+const (
+	LeakedSecretsIgnore = "ignore" // don't scan the logs for the secrets
+	LeakedSecretsFlag   = "flag"   // record the leak and flag the secret for rotation
+	LeakedSecretsDelete = "delete" // record the leak and delete the secret, so the following runs never get it
+)
+
+// Who could see the status of Actions of the instance
+const (
+	ActionsStatusAccessPublic   = "public"    // everyone, including the anonymous users
+	ActionsStatusAccessSignedIn = "signed_in" // the signed-in users
+	ActionsStatusAccessDisabled = "disabled"  // nobody, the status isn't served
+)
+
+// Modes of adding the default workflows to new repositories
+const (
+	DefaultWorkflowsModeCommit  = "commit"  // commit the workflows with the initial commit
+	DefaultWorkflowsModeSuggest = "suggest" // suggest the workflows on the actions page
+)
+
+// TimeWindow is a window of the times of a day in DefaultUILocation, it spans midnight if End is before Start
+type TimeWindow struct {
+	Start time.Duration // since midnight, inclusive
+	End   time.Duration // since midnight, exclusive
+}
+
+// parseTimeWindow parses a window like "22:00-06:00"
+func parseTimeWindow(s string) (TimeWindow, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q, it should be like 22:00-06:00", s)
+	}
+	var w TimeWindow
+	for _, v := range []struct {
+		s string
+		d *time.Duration
+	}{{start, &w.Start}, {end, &w.End}} {
+		t, err := time.Parse("15:04", strings.TrimSpace(v.s))
+		if err != nil {
+			return TimeWindow{}, fmt.Errorf("invalid time %q of time window %q: %w", v.s, s, err)
+		}
+		*v.d = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if w.Start == w.End {
+		return TimeWindow{}, fmt.Errorf("empty time window %q", s)
+	}
+	return w, nil
+}
+
+// Contains returns whether the time is in the window
+func (w TimeWindow) Contains(t time.Time) bool {
+	t = t.In(DefaultUILocation)
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return d >= w.Start && d < w.End
+	}
+	return d >= w.Start || d < w.End
+}
+
 type defaultActionsURL string
 
 func (url defaultActionsURL) URL() string {
@@ -82,15 +264,192 @@ func loadActionsFrom(rootCfg ConfigProvider) error {
 	actionsSec, _ := rootCfg.GetSection("actions.artifacts")
 
 	Actions.ArtifactStorage, err = getStorage(rootCfg, "actions_artifacts", "", actionsSec)
+	if err != nil {
+		return err
+	}
 
 	// default to 90 days in Github Actions
 	if Actions.ArtifactRetentionDays <= 0 {
 		Actions.ArtifactRetentionDays = 90
 	}
+	// default to 1 year, only the logs are deleted, the runs, jobs and tasks are kept
+	if Actions.LogRetentionDays <= 0 {
+		Actions.LogRetentionDays = 365
+	}
+	if Actions.RunRetentionDays < 0 {
+		Actions.RunRetentionDays = 0
+	}
 
 	Actions.ZombieTaskTimeout = sec.Key("ZOMBIE_TASK_TIMEOUT").MustDuration(10 * time.Minute)
 	Actions.EndlessTaskTimeout = sec.Key("ENDLESS_TASK_TIMEOUT").MustDuration(3 * time.Hour)
 	Actions.AbandonedJobTimeout = sec.Key("ABANDONED_JOB_TIMEOUT").MustDuration(24 * time.Hour)
+	Actions.StuckJobTimeout = sec.Key("STUCK_JOB_TIMEOUT").MustDuration(time.Hour)
+	Actions.RunnerAffinityTimeout = sec.Key("RUNNER_AFFINITY_TIMEOUT").MustDuration(0)
+	Actions.RunnerAffinityWindow = sec.Key("RUNNER_AFFINITY_WINDOW").MustDuration(24 * time.Hour)
+	Actions.DownloadURLExpiry = sec.Key("DOWNLOAD_URL_EXPIRY").MustDuration(15 * time.Minute)
+	Actions.FetchTaskMaxWait = sec.Key("FETCH_TASK_MAX_WAIT").MustDuration(30 * time.Second)
+
+	Actions.PlatformImages = map[string]string{}
+	for _, pair := range sec.Key("PLATFORM_IMAGES").Strings(",") {
+		label, image, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(label) == "" || strings.TrimSpace(image) == "" {
+			log.Error("[actions] PLATFORM_IMAGES: invalid pair %q, it should be like label=image", pair)
+			continue
+		}
+		Actions.PlatformImages[strings.TrimSpace(label)] = strings.TrimSpace(image)
+	}
+
+	Actions.RunnerLabelWeights = map[string]float64{}
+	for _, pair := range sec.Key("RUNNER_LABEL_WEIGHTS").Strings(",") {
+		label, weight, ok := strings.Cut(pair, "=")
+		w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if !ok || strings.TrimSpace(label) == "" || err != nil || w < 0 {
+			log.Error("[actions] RUNNER_LABEL_WEIGHTS: invalid pair %q, it should be like label=weight", pair)
+			continue
+		}
+		Actions.RunnerLabelWeights[strings.TrimSpace(label)] = w
+	}
+
+	Actions.RunnerActiveHours = map[string]TimeWindow{}
+	for _, pair := range sec.Key("RUNNER_ACTIVE_HOURS").Strings(",") {
+		label, window, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(label) == "" {
+			log.Error("[actions] RUNNER_ACTIVE_HOURS: invalid pair %q, it should be like label=22:00-06:00", pair)
+			continue
+		}
+		w, err := parseTimeWindow(window)
+		if err != nil {
+			log.Error("[actions] RUNNER_ACTIVE_HOURS: %v", err)
+			continue
+		}
+		Actions.RunnerActiveHours[strings.TrimSpace(label)] = w
+	}
+
+	Actions.RunnerSandboxPolicies = map[string][]string{}
+	for _, pair := range sec.Key("RUNNER_SANDBOX_POLICIES").Strings(",") {
+		label, restriction, ok := strings.Cut(pair, "=")
+		label, restriction = strings.TrimSpace(label), strings.TrimSpace(restriction)
+		if !ok || label == "" {
+			log.Error("[actions] RUNNER_SANDBOX_POLICIES: invalid pair %q, it should be like label=no_privileged", pair)
+			continue
+		}
+		switch restriction {
+		case SandboxNoPrivileged, SandboxNoDockerInDocker, SandboxNoServices, SandboxNoHostNetwork, SandboxReadOnlyNetwork:
+			Actions.RunnerSandboxPolicies[label] = append(Actions.RunnerSandboxPolicies[label], restriction)
+		default:
+			log.Error("[actions] RUNNER_SANDBOX_POLICIES: unknown restriction %q", restriction)
+		}
+	}
+
+	switch Actions.LeakedSecrets {
+	case "":
+		Actions.LeakedSecrets = LeakedSecretsFlag
+	case LeakedSecretsIgnore, LeakedSecretsFlag, LeakedSecretsDelete:
+	default:
+		return fmt.Errorf("unsupported [actions] LEAKED_SECRETS: %q", Actions.LeakedSecrets)
+	}
+
+	switch Actions.ArtifactNameCollision {
+	case "":
+		Actions.ArtifactNameCollision = ArtifactNameCollisionReject
+	case ArtifactNameCollisionReject, ArtifactNameCollisionOverwrite, ArtifactNameCollisionSuffix:
+	default:
+		return fmt.Errorf("unsupported [actions] ARTIFACT_NAME_COLLISION: %q", Actions.ArtifactNameCollision)
+	}
+
+	switch Actions.StatusAccess {
+	case "":
+		Actions.StatusAccess = ActionsStatusAccessPublic
+	case ActionsStatusAccessPublic, ActionsStatusAccessSignedIn, ActionsStatusAccessDisabled:
+	default:
+		return fmt.Errorf("unsupported [actions] STATUS_ACCESS: %q", Actions.StatusAccess)
+	}
+
+	switch Actions.DefaultWorkflowsMode {
+	case "":
+		Actions.DefaultWorkflowsMode = DefaultWorkflowsModeCommit
+	case DefaultWorkflowsModeCommit, DefaultWorkflowsModeSuggest:
+	default:
+		return fmt.Errorf("unsupported [actions] DEFAULT_WORKFLOWS_MODE: %q", Actions.DefaultWorkflowsMode)
+	}
+
+	if err := rootCfg.Section("actions.events").MapTo(&ActionsEvents); err != nil {
+		return fmt.Errorf("failed to map Actions events settings: %v", err)
+	}
+	switch ActionsEvents.Publisher {
+	case "":
+	case ActionsEventsPublisherNATS, ActionsEventsPublisherRedis:
+		if ActionsEvents.ConnStr == "" {
+			return fmt.Errorf("[actions.events] CONN_STR is required by PUBLISHER %q", ActionsEvents.Publisher)
+		}
+	default:
+		return fmt.Errorf("unsupported [actions.events] PUBLISHER: %q", ActionsEvents.Publisher)
+	}
+
+	if err := rootCfg.Section("actions.hooks").MapTo(&ActionsHooks); err != nil {
+		return fmt.Errorf("failed to map Actions hooks settings: %v", err)
+	}
+	if ActionsHooks.Timeout <= 0 {
+		ActionsHooks.Timeout = 10 * time.Second
+	}
+
+	ActionsPolicies = nil
+	for _, policySec := range rootCfg.Section("actions.policies").ChildSections() {
+		name := strings.TrimPrefix(policySec.Name(), "actions.policies.")
+		deny := strings.TrimSpace(policySec.Key("DENY").String())
+		if deny == "" {
+			log.Error("[%s] DENY is required, the policy is ignored", policySec.Name())
+			continue
+		}
+		ActionsPolicies = append(ActionsPolicies, &ActionsPolicy{
+			Name:    name,
+			Deny:    deny,
+			Message: policySec.Key("MESSAGE").MustString(fmt.Sprintf("the job violates the policy %q", name)),
+		})
+	}
+
+	alertsSec := rootCfg.Section("actions.alerts")
+	ActionsAlerts.WebhookURL = alertsSec.Key("WEBHOOK_URL").String()
+	ActionsAlerts.QueueWaitThresholds = map[string]time.Duration{}
+	for _, pair := range alertsSec.Key("QUEUE_WAIT_THRESHOLDS").Strings(",") {
+		label, threshold, ok := strings.Cut(pair, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(threshold))
+		if !ok || strings.TrimSpace(label) == "" || err != nil || d <= 0 {
+			log.Error("[actions.alerts] QUEUE_WAIT_THRESHOLDS: invalid pair %q, it should be like label=15m", pair)
+			continue
+		}
+		ActionsAlerts.QueueWaitThresholds[strings.TrimSpace(label)] = d
+	}
+	ActionsAlerts.QueueDepthThresholds = map[string]int64{}
+	for _, pair := range alertsSec.Key("QUEUE_DEPTH_THRESHOLDS").Strings(",") {
+		label, threshold, ok := strings.Cut(pair, "=")
+		n, err := strconv.ParseInt(strings.TrimSpace(threshold), 10, 64)
+		if !ok || strings.TrimSpace(label) == "" || err != nil || n <= 0 {
+			log.Error("[actions.alerts] QUEUE_DEPTH_THRESHOLDS: invalid pair %q, it should be like label=50", pair)
+			continue
+		}
+		ActionsAlerts.QueueDepthThresholds[strings.TrimSpace(label)] = n
+	}
+
+	Actions.AutomationActivityEvents = nil
+	for _, event := range sec.Key("AUTOMATION_ACTIVITY_EVENTS").Strings(",") {
+		Actions.AutomationActivityEvents = append(Actions.AutomationActivityEvents, strings.ToLower(event))
+	}
+
+	Actions.BlockedEvents = nil
+	for _, event := range sec.Key("BLOCKED_EVENTS").Strings(",") {
+		Actions.BlockedEvents = append(Actions.BlockedEvents, strings.ToLower(event))
+	}
+
+	Actions.PreflightChecks = nil
+	for _, check := range sec.Key("PREFLIGHT_CHECKS").Strings(",") {
+		switch check {
+		case PreflightCheckSecrets, PreflightCheckRunnerLabels, PreflightCheckEnvironments:
+			Actions.PreflightChecks = append(Actions.PreflightChecks, check)
+		default:
+			log.Error("[actions] PREFLIGHT_CHECKS: unknown check %q", check)
+		}
+	}
 
 	return err
 }
@@ -6,6 +6,7 @@ package setting
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,6 +96,36 @@ STORAGE_TYPE = minio
 	assert.EqualValues(t, "actions_log", filepath.Base(Actions.LogStorage.Path))
 	assert.EqualValues(t, "local", Actions.ArtifactStorage.Type)
 	assert.EqualValues(t, "actions_artifacts", filepath.Base(Actions.ArtifactStorage.Path))
+
+	// the logs and the artifacts could be stored by different backends independently
+	iniStr = `
+[storage.actions_log]
+STORAGE_TYPE = cheap_s3
+
+[storage.cheap_s3]
+STORAGE_TYPE = minio
+MINIO_BUCKET = logs
+
+[storage.actions_artifacts]
+STORAGE_TYPE = local
+PATH = /mnt/fast/artifacts
+`
+	cfg, err = NewConfigProviderFromData(iniStr)
+	assert.NoError(t, err)
+	assert.NoError(t, loadActionsFrom(cfg))
+
+	assert.EqualValues(t, "minio", Actions.LogStorage.Type)
+	assert.EqualValues(t, "logs", Actions.LogStorage.MinioConfig.Bucket)
+	assert.EqualValues(t, "local", Actions.ArtifactStorage.Type)
+	assert.EqualValues(t, "/mnt/fast/artifacts", Actions.ArtifactStorage.Path)
+
+	iniStr = `
+[storage.actions_artifacts]
+STORAGE_TYPE = missing_storage
+`
+	cfg, err = NewConfigProviderFromData(iniStr)
+	assert.NoError(t, err)
+	assert.Error(t, loadActionsFrom(cfg))
 }
 
 func Test_getDefaultActionsURLForActions(t *testing.T) {
@@ -179,3 +210,204 @@ DEFAULT_ACTIONS_URL = gitea
 		})
 	}
 }
+
+func Test_loadActionsEventsFrom(t *testing.T) {
+	oldActions, oldActionsEvents := Actions, ActionsEvents
+	defer func() {
+		Actions, ActionsEvents = oldActions, oldActionsEvents
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions.events]
+PUBLISHER = nats
+CONN_STR = nats://127.0.0.1:4222
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Equal(t, ActionsEventsPublisherNATS, ActionsEvents.Publisher)
+	assert.Equal(t, "nats://127.0.0.1:4222", ActionsEvents.ConnStr)
+	assert.Equal(t, "gitea.actions", ActionsEvents.Subject)
+
+	ActionsEvents = oldActionsEvents
+	cfg, err = NewConfigProviderFromData(`
+[actions.events]
+PUBLISHER = redis
+`)
+	require.NoError(t, err)
+	assert.Error(t, loadActionsFrom(cfg))
+
+	cfg, err = NewConfigProviderFromData(`
+[actions.events]
+PUBLISHER = kafka
+CONN_STR = 127.0.0.1:9092
+`)
+	require.NoError(t, err)
+	assert.Error(t, loadActionsFrom(cfg))
+}
+
+func Test_loadActionsAlertsFrom(t *testing.T) {
+	oldActions, oldActionsAlerts := Actions, ActionsAlerts
+	defer func() {
+		Actions, ActionsAlerts = oldActions, oldActionsAlerts
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions.alerts]
+QUEUE_WAIT_THRESHOLDS = ubuntu-latest=15m, *=1h, gpu=soon
+QUEUE_DEPTH_THRESHOLDS = gpu=10, macos=-1
+WEBHOOK_URL = https://alerts.example.com/hook
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Equal(t, map[string]time.Duration{"ubuntu-latest": 15 * time.Minute, "*": time.Hour}, ActionsAlerts.QueueWaitThresholds)
+	assert.Equal(t, map[string]int64{"gpu": 10}, ActionsAlerts.QueueDepthThresholds)
+	assert.Equal(t, "https://alerts.example.com/hook", ActionsAlerts.WebhookURL)
+}
+
+func Test_loadActionsBlockedEventsFrom(t *testing.T) {
+	oldActions := Actions
+	defer func() {
+		Actions = oldActions
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions]
+BLOCKED_EVENTS = Issue_Comment, schedule
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Equal(t, []string{"issue_comment", "schedule"}, Actions.BlockedEvents)
+
+	cfg, err = NewConfigProviderFromData(``)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Empty(t, Actions.BlockedEvents)
+}
+
+func Test_loadActionsRunnerActiveHoursFrom(t *testing.T) {
+	oldActions := Actions
+	defer func() {
+		Actions = oldActions
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions]
+RUNNER_ACTIVE_HOURS = office=22:00-06:00, gpu=09:30-17:00, nightly=25:00-01:00, broken, empty=08:00-08:00
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Equal(t, map[string]TimeWindow{
+		"office": {Start: 22 * time.Hour, End: 6 * time.Hour},
+		"gpu":    {Start: 9*time.Hour + 30*time.Minute, End: 17 * time.Hour},
+	}, Actions.RunnerActiveHours)
+}
+
+func Test_loadActionsLogRetentionDaysFrom(t *testing.T) {
+	oldActions := Actions
+	defer func() {
+		Actions = oldActions
+	}()
+
+	// the logs are kept for a year by default
+	cfg, err := NewConfigProviderFromData(``)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.EqualValues(t, 365, Actions.LogRetentionDays)
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+LOG_RETENTION_DAYS = 30
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.EqualValues(t, 30, Actions.LogRetentionDays)
+}
+
+func Test_loadActionsPlatformImagesFrom(t *testing.T) {
+	oldActions := Actions
+	defer func() {
+		Actions = oldActions
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions]
+PLATFORM_IMAGES = ubuntu-latest=docker.gitea.com/runner-images:ubuntu-latest, gpu = registry.example.com/cuda:12 , broken, =empty, macos=
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Equal(t, map[string]string{
+		"ubuntu-latest": "docker.gitea.com/runner-images:ubuntu-latest",
+		"gpu":           "registry.example.com/cuda:12",
+	}, Actions.PlatformImages)
+
+	cfg, err = NewConfigProviderFromData(``)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Empty(t, Actions.PlatformImages)
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	oldLocation := DefaultUILocation
+	defer func() {
+		DefaultUILocation = oldLocation
+	}()
+	DefaultUILocation = time.UTC
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.UTC)
+	}
+	day := TimeWindow{Start: 9 * time.Hour, End: 17 * time.Hour}
+	assert.True(t, day.Contains(at(9, 0)))
+	assert.True(t, day.Contains(at(16, 59)))
+	assert.False(t, day.Contains(at(17, 0)))
+	assert.False(t, day.Contains(at(8, 59)))
+
+	night := TimeWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	assert.True(t, night.Contains(at(22, 0)))
+	assert.True(t, night.Contains(at(0, 0)))
+	assert.True(t, night.Contains(at(5, 59)))
+	assert.False(t, night.Contains(at(6, 0)))
+	assert.False(t, night.Contains(at(12, 0)))
+	// the times are in DefaultUILocation
+	assert.True(t, night.Contains(time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("UTC+12", 12*3600))))
+}
+
+func Test_loadActionsRunnerSandboxPoliciesFrom(t *testing.T) {
+	oldActions := Actions
+	defer func() {
+		Actions = oldActions
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions]
+RUNNER_SANDBOX_POLICIES = office=no_docker_in_docker, office=read_only_network, gpu=no_services, gpu=no_root, broken
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Equal(t, map[string][]string{
+		"office": {SandboxNoDockerInDocker, SandboxReadOnlyNetwork},
+		"gpu":    {SandboxNoServices},
+	}, Actions.RunnerSandboxPolicies)
+}
+
+func Test_loadActionsPoliciesFrom(t *testing.T) {
+	defer func() {
+		ActionsPolicies = nil
+	}()
+
+	cfg, err := NewConfigProviderFromData(`
+[actions.policies.require-timeout]
+DENY = !job['timeout-minutes']
+MESSAGE = the jobs must set timeout-minutes
+[actions.policies.no-privileged]
+DENY = contains(job.container.options, '--privileged')
+[actions.policies.broken]
+MESSAGE = no expression
+`)
+	require.NoError(t, err)
+	require.NoError(t, loadActionsFrom(cfg))
+	assert.Equal(t, []*ActionsPolicy{
+		{Name: "require-timeout", Deny: "!job['timeout-minutes']", Message: "the jobs must set timeout-minutes"},
+		{Name: "no-privileged", Deny: "contains(job.container.options, '--privileged')", Message: `the job violates the policy "no-privileged"`},
+	}, ActionsPolicies)
+}
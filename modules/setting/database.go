@@ -27,6 +27,7 @@ var (
 	Database = struct {
 		Type               DatabaseType
 		Host               string
+		ReplicaHost        string // the host of the read replica, the heavy read-only queries are routed to it if it's set
 		Name               string
 		User               string
 		Passwd             string
@@ -62,6 +63,7 @@ func loadDBSetting(rootCfg ConfigProvider) {
 	Database.Type = DatabaseType(sec.Key("DB_TYPE").String())
 
 	Database.Host = sec.Key("HOST").String()
+	Database.ReplicaHost = sec.Key("REPLICA_HOST").String()
 	Database.Name = sec.Key("NAME").String()
 	Database.User = sec.Key("USER").String()
 	if len(Database.Passwd) == 0 {
@@ -93,6 +95,18 @@ func loadDBSetting(rootCfg ConfigProvider) {
 
 // DBConnStr returns database connection string
 func DBConnStr() (string, error) {
+	return dbConnStr(Database.Host)
+}
+
+// DBReplicaConnStr returns the connection string of the read replica, which shares the settings of the primary database except the host
+func DBReplicaConnStr() (string, error) {
+	if Database.Type.IsSQLite3() {
+		return "", errors.New("SQLite3 doesn't support read replicas")
+	}
+	return dbConnStr(Database.ReplicaHost)
+}
+
+func dbConnStr(host string) (string, error) {
 	var connStr string
 	paramSep := "?"
 	if strings.Contains(Database.Name, paramSep) {
@@ -101,7 +115,7 @@ func DBConnStr() (string, error) {
 	switch Database.Type {
 	case "mysql":
 		connType := "tcp"
-		if len(Database.Host) > 0 && Database.Host[0] == '/' { // looks like a unix socket
+		if len(host) > 0 && host[0] == '/' { // looks like a unix socket
 			connType = "unix"
 		}
 		tls := Database.SSLMode
@@ -109,11 +123,11 @@ func DBConnStr() (string, error) {
 			tls = "false"
 		}
 		connStr = fmt.Sprintf("%s:%s@%s(%s)/%s%sparseTime=true&tls=%s",
-			Database.User, Database.Passwd, connType, Database.Host, Database.Name, paramSep, tls)
+			Database.User, Database.Passwd, connType, host, Database.Name, paramSep, tls)
 	case "postgres":
-		connStr = getPostgreSQLConnectionString(Database.Host, Database.User, Database.Passwd, Database.Name, Database.SSLMode)
+		connStr = getPostgreSQLConnectionString(host, Database.User, Database.Passwd, Database.Name, Database.SSLMode)
 	case "mssql":
-		host, port := ParseMSSQLHostPort(Database.Host)
+		host, port := ParseMSSQLHostPort(host)
 		connStr = fmt.Sprintf("server=%s; port=%s; database=%s; user id=%s; password=%s;", host, port, Database.Name, Database.User, Database.Passwd)
 	case "sqlite3":
 		if !EnableSQLite3 {
@@ -0,0 +1,78 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// healthCheckPath is the directory of the objects written by the health checks, they are deleted right after
+const healthCheckPath = ".healthcheck"
+
+// HealthStatus is the result of the latest health check of a storage
+type HealthStatus struct {
+	Name    string
+	Healthy bool
+	Error   string
+	Checked time.Time
+}
+
+var (
+	healthStatuses   = map[string]*HealthStatus{}
+	healthStatusesMu sync.RWMutex
+)
+
+// CheckHealth checks whether the storage could save, stat and delete an object, the result is recorded by the name
+func CheckHealth(name string, s ObjectStorage) *HealthStatus {
+	status := &HealthStatus{Name: name, Healthy: true, Checked: time.Now()}
+	if err := probe(s); err != nil {
+		status.Healthy = false
+		status.Error = err.Error()
+	}
+
+	healthStatusesMu.Lock()
+	healthStatuses[name] = status
+	healthStatusesMu.Unlock()
+	return status
+}
+
+func probe(s ObjectStorage) error {
+	content := []byte("ok")
+	p := fmt.Sprintf("%s/%d", healthCheckPath, time.Now().UnixNano())
+	if _, err := s.Save(p, bytes.NewReader(content), int64(len(content))); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	fi, err := s.Stat(p)
+	if err == nil && fi.Size() != int64(len(content)) {
+		err = fmt.Errorf("unexpected size %d", fi.Size())
+	}
+	if err != nil {
+		_ = s.Delete(p)
+		return fmt.Errorf("stat: %w", err)
+	}
+	if err := s.Delete(p); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+// GetHealthStatuses returns the results of the latest health checks of the storages, sorted by their names
+func GetHealthStatuses() []*HealthStatus {
+	healthStatusesMu.RLock()
+	defer healthStatusesMu.RUnlock()
+
+	statuses := make([]*HealthStatus, 0, len(healthStatuses))
+	for _, status := range healthStatuses {
+		statuses = append(statuses, status)
+	}
+	slices.SortFunc(statuses, func(a, b *HealthStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return statuses
+}
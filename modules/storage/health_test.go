@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHealth(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(context.Background(), &setting.Storage{Path: dir})
+	require.NoError(t, err)
+
+	status := CheckHealth("test_healthy", s)
+	assert.True(t, status.Healthy)
+	assert.Empty(t, status.Error)
+	// the probe is cleaned up
+	entries, err := os.ReadDir(filepath.Join(dir, healthCheckPath))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	status = CheckHealth("test_unhealthy", discardStorage("storage is down"))
+	assert.False(t, status.Healthy)
+	assert.Contains(t, status.Error, "storage is down")
+
+	var names []string
+	for _, status := range GetHealthStatuses() {
+		names = append(names, status.Name)
+	}
+	assert.Equal(t, []string{"test_healthy", "test_unhealthy"}, names)
+}
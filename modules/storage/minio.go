@@ -236,10 +236,15 @@ func (m *MinioStorage) Delete(path string) error {
 
 // URL gets the redirect URL to a file. The presigned link is valid for 5 minutes.
 func (m *MinioStorage) URL(path, name string) (*url.URL, error) {
+	return m.PresignedURL(path, name, 5*time.Minute)
+}
+
+// PresignedURL gets the URL to download a file, which is valid for the expiry
+func (m *MinioStorage) PresignedURL(path, name string, expiry time.Duration) (*url.URL, error) {
 	reqParams := make(url.Values)
 	// TODO it may be good to embed images with 'inline' like ServeData does, but we don't want to have to read the file, do we?
 	reqParams.Set("response-content-disposition", "attachment; filename=\""+quoteEscaper.Replace(name)+"\"")
-	u, err := m.client.PresignedGetObject(m.ctx, m.bucket, m.buildMinioPath(path), 5*time.Minute, reqParams)
+	u, err := m.client.PresignedGetObject(m.ctx, m.bucket, m.buildMinioPath(path), expiry, reqParams)
 	return u, convertMinioErr(err)
 }
 
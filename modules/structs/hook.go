@@ -114,6 +114,8 @@ var (
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
 	_ Payloader = &PackagePayload{}
+	_ Payloader = &WorkflowDispatchPayload{}
+	_ Payloader = &WorkflowRunPayload{}
 )
 
 // _________                        __
@@ -494,3 +496,38 @@ type PackagePayload struct {
 func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
+
+// WorkflowDispatchPayload represents a payload of dispatching a workflow
+type WorkflowDispatchPayload struct {
+	// the file name of the workflow
+	Workflow   string            `json:"workflow"`
+	Ref        string            `json:"ref"`
+	Inputs     map[string]string `json:"inputs"`
+	Repository *Repository       `json:"repository"`
+	Sender     *User             `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowDispatchPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookWorkflowRunAction an action that happens to a workflow run
+type HookWorkflowRunAction string
+
+// HookWorkflowRunCompleted completed
+const HookWorkflowRunCompleted HookWorkflowRunAction = "completed"
+
+// WorkflowRunPayload represents a payload of a workflow run
+type WorkflowRunPayload struct {
+	Action      HookWorkflowRunAction `json:"action"`
+	WorkflowRun *ActionRun            `json:"workflow_run"`
+	Repository  *Repository           `json:"repository"`
+	// the user who triggered the run
+	Sender *User `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowRunPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
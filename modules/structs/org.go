@@ -57,3 +57,52 @@ type EditOrgOption struct {
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess *bool  `json:"repo_admin_change_team_access"`
 }
+
+// OrgActionsSettings represents the Actions settings of an organization
+type OrgActionsSettings struct {
+	// whether the workflows must pin third-party actions to full commit SHAs,
+	// it's always true if the instance requires it
+	RequirePinnedActions bool `json:"require_pinned_actions"`
+	// which runners the repositories of the organization could use
+	// enum: all,shared_public_only,no_shared,owner_only
+	RunnerSharingPolicy string `json:"runner_sharing_policy"`
+	// overrides of the runs-on labels of the jobs of the repositories in the new runs
+	RunsOnOverrides []*ActionRunsOnOverride `json:"runs_on_overrides"`
+	// the identity which the commits, comments and statuses made with the tokens of the jobs of the repositories
+	// are attributed to, the repositories could override it, null means the global actions user
+	BotIdentity *ActionBotIdentity `json:"bot_identity"`
+	// the licenses the dependencies of the repositories could have, which the license scans of the jobs are checked against
+	LicensePolicy *ActionLicensePolicy `json:"license_policy"`
+	// the domains like "github.com" and "*.npmjs.org" the jobs of the repositories could connect to,
+	// the domains declared by the workflows are restricted by them, empty if the jobs could connect to anywhere
+	EgressAllowlist []string `json:"egress_allowlist"`
+	// how the badges of the workflows of the repositories are rendered, the repositories could override it,
+	// null means the default badges
+	BadgeTheme *ActionBadgeTheme `json:"badge_theme"`
+}
+
+// ActionLicensePolicy represents the SPDX license identifiers the dependencies could have, they're matched case-insensitively
+type ActionLicensePolicy struct {
+	// the licenses allowed, the other licenses are violations unless it's empty
+	Allowed []string `json:"allowed"`
+	// the licenses denied, they're always violations
+	Denied []string `json:"denied"`
+}
+
+// EditOrgActionsSettingsOption options when editing the Actions settings of an organization,
+// the settings which aren't set are kept
+type EditOrgActionsSettingsOption struct {
+	RequirePinnedActions *bool `json:"require_pinned_actions"`
+	// enum: all,shared_public_only,no_shared,owner_only
+	RunnerSharingPolicy *string `json:"runner_sharing_policy"`
+	// replaces all the runs-on overrides, an empty list removes them
+	RunsOnOverrides []*ActionRunsOnOverride `json:"runs_on_overrides"`
+	// an identity with an empty name removes it
+	BotIdentity *ActionBotIdentity `json:"bot_identity"`
+	// replaces the license policy, empty lists remove it
+	LicensePolicy *ActionLicensePolicy `json:"license_policy"`
+	// replaces the egress allowlist, an empty list removes it
+	EgressAllowlist []string `json:"egress_allowlist"`
+	// an empty theme removes it
+	BadgeTheme *ActionBadgeTheme `json:"badge_theme"`
+}
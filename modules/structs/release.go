@@ -49,3 +49,24 @@ type EditReleaseOption struct {
 	IsDraft      *bool  `json:"draft"`
 	IsPrerelease *bool  `json:"prerelease"`
 }
+
+// GenerateReleaseNotesOption options when generating the notes of a release
+type GenerateReleaseNotesOption struct {
+	// the tag of the release, the notes are generated for target_commitish if it doesn't exist
+	// required: true
+	TagName string `json:"tag_name" binding:"Required"`
+	// the branch or the commit of the release if the tag doesn't exist, the default branch if it's empty
+	Target string `json:"target_commitish"`
+	// the tag which the notes start from, the latest tag whose commit is an ancestor of the release if it's empty
+	PreviousTagName string `json:"previous_tag_name"`
+	// the path of the configuration file in the default branch, `.gitea/release.yml` or `.github/release.yml` if it's empty
+	ConfigurationFilePath string `json:"configuration_file_path"`
+}
+
+// GeneratedReleaseNotes represents the notes generated for a release
+type GeneratedReleaseNotes struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+	// the tag which the notes start from, empty if there isn't a previous tag
+	PreviousTagName string `json:"previous_tag_name"`
+}
@@ -253,6 +253,8 @@ type GenerateRepoOption struct {
 	Labels bool `json:"labels"`
 	// include protected branches in template repo
 	ProtectedBranch bool `json:"protected_branch"`
+	// register the schedules of the workflows in the git content immediately
+	WorkflowSchedules bool `json:"workflow_schedules"`
 }
 
 // CreateBranchRepoOption options when creating a branch in a repository
@@ -16,15 +16,31 @@ type ActionTask struct {
 	RunNumber    int64  `json:"run_number"`
 	Event        string `json:"event"`
 	DisplayTitle string `json:"display_title"`
-	Status       string `json:"status"`
-	WorkflowID   string `json:"workflow_id"`
-	URL          string `json:"url"`
+	// enum: unknown,waiting,running,success,failure,cancelled,skipped,blocked
+	Status string `json:"status"`
+	// the final result of the task, empty until it's done
+	// enum: success,failure,cancelled,skipped
+	Conclusion string `json:"conclusion"`
+	// the status to display, in the language of the request
+	StatusDisplay string `json:"status_display"`
+	WorkflowID    string `json:"workflow_id"`
+	// the id of the job the task is an attempt of
+	JobID   int64 `json:"job_id"`
+	Attempt int64 `json:"attempt"`
+	// the url of the run in the web UI
+	URL string `json:"url"`
+	// the url of the logs of the latest attempt of the job in the API
+	LogsURL string `json:"logs_url"`
 	// swagger:strfmt date-time
 	CreatedAt time.Time `json:"created_at"`
 	// swagger:strfmt date-time
 	UpdatedAt time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
 	RunStartedAt time.Time `json:"run_started_at"`
+	// swagger:strfmt date-time
+	StoppedAt time.Time `json:"stopped_at"`
+	// environment reported by the runner, e.g. os, os_build, container_image_digest and tool versions
+	Environment map[string]string `json:"environment,omitempty"`
 }
 
 // ActionTaskResponse returns a ActionTask
@@ -32,3 +48,1385 @@ type ActionTaskResponse struct {
 	Entries    []*ActionTask `json:"workflow_runs"`
 	TotalCount int64         `json:"total_count"`
 }
+
+// ActionRun represents a run of Gitea Actions or a run reported by an external CI system
+type ActionRun struct {
+	ID         int64  `json:"id"`
+	RunNumber  int64  `json:"run_number"`
+	Title      string `json:"title"`
+	WorkflowID string `json:"workflow_id"`
+	// the component of a monorepo which the run belongs to, empty if none
+	Component string `json:"component"`
+	Event     string `json:"event"`
+	// enum: unknown,waiting,running,success,failure,cancelled,skipped,blocked
+	Status string `json:"status"`
+	// the final result of the run, empty until it's done
+	// enum: success,failure,cancelled,skipped
+	Conclusion string `json:"conclusion"`
+	// the status to display, in the language of the request
+	StatusDisplay string `json:"status_display"`
+	HeadBranch    string `json:"head_branch"`
+	HeadSHA       string `json:"head_sha"`
+	// the name of the external CI system, empty for runs of Gitea Actions
+	ExternalSystem string          `json:"external_system"`
+	ExternalURL    string          `json:"external_url"`
+	HTMLURL        string          `json:"html_url"`
+	URL            string          `json:"url"`
+	Repo           *RepositoryMeta `json:"repository"`
+	Jobs           []*ActionRunJob `json:"jobs"`
+	// the acknowledgement of the failure, null if it hasn't been acknowledged
+	Acknowledgement *ActionRunAcknowledgement `json:"acknowledgement"`
+	// how many times the event of the run was delivered again and skipped as a duplicate
+	DuplicateDeliveries int64 `json:"duplicate_deliveries"`
+	// swagger:strfmt date-time
+	Started time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	Stopped time.Time `json:"stopped_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionRunCreationFailure represents a run which wasn't created though its workflow was expected to be triggered by an event
+type ActionRunCreationFailure struct {
+	ID int64 `json:"id"`
+	// the file name of the workflow, empty if the failure isn't of a single workflow
+	WorkflowID string `json:"workflow_id"`
+	Event      string `json:"event"`
+	Ref        string `json:"ref"`
+	CommitSHA  string `json:"commit_sha"`
+	// the user who triggered the event, null if the user has been deleted
+	TriggerUser *User `json:"trigger_user"`
+	// enum: invalid_workflow,quota_exceeded,actor_blocked,policy_rejected,internal_error
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// ActionComponent represents the statistics of the runs of a component of a monorepo
+type ActionComponent struct {
+	Name           string `json:"name"`
+	TotalRuns      int64  `json:"total_runs"`
+	SuccessfulRuns int64  `json:"successful_runs"`
+	FailedRuns     int64  `json:"failed_runs"`
+	CancelledRuns  int64  `json:"cancelled_runs"`
+	// the latest run of the component
+	LatestRun *ActionRun `json:"latest_run"`
+}
+
+// ActionRunBacklog represents the runs of a repository which are queued, i.e. not running yet
+type ActionRunBacklog struct {
+	// the number of the queued runs
+	Queued int64 `json:"queued"`
+	// the number of the runs waiting for runners
+	Waiting int64 `json:"waiting"`
+	// the number of the runs blocked by their needs, approvals or concurrency
+	Blocked int64 `json:"blocked"`
+	// the most runs which could be queued, the oldest queued runs not of the default branch beyond it are cancelled, 0 means unlimited
+	Limit int64 `json:"limit"`
+}
+
+// ActionCodeScanningAlert represents a finding of a code scanning tool on a ref, deduplicated across the runs
+type ActionCodeScanningAlert struct {
+	ID  int64  `json:"id"`
+	Ref string `json:"ref"`
+	// identifies the same finding across the runs
+	Fingerprint string `json:"fingerprint"`
+	Tool        string `json:"tool"`
+	RuleID      string `json:"rule_id"`
+	// enum: error,warning,note
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	// enum: open,fixed
+	State string `json:"state"`
+	// the number of the run which reported it first
+	FirstRunNumber int64 `json:"first_run_number"`
+	// the number of the latest run which reported it
+	LastRunNumber int64 `json:"last_run_number"`
+	// the number of the run which found it fixed, 0 if it's open
+	FixedRunNumber int64 `json:"fixed_run_number"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Fixed *time.Time `json:"fixed_at"`
+}
+
+// ActionCodeScanningUpload represents the changes of the alerts made by an upload of the SARIF results of a run
+type ActionCodeScanningUpload struct {
+	// the tools of the upload
+	Tools []string `json:"tools"`
+	// the results after the deduplication
+	Results int `json:"results"`
+	// the alerts never reported on the ref before
+	New int `json:"new"`
+	// the fixed alerts reported again
+	Reopened int `json:"reopened"`
+	// the open alerts of the tools which aren't reported any more
+	Fixed int `json:"fixed"`
+}
+
+// ActionCodeScanningComparison represents the alerts of the head of a pull request compared to the ones of its base branch
+type ActionCodeScanningComparison struct {
+	BaseRef string `json:"base_ref"`
+	HeadRef string `json:"head_ref"`
+	// the alerts open on the head but not on the base branch
+	NewAlerts []*ActionCodeScanningAlert `json:"new_alerts"`
+	// the alerts open on the base branch but fixed on the head, only the tools which have uploaded to the head are compared
+	FixedAlerts []*ActionCodeScanningAlert `json:"fixed_alerts"`
+}
+
+// ActionDependency represents a package which a manifest depends on
+type ActionDependency struct {
+	// required: true
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// like "runtime" or "development", empty if the ecosystem doesn't tell
+	Scope string `json:"scope"`
+}
+
+// ActionDependencyManifest represents a manifest file of the dependencies, like go.mod or package-lock.json
+type ActionDependencyManifest struct {
+	// the path of the manifest in the repository
+	// required: true
+	Path string `json:"path"`
+	// like "go", "npm" or "pip"
+	Ecosystem    string              `json:"ecosystem"`
+	Dependencies []*ActionDependency `json:"dependencies"`
+}
+
+// SubmitActionDependencySnapshotOption options for submitting the dependency manifests of the commit of a run
+type SubmitActionDependencySnapshotOption struct {
+	// the manifests replace the ones of the same paths submitted for the same commit
+	// required: true
+	Manifests []*ActionDependencyManifest `json:"manifests" binding:"Required"`
+}
+
+// ActionDependencySnapshot represents the latest snapshot of the dependency manifests of a ref
+type ActionDependencySnapshot struct {
+	Ref       string `json:"ref"`
+	CommitSHA string `json:"commit_sha"`
+	// the number of the latest run which submitted to the snapshot
+	RunNumber int64                       `json:"run_number"`
+	Manifests []*ActionDependencyManifest `json:"manifests"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionDependencyChange represents a dependency added, removed or updated by a pull request
+type ActionDependencyChange struct {
+	// enum: added,removed,updated
+	ChangeType string `json:"change_type"`
+	Manifest   string `json:"manifest"`
+	Ecosystem  string `json:"ecosystem"`
+	Name       string `json:"name"`
+	// the version of the head, or the removed version
+	Version string `json:"version"`
+	// the version of the base branch, empty unless it's updated
+	PreviousVersion string `json:"previous_version"`
+	Scope           string `json:"scope"`
+}
+
+// ActionDependencyReview represents the changes of the dependencies of a pull request for the review
+type ActionDependencyReview struct {
+	BaseRef       string                    `json:"base_ref"`
+	BaseCommitSHA string                    `json:"base_commit_sha"`
+	HeadRef       string                    `json:"head_ref"`
+	HeadCommitSHA string                    `json:"head_commit_sha"`
+	Changes       []*ActionDependencyChange `json:"changes"`
+}
+
+// ActionLicensedPackage represents a dependency with its license reported by a license scan
+type ActionLicensedPackage struct {
+	// required: true
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// the SPDX license expression, like "MIT" or "Apache-2.0 OR MIT", empty if it's unknown
+	License string `json:"license"`
+}
+
+// SubmitActionLicenseScanOption options for submitting the result of a license scan of a job
+type SubmitActionLicenseScanOption struct {
+	// required: true
+	Packages []*ActionLicensedPackage `json:"packages" binding:"Required"`
+}
+
+// ActionLicenseViolation represents a dependency whose license violates the license policy
+type ActionLicenseViolation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	License string `json:"license"`
+	// enum: denied,not_allowed,unknown
+	Reason string `json:"reason"`
+}
+
+// ActionLicenseScan represents the result of a license scan of a job checked against the license policy of the owner
+type ActionLicenseScan struct {
+	ID     int64 `json:"id"`
+	TaskID int64 `json:"task_id"`
+	Passed bool  `json:"passed"`
+	// how many packages have been scanned
+	Packages   int                       `json:"packages"`
+	Violations []*ActionLicenseViolation `json:"violations"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// ActionEgressConnection represents a connection of a job blocked by the runner
+type ActionEgressConnection struct {
+	// required: true
+	Host string `json:"host"`
+	// 0 if it's unknown
+	Port int `json:"port"`
+	// how many times it was blocked, 1 if it's not set
+	Count int64 `json:"count"`
+}
+
+// SubmitActionEgressViolationsOption options for reporting the connections of a job blocked by the egress policy of its run
+type SubmitActionEgressViolationsOption struct {
+	// required: true
+	Connections []*ActionEgressConnection `json:"connections" binding:"Required"`
+}
+
+// ActionEgressViolation represents a connection of a job blocked by the egress policy of its run
+type ActionEgressViolation struct {
+	ID     int64  `json:"id"`
+	TaskID int64  `json:"task_id"`
+	Host   string `json:"host"`
+	Port   int    `json:"port"`
+	Count  int64  `json:"count"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionLifecycleEvent is published to the message queue when a run is created or completed, or a job changes its status
+type ActionLifecycleEvent struct {
+	// enum: run.created,run.completed,job.updated
+	Event string `json:"event"`
+	// the run, for the job events only the changed job is listed in its jobs
+	Run *ActionRun `json:"run"`
+	// the changed job, null for the run events
+	Job *ActionRunJob `json:"job"`
+	// swagger:strfmt date-time
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ActionRunAcknowledgement represents that the failure of a run has been acknowledged as known or won't fix
+type ActionRunAcknowledgement struct {
+	User    *User  `json:"user"`
+	Comment string `json:"comment"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// AcknowledgeActionRunOption options for acknowledging the failure of a run
+type AcknowledgeActionRunOption struct {
+	Comment string `json:"comment" binding:"MaxSize(1024)"`
+}
+
+// RerunActionRunOption options for re-running a run
+type RerunActionRunOption struct {
+	// the id of the job to rerun with the jobs depending on it, all jobs of the run are rerun if it's empty
+	JobID int64 `json:"job_id"`
+	// the variables to override for the new attempts, the variables of the repository are not changed
+	Vars map[string]string `json:"vars"`
+	// the inputs to override for the new attempts, only the inputs the run was dispatched with can be overridden
+	Inputs map[string]string `json:"inputs"`
+}
+
+// CreateActionRunIssueOption options for opening an issue to track the failure of a run
+type CreateActionRunIssueOption struct {
+	// close the issue automatically when the workflow passes later on the same ref
+	AutoClose bool `json:"auto_close"`
+}
+
+// ActionRunSummary represents the summary of a run aggregated from the summaries written by its jobs
+type ActionRunSummary struct {
+	// the markdown document, every job which has written a summary has a section headed by its name
+	Body string `json:"body"`
+	// the rendered HTML of the body
+	BodyHTML string `json:"body_html"`
+}
+
+// ActionRunComment represents a comment for discussing a run or a job of it
+type ActionRunComment struct {
+	ID int64 `json:"id"`
+	// the id of the job which the comment is about, 0 if it's about the whole run
+	JobID int64  `json:"job_id"`
+	User  *User  `json:"user"`
+	Body  string `json:"body"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionTokenActivity represents a request made with the token of a task of a run
+type ActionTokenActivity struct {
+	ID     int64 `json:"id"`
+	TaskID int64 `json:"task_id"`
+	// the id of the repository requested, 0 if the request isn't about a repository
+	TargetRepoID int64  `json:"target_repo_id"`
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Status       int    `json:"status"`
+	// empty if the request looks normal, or one of rate_limited, other_repo, write_other_repo and repo_enumeration
+	Anomaly string `json:"anomaly"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateActionRunCommentOption options for creating a comment on a run
+type CreateActionRunCommentOption struct {
+	// required: true
+	Body string `json:"body" binding:"Required"`
+	// the id of the job which the comment is about, omit it for the whole run
+	JobID int64 `json:"job_id"`
+}
+
+// EditActionRunCommentOption options for editing a comment of a run
+type EditActionRunCommentOption struct {
+	// required: true
+	Body string `json:"body" binding:"Required"`
+}
+
+// ActionRunJob represents a job of a run
+type ActionRunJob struct {
+	ID    int64  `json:"id"`
+	RunID int64  `json:"run_id"`
+	Name  string `json:"name"`
+	// the component of a monorepo which the job belongs to, empty if none
+	Component string `json:"component"`
+	// enum: unknown,waiting,running,success,failure,cancelled,skipped,blocked
+	Status string `json:"status"`
+	// the final result of the job, empty until it's done
+	// enum: success,failure,cancelled,skipped
+	Conclusion string `json:"conclusion"`
+	// the status to display, in the language of the request
+	StatusDisplay string `json:"status_display"`
+	// why the job didn't succeed, empty if it succeeded, was skipped or hasn't stopped
+	// enum: preflight,infrastructure,user,cancellation
+	FailureReason string `json:"failure_reason,omitempty"`
+	// the failure reason to display with its details, in the language of the request
+	FailureReasonDisplay string `json:"failure_reason_display,omitempty"`
+	ExternalURL          string `json:"external_url"`
+	// the number of the latest attempt of the job, 0 if it hasn't been picked up by a runner
+	Attempt int64 `json:"attempt"`
+	// the url of the logs of the latest attempt of the job in the API, empty for the jobs of the external CI systems
+	LogsURL string `json:"logs_url"`
+	// when the job was picked up by a runner
+	// swagger:strfmt date-time
+	Started time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	Stopped time.Time `json:"stopped_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// when the job's dependencies were satisfied and it started to wait for a runner
+	// swagger:strfmt date-time
+	Queued *time.Time `json:"queued_at,omitempty"`
+	// when the first step of the job started to execute
+	// swagger:strfmt date-time
+	ExecutionStarted *time.Time `json:"execution_started_at,omitempty"`
+	// null until the job is ready, or if the job was created before its queue time was recorded
+	Timings *ActionRunJobTimings `json:"timings"`
+	// the runner which executed the latest attempt of the job, null if no runner has picked it up
+	Runner *ActionRunJobRunner `json:"runner"`
+	// the resource usage of the latest attempt of the job, null if the runner doesn't report it
+	ResourceUsage *ActionRunJobResourceUsage `json:"resource_usage"`
+	// why the latest attempt of the job didn't succeed, empty if it succeeded, was skipped or hasn't stopped
+	// enum: infrastructure,user,cancellation
+	ErrorClass string `json:"error_class,omitempty"`
+	// the variables and inputs overridden by the re-run which created the latest attempt of the job
+	Overrides *ActionRunOverrides `json:"overrides,omitempty"`
+	// the steps of the latest attempt of the job, only listed by the run details including the steps
+	Steps []*ActionRunStep `json:"steps,omitempty"`
+	// the environment the job deploys to, in lower case, empty if none
+	Environment string `json:"environment,omitempty"`
+	// the freeze window which holds the waiting job, null if it isn't frozen
+	FrozenBy *ActionFreezeWindow `json:"frozen_by,omitempty"`
+	// the history of the job in the latest finished runs of the workflow on the same ref,
+	// null if the hints are disabled or the job never finished in them
+	Hints *ActionRunJobHints `json:"hints,omitempty"`
+}
+
+// ActionRunJobHints represents the history of a job in the latest finished runs of the workflow on the same ref,
+// see JOB_HINTS_SAMPLE_RUNS in the [actions] config
+type ActionRunJobHints struct {
+	// the number of the sampled runs which have the job finished
+	SampledRuns int64 `json:"sampled_runs"`
+	// the ratio of the sampled runs in which the job failed, from 0 to 1
+	FailureRate float64 `json:"failure_rate"`
+	// the number of the sampled runs in which the job failed at first but passed after being rerun
+	FlakyRuns int64 `json:"flaky_runs"`
+	// whether the job is known to be flaky, it passed only after being rerun in at least 2 of the sampled runs
+	Flaky bool `json:"flaky"`
+	// the average duration of the job in the sampled runs in which it succeeded, in seconds, 0 if it hasn't succeeded in them
+	AverageDuration int64 `json:"average_duration"`
+}
+
+// ActionRunStep represents a step of a job
+type ActionRunStep struct {
+	// the 1-based number of the step in the job
+	Number int64  `json:"number"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// swagger:strfmt date-time
+	Started time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	Stopped time.Time `json:"stopped_at"`
+}
+
+// ActionLogSegment represents a part of a log line in the same style set by the ANSI escape sequences
+type ActionLogSegment struct {
+	Text string `json:"text"`
+	// the color of the text, the names like "red" and "bright-red" of the 16 basic colors, or like "#ff8700" of the others, empty for the default color
+	Foreground string `json:"fg"`
+	// the color of the background, in the same format as fg
+	Background string `json:"bg"`
+	Bold       bool   `json:"bold"`
+	Italic     bool   `json:"italic"`
+	Underline  bool   `json:"underline"`
+}
+
+// ActionLogLine represents a line of the logs of a job parsed from the ANSI escape sequences and the workflow commands
+type ActionLogLine struct {
+	// the 0-based index of the line in the raw logs
+	Index int64 `json:"index"`
+	// swagger:strfmt date-time
+	Time time.Time `json:"time"`
+	// "group" starts a group with the segments as its title, "endgroup" ends it
+	// enum: line,group,endgroup
+	Type string `json:"type"`
+	// the level set by the workflow commands like "::error::message", empty if it isn't set
+	// enum: ,debug,notice,warning,error
+	Level string `json:"level"`
+	// the 1-based number of the group the line is in, 0 if it isn't in a group
+	Group    int64               `json:"group"`
+	Segments []*ActionLogSegment `json:"segments"`
+}
+
+// ActionJobStructuredLogs represents a part of the structured logs of the latest attempt of a job
+type ActionJobStructuredLogs struct {
+	Lines []*ActionLogLine `json:"lines"`
+	// the number of the lines of the logs so far
+	TotalLines int64 `json:"total_lines"`
+	// whether the logs are complete, no more lines will be appended
+	Complete bool `json:"complete"`
+}
+
+// ActionRunDetails represents a run with the related data included by the request, the ones not included are null
+type ActionRunDetails struct {
+	// the run, its jobs are only listed if the jobs or the steps are included
+	Run       *ActionRun          `json:"run"`
+	Artifacts []*ActionArtifact   `json:"artifacts"`
+	Comments  []*ActionRunComment `json:"comments"`
+	Summary   *ActionRunSummary   `json:"summary"`
+}
+
+// ActionRunOverrides represents the variables and inputs overridden when re-running a run
+type ActionRunOverrides struct {
+	Vars   map[string]string `json:"vars,omitempty"`
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// ActionRunJobResourceUsage summarizes the resource usage samples reported by the runner of a job
+type ActionRunJobResourceUsage struct {
+	Samples int64 `json:"samples"`
+	// the CPU usage in percent of one core, it could be over 100 if multiple cores are used
+	CPUPercentAvg  float64 `json:"cpu_percent_avg"`
+	CPUPercentMax  float64 `json:"cpu_percent_max"`
+	MemoryBytesAvg int64   `json:"memory_bytes_avg"`
+	MemoryBytesMax int64   `json:"memory_bytes_max"`
+	DiskBytesMax   int64   `json:"disk_bytes_max"`
+}
+
+// ActionRunJobRunner represents the runner which executed a job
+type ActionRunJobRunner struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Labels []string `json:"labels"`
+	// the level the runner is registered at, which decides the jobs it could pick up
+	// enum: system-global,organization,individual,repository
+	Group string `json:"group"`
+	// whether the runner has been deleted since it executed the job
+	Deleted bool `json:"deleted"`
+}
+
+// ActionRunJobTimings represents how long a job spent in each phase, in seconds.
+// A phase which hasn't finished yet is counted up to now, and a phase which hasn't started is zero.
+type ActionRunJobTimings struct {
+	// from the creation to being ready, waiting for needed jobs or an approval
+	Blocked int64 `json:"blocked"`
+	// from being ready to being picked up by a runner
+	Queued int64 `json:"queued"`
+	// from being picked up to the first step, e.g. preparing the environment
+	Setup int64 `json:"setup"`
+	// from the first step to the end of the job
+	Execution int64 `json:"execution"`
+}
+
+// ExternalRunJobOption represents a job reported by an external CI system
+type ExternalRunJobOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// required: true
+	// enum: waiting,running,success,failure,cancelled,skipped
+	Status string `json:"status" binding:"Required;In(waiting,running,success,failure,cancelled,skipped)"`
+	// link to the job in the external CI system
+	URL string `json:"url" binding:"ValidUrl"`
+}
+
+// CreateExternalRunOption options when reporting a run of an external CI system
+type CreateExternalRunOption struct {
+	// name of the run, it's also used as the workflow id of the run
+	//
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// name of the external CI system, e.g. jenkins
+	//
+	// required: true
+	System string `json:"system" binding:"Required;MaxSize(255)"`
+	// link to the run in the external CI system
+	URL string `json:"url" binding:"ValidUrl"`
+	// the commit the run is reported against
+	//
+	// required: true
+	HeadSHA string `json:"head_sha" binding:"Required"`
+	// the ref of the commit, e.g. refs/heads/main
+	Ref string `json:"ref"`
+	// required: true
+	Jobs []*ExternalRunJobOption `json:"jobs" binding:"Required"`
+}
+
+// UpdateExternalRunOption options when updating a run of an external CI system
+type UpdateExternalRunOption struct {
+	// link to the run in the external CI system
+	URL string `json:"url" binding:"ValidUrl"`
+	// statuses of the jobs, the jobs which don't exist yet will be added to the run
+	Jobs []*ExternalRunJobOption `json:"jobs"`
+}
+
+// ActionsLocalWorkflow represents a workflow file of a repository
+type ActionsLocalWorkflow struct {
+	// path of the workflow file in the repository
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ActionsLocalConfig represents what is needed to run the workflows of a repository locally with act
+type ActionsLocalConfig struct {
+	Ref       string                  `json:"ref"`
+	CommitSHA string                  `json:"commit_sha"`
+	Workflows []*ActionsLocalWorkflow `json:"workflows"`
+	// variables the workflows will get, secrets are never included
+	Vars map[string]string `json:"vars"`
+	// images used by the runners for their labels
+	Platforms map[string]string `json:"platforms"`
+	// labels of the runners available to the repository
+	Labels []string `json:"labels"`
+	// the platforms and variables as arguments of act, it could be saved as an .actrc file
+	Actrc string `json:"actrc"`
+}
+
+// ActionWorkflowLintFinding represents a deprecated or unsupported syntax found in a workflow file
+type ActionWorkflowLintFinding struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// enum: invalid-syntax,unsupported-key,unsupported-event,unsupported-runs-on,unsupported-expression,deprecated-command
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ActionWorkflowLintResult represents the lint findings of the workflows of a repository
+type ActionWorkflowLintResult struct {
+	Ref       string                       `json:"ref"`
+	CommitSHA string                       `json:"commit_sha"`
+	Findings  []*ActionWorkflowLintFinding `json:"findings"`
+}
+
+// ActionConfigSnapshotWorkflow represents a workflow of the default branch of a repository as of a time
+type ActionConfigSnapshotWorkflow struct {
+	// the file name of the workflow
+	WorkflowID string   `json:"workflow_id"`
+	Events     []string `json:"events"`
+	// the cron expressions of the schedule event
+	Schedules []string `json:"schedules"`
+	// whether the workflow was disabled, false if the settings weren't recorded at the time
+	Disabled bool `json:"disabled"`
+	// why the workflow was invalid, empty if it was valid
+	Error string `json:"error,omitempty"`
+}
+
+// ActionConfigSnapshot represents the Actions configuration of a repository as of a time
+type ActionConfigSnapshot struct {
+	// swagger:strfmt date-time
+	At time.Time `json:"at"`
+	// the commit of the default branch at the time by the committer dates, empty if the branch had no commits then
+	CommitSHA string                          `json:"commit_sha"`
+	Workflows []*ActionConfigSnapshotWorkflow `json:"workflows"`
+	// the settings of the repository at the time, null if they weren't recorded yet
+	Settings *RepoActionsSettings `json:"settings"`
+	// when the settings were changed to the ones at the time
+	// swagger:strfmt date-time
+	SettingsChangedAt *time.Time `json:"settings_changed_at"`
+	// since when the settings are recorded, null if they never were
+	// swagger:strfmt date-time
+	SettingsRecordedSince *time.Time `json:"settings_recorded_since"`
+	// the freeze windows of the instance and of the owner active at the time, the deleted ones are missing
+	FreezeWindows []*ActionFreezeWindow `json:"freeze_windows"`
+}
+
+// RepoActionsSettings represents the Actions settings of a repository
+type RepoActionsSettings struct {
+	Enabled bool `json:"enabled"`
+	// permissions of the tokens of the jobs which aren't triggered by pull requests from forks
+	// enum: read,write
+	DefaultTokenPermissions string `json:"default_token_permissions"`
+	// which runs triggered by pull requests from forks need approval
+	// enum: first_time,always,never
+	ForkPullRequestApproval string `json:"fork_pull_request_approval"`
+	// retention days of the artifacts, 0 means the default of the instance
+	ArtifactRetentionDays int64 `json:"artifact_retention_days"`
+	// retention days of the logs, 0 means the default of the instance
+	LogRetentionDays int64 `json:"log_retention_days"`
+	// retention days of the runs, 0 means the default of the instance
+	RunRetentionDays int64 `json:"run_retention_days"`
+	// glob patterns of the actions the workflows could use, empty means all actions are allowed
+	AllowedActions    []string `json:"allowed_actions"`
+	DisabledWorkflows []string `json:"disabled_workflows"`
+	// slash commands in the comments of pull requests which dispatch workflows
+	ChatOpsCommands []*RepoActionsChatOpsCommand `json:"chatops_commands"`
+	// workflows whose jobs don't publish commit statuses
+	StatusExcludedWorkflows []string `json:"status_excluded_workflows"`
+	// jobs of the workflows which are skipped in the new runs
+	DisabledJobs []*RepoActionsDisabledJob `json:"disabled_jobs"`
+	// overrides of the runs-on labels of the jobs in the new runs, the ones of the owner take precedence
+	RunsOnOverrides []*ActionRunsOnOverride `json:"runs_on_overrides"`
+	// which completed runs are included in the RSS/Atom feed of the repository
+	// enum: none,all,failure
+	FeedRuns string `json:"feed_runs"`
+	// only include the runs of the default branch in the feed
+	FeedRunsDefaultBranchOnly bool `json:"feed_runs_default_branch_only"`
+	// what anonymous users can see of the runs of the public repository,
+	// "metadata" hides the logs, artifacts and summaries from them
+	// enum: full,metadata
+	AnonymousRunAccess string `json:"anonymous_run_access"`
+	// which runs of the previous head commit are cancelled when a pull request is synchronized,
+	// "queued" cancels the runs which haven't started, "all" cancels the running runs too
+	// enum: none,queued,all
+	SupersedePullRequestRuns string `json:"supersede_pull_request_runs"`
+	// which runs of a pull request are cancelled when it's closed without merging,
+	// "queued" cancels the runs which haven't started, "all" cancels the running runs too
+	// enum: none,queued,all
+	ClosedPullRequestRuns string `json:"closed_pull_request_runs"`
+	// the runs of a pull request closed without merging are deleted with their logs and artifacts after the days since it's closed,
+	// 0 keeps them by the retentions
+	ClosedPullRequestRetentionDays int64 `json:"closed_pull_request_retention_days"`
+	// which repositories of the submodules on the same instance the tokens of the jobs could read for the recursive checkouts,
+	// "owner" allows the ones of the same owner, "instance" allows all, the user triggering the run must be able to read them too
+	// enum: none,owner,instance
+	SubmoduleTokenScope string `json:"submodule_token_scope"`
+	// the identity which the commits, comments and statuses made with the tokens of the jobs are attributed to,
+	// null means the one of the owner, or the global actions user
+	BotIdentity *ActionBotIdentity `json:"bot_identity"`
+	// creates the releases of the tags whose runs of a workflow succeed, null if it's disabled
+	ReleaseAutomation *RepoActionsReleaseAutomation `json:"release_automation"`
+	// removes the old versions of the packages published by the runs by the cleanup task, null if it's disabled
+	PackageRetention *RepoActionsPackageRetention `json:"package_retention"`
+	// how the badges of the workflows are rendered, null means the theme of the owner
+	BadgeTheme *ActionBadgeTheme `json:"badge_theme"`
+	// the labels of the badges of the workflows instead of their names, by the file names of the workflows
+	BadgeLabels map[string]string `json:"badge_labels"`
+	// the repository on a peer Gitea instance which the statuses of the runs are pushed to, null if it's disabled
+	FederationPeer *RepoActionsFederationPeer `json:"federation_peer"`
+	// whether the peer instances could push the statuses of their runs to the repository with the federation token
+	AcceptsFederatedStatuses bool `json:"accepts_federated_statuses"`
+}
+
+// RepoActionsFederationPeer represents the repository on a peer Gitea instance, like a mirror of the repository,
+// which shows the statuses of the runs of the repository
+type RepoActionsFederationPeer struct {
+	// the URL of the repository on the peer instance, like "https://gitea.example.com/owner/repo"
+	// required: true
+	URL string `json:"url"`
+	// the federation token accepted by the repository on the peer instance, it's never returned
+	Token string `json:"token,omitempty"`
+}
+
+// FederatedActionStatusOption represents a status of a job pushed by a peer Gitea instance which has run it
+type FederatedActionStatusOption struct {
+	// the full name of the repository, like "owner/repo"
+	// required: true
+	Repository string `json:"repository"`
+	// required: true
+	SHA string `json:"sha"`
+	// required: true
+	State       CommitStatusState `json:"state"`
+	Context     string            `json:"context"`
+	TargetURL   string            `json:"target_url"`
+	Description string            `json:"description"`
+}
+
+// RepoActionsChatOpsCommand represents a slash command in the comments of pull requests which dispatches a workflow
+type RepoActionsChatOpsCommand struct {
+	// the command without the slash, e.g. "deploy" for "/deploy staging"
+	Name string `json:"name"`
+	// the file name of the workflow to dispatch, it must be triggered by workflow_dispatch
+	WorkflowID string `json:"workflow_id"`
+	// the input of the workflow receiving the arguments of the command, they're ignored if it's empty
+	ArgsInput string `json:"args_input"`
+	// require the commenter to be an administrator of the repository, instead of a writer of Actions
+	RequireAdmin bool `json:"require_admin"`
+}
+
+// RepoActionsDisabledJob represents a job of a workflow which is skipped in the new runs
+type RepoActionsDisabledJob struct {
+	// the file name of the workflow
+	WorkflowID string `json:"workflow_id"`
+	// the id of the job, or the name of a job like "test (windows-latest)" to disable one of a matrix
+	Job string `json:"job"`
+}
+
+// ActionRunsOnOverride represents an override of the runs-on labels of the jobs matching a pattern
+type ActionRunsOnOverride struct {
+	// the glob pattern of the ids or names of the jobs, like "deploy-*"
+	// required: true
+	Job string `json:"job"`
+	// the labels replacing the runs-on labels of the jobs
+	RunsOn []string `json:"runs_on"`
+	// the labels replacing the runs-on labels of the jobs one by one, used if runs_on is empty
+	Labels map[string]string `json:"labels"`
+}
+
+// ActionBotIdentity represents the identity of the actions user in a repository or the repositories of an owner
+type ActionBotIdentity struct {
+	// the display name, also the name of the commits
+	Name string `json:"name"`
+	// the email of the commits, the email of the actions user is kept private if it's empty
+	Email string `json:"email"`
+	// the email whose avatar is shown, the email is used if it's empty
+	AvatarEmail string `json:"avatar_email"`
+}
+
+// ActionBadgeTheme represents how the badges of the workflows of a repository or the repositories of an owner are rendered
+type ActionBadgeTheme struct {
+	// the style of the badges, empty for the default style "plastic"
+	// enum: plastic,flat,flat-square
+	Style string `json:"style"`
+	// the background color of the labels, like "#555" or "blue"
+	LabelColor string `json:"label_color"`
+	// the colors replacing the ones of the statuses, by the names of the statuses like "success"
+	Colors map[string]string `json:"colors"`
+	// the base64 data URI of the SVG or PNG logo before the labels, like "data:image/svg+xml;base64,..."
+	Logo string `json:"logo"`
+}
+
+// RepoActionsReleaseAutomation represents the release created when a run of a workflow for a tag succeeds
+type RepoActionsReleaseAutomation struct {
+	// the file name of the workflow whose successful runs create the releases
+	// required: true
+	WorkflowID string `json:"workflow_id"`
+	// the glob pattern of the tags, like "v*", empty means all tags
+	TagPattern string `json:"tag_pattern"`
+	// the glob patterns of the names of the artifacts of the run attached to the release as zip files
+	Artifacts []string `json:"artifacts"`
+	// generate the notes of the release from the pull requests merged since the previous tag
+	GenerateNotes bool `json:"generate_notes"`
+	// create the releases as drafts
+	Draft bool `json:"draft"`
+}
+
+// RepoActionsPackageRetention represents how the old versions of the packages published by the runs are removed,
+// a version is kept by the first rule matching it, the versions matching no rules are kept
+type RepoActionsPackageRetention struct {
+	Rules []*RepoActionsPackageRetentionRule `json:"rules"`
+	// only log the versions which would be removed, see the report of the package retention
+	DryRun bool `json:"dry_run"`
+}
+
+// RepoActionsPackageRetentionRule represents how many versions of each package published by the runs of each ref are kept
+type RepoActionsPackageRetentionRule struct {
+	// the type of the packages like "container", empty means all types
+	Type string `json:"type"`
+	// the glob pattern of the names of the packages, empty means all packages
+	PackagePattern string `json:"package_pattern"`
+	// the glob pattern of the refs of the runs like "refs/heads/*", empty means all refs
+	RefPattern string `json:"ref_pattern"`
+	// how many latest versions of each package are kept for each ref
+	KeepCount int `json:"keep_count"`
+	// keep the versions published in the last days even if they're more than keep_count
+	KeepDays int `json:"keep_days"`
+}
+
+// ActionRunPackageOption options when recording a package version as published by a run
+type ActionRunPackageOption struct {
+	// required: true
+	Type string `json:"type"`
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	Version string `json:"version"`
+}
+
+// ActionPackageRetentionRemoval represents a package version published by a run which is removed by the package retention
+type ActionPackageRetentionRemoval struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Ref     string `json:"ref"`
+	RunID   int64  `json:"run_id"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EditRepoActionsSettingsOption options when editing the Actions settings of a repository,
+// the settings which aren't set are kept
+type EditRepoActionsSettingsOption struct {
+	Enabled *bool `json:"enabled"`
+	// enum: read,write
+	DefaultTokenPermissions *string `json:"default_token_permissions"`
+	// enum: first_time,always,never
+	ForkPullRequestApproval *string `json:"fork_pull_request_approval"`
+	ArtifactRetentionDays   *int64  `json:"artifact_retention_days"`
+	LogRetentionDays        *int64  `json:"log_retention_days"`
+	RunRetentionDays        *int64  `json:"run_retention_days"`
+	// an empty list allows all actions
+	AllowedActions    []string `json:"allowed_actions"`
+	DisabledWorkflows []string `json:"disabled_workflows"`
+	// replaces all the slash commands, an empty list removes them
+	ChatOpsCommands []*RepoActionsChatOpsCommand `json:"chatops_commands"`
+	// an empty list lets all workflows publish commit statuses
+	StatusExcludedWorkflows []string `json:"status_excluded_workflows"`
+	// replaces all the disabled jobs, an empty list enables them
+	DisabledJobs []*RepoActionsDisabledJob `json:"disabled_jobs"`
+	// replaces all the runs-on overrides, an empty list removes them
+	RunsOnOverrides []*ActionRunsOnOverride `json:"runs_on_overrides"`
+	// enum: none,all,failure
+	FeedRuns                  *string `json:"feed_runs"`
+	FeedRunsDefaultBranchOnly *bool   `json:"feed_runs_default_branch_only"`
+	// enum: full,metadata
+	AnonymousRunAccess *string `json:"anonymous_run_access"`
+	// enum: none,queued,all
+	SupersedePullRequestRuns *string `json:"supersede_pull_request_runs"`
+	// enum: none,queued,all
+	ClosedPullRequestRuns          *string `json:"closed_pull_request_runs"`
+	ClosedPullRequestRetentionDays *int64  `json:"closed_pull_request_retention_days"`
+	// enum: none,owner,instance
+	SubmoduleTokenScope *string `json:"submodule_token_scope"`
+	// an identity with an empty name removes it
+	BotIdentity *ActionBotIdentity `json:"bot_identity"`
+	// an automation with an empty workflow_id disables it
+	ReleaseAutomation *RepoActionsReleaseAutomation `json:"release_automation"`
+	// a retention without rules disables it
+	PackageRetention *RepoActionsPackageRetention `json:"package_retention"`
+	// an empty theme removes it
+	BadgeTheme *ActionBadgeTheme `json:"badge_theme"`
+	// replaces all the labels of the badges, an empty object removes them
+	BadgeLabels map[string]string `json:"badge_labels"`
+	// a peer with an empty url disables it, an empty token keeps the current one
+	FederationPeer *RepoActionsFederationPeer `json:"federation_peer"`
+	// the token the peer instances push the statuses of their runs with, it's stored hashed,
+	// an empty one stops accepting them
+	FederationToken *string `json:"federation_token"`
+}
+
+// ActionsConfigBundle represents the Actions configuration of a repository as a YAML document,
+// exported from a repository to import it into another one, on the same instance or another
+type ActionsConfigBundle struct {
+	// the version of the format of the bundle
+	Version int `json:"version"`
+	// the full name of the repository the bundle is exported from, it's ignored by the import
+	Repository string `json:"repository,omitempty"`
+	// swagger:strfmt date-time
+	ExportedAt *time.Time `json:"exported_at,omitempty"`
+	// the settings of Actions without whether they're enabled and the federation, the settings missing from the bundle are kept by the import
+	Settings *EditRepoActionsSettingsOption `json:"settings,omitempty"`
+	// the variables of the repository, the ones missing from the bundle are kept by the import, the secrets are never included
+	Variables []*ActionsConfigBundleVariable `json:"variables,omitempty"`
+	// the dispatch presets of the repository, the ones missing from the bundle are kept by the import
+	DispatchPresets []*ActionsConfigBundleDispatchPreset `json:"dispatch_presets,omitempty"`
+}
+
+// ActionsConfigBundleVariable represents a variable of an Actions configuration bundle
+type ActionsConfigBundleVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ActionsConfigBundleDispatchPreset represents a dispatch preset of an Actions configuration bundle
+type ActionsConfigBundleDispatchPreset struct {
+	Name string `json:"name"`
+	// the file name of the workflow
+	WorkflowID string `json:"workflow_id"`
+	// the branch or tag to dispatch the workflow on, the default branch if empty
+	Ref    string            `json:"ref,omitempty"`
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// ActionsConfigImport represents the changes made by importing an Actions configuration bundle into a repository
+type ActionsConfigImport struct {
+	// the settings after the import
+	Settings         *RepoActionsSettings `json:"settings"`
+	VariablesCreated []string             `json:"variables_created"`
+	VariablesUpdated []string             `json:"variables_updated"`
+	// the presets created or replaced
+	DispatchPresetsSaved []string `json:"dispatch_presets_saved"`
+	// the presets which couldn't dispatch their workflows in the repository, e.g. the workflows don't exist on their refs
+	DispatchPresetsSkipped []*ActionsConfigImportSkipped `json:"dispatch_presets_skipped"`
+}
+
+// ActionsConfigImportSkipped represents an item of an Actions configuration bundle which wasn't imported
+type ActionsConfigImportSkipped struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ActionUsage represents an action used by a workflow on the default branch of a repository
+type ActionUsage struct {
+	Repository *Repository `json:"repository"`
+	// the name of the workflow file
+	WorkflowID string `json:"workflow_id"`
+	// the action name without ref, in lower case
+	Action string `json:"action"`
+	// the tag, branch or commit SHA the action is used at
+	Ref string `json:"ref"`
+	// whether the ref is a full commit SHA
+	Pinned bool `json:"pinned"`
+	// swagger:strfmt date-time
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ActionTaskErrorStats represents how many tasks stopped in a period, by the classes of the errors which made them not succeed
+type ActionTaskErrorStats struct {
+	Total int64 `json:"total"`
+	// the runners or their machines failed, e.g. the disks were full or the runners were lost
+	Infrastructure int64 `json:"infrastructure"`
+	// the workflows failed, e.g. the tests failed or the tasks timed out
+	User         int64 `json:"user"`
+	Cancellation int64 `json:"cancellation"`
+}
+
+// ActionStorageHealth represents the latest health check of a storage of Actions
+type ActionStorageHealth struct {
+	// enum: actions_log,actions_artifacts
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	// why the check failed, empty if it passed
+	Error string `json:"error"`
+	// swagger:strfmt date-time
+	Checked time.Time `json:"checked_at"`
+}
+
+// ActionMinutesUsage represents how many minutes the tasks of a repository ran in a period
+type ActionMinutesUsage struct {
+	Repository *RepositoryMeta `json:"repository"`
+	Minutes    float64         `json:"minutes"`
+	// the minutes multiplied by the weights of the labels of the runners the tasks ran on, see RUNNER_LABEL_WEIGHTS in the [actions] config
+	WeightedMinutes float64 `json:"weighted_minutes"`
+}
+
+// ActionSchedule represents a cron schedule of a workflow with its upcoming runs
+type ActionSchedule struct {
+	Repository *Repository `json:"repository"`
+	// the name of the workflow file
+	WorkflowID string `json:"workflow_id"`
+	Title      string `json:"title"`
+	// the cron expression
+	Spec string `json:"spec"`
+	// the upcoming runs in the requested time window
+	NextRuns []time.Time `json:"next_runs"`
+}
+
+// ActionBlockedRef represents a ref of an action blocked by admins, new runs using it are refused
+type ActionBlockedRef struct {
+	ID int64 `json:"id"`
+	// the action name without ref, in lower case
+	Action string `json:"action"`
+	// the blocked tag, branch or commit SHA, empty means all refs of the action
+	Ref    string `json:"ref"`
+	Reason string `json:"reason"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateActionBlockedRefOption options when blocking a ref of an action
+type CreateActionBlockedRefOption struct {
+	// the action name without ref, e.g. actions/checkout or https://gitea.com/actions/checkout
+	// required: true
+	Action string `json:"action" binding:"Required;MaxSize(255)"`
+	// the tag, branch or commit SHA to block, empty blocks all refs of the action
+	Ref    string `json:"ref" binding:"MaxSize(255)"`
+	Reason string `json:"reason"`
+}
+
+// ActionFreezeWindow represents a period during which the jobs deploying to the environments or requiring the labels aren't picked by the runners,
+// they keep waiting until the window ends
+type ActionFreezeWindow struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	// the environments the frozen jobs deploy to, in lower case
+	Environments []string `json:"environments"`
+	// the glob patterns of the runs-on labels of the frozen jobs, like "deploy-*"
+	Labels []string `json:"labels"`
+	// swagger:strfmt date-time
+	Start time.Time `json:"start_at"`
+	// swagger:strfmt date-time
+	End time.Time `json:"end_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateActionFreezeWindowOption options when creating a freeze window
+type CreateActionFreezeWindowOption struct {
+	// required: true
+	Name   string `json:"name" binding:"Required;MaxSize(255)"`
+	Reason string `json:"reason"`
+	// the environments the frozen jobs deploy to, case-insensitive
+	Environments []string `json:"environments"`
+	// the glob patterns of the runs-on labels of the frozen jobs, at least one environment or label is required
+	Labels []string `json:"labels"`
+	// required: true
+	// swagger:strfmt date-time
+	Start time.Time `json:"start_at" binding:"Required"`
+	// required: true
+	// swagger:strfmt date-time
+	End time.Time `json:"end_at" binding:"Required"`
+}
+
+// BackfillActionRunsOption options for synthesizing push events for existing tags and commits to trigger their workflows
+type BackfillActionRunsOption struct {
+	// glob patterns of the tags to backfill, like "v1.*"
+	Tags []string `json:"tags"`
+	// the branch whose commits are backfilled
+	Branch string `json:"branch"`
+	// the commits of the branch after this commit are backfilled, required if branch is set
+	Since string `json:"since"`
+	// the last commit of the branch to backfill, the head of the branch if empty
+	Until string `json:"until"`
+}
+
+// ActionBackfilledEvent represents a push event synthesized for an existing tag or commit
+type ActionBackfilledEvent struct {
+	Ref       string `json:"ref"`
+	CommitSHA string `json:"commit_sha"`
+}
+
+// SimulateActionTriggerOption options for simulating an event to find the workflows it would trigger
+type SimulateActionTriggerOption struct {
+	// the type of the event, like "push" or "pull_request"
+	// required: true
+	Event string `json:"event" binding:"Required"`
+	// the ref of the event, a branch or tag name is also accepted
+	Ref string `json:"ref"`
+	// the JSON payload of the event, a push of the ref is simulated if it's empty
+	Payload string `json:"payload"`
+}
+
+// ActionSimulatedJob represents a job which would be created by a simulated event
+type ActionSimulatedJob struct {
+	JobID  string   `json:"job_id"`
+	Name   string   `json:"name"`
+	Needs  []string `json:"needs"`
+	RunsOn []string `json:"runs_on"`
+}
+
+// ActionSimulatedWorkflow represents a workflow which would be triggered by a simulated event
+type ActionSimulatedWorkflow struct {
+	WorkflowID   string `json:"workflow_id"`
+	TriggerEvent string `json:"trigger_event"`
+	// the error of parsing the jobs of the workflow
+	Error string                `json:"error,omitempty"`
+	Jobs  []*ActionSimulatedJob `json:"jobs"`
+}
+
+// ActionTriggerSimulation represents the workflows which would be triggered by a simulated event
+type ActionTriggerSimulation struct {
+	Ref       string `json:"ref"`
+	CommitSHA string `json:"commit_sha"`
+	// whether all workflows are skipped because of a skip string in the commit message or the pull request title
+	Skipped   bool                       `json:"skipped"`
+	Workflows []*ActionSimulatedWorkflow `json:"workflows"`
+}
+
+// ActionCommitGatingWorkflow represents a workflow expected to run for a commit, or which has run or failed to be triggered for it
+type ActionCommitGatingWorkflow struct {
+	WorkflowID string `json:"workflow_id"`
+	// the event of the workflow, empty if the workflow is invalid
+	Event string `json:"event"`
+	// enum: ran,skipped_by_path_filter,skipped_by_commit_message,disabled,failed_to_trigger,missing
+	State string `json:"state"`
+	// the latest run of the workflow for the event, 0 if it didn't run
+	RunID     int64  `json:"run_id"`
+	RunNumber int64  `json:"run_number"`
+	HTMLURL   string `json:"html_url"`
+	// enum: unknown,waiting,running,success,failure,cancelled,skipped,blocked
+	Status string `json:"status"`
+	// the final result of the run, empty until it's done
+	// enum: success,failure,cancelled,skipped
+	Conclusion string `json:"conclusion"`
+	// why the run wasn't created if the state is failed_to_trigger
+	// enum: invalid_workflow,quota_exceeded,actor_blocked,policy_rejected,internal_error
+	FailureReason  string `json:"failure_reason"`
+	FailureMessage string `json:"failure_message"`
+}
+
+// ActionCommitGating represents the workflows of a commit, to debug its required checks
+type ActionCommitGating struct {
+	Ref       string                        `json:"ref"`
+	CommitSHA string                        `json:"commit_sha"`
+	Workflows []*ActionCommitGatingWorkflow `json:"workflows"`
+}
+
+// ActionRunJobDiff represents a job which differs between a run and the replay of its event
+type ActionRunJobDiff struct {
+	JobID string `json:"job_id"`
+	Name  string `json:"name"`
+	// added if the replayed event would create the job but the run hasn't it, removed if it's the opposite
+	// enum: added,removed,changed
+	Change string `json:"change"`
+	// the changed fields of the changed job, "definition" is the workflow the runners receive
+	Fields []string `json:"fields"`
+}
+
+// ActionRunReplay represents the jobs the stored event of a run would create by the current parsers, compared with the jobs of the run
+type ActionRunReplay struct {
+	RunID        int64  `json:"run_id"`
+	RunNumber    int64  `json:"run_number"`
+	Repository   string `json:"repository"`
+	WorkflowID   string `json:"workflow_id"`
+	Event        string `json:"event"`
+	TriggerEvent string `json:"trigger_event"`
+	CommitSHA    string `json:"commit_sha"`
+	// whether the workflow of the run is still triggered by the event
+	Triggered bool `json:"triggered"`
+	// the error of parsing the jobs of the workflow
+	Error string `json:"error,omitempty"`
+	// whether the replayed event would create different jobs than the run has
+	Changed bool                  `json:"changed"`
+	Jobs    []*ActionSimulatedJob `json:"jobs"`
+	Diffs   []*ActionRunJobDiff   `json:"diffs"`
+}
+
+// ActionDeadLetter represents an event whose workflows couldn't be triggered, kept to be inspected and retried
+type ActionDeadLetter struct {
+	ID     int64 `json:"id"`
+	RepoID int64 `json:"repo_id"`
+	// the full name of the repository, empty if it has been deleted
+	Repository string `json:"repository"`
+	// the notifier method which handled the event
+	Method    string `json:"method"`
+	Event     string `json:"event"`
+	Ref       string `json:"ref"`
+	CommitSHA string `json:"commit_sha"`
+	// the payload of the event in JSON, empty if the event had none
+	Payload string `json:"payload"`
+	// the error of the latest attempt, with the stack if it panicked
+	Error    string `json:"error"`
+	Attempts int64  `json:"attempts"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionOutboxEvent is a side effect of a change to a run which has failed too many times to be processed
+type ActionOutboxEvent struct {
+	ID     int64 `json:"id"`
+	RunID  int64 `json:"run_id"`
+	RepoID int64 `json:"repo_id"`
+	// the full name of the repository, empty if it has been deleted
+	Repository string `json:"repository"`
+	// the change to the run, "run_created" or "run_done"
+	Type string `json:"type"`
+	// the steps done by the previous attempts, they aren't repeated by the retry
+	DoneSteps []string `json:"done_steps"`
+	Attempts  int      `json:"attempts"`
+	// the error of the latest attempt
+	LastError string `json:"last_error"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// EstimateActionDispatchOption options for estimating the cost of dispatching a workflow
+type EstimateActionDispatchOption struct {
+	// the file name of the workflow
+	// required: true
+	WorkflowID string `json:"workflow_id" binding:"Required"`
+	// the branch or tag to dispatch the workflow on, the default branch if empty
+	Ref    string            `json:"ref"`
+	Inputs map[string]string `json:"inputs"`
+}
+
+// ActionDispatchEstimateJob represents a job of a workflow with its matrix expanded
+type ActionDispatchEstimateJob struct {
+	JobID      string   `json:"job_id"`
+	RunsOn     []string `json:"runs_on"`
+	MatrixSize int      `json:"matrix_size"`
+	// the average duration of the job in the sampled runs, in seconds, 0 if it hasn't succeeded in them
+	AverageDuration int64   `json:"average_duration"`
+	Minutes         float64 `json:"minutes"`
+	// the minutes multiplied by the weights of the labels of the runners, see RUNNER_LABEL_WEIGHTS in the [actions] config
+	WeightedMinutes float64 `json:"weighted_minutes"`
+}
+
+// ActionDispatchEstimate represents the estimated cost of dispatching a workflow by its latest successful runs
+type ActionDispatchEstimate struct {
+	WorkflowID string `json:"workflow_id"`
+	Ref        string `json:"ref"`
+	CommitSHA  string `json:"commit_sha"`
+	// the number of the jobs which would be created, with their matrices expanded
+	JobCount int `json:"job_count"`
+	// the number of the latest successful runs of the workflow the durations are averaged over
+	SampledRuns int `json:"sampled_runs"`
+	// the average duration of the sampled runs, in seconds
+	AverageRunDuration int64                        `json:"average_run_duration"`
+	Minutes            float64                      `json:"minutes"`
+	WeightedMinutes    float64                      `json:"weighted_minutes"`
+	Jobs               []*ActionDispatchEstimateJob `json:"jobs"`
+}
+
+// ActionDispatchPreset represents a named set of the inputs to dispatch a workflow
+type ActionDispatchPreset struct {
+	Name string `json:"name"`
+	// the file name of the workflow
+	WorkflowID string `json:"workflow_id"`
+	// the branch or tag to dispatch the workflow on, the default branch if empty
+	Ref    string            `json:"ref"`
+	Inputs map[string]string `json:"inputs"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SaveActionDispatchPresetOption options for creating or replacing a dispatch preset
+type SaveActionDispatchPresetOption struct {
+	// the file name of the workflow, it must be triggered by workflow_dispatch
+	// required: true
+	WorkflowID string `json:"workflow_id" binding:"Required;MaxSize(255)"`
+	// the branch or tag to dispatch the workflow on, the default branch if empty
+	Ref    string            `json:"ref" binding:"MaxSize(255)"`
+	Inputs map[string]string `json:"inputs"`
+}
+
+// ActionRunFilter represents a named set of the filters of the runs list of a repository saved by a user
+type ActionRunFilter struct {
+	Name string `json:"name"`
+	// the file name of the workflow, all workflows if empty
+	WorkflowID string `json:"workflow_id"`
+	// the branch of the runs, all refs if empty
+	Branch string `json:"branch"`
+	// the username of the user who triggered the runs, all users if empty
+	Actor string `json:"actor"`
+	// the status of the runs, all statuses if empty
+	Status string `json:"status"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SaveActionRunFilterOption options for creating or replacing a run filter
+type SaveActionRunFilterOption struct {
+	// the file name of the workflow, all workflows if empty
+	WorkflowID string `json:"workflow_id" binding:"MaxSize(255)"`
+	// the branch of the runs, all refs if empty
+	Branch string `json:"branch" binding:"MaxSize(255)"`
+	// the username of the user who triggered the runs, all users if empty
+	Actor string `json:"actor"`
+	// the status of the runs, all statuses if empty
+	// enum: success,failure,cancelled,skipped,waiting,running,blocked
+	Status string `json:"status"`
+}
+
+// ActionArtifact represents an artifact uploaded by a run
+type ActionArtifact struct {
+	Name string `json:"name"`
+	// the total size of the files in bytes
+	Size int64 `json:"size"`
+	// enum: completed,expired
+	Status string `json:"status"`
+	// swagger:strfmt date-time
+	Expires time.Time `json:"expires_at"`
+}
+
+// ActionDownloadURL represents a link to download an artifact or the logs of a job without authentication until it expires
+type ActionDownloadURL struct {
+	URL string `json:"url"`
+	// swagger:strfmt date-time
+	Expires time.Time `json:"expires_at"`
+}
+
+// ActionsStatus represents the status of Actions of the instance
+type ActionsStatus struct {
+	Enabled bool `json:"enabled"`
+	// whether the server is shutting down, the jobs aren't assigned to the runners until it's restarted
+	Draining bool `json:"draining"`
+	// the maintenance announced by the admins, empty if there is no maintenance
+	Maintenance string `json:"maintenance"`
+	// whether the jobs have waited for the runners longer, or are more, than the thresholds of the admins
+	Congested     bool  `json:"congested"`
+	OnlineRunners int64 `json:"online_runners"`
+	RunningJobs   int64 `json:"running_jobs"`
+	WaitingJobs   int64 `json:"waiting_jobs"`
+	// the waiting jobs by the labels of the runners they require, a job requiring multiple labels is counted for each of them
+	Queue []*ActionsQueueClass `json:"queue"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionsQueueClass represents the jobs waiting for the runners with a label
+type ActionsQueueClass struct {
+	Label   string `json:"label"`
+	Waiting int64  `json:"waiting"`
+	// how long the longest waiting job has waited, in seconds
+	LongestWait int64 `json:"longest_wait"`
+}
+
+// SetActionRunnerTargetVersionOption options for starting the rollout of a version to the runners
+type SetActionRunnerTargetVersionOption struct {
+	// the version the idle runners are signaled to update themselves to, like "v0.2.11", empty stops the rollout
+	Version string `json:"version" binding:"MaxSize(64)"`
+}
+
+// ActionRunnerUpdateRollout represents the rollout of the target version to the runners
+type ActionRunnerUpdateRollout struct {
+	// empty if there is no rollout
+	TargetVersion string                `json:"target_version"`
+	Runners       []*ActionRunnerUpdate `json:"runners"`
+	// the statuses of the runners by their labels, a runner with multiple labels is counted for each of them
+	Labels []*ActionRunnerUpdateLabel `json:"labels"`
+}
+
+// ActionRunnerUpdate represents the status of a runner in the rollout of the target version
+type ActionRunnerUpdate struct {
+	ID      int64    `json:"id"`
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Labels  []string `json:"labels"`
+	// the runners driven by the executors are unsupported, they can't be signaled
+	// enum: up_to_date,pending,signaled,failed,unsupported
+	Status string `json:"status"`
+	// when the runner was signaled to update itself to the target version, empty if it hasn't been signaled
+	// swagger:strfmt date-time
+	Signaled *time.Time `json:"signaled_at"`
+}
+
+// ActionRunnerUpdateLabel represents how many runners with a label are in each status of the rollout of the target version
+type ActionRunnerUpdateLabel struct {
+	Label       string `json:"label"`
+	Total       int64  `json:"total"`
+	UpToDate    int64  `json:"up_to_date"`
+	Pending     int64  `json:"pending"`
+	Signaled    int64  `json:"signaled"`
+	Failed      int64  `json:"failed"`
+	Unsupported int64  `json:"unsupported"`
+}
@@ -38,3 +38,24 @@ type CreateTagOption struct {
 	Message string `json:"message"`
 	Target  string `json:"target"`
 }
+
+// BumpTagOption options when creating the tag of the next semantic version
+type BumpTagOption struct {
+	// the part of the version to bump, a prerelease is released by bumping the part it's a prerelease of
+	// required: true
+	// enum: major,minor,patch,prerelease
+	Bump string `json:"bump" binding:"Required;In(major,minor,patch,prerelease)"`
+	// the branch or the commit of the tag, the default branch if it's empty
+	Target string `json:"target"`
+	// the message of the annotated tag, a lightweight tag is created if it's empty
+	Message string `json:"message"`
+	// the path of the configuration file in the default branch, `.gitea/release.yml` or `.github/release.yml` if it's empty
+	ConfigurationFilePath string `json:"configuration_file_path"`
+}
+
+// BumpedTag represents the tag created for the next semantic version
+type BumpedTag struct {
+	Tag *Tag `json:"tag"`
+	// the tag of the previous version, empty if it's the first version
+	PreviousTagName string `json:"previous_tag_name"`
+}
@@ -10,8 +10,13 @@ import "time"
 type Secret struct {
 	// the secret's name
 	Name string `json:"name"`
+	// whether the secret is only available to the runs triggered by protected branches or tags
+	ProtectedRefsOnly bool `json:"protected_refs_only"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
+	// when the value of the secret was found in the logs of a run, it's flagged for rotation until its data is updated
+	// swagger:strfmt date-time
+	Leaked *time.Time `json:"leaked_at,omitempty"`
 }
 
 // CreateOrUpdateSecretOption options when creating or updating secret
@@ -21,4 +26,8 @@ type CreateOrUpdateSecretOption struct {
 	//
 	// required: true
 	Data string `json:"data" binding:"Required"`
+	// Only provide the secret to the runs triggered by protected branches or tags,
+	// so it will never be available to pull requests or unprotected branches.
+	// When updating a secret, the restriction is kept unchanged if it's not specified.
+	ProtectedRefsOnly *bool `json:"protected_refs_only"`
 }
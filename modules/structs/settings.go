@@ -35,3 +35,13 @@ type GeneralAttachmentSettings struct {
 	MaxSize      int64  `json:"max_size"`
 	MaxFiles     int    `json:"max_files"`
 }
+
+// ActionDefaultEnvVariable is an environment variable defined for every job of Actions
+type ActionDefaultEnvVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// "gitea" if Gitea sets it unless the workflow sets it, "runner" if the runner sets it
+	Source string `json:"source"`
+	// the value set by Gitea, or the expression of the github context which the value comes from, empty if the runner sets it
+	Value string `json:"value"`
+}
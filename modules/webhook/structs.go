@@ -26,6 +26,7 @@ type HookEvents struct {
 	Repository               bool `json:"repository"`
 	Release                  bool `json:"release"`
 	Package                  bool `json:"package"`
+	WorkflowRun              bool `json:"workflow_run"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -32,6 +32,8 @@ const (
 	HookEventRelease                   HookEventType = "release"
 	HookEventPackage                   HookEventType = "package"
 	HookEventSchedule                  HookEventType = "schedule"
+	HookEventWorkflowDispatch          HookEventType = "workflow_dispatch"
+	HookEventWorkflowRun               HookEventType = "workflow_run"
 )
 
 // Event returns the HookEventType as an event string
@@ -64,6 +66,8 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventWorkflowRun:
+		return "workflow_run"
 	}
 	return ""
 }
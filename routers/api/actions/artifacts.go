@@ -119,6 +119,8 @@ func ArtifactsRoutes(prefix string) *web.Route {
 		m.Get("/{artifact_hash}/download_url", r.getDownloadArtifactURL)
 		m.Get("/{artifact_id}/download", r.downloadArtifact)
 	})
+	m.Combo(handoffRouteBase + "/{name}").Get(downloadHandoffBlob).Put(uploadHandoffBlob)
+	m.Post(summaryRouteBase, appendSummary)
 
 	return m
 }
@@ -250,7 +252,7 @@ func (ar artifactRoutes) uploadArtifact(ctx *ArtifactContext) {
 	}
 
 	// get artifact retention days
-	expiredDays := setting.Actions.ArtifactRetentionDays
+	expiredDays := getArtifactRetentionDays(ctx, task)
 	if queryRetentionDays := ctx.Req.URL.Query().Get("retentionDays"); queryRetentionDays != "" {
 		expiredDays, err = strconv.ParseInt(queryRetentionDays, 10, 64)
 		if err != nil {
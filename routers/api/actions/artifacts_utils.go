@@ -4,6 +4,7 @@
 package actions
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
 	"net/http"
@@ -11,7 +12,10 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -23,15 +27,49 @@ const (
 // The rules are from https://github.com/actions/toolkit/blob/main/packages/artifact/src/internal/path-and-artifact-name-validation.ts#L32
 var invalidArtifactNameChars = strings.Join([]string{"\\", "/", "\"", ":", "<", ">", "|", "*", "?", "\r", "\n"}, "")
 
+// maxArtifactNameLength is the length of the column of the artifact name
+const maxArtifactNameLength = 255
+
+// checkArtifactName returns an error telling why the artifact name is invalid, so the runner shows an actionable message.
+// The name is used in the paths of the storage and of the downloaded files, so it can't point to another directory.
+func checkArtifactName(artifactName string) error {
+	switch {
+	case artifactName == "":
+		return util.NewInvalidArgumentErrorf("the artifact name is empty")
+	case artifactName == "." || artifactName == "..":
+		return util.NewInvalidArgumentErrorf("the artifact name %q isn't a valid file name", artifactName)
+	case len(artifactName) > maxArtifactNameLength:
+		return util.NewInvalidArgumentErrorf("the artifact name is longer than %d bytes", maxArtifactNameLength)
+	case strings.ContainsAny(artifactName, invalidArtifactNameChars):
+		return util.NewInvalidArgumentErrorf(`the artifact name %q contains invalid characters, it can't contain any of \ / " : < > | * ? \r \n`, artifactName)
+	}
+	return nil
+}
+
 func validateArtifactName(ctx *ArtifactContext, artifactName string) bool {
-	if strings.ContainsAny(artifactName, invalidArtifactNameChars) {
-		log.Error("Error checking artifact name contains invalid character")
-		ctx.Error(http.StatusBadRequest, "Error checking artifact name contains invalid character")
+	if err := checkArtifactName(artifactName); err != nil {
+		log.Error("Error checking artifact name: %v", err)
+		ctx.Error(http.StatusBadRequest, err.Error())
 		return false
 	}
 	return true
 }
 
+// getArtifactRetentionDays returns the default retention days of the artifacts uploaded by the task,
+// the repository could override setting.Actions.ArtifactRetentionDays in its Actions settings.
+func getArtifactRetentionDays(ctx context.Context, task *actions.ActionTask) int64 {
+	repo, err := repo_model.GetRepositoryByID(ctx, task.RepoID)
+	if err != nil {
+		log.Error("Error getting repository %d: %v", task.RepoID, err)
+		return setting.Actions.ArtifactRetentionDays
+	}
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		return setting.Actions.ArtifactRetentionDays
+	}
+	return cfgUnit.ActionsConfig().GetArtifactRetentionDays()
+}
+
 func validateRunID(ctx *ArtifactContext) (*actions.ActionTask, int64, bool) {
 	task := ctx.ActionTask
 	runID := ctx.ParamsInt64("run_id")
@@ -82,6 +82,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -96,6 +97,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
@@ -218,6 +220,72 @@ func (r *artifactV4Routes) getArtifactByName(ctx *ArtifactContext, runID int64,
 	return &art, nil
 }
 
+// maxArtifactNameSuffix is the largest suffix of the names of the artifacts uploaded with the same name in a run
+const maxArtifactNameSuffix = 100
+
+// resolveArtifactName returns the name to create the artifact with, if the run has an uploaded artifact with the name,
+// it depends on setting.Actions.ArtifactNameCollision, the error is util.ErrAlreadyExist if the artifact is rejected.
+func (r *artifactV4Routes) resolveArtifactName(ctx *ArtifactContext, runID int64, name string) (string, error) {
+	existing, err := r.getArtifactByName(ctx, runID, name)
+	if errors.Is(err, util.ErrNotExist) {
+		return name, nil
+	} else if err != nil {
+		return "", err
+	}
+	if existing.Status != int64(actions.ArtifactStatusUploadConfirmed) {
+		// it's being uploaded and the request is retried, or it has been deleted or expired, e.g. by the "overwrite" option of upload-artifact
+		return name, nil
+	}
+
+	switch setting.Actions.ArtifactNameCollision {
+	case setting.ArtifactNameCollisionOverwrite:
+		return name, nil
+	case setting.ArtifactNameCollisionSuffix:
+		for i := 2; i <= maxArtifactNameSuffix; i++ {
+			suffixed := fmt.Sprintf("%s-%d", name, i)
+			if len(suffixed) > maxArtifactNameLength {
+				break
+			}
+			if _, err := r.getArtifactByName(ctx, runID, suffixed); errors.Is(err, util.ErrNotExist) {
+				return suffixed, nil
+			} else if err != nil {
+				return "", err
+			}
+		}
+		return "", util.NewAlreadyExistErrorf("too many artifacts named %q have been uploaded in the workflow run, use another name", name)
+	}
+	return "", util.NewAlreadyExistErrorf("an artifact named %q has been uploaded in the workflow run, "+
+		"set the \"overwrite\" option of actions/upload-artifact to replace it, or use another name", name)
+}
+
+// getUploadingArtifact returns the artifact being uploaded with the name requested by the runner,
+// which could have been created with a suffixed name, see resolveArtifactName
+func (r *artifactV4Routes) getUploadingArtifact(ctx *ArtifactContext, runID int64, name string) (*actions.ActionArtifact, error) {
+	artifact, err := r.getArtifactByName(ctx, runID, name)
+	if err != nil || artifact.Status == int64(actions.ArtifactStatusUploadPending) ||
+		setting.Actions.ArtifactNameCollision != setting.ArtifactNameCollisionSuffix {
+		return artifact, err
+	}
+	for i := 2; i <= maxArtifactNameSuffix; i++ {
+		suffixed, err := r.getArtifactByName(ctx, runID, fmt.Sprintf("%s-%d", name, i))
+		if errors.Is(err, util.ErrNotExist) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if suffixed.Status == int64(actions.ArtifactStatusUploadPending) {
+			return suffixed, nil
+		}
+	}
+	return artifact, nil
+}
+
+// sendTwirpError responds the error in the format of twirp, the runner shows its message to the users
+func (r *artifactV4Routes) sendTwirpError(ctx *ArtifactContext, status int, code, msg string) {
+	log.Error("Error %s: %s", code, msg)
+	ctx.JSON(status, map[string]string{"code": code, "msg": msg})
+}
+
 func (r *artifactV4Routes) parseProtbufBody(ctx *ArtifactContext, req protoreflect.ProtoMessage) bool {
 	body, err := io.ReadAll(ctx.Req.Body)
 	if err != nil {
@@ -252,14 +320,27 @@ func (r *artifactV4Routes) createArtifact(ctx *ArtifactContext) {
 	if ok := r.parseProtbufBody(ctx, &req); !ok {
 		return
 	}
-	_, _, ok := validateRunIDV4(ctx, req.WorkflowRunBackendId)
+	_, runID, ok := validateRunIDV4(ctx, req.WorkflowRunBackendId)
 	if !ok {
 		return
 	}
 
-	artifactName := req.Name
+	if err := checkArtifactName(req.Name); err != nil {
+		r.sendTwirpError(ctx, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	artifactName, err := r.resolveArtifactName(ctx, runID, req.Name)
+	if err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			r.sendTwirpError(ctx, http.StatusConflict, "already_exists", err.Error())
+			return
+		}
+		log.Error("Error resolve artifact name: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error resolve artifact name")
+		return
+	}
 
-	rententionDays := setting.Actions.ArtifactRetentionDays
+	rententionDays := getArtifactRetentionDays(ctx, ctx.ActionTask)
 	if req.ExpiresAt != nil {
 		rententionDays = int64(time.Until(req.ExpiresAt.AsTime()).Hours() / 24)
 	}
@@ -270,6 +351,9 @@ func (r *artifactV4Routes) createArtifact(ctx *ArtifactContext) {
 		ctx.Error(http.StatusInternalServerError, "Error create or get artifact")
 		return
 	}
+	// the existing artifact is uploaded again if it's overwritten, or it has been deleted or expired
+	artifact.Status = int64(actions.ArtifactStatusUploadPending)
+	artifact.ExpiredUnix = timeutil.TimeStamp(time.Now().Unix() + 3600*24*rententionDays)
 	artifact.ContentEncoding = ArtifactV4ContentEncoding
 	if err := actions.UpdateArtifactByID(ctx, artifact.ID, artifact); err != nil {
 		log.Error("Error UpdateArtifactByID: %v", err)
@@ -337,7 +421,7 @@ func (r *artifactV4Routes) finalizeArtifact(ctx *ArtifactContext) {
 	}
 
 	// get artifact by name
-	artifact, err := r.getArtifactByName(ctx, runID, req.Name)
+	artifact, err := r.getUploadingArtifact(ctx, runID, req.Name)
 	if err != nil {
 		log.Error("Error artifact not found: %v", err)
 		ctx.Error(http.StatusNotFound, "Error artifact not found")
@@ -0,0 +1,95 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// Download API serves the artifacts and the logs by the links signed by Gitea, without authentication until they expire,
+// the links are requested by the API of the repository, see actions_service.GetArtifactDownloadURL.
+//
+// 1. Download an artifact of a run as a zip file
+// GET: /api/actions/downloads/artifacts/{id}?name={name}&expires={expires}&sig={sig}
+//
+// 2. Download the logs of the latest attempt of a job
+// GET: /api/actions/downloads/logs/{id}?redact={redact}&expires={expires}&sig={sig}
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/common"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+func downloadRoutes(m *web.Route) {
+	m.Group("/downloads", func() {
+		m.Get("/"+actions_service.DownloadKindArtifacts+"/{id}", downloadSignedArtifact)
+		m.Get("/"+actions_service.DownloadKindLogs+"/{id}", downloadSignedLogs)
+	}, downloadContexter())
+}
+
+// downloadContexter prepares the context of the signed downloads, there is no task since they aren't requested by the runners
+func downloadContexter() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			base, baseCleanUp := context.NewBaseContext(resp, req)
+			defer baseCleanUp()
+
+			ctx := &ArtifactContext{Base: base}
+			ctx.AppendContextValue(artifactContextKey, ctx)
+			next.ServeHTTP(ctx.Resp, ctx.Req)
+		})
+	}
+}
+
+func verifyDownloadSignature(ctx *ArtifactContext, kind, arg string) (int64, bool) {
+	id := ctx.ParamsInt64("id")
+	if err := actions_service.VerifyDownloadSignature(kind, id, arg, ctx.FormString("expires"), ctx.FormString("sig")); err != nil {
+		ctx.Error(http.StatusForbidden, err.Error())
+		return 0, false
+	}
+	return id, true
+}
+
+func downloadSignedArtifact(ctx *ArtifactContext) {
+	name := ctx.FormString("name")
+	runID, ok := verifyDownloadSignature(ctx, actions_service.DownloadKindArtifacts, name)
+	if !ok {
+		return
+	}
+	if err := common.DownloadActionsArtifact(ctx.Base, runID, name); err != nil {
+		writeDownloadError(ctx, err)
+	}
+}
+
+func downloadSignedLogs(ctx *ArtifactContext) {
+	redact := ctx.FormString("redact")
+	jobID, ok := verifyDownloadSignature(ctx, actions_service.DownloadKindLogs, redact)
+	if !ok {
+		return
+	}
+	job, err := actions_model.GetRunJobByID(ctx, jobID)
+	if err != nil {
+		writeDownloadError(ctx, err)
+		return
+	}
+	// the value has been signed, it's always a valid bool
+	redacted, _ := strconv.ParseBool(redact)
+	if err := common.DownloadActionsRunJobLogs(ctx.Base, job, redacted); err != nil {
+		writeDownloadError(ctx, err)
+	}
+}
+
+func writeDownloadError(ctx *ArtifactContext, err error) {
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.Error(http.StatusNotFound, err.Error())
+		return
+	}
+	log.Error("Error downloading: %v", err)
+	ctx.Error(http.StatusInternalServerError, "Error downloading")
+}
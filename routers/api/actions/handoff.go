@@ -0,0 +1,80 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// Handoff API passes transient files between the jobs of a run, they are encrypted at rest,
+// and deleted once the run has been done for longer than the artifact retention.
+// Unlike artifacts, handoff blobs are never visible to users.
+//
+// 1. Upload blob
+// PUT: /api/actions_pipeline/_apis/pipelines/workflows/{run_id}/handoff/{name}
+// the request body is the content of the blob, a blob with the same name will be replaced
+//
+// 2. Download blob
+// GET: /api/actions_pipeline/_apis/pipelines/workflows/{run_id}/handoff/{name}
+// Response:
+// the content of the blob
+//
+// Both requests are authenticated with Bearer ACTIONS_RUNTIME_TOKEN, and {run_id} must be the run of the task.
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+const handoffRouteBase = "/_apis/pipelines/workflows/{run_id}/handoff"
+
+func uploadHandoffBlob(ctx *ArtifactContext) {
+	task, _, ok := validateRunID(ctx)
+	if !ok {
+		return
+	}
+
+	blob, err := actions_service.UploadHandoffBlob(ctx, task, ctx.Params("name"), ctx.Req.Body)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Error uploading handoff blob: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Debug("[handoff] uploadHandoffBlob, run: %d, name: %s, size: %d", blob.RunID, blob.Name, blob.Size)
+	ctx.Status(http.StatusCreated)
+}
+
+func downloadHandoffBlob(ctx *ArtifactContext) {
+	_, runID, ok := validateRunID(ctx)
+	if !ok {
+		return
+	}
+
+	blob, reader, err := actions_service.OpenHandoffBlob(ctx, runID, ctx.Params("name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		log.Error("Error opening handoff blob: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer reader.Close()
+
+	ctx.SetServeHeaders(&context.ServeHeaderOptions{
+		Filename:      blob.Name,
+		ContentLength: &blob.Size,
+		LastModified:  blob.Updated.AsLocalTime(),
+	})
+	if _, err := io.Copy(ctx.Resp, reader); err != nil {
+		log.Error("Error serving handoff blob: %v", err)
+	}
+}
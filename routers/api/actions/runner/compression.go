@@ -0,0 +1,37 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runner
+
+import (
+	"connectrpc.com/connect"
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMaxMemory is the most memory a request compressed with zstd could be decompressed into
+const zstdMaxMemory = 64 << 20
+
+// withZstd accepts the requests compressed with zstd besides gzip, which is much cheaper for the verbose logs uploaded by the runners.
+// The accepted compressions are advertised by the responses with the "Accept-Encoding" headers,
+// so the runners could choose the compression by them.
+var withZstd = connect.WithCompression("zstd", newZstdDecompressor, newZstdCompressor)
+
+func newZstdDecompressor() connect.Decompressor {
+	// the options are valid, so it never fails
+	d, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(zstdMaxMemory))
+	return &zstdDecompressor{d}
+}
+
+func newZstdCompressor() connect.Compressor {
+	e, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	return e
+}
+
+// zstdDecompressor is reused by connect after being closed, so closing it shouldn't release the decoder
+type zstdDecompressor struct {
+	*zstd.Decoder
+}
+
+func (d *zstdDecompressor) Close() error {
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runner
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZstdDecompressor(t *testing.T) {
+	data := strings.Repeat("a verbose log line\n", 1000)
+
+	// the compressors and decompressors are reused by connect, so they should work after being closed
+	c, d := newZstdCompressor(), newZstdDecompressor()
+	for i := 0; i < 2; i++ {
+		var compressed bytes.Buffer
+		c.Reset(&compressed)
+		_, err := c.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, c.Close())
+		assert.Less(t, compressed.Len(), len(data)/10)
+
+		require.NoError(t, d.Reset(&compressed))
+		decompressed, err := io.ReadAll(d)
+		require.NoError(t, err)
+		require.NoError(t, d.Close())
+		assert.Equal(t, data, string(decompressed))
+	}
+}
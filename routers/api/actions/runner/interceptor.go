@@ -23,6 +23,26 @@ import (
 const (
 	uuidHeaderKey  = "x-runner-uuid"
 	tokenHeaderKey = "x-runner-token"
+
+	// environmentHeaderKey is an optional header of UpdateTask requests,
+	// it's a JSON object of the environment where the task runs, see actions_model.TaskEnvironmentOS and other keys
+	environmentHeaderKey = "x-runner-environment"
+	// resourceUsageHeaderKey is an optional header of UpdateTask requests,
+	// it's a JSON object of the current resource usage of the task, see actions_model.TaskResourceSample
+	resourceUsageHeaderKey = "x-runner-resource-usage"
+	// errorClassHeaderKey is an optional header of UpdateTask requests,
+	// it tells whether the task fails because of the runner or its machine, see actions_model.TaskErrorClassInfrastructure
+	errorClassHeaderKey = "x-runner-error-class"
+	// updateVersionHeaderKey is a header of FetchTask responses to the idle runners, no task is assigned with it,
+	// the runner should update itself to the version and restart, see actions_service.PickRunnerUpdate
+	updateVersionHeaderKey = "x-runner-update-version"
+	// fetchWaitHeaderKey is an optional header of FetchTask requests, it's the seconds the runner would wait for a task,
+	// the request is held open until a task could be assigned or the wait elapses, see actions_service.WaitTasksVersion.
+	// The responses of the requests held open have it too, so the runner knows it could fetch again at once instead of sleeping.
+	fetchWaitHeaderKey = "x-runner-fetch-wait"
+	// logMaxRowsHeaderKey is a header of Declare responses, it's the most log rows accepted by an UpdateLog request,
+	// the runner could coalesce the rows up to it, and the rows beyond it aren't acknowledged, see actions_service.AppendTaskLogs
+	logMaxRowsHeaderKey = "x-runner-log-max-rows"
 )
 
 var withRunner = connect.WithInterceptors(connect.UnaryInterceptorFunc(func(unaryFunc connect.UnaryFunc) connect.UnaryFunc {
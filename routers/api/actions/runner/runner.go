@@ -6,13 +6,17 @@ package runner
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
-	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	actions_service "code.gitea.io/gitea/services/actions"
 
@@ -28,6 +32,7 @@ func NewRunnerServiceHandler() (string, http.Handler) {
 	return runnerv1connect.NewRunnerServiceHandler(
 		&Service{},
 		connect.WithCompressMinBytes(1024),
+		withZstd,
 		withRunner,
 	)
 }
@@ -61,9 +66,17 @@ func (s *Service) Register(
 	}
 
 	if runnerToken.RepoID > 0 {
-		if _, err := repo_model.GetRepositoryByID(ctx, runnerToken.RepoID); err != nil {
+		repo, err := repo_model.GetRepositoryByID(ctx, runnerToken.RepoID)
+		if err != nil {
 			return nil, errors.New("repository of the token not found")
 		}
+		policy, err := actions_model.GetRunnerSharingPolicy(ctx, repo.OwnerID)
+		if err != nil {
+			return nil, errors.New("can't get runner sharing policy of the repository owner")
+		}
+		if !policy.AllowsRepoRunners() {
+			return nil, errors.New("the owner of the repository doesn't allow runners of repositories")
+		}
 	}
 
 	labels := req.Msg.Labels
@@ -78,6 +91,9 @@ func (s *Service) Register(
 		Version:     req.Msg.Version,
 		AgentLabels: labels,
 	}
+	if err := actions_model.CheckRunnerLabelNamespaces(ctx, runner, labels); err != nil {
+		return nil, fmt.Errorf("invalid labels: %w", err)
+	}
 	if err := runner.GenerateToken(); err != nil {
 		return nil, errors.New("can't generate token")
 	}
@@ -112,13 +128,19 @@ func (s *Service) Declare(
 	req *connect.Request[runnerv1.DeclareRequest],
 ) (*connect.Response[runnerv1.DeclareResponse], error) {
 	runner := GetRunner(ctx)
+	if err := actions_model.CheckRunnerLabelNamespaces(ctx, runner, req.Msg.Labels); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid labels: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "check labels: %v", err)
+	}
 	runner.AgentLabels = req.Msg.Labels
-	runner.Version = req.Msg.Version
-	if err := actions_model.UpdateRunner(ctx, runner, "agent_labels", "version"); err != nil {
+	runner.DeclareVersion(req.Msg.Version)
+	if err := actions_model.UpdateRunner(ctx, runner, "agent_labels", "version", "update_failed"); err != nil {
 		return nil, status.Errorf(codes.Internal, "update runner: %v", err)
 	}
 
-	return connect.NewResponse(&runnerv1.DeclareResponse{
+	res := connect.NewResponse(&runnerv1.DeclareResponse{
 		Runner: &runnerv1.Runner{
 			Id:      runner.ID,
 			Uuid:    runner.UUID,
@@ -127,7 +149,11 @@ func (s *Service) Declare(
 			Version: runner.Version,
 			Labels:  runner.AgentLabels,
 		},
-	}), nil
+	})
+	if setting.Actions.LogUploadMaxRows > 0 {
+		res.Header().Set(logMaxRowsHeaderKey, strconv.FormatInt(setting.Actions.LogUploadMaxRows, 10))
+	}
+	return res, nil
 }
 
 // FetchTask assigns a task to the runner
@@ -136,6 +162,10 @@ func (s *Service) FetchTask(
 	req *connect.Request[runnerv1.FetchTaskRequest],
 ) (*connect.Response[runnerv1.FetchTaskResponse], error) {
 	runner := GetRunner(ctx)
+	if runner.Executor != "" {
+		// the tasks of the runner are dispatched to its executor, polling them could make a task run twice
+		return nil, status.Errorf(codes.FailedPrecondition, "runner %d is driven by executor %q, it can't fetch tasks", runner.ID, runner.Executor)
+	}
 
 	var task *runnerv1.Task
 	tasksVersion := req.Msg.TasksVersion // task version from runner
@@ -152,11 +182,36 @@ func (s *Service) FetchTask(
 		latestVersion++
 	}
 
-	if tasksVersion != latestVersion {
+	// the idle runner is signaled to update itself instead of being assigned a task,
+	// the tasks version isn't changed so it could still pick the tasks if it doesn't update
+	if version, err := actions_service.PickRunnerUpdate(ctx, runner); err != nil {
+		log.Error("pick runner update failed: %v", err)
+	} else if version != "" {
+		res := connect.NewResponse(&runnerv1.FetchTaskResponse{
+			TasksVersion: tasksVersion,
+		})
+		res.Header().Set(updateVersionHeaderKey, version)
+		return res, nil
+	}
+
+	// the request is held open until a task could be assigned if the runner asks to wait,
+	// so the jobs start once they're queued instead of at the next poll of the runner
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(req.Header().Get(fetchWaitHeaderKey)); err == nil {
+		wait = actions_service.FetchTaskWait(time.Duration(seconds) * time.Second)
+	}
+	if wait > 0 && tasksVersion == latestVersion {
+		if latestVersion, err = actions_service.WaitTasksVersion(ctx, runner, tasksVersion, wait); err != nil {
+			return nil, status.Errorf(codes.Internal, "wait tasks version failed: %v", err)
+		}
+	}
+
+	// no task is assigned when shutting down, since it could be lost if the response is cut off
+	if tasksVersion != latestVersion && !actions_service.IsDrainingRunners() {
 		// if the task version in request is not equal to the version in db,
 		// it means there may still be some tasks not be assgined.
 		// try to pick a task for the runner that send the request.
-		if t, ok, err := pickTask(ctx, runner); err != nil {
+		if t, ok, err := actions_service.PickTask(ctx, runner); err != nil {
 			log.Error("pick task failed: %v", err)
 			return nil, status.Errorf(codes.Internal, "pick task: %v", err)
 		} else if ok {
@@ -167,6 +222,9 @@ func (s *Service) FetchTask(
 		Task:         task,
 		TasksVersion: latestVersion,
 	})
+	if wait > 0 {
+		res.Header().Set(fetchWaitHeaderKey, strconv.Itoa(int(wait/time.Second)))
+	}
 	return res, nil
 }
 
@@ -175,51 +233,55 @@ func (s *Service) UpdateTask(
 	ctx context.Context,
 	req *connect.Request[runnerv1.UpdateTaskRequest],
 ) (*connect.Response[runnerv1.UpdateTaskResponse], error) {
-	task, err := actions_model.UpdateTaskByState(ctx, req.Msg.State)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "update task: %v", err)
-	}
-
-	for k, v := range req.Msg.Outputs {
-		if len(k) > 255 {
-			log.Warn("Ignore the output of task %d because the key is too long: %q", task.ID, k)
-			continue
+	// validate the environment before updating the task, so an invalid header doesn't leave the task half updated
+	var env map[string]string
+	if header := req.Header().Get(environmentHeaderKey); header != "" {
+		if err := json.Unmarshal([]byte(header), &env); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid environment: %v", err)
 		}
-		// The value can be a maximum of 1 MB
-		if l := len(v); l > 1024*1024 {
-			log.Warn("Ignore the output %q of task %d because the value is too long: %v", k, task.ID, l)
-			continue
+		if err := actions_model.ValidateTaskEnvironment(env); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid environment: %v", err)
 		}
-		// There's another limitation on GitHub that the total of all outputs in a workflow run can be a maximum of 50 MB.
-		// We don't check the total size here because it's not easy to do, and it doesn't really worth it.
-		// See https://docs.github.com/en/actions/using-jobs/defining-outputs-for-jobs
+	}
 
-		if err := actions_model.InsertTaskOutputIfNotExist(ctx, task.ID, k, v); err != nil {
-			log.Warn("Failed to insert the output %q of task %d: %v", k, task.ID, err)
-			// It's ok not to return errors, the runner will resend the outputs.
+	var sample *actions_model.TaskResourceSample
+	if header := req.Header().Get(resourceUsageHeaderKey); header != "" {
+		sample = &actions_model.TaskResourceSample{}
+		if err := json.Unmarshal([]byte(header), sample); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid resource usage: %v", err)
+		}
+		if err := sample.Validate(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid resource usage: %v", err)
 		}
-	}
-	sentOutputs, err := actions_model.FindTaskOutputKeyByTaskID(ctx, task.ID)
-	if err != nil {
-		log.Warn("Failed to find the sent outputs of task %d: %v", task.ID, err)
-		// It's not to return errors, it can be handled when the runner resends sent outputs.
 	}
 
-	if err := task.LoadJob(ctx); err != nil {
-		return nil, status.Errorf(codes.Internal, "load job: %v", err)
+	errorClass := req.Header().Get(errorClassHeaderKey)
+	if errorClass != "" && !actions_model.IsValidReportedTaskErrorClass(errorClass) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid error class: %q", errorClass)
 	}
-	if err := task.Job.LoadRun(ctx); err != nil {
-		return nil, status.Errorf(codes.Internal, "load run: %v", err)
+
+	done, ok := actions_service.BeginRunnerUpdate()
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "the server is shutting down, retry later")
 	}
+	defer done()
 
-	// don't create commit status for cron job
-	if task.Job.Run.ScheduleID == 0 {
-		actions_service.CreateCommitStatus(ctx, task.Job)
+	task, sentOutputs, err := actions_service.UpdateTaskByState(ctx, req.Msg.State, req.Msg.Outputs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "update task: %v", err)
 	}
 
-	if req.Msg.State.Result != runnerv1.Result_RESULT_UNSPECIFIED {
-		if err := actions_service.EmitJobsIfReady(task.Job.RunID); err != nil {
-			log.Error("Emit ready jobs of run %d: %v", task.Job.RunID, err)
+	if err := actions_model.UpdateTaskEnvironment(ctx, task, env); err != nil {
+		log.Warn("Failed to record the environment of task %d: %v", task.ID, err)
+	}
+	if sample != nil {
+		if err := actions_model.AddTaskResourceSample(ctx, task, sample); err != nil {
+			log.Warn("Failed to record the resource usage of task %d: %v", task.ID, err)
+		}
+	}
+	if errorClass != "" {
+		if err := actions_model.SetTaskErrorClassHint(ctx, task, errorClass); err != nil {
+			log.Warn("Failed to record the error class of task %d: %v", task.ID, err)
 		}
 	}
 
@@ -237,51 +299,21 @@ func (s *Service) UpdateLog(
 	ctx context.Context,
 	req *connect.Request[runnerv1.UpdateLogRequest],
 ) (*connect.Response[runnerv1.UpdateLogResponse], error) {
-	res := connect.NewResponse(&runnerv1.UpdateLogResponse{})
-
-	task, err := actions_model.GetTaskByID(ctx, req.Msg.TaskId)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "get task: %v", err)
-	}
-	ack := task.LogLength
-
-	if len(req.Msg.Rows) == 0 || req.Msg.Index > ack || int64(len(req.Msg.Rows))+req.Msg.Index <= ack {
-		res.Msg.AckIndex = ack
-		return res, nil
+	done, ok := actions_service.BeginRunnerUpdate()
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "the server is shutting down, retry later")
 	}
+	defer done()
 
-	if task.LogInStorage {
-		return nil, status.Errorf(codes.AlreadyExists, "log file has been archived")
-	}
-
-	rows := req.Msg.Rows[ack-req.Msg.Index:]
-	ns, err := actions.WriteLogs(ctx, task.LogFilename, task.LogSize, rows)
+	ack, err := actions_service.AppendTaskLogs(ctx, req.Msg.TaskId, req.Msg.Index, req.Msg.Rows, req.Msg.NoMore)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "write logs: %v", err)
-	}
-	task.LogLength += int64(len(rows))
-	for _, n := range ns {
-		task.LogIndexes = append(task.LogIndexes, task.LogSize)
-		task.LogSize += int64(n)
-	}
-
-	res.Msg.AckIndex = task.LogLength
-
-	var remove func()
-	if req.Msg.NoMore {
-		task.LogInStorage = true
-		remove, err = actions.TransferLogs(ctx, task.LogFilename)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "transfer logs: %v", err)
+		if errors.Is(err, util.ErrAlreadyExist) {
+			return nil, status.Errorf(codes.AlreadyExists, "%v", err)
 		}
+		return nil, status.Errorf(codes.Internal, "%v", err)
 	}
 
-	if err := actions_model.UpdateTask(ctx, task, "log_indexes", "log_length", "log_size", "log_in_storage"); err != nil {
-		return nil, status.Errorf(codes.Internal, "update task: %v", err)
-	}
-	if remove != nil {
-		remove()
-	}
-
-	return res, nil
+	return connect.NewResponse(&runnerv1.UpdateLogResponse{
+		AckIndex: ack,
+	}), nil
 }
@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runner
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterWithSharingPolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	register := func(token string) error {
+		_, err := (&Service{}).Register(db.DefaultContext, connect.NewRequest(&runnerv1.RegisterRequest{
+			Token: token,
+			Name:  "runner",
+		}))
+		return err
+	}
+	// token 4 is for repo 1 owned by user 2, token 3 is for user 1
+	repoToken := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunnerToken{ID: 4}).Token
+	ownerToken := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunnerToken{ID: 3}).Token
+
+	assert.NoError(t, actions_model.SetRunnerSharingPolicy(db.DefaultContext, 2, actions_model.RunnerSharingPolicyOwnerOnly))
+	assert.Error(t, register(repoToken))
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRunner{RepoID: 1})
+
+	assert.NoError(t, actions_model.SetRunnerSharingPolicy(db.DefaultContext, 2, actions_model.RunnerSharingPolicyNoShared))
+	assert.NoError(t, register(repoToken))
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{RepoID: 1})
+
+	// the policy only restricts the runners of repositories
+	assert.NoError(t, actions_model.SetRunnerSharingPolicy(db.DefaultContext, 1, actions_model.RunnerSharingPolicyOwnerOnly))
+	assert.NoError(t, register(ownerToken))
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{OwnerID: 1})
+
+	assert.NoError(t, actions_model.SetRunnerSharingPolicy(db.DefaultContext, 1, actions_model.RunnerSharingPolicyAll))
+	assert.NoError(t, actions_model.SetRunnerSharingPolicy(db.DefaultContext, 2, actions_model.RunnerSharingPolicyAll))
+}
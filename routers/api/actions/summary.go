@@ -0,0 +1,55 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// Summary API lets the steps of a job write markdown to the summary of the run, like $GITHUB_STEP_SUMMARY of GitHub.
+//
+// 1. Append to the summary of the job
+// POST: /api/actions_pipeline/_apis/pipelines/workflows/{run_id}/summary
+// the request body is the markdown written by a step, it's appended to the summary of the job
+//
+// The request is authenticated with Bearer ACTIONS_RUNTIME_TOKEN, and {run_id} must be the run of the task.
+// The summaries of the jobs are aggregated as the summary of the run, see GetRunSummary.
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const summaryRouteBase = "/_apis/pipelines/workflows/{run_id}/summary"
+
+func appendSummary(ctx *ArtifactContext) {
+	task, _, ok := validateRunID(ctx)
+	if !ok {
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(ctx.Req.Body, actions_model.MaxTaskSummarySize+1))
+	if err != nil {
+		log.Error("Error reading summary: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(content) > actions_model.MaxTaskSummarySize {
+		ctx.Error(http.StatusBadRequest, "summary is too large")
+		return
+	}
+
+	if err := actions_model.AppendTaskSummary(ctx, task, string(content)); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Error appending summary: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Debug("[summary] appendSummary, task: %d, size: %d", task.ID, len(content))
+	ctx.Status(http.StatusCreated)
+}
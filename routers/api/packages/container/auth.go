@@ -4,8 +4,10 @@
 package container
 
 import (
+	"fmt"
 	"net/http"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/services/auth"
@@ -23,9 +25,9 @@ func (a *Auth) Name() string {
 // Verify extracts the user from the Bearer token
 // If it's an anonymous session a ghost user is returned
 func (a *Auth) Verify(req *http.Request, w http.ResponseWriter, store auth.DataStore, sess auth.SessionStore) (*user_model.User, error) {
-	uid, err := packages.ParseAuthorizationToken(req)
+	uid, taskID, err := packages.ParseActionsAuthorizationToken(req)
 	if err != nil {
-		log.Trace("ParseAuthorizationToken: %v", err)
+		log.Trace("ParseActionsAuthorizationToken: %v", err)
 		return nil, err
 	}
 
@@ -33,6 +35,21 @@ func (a *Auth) Verify(req *http.Request, w http.ResponseWriter, store auth.DataS
 		return nil, nil
 	}
 
+	if taskID != 0 {
+		// the token of a task is only valid while the task is running, like the token of the task itself
+		task, err := actions_model.GetTaskByID(req.Context(), taskID)
+		if err != nil {
+			log.Error("GetTaskByID:  %v", err)
+			return nil, err
+		}
+		if task.Status != actions_model.StatusRunning {
+			return nil, fmt.Errorf("task %d isn't running", taskID)
+		}
+
+		store.GetData()["IsActionsToken"] = true
+		store.GetData()["ActionsTaskID"] = task.ID
+	}
+
 	u, err := user_model.GetPossibleUserByID(req.Context(), uid)
 	if err != nil {
 		log.Error("GetPossibleUserByID:  %v", err)
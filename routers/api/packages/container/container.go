@@ -14,8 +14,10 @@ import (
 	"strconv"
 	"strings"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	packages_model "code.gitea.io/gitea/models/packages"
 	container_model "code.gitea.io/gitea/models/packages/container"
+	"code.gitea.io/gitea/models/perm"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/httplib"
 	"code.gitea.io/gitea/modules/json"
@@ -127,6 +129,37 @@ func ReqContainerAccess(ctx *context.Context) {
 	}
 }
 
+// AssignRunScratchAccess is a middleware which grants the token of a job the write access to the images in the scratch namespace of its run,
+// see container_model.RunScratchImagePrefix. The jobs of the runs of pull requests from forks aren't granted, like their read-only tokens.
+func AssignRunScratchAccess(ctx *context.Context) {
+	taskID, ok := ctx.Data["ActionsTaskID"].(int64)
+	if !ok || !ctx.Doer.IsActions() {
+		return
+	}
+	// the image is a part of the path for the manually mapped routes, see ContainerRoutes
+	image := ctx.Params("image")
+	if image == "" {
+		image = ctx.Params("*")
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, taskID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if task.OwnerID != ctx.Package.Owner.ID || task.IsForkPullRequest {
+		return
+	}
+	job, err := actions_model.GetRunJobByID(ctx, task.JobID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if strings.HasPrefix(image, container_model.RunScratchImagePrefix(job.RunID)) {
+		ctx.Package.AccessMode = max(ctx.Package.AccessMode, perm.AccessModeWrite)
+	}
+}
+
 // VerifyImageName is a middleware which checks if the image name is allowed
 func VerifyImageName(ctx *context.Context) {
 	if !imageNamePattern.MatchString(ctx.Params("image")) {
@@ -155,7 +188,13 @@ func Authenticate(ctx *context.Context) {
 		u = user_model.NewGhostUser()
 	}
 
-	token, err := packages_service.CreateAuthorizationToken(u)
+	var token string
+	var err error
+	if taskID, ok := ctx.Data["ActionsTaskID"].(int64); ok && u.IsActions() {
+		token, err = packages_service.CreateActionsAuthorizationToken(taskID)
+	} else {
+		token, err = packages_service.CreateAuthorizationToken(u)
+	}
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -0,0 +1,930 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListActionUsages lists the usages of actions by the workflows of all repositories
+func ListActionUsages(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/usages admin adminListActionUsages
+	// ---
+	// summary: List which repositories use which actions, at which refs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: action
+	//   in: query
+	//   description: "name of the action without ref, e.g. actions/checkout or https://gitea.com/actions/checkout, it's case-insensitive"
+	//   type: string
+	// - name: ref
+	//   in: query
+	//   description: the tag, branch or commit SHA the action is used at
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listActionUsages(ctx, actions_model.FindActionUsagesOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Action:      ctx.FormTrim("action"),
+		Ref:         ctx.FormTrim("ref"),
+	})
+}
+
+// ListActionMinutesUsages lists the minutes used by the tasks of all repositories
+func ListActionMinutesUsages(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/minutes admin adminListActionMinutesUsages
+	// ---
+	// summary: List the minutes used by the tasks of the repositories, weighted by the labels of the runners
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: since
+	//   in: query
+	//   description: count the tasks stopped since this time, in RFC 3339 format, defaults to 30 days before the end of the period
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: count the tasks stopped before this time, in RFC 3339 format, defaults to now
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionMinutesUsageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.ListActionMinutesUsages(ctx, 0)
+}
+
+// GetActionTaskErrorStats counts the tasks of all repositories by the classes of their errors
+func GetActionTaskErrorStats(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/error-stats admin adminGetActionTaskErrorStats
+	// ---
+	// summary: Count the stopped tasks by the classes of the errors which made them not succeed, e.g. infrastructure errors
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: since
+	//   in: query
+	//   description: count the tasks stopped since this time, in RFC 3339 format, defaults to 30 days before the end of the period
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: count the tasks stopped before this time, in RFC 3339 format, defaults to now
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionTaskErrorStats"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GetActionTaskErrorStats(ctx, 0)
+}
+
+// ListActionStorageHealth lists the latest health checks of the storages of Actions
+func ListActionStorageHealth(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/storage-health admin adminListActionStorageHealth
+	// ---
+	// summary: List the latest health checks of the storages of the logs and the artifacts by this instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: refresh
+	//   in: query
+	//   description: check the storages again instead of returning the results of the latest checks
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionStorageHealthList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if ctx.FormBool("refresh") {
+		// the failures are in the results
+		_ = actions_service.CheckStorageHealth(ctx)
+	}
+
+	statuses := actions_service.GetStorageHealth()
+	res := make([]*api.ActionStorageHealth, 0, len(statuses))
+	for _, status := range statuses {
+		res = append(res, &api.ActionStorageHealth{
+			Name:    status.Name,
+			Healthy: status.Healthy,
+			Error:   status.Error,
+			Checked: status.Checked,
+		})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func listActionUsages(ctx *context.APIContext, opts actions_model.FindActionUsagesOptions) {
+	usages, total, err := db.FindAndCount[actions_model.ActionUsage](ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindActionUsages", err)
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(usages))
+	for _, usage := range usages {
+		repoIDs = append(repoIDs, usage.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	res := make([]*api.ActionUsage, 0, len(usages))
+	for _, usage := range usages {
+		repo, ok := repos[usage.RepoID]
+		if !ok {
+			continue
+		}
+		permission, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		res = append(res, &api.ActionUsage{
+			Repository: convert.ToRepo(ctx, repo, permission),
+			WorkflowID: usage.WorkflowID,
+			Action:     usage.Action,
+			Ref:        usage.Ref,
+			Pinned:     actions_module.IsActionPinned(usage.Action + "@" + usage.Ref),
+			UpdatedAt:  usage.Updated.AsLocalTime(),
+		})
+	}
+
+	ctx.SetLinkHeader(int(total), opts.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ListActionBlockedRefs lists the refs of actions blocked by admins
+func ListActionBlockedRefs(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/blocked-refs admin adminListActionBlockedRefs
+	// ---
+	// summary: List the blocked refs of actions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: action
+	//   in: query
+	//   description: name of the action without ref, it's case-insensitive
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionBlockedRefList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+	blockedRefs, total, err := db.FindAndCount[actions_model.ActionBlockedRef](ctx, actions_model.FindBlockedRefsOptions{
+		ListOptions: listOptions,
+		Action:      ctx.FormTrim("action"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindBlockedRefs", err)
+		return
+	}
+
+	res := make([]*api.ActionBlockedRef, 0, len(blockedRefs))
+	for _, b := range blockedRefs {
+		res = append(res, toActionBlockedRef(b))
+	}
+
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreateActionBlockedRef blocks a ref of an action
+func CreateActionBlockedRef(ctx *context.APIContext) {
+	// swagger:operation POST /admin/actions/blocked-refs admin adminCreateActionBlockedRef
+	// ---
+	// summary: Block a ref of an action, new runs using it are refused
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionBlockedRefOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionBlockedRef"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     description: The ref of the action is already blocked
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.CreateActionBlockedRefOption)
+	b := &actions_model.ActionBlockedRef{
+		Action:    opts.Action,
+		Ref:       opts.Ref,
+		Reason:    opts.Reason,
+		CreatorID: ctx.Doer.ID,
+	}
+	if err := actions_model.CreateBlockedRef(ctx, b); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusConflict, "CreateBlockedRef", err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateBlockedRef", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateBlockedRef", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, toActionBlockedRef(b))
+}
+
+// DeleteActionBlockedRef unblocks a blocked ref of an action
+func DeleteActionBlockedRef(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/blocked-refs/{id} admin adminDeleteActionBlockedRef
+	// ---
+	// summary: Unblock a blocked ref of an action
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the blocked ref
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := actions_model.DeleteBlockedRef(ctx, ctx.ParamsInt64(":id")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteBlockedRef", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListActionBlockedRefUsages lists the repositories affected by a blocked ref of an action
+func ListActionBlockedRefUsages(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/blocked-refs/{id}/usages admin adminListActionBlockedRefUsages
+	// ---
+	// summary: List the usages of a blocked ref of an action, to find out the affected repositories
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the blocked ref
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	b, err := actions_model.GetBlockedRefByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlockedRefByID", err)
+		}
+		return
+	}
+	listActionUsages(ctx, b.ToUsagesOptions(utils.GetListOptions(ctx)))
+}
+
+func toActionBlockedRef(b *actions_model.ActionBlockedRef) *api.ActionBlockedRef {
+	return &api.ActionBlockedRef{
+		ID:      b.ID,
+		Action:  b.Action,
+		Ref:     b.Ref,
+		Reason:  b.Reason,
+		Created: b.Created.AsLocalTime(),
+	}
+}
+
+// ListActionFreezeWindows lists the freeze windows of the instance
+func ListActionFreezeWindows(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/freeze-windows admin adminListActionFreezeWindows
+	// ---
+	// summary: List the freeze windows of the instance, during which the jobs deploying to the environments or requiring the labels wait
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionFreezeWindowList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListActionFreezeWindows(ctx, 0)
+}
+
+// CreateActionFreezeWindow creates a freeze window of the instance
+func CreateActionFreezeWindow(ctx *context.APIContext) {
+	// swagger:operation POST /admin/actions/freeze-windows admin adminCreateActionFreezeWindow
+	// ---
+	// summary: Create a freeze window of the instance, the jobs deploying to the environments or requiring the labels wait until it ends
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionFreezeWindowOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionFreezeWindow"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.CreateActionFreezeWindow(ctx, 0)
+}
+
+// DeleteActionFreezeWindow deletes a freeze window of the instance
+func DeleteActionFreezeWindow(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/freeze-windows/{id} admin adminDeleteActionFreezeWindow
+	// ---
+	// summary: Delete a freeze window of the instance, the jobs it froze are released
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the freeze window
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteActionFreezeWindow(ctx, 0)
+}
+
+// ReplayActionRun replays the event of a run and compares the jobs it would create with the jobs of the run
+func ReplayActionRun(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/runs/{run_id}/replay admin adminReplayActionRun
+	// ---
+	// summary: Replay the stored event of a run through the current trigger and parse pipeline and diff the jobs, without creating any runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunReplay"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run, err := actions_model.GetRunByID(ctx, ctx.ParamsInt64(":run_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		}
+		return
+	}
+
+	result, err := actions_service.ReplayRunEvent(ctx, run)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ReplayRunEvent", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, toActionRunReplay(result))
+}
+
+func toActionRunReplay(r *actions_service.ReplayedRun) *api.ActionRunReplay {
+	res := &api.ActionRunReplay{
+		RunID:        r.Run.ID,
+		RunNumber:    r.Run.Index,
+		Repository:   r.Run.Repo.FullName(),
+		WorkflowID:   r.Run.WorkflowID,
+		Event:        string(r.Run.Event),
+		TriggerEvent: r.Run.TriggerEvent,
+		CommitSHA:    r.Run.CommitSHA,
+		Triggered:    r.Triggered,
+		Error:        r.Error,
+		Changed:      r.Changed(),
+		Jobs:         make([]*api.ActionSimulatedJob, 0, len(r.Jobs)),
+		Diffs:        make([]*api.ActionRunJobDiff, 0, len(r.Diffs)),
+	}
+	for _, job := range r.Jobs {
+		res.Jobs = append(res.Jobs, &api.ActionSimulatedJob{
+			JobID:  job.JobID,
+			Name:   job.Name,
+			Needs:  job.Needs,
+			RunsOn: job.RunsOn,
+		})
+	}
+	for _, diff := range r.Diffs {
+		fields := diff.Fields
+		if fields == nil {
+			fields = []string{}
+		}
+		res.Diffs = append(res.Diffs, &api.ActionRunJobDiff{
+			JobID:  diff.JobID,
+			Name:   diff.Name,
+			Change: string(diff.Change),
+			Fields: fields,
+		})
+	}
+	return res
+}
+
+// ListActionDeadLetters lists the events whose workflows couldn't be triggered
+func ListActionDeadLetters(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/dead-letters admin adminListActionDeadLetters
+	// ---
+	// summary: List the events whose workflows couldn't be triggered, like their payloads were bad or the processing panicked
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: repo_id
+	//   in: query
+	//   description: only the events of the repository
+	//   type: integer
+	//   format: int64
+	// - name: event
+	//   in: query
+	//   description: only the events of the type, like "push"
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDeadLetterList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+	letters, total, err := db.FindAndCount[actions_model.ActionDeadLetter](ctx, actions_model.FindDeadLettersOptions{
+		ListOptions: listOptions,
+		RepoID:      ctx.FormInt64("repo_id"),
+		Event:       webhook_module.HookEventType(ctx.FormTrim("event")),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindDeadLetters", err)
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(letters))
+	for _, letter := range letters {
+		repoIDs = append(repoIDs, letter.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+	res := make([]*api.ActionDeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		res = append(res, toActionDeadLetter(letter, repos[letter.RepoID]))
+	}
+
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionDeadLetter returns an event whose workflows couldn't be triggered
+func GetActionDeadLetter(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/dead-letters/{id} admin adminGetActionDeadLetter
+	// ---
+	// summary: Get an event whose workflows couldn't be triggered, with its payload and error
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the dead letter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDeadLetter"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	letter := getActionDeadLetterByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, letter.RepoID)
+	if err != nil && !repo_model.IsErrRepoNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toActionDeadLetter(letter, repo))
+}
+
+// RetryActionDeadLetter handles an event whose workflows couldn't be triggered again
+func RetryActionDeadLetter(ctx *context.APIContext) {
+	// swagger:operation POST /admin/actions/dead-letters/{id}/retry admin adminRetryActionDeadLetter
+	// ---
+	// summary: Trigger the workflows of an event which couldn't be triggered again, the dead letter is deleted if it succeeds
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the dead letter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	letter := getActionDeadLetterByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.RetryDeadLetter(ctx, letter); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RetryDeadLetter", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteActionDeadLetter discards an event whose workflows couldn't be triggered
+func DeleteActionDeadLetter(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/dead-letters/{id} admin adminDeleteActionDeadLetter
+	// ---
+	// summary: Discard an event whose workflows couldn't be triggered
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the dead letter
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := actions_model.DeleteDeadLetter(ctx, ctx.ParamsInt64(":id")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteDeadLetter", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getActionDeadLetterByParams(ctx *context.APIContext) *actions_model.ActionDeadLetter {
+	letter, err := actions_model.GetDeadLetterByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetDeadLetterByID", err)
+		}
+		return nil
+	}
+	return letter
+}
+
+func toActionDeadLetter(letter *actions_model.ActionDeadLetter, repo *repo_model.Repository) *api.ActionDeadLetter {
+	res := &api.ActionDeadLetter{
+		ID:        letter.ID,
+		RepoID:    letter.RepoID,
+		Method:    letter.Method,
+		Event:     string(letter.Event),
+		Ref:       letter.Ref,
+		CommitSHA: letter.CommitSHA,
+		Error:     letter.Error,
+		Attempts:  letter.Attempts,
+		Created:   letter.Created.AsLocalTime(),
+		Updated:   letter.Updated.AsLocalTime(),
+	}
+	if repo != nil {
+		res.Repository = repo.FullName()
+	}
+	payload, err := actions_service.GetDeadLetterPayload(letter)
+	if err != nil {
+		log.Error("GetDeadLetterPayload of dead letter %d: %v", letter.ID, err)
+	}
+	res.Payload = payload
+	return res
+}
+
+// ListActionOutboxEvents lists the side effects of the runs which have failed too many times to be processed
+func ListActionOutboxEvents(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/outbox-events admin adminListActionOutboxEvents
+	// ---
+	// summary: List the side effects of the runs which have failed too many times to be processed, like their commit statuses or releases
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: repo_id
+	//   in: query
+	//   description: only the side effects of the runs of the repository
+	//   type: integer
+	//   format: int64
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionOutboxEventList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+	events, total, err := db.FindAndCount[actions_model.ActionOutboxEvent](ctx, actions_model.FindOutboxEventOptions{
+		ListOptions: listOptions,
+		RepoID:      ctx.FormInt64("repo_id"),
+		IsDead:      optional.Some(true),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindOutboxEvents", err)
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(events))
+	for _, event := range events {
+		repoIDs = append(repoIDs, event.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+	res := make([]*api.ActionOutboxEvent, 0, len(events))
+	for _, event := range events {
+		res = append(res, toActionOutboxEvent(event, repos[event.RepoID]))
+	}
+
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionOutboxEvent returns a side effect of a run which has failed too many times to be processed
+func GetActionOutboxEvent(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/outbox-events/{id} admin adminGetActionOutboxEvent
+	// ---
+	// summary: Get a side effect of a run which has failed too many times to be processed, with its error
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the outbox event
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionOutboxEvent"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	event := getDeadOutboxEventByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, event.RepoID)
+	if err != nil && !repo_model.IsErrRepoNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toActionOutboxEvent(event, repo))
+}
+
+// RetryActionOutboxEvent processes a side effect of a run which has failed too many times again
+func RetryActionOutboxEvent(ctx *context.APIContext) {
+	// swagger:operation POST /admin/actions/outbox-events/{id}/retry admin adminRetryActionOutboxEvent
+	// ---
+	// summary: Process a side effect of a run which has failed too many times again, the steps done by the previous attempts aren't repeated
+	// description: The side effect is processed in the background with the attempts reset, it's listed again if it fails too many times.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the outbox event
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	event := getDeadOutboxEventByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.RetryOutboxEvent(ctx, event); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RetryOutboxEvent", err)
+		return
+	}
+	ctx.Status(http.StatusAccepted)
+}
+
+// DeleteActionOutboxEvent discards a side effect of a run which has failed too many times to be processed
+func DeleteActionOutboxEvent(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/outbox-events/{id} admin adminDeleteActionOutboxEvent
+	// ---
+	// summary: Discard a side effect of a run which has failed too many times to be processed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the outbox event
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	event := getDeadOutboxEventByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.DeleteOutboxEvent(ctx, event.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteOutboxEvent", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getDeadOutboxEventByParams(ctx *context.APIContext) *actions_model.ActionOutboxEvent {
+	event, err := actions_model.GetDeadOutboxEventByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetDeadOutboxEventByID", err)
+		}
+		return nil
+	}
+	return event
+}
+
+func toActionOutboxEvent(event *actions_model.ActionOutboxEvent, repo *repo_model.Repository) *api.ActionOutboxEvent {
+	res := &api.ActionOutboxEvent{
+		ID:        event.ID,
+		RunID:     event.RunID,
+		RepoID:    event.RepoID,
+		Type:      event.Type.String(),
+		DoneSteps: event.DoneSteps.Names(),
+		Attempts:  event.Attempts,
+		LastError: event.LastError,
+		Created:   event.Created.AsLocalTime(),
+	}
+	if repo != nil {
+		res.Repository = repo.FullName()
+	}
+	return res
+}
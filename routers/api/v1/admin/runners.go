@@ -4,8 +4,18 @@
 package admin
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/shared"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
+
+	"github.com/hashicorp/go-version"
 )
 
 // https://docs.github.com/en/rest/actions/self-hosted-runners?apiVersion=2022-11-28#create-a-registration-token-for-an-organization
@@ -24,3 +34,107 @@ func GetRegistrationToken(ctx *context.APIContext) {
 
 	shared.GetRegistrationToken(ctx, 0, 0)
 }
+
+// RotateRunnerToken replaces the token of a runner
+func RotateRunnerToken(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners/{runner_id}/rotate-token admin adminRotateRunnerToken
+	// ---
+	// summary: Replace the token of a runner, the old token stops working immediately
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RunnerToken"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RotateRunnerToken(ctx, 0, 0)
+}
+
+// RevokeRunner invalidates the token of a runner and fails the tasks it's running
+func RevokeRunner(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners/{runner_id}/revoke admin adminRevokeRunner
+	// ---
+	// summary: Revoke a runner, its token is invalidated and the tasks it's running are failed
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RevokeRunner(ctx, 0, 0)
+}
+
+// GetRunnerUpdateRollout shows the rollout of the target version to the runners
+func GetRunnerUpdateRollout(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runners/update admin adminGetRunnerUpdateRollout
+	// ---
+	// summary: Get the statuses of the runners in the rollout of the target version
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerUpdateRollout"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	rollout, err := actions_service.GetRunnerUpdateRollout(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunnerUpdateRollout", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rollout)
+}
+
+// SetRunnerTargetVersion starts the rollout of a version to the runners
+func SetRunnerTargetVersion(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/runners/update admin adminSetRunnerTargetVersion
+	// ---
+	// summary: Set the version the runners should update themselves to
+	// description: The idle act_runners are signaled to update themselves to the version once, by the header
+	//   `x-runner-update-version` of the responses of fetching tasks. An empty version stops the rollout.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetActionRunnerTargetVersionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerUpdateRollout"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.SetActionRunnerTargetVersionOption)
+	target := strings.TrimSpace(opts.Version)
+	if target != "" {
+		if _, err := version.NewVersion(target); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid version %q", target))
+			return
+		}
+	}
+	if err := actions_model.SetRunnerTargetVersion(ctx, target); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetRunnerTargetVersion", err)
+		return
+	}
+	GetRunnerUpdateRollout(ctx)
+}
@@ -200,10 +200,10 @@ func repoAssignment() func(ctx *context.APIContext) {
 				return
 			}
 
-			if task.IsForkPullRequest {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeRead
-			} else {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeWrite
+			ctx.Repo.Permission.AccessMode, err = task.TokenAccessMode(ctx)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "TokenAccessMode", err)
+				return
 			}
 
 			if err := ctx.Repo.Repository.LoadUnits(ctx); err != nil {
@@ -211,6 +211,18 @@ func repoAssignment() func(ctx *context.APIContext) {
 				return
 			}
 			ctx.Repo.Permission.SetUnitsWithDefaultAccessMode(ctx.Repo.Repository.Units, ctx.Repo.Permission.AccessMode)
+
+			// the jobs with `permissions: pull-requests: write` could label, comment on and review their pull requests,
+			// the write access is granted by reqActionsPullRequestScope on these routes only
+			prIndex, err := task.PullRequestScope(ctx)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "PullRequestScope", err)
+				return
+			}
+			ctx.Data["ActionsPullRequestIndex"] = prIndex
+
+			// the commits, comments and statuses made with the token are attributed to the bot identity of the repository
+			ctx.Doer = actions_model.ApplyBotIdentity(ctx, repo, ctx.Doer)
 		} else {
 			ctx.Repo.Permission, err = access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
 			if err != nil {
@@ -381,6 +393,16 @@ func reqAdmin() func(ctx *context.APIContext) {
 	}
 }
 
+// reqRepoActionsAdmin user should administer the Actions of a repo, or be a site admin, see access.Permission.CanManageActions
+func reqRepoActionsAdmin() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if !ctx.Repo.Permission.CanManageActions() && !ctx.IsUserSiteAdmin() {
+			ctx.Error(http.StatusForbidden, "reqRepoActionsAdmin", "user should administer the Actions of the repository")
+			return
+		}
+	}
+}
+
 // reqRepoWriter user should have a permission to write to a repo, or be a site admin
 func reqRepoWriter(unitTypes ...unit.Type) func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
@@ -391,6 +413,17 @@ func reqRepoWriter(unitTypes ...unit.Type) func(ctx *context.APIContext) {
 	}
 }
 
+// reqActionsPullRequestScope grants the write access to the pull requests unit to the tokens of the jobs with
+// `permissions: pull-requests: write`, if the pull request of the route is the one which triggered the run
+func reqActionsPullRequestScope() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		prIndex, _ := ctx.Data["ActionsPullRequestIndex"].(int64)
+		if prIndex > 0 && ctx.ParamsInt64(":index") == prIndex {
+			ctx.Repo.Permission.SetUnitAccessMode(unit.TypePullRequests, perm.AccessModeWrite)
+		}
+	}
+}
+
 // reqRepoBranchWriter user should have a permission to write to a branch, or be a site admin
 func reqRepoBranchWriter(ctx *context.APIContext) {
 	options, ok := web.GetForm(ctx).(api.FileOptionInterface)
@@ -811,6 +844,41 @@ func checkDeprecatedAuthMethods(ctx *context.APIContext) {
 	}
 }
 
+// recordActionsTokenActivity rate-limits the requests made with the tokens of the tasks, and records them to the trails of the runs
+func recordActionsTokenActivity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.GetAPIContext(req)
+		taskID, ok := ctx.Data["ActionsTaskID"].(int64)
+		if !ok || ctx.Data["IsActionsToken"] != true {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		limited, err := actions.IsTokenRateLimited(ctx, taskID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsTokenRateLimited", err)
+			return
+		}
+		if limited {
+			ctx.Error(http.StatusTooManyRequests, "recordActionsTokenActivity", "the token of the task has made too many requests, retry later")
+			if err := actions.RecordTokenActivity(ctx, taskID, req, 0, http.StatusTooManyRequests, actions_model.TokenAnomalyRateLimited); err != nil {
+				log.Error("RecordTokenActivity of task %d: %v", taskID, err)
+			}
+			return
+		}
+
+		next.ServeHTTP(w, req)
+
+		var targetRepoID int64
+		if ctx.Repo.Repository != nil {
+			targetRepoID = ctx.Repo.Repository.ID
+		}
+		if err := actions.RecordTokenActivity(ctx, taskID, req, targetRepoID, ctx.Resp.WrittenStatus(), ""); err != nil {
+			log.Error("RecordTokenActivity of task %d: %v", taskID, err)
+		}
+	})
+}
+
 // Routes registers all v1 APIs routes to web application.
 func Routes() *web.Route {
 	m := web.NewRoute()
@@ -836,6 +904,8 @@ func Routes() *web.Route {
 		SignInRequired: setting.Service.RequireSignInView,
 	}))
 
+	m.Use(recordActionsTokenActivity)
+
 	addActionsRoutes := func(
 		m *web.Route,
 		reqChecker func(ctx *context.APIContext),
@@ -860,6 +930,8 @@ func Routes() *web.Route {
 
 			m.Group("/runners", func() {
 				m.Get("/registration-token", reqToken(), reqChecker, act.GetRegistrationToken)
+				m.Post("/{runner_id}/rotate-token", reqToken(), reqChecker, act.RotateRunnerToken)
+				m.Post("/{runner_id}/revoke", reqToken(), reqChecker, act.RevokeRunner)
 			})
 		})
 	}
@@ -890,6 +962,7 @@ func Routes() *web.Route {
 		// Misc (public accessible)
 		m.Group("", func() {
 			m.Get("/version", misc.Version)
+			m.Get("/actions/status", misc.GetActionsStatus)
 			m.Get("/signing-key.gpg", misc.SigningKey)
 			m.Post("/markup", reqToken(), bind(api.MarkupOption{}), misc.Markup)
 			m.Post("/markdown", reqToken(), bind(api.MarkdownOption{}), misc.Markdown)
@@ -906,6 +979,7 @@ func Routes() *web.Route {
 				m.Get("/api", settings.GetGeneralAPISettings)
 				m.Get("/attachment", settings.GetGeneralAttachmentSettings)
 				m.Get("/repository", settings.GetGeneralRepoSettings)
+				m.Get("/actions/default-env", settings.GetActionsDefaultEnv)
 			})
 		})
 
@@ -929,6 +1003,9 @@ func Routes() *web.Route {
 
 				if setting.Service.EnableUserHeatmap {
 					m.Get("/heatmap", user.GetUserHeatmapData)
+					if len(setting.Actions.AutomationActivityEvents) > 0 {
+						m.Get("/heatmap/automation", user.GetUserAutomationHeatmapData)
+					}
 				}
 
 				m.Get("/repos", tokenRequiresScopes(auth_model.AccessTokenScopeCategoryRepository), reqExploreSignIn(), user.ListUserRepos)
@@ -991,6 +1068,8 @@ func Routes() *web.Route {
 
 				m.Group("/runners", func() {
 					m.Get("/registration-token", reqToken(), user.GetRegistrationToken)
+					m.Post("/{runner_id}/rotate-token", reqToken(), user.RotateRunnerToken)
+					m.Post("/{runner_id}/revoke", reqToken(), user.RevokeRunner)
 				})
 			})
 
@@ -1086,6 +1165,9 @@ func Routes() *web.Route {
 		// Repos (requires repo scope)
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
+			m.Get("/actions/runs/search", repo.SearchActionRuns)
+			// authenticated by the federation tokens of the repositories instead of the users
+			m.Post("/actions/federation/statuses", bind(api.FederatedActionStatusOption{}), repo.ReceiveFederatedActionStatus)
 
 			// (repo scope)
 			m.Post("/migrate", reqToken(), bind(api.MigrateRepoOptions{}), repo.Migrate)
@@ -1104,7 +1186,7 @@ func Routes() *web.Route {
 				}, reqToken())
 				addActionsRoutes(
 					m,
-					reqOwner(),
+					reqRepoActionsAdmin(),
 					repo.NewAction(),
 				)
 				m.Group("/hooks/git", func() {
@@ -1166,11 +1248,100 @@ func Routes() *web.Route {
 					m.Get("", repo.ListTags)
 					m.Get("/*", repo.GetTag)
 					m.Post("", reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, bind(api.CreateTagOption{}), repo.CreateTag)
+					m.Post("/bump", reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, bind(api.BumpTagOption{}), repo.BumpTag)
 					m.Delete("/*", reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, repo.DeleteTag)
 				}, reqRepoReader(unit.TypeCode), context.ReferencesGitRepo(true))
 				m.Group("/actions", func() {
 					m.Get("/tasks", repo.ListActionTasks)
+					m.Get("/local-config", reqToken(), reqRepoWriter(unit.TypeActions), repo.GetActionsLocalConfig)
+					m.Get("/workflows/lint", repo.LintActionWorkflows)
+					m.Post("/workflows/simulate", reqToken(), bind(api.SimulateActionTriggerOption{}), repo.SimulateActionTrigger)
+					m.Get("/commits/{sha}/gating", repo.GetActionCommitGating)
+					m.Post("/workflows/estimate", reqToken(), reqRepoWriter(unit.TypeActions), bind(api.EstimateActionDispatchOption{}), repo.EstimateActionDispatch)
+					m.Get("/schedules", repo.ListActionSchedules)
+					m.Get("/schedules.ics", repo.GetActionSchedulesICalendar)
+					m.Get("/runs", repo.ListActionRuns)
+					m.Get("/backlog", repo.GetActionRunBacklog)
+					m.Get("/creation-failures", repo.ListActionRunCreationFailures)
+					m.Get("/components", repo.ListActionComponents)
+					m.Group("/code-scanning", func() {
+						m.Get("/alerts", repo.ListActionCodeScanningAlerts)
+						m.Get("/pulls/{index}", reqRepoReader(unit.TypePullRequests), repo.CompareActionCodeScanningAlerts)
+					}, reqRepoReader(unit.TypeCode))
+					m.Group("/dependencies", func() {
+						m.Get("", repo.GetActionDependencySnapshot)
+						m.Get("/pulls/{index}", reqRepoReader(unit.TypePullRequests), repo.ReviewActionDependencies)
+					}, reqRepoReader(unit.TypeCode))
+					if len(setting.Actions.AutomationActivityEvents) > 0 {
+						m.Get("/heatmap", repo.GetActionsHeatmapData)
+					}
+					m.Combo("/runs/{run}").Get(repo.GetActionRun).
+						Delete(reqToken(), reqRepoActionsAdmin(), mustNotBeArchived, repo.DeleteActionRun)
+					m.Get("/runs/{run}/jobs", repo.ListActionRunJobs)
+					m.Get("/runs/{run}/jobs/{job}/logs", repo.DownloadActionRunJobLogs)
+					m.Get("/runs/{run}/jobs/{job}/logs/url", repo.GetActionRunJobLogsURL)
+					m.Get("/runs/{run}/jobs/{job}/logs/structured", repo.GetActionRunJobStructuredLogs)
+					m.Post("/runs/{run}/cancel", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.CancelActionRun)
+					m.Get("/runs/{run}/artifacts", repo.ListActionRunArtifacts)
+					m.Combo("/runs/{run}/artifacts/{artifact_name}").Get(repo.DownloadActionRunArtifact).
+						Delete(reqToken(), reqRepoWriter(unit.TypeActions), repo.DeleteActionRunArtifact)
+					m.Get("/runs/{run}/artifacts/{artifact_name}/url", repo.GetActionRunArtifactURL)
+					m.Group("/runs/{run}/comments", func() {
+						m.Combo("").Get(repo.ListActionRunComments).
+							Post(reqToken(), mustNotBeArchived, bind(api.CreateActionRunCommentOption{}), repo.CreateActionRunComment)
+						m.Combo("/{id}", reqToken(), mustNotBeArchived).
+							Patch(bind(api.EditActionRunCommentOption{}), repo.EditActionRunComment).
+							Delete(repo.DeleteActionRunComment)
+					})
+					m.Get("/runs/{run}/summary", repo.GetActionRunSummary)
+					m.Get("/runs/{run}/details", repo.GetActionRunDetails)
+					m.Get("/runs/{run}/bundle", reqRepoReader(unit.TypeCode), repo.GetActionRunBundle)
+					m.Get("/runs/{run}/packfile", reqRepoReader(unit.TypeCode), repo.GetActionRunPackfile)
+					m.Post("/runs/{run}/sarif", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.UploadActionRunSARIF)
+					m.Post("/runs/{run}/dependencies", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived,
+						bind(api.SubmitActionDependencySnapshotOption{}), repo.SubmitActionRunDependencies)
+					m.Combo("/runs/{run}/licenses").Get(repo.ListActionRunLicenseScans).
+						Post(reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.SubmitActionLicenseScanOption{}), repo.SubmitActionRunLicenseScan)
+					m.Combo("/runs/{run}/packages").Get(repo.ListActionRunPackages).
+						Post(reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.ActionRunPackageOption{}), repo.RecordActionRunPackage)
+					m.Combo("/runs/{run}/egress-violations").Get(repo.ListActionRunEgressViolations).
+						Post(reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.SubmitActionEgressViolationsOption{}), repo.SubmitActionRunEgressViolations)
+					m.Get("/runs/{run}/evidence", reqToken(), reqRepoWriter(unit.TypeActions), repo.GetActionRunEvidence)
+					m.Get("/runs/{run}/token-activity", reqToken(), reqAdmin(), repo.ListActionRunTokenActivities)
+					m.Combo("/runs/{run}/acknowledge", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived).
+						Post(bind(api.AcknowledgeActionRunOption{}), repo.AcknowledgeActionRun).
+						Delete(repo.UnacknowledgeActionRun)
+					m.Post("/runs/{run}/issue", reqToken(), reqRepoWriter(unit.TypeActions), reqRepoReader(unit.TypeIssues), mustNotBeArchived,
+						bind(api.CreateActionRunIssueOption{}), repo.CreateActionRunIssue)
+					m.Post("/runs/{run}/redeliver", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.RedeliverActionRunEvent)
+					m.Post("/runs/{run}/rerun", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.RerunActionRunOption{}), repo.RerunActionRun)
+					m.Post("/backfill", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.BackfillActionRunsOption{}), repo.BackfillActionRuns)
+					m.Group("/dispatch-presets", func() {
+						m.Get("", repo.ListActionDispatchPresets)
+						m.Combo("/{name}").Get(repo.GetActionDispatchPreset).
+							Put(reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.SaveActionDispatchPresetOption{}), repo.SaveActionDispatchPreset).
+							Delete(reqToken(), reqRepoWriter(unit.TypeActions), repo.DeleteActionDispatchPreset)
+						m.Post("/{name}/dispatch", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.DispatchActionPreset)
+					})
+					m.Group("/run-filters", func() {
+						m.Get("", repo.ListActionRunFilters)
+						m.Combo("/{name}").Get(repo.GetActionRunFilter).
+							Put(bind(api.SaveActionRunFilterOption{}), repo.SaveActionRunFilter).
+							Delete(repo.DeleteActionRunFilter)
+					}, reqToken())
+					m.Group("/runs/external", func() {
+						m.Post("", bind(api.CreateExternalRunOption{}), repo.CreateExternalActionRun)
+						m.Patch("/{run}", bind(api.UpdateExternalRunOption{}), repo.UpdateExternalActionRun)
+					}, reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived)
 				}, reqRepoReader(unit.TypeActions), context.ReferencesGitRepo(true))
+				m.Combo("/actions/settings", reqToken(), reqRepoActionsAdmin()).Get(repo.GetActionsSettings).
+					Patch(bind(api.EditRepoActionsSettingsOption{}), repo.EditActionsSettings)
+				m.Get("/actions/package-retention/report", reqToken(), reqRepoActionsAdmin(), repo.GetActionsPackageRetentionReport)
+				m.Get("/actions/snapshot", reqToken(), reqRepoActionsAdmin(), repo.GetActionsConfigSnapshot)
+				m.Group("/actions/config", func() {
+					m.Get("/export", repo.ExportActionsConfigBundle)
+					m.Post("/import", mustNotBeArchived, repo.ImportActionsConfigBundle)
+				}, reqToken(), reqRepoActionsAdmin())
 				m.Group("/keys", func() {
 					m.Combo("").Get(repo.ListDeployKeys).
 						Post(bind(api.CreateKeyOption{}), repo.CreateDeployKey)
@@ -1204,6 +1375,7 @@ func Routes() *web.Route {
 					m.Combo("").Get(repo.ListReleases).
 						Post(reqToken(), reqRepoWriter(unit.TypeReleases), context.ReferencesGitRepo(), bind(api.CreateReleaseOption{}), repo.CreateRelease)
 					m.Combo("/latest").Get(repo.GetLatestRelease)
+					m.Post("/generate-notes", reqToken(), reqRepoWriter(unit.TypeReleases), context.ReferencesGitRepo(), bind(api.GenerateReleaseNotesOption{}), repo.GenerateReleaseNotes)
 					m.Group("/{id}", func() {
 						m.Combo("").Get(repo.GetRelease).
 							Patch(reqToken(), reqRepoWriter(unit.TypeReleases), context.ReferencesGitRepo(), bind(api.EditReleaseOption{}), repo.EditRelease).
@@ -1250,19 +1422,19 @@ func Routes() *web.Route {
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
-								Post(reqToken(), bind(api.CreatePullReviewOptions{}), repo.CreatePullReview)
+								Post(reqToken(), reqActionsPullRequestScope(), bind(api.CreatePullReviewOptions{}), repo.CreatePullReview)
 							m.Group("/{id}", func() {
 								m.Combo("").
 									Get(repo.GetPullReview).
 									Delete(reqToken(), repo.DeletePullReview).
-									Post(reqToken(), bind(api.SubmitPullReviewOptions{}), repo.SubmitPullReview)
+									Post(reqToken(), reqActionsPullRequestScope(), bind(api.SubmitPullReviewOptions{}), repo.SubmitPullReview)
 								m.Combo("/comments").
 									Get(repo.GetPullReviewComments)
 								m.Post("/dismissals", reqToken(), bind(api.DismissPullReviewOptions{}), repo.DismissPullReview)
 								m.Post("/undismissals", reqToken(), repo.UnDismissPullReview)
 							})
 						})
-						m.Combo("/requested_reviewers", reqToken()).
+						m.Combo("/requested_reviewers", reqToken(), reqActionsPullRequestScope()).
 							Delete(bind(api.PullReviewRequestOptions{}), repo.DeleteReviewRequests).
 							Post(bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
 					})
@@ -1371,17 +1543,17 @@ func Routes() *web.Route {
 							Delete(reqToken(), reqAdmin(), context.ReferencesGitRepo(), repo.DeleteIssue)
 						m.Group("/comments", func() {
 							m.Combo("").Get(repo.ListIssueComments).
-								Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
+								Post(reqToken(), mustNotBeArchived, reqActionsPullRequestScope(), bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
 							m.Combo("/{id}", reqToken()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
 								Delete(repo.DeleteIssueCommentDeprecated)
 						})
 						m.Get("/timeline", repo.ListIssueCommentsAndTimeline)
 						m.Group("/labels", func() {
 							m.Combo("").Get(repo.ListIssueLabels).
-								Post(reqToken(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
-								Put(reqToken(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
-								Delete(reqToken(), repo.ClearIssueLabels)
-							m.Delete("/{id}", reqToken(), repo.DeleteIssueLabel)
+								Post(reqToken(), reqActionsPullRequestScope(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
+								Put(reqToken(), reqActionsPullRequestScope(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
+								Delete(reqToken(), reqActionsPullRequestScope(), repo.ClearIssueLabels)
+							m.Delete("/{id}", reqToken(), reqActionsPullRequestScope(), repo.DeleteIssueLabel)
 						})
 						m.Group("/times", func() {
 							m.Combo("").
@@ -1482,6 +1654,17 @@ func Routes() *web.Route {
 				reqOrgOwnership(),
 				org.NewAction(),
 			)
+			m.Get("/actions/error-stats", reqToken(), reqOrgOwnership(), org.GetActionTaskErrorStats)
+			m.Get("/actions/minutes", reqToken(), reqOrgOwnership(), org.ListActionMinutesUsages)
+			m.Get("/actions/schedules", org.ListActionSchedules)
+			m.Get("/actions/schedules.ics", org.GetActionSchedulesICalendar)
+			m.Combo("/actions/settings", reqToken(), reqOrgOwnership()).Get(org.GetActionsSettings).
+				Patch(bind(api.EditOrgActionsSettingsOption{}), org.EditActionsSettings)
+			m.Group("/actions/freeze-windows", func() {
+				m.Combo("").Get(org.ListActionFreezeWindows).
+					Post(bind(api.CreateActionFreezeWindowOption{}), org.CreateActionFreezeWindow)
+				m.Delete("/{id}", org.DeleteActionFreezeWindow)
+			}, reqToken(), reqOrgOwnership())
 			m.Group("/public_members", func() {
 				m.Get("", org.ListPublicMembers)
 				m.Combo("/{username}").Get(org.IsPublicMember).
@@ -1586,6 +1769,40 @@ func Routes() *web.Route {
 			})
 			m.Group("/runners", func() {
 				m.Get("/registration-token", admin.GetRegistrationToken)
+				m.Post("/{runner_id}/rotate-token", admin.RotateRunnerToken)
+				m.Post("/{runner_id}/revoke", admin.RevokeRunner)
+				m.Combo("/update").Get(admin.GetRunnerUpdateRollout).
+					Put(bind(api.SetActionRunnerTargetVersionOption{}), admin.SetRunnerTargetVersion)
+			})
+			m.Group("/actions", func() {
+				m.Get("/usages", admin.ListActionUsages)
+				m.Get("/minutes", admin.ListActionMinutesUsages)
+				m.Get("/error-stats", admin.GetActionTaskErrorStats)
+				m.Get("/storage-health", admin.ListActionStorageHealth)
+				m.Group("/blocked-refs", func() {
+					m.Combo("").Get(admin.ListActionBlockedRefs).
+						Post(bind(api.CreateActionBlockedRefOption{}), admin.CreateActionBlockedRef)
+					m.Delete("/{id}", admin.DeleteActionBlockedRef)
+					m.Get("/{id}/usages", admin.ListActionBlockedRefUsages)
+				})
+				m.Group("/freeze-windows", func() {
+					m.Combo("").Get(admin.ListActionFreezeWindows).
+						Post(bind(api.CreateActionFreezeWindowOption{}), admin.CreateActionFreezeWindow)
+					m.Delete("/{id}", admin.DeleteActionFreezeWindow)
+				})
+				m.Get("/runs/{run_id}/replay", admin.ReplayActionRun)
+				m.Group("/dead-letters", func() {
+					m.Get("", admin.ListActionDeadLetters)
+					m.Combo("/{id}").Get(admin.GetActionDeadLetter).
+						Delete(admin.DeleteActionDeadLetter)
+					m.Post("/{id}/retry", admin.RetryActionDeadLetter)
+				})
+				m.Group("/outbox-events", func() {
+					m.Get("", admin.ListActionOutboxEvents)
+					m.Combo("/{id}").Get(admin.GetActionOutboxEvent).
+						Delete(admin.DeleteActionOutboxEvent)
+					m.Post("/{id}/retry", admin.RetryActionOutboxEvent)
+				})
 			})
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryAdmin), reqToken(), reqSiteAdmin())
 
@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1
+
+import (
+	"net/http"
+	"testing"
+
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/services/contexttest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReqRepoActionsAdmin(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	cases := []struct {
+		name        string
+		userID      int64
+		actionsMode perm_model.AccessMode // the access of the team to the Actions, the permission of the user is kept if it's none
+		allowed     bool
+	}{
+		{name: "repo admin", userID: 2, allowed: true},
+		{name: "site admin", userID: 1, allowed: true},
+		{name: "actions admin team", userID: 4, actionsMode: perm_model.AccessModeAdmin, allowed: true},
+		{name: "actions write team", userID: 4, actionsMode: perm_model.AccessModeWrite},
+		{name: "actions read team", userID: 4, actionsMode: perm_model.AccessModeRead},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, resp := contexttest.MockAPIContext(t, "GET /api/v1/repos/user2/repo1/actions/settings")
+			contexttest.LoadUser(t, ctx, c.userID)
+			ctx.IsSigned = true
+			contexttest.LoadRepo(t, ctx, 1)
+			if c.actionsMode != perm_model.AccessModeNone {
+				// a member of a team with the read access to the other units, see access.TestGetUserRepoPermissionOfActionsTeams
+				perm := access_model.Permission{AccessMode: perm_model.AccessModeRead}
+				perm.SetUnitsWithDefaultAccessMode(ctx.Repo.Repository.Units, perm_model.AccessModeRead)
+				perm.SetUnitAccessMode(unit.TypeActions, c.actionsMode)
+				ctx.Repo.Permission = perm
+			}
+
+			reqRepoActionsAdmin()(ctx)
+			if c.allowed {
+				assert.False(t, ctx.Written())
+			} else {
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			}
+		})
+	}
+}
+
+func TestReqActionsPullRequestScope(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	cases := []struct {
+		name     string
+		prIndex  any // the index of the pull request which the token could write to, it's unset for the other tokens
+		index    string
+		writable bool
+	}{
+		{name: "scoped pull request", prIndex: int64(3), index: "3", writable: true},
+		{name: "other pull request", prIndex: int64(3), index: "4"},
+		{name: "not granted", prIndex: int64(0), index: "3"},
+		{name: "not an actions token", index: "3"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, _ := contexttest.MockAPIContext(t, "POST /api/v1/repos/user2/repo1/issues/"+c.index+"/comments")
+			contexttest.LoadRepo(t, ctx, 1)
+			ctx.SetParams(":index", c.index)
+			if c.prIndex != nil {
+				ctx.Data["ActionsPullRequestIndex"] = c.prIndex
+			}
+			perm := access_model.Permission{AccessMode: perm_model.AccessModeRead}
+			perm.SetUnitsWithDefaultAccessMode(ctx.Repo.Repository.Units, perm_model.AccessModeRead)
+			ctx.Repo.Permission = perm
+
+			reqActionsPullRequestScope()(ctx)
+			assert.False(t, ctx.Written())
+			assert.Equal(t, c.writable, ctx.Repo.Permission.CanWrite(unit.TypePullRequests))
+			assert.False(t, ctx.Repo.Permission.CanWrite(unit.TypeIssues))
+		})
+	}
+}
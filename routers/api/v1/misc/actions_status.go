@@ -0,0 +1,46 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package misc
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/setting"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionsStatus shows the status of Actions of the instance
+func GetActionsStatus(ctx *context.APIContext) {
+	// swagger:operation GET /actions/status miscellaneous getActionsStatus
+	// ---
+	// summary: Returns the status of Actions of the instance, including the availability of the runners, the queues and the maintenance
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsStatus"
+	//   "401":
+	//     "$ref": "#/responses/unauthorized"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	switch setting.Actions.StatusAccess {
+	case setting.ActionsStatusAccessDisabled:
+		ctx.NotFound()
+		return
+	case setting.ActionsStatusAccessSignedIn:
+		if !ctx.IsSigned {
+			ctx.Error(http.StatusUnauthorized, "", "the status of Actions is only visible to the signed-in users")
+			return
+		}
+	}
+
+	status, err := actions_service.GetInstanceStatus(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetInstanceStatus", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, status)
+}
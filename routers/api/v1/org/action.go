@@ -5,11 +5,15 @@ package org
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	secret_model "code.gitea.io/gitea/models/secret"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/optional"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
@@ -17,6 +21,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
 	secret_service "code.gitea.io/gitea/services/secrets"
 )
 
@@ -60,10 +65,7 @@ func (Action) ListActionsSecrets(ctx *context.APIContext) {
 
 	apiSecrets := make([]*api.Secret, len(secrets))
 	for k, v := range secrets {
-		apiSecrets[k] = &api.Secret{
-			Name:    v.Name,
-			Created: v.CreatedUnix.AsTime(),
-		}
+		apiSecrets[k] = convert.ToSecret(v)
 	}
 
 	ctx.SetTotalCountHeader(count)
@@ -106,7 +108,7 @@ func (Action) CreateOrUpdateSecret(ctx *context.APIContext) {
 
 	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
 
-	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Org.Organization.ID, 0, ctx.Params("secretname"), opt.Data)
+	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Org.Organization.ID, 0, ctx.Params("secretname"), opt.Data, optional.FromPtr(opt.ProtectedRefsOnly))
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "CreateOrUpdateSecret", err)
@@ -189,6 +191,60 @@ func (Action) GetRegistrationToken(ctx *context.APIContext) {
 	shared.GetRegistrationToken(ctx, ctx.Org.Organization.ID, 0)
 }
 
+// RotateRunnerToken replaces the token of an org runner
+func (Action) RotateRunnerToken(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runners/{runner_id}/rotate-token organization orgRotateRunnerToken
+	// ---
+	// summary: Replace the token of an organization's runner, the old token stops working immediately
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RunnerToken"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RotateRunnerToken(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// RevokeRunner invalidates the token of an org runner and fails the tasks it's running
+func (Action) RevokeRunner(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runners/{runner_id}/revoke organization orgRevokeRunner
+	// ---
+	// summary: Revoke an organization's runner, its token is invalidated and the tasks it's running are failed
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RevokeRunner(ctx, ctx.Org.Organization.ID, 0)
+}
+
 // ListVariables list org-level variables
 func (Action) ListVariables(ctx *context.APIContext) {
 	// swagger:operation GET /orgs/{org}/actions/variables organization getOrgVariablesList
@@ -462,6 +518,194 @@ func (Action) UpdateVariable(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// GetActionsSettings returns the Actions settings of an organization
+func GetActionsSettings(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/settings organization orgGetActionsSettings
+	// ---
+	// summary: Get the Actions settings of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgActionsSettings"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	settings, err := getActionsSettings(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "getActionsSettings", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// EditActionsSettings edits the Actions settings of an organization
+func EditActionsSettings(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/actions/settings organization orgEditActionsSettings
+	// ---
+	// summary: Edit the Actions settings of an organization, the settings which aren't set are kept
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditOrgActionsSettingsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgActionsSettings"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.EditOrgActionsSettingsOption)
+	ownerID := ctx.Org.Organization.ID
+
+	if opts.RunnerSharingPolicy != nil {
+		policy, ok := actions_model.ParseRunnerSharingPolicy(*opts.RunnerSharingPolicy)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "RunnerSharingPolicy", fmt.Errorf("invalid runner sharing policy %q", *opts.RunnerSharingPolicy))
+			return
+		}
+		if err := actions_model.SetRunnerSharingPolicy(ctx, ownerID, policy); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetRunnerSharingPolicy", err)
+			return
+		}
+	}
+	if opts.RequirePinnedActions != nil {
+		if err := actions_model.SetPinnedActionsRequired(ctx, ownerID, *opts.RequirePinnedActions); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetPinnedActionsRequired", err)
+			return
+		}
+	}
+	if opts.RunsOnOverrides != nil {
+		overrides, ok := shared.ParseRunsOnOverrides(ctx, opts.RunsOnOverrides)
+		if !ok {
+			return
+		}
+		if err := actions_model.SetRunsOnOverrides(ctx, ownerID, overrides); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetRunsOnOverrides", err)
+			return
+		}
+	}
+	if opts.BotIdentity != nil {
+		identity, ok := shared.ParseBotIdentity(ctx, opts.BotIdentity)
+		if !ok {
+			return
+		}
+		if err := actions_model.SetOwnerBotIdentity(ctx, ownerID, identity); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetOwnerBotIdentity", err)
+			return
+		}
+	}
+	if opts.BadgeTheme != nil {
+		theme, ok := shared.ParseBadgeTheme(ctx, opts.BadgeTheme)
+		if !ok {
+			return
+		}
+		if err := actions_model.SetOwnerBadgeTheme(ctx, ownerID, theme); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetOwnerBadgeTheme", err)
+			return
+		}
+	}
+	if opts.LicensePolicy != nil {
+		policy := &actions_model.LicensePolicy{
+			Allowed: trimLicenses(opts.LicensePolicy.Allowed),
+			Denied:  trimLicenses(opts.LicensePolicy.Denied),
+		}
+		if err := actions_model.SetLicensePolicy(ctx, ownerID, policy); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetLicensePolicy", err)
+			return
+		}
+	}
+	if opts.EgressAllowlist != nil {
+		domains, err := actions_module.NormalizeEgressDomains(opts.EgressAllowlist)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "EgressAllowlist", err)
+			return
+		}
+		if err := actions_model.SetEgressAllowlist(ctx, ownerID, domains); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetEgressAllowlist", err)
+			return
+		}
+	}
+
+	settings, err := getActionsSettings(ctx, ownerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "getActionsSettings", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// trimLicenses trims the license identifiers and drops the empty ones
+func trimLicenses(licenses []string) []string {
+	ret := make([]string, 0, len(licenses))
+	for _, license := range licenses {
+		if license = strings.TrimSpace(license); license != "" {
+			ret = append(ret, license)
+		}
+	}
+	return ret
+}
+
+func getActionsSettings(ctx *context.APIContext, ownerID int64) (*api.OrgActionsSettings, error) {
+	requirePinnedActions, err := actions_model.IsPinnedActionsRequired(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := actions_model.GetRunnerSharingPolicy(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := actions_model.GetRunsOnOverrides(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	botIdentity, err := actions_model.GetOwnerBotIdentity(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	licensePolicy, err := actions_model.GetLicensePolicy(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	egressAllowlist, err := actions_model.GetEgressAllowlist(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if egressAllowlist == nil {
+		egressAllowlist = []string{}
+	}
+	badgeTheme, err := actions_model.GetOwnerBadgeTheme(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.OrgActionsSettings{
+		RequirePinnedActions: requirePinnedActions,
+		RunnerSharingPolicy:  string(policy),
+		RunsOnOverrides:      convert.ToActionRunsOnOverrides(overrides),
+		BotIdentity:          convert.ToActionBotIdentity(botIdentity),
+		LicensePolicy:        convert.ToActionLicensePolicy(licensePolicy),
+		EgressAllowlist:      egressAllowlist,
+		BadgeTheme:           convert.ToActionBadgeTheme(badgeTheme),
+	}, nil
+}
+
 var _ actions_service.API = new(Action)
 
 // Action implements actions_service.API
@@ -471,3 +715,222 @@ type Action struct{}
 func NewAction() actions_service.API {
 	return Action{}
 }
+
+// ListActionMinutesUsages lists the minutes used by the tasks of the repositories of an organization
+func ListActionMinutesUsages(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/minutes organization orgListActionMinutesUsages
+	// ---
+	// summary: List the minutes used by the tasks of the repositories of an organization, weighted by the labels of the runners
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: count the tasks stopped since this time, in RFC 3339 format, defaults to 30 days before the end of the period
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: count the tasks stopped before this time, in RFC 3339 format, defaults to now
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionMinutesUsageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.ListActionMinutesUsages(ctx, ctx.Org.Organization.ID)
+}
+
+// GetActionTaskErrorStats counts the tasks of the repositories of an organization by the classes of their errors
+func GetActionTaskErrorStats(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/error-stats organization orgGetActionTaskErrorStats
+	// ---
+	// summary: Count the stopped tasks of an organization by the classes of the errors which made them not succeed, e.g. infrastructure errors
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: count the tasks stopped since this time, in RFC 3339 format, defaults to 30 days before the end of the period
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: count the tasks stopped before this time, in RFC 3339 format, defaults to now
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionTaskErrorStats"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GetActionTaskErrorStats(ctx, ctx.Org.Organization.ID)
+}
+
+// ListActionSchedules lists the cron schedules of the workflows of an organization with their upcoming runs
+func ListActionSchedules(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/schedules organization orgListActionSchedules
+	// ---
+	// summary: List the cron schedules of the workflows of an organization with their upcoming runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: days
+	//   in: query
+	//   description: list the runs in the next days, defaults to 7, at most 31
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionScheduleList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListActionSchedules(ctx, actions_model.FindSpecOptions{OwnerID: ctx.Org.Organization.ID}, ctx.Org.Organization.Name, false)
+}
+
+// GetActionSchedulesICalendar gets the upcoming runs of the cron schedules of the workflows of an organization as an iCalendar
+func GetActionSchedulesICalendar(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/schedules.ics organization orgGetActionSchedulesICalendar
+	// ---
+	// summary: Get the upcoming runs of the cron schedules of the workflows of an organization as an iCalendar
+	// produces:
+	// - text/calendar
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: days
+	//   in: query
+	//   description: include the runs in the next days, defaults to 7, at most 31
+	//   type: integer
+	// responses:
+	//   "200":
+	//     description: the iCalendar of the upcoming runs
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListActionSchedules(ctx, actions_model.FindSpecOptions{OwnerID: ctx.Org.Organization.ID}, ctx.Org.Organization.Name, true)
+}
+
+// ListActionFreezeWindows lists the freeze windows of an organization
+func ListActionFreezeWindows(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/freeze-windows organization orgListActionFreezeWindows
+	// ---
+	// summary: List the freeze windows of an organization, during which the jobs deploying to the environments or requiring the labels wait
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionFreezeWindowList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListActionFreezeWindows(ctx, ctx.Org.Organization.ID)
+}
+
+// CreateActionFreezeWindow creates a freeze window of an organization
+func CreateActionFreezeWindow(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/freeze-windows organization orgCreateActionFreezeWindow
+	// ---
+	// summary: Create a freeze window of an organization, the jobs deploying to the environments or requiring the labels wait until it ends
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionFreezeWindowOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionFreezeWindow"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.CreateActionFreezeWindow(ctx, ctx.Org.Organization.ID)
+}
+
+// DeleteActionFreezeWindow deletes a freeze window of an organization
+func DeleteActionFreezeWindow(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/freeze-windows/{id} organization orgDeleteActionFreezeWindow
+	// ---
+	// summary: Delete a freeze window of an organization, the jobs it froze are released
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the freeze window
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteActionFreezeWindow(ctx, ctx.Org.Organization.ID)
+}
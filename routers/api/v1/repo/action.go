@@ -4,21 +4,40 @@
 package repo
 
 import (
+	gocontext "context"
 	"errors"
+	"fmt"
 	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
 	secret_model "code.gitea.io/gitea/models/secret"
+	unit_model "code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/routers/api/v1/shared"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
 	secret_service "code.gitea.io/gitea/services/secrets"
+
+	"github.com/gobwas/glob"
 )
 
 // ListActionsSecrets list an repo's actions secrets
@@ -68,10 +87,7 @@ func (Action) ListActionsSecrets(ctx *context.APIContext) {
 
 	apiSecrets := make([]*api.Secret, len(secrets))
 	for k, v := range secrets {
-		apiSecrets[k] = &api.Secret{
-			Name:    v.Name,
-			Created: v.CreatedUnix.AsTime(),
-		}
+		apiSecrets[k] = convert.ToSecret(v)
 	}
 
 	ctx.SetTotalCountHeader(count)
@@ -122,7 +138,7 @@ func (Action) CreateOrUpdateSecret(ctx *context.APIContext) {
 
 	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
 
-	_, created, err := secret_service.CreateOrUpdateSecret(ctx, owner.ID, repo.ID, ctx.Params("secretname"), opt.Data)
+	_, created, err := secret_service.CreateOrUpdateSecret(ctx, owner.ID, repo.ID, ctx.Params("secretname"), opt.Data, optional.FromPtr(opt.ProtectedRefsOnly))
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "CreateOrUpdateSecret", err)
@@ -509,6 +525,70 @@ func (Action) GetRegistrationToken(ctx *context.APIContext) {
 	shared.GetRegistrationToken(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository.ID)
 }
 
+// RotateRunnerToken replaces the token of a repo runner
+func (Action) RotateRunnerToken(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runners/{runner_id}/rotate-token repository repoRotateRunnerToken
+	// ---
+	// summary: Replace the token of a repository's runner, the old token stops working immediately
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RunnerToken"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RotateRunnerToken(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// RevokeRunner invalidates the token of a repo runner and fails the tasks it's running
+func (Action) RevokeRunner(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runners/{runner_id}/revoke repository repoRevokeRunner
+	// ---
+	// summary: Revoke a repository's runner, its token is invalidated and the tasks it's running are failed
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RevokeRunner(ctx, 0, ctx.Repo.Repository.ID)
+}
+
 var _ actions_service.API = new(Action)
 
 // Action implements actions_service.API
@@ -583,3 +663,2012 @@ func ListActionTasks(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &res)
 }
+
+// GetActionsLocalConfig returns what is needed to run the workflows of a repository locally with act
+func GetActionsLocalConfig(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/local-config repository GetActionsLocalConfig
+	// ---
+	// summary: Get the workflows, variables and platform images to run the workflows of a repository locally with act
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch"
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsLocalConfig"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	ref := ctx.FormTrim("ref")
+	if ref == "" {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	res := &api.ActionsLocalConfig{
+		Ref:       ref,
+		CommitSHA: commit.ID.String(),
+		Workflows: []*api.ActionsLocalWorkflow{},
+		Platforms: setting.Actions.PlatformImages,
+		Labels:    []string{},
+	}
+
+	dir, err := actions_module.GetWorkflowsDir(commit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetWorkflowsDir", err)
+		return
+	}
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListWorkflows", err)
+		return
+	}
+	for _, entry := range entries {
+		content, err := actions_module.GetContentFromEntry(entry)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetContentFromEntry", err)
+			return
+		}
+		res.Workflows = append(res.Workflows, &api.ActionsLocalWorkflow{
+			Path:    path.Join(dir, entry.Name()),
+			Content: string(content),
+		})
+	}
+
+	res.Vars, err = actions_model.GetVariablesOfRun(ctx, &actions_model.ActionRun{
+		RepoID: ctx.Repo.Repository.ID,
+		Repo:   ctx.Repo.Repository,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetVariablesOfRun", err)
+		return
+	}
+
+	runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
+		RepoID:        ctx.Repo.Repository.ID,
+		WithAvailable: true,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRunners", err)
+		return
+	}
+	labels := make(container.Set[string])
+	for _, runner := range runners {
+		labels.AddMultiple(runner.AgentLabels...)
+	}
+	res.Labels = labels.Values()
+	sort.Strings(res.Labels)
+
+	res.Actrc = buildActrc(res.Platforms, res.Vars)
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// buildActrc builds the arguments of act for the platforms and variables, one per line as act reads them from .actrc.
+// act splits the lines by whitespace, so the variables with whitespace in their values are left out.
+func buildActrc(platforms, vars map[string]string) string {
+	var lines []string
+	for _, label := range util.KeysOfMap(platforms) {
+		lines = append(lines, fmt.Sprintf("-P %s=%s", label, platforms[label]))
+	}
+	for _, name := range util.KeysOfMap(vars) {
+		if strings.ContainsAny(vars[name], " \t\r\n") {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("--var %s=%s", name, vars[name]))
+	}
+	sort.Strings(lines)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// LintActionWorkflows returns the deprecated or unsupported syntax found in the workflows of a repository
+func LintActionWorkflows(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/workflows/lint repository LintActionWorkflows
+	// ---
+	// summary: Find the deprecated or unsupported syntax in the workflows of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch"
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionWorkflowLintResult"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	ref := ctx.FormTrim("ref")
+	if ref == "" {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	findings, err := actions_service.LintWorkflows(commit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "LintWorkflows", err)
+		return
+	}
+
+	res := &api.ActionWorkflowLintResult{
+		Ref:       ref,
+		CommitSHA: commit.ID.String(),
+		Findings:  make([]*api.ActionWorkflowLintFinding, 0, len(findings)),
+	}
+	for _, f := range findings {
+		res.Findings = append(res.Findings, &api.ActionWorkflowLintFinding{
+			Path:    f.Path,
+			Line:    f.Line,
+			Column:  f.Column,
+			Rule:    f.Rule,
+			Message: f.Message,
+		})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ListActionSchedules lists the cron schedules of the workflows of a repository with their upcoming runs
+func ListActionSchedules(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/schedules repository repoListActionSchedules
+	// ---
+	// summary: List the cron schedules of the workflows of a repository with their upcoming runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: days
+	//   in: query
+	//   description: list the runs in the next days, defaults to 7, at most 31
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionScheduleList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListActionSchedules(ctx, actions_model.FindSpecOptions{RepoID: ctx.Repo.Repository.ID}, ctx.Repo.Repository.FullName(), false)
+}
+
+// GetActionSchedulesICalendar gets the upcoming runs of the cron schedules of the workflows of a repository as an iCalendar
+func GetActionSchedulesICalendar(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/schedules.ics repository repoGetActionSchedulesICalendar
+	// ---
+	// summary: Get the upcoming runs of the cron schedules of the workflows of a repository as an iCalendar
+	// produces:
+	// - text/calendar
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: days
+	//   in: query
+	//   description: include the runs in the next days, defaults to 7, at most 31
+	//   type: integer
+	// responses:
+	//   "200":
+	//     description: the iCalendar of the upcoming runs
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListActionSchedules(ctx, actions_model.FindSpecOptions{RepoID: ctx.Repo.Repository.ID}, ctx.Repo.Repository.FullName(), true)
+}
+
+// GetActionsConfigSnapshot returns the Actions configuration of a repository as of a time
+func GetActionsConfigSnapshot(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/snapshot repository repoGetActionsConfigSnapshot
+	// ---
+	// summary: Get the Actions configuration of a repository as of a time, the workflows of the default branch, the settings and the freeze windows
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: at
+	//   in: query
+	//   description: the time in RFC 3339 format, defaults to now
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionConfigSnapshot"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	at := time.Now()
+	if qAt := ctx.FormTrim("at"); qAt != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, qAt); err != nil {
+			ctx.Error(http.StatusBadRequest, "Parse", err)
+			return
+		}
+	}
+
+	snapshot, err := actions_service.GetConfigSnapshot(ctx, ctx.Repo.Repository, at)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetConfigSnapshot", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toActionConfigSnapshot(snapshot))
+}
+
+func toActionConfigSnapshot(snapshot *actions_service.ConfigSnapshot) *api.ActionConfigSnapshot {
+	res := &api.ActionConfigSnapshot{
+		At:            snapshot.At.AsTime(),
+		Workflows:     make([]*api.ActionConfigSnapshotWorkflow, 0, len(snapshot.Workflows)),
+		FreezeWindows: make([]*api.ActionFreezeWindow, 0, len(snapshot.FreezeWindows)),
+	}
+	if snapshot.Commit != nil {
+		res.CommitSHA = snapshot.Commit.ID.String()
+	}
+	for _, wf := range snapshot.Workflows {
+		res.Workflows = append(res.Workflows, &api.ActionConfigSnapshotWorkflow{
+			WorkflowID: wf.WorkflowID,
+			Events:     wf.Events,
+			Schedules:  wf.Schedules,
+			Disabled:   wf.Disabled,
+			Error:      wf.Error,
+		})
+	}
+	if snapshot.Revision != nil {
+		res.Settings = convert.ToRepoActionsSettings(snapshot.Revision.ActionsConfig())
+		changedAt := snapshot.Revision.CreatedUnix.AsTime()
+		res.SettingsChangedAt = &changedAt
+	}
+	if snapshot.RecordedSince > 0 {
+		recordedSince := snapshot.RecordedSince.AsTime()
+		res.SettingsRecordedSince = &recordedSince
+	}
+	for _, w := range snapshot.FreezeWindows {
+		res.FreezeWindows = append(res.FreezeWindows, convert.ToActionFreezeWindow(w))
+	}
+	return res
+}
+
+// GetActionsSettings returns the Actions settings of a repository
+func GetActionsSettings(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/settings repository repoGetActionsSettings
+	// ---
+	// summary: Get the Actions settings of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoActionsSettings"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	cfg, err := getActionsConfig(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUnit", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoActionsSettings(cfg))
+}
+
+// EditActionsSettings edits the Actions settings of a repository
+func EditActionsSettings(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/actions/settings repository repoEditActionsSettings
+	// ---
+	// summary: Edit the Actions settings of a repository, the settings which aren't set are kept
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoActionsSettingsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoActionsSettings"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.EditRepoActionsSettingsOption)
+	repo := ctx.Repo.Repository
+
+	cfg, err := getActionsConfig(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUnit", err)
+		return
+	}
+
+	if opts.Enabled != nil && *opts.Enabled != (cfg != nil) {
+		// like the other units, only the repository admins could enable or disable the unit
+		if !ctx.IsUserRepoAdmin() && !ctx.IsUserSiteAdmin() {
+			ctx.Error(http.StatusForbidden, "Enabled", errors.New("only the repository admins could enable or disable Actions"))
+			return
+		}
+		if *opts.Enabled {
+			if unit_model.TypeActions.UnitGlobalDisabled() {
+				ctx.Error(http.StatusUnprocessableEntity, "Enabled", errors.New("Actions are disabled for the instance"))
+				return
+			}
+			cfg = &repo_model.ActionsConfig{}
+		} else {
+			if err := repo_service.UpdateRepositoryUnits(ctx, repo, nil, []unit_model.Type{unit_model.TypeActions}); err != nil {
+				ctx.Error(http.StatusInternalServerError, "UpdateRepositoryUnits", err)
+				return
+			}
+			// the other settings are dropped with the unit
+			ctx.JSON(http.StatusOK, convert.ToRepoActionsSettings(nil))
+			return
+		}
+	}
+	if cfg == nil {
+		// nothing could be changed while Actions are disabled
+		ctx.JSON(http.StatusOK, convert.ToRepoActionsSettings(nil))
+		return
+	}
+
+	if !applyActionsSettingsOption(ctx, opts, cfg) {
+		return
+	}
+
+	if err := repo_service.UpdateRepositoryUnits(ctx, repo, []repo_model.RepoUnit{{
+		RepoID: repo.ID,
+		Type:   unit_model.TypeActions,
+		Config: cfg,
+	}}, nil); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRepositoryUnits", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToRepoActionsSettings(cfg))
+}
+
+// applyActionsSettingsOption checks the options and applies them to the config, it writes the error and returns false if they're invalid
+func applyActionsSettingsOption(ctx *context.APIContext, opts *api.EditRepoActionsSettingsOption, cfg *repo_model.ActionsConfig) bool {
+	if opts.DefaultTokenPermissions != nil {
+		switch repo_model.ActionsTokenPermissions(*opts.DefaultTokenPermissions) {
+		case repo_model.ActionsTokenPermissionsRead, repo_model.ActionsTokenPermissionsWrite:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "DefaultTokenPermissions", fmt.Errorf("invalid default token permissions %q", *opts.DefaultTokenPermissions))
+			return false
+		}
+	}
+	if opts.ForkPullRequestApproval != nil {
+		switch repo_model.ActionsForkPullRequestApproval(*opts.ForkPullRequestApproval) {
+		case repo_model.ActionsForkPullRequestApprovalFirstTime, repo_model.ActionsForkPullRequestApprovalAlways, repo_model.ActionsForkPullRequestApprovalNever:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "ForkPullRequestApproval", fmt.Errorf("invalid fork pull request approval %q", *opts.ForkPullRequestApproval))
+			return false
+		}
+	}
+	if opts.FeedRuns != nil {
+		switch repo_model.ActionsFeedRuns(*opts.FeedRuns) {
+		case repo_model.ActionsFeedRunsNone, repo_model.ActionsFeedRunsAll, repo_model.ActionsFeedRunsFailure:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "FeedRuns", fmt.Errorf("invalid feed runs %q", *opts.FeedRuns))
+			return false
+		}
+	}
+	if opts.AnonymousRunAccess != nil {
+		switch repo_model.ActionsAnonymousRunAccess(*opts.AnonymousRunAccess) {
+		case repo_model.ActionsAnonymousRunAccessFull, repo_model.ActionsAnonymousRunAccessMetadata:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "AnonymousRunAccess", fmt.Errorf("invalid anonymous run access %q", *opts.AnonymousRunAccess))
+			return false
+		}
+	}
+	if opts.SupersedePullRequestRuns != nil {
+		switch repo_model.ActionsSupersedePullRequestRuns(*opts.SupersedePullRequestRuns) {
+		case repo_model.ActionsSupersedePullRequestRunsNone, repo_model.ActionsSupersedePullRequestRunsQueued, repo_model.ActionsSupersedePullRequestRunsAll:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "SupersedePullRequestRuns", fmt.Errorf("invalid supersede pull request runs %q", *opts.SupersedePullRequestRuns))
+			return false
+		}
+	}
+	if opts.ClosedPullRequestRuns != nil {
+		switch repo_model.ActionsClosedPullRequestRuns(*opts.ClosedPullRequestRuns) {
+		case repo_model.ActionsClosedPullRequestRunsNone, repo_model.ActionsClosedPullRequestRunsQueued, repo_model.ActionsClosedPullRequestRunsAll:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "ClosedPullRequestRuns", fmt.Errorf("invalid closed pull request runs %q", *opts.ClosedPullRequestRuns))
+			return false
+		}
+	}
+	if opts.SubmoduleTokenScope != nil {
+		switch repo_model.ActionsSubmoduleTokenScope(*opts.SubmoduleTokenScope) {
+		case repo_model.ActionsSubmoduleTokenScopeNone, repo_model.ActionsSubmoduleTokenScopeOwner, repo_model.ActionsSubmoduleTokenScopeInstance:
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "SubmoduleTokenScope", fmt.Errorf("invalid submodule token scope %q", *opts.SubmoduleTokenScope))
+			return false
+		}
+	}
+	if opts.ArtifactRetentionDays != nil && *opts.ArtifactRetentionDays < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "ArtifactRetentionDays", errors.New("artifact retention days can't be negative"))
+		return false
+	}
+	if opts.LogRetentionDays != nil && *opts.LogRetentionDays < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "LogRetentionDays", errors.New("log retention days can't be negative"))
+		return false
+	}
+	if opts.RunRetentionDays != nil && *opts.RunRetentionDays < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "RunRetentionDays", errors.New("run retention days can't be negative"))
+		return false
+	}
+	if opts.ClosedPullRequestRetentionDays != nil && *opts.ClosedPullRequestRetentionDays < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "ClosedPullRequestRetentionDays", errors.New("closed pull request retention days can't be negative"))
+		return false
+	}
+	for _, pattern := range opts.AllowedActions {
+		if _, err := glob.Compile(pattern, '/'); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "AllowedActions", fmt.Errorf("invalid pattern %q: %w", pattern, err))
+			return false
+		}
+	}
+	chatOpsCommandNames := make(container.Set[string], len(opts.ChatOpsCommands))
+	for _, c := range opts.ChatOpsCommands {
+		if c == nil || !actions_module.IsValidChatOpsCommandName(c.Name) {
+			ctx.Error(http.StatusUnprocessableEntity, "ChatOpsCommands", errors.New("the name of a command must consist of lowercase letters, digits, dashes and underscores"))
+			return false
+		}
+		if !chatOpsCommandNames.Add(c.Name) {
+			ctx.Error(http.StatusUnprocessableEntity, "ChatOpsCommands", fmt.Errorf("duplicate command %q", c.Name))
+			return false
+		}
+		if c.WorkflowID == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "ChatOpsCommands", fmt.Errorf("the workflow of command %q is required", c.Name))
+			return false
+		}
+	}
+	for _, j := range opts.DisabledJobs {
+		if j == nil || j.WorkflowID == "" || j.Job == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "DisabledJobs", errors.New("the workflow and the job of a disabled job are required"))
+			return false
+		}
+	}
+	runsOnOverrides, ok := shared.ParseRunsOnOverrides(ctx, opts.RunsOnOverrides)
+	if !ok {
+		return false
+	}
+	var botIdentity *repo_model.ActionsBotIdentity
+	if opts.BotIdentity != nil {
+		if botIdentity, ok = shared.ParseBotIdentity(ctx, opts.BotIdentity); !ok {
+			return false
+		}
+	}
+	var badgeTheme *repo_model.ActionsBadgeTheme
+	if opts.BadgeTheme != nil {
+		if badgeTheme, ok = shared.ParseBadgeTheme(ctx, opts.BadgeTheme); !ok {
+			return false
+		}
+	}
+	for workflowID, label := range opts.BadgeLabels {
+		if workflowID == "" || strings.TrimSpace(label) == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "BadgeLabels", errors.New("the workflows and the labels of the badges are required"))
+			return false
+		}
+	}
+	var releaseAutomation *repo_model.ActionsReleaseAutomation
+	if opts.ReleaseAutomation != nil && opts.ReleaseAutomation.WorkflowID != "" {
+		a := opts.ReleaseAutomation
+		for _, pattern := range append([]string{a.TagPattern}, a.Artifacts...) {
+			if _, err := glob.Compile(pattern); err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "ReleaseAutomation", fmt.Errorf("invalid pattern %q: %w", pattern, err))
+				return false
+			}
+		}
+		releaseAutomation = &repo_model.ActionsReleaseAutomation{
+			WorkflowID:    a.WorkflowID,
+			TagPattern:    a.TagPattern,
+			Artifacts:     a.Artifacts,
+			GenerateNotes: a.GenerateNotes,
+			Draft:         a.Draft,
+		}
+	}
+	var packageRetention *repo_model.ActionsPackageRetention
+	if opts.PackageRetention != nil && len(opts.PackageRetention.Rules) > 0 {
+		packageRetention = &repo_model.ActionsPackageRetention{DryRun: opts.PackageRetention.DryRun}
+		for _, r := range opts.PackageRetention.Rules {
+			if r == nil {
+				ctx.Error(http.StatusUnprocessableEntity, "PackageRetention", errors.New("the rules of the package retention are required"))
+				return false
+			}
+			if r.Type != "" && !slices.Contains(packages_model.TypeList, packages_model.Type(r.Type)) {
+				ctx.Error(http.StatusUnprocessableEntity, "PackageRetention", fmt.Errorf("invalid package type %q", r.Type))
+				return false
+			}
+			for _, pattern := range []string{r.PackagePattern, r.RefPattern} {
+				if _, err := glob.Compile(pattern); err != nil {
+					ctx.Error(http.StatusUnprocessableEntity, "PackageRetention", fmt.Errorf("invalid pattern %q: %w", pattern, err))
+					return false
+				}
+			}
+			if r.KeepCount < 0 || r.KeepDays < 0 {
+				ctx.Error(http.StatusUnprocessableEntity, "PackageRetention", errors.New("keep_count and keep_days can't be negative"))
+				return false
+			}
+			packageRetention.Rules = append(packageRetention.Rules, &repo_model.ActionsPackageRetentionRule{
+				Type:           r.Type,
+				PackagePattern: strings.ToLower(r.PackagePattern),
+				RefPattern:     r.RefPattern,
+				KeepCount:      r.KeepCount,
+				KeepDays:       r.KeepDays,
+			})
+		}
+	}
+
+	if opts.FederationPeer != nil && opts.FederationPeer.URL != "" {
+		if _, _, err := actions_service.ParseFederationPeerURL(opts.FederationPeer.URL); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "FederationPeer", err)
+			return false
+		}
+	}
+	if opts.FederationToken != nil && *opts.FederationToken != "" && len(*opts.FederationToken) < 16 {
+		ctx.Error(http.StatusUnprocessableEntity, "FederationToken", errors.New("the federation token must have at least 16 characters"))
+		return false
+	}
+
+	if opts.DefaultTokenPermissions != nil {
+		cfg.DefaultTokenPermissions = repo_model.ActionsTokenPermissions(*opts.DefaultTokenPermissions)
+	}
+	if opts.ForkPullRequestApproval != nil {
+		cfg.ForkPullRequestApproval = repo_model.ActionsForkPullRequestApproval(*opts.ForkPullRequestApproval)
+	}
+	if opts.ArtifactRetentionDays != nil {
+		cfg.ArtifactRetentionDays = *opts.ArtifactRetentionDays
+	}
+	if opts.LogRetentionDays != nil {
+		cfg.LogRetentionDays = *opts.LogRetentionDays
+	}
+	if opts.RunRetentionDays != nil {
+		cfg.RunRetentionDays = *opts.RunRetentionDays
+	}
+	if opts.AllowedActions != nil {
+		cfg.AllowedActions = opts.AllowedActions
+	}
+	if opts.DisabledWorkflows != nil {
+		cfg.DisabledWorkflows = opts.DisabledWorkflows
+	}
+	if opts.ChatOpsCommands != nil {
+		cfg.ChatOpsCommands = make([]*repo_model.ActionsChatOpsCommand, 0, len(opts.ChatOpsCommands))
+		for _, c := range opts.ChatOpsCommands {
+			cfg.ChatOpsCommands = append(cfg.ChatOpsCommands, &repo_model.ActionsChatOpsCommand{
+				Name:         c.Name,
+				WorkflowID:   c.WorkflowID,
+				ArgsInput:    c.ArgsInput,
+				RequireAdmin: c.RequireAdmin,
+			})
+		}
+	}
+	if opts.StatusExcludedWorkflows != nil {
+		cfg.StatusExcludedWorkflows = opts.StatusExcludedWorkflows
+	}
+	if opts.RunsOnOverrides != nil {
+		cfg.RunsOnOverrides = runsOnOverrides
+	}
+	if opts.DisabledJobs != nil {
+		cfg.DisabledJobs = make([]*repo_model.ActionsDisabledJob, 0, len(opts.DisabledJobs))
+		for _, j := range opts.DisabledJobs {
+			cfg.DisabledJobs = append(cfg.DisabledJobs, &repo_model.ActionsDisabledJob{
+				WorkflowID: j.WorkflowID,
+				Job:        j.Job,
+			})
+		}
+	}
+	if opts.FeedRuns != nil {
+		cfg.FeedRuns = repo_model.ActionsFeedRuns(*opts.FeedRuns)
+	}
+	if opts.FeedRunsDefaultBranchOnly != nil {
+		cfg.FeedRunsDefaultBranchOnly = *opts.FeedRunsDefaultBranchOnly
+	}
+	if opts.AnonymousRunAccess != nil {
+		cfg.AnonymousRunAccess = repo_model.ActionsAnonymousRunAccess(*opts.AnonymousRunAccess)
+	}
+	if opts.SupersedePullRequestRuns != nil {
+		cfg.SupersedePullRequestRuns = repo_model.ActionsSupersedePullRequestRuns(*opts.SupersedePullRequestRuns)
+	}
+	if opts.ClosedPullRequestRuns != nil {
+		cfg.ClosedPullRequestRuns = repo_model.ActionsClosedPullRequestRuns(*opts.ClosedPullRequestRuns)
+	}
+	if opts.ClosedPullRequestRetentionDays != nil {
+		cfg.ClosedPullRequestRetentionDays = *opts.ClosedPullRequestRetentionDays
+	}
+	if opts.SubmoduleTokenScope != nil {
+		cfg.SubmoduleTokenScope = repo_model.ActionsSubmoduleTokenScope(*opts.SubmoduleTokenScope)
+	}
+	if opts.BotIdentity != nil {
+		cfg.BotIdentity = botIdentity
+	}
+	if opts.BadgeTheme != nil {
+		cfg.BadgeTheme = badgeTheme
+	}
+	if opts.BadgeLabels != nil {
+		cfg.BadgeLabels = nil
+		if len(opts.BadgeLabels) > 0 {
+			cfg.BadgeLabels = make(map[string]string, len(opts.BadgeLabels))
+			for workflowID, label := range opts.BadgeLabels {
+				cfg.BadgeLabels[workflowID] = strings.TrimSpace(label)
+			}
+		}
+	}
+	if opts.ReleaseAutomation != nil {
+		cfg.ReleaseAutomation = releaseAutomation
+	}
+	if opts.PackageRetention != nil {
+		cfg.PackageRetention = packageRetention
+	}
+	if opts.FederationPeer != nil {
+		if opts.FederationPeer.URL == "" {
+			cfg.FederationPeer = nil
+		} else {
+			peer := &repo_model.ActionsFederationPeer{URL: strings.TrimSuffix(opts.FederationPeer.URL, "/")}
+			if opts.FederationPeer.Token != "" {
+				var err error
+				if peer.TokenEncrypted, err = actions_service.EncryptFederationToken(opts.FederationPeer.Token); err != nil {
+					ctx.Error(http.StatusInternalServerError, "EncryptFederationToken", err)
+					return false
+				}
+			} else if cfg.FederationPeer != nil {
+				peer.TokenEncrypted = cfg.FederationPeer.TokenEncrypted
+			} else {
+				ctx.Error(http.StatusUnprocessableEntity, "FederationPeer", errors.New("the token of the federation peer is required"))
+				return false
+			}
+			cfg.FederationPeer = peer
+		}
+	}
+	if opts.FederationToken != nil {
+		cfg.FederationTokenHash = ""
+		if *opts.FederationToken != "" {
+			cfg.FederationTokenHash = repo_model.HashFederationToken(*opts.FederationToken)
+		}
+	}
+
+	return true
+}
+
+// getActionsConfig returns the Actions config of the repository, it's nil if Actions are disabled in the repository
+func getActionsConfig(ctx *context.APIContext) (*repo_model.ActionsConfig, error) {
+	cfgUnit, err := ctx.Repo.Repository.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cfgUnit.ActionsConfig(), nil
+}
+
+// CreateExternalActionRun reports a run of an external CI system
+func CreateExternalActionRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/external repository repoCreateExternalActionRun
+	// ---
+	// summary: Report a run of an external CI system
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateExternalRunOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateExternalRunOption)
+
+	if _, err := ctx.Repo.GitRepo.GetCommit(form.HeadSHA); err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "GetCommit", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	jobs, ok := toExternalJobOptions(ctx, form.Jobs)
+	if !ok {
+		return
+	}
+
+	run, err := actions_service.CreateExternalRun(ctx, ctx.Repo.Repository, ctx.Doer, &actions_service.ExternalRunOptions{
+		Name:      form.Name,
+		System:    form.System,
+		URL:       form.URL,
+		Ref:       form.Ref,
+		CommitSHA: form.HeadSHA,
+		Jobs:      jobs,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateExternalRun", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateExternalRun", err)
+		}
+		return
+	}
+
+	writeActionRun(ctx, http.StatusCreated, run)
+}
+
+// UpdateExternalActionRun updates a run of an external CI system
+func UpdateExternalActionRun(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/actions/runs/external/{run} repository repoUpdateExternalActionRun
+	// ---
+	// summary: Update a run of an external CI system
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdateExternalRunOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.UpdateExternalRunOption)
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return
+	}
+	if !run.IsExternal() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the run isn't reported by an external CI system")
+		return
+	}
+
+	jobs, ok := toExternalJobOptions(ctx, form.Jobs)
+	if !ok {
+		return
+	}
+
+	if err := actions_service.UpdateExternalRun(ctx, run, form.URL, jobs); err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateExternalRun", err)
+		case errors.As(err, &actions_model.ErrConcurrentUpdate{}):
+			ctx.Error(http.StatusConflict, "UpdateExternalRun", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "UpdateExternalRun", err)
+		}
+		return
+	}
+
+	run, err = actions_model.GetRunByID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		return
+	}
+	writeActionRun(ctx, http.StatusOK, run)
+}
+
+// ReceiveFederatedActionStatus creates the status of a job pushed by a peer instance which has run it
+func ReceiveFederatedActionStatus(ctx *context.APIContext) {
+	// swagger:operation POST /repos/actions/federation/statuses repository repoReceiveFederatedActionStatus
+	// ---
+	// summary: Create the status of a job pushed by a peer instance, authenticated by the federation token of the repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: X-Gitea-Federation-Token
+	//   in: header
+	//   description: the federation token accepted by the repository
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/FederatedActionStatusOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.FederatedActionStatusOption)
+	if err := actions_service.ReceiveFederatedStatus(ctx, ctx.Req.Header.Get(actions_service.FederationTokenHeader), opts); err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden, "", err)
+		case errors.Is(err, util.ErrNotExist):
+			ctx.Error(http.StatusNotFound, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "ReceiveFederatedStatus", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// SearchActionRuns searches the runs of the repositories the doer can access by their titles and workflows
+func SearchActionRuns(ctx *context.APIContext) {
+	// swagger:operation GET /repos/actions/runs/search repository repoSearchActionRuns
+	// ---
+	// summary: Search the runs of the repositories the user can access by their titles and workflows
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword to match the titles and the workflow file names of the runs
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	keyword := strings.TrimSpace(ctx.FormString("q"))
+	if keyword == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the keyword is required")
+		return
+	}
+
+	if unit_model.TypeActions.UnitGlobalDisabled() {
+		ctx.SetTotalCountHeader(0)
+		ctx.JSON(http.StatusOK, []*api.ActionRun{})
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	runs, total, err := db.FindAndCount[actions_model.ActionRun](db.WithReplica(ctx), actions_model.FindRunOptions{
+		ListOptions: listOptions,
+		Keyword:     keyword,
+		RepoCond:    repo_model.AccessibleRepositoryCondition(ctx.Doer, unit_model.TypeActions),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindAndCount", err)
+		return
+	}
+	if err := actions_model.RunList(runs).LoadRepos(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepos", err)
+		return
+	}
+
+	apiRuns := make([]*api.ActionRun, 0, len(runs))
+	for _, run := range runs {
+		// the jobs are left out, the results are meant to link to the runs
+		apiRun, err := convert.ToActionRun(ctx, run, nil)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+			return
+		}
+		apiRuns = append(apiRuns, apiRun)
+	}
+
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, apiRuns)
+}
+
+// GetActionRun gets a run with the timings of its jobs
+func GetActionRun(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run} repository repoGetActionRun
+	// ---
+	// summary: Get a run of the repository, including how long its jobs were blocked, queued and executed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: wait
+	//   in: query
+	//   description: seconds to wait for the run or its jobs to change if they match the `If-None-Match` header, up to 60
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "304":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return
+	}
+
+	var jobs []*actions_model.ActionRunJob
+	load := func(c gocontext.Context) (string, error) {
+		if run, err = actions_model.GetRunByID(c, run.ID); err != nil {
+			return "", err
+		}
+		run.Repo = ctx.Repo.Repository
+		if jobs, err = actions_model.GetRunJobsByRunID(c, run.ID); err != nil {
+			return "", err
+		}
+		taskIDs := make([]int64, 0, len(jobs))
+		for _, job := range jobs {
+			if job.TaskID > 0 {
+				taskIDs = append(taskIDs, job.TaskID)
+			}
+		}
+		tasks, err := actions_model.GetTasksByIDs(c, taskIDs)
+		if err != nil {
+			return "", err
+		}
+		return actions_service.RunETag(run, jobs, tasks), nil
+	}
+	if !waitActionRunsChange(ctx, load) {
+		return
+	}
+	apiRun, err := convert.ToActionRun(ctx, run, jobs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+		return
+	}
+	if err := addActionRunJobHints(ctx, run, jobs, apiRun); err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobHints", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiRun)
+}
+
+// AcknowledgeActionRun acknowledges the failure of a run as known or won't fix
+func AcknowledgeActionRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/acknowledge repository repoAcknowledgeActionRun
+	// ---
+	// summary: Acknowledge the failure of a run as known or won't fix
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/AcknowledgeActionRunOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.AcknowledgeActionRunOption)
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return
+	}
+
+	if err := actions_model.AcknowledgeRun(ctx, run, ctx.Doer.ID, strings.TrimSpace(form.Comment)); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "only failed runs can be acknowledged")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AcknowledgeRun", err)
+		}
+		return
+	}
+	writeActionRun(ctx, http.StatusOK, run)
+}
+
+// UnacknowledgeActionRun withdraws the acknowledgement of a failed run
+func UnacknowledgeActionRun(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{run}/acknowledge repository repoUnacknowledgeActionRun
+	// ---
+	// summary: Withdraw the acknowledgement of a failed run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return
+	}
+
+	if err := actions_model.UnacknowledgeRun(ctx, run); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnacknowledgeRun", err)
+		return
+	}
+	writeActionRun(ctx, http.StatusOK, run)
+}
+
+// CreateActionRunIssue opens an issue to track the failure of a run
+func CreateActionRunIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/issue repository repoCreateActionRunIssue
+	// ---
+	// summary: Open an issue to track the failure of a run, with the failed jobs and their last log lines
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionRunIssueOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateActionRunIssueOption)
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return
+	}
+
+	issue, err := actions_service.CreateIssueFromRun(ctx, ctx.Doer, run, form.AutoClose)
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case errors.Is(err, util.ErrAlreadyExist):
+			ctx.Error(http.StatusConflict, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CreateIssueFromRun", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(ctx, ctx.Doer, issue))
+}
+
+// RedeliverActionRunEvent delivers the event which triggered a run again
+func RedeliverActionRunEvent(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/redeliver repository repoRedeliverActionRunEvent
+	// ---
+	// summary: Deliver the event which triggered a run again, the workflows matching the event are triggered as new runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return
+	}
+
+	if err := actions_service.RedeliverRunEvent(ctx, run); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RedeliverRunEvent", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RerunActionRun reruns the jobs of a run, optionally with overridden variables and inputs
+func RerunActionRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/rerun repository repoRerunActionRun
+	// ---
+	// summary: Rerun the jobs of a run, the variables and inputs can be overridden for the new attempts
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RerunActionRunOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.RerunActionRunOption)
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return
+	}
+	if run.IsExternal() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the run is reported by an external CI system")
+		return
+	}
+	cfg := ctx.Repo.Repository.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig()
+	if cfg.IsWorkflowDisabled(run.WorkflowID) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("workflow %s is disabled", run.WorkflowID))
+		return
+	}
+
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+	var job *actions_model.ActionRunJob
+	if form.JobID != 0 {
+		for _, j := range jobs {
+			if j.ID == form.JobID {
+				job = j
+				break
+			}
+		}
+		if job == nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("job %d doesn't belong to the run", form.JobID))
+			return
+		}
+	}
+
+	opts := &actions_service.RerunOptions{
+		Overrides: &actions_model.RunOverrides{Vars: form.Vars, Inputs: form.Inputs},
+	}
+	if err := actions_service.RerunJobs(ctx, run, jobs, job, opts); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RerunJobs", err)
+		}
+		return
+	}
+	writeActionRun(ctx, http.StatusOK, run)
+}
+
+// BackfillActionRuns synthesizes push events for existing tags and commits to trigger their workflows
+func BackfillActionRuns(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/backfill repository repoBackfillActionRuns
+	// ---
+	// summary: Synthesize push events for existing tags and commits to trigger their workflows without pushing them again
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/BackfillActionRunsOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/ActionBackfilledEventList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.BackfillActionRunsOption)
+
+	events, err := actions_service.BackfillRuns(ctx, ctx.Repo.Repository, ctx.Doer, &actions_service.BackfillOptions{
+		Tags:   form.Tags,
+		Branch: form.Branch,
+		Since:  form.Since,
+		Until:  form.Until,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "BackfillRuns", err)
+		}
+		return
+	}
+
+	res := make([]*api.ActionBackfilledEvent, 0, len(events))
+	for _, event := range events {
+		res = append(res, &api.ActionBackfilledEvent{Ref: event.Ref, CommitSHA: event.CommitID})
+	}
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+// SimulateActionTrigger reports the workflows which would be triggered by an event
+func SimulateActionTrigger(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/workflows/simulate repository repoSimulateActionTrigger
+	// ---
+	// summary: Find the workflows an event would trigger and the jobs they would create, without creating any runs
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SimulateActionTriggerOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionTriggerSimulation"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SimulateActionTriggerOption)
+
+	result, err := actions_service.SimulateTrigger(ctx, ctx.Repo.Repository, ctx.Doer, &actions_service.SimulateOptions{
+		Event:   webhook_module.HookEventType(form.Event),
+		Ref:     form.Ref,
+		Payload: form.Payload,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SimulateTrigger", err)
+		}
+		return
+	}
+
+	res := &api.ActionTriggerSimulation{
+		Ref:       result.Ref,
+		CommitSHA: result.CommitID,
+		Skipped:   result.Skipped,
+		Workflows: make([]*api.ActionSimulatedWorkflow, 0, len(result.Workflows)),
+	}
+	for _, wf := range result.Workflows {
+		workflow := &api.ActionSimulatedWorkflow{
+			WorkflowID:   wf.WorkflowID,
+			TriggerEvent: wf.TriggerEvent,
+			Error:        wf.Error,
+			Jobs:         make([]*api.ActionSimulatedJob, 0, len(wf.Jobs)),
+		}
+		for _, job := range wf.Jobs {
+			workflow.Jobs = append(workflow.Jobs, &api.ActionSimulatedJob{
+				JobID:  job.JobID,
+				Name:   job.Name,
+				Needs:  job.Needs,
+				RunsOn: job.RunsOn,
+			})
+		}
+		res.Workflows = append(res.Workflows, workflow)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionCommitGating reports the workflows expected to run for a commit, whether they ran and why they didn't
+func GetActionCommitGating(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/commits/{sha}/gating repository repoGetActionCommitGating
+	// ---
+	// summary: Get every workflow expected to run for the push of a commit, whether it ran and why it didn't, followed by the other runs and failures of the commit
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: the commit
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: the ref the commit was pushed to, a branch or tag name is also accepted, the default branch by default
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionCommitGating"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	gating, err := actions_service.GetCommitGating(ctx, ctx.Repo.Repository, ctx.FormTrim("ref"), ctx.Params(":sha"))
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommitGating", err)
+		}
+		return
+	}
+
+	res := &api.ActionCommitGating{
+		Ref:       gating.Ref,
+		CommitSHA: gating.CommitID,
+		Workflows: make([]*api.ActionCommitGatingWorkflow, 0, len(gating.Workflows)),
+	}
+	for _, wf := range gating.Workflows {
+		workflow := &api.ActionCommitGatingWorkflow{
+			WorkflowID: wf.WorkflowID,
+			Event:      wf.Event,
+			State:      string(wf.State),
+		}
+		if wf.Run != nil {
+			workflow.RunID = wf.Run.ID
+			workflow.RunNumber = wf.Run.Index
+			workflow.HTMLURL = wf.Run.HTMLURL()
+			workflow.Status = wf.Run.Status.String()
+			if wf.Run.Status.IsDone() {
+				workflow.Conclusion = wf.Run.Status.String()
+			}
+		}
+		if wf.Failure != nil {
+			workflow.FailureReason = string(wf.Failure.Reason)
+			workflow.FailureMessage = wf.Failure.Message
+		}
+		res.Workflows = append(res.Workflows, workflow)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// EstimateActionDispatch estimates the jobs, duration and minutes of dispatching a workflow
+func EstimateActionDispatch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/workflows/estimate repository repoEstimateActionDispatch
+	// ---
+	// summary: Estimate the jobs, duration and minutes of dispatching a workflow by its latest successful runs, without dispatching it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EstimateActionDispatchOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDispatchEstimate"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EstimateActionDispatchOption)
+
+	estimate, err := actions_service.EstimateDispatch(ctx, ctx.Repo.Repository, &actions_service.DispatchEstimateOptions{
+		WorkflowID: form.WorkflowID,
+		Ref:        form.Ref,
+		Inputs:     form.Inputs,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "EstimateDispatch", err)
+		}
+		return
+	}
+
+	res := &api.ActionDispatchEstimate{
+		WorkflowID:         estimate.WorkflowID,
+		Ref:                estimate.Ref,
+		CommitSHA:          estimate.CommitID,
+		JobCount:           estimate.JobCount,
+		SampledRuns:        estimate.SampledRuns,
+		AverageRunDuration: int64(estimate.AverageRunDuration.Seconds()),
+		Minutes:            estimate.Minutes,
+		WeightedMinutes:    estimate.WeightedMinutes,
+		Jobs:               make([]*api.ActionDispatchEstimateJob, 0, len(estimate.Jobs)),
+	}
+	for _, job := range estimate.Jobs {
+		res.Jobs = append(res.Jobs, &api.ActionDispatchEstimateJob{
+			JobID:           job.JobID,
+			RunsOn:          job.RunsOn,
+			MatrixSize:      job.MatrixSize,
+			AverageDuration: int64(job.AverageDuration.Seconds()),
+			Minutes:         job.Minutes,
+			WeightedMinutes: job.WeightedMinutes,
+		})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ListActionDispatchPresets lists the dispatch presets of a repository
+func ListActionDispatchPresets(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/dispatch-presets repository repoListActionDispatchPresets
+	// ---
+	// summary: List the named input presets to dispatch the workflows of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDispatchPresetList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	presets, err := actions_model.GetDispatchPresets(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDispatchPresets", err)
+		return
+	}
+	res := make([]*api.ActionDispatchPreset, 0, len(presets))
+	for _, p := range presets {
+		res = append(res, convert.ToActionDispatchPreset(p))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionDispatchPreset gets a dispatch preset of a repository
+func GetActionDispatchPreset(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/dispatch-presets/{name} repository repoGetActionDispatchPreset
+	// ---
+	// summary: Get a named input preset to dispatch a workflow of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the dispatch preset
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDispatchPreset"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	preset := getDispatchPresetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionDispatchPreset(preset))
+}
+
+// SaveActionDispatchPreset creates or replaces a dispatch preset of a repository
+func SaveActionDispatchPreset(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/dispatch-presets/{name} repository repoSaveActionDispatchPreset
+	// ---
+	// summary: Create or replace a named input preset to dispatch a workflow of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the dispatch preset
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SaveActionDispatchPresetOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDispatchPreset"
+	//   "201":
+	//     "$ref": "#/responses/ActionDispatchPreset"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SaveActionDispatchPresetOption)
+	name := ctx.Params(":name")
+	if len(name) > 255 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the name of a dispatch preset can't be longer than 255 characters")
+		return
+	}
+
+	preset := &actions_model.ActionDispatchPreset{
+		Name:       name,
+		WorkflowID: form.WorkflowID,
+		Ref:        form.Ref,
+		Inputs:     form.Inputs,
+	}
+	created, err := actions_service.SaveDispatchPreset(ctx, ctx.Repo.Repository, ctx.Doer, preset)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SaveDispatchPreset", err)
+		}
+		return
+	}
+
+	preset, err = actions_model.GetDispatchPresetByName(ctx, ctx.Repo.Repository.ID, name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDispatchPresetByName", err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, convert.ToActionDispatchPreset(preset))
+}
+
+// DeleteActionDispatchPreset deletes a dispatch preset of a repository
+func DeleteActionDispatchPreset(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/dispatch-presets/{name} repository repoDeleteActionDispatchPreset
+	// ---
+	// summary: Delete a named input preset to dispatch a workflow of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the dispatch preset
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := actions_model.DeleteDispatchPreset(ctx, ctx.Repo.Repository.ID, ctx.Params(":name")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteDispatchPreset", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DispatchActionPreset dispatches the workflow of a dispatch preset with its inputs
+func DispatchActionPreset(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/dispatch-presets/{name}/dispatch repository repoDispatchActionPreset
+	// ---
+	// summary: Dispatch the workflow of a named input preset with its inputs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the dispatch preset
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	preset := getDispatchPresetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.DispatchPreset(ctx, ctx.Repo.Repository, ctx.Doer, preset); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DispatchPreset", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getDispatchPresetByParams(ctx *context.APIContext) *actions_model.ActionDispatchPreset {
+	preset, err := actions_model.GetDispatchPresetByName(ctx, ctx.Repo.Repository.ID, ctx.Params(":name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetDispatchPresetByName", err)
+		}
+		return nil
+	}
+	return preset
+}
+
+func toExternalJobOptions(ctx *context.APIContext, opts []*api.ExternalRunJobOption) ([]*actions_service.ExternalJobOptions, bool) {
+	jobs := make([]*actions_service.ExternalJobOptions, 0, len(opts))
+	for _, v := range opts {
+		status, ok := actions_model.StatusFromString(v.Status)
+		if !ok || status.In(actions_model.StatusUnknown, actions_model.StatusBlocked) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid status %q of job %q", v.Status, v.Name))
+			return nil, false
+		}
+		jobs = append(jobs, &actions_service.ExternalJobOptions{
+			Name:   v.Name,
+			Status: status,
+			URL:    v.URL,
+		})
+	}
+	return jobs, true
+}
+
+func writeActionRun(ctx *context.APIContext, status int, run *actions_model.ActionRun) {
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+	apiRun, err := convert.ToActionRun(ctx, run, jobs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+		return
+	}
+	ctx.JSON(status, apiRun)
+}
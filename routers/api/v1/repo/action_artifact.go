@@ -0,0 +1,257 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/common"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListActionRunArtifacts lists the artifacts uploaded by a run
+func ListActionRunArtifacts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/artifacts repository repoListActionRunArtifacts
+	// ---
+	// summary: List the artifacts uploaded by a run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionArtifactList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	artifacts, err := getActionRunArtifacts(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListUploadedArtifactsMeta", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, artifacts)
+}
+
+// getActionRunArtifacts returns the artifacts uploaded by the run, it's empty for the users who can't see the details of the run
+func getActionRunArtifacts(ctx *context.APIContext, run *actions_model.ActionRun) ([]*api.ActionArtifact, error) {
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		return []*api.ActionArtifact{}, nil
+	}
+
+	artifacts, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	apiArtifacts := make([]*api.ActionArtifact, 0, len(artifacts))
+	for _, art := range artifacts {
+		status := "completed"
+		if art.Status == actions_model.ArtifactStatusExpired {
+			status = "expired"
+		}
+		apiArtifacts = append(apiArtifacts, &api.ActionArtifact{
+			Name:    art.ArtifactName,
+			Size:    art.FileSize,
+			Status:  status,
+			Expires: art.ExpiredUnix.AsTime(),
+		})
+	}
+	return apiArtifacts, nil
+}
+
+// DownloadActionRunArtifact downloads an artifact of a run as a zip file
+func DownloadActionRunArtifact(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/artifacts/{artifact_name} repository repoDownloadActionRunArtifact
+	// ---
+	// summary: Download an artifact of a run as a zip file
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the zip file of the artifact
+	//     schema:
+	//       type: file
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.NotFound()
+		return
+	}
+
+	if err := common.DownloadActionsArtifact(ctx.Base, run.ID, ctx.Params(":artifact_name")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DownloadActionsArtifact", err)
+		}
+	}
+}
+
+// GetActionRunArtifactURL returns an expiring link to download an artifact of a run
+func GetActionRunArtifactURL(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/artifacts/{artifact_name}/url repository repoGetActionRunArtifactURL
+	// ---
+	// summary: Get a link to download an artifact of a run as a zip file without authentication until it expires
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDownloadURL"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.NotFound()
+		return
+	}
+
+	u, err := actions_service.GetArtifactDownloadURL(ctx, run, ctx.Params(":artifact_name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetArtifactDownloadURL", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionDownloadURL{URL: u.URL, Expires: u.Expires})
+}
+
+// DeleteActionRunArtifact deletes an artifact of a run
+func DeleteActionRunArtifact(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{run}/artifacts/{artifact_name} repository repoDeleteActionRunArtifact
+	// ---
+	// summary: Delete an artifact of a run, the files are removed in the background
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	artifactName := ctx.Params(":artifact_name")
+	exist, err := db.Exist[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
+		RunID:        run.ID,
+		ArtifactName: artifactName,
+		Status:       int(actions_model.ArtifactStatusUploadConfirmed),
+	}.ToConds())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Exist", err)
+		return
+	}
+	if !exist {
+		ctx.NotFound()
+		return
+	}
+	if err := actions_model.SetArtifactNeedDelete(ctx, run.ID, artifactName); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetArtifactNeedDelete", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/httpcache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/services/context"
+)
+
+// checkoutCacheDuration is how long the clients could cache the bundles and the packs of the commits of the runs,
+// they never change since the commits are immutable
+const checkoutCacheDuration = 24 * time.Hour
+
+// GetActionRunBundle downloads a git bundle of the commit of a run
+func GetActionRunBundle(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/bundle repository repoGetActionRunBundle
+	// ---
+	// summary: Download a git bundle of the commit of a run with its history
+	// description: The bundle could be cloned by the runners instead of the repository, it's cached by the ETag of the commit.
+	// produces:
+	// - application/octet-stream
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the git bundle of the commit
+	//     schema:
+	//       type: file
+	//   "304":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if run.IsExternal() {
+		ctx.NotFound()
+		return
+	}
+
+	if httpcache.HandleGenericETagCache(ctx.Req, ctx.Resp, fmt.Sprintf(`"%s"`, run.CommitSHA)) {
+		return
+	}
+	serveActionRunCheckout(ctx, run, fmt.Sprintf("%s-%s.bundle", ctx.Repo.Repository.Name, run.CommitSHA), func(w io.Writer) error {
+		return ctx.Repo.GitRepo.CreateBundle(ctx, run.CommitSHA, w)
+	})
+}
+
+// GetActionRunPackfile downloads a git pack of the commit of a run
+func GetActionRunPackfile(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/packfile repository repoGetActionRunPackfile
+	// ---
+	// summary: Download a git pack of the commit of a run and its ancestors to the depth
+	// description: The depth and the filter default to the checkout hints declared by the workflow.
+	//   The pack could be indexed by `git index-pack` into a shallow repository, it's cached by the ETag of the commit, the depth and the filter.
+	// produces:
+	// - application/octet-stream
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: depth
+	//   in: query
+	//   description: how many commits of the history to include, 0 for all
+	//   type: integer
+	// - name: filter
+	//   in: query
+	//   description: partial clone filter of the objects, `blob:none`, `tree:0` or `blob:limit=<size>`
+	//   type: string
+	// responses:
+	//   "200":
+	//     description: the git pack of the commit
+	//     schema:
+	//       type: file
+	//   "304":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if run.IsExternal() {
+		ctx.NotFound()
+		return
+	}
+
+	checkout := run.Checkout
+	if checkout == nil {
+		checkout = &actions_model.RunCheckout{}
+	}
+	depth, filter := checkout.Depth, checkout.Filter
+	if ctx.FormString("depth") != "" {
+		depth = ctx.FormInt("depth")
+	}
+	if ctx.FormString("filter") != "" {
+		filter = ctx.FormTrim("filter")
+	}
+	if depth < 0 || depth > actions_module.MaxCheckoutDepth {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid depth %d, it should be between 0 and %d", depth, actions_module.MaxCheckoutDepth))
+		return
+	}
+	if err := actions_module.ValidateCheckoutFilter(filter); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if httpcache.HandleGenericETagCache(ctx.Req, ctx.Resp, fmt.Sprintf(`"%s-%d-%s"`, run.CommitSHA, depth, filter)) {
+		return
+	}
+	serveActionRunCheckout(ctx, run, fmt.Sprintf("%s-%s.pack", ctx.Repo.Repository.Name, run.CommitSHA), func(w io.Writer) error {
+		return ctx.Repo.GitRepo.CreateCommitPack(ctx, run.CommitSHA, depth, filter, w)
+	})
+}
+
+// serveActionRunCheckout streams the content written by the function, the headers are set when it starts writing,
+// so the failures before that could still be responded
+func serveActionRunCheckout(ctx *context.APIContext, run *actions_model.ActionRun, filename string, write func(w io.Writer) error) {
+	w := &checkoutResponseWriter{ctx: ctx, filename: filename}
+	if err := write(w); err != nil {
+		if !w.started {
+			ctx.Error(http.StatusInternalServerError, "WriteCheckout", err)
+			return
+		}
+		log.Error("Failed to write %s of run %d: %v", filename, run.ID, err)
+	}
+}
+
+type checkoutResponseWriter struct {
+	ctx      *context.APIContext
+	filename string
+	started  bool
+}
+
+func (w *checkoutResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.SetServeHeaders(&context.ServeHeaderOptions{
+			Filename:      w.filename,
+			CacheDuration: checkoutCacheDuration,
+		})
+	}
+	return w.ctx.Resp.Write(p)
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// maxSARIFSize is the largest SARIF log which could be uploaded
+const maxSARIFSize = 32 << 20
+
+// UploadActionRunSARIF uploads the code scanning results of a run
+func UploadActionRunSARIF(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/sarif repository repoUploadActionRunSARIF
+	// ---
+	// summary: Upload the code scanning results of a run in a SARIF 2.1.0 log
+	// description: The results update the alerts of the ref of the run, the open alerts of the tools of the log which aren't reported any more are fixed.
+	//   The tokens of the jobs could only upload to their own runs.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: the SARIF 2.1.0 log
+	//   schema:
+	//     type: object
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionCodeScanningUpload"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if checkActionsTaskOfRun(ctx, run); ctx.Written() {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Req.Body, maxSARIFSize+1))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReadAll", err)
+		return
+	}
+	if len(body) > maxSARIFSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", "the SARIF log is too large")
+		return
+	}
+	upload, err := actions_module.ParseSARIF(bytes.NewReader(body))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	stats, err := actions_model.UploadCodeScanningResults(ctx, run, upload)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "UploadCodeScanningResults", err)
+		return
+	}
+	tools := upload.Tools
+	if tools == nil {
+		tools = []string{}
+	}
+	ctx.JSON(http.StatusCreated, &api.ActionCodeScanningUpload{
+		Tools:    tools,
+		Results:  stats.Results,
+		New:      stats.New,
+		Reopened: stats.Reopened,
+		Fixed:    stats.Fixed,
+	})
+}
+
+// ListActionCodeScanningAlerts lists the code scanning alerts of a repository
+func ListActionCodeScanningAlerts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/code-scanning/alerts repository repoListActionCodeScanningAlerts
+	// ---
+	// summary: List the code scanning alerts uploaded by the runs of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: the ref of the alerts, a branch name or a full ref like `refs/pull/1/head`
+	//   type: string
+	// - name: state
+	//   in: query
+	//   description: the state of the alerts
+	//   type: string
+	//   enum: [open, fixed]
+	// - name: tool
+	//   in: query
+	//   description: the name of the tool
+	//   type: string
+	// - name: severity
+	//   in: query
+	//   description: the severity of the alerts
+	//   type: string
+	//   enum: [error, warning, note]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionCodeScanningAlertList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	ref := ctx.FormTrim("ref")
+	if ref != "" && !strings.HasPrefix(ref, "refs/") {
+		ref = git.RefNameFromBranch(ref).String()
+	}
+	alerts, count, err := db.FindAndCount[actions_model.ActionCodeScanningAlert](ctx, actions_model.FindCodeScanningAlertsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		Ref:         ref,
+		State:       ctx.FormTrim("state"),
+		Tool:        ctx.FormTrim("tool"),
+		Severity:    ctx.FormTrim("severity"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindCodeScanningAlerts", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, convert.ToActionCodeScanningAlerts(alerts))
+}
+
+// CompareActionCodeScanningAlerts compares the code scanning alerts of a pull request to the ones of its base branch
+func CompareActionCodeScanningAlerts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/code-scanning/pulls/{index} repository repoCompareActionCodeScanningAlerts
+	// ---
+	// summary: Compare the code scanning alerts of a pull request to the ones of its base branch
+	// description: The pull requests could be gated by whether there are new alerts.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionCodeScanningComparison"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	baseRef := git.RefNameFromBranch(pr.BaseBranch).String()
+	headRef := pr.GetGitRefName()
+	newAlerts, fixedAlerts, err := actions_model.CompareCodeScanningAlerts(ctx, ctx.Repo.Repository.ID, baseRef, headRef)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CompareCodeScanningAlerts", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionCodeScanningComparison{
+		BaseRef:     baseRef,
+		HeadRef:     headRef,
+		NewAlerts:   convert.ToActionCodeScanningAlerts(newAlerts),
+		FixedAlerts: convert.ToActionCodeScanningAlerts(fixedAlerts),
+	})
+}
@@ -0,0 +1,309 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unit"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListActionRunComments lists the comments of a run
+func ListActionRunComments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/comments repository repoListActionRunComments
+	// ---
+	// summary: List the comments of a run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunCommentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	comments, count, err := db.FindAndCount[actions_model.ActionRunComment](ctx, actions_model.FindRunCommentsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RunID:       run.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRunComments", err)
+		return
+	}
+	if err := actions_model.RunCommentList(comments).LoadPosters(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadPosters", err)
+		return
+	}
+
+	apiComments := make([]*api.ActionRunComment, 0, len(comments))
+	for _, c := range comments {
+		apiComment, err := convert.ToActionRunComment(ctx, c, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionRunComment", err)
+			return
+		}
+		apiComments = append(apiComments, apiComment)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiComments)
+}
+
+// CreateActionRunComment creates a comment on a run or a job of it
+func CreateActionRunComment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/comments repository repoCreateActionRunComment
+	// ---
+	// summary: Create a comment on a run or a job of it, the mentioned users will be notified
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionRunCommentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunComment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateActionRunCommentOption)
+
+	run := getActionRunByParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	comment, err := actions_service.CreateRunComment(ctx, ctx.Doer, run, form.JobID, form.Body)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateRunComment", err)
+		}
+		return
+	}
+	apiComment, err := convert.ToActionRunComment(ctx, comment, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRunComment", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiComment)
+}
+
+// EditActionRunComment edits a comment of a run
+func EditActionRunComment(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/actions/runs/{run}/comments/{id} repository repoEditActionRunComment
+	// ---
+	// summary: Edit a comment of a run
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunCommentOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunComment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditActionRunCommentOption)
+
+	comment := getActionRunCommentForModification(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.UpdateRunComment(ctx, comment, form.Body); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateRunComment", err)
+		}
+		return
+	}
+	apiComment, err := convert.ToActionRunComment(ctx, comment, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRunComment", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiComment)
+}
+
+// DeleteActionRunComment deletes a comment of a run
+func DeleteActionRunComment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{run}/comments/{id} repository repoDeleteActionRunComment
+	// ---
+	// summary: Delete a comment of a run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getActionRunCommentForModification(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_model.DeleteRunComment(ctx, comment.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRunComment", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// getActionRunByParam gets the run of the repository by the number in the route
+func getActionRunByParam(ctx *context.APIContext) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return nil
+	}
+	run.Repo = ctx.Repo.Repository
+	return run
+}
+
+// getActionRunCommentForModification gets the comment to edit or delete,
+// only the poster and the users who can write Actions of the repository are allowed.
+func getActionRunCommentForModification(ctx *context.APIContext) *actions_model.ActionRunComment {
+	run := getActionRunByParam(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	comment, err := actions_model.GetRunCommentByID(ctx, run.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunCommentByID", err)
+		}
+		return nil
+	}
+	if !actions_service.CanModifyRunComment(ctx.Doer, comment, ctx.Repo.CanWrite(unit.TypeActions)) {
+		ctx.Error(http.StatusForbidden, "", "you can't modify the comment")
+		return nil
+	}
+	return comment
+}
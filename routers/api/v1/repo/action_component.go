@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListActionComponents lists the components of a monorepo with the statistics of their runs
+func ListActionComponents(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/components repository repoListActionComponents
+	// ---
+	// summary: List the components of the runs of a repository with their statistics
+	// description: The runs of a component could be listed by the `component` query parameter of the runs list.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionComponentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	stats, err := actions_model.GetComponentStats(db.WithReplica(ctx), ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetComponentStats", err)
+		return
+	}
+
+	res := make([]*api.ActionComponent, 0, len(stats))
+	for _, s := range stats {
+		component := &api.ActionComponent{
+			Name:           s.Component,
+			TotalRuns:      s.TotalRuns,
+			SuccessfulRuns: s.SuccessfulRuns,
+			FailedRuns:     s.FailedRuns,
+			CancelledRuns:  s.CancelledRuns,
+		}
+		if s.LatestRun != nil {
+			s.LatestRun.Repo = ctx.Repo.Repository
+			component.LatestRun, err = convert.ToActionRun(ctx, s.LatestRun, nil)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+				return
+			}
+		}
+		res = append(res, component)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// maxActionsConfigBundleSize is the largest Actions configuration bundle which could be imported
+const maxActionsConfigBundleSize = 1 << 20
+
+// ExportActionsConfigBundle exports the Actions configuration of a repository as a YAML bundle
+func ExportActionsConfigBundle(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/config/export repository repoExportActionsConfigBundle
+	// ---
+	// summary: Export the Actions configuration of a repository as a YAML bundle, its settings, variables and dispatch presets
+	// description: The secrets and the federation aren't exported. The schema of the bundle is ActionsConfigBundle.
+	// produces:
+	// - application/yaml
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the YAML bundle
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	bundle, err := actions_service.ExportConfigBundle(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ExportConfigBundle", err)
+		return
+	}
+	data, err := actions_service.MarshalConfigBundle(bundle)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "MarshalConfigBundle", err)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Disposition", `attachment; filename="`+ctx.Repo.Repository.Name+`-actions.yaml"`)
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(data); err != nil {
+		log.Error("Write: %v", err)
+	}
+}
+
+// ImportActionsConfigBundle imports a YAML bundle into the Actions configuration of a repository
+func ImportActionsConfigBundle(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/config/import repository repoImportActionsConfigBundle
+	// ---
+	// summary: Import a YAML bundle exported from a repository into the Actions configuration of a repository
+	// description: The settings are checked like editing them and the variables are checked before anything is written.
+	//   The variables and the dispatch presets are created or replaced by their names, the ones missing from the bundle are kept.
+	//   The dispatch presets which couldn't dispatch their workflows in the repository are skipped.
+	// consumes:
+	// - application/yaml
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: the YAML bundle
+	//   schema:
+	//     "$ref": "#/definitions/ActionsConfigBundle"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsConfigImport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Req.Body, maxActionsConfigBundleSize+1))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReadAll", err)
+		return
+	}
+	if len(body) > maxActionsConfigBundleSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", "the bundle is too large")
+		return
+	}
+	bundle, err := actions_service.UnmarshalConfigBundle(body)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	cfg, err := getActionsConfig(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUnit", err)
+		return
+	}
+	if cfg == nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("Actions are disabled in the repository"))
+		return
+	}
+	if bundle.Settings != nil {
+		// whether Actions are enabled and the federation are bound to the repository, they aren't imported
+		bundle.Settings.Enabled = nil
+		bundle.Settings.FederationPeer = nil
+		bundle.Settings.FederationToken = nil
+		if !applyActionsSettingsOption(ctx, bundle.Settings, cfg) {
+			return
+		}
+	}
+
+	res, err := actions_service.ImportConfigBundle(ctx, ctx.Repo.Repository, ctx.Doer, bundle)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "ImportConfigBundle", err)
+		return
+	}
+
+	if bundle.Settings != nil {
+		if err := repo_service.UpdateRepositoryUnits(ctx, ctx.Repo.Repository, []repo_model.RepoUnit{{
+			RepoID: ctx.Repo.Repository.ID,
+			Type:   unit_model.TypeActions,
+			Config: cfg,
+		}}, nil); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepositoryUnits", err)
+			return
+		}
+	}
+	res.Settings = convert.ToRepoActionsSettings(cfg)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// maxSnapshotDependencies is how many dependencies a submission of a dependency snapshot could have
+const maxSnapshotDependencies = 50000
+
+// SubmitActionRunDependencies submits the dependency manifests of the commit of a run
+func SubmitActionRunDependencies(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/dependencies repository repoSubmitActionRunDependencies
+	// ---
+	// summary: Submit the dependency manifests of the commit of a run to the snapshot of its ref
+	// description: The manifests replace the ones of the same paths submitted for the same commit, so the jobs of different ecosystems
+	//   could submit their own. A submission of a new commit replaces the snapshot. The tokens of the jobs could only submit to their own runs.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SubmitActionDependencySnapshotOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionDependencySnapshot"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if checkActionsTaskOfRun(ctx, run); ctx.Written() {
+		return
+	}
+
+	opts := web.GetForm(ctx).(*api.SubmitActionDependencySnapshotOption)
+	manifests, err := toDependencyManifests(opts.Manifests)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	snapshot, err := actions_model.SubmitDependencySnapshot(ctx, run, manifests)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SubmitDependencySnapshot", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToActionDependencySnapshot(snapshot))
+}
+
+func toDependencyManifests(opts []*api.ActionDependencyManifest) ([]*actions_model.DependencyManifest, error) {
+	manifests := make([]*actions_model.DependencyManifest, 0, len(opts))
+	paths := make(map[string]bool, len(opts))
+	total := 0
+	for _, opt := range opts {
+		if opt == nil || strings.TrimSpace(opt.Path) == "" {
+			return nil, errors.New("the path of the manifest is required")
+		}
+		if paths[opt.Path] {
+			return nil, fmt.Errorf("duplicate manifest %q", opt.Path)
+		}
+		paths[opt.Path] = true
+
+		manifest := &actions_model.DependencyManifest{
+			Path:         opt.Path,
+			Ecosystem:    opt.Ecosystem,
+			Dependencies: make([]*actions_model.Dependency, 0, len(opt.Dependencies)),
+		}
+		for _, dep := range opt.Dependencies {
+			if dep == nil || strings.TrimSpace(dep.Name) == "" {
+				return nil, fmt.Errorf("the name of a dependency of manifest %q is required", opt.Path)
+			}
+			manifest.Dependencies = append(manifest.Dependencies, &actions_model.Dependency{
+				Name:    dep.Name,
+				Version: dep.Version,
+				Scope:   dep.Scope,
+			})
+		}
+		if total += len(manifest.Dependencies); total > maxSnapshotDependencies {
+			return nil, fmt.Errorf("too many dependencies, at most %d are allowed", maxSnapshotDependencies)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// GetActionDependencySnapshot gets the latest dependency snapshot of a ref
+func GetActionDependencySnapshot(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/dependencies repository repoGetActionDependencySnapshot
+	// ---
+	// summary: Get the latest dependency snapshot of a ref submitted by the runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: a branch name or a full ref like `refs/pull/1/head`, the default branch if it's empty
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDependencySnapshot"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	ref := ctx.FormTrim("ref")
+	if ref == "" {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	if !strings.HasPrefix(ref, "refs/") {
+		ref = git.RefNameFromBranch(ref).String()
+	}
+	snapshot, err := actions_model.GetDependencySnapshot(ctx, ctx.Repo.Repository.ID, ref)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetDependencySnapshot", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionDependencySnapshot(snapshot))
+}
+
+// ReviewActionDependencies lists the changes of the dependencies of a pull request
+func ReviewActionDependencies(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/dependencies/pulls/{index} repository repoReviewActionDependencies
+	// ---
+	// summary: List the dependencies added, removed or updated by a pull request
+	// description: The latest dependency snapshot of the head of the pull request is compared to the one of its base branch,
+	//   so the pull requests could be gated by the changes of the dependencies.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDependencyReview"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	snapshots := make([]*actions_model.ActionDependencySnapshot, 0, 2)
+	for _, ref := range []string{git.RefNameFromBranch(pr.BaseBranch).String(), pr.GetGitRefName()} {
+		snapshot, err := actions_model.GetDependencySnapshot(ctx, ctx.Repo.Repository.ID, ref)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				ctx.NotFound(fmt.Errorf("no dependency snapshot of %s has been submitted", ref))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetDependencySnapshot", err)
+			}
+			return
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	base, head := snapshots[0], snapshots[1]
+
+	ctx.JSON(http.StatusOK, &api.ActionDependencyReview{
+		BaseRef:       base.Ref,
+		BaseCommitSHA: base.CommitSHA,
+		HeadRef:       head.Ref,
+		HeadCommitSHA: head.CommitSHA,
+		Changes:       convert.ToActionDependencyChanges(actions_model.DiffDependencySnapshots(base, head)),
+	})
+}
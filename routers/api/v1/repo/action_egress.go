@@ -0,0 +1,153 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// SubmitActionRunEgressViolations reports the connections of a job blocked by the egress policy of its run
+func SubmitActionRunEgressViolations(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/egress-violations repository repoSubmitActionRunEgressViolations
+	// ---
+	// summary: Report the connections of a job blocked by the egress policy of its run
+	// description: Only the tokens of the running jobs of the run could report, the runners enforcing the egress policy
+	//   get the url from `gitea.gitea_egress.report_url`. The connections allowed by the policy are ignored.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SubmitActionEgressViolationsOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionEgressViolationList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.Doer.ID != user_model.ActionsUserID {
+		ctx.Error(http.StatusForbidden, "", "only the jobs of the run could report the egress violations")
+		return
+	}
+	if checkActionsTaskOfRun(ctx, run); ctx.Written() {
+		return
+	}
+
+	opts := web.GetForm(ctx).(*api.SubmitActionEgressViolationsOption)
+	connections := make([]*actions_service.EgressConnection, 0, len(opts.Connections))
+	for _, c := range opts.Connections {
+		if c == nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", "the host of the connection is required")
+			return
+		}
+		connections = append(connections, &actions_service.EgressConnection{
+			Host:  c.Host,
+			Port:  c.Port,
+			Count: c.Count,
+		})
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, ctx.Data["ActionsTaskID"].(int64))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTaskByID", err)
+		return
+	}
+	violations, err := actions_service.RecordEgressViolations(ctx, task, connections)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RecordEgressViolations", err)
+		}
+		return
+	}
+	res := make([]*api.ActionEgressViolation, 0, len(violations))
+	for _, v := range violations {
+		res = append(res, convert.ToActionEgressViolation(v))
+	}
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// ListActionRunEgressViolations lists the connections of the jobs of a run blocked by its egress policy
+func ListActionRunEgressViolations(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/egress-violations repository repoListActionRunEgressViolations
+	// ---
+	// summary: List the connections of the jobs of a run blocked by its egress policy, the latest ones go first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionEgressViolationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	violations, err := actions_model.FindEgressViolationsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindEgressViolationsByRunID", err)
+		return
+	}
+	res := make([]*api.ActionEgressViolation, 0, len(violations))
+	for _, v := range violations {
+		res = append(res, convert.ToActionEgressViolation(v))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionRunEvidence exports the signed evidence bundle of a run for audits
+func GetActionRunEvidence(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/evidence repository repoGetActionRunEvidence
+	// ---
+	// summary: Export the evidence bundle of a run for audits, a zip file of the workflow definitions, the digests of the logs and the artifacts, the approval and the token permissions, signed by the instance
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the zip file of the evidence bundle
+	//     schema:
+	//       type: file
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	// the bundle is built in memory, so a failure is reported by the status code rather than by a broken zip file
+	buf := &bytes.Buffer{}
+	if err := actions_service.WriteEvidenceBundle(ctx, run, buf); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "WriteEvidenceBundle", err)
+		}
+		return
+	}
+	size := int64(buf.Len())
+	ctx.SetServeHeaders(&context.ServeHeaderOptions{
+		Filename:      fmt.Sprintf("%s-run-%d-evidence.zip", ctx.Repo.Repository.Name, run.Index),
+		ContentType:   "application/zip",
+		ContentLength: &size,
+	})
+	_, _ = buf.WriteTo(ctx.Resp)
+}
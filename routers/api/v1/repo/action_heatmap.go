@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionsHeatmapData gets the heatmap of the automation activity of the repository
+func GetActionsHeatmapData(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/heatmap repository repoGetActionsHeatmapData
+	// ---
+	// summary: Get the heatmap of the automation activity of the repository, the successful runs of the events configured by the administrator
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserHeatmapData"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	heatmap, err := activities_model.GetRepoAutomationHeatmapData(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoAutomationHeatmapData", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, heatmap)
+}
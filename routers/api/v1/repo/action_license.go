@@ -0,0 +1,151 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// SubmitActionRunLicenseScan submits the result of the license scan of a job
+func SubmitActionRunLicenseScan(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/licenses repository repoSubmitActionRunLicenseScan
+	// ---
+	// summary: Submit the licenses of the dependencies scanned by a job to check them against the license policy of the owner
+	// description: Only the tokens of the running jobs of the run could submit. The result is reported as the commit status
+	//   `actions / license-check` of the commit of the run, which could be a required status check, and the job fails when it's done
+	//   if the scan fails.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SubmitActionLicenseScanOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionLicenseScan"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.Doer.ID != user_model.ActionsUserID {
+		ctx.Error(http.StatusForbidden, "", "only the jobs of the run could submit the license scans")
+		return
+	}
+	if checkActionsTaskOfRun(ctx, run); ctx.Written() {
+		return
+	}
+
+	opts := web.GetForm(ctx).(*api.SubmitActionLicenseScanOption)
+	packages := make([]*actions_model.LicensedPackage, 0, len(opts.Packages))
+	for _, pkg := range opts.Packages {
+		if pkg == nil || strings.TrimSpace(pkg.Name) == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "the name of the package is required")
+			return
+		}
+		packages = append(packages, &actions_model.LicensedPackage{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			License: pkg.License,
+		})
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, ctx.Data["ActionsTaskID"].(int64))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTaskByID", err)
+		return
+	}
+	scan, err := actions_service.SubmitLicenseScan(ctx, task, packages)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SubmitLicenseScan", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToActionLicenseScan(scan))
+}
+
+// ListActionRunLicenseScans lists the license scans of a run
+func ListActionRunLicenseScans(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/licenses repository repoListActionRunLicenseScans
+	// ---
+	// summary: List the license scans submitted by the jobs of a run, the latest ones go first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionLicenseScanList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	scans, err := actions_model.FindLicenseScansByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindLicenseScansByRunID", err)
+		return
+	}
+	res := make([]*api.ActionLicenseScan, 0, len(scans))
+	for _, scan := range scans {
+		res = append(res, convert.ToActionLicenseScan(scan))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,207 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
+)
+
+// RecordActionRunPackage records a package version as published by a run
+func RecordActionRunPackage(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/packages repository repoRecordActionRunPackage
+	// ---
+	// summary: Record a package version as published by a run, so the package retention of the repository applies to it
+	// description: Only the tokens of the running jobs of the run could record. The package must be linked to the repository,
+	//   and a version could only be recorded for one run.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ActionRunPackageOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Package"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.Doer.ID != user_model.ActionsUserID {
+		ctx.Error(http.StatusForbidden, "", "only the jobs of the run could record the packages")
+		return
+	}
+	if checkActionsTaskOfRun(ctx, run); ctx.Written() {
+		return
+	}
+
+	opts := web.GetForm(ctx).(*api.ActionRunPackageOption)
+	pv, err := actions_service.RecordPackagePublished(ctx, run, packages_model.Type(opts.Type), opts.Name, opts.Version)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, "", err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RecordPackagePublished", err)
+		}
+		return
+	}
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackageDescriptor", err)
+		return
+	}
+	p, err := convert.ToPackage(ctx, pd, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToPackage", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, p)
+}
+
+// ListActionRunPackages lists the package versions published by a run
+func ListActionRunPackages(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/packages repository repoListActionRunPackages
+	// ---
+	// summary: List the package versions recorded as published by a run, the latest ones go first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	pvs, err := actions_service.FindRunPackageVersions(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRunPackageVersions", err)
+		return
+	}
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackageDescriptors", err)
+		return
+	}
+	res := make([]*api.Package, 0, len(pds))
+	for _, pd := range pds {
+		p, err := convert.ToPackage(ctx, pd, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToPackage", err)
+			return
+		}
+		res = append(res, p)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionsPackageRetentionReport lists the package versions which the package retention of a repository removes
+func GetActionsPackageRetentionReport(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/package-retention/report repository repoGetActionsPackageRetentionReport
+	// ---
+	// summary: List the package versions published by the runs which the package retention of a repository removes, the latest ones go first
+	// description: It's the dry run of the package retention, nothing is removed. The list is empty if the package retention is disabled.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionPackageRetentionRemovalList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	cfg, err := getActionsConfig(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUnit", err)
+		return
+	}
+	res := []*api.ActionPackageRetentionRemoval{}
+	if cfg == nil || cfg.PackageRetention == nil {
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+	removals, err := packages_cleanup_service.FindActionsRetentionRemovals(ctx, ctx.Repo.Repository, cfg.PackageRetention)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindActionsRetentionRemovals", err)
+		return
+	}
+	for _, r := range removals {
+		res = append(res, &api.ActionPackageRetentionRemoval{
+			Type:      string(r.Package.Type),
+			Name:      r.Package.Name,
+			Version:   r.Version.Version,
+			Ref:       r.Ref,
+			RunID:     r.RunID,
+			CreatedAt: r.Version.CreatedUnix.AsTime(),
+		})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
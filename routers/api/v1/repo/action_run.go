@@ -0,0 +1,761 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/httpcache"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/routers/common"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListActionRuns lists the runs of the repository
+func ListActionRuns(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs repository repoListActionRuns
+	// ---
+	// summary: List the runs of the repository, the latest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow
+	//   in: query
+	//   description: file name of the workflow
+	//   type: string
+	// - name: branch
+	//   in: query
+	//   description: branch which the runs were triggered on
+	//   type: string
+	// - name: event
+	//   in: query
+	//   description: event which triggered the runs
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: status of the runs
+	//   type: string
+	//   enum: [success, failure, cancelled, skipped, waiting, running, blocked]
+	// - name: component
+	//   in: query
+	//   description: component of the monorepo which the runs belong to
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// - name: wait
+	//   in: query
+	//   description: seconds to wait for the runs to change if they match the `If-None-Match` header, up to 60
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunList"
+	//   "304":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := actions_model.FindRunOptions{
+		ListOptions:  listOptions,
+		RepoID:       ctx.Repo.Repository.ID,
+		WorkflowID:   ctx.FormTrim("workflow"),
+		TriggerEvent: webhook_module.HookEventType(ctx.FormTrim("event")),
+		Component:    ctx.FormTrim("component"),
+	}
+	if branch := ctx.FormTrim("branch"); branch != "" {
+		opts.Ref = git.RefNameFromBranch(branch).String()
+	}
+	if name := ctx.FormTrim("status"); name != "" {
+		status, ok := actions_model.StatusFromString(name)
+		if !ok || status.IsUnknown() {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid status: %s", name))
+			return
+		}
+		opts.Status = []actions_model.Status{status}
+	}
+
+	var (
+		runs  []*actions_model.ActionRun
+		total int64
+	)
+	load := func(c gocontext.Context) (string, error) {
+		var err error
+		runs, total, err = db.FindAndCount[actions_model.ActionRun](db.WithReplica(c), opts)
+		if err != nil {
+			return "", err
+		}
+		return actions_service.RunsETag(runs, total), nil
+	}
+	if !waitActionRunsChange(ctx, load) {
+		return
+	}
+
+	apiRuns := make([]*api.ActionRun, 0, len(runs))
+	for _, run := range runs {
+		run.Repo = ctx.Repo.Repository
+		// the jobs are left out, they can be listed by the run
+		apiRun, err := convert.ToActionRun(ctx, run, nil)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+			return
+		}
+		apiRuns = append(apiRuns, apiRun)
+	}
+
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, apiRuns)
+}
+
+// GetActionRunBacklog returns how many runs of a repository are queued
+func GetActionRunBacklog(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/backlog repository repoGetActionRunBacklog
+	// ---
+	// summary: Get how many runs of a repository are queued, i.e. waiting for runners or blocked
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunBacklog"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	backlog := &api.ActionRunBacklog{Limit: max(setting.Actions.MaxQueuedRunsPerRepo, 0)}
+	for status, count := range map[actions_model.Status]*int64{
+		actions_model.StatusWaiting: &backlog.Waiting,
+		actions_model.StatusBlocked: &backlog.Blocked,
+	} {
+		var err error
+		*count, err = db.Count[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+			RepoID: ctx.Repo.Repository.ID,
+			Status: []actions_model.Status{status},
+		})
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "CountRuns", err)
+			return
+		}
+	}
+	backlog.Queued = backlog.Waiting + backlog.Blocked
+	ctx.JSON(http.StatusOK, backlog)
+}
+
+// ListActionRunCreationFailures lists the runs of a repository which weren't created though their workflows were expected to be triggered
+func ListActionRunCreationFailures(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/creation-failures repository repoListActionRunCreationFailures
+	// ---
+	// summary: List the runs of a repository which weren't created though their workflows were expected to be triggered, the latest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow
+	//   in: query
+	//   description: only the failures of the workflow, by its file name
+	//   type: string
+	// - name: reason
+	//   in: query
+	//   description: only the failures of the reason
+	//   type: string
+	//   enum: [invalid_workflow, quota_exceeded, actor_blocked, policy_rejected, internal_error]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunCreationFailureList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	reason := actions_model.RunCreationFailureReason(ctx.FormTrim("reason"))
+	if reason != "" && !reason.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid reason %q", reason))
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	failures, total, err := db.FindAndCount[actions_model.ActionRunCreationFailure](ctx, actions_model.FindRunCreationFailuresOptions{
+		ListOptions: listOptions,
+		RepoID:      ctx.Repo.Repository.ID,
+		WorkflowID:  ctx.FormTrim("workflow"),
+		Reason:      reason,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRunCreationFailures", err)
+		return
+	}
+
+	userIDs := make([]int64, 0, len(failures))
+	for _, f := range failures {
+		userIDs = append(userIDs, f.TriggerUserID)
+	}
+	users, err := user_model.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsersByIDs", err)
+		return
+	}
+	usersByID := make(map[int64]*user_model.User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+	res := make([]*api.ActionRunCreationFailure, 0, len(failures))
+	for _, f := range failures {
+		apiFailure := &api.ActionRunCreationFailure{
+			ID:         f.ID,
+			WorkflowID: f.WorkflowID,
+			Event:      string(f.Event),
+			Ref:        f.Ref,
+			CommitSHA:  f.CommitSHA,
+			Reason:     string(f.Reason),
+			Message:    f.Message,
+			Created:    f.Created.AsLocalTime(),
+		}
+		if u, ok := usersByID[f.TriggerUserID]; ok {
+			apiFailure.TriggerUser = convert.ToUser(ctx, u, ctx.Doer)
+		}
+		res = append(res, apiFailure)
+	}
+
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteActionRun deletes a run with its jobs, logs and artifacts
+func DeleteActionRun(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{run} repository repoDeleteActionRun
+	// ---
+	// summary: Delete a done run with its jobs, logs and artifacts, it's permitted to the administrators of the Actions of the repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.DeleteRun(ctx, run); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteRun", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// waitActionRunsChange loads the runs and responds 304 if their ETag matches the `If-None-Match` header of the request,
+// it waits for them to change before that if the `wait` query parameter is given. It returns whether to go on responding the runs.
+func waitActionRunsChange(ctx *context.APIContext, load func(ctx gocontext.Context) (etag string, err error)) bool {
+	changed := func(c gocontext.Context) (bool, error) {
+		etag, err := load(c)
+		if err != nil {
+			return false, err
+		}
+		ctx.Resp.Header().Set("ETag", etag)
+		return !httpcache.CheckIfNoneMatchIsValid(ctx.Req, etag), nil
+	}
+
+	ok, err := changed(ctx)
+	if err == nil && !ok {
+		if wait := ctx.FormInt("wait"); wait > 0 {
+			err = actions_service.WaitRunsChange(ctx, ctx.Repo.Repository.ID, time.Duration(wait)*time.Second, func(c gocontext.Context) (bool, error) {
+				ok, err = changed(c)
+				return ok, err
+			})
+		}
+	}
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadActionRuns", err)
+		return false
+	}
+	if !ok {
+		ctx.Status(http.StatusNotModified)
+		return false
+	}
+	return true
+}
+
+// ListActionRunJobs lists the jobs of a run
+func ListActionRunJobs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/jobs repository repoListActionRunJobs
+	// ---
+	// summary: List the jobs of a run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunJobList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+	apiRun, err := convert.ToActionRun(ctx, run, jobs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+		return
+	}
+	if err := addActionRunJobHints(ctx, run, jobs, apiRun); err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobHints", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiRun.Jobs)
+}
+
+// CancelActionRun cancels the jobs of a run which aren't done
+func CancelActionRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run}/cancel repository repoCancelActionRun
+	// ---
+	// summary: Cancel the jobs of a run which aren't done
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if run.IsExternal() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the run is reported by an external CI system")
+		return
+	}
+
+	if err := actions_model.CancelRun(ctx, run.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CancelRun", err)
+		return
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+	actions_service.CreateCommitStatus(ctx, jobs...)
+
+	run, err = actions_model.GetRunByID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		return
+	}
+	writeActionRun(ctx, http.StatusOK, run)
+}
+
+// DownloadActionRunJobLogs downloads the logs of the latest attempt of a job
+func DownloadActionRunJobLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/jobs/{job}/logs repository repoDownloadActionRunJobLogs
+	// ---
+	// summary: Download the logs of the latest attempt of a job, the private sections are redacted for the users who can't write Actions
+	// produces:
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: job
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the logs
+	//     schema:
+	//       type: string
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.NotFound()
+		return
+	}
+	job := getActionRunJobByParams(ctx, run)
+	if ctx.Written() {
+		return
+	}
+
+	if err := common.DownloadActionsRunJobLogs(ctx.Base, job, !ctx.Repo.CanWrite(unit_model.TypeActions)); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DownloadActionsRunJobLogs", err)
+		}
+	}
+}
+
+// GetActionRunJobLogsURL returns an expiring link to download the logs of the latest attempt of a job
+func GetActionRunJobLogsURL(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/jobs/{job}/logs/url repository repoGetActionRunJobLogsURL
+	// ---
+	// summary: Get a link to download the logs of the latest attempt of a job without authentication until it expires, the private sections are redacted for the users who can't write Actions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: job
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDownloadURL"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.NotFound()
+		return
+	}
+	job := getActionRunJobByParams(ctx, run)
+	if ctx.Written() {
+		return
+	}
+
+	u, err := actions_service.GetJobLogsDownloadURL(ctx, job, !ctx.Repo.CanWrite(unit_model.TypeActions))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetJobLogsDownloadURL", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionDownloadURL{URL: u.URL, Expires: u.Expires})
+}
+
+// maxStructuredLogLines is the default and the max number of the lines returned by GetActionRunJobStructuredLogs
+const maxStructuredLogLines = 1000
+
+// GetActionRunJobStructuredLogs returns a part of the structured logs of the latest attempt of a job
+func GetActionRunJobStructuredLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/jobs/{job}/logs/structured repository repoGetActionRunJobStructuredLogs
+	// ---
+	// summary: Get the logs of the latest attempt of a job parsed from the ANSI escape sequences and the workflow commands like "::group::", the private sections are redacted for the users who can't write Actions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: job
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: from
+	//   in: query
+	//   description: the 0-based index of the first line
+	//   type: integer
+	//   format: int64
+	// - name: limit
+	//   in: query
+	//   description: the max number of the lines, 1000 at most
+	//   type: integer
+	//   format: int64
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionJobStructuredLogs"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.NotFound()
+		return
+	}
+	job := getActionRunJobByParams(ctx, run)
+	if ctx.Written() {
+		return
+	}
+	if job.TaskID == 0 {
+		ctx.NotFound("job is not started")
+		return
+	}
+	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTaskByID", err)
+		return
+	}
+	task.Job = job
+
+	limit := ctx.FormInt64("limit")
+	if limit <= 0 || limit > maxStructuredLogLines {
+		limit = maxStructuredLogLines
+	}
+	lines, err := actions_service.ReadStructuredTaskLogs(ctx, task, max(ctx.FormInt64("from"), 0), limit)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ReadStructuredTaskLogs", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionJobStructuredLogs{
+		Lines:      convert.ToActionLogLines(lines, !ctx.Repo.CanWrite(unit_model.TypeActions)),
+		TotalLines: task.LogLength,
+		Complete:   task.LogInStorage,
+	})
+}
+
+// getActionRunJobByParams returns the job of the run by the ":job" parameter
+func getActionRunJobByParams(ctx *context.APIContext, run *actions_model.ActionRun) *actions_model.ActionRunJob {
+	job, err := actions_model.GetRunJobByID(ctx, ctx.ParamsInt64(":job"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunJobByID", err)
+		}
+		return nil
+	}
+	if job.RunID != run.ID {
+		ctx.NotFound()
+		return nil
+	}
+	job.Run = run
+	return job
+}
+
+// addActionRunJobHints adds the hints of the jobs of the run drawn from the history, if they are enabled
+func addActionRunJobHints(ctx *context.APIContext, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob, apiRun *api.ActionRun) error {
+	hints, err := actions_service.GetRunJobHints(ctx, run, jobs)
+	if err != nil {
+		return err
+	}
+	for _, apiJob := range apiRun.Jobs {
+		if h, ok := hints[apiJob.ID]; ok {
+			apiJob.Hints = &api.ActionRunJobHints{
+				SampledRuns:     h.SampledRuns,
+				FailureRate:     h.FailureRate(),
+				FlakyRuns:       h.Flaky,
+				Flaky:           h.IsFlaky(),
+				AverageDuration: int64(h.AverageDuration.Seconds()),
+			}
+		}
+	}
+	return nil
+}
+
+func getActionRunByParams(ctx *context.APIContext) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByIndex", err)
+		}
+		return nil
+	}
+	return run
+}
+
+// checkActionsTaskOfRun responds forbidden if the request is made with the token of a job of another run,
+// the runs only submit their own results
+func checkActionsTaskOfRun(ctx *context.APIContext, run *actions_model.ActionRun) {
+	if ctx.Doer.ID != user_model.ActionsUserID {
+		return
+	}
+	task, err := actions_model.GetTaskByID(ctx, ctx.Data["ActionsTaskID"].(int64))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTaskByID", err)
+		return
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadJob", err)
+		return
+	}
+	if task.Job.RunID != run.ID {
+		ctx.Error(http.StatusForbidden, "", "the token of the job could only submit to its own run")
+	}
+}
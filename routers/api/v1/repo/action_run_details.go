@@ -0,0 +1,150 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// actionRunDetailsIncludes are the data could be included in the details of a run
+var actionRunDetailsIncludes = container.SetOf("jobs", "steps", "artifacts", "comments", "summary")
+
+// GetActionRunDetails gets a run with the related data requested by the client in a single request
+func GetActionRunDetails(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/details repository repoGetActionRunDetails
+	// ---
+	// summary: Get a run with its jobs, the steps of the jobs, its artifacts, comments and summary in a single request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: include
+	//   in: query
+	//   description: "comma-separated data to include: jobs, steps, artifacts, comments and summary, the steps include the jobs too"
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunDetails"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	includes := make(container.Set[string])
+	for _, name := range strings.Split(ctx.FormTrim("include"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !actionRunDetailsIncludes.Contains(name) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown data to include: %s", name))
+			return
+		}
+		includes.Add(name)
+	}
+
+	run := getActionRunByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	run.Repo = ctx.Repo.Repository
+
+	var (
+		jobs []*actions_model.ActionRunJob
+		err  error
+	)
+	if includes.Contains("jobs") || includes.Contains("steps") {
+		if jobs, err = actions_model.GetRunJobsByRunID(ctx, run.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+			return
+		}
+	}
+	apiRun, err := convert.ToActionRun(ctx, run, jobs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+		return
+	}
+	details := &api.ActionRunDetails{Run: apiRun}
+
+	if includes.Contains("steps") {
+		taskIDs := make([]int64, 0, len(jobs))
+		for _, job := range jobs {
+			if job.TaskID > 0 {
+				taskIDs = append(taskIDs, job.TaskID)
+			}
+		}
+		stepsMap, err := actions_model.GetTaskStepsByTaskIDs(ctx, taskIDs)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetTaskStepsByTaskIDs", err)
+			return
+		}
+		// the jobs are converted in the same order
+		for i, job := range jobs {
+			apiRun.Jobs[i].Steps = convert.ToActionRunSteps(stepsMap[job.TaskID])
+		}
+	}
+
+	if includes.Contains("artifacts") {
+		if details.Artifacts, err = getActionRunArtifacts(ctx, run); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ListUploadedArtifactsMeta", err)
+			return
+		}
+	}
+
+	if includes.Contains("comments") {
+		comments, err := db.Find[actions_model.ActionRunComment](ctx, actions_model.FindRunCommentsOptions{
+			ListOptions: db.ListOptionsAll,
+			RunID:       run.ID,
+		})
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "FindRunComments", err)
+			return
+		}
+		if err := actions_model.RunCommentList(comments).LoadPosters(ctx); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadPosters", err)
+			return
+		}
+		details.Comments = make([]*api.ActionRunComment, 0, len(comments))
+		for _, c := range comments {
+			apiComment, err := convert.ToActionRunComment(ctx, c, ctx.Doer)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "ToActionRunComment", err)
+				return
+			}
+			details.Comments = append(details.Comments, apiComment)
+		}
+	}
+
+	if includes.Contains("summary") {
+		if details.Summary, err = getActionRunSummary(ctx, run); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetRunSummary", err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, details)
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListActionRunFilters lists the run filters saved by the user for a repository
+func ListActionRunFilters(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/run-filters repository repoListActionRunFilters
+	// ---
+	// summary: List the filters of the runs list of a repository saved by the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunFilterList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	filters, err := actions_model.GetRunFilters(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunFilters", err)
+		return
+	}
+	res := make([]*api.ActionRunFilter, 0, len(filters))
+	for _, f := range filters {
+		filter, err := convert.ToActionRunFilter(ctx, f)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionRunFilter", err)
+			return
+		}
+		res = append(res, filter)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionRunFilter gets a run filter saved by the user for a repository
+func GetActionRunFilter(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/run-filters/{name} repository repoGetActionRunFilter
+	// ---
+	// summary: Get a filter of the runs list of a repository saved by the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the run filter
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	filter, err := actions_model.GetRunFilterByName(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, ctx.Params(":name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunFilterByName", err)
+		}
+		return
+	}
+	writeActionRunFilter(ctx, http.StatusOK, filter)
+}
+
+// SaveActionRunFilter creates or replaces a run filter saved by the user for a repository
+func SaveActionRunFilter(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/run-filters/{name} repository repoSaveActionRunFilter
+	// ---
+	// summary: Create or replace a filter of the runs list of a repository saved by the authenticated user
+	// description: The runs list of the repository applies the filter with the `filter` query parameter.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the run filter
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SaveActionRunFilterOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunFilter"
+	//   "201":
+	//     "$ref": "#/responses/ActionRunFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SaveActionRunFilterOption)
+	name := ctx.Params(":name")
+	if len(name) > 255 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the name of a run filter can't be longer than 255 characters")
+		return
+	}
+
+	filter := &actions_model.ActionRunFilter{
+		UserID:     ctx.Doer.ID,
+		RepoID:     ctx.Repo.Repository.ID,
+		Name:       name,
+		WorkflowID: form.WorkflowID,
+		Branch:     form.Branch,
+	}
+	if form.Actor != "" {
+		actor, err := user_model.GetUserByName(ctx, form.Actor)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("actor %q doesn't exist", form.Actor))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		filter.ActorID = actor.ID
+	}
+	if form.Status != "" {
+		status, ok := actions_model.StatusFromString(form.Status)
+		if !ok || status.IsUnknown() {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid status: %s", form.Status))
+			return
+		}
+		filter.Status = status
+	}
+
+	created, err := actions_model.SaveRunFilter(ctx, filter)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SaveRunFilter", err)
+		}
+		return
+	}
+
+	filter, err = actions_model.GetRunFilterByName(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunFilterByName", err)
+		return
+	}
+	writeActionRunFilter(ctx, util.Iif(created, http.StatusCreated, http.StatusOK), filter)
+}
+
+// DeleteActionRunFilter deletes a run filter saved by the user for a repository
+func DeleteActionRunFilter(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/run-filters/{name} repository repoDeleteActionRunFilter
+	// ---
+	// summary: Delete a filter of the runs list of a repository saved by the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the run filter
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := actions_model.DeleteRunFilter(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, ctx.Params(":name")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteRunFilter", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func writeActionRunFilter(ctx *context.APIContext, status int, filter *actions_model.ActionRunFilter) {
+	apiFilter, err := convert.ToActionRunFilter(ctx, filter)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRunFilter", err)
+		return
+	}
+	ctx.JSON(status, apiFilter)
+}
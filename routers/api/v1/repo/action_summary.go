@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	api "code.gitea.io/gitea/modules/structs"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionRunSummary gets the summary of a run aggregated from the summaries written by its jobs
+func GetActionRunSummary(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/summary repository repoGetActionRunSummary
+	// ---
+	// summary: Get the summary of a run aggregated from the summaries written by its jobs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunSummary"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	summary, err := getActionRunSummary(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunSummary", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, summary)
+}
+
+// getActionRunSummary returns the summary of the run with its rendered HTML, it's empty for the users who can't see the details of the run
+func getActionRunSummary(ctx *context.APIContext, run *actions_model.ActionRun) (*api.ActionRunSummary, error) {
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		// anonymous users can only see the metadata of the runs
+		return &api.ActionRunSummary{}, nil
+	}
+
+	body, err := actions_service.GetRunSummary(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := markdown.RenderString(&markup.RenderContext{
+		Links: markup.Links{
+			Base: ctx.Repo.RepoLink,
+		},
+		Metas: ctx.Repo.Repository.ComposeMetas(ctx),
+		Ctx:   ctx,
+	}, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ActionRunSummary{
+		Body:     body,
+		BodyHTML: string(rendered),
+	}, nil
+}
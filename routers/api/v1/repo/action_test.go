@@ -0,0 +1,119 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/contexttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditActionsSettingsByActionsAdmin(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+	// repository 4 has no webhooks to be delivered when the units are updated
+	require.NoError(t, db.Insert(db.DefaultContext, &repo_model.RepoUnit{RepoID: 4, Type: unit_model.TypeActions, Config: &repo_model.ActionsConfig{}}))
+
+	editSettings := func(userID int64, isActionsAdmin bool, opts *api.EditRepoActionsSettingsOption) int {
+		ctx, resp := contexttest.MockAPIContext(t, "PATCH /api/v1/repos/user5/repo4/actions/settings")
+		contexttest.LoadUser(t, ctx, userID)
+		ctx.IsSigned = true
+		contexttest.LoadRepo(t, ctx, 4)
+		if isActionsAdmin {
+			// a member of a team with the read access to the code and the admin access to the Actions,
+			// see access.TestGetUserRepoPermissionOfActionsTeams
+			perm := access_model.Permission{AccessMode: perm_model.AccessModeRead}
+			perm.SetUnitsWithDefaultAccessMode(ctx.Repo.Repository.Units, perm_model.AccessModeRead)
+			perm.SetUnitAccessMode(unit_model.TypeActions, perm_model.AccessModeAdmin)
+			ctx.Repo.Permission = perm
+		}
+		web.SetForm(ctx, opts)
+		EditActionsSettings(ctx)
+		return resp.Code
+	}
+
+	// the Actions admins could change the settings
+	code := editSettings(4, true, &api.EditRepoActionsSettingsOption{RunRetentionDays: util.ToPointer[int64](30)})
+	assert.Equal(t, http.StatusOK, code)
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 4})
+	cfgUnit, err := repo.GetUnit(db.DefaultContext, unit_model.TypeActions)
+	require.NoError(t, err)
+	assert.EqualValues(t, 30, cfgUnit.ActionsConfig().RunRetentionDays)
+
+	// but only the repository admins could disable the unit
+	code = editSettings(4, true, &api.EditRepoActionsSettingsOption{Enabled: util.ToPointer(false)})
+	assert.Equal(t, http.StatusForbidden, code)
+	unittest.AssertExistsAndLoadBean(t, &repo_model.RepoUnit{RepoID: 4, Type: unit_model.TypeActions})
+
+	// it isn't forbidden if the unit isn't toggled
+	code = editSettings(4, true, &api.EditRepoActionsSettingsOption{Enabled: util.ToPointer(true)})
+	assert.Equal(t, http.StatusOK, code)
+
+	code = editSettings(5, false, &api.EditRepoActionsSettingsOption{Enabled: util.ToPointer(false)})
+	assert.Equal(t, http.StatusOK, code)
+	unittest.AssertNotExistsBean(t, &repo_model.RepoUnit{RepoID: 4, Type: unit_model.TypeActions})
+}
+
+func TestGetActionsLocalConfig(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+	defer test.MockVariableValue(&setting.Actions.PlatformImages, map[string]string{"ubuntu-latest": "node:20-bookworm"})()
+	_, err := actions_model.InsertVariable(db.DefaultContext, 0, 1, "STAGE", "prod")
+	require.NoError(t, err)
+	_, err = actions_model.InsertVariable(db.DefaultContext, 0, 1, "NOTES", "line 1\nline 2")
+	require.NoError(t, err)
+
+	ctx, resp := contexttest.MockAPIContext(t, "GET /api/v1/repos/user2/repo1/actions/local-config")
+	contexttest.LoadUser(t, ctx, 2)
+	contexttest.LoadRepo(t, ctx, 1)
+	ctx.Repo.GitRepo, err = gitrepo.OpenRepository(ctx, ctx.Repo.Repository)
+	require.NoError(t, err)
+	defer ctx.Repo.GitRepo.Close()
+
+	GetActionsLocalConfig(ctx)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var res api.ActionsLocalConfig
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+	assert.Equal(t, "master", res.Ref)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", res.CommitSHA)
+	// repository 1 has no workflows
+	assert.Empty(t, res.Workflows)
+	assert.Equal(t, map[string]string{"STAGE": "prod", "NOTES": "line 1\nline 2"}, res.Vars)
+	assert.Equal(t, map[string]string{"ubuntu-latest": "node:20-bookworm"}, res.Platforms)
+	// the variables with whitespace can't be passed to act in .actrc
+	assert.Equal(t, "--var STAGE=prod\n-P ubuntu-latest=node:20-bookworm\n", res.Actrc)
+
+	ctx, resp = contexttest.MockAPIContext(t, "GET /api/v1/repos/user2/repo1/actions/local-config?ref=not-exist")
+	contexttest.LoadUser(t, ctx, 2)
+	contexttest.LoadRepo(t, ctx, 1)
+	ctx.Repo.GitRepo, err = gitrepo.OpenRepository(ctx, ctx.Repo.Repository)
+	require.NoError(t, err)
+	defer ctx.Repo.GitRepo.Close()
+	GetActionsLocalConfig(ctx)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestBuildActrc(t *testing.T) {
+	assert.Empty(t, buildActrc(nil, nil))
+	assert.Equal(t, "--var A=1\n--var B=x=y\n-P gpu=cuda:12\n-P linux=debian:12\n", buildActrc(
+		map[string]string{"linux": "debian:12", "gpu": "cuda:12"},
+		map[string]string{"B": "x=y", "A": "1", "C": "with space", "D": "tab\there"},
+	))
+}
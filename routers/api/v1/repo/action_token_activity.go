@@ -0,0 +1,83 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListActionRunTokenActivities lists the requests made with the tokens of the tasks of a run
+func ListActionRunTokenActivities(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/token-activity repository repoListActionRunTokenActivities
+	// ---
+	// summary: List the API requests made with the tokens of the tasks of a run and their anomalies, the trail of the run for audits
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: number of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: anomalous
+	//   in: query
+	//   description: only list the requests with anomalies
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionTokenActivityList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRunByParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	activities, count, err := db.FindAndCount[actions_model.ActionTokenActivity](ctx, actions_model.FindTokenActivitiesOptions{
+		ListOptions:   utils.GetListOptions(ctx),
+		RunID:         run.ID,
+		AnomalousOnly: ctx.FormBool("anomalous"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindTokenActivities", err)
+		return
+	}
+
+	apiActivities := make([]*api.ActionTokenActivity, 0, len(activities))
+	for _, activity := range activities {
+		apiActivities = append(apiActivities, convert.ToActionTokenActivity(activity))
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiActivities)
+}
@@ -675,10 +675,14 @@ func apiReviewRequest(ctx *context.APIContext, opts api.PullReviewRequestOptions
 
 	reviewers := make([]*user_model.User, 0, len(opts.Reviewers))
 
-	permDoer, err := access_model.GetUserRepoPermission(ctx, pr.Issue.Repo, ctx.Doer)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
-		return
+	// the permission of the actions user is the one of the token
+	permDoer := ctx.Repo.Permission
+	if !ctx.Doer.IsActions() {
+		permDoer, err = access_model.GetUserRepoPermission(ctx, pr.Issue.Repo, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
 	}
 
 	for _, r := range opts.Reviewers {
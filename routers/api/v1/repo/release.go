@@ -4,6 +4,7 @@
 package repo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -13,6 +14,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
@@ -280,6 +282,68 @@ func CreateRelease(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToAPIRelease(ctx, ctx.Repo.Repository, rel))
 }
 
+// GenerateReleaseNotes generates the notes of a release
+func GenerateReleaseNotes(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/releases/generate-notes repository repoGenerateReleaseNotes
+	// ---
+	// summary: Generate the notes of a release from the pull requests merged since the previous tag
+	// description: The pull requests are grouped and excluded by the `changelog` of `.gitea/release.yml` or `.github/release.yml`
+	//   of the default branch like GitHub, and its `template` could customize the notes. The release isn't created.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/GenerateReleaseNotesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GeneratedReleaseNotes"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.GenerateReleaseNotesOption)
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Error(http.StatusUnprocessableEntity, "RepoIsEmpty", fmt.Errorf("repo is empty"))
+		return
+	}
+	notes, err := release_service.GenerateReleaseNotes(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, release_service.GenerateReleaseNotesOptions{
+		TagName:         form.TagName,
+		Target:          form.Target,
+		PreviousTagName: form.PreviousTagName,
+		ConfigPath:      form.ConfigurationFilePath,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GenerateReleaseNotes", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.GeneratedReleaseNotes{
+		Name:            notes.TagName,
+		Body:            notes.Body,
+		PreviousTagName: notes.PreviousTagName,
+	})
+}
+
 // EditRelease edit a release
 func EditRelease(ctx *context.APIContext) {
 	// swagger:operation PATCH /repos/{owner}/{repo}/releases/{id} repository repoEditRelease
@@ -361,17 +361,18 @@ func Generate(ctx *context.APIContext) {
 	}
 
 	opts := repo_service.GenerateRepoOptions{
-		Name:            form.Name,
-		DefaultBranch:   form.DefaultBranch,
-		Description:     form.Description,
-		Private:         form.Private,
-		GitContent:      form.GitContent,
-		Topics:          form.Topics,
-		GitHooks:        form.GitHooks,
-		Webhooks:        form.Webhooks,
-		Avatar:          form.Avatar,
-		IssueLabels:     form.Labels,
-		ProtectedBranch: form.ProtectedBranch,
+		Name:              form.Name,
+		DefaultBranch:     form.DefaultBranch,
+		Description:       form.Description,
+		Private:           form.Private,
+		GitContent:        form.GitContent,
+		Topics:            form.Topics,
+		GitHooks:          form.GitHooks,
+		Webhooks:          form.Webhooks,
+		Avatar:            form.Avatar,
+		IssueLabels:       form.Labels,
+		ProtectedBranch:   form.ProtectedBranch,
+		WorkflowSchedules: form.WorkflowSchedules,
 	}
 
 	if !opts.IsValid() {
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models"
 	repo_model "code.gitea.io/gitea/models/repo"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
@@ -223,6 +224,79 @@ func CreateTag(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToTag(ctx.Repo.Repository, tag))
 }
 
+// BumpTag creates the tag of the next semantic version of a repository
+func BumpTag(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/tags/bump repository repoBumpTag
+	// ---
+	// summary: Create the tag of the next semantic version of a repository
+	// description: The next version is computed from the latest semantic version tag by the `versioning` section of the
+	//   release configuration file. The concurrent bumps of a repository don't create the same version, and the protected
+	//   tags are respected.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/BumpTagOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/BumpedTag"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+	form := web.GetForm(ctx).(*api.BumpTagOption)
+
+	tagName, previousTagName, err := releaseservice.BumpVersionTag(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, releaseservice.BumpVersionTagOptions{
+		Bump:       form.Bump,
+		Target:     form.Target,
+		Message:    form.Message,
+		ConfigPath: form.ConfigurationFilePath,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case errors.Is(err, util.ErrNotExist):
+			ctx.Error(http.StatusNotFound, "", err)
+		case models.IsErrTagAlreadyExists(err):
+			ctx.Error(http.StatusConflict, "tag exist", err)
+		case models.IsErrProtectedTagName(err):
+			ctx.Error(http.StatusUnprocessableEntity, "BumpVersionTag", "user not allowed to create protected tag")
+		default:
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	tag, err := ctx.Repo.GitRepo.GetTag(tagName)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, &api.BumpedTag{
+		Tag:             convert.ToTag(ctx.Repo.Repository, tag),
+		PreviousTagName: previousTagName,
+	})
+}
+
 // DeleteTag delete a specific tag of in a repository by name
 func DeleteTag(ctx *context.APIContext) {
 	// swagger:operation DELETE /repos/{owner}/{repo}/tags/{tag} repository repoDeleteTag
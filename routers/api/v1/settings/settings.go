@@ -6,6 +6,7 @@ package settings
 import (
 	"net/http"
 
+	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/services/context"
@@ -83,3 +84,25 @@ func GetGeneralAttachmentSettings(ctx *context.APIContext) {
 		MaxSize:      setting.Attachment.MaxSize,
 	})
 }
+
+// GetActionsDefaultEnv returns the default environment variables of the jobs of Actions
+func GetActionsDefaultEnv(ctx *context.APIContext) {
+	// swagger:operation GET /settings/actions/default-env settings getActionsDefaultEnv
+	// ---
+	// summary: Get the default environment variables defined for every job of Actions
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionDefaultEnvVariableList"
+	vars := make([]*api.ActionDefaultEnvVariable, 0, len(actions_module.DefaultEnvVariables))
+	for _, v := range actions_module.DefaultEnvVariables {
+		vars = append(vars, &api.ActionDefaultEnvVariable{
+			Name:        v.Name,
+			Description: v.Description,
+			Source:      v.Source,
+			Value:       v.Expression(),
+		})
+	}
+	ctx.JSON(http.StatusOK, vars)
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"fmt"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/badge"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ParseBadgeTheme validates the badge theme of the options and converts it with the colors normalized, it responds 422 if it's invalid.
+// The theme is nil if it's empty, which means the default badges.
+func ParseBadgeTheme(ctx *context.APIContext, opt *api.ActionBadgeTheme) (*repo_model.ActionsBadgeTheme, bool) {
+	if opt.Style == "" && opt.LabelColor == "" && len(opt.Colors) == 0 && opt.Logo == "" {
+		return nil, true
+	}
+	theme := &repo_model.ActionsBadgeTheme{Style: opt.Style, Logo: opt.Logo}
+	if theme.Style != "" && !badge.IsValidStyle(theme.Style) {
+		ctx.Error(http.StatusUnprocessableEntity, "BadgeTheme", fmt.Errorf("invalid style %q of the badge theme", theme.Style))
+		return nil, false
+	}
+	if opt.LabelColor != "" {
+		c, ok := badge.NormalizeColor(opt.LabelColor)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "BadgeTheme", fmt.Errorf("invalid label color %q of the badge theme", opt.LabelColor))
+			return nil, false
+		}
+		theme.LabelColor = c
+	}
+	if len(opt.Colors) > 0 {
+		statuses := make(map[string]bool, len(badge.StatusColorMap))
+		for status := range badge.StatusColorMap {
+			statuses[status.String()] = true
+		}
+		theme.Colors = make(map[string]string, len(opt.Colors))
+		for status, color := range opt.Colors {
+			if !statuses[status] {
+				ctx.Error(http.StatusUnprocessableEntity, "BadgeTheme", fmt.Errorf("invalid status %q of the badge theme", status))
+				return nil, false
+			}
+			c, ok := badge.NormalizeColor(color)
+			if !ok {
+				ctx.Error(http.StatusUnprocessableEntity, "BadgeTheme", fmt.Errorf("invalid color %q of status %q of the badge theme", color, status))
+				return nil, false
+			}
+			theme.Colors[status] = c
+		}
+	}
+	if theme.Logo != "" && !badge.IsValidLogo(theme.Logo) {
+		ctx.Error(http.StatusUnprocessableEntity, "BadgeTheme", fmt.Errorf("the logo of the badge theme must be the base64 data URI of a SVG or PNG image of at most %d bytes", badge.MaxLogoSize))
+		return nil, false
+	}
+	return theme, true
+}
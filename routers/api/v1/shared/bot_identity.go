@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ParseBotIdentity validates the bot identity of the options and converts it, it responds 422 if it's invalid.
+// The identity is nil if its name is empty, which means the global actions user.
+func ParseBotIdentity(ctx *context.APIContext, opt *api.ActionBotIdentity) (*repo_model.ActionsBotIdentity, bool) {
+	name := strings.TrimSpace(opt.Name)
+	if name == "" {
+		return nil, true
+	}
+	if len(name) > 255 {
+		ctx.Error(http.StatusUnprocessableEntity, "BotIdentity", "the name of the bot identity is too long")
+		return nil, false
+	}
+	for _, email := range []string{opt.Email, opt.AvatarEmail} {
+		if email == "" {
+			continue
+		}
+		if err := user_model.ValidateEmailForAdmin(email); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "BotIdentity", fmt.Errorf("invalid email %q of the bot identity: %w", email, err))
+			return nil, false
+		}
+	}
+	return &repo_model.ActionsBotIdentity{
+		Name:        name,
+		Email:       opt.Email,
+		AvatarEmail: opt.AvatarEmail,
+	}, true
+}
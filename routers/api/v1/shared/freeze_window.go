@@ -0,0 +1,78 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListActionFreezeWindows responds the freeze windows of the owner, or of the instance if ownerID is 0
+func ListActionFreezeWindows(ctx *context.APIContext, ownerID int64) {
+	listOptions := utils.GetListOptions(ctx)
+	windows, total, err := db.FindAndCount[actions_model.ActionFreezeWindow](ctx, actions_model.FindFreezeWindowsOptions{
+		ListOptions: listOptions,
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindFreezeWindows", err)
+		return
+	}
+
+	res := make([]*api.ActionFreezeWindow, 0, len(windows))
+	for _, w := range windows {
+		res = append(res, convert.ToActionFreezeWindow(w))
+	}
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreateActionFreezeWindow creates a freeze window of the owner, or of the instance if ownerID is 0
+func CreateActionFreezeWindow(ctx *context.APIContext, ownerID int64) {
+	opts := web.GetForm(ctx).(*api.CreateActionFreezeWindowOption)
+	w := &actions_model.ActionFreezeWindow{
+		OwnerID:      ownerID,
+		Name:         opts.Name,
+		Reason:       opts.Reason,
+		Environments: opts.Environments,
+		Labels:       opts.Labels,
+		StartUnix:    timeutil.TimeStamp(opts.Start.Unix()),
+		EndUnix:      timeutil.TimeStamp(opts.End.Unix()),
+		CreatorID:    ctx.Doer.ID,
+	}
+	if err := actions_model.CreateFreezeWindow(ctx, w); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateFreezeWindow", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateFreezeWindow", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToActionFreezeWindow(w))
+}
+
+// DeleteActionFreezeWindow deletes a freeze window of the owner, or of the instance if ownerID is 0, the jobs it froze are released
+func DeleteActionFreezeWindow(ctx *context.APIContext, ownerID int64) {
+	if err := actions_service.DeleteFreezeWindow(ctx, ownerID, ctx.ParamsInt64(":id")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteFreezeWindow", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"net/http"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+const defaultStoppedTasksDays = 30
+
+// getStoppedTasksOptions returns the options to find the tasks of the owner, or of all repositories if ownerID is 0,
+// which have stopped in the period between the "since" and "before" query parameters, the last 30 days by default
+func getStoppedTasksOptions(ctx *context.APIContext, ownerID int64) (actions_model.FindTaskDurationsOptions, bool) {
+	before, since, err := context.GetQueryBeforeSince(ctx.Base)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return actions_model.FindTaskDurationsOptions{}, false
+	}
+	if before == 0 {
+		before = time.Now().Unix()
+	}
+	if since == 0 {
+		since = time.Unix(before, 0).AddDate(0, 0, -defaultStoppedTasksDays).Unix()
+	}
+	return actions_model.FindTaskDurationsOptions{
+		OwnerID:       ownerID,
+		StoppedSince:  timeutil.TimeStamp(since),
+		StoppedBefore: timeutil.TimeStamp(before),
+	}, true
+}
+
+// ListActionMinutesUsages responds the minutes used by the tasks of the repositories of the owner, or of all repositories if ownerID is 0,
+// which have stopped in the period between the "since" and "before" query parameters, the last 30 days by default.
+func ListActionMinutesUsages(ctx *context.APIContext, ownerID int64) {
+	opts, ok := getStoppedTasksOptions(ctx, ownerID)
+	if !ok {
+		return
+	}
+
+	usages, err := actions_service.GetMinutesUsages(db.WithReplica(ctx), opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMinutesUsages", err)
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(usages))
+	for _, usage := range usages {
+		repoIDs = append(repoIDs, usage.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	res := make([]*api.ActionMinutesUsage, 0, len(usages))
+	for _, usage := range usages {
+		repo, ok := repos[usage.RepoID]
+		if !ok {
+			continue
+		}
+		res = append(res, &api.ActionMinutesUsage{
+			Repository: &api.RepositoryMeta{
+				ID:       repo.ID,
+				Name:     repo.Name,
+				Owner:    repo.OwnerName,
+				FullName: repo.FullName(),
+			},
+			Minutes:         usage.Minutes,
+			WeightedMinutes: usage.WeightedMinutes,
+		})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionTaskErrorStats responds how many tasks of the repositories of the owner, or of all repositories if ownerID is 0,
+// have stopped in the period by the classes of their errors, the period is like ListActionMinutesUsages
+func GetActionTaskErrorStats(ctx *context.APIContext, ownerID int64) {
+	opts, ok := getStoppedTasksOptions(ctx, ownerID)
+	if !ok {
+		return
+	}
+
+	counts, err := actions_model.CountTasksByErrorClass(db.WithReplica(ctx), opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountTasksByErrorClass", err)
+		return
+	}
+
+	stats := &api.ActionTaskErrorStats{}
+	for _, count := range counts {
+		stats.Total += count.Count
+		switch count.ErrorClass {
+		case actions_model.TaskErrorClassInfrastructure:
+			stats.Infrastructure += count.Count
+		case actions_model.TaskErrorClassUser:
+			stats.User += count.Count
+		case actions_model.TaskErrorClassCancellation:
+			stats.Cancellation += count.Count
+		}
+	}
+	ctx.JSON(http.StatusOK, stats)
+}
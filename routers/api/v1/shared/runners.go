@@ -9,6 +9,7 @@ import (
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 )
 
@@ -30,3 +31,58 @@ func GetRegistrationToken(ctx *context.APIContext, ownerID, repoID int64) {
 
 	ctx.JSON(http.StatusOK, RegistrationToken{Token: token.Token})
 }
+
+// RunnerToken is the new token a runner authenticates with
+// swagger:response RunnerToken
+type RunnerToken struct {
+	Token string `json:"token"`
+}
+
+// getRunnerByParam returns the runner of the path parameter if it could be managed at the level of the owner or the repository
+func getRunnerByParam(ctx *context.APIContext, ownerID, repoID int64) *actions_model.ActionRunner {
+	runner, err := actions_model.GetRunnerByID(ctx, ctx.ParamsInt64(":runner_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	if !runner.Editable(ownerID, repoID) {
+		ctx.NotFound()
+		return nil
+	}
+	return runner
+}
+
+// RotateRunnerToken replaces the token of a runner and responds the new one
+func RotateRunnerToken(ctx *context.APIContext, ownerID, repoID int64) {
+	runner := getRunnerByParam(ctx, ownerID, repoID)
+	if ctx.Written() {
+		return
+	}
+
+	token, err := actions_service.RotateRunnerToken(ctx, runner)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RunnerToken{Token: token})
+}
+
+// RevokeRunner invalidates the token of a runner and fails the tasks it's running
+func RevokeRunner(ctx *context.APIContext, ownerID, repoID int64) {
+	runner := getRunnerByParam(ctx, ownerID, repoID)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.RevokeRunner(ctx, runner); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
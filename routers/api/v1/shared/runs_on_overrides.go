@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"fmt"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+
+	"github.com/gobwas/glob"
+)
+
+// ParseRunsOnOverrides validates the runs-on overrides of the options and converts them, it responds 422 if they're invalid
+func ParseRunsOnOverrides(ctx *context.APIContext, opts []*api.ActionRunsOnOverride) ([]*repo_model.ActionsRunsOnOverride, bool) {
+	overrides := make([]*repo_model.ActionsRunsOnOverride, 0, len(opts))
+	for _, o := range opts {
+		if o == nil || o.Job == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "RunsOnOverrides", "the job pattern of a runs-on override is required")
+			return nil, false
+		}
+		if _, err := glob.Compile(o.Job); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "RunsOnOverrides", fmt.Errorf("invalid job pattern %q: %w", o.Job, err))
+			return nil, false
+		}
+		if len(o.RunsOn) == 0 && len(o.Labels) == 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "RunsOnOverrides", fmt.Errorf("the runs-on override of %q replaces nothing", o.Job))
+			return nil, false
+		}
+		overrides = append(overrides, &repo_model.ActionsRunsOnOverride{
+			Job:    o.Job,
+			RunsOn: o.RunsOn,
+			Labels: o.Labels,
+		})
+	}
+	return overrides, true
+}
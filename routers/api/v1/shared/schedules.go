@@ -0,0 +1,80 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"net/http"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+const (
+	defaultScheduleFeedDays = 7
+	maxScheduleFeedDays     = 31
+)
+
+// ListActionSchedules responds the cron schedules of the workflows with their upcoming runs in the next "days" days,
+// as JSON, or as an iCalendar if asICalendar is true. Only the repositories whose Actions could be read by the doer are included.
+func ListActionSchedules(ctx *context.APIContext, opts actions_model.FindSpecOptions, calendarName string, asICalendar bool) {
+	days := ctx.FormInt("days")
+	if days <= 0 {
+		days = defaultScheduleFeedDays
+	} else if days > maxScheduleFeedDays {
+		days = maxScheduleFeedDays
+	}
+	now := time.Now()
+
+	schedules, err := actions_service.FindUpcomingSchedules(ctx, opts, now, now.AddDate(0, 0, days))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindUpcomingSchedules", err)
+		return
+	}
+
+	permissions := make(map[int64]access_model.Permission)
+	visible := make([]*actions_service.UpcomingSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		repo := schedule.Spec.Repo
+		permission, ok := permissions[repo.ID]
+		if !ok {
+			if permission, err = access_model.GetUserRepoPermission(ctx, repo, ctx.Doer); err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+				return
+			}
+			permissions[repo.ID] = permission
+		}
+		if permission.CanRead(unit.TypeActions) {
+			visible = append(visible, schedule)
+		}
+	}
+
+	if asICalendar {
+		ctx.Resp.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		ctx.Resp.WriteHeader(http.StatusOK)
+		if err := actions_service.WriteSchedulesICalendar(ctx.Resp, calendarName, visible, now); err != nil {
+			log.Error("WriteSchedulesICalendar: %v", err)
+		}
+		return
+	}
+
+	res := make([]*api.ActionSchedule, 0, len(visible))
+	for _, schedule := range visible {
+		spec := schedule.Spec
+		res = append(res, &api.ActionSchedule{
+			Repository: convert.ToRepo(ctx, spec.Repo, permissions[spec.RepoID]),
+			WorkflowID: spec.Schedule.WorkflowID,
+			Title:      spec.Schedule.Title,
+			Spec:       spec.Spec,
+			NextRuns:   schedule.Runs,
+		})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
@@ -62,3 +62,10 @@ type swaggerResponseLabelTemplateInfo struct {
 	// in:body
 	Body []api.LabelTemplate `json:"body"`
 }
+
+// ActionsStatus
+// swagger:response ActionsStatus
+type swaggerResponseActionsStatus struct {
+	// in:body
+	Body api.ActionsStatus `json:"body"`
+}
@@ -199,4 +199,79 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	UpdateVariableOption api.UpdateVariableOption
+
+	// in:body
+	CreateExternalRunOption api.CreateExternalRunOption
+
+	// in:body
+	UpdateExternalRunOption api.UpdateExternalRunOption
+
+	// in:body
+	EditRepoActionsSettingsOption api.EditRepoActionsSettingsOption
+
+	// in:body
+	ActionsConfigBundle api.ActionsConfigBundle
+
+	// in:body
+	EditOrgActionsSettingsOption api.EditOrgActionsSettingsOption
+
+	// in:body
+	CreateActionBlockedRefOption api.CreateActionBlockedRefOption
+
+	// in:body
+	AcknowledgeActionRunOption api.AcknowledgeActionRunOption
+
+	// in:body
+	CreateActionRunCommentOption api.CreateActionRunCommentOption
+
+	// in:body
+	EditActionRunCommentOption api.EditActionRunCommentOption
+
+	// in:body
+	CreateActionRunIssueOption api.CreateActionRunIssueOption
+
+	// in:body
+	BackfillActionRunsOption api.BackfillActionRunsOption
+
+	// in:body
+	SimulateActionTriggerOption api.SimulateActionTriggerOption
+
+	// in:body
+	SaveActionDispatchPresetOption api.SaveActionDispatchPresetOption
+
+	// in:body
+	SaveActionRunFilterOption api.SaveActionRunFilterOption
+
+	// in:body
+	RerunActionRunOption api.RerunActionRunOption
+
+	// in:body
+	SubmitActionDependencySnapshotOption api.SubmitActionDependencySnapshotOption
+
+	// in:body
+	SubmitActionLicenseScanOption api.SubmitActionLicenseScanOption
+
+	// in:body
+	GenerateReleaseNotesOption api.GenerateReleaseNotesOption
+
+	// in:body
+	BumpTagOption api.BumpTagOption
+
+	// in:body
+	ActionRunPackageOption api.ActionRunPackageOption
+
+	// in:body
+	SubmitActionEgressViolationsOption api.SubmitActionEgressViolationsOption
+
+	// in:body
+	SetActionRunnerTargetVersionOption api.SetActionRunnerTargetVersionOption
+
+	// in:body
+	EstimateActionDispatchOption api.EstimateActionDispatchOption
+
+	// in:body
+	CreateActionFreezeWindowOption api.CreateActionFreezeWindowOption
+
+	// in:body
+	FederatedActionStatusOption api.FederatedActionStatusOption
 }
@@ -41,3 +41,10 @@ type swaggerResponseOrganizationPermissions struct {
 	// in:body
 	Body api.OrganizationPermissions `json:"body"`
 }
+
+// OrgActionsSettings
+// swagger:response OrgActionsSettings
+type swaggerResponseOrgActionsSettings struct {
+	// in:body
+	Body api.OrgActionsSettings `json:"body"`
+}
@@ -63,6 +63,13 @@ type swaggerResponseTag struct {
 	Body api.Tag `json:"body"`
 }
 
+// BumpedTag
+// swagger:response BumpedTag
+type swaggerResponseBumpedTag struct {
+	// in:body
+	Body api.BumpedTag `json:"body"`
+}
+
 // AnnotatedTag
 // swagger:response AnnotatedTag
 type swaggerResponseAnnotatedTag struct {
@@ -126,6 +133,13 @@ type swaggerResponseReleaseList struct {
 	Body []api.Release `json:"body"`
 }
 
+// GeneratedReleaseNotes
+// swagger:response GeneratedReleaseNotes
+type swaggerResponseGeneratedReleaseNotes struct {
+	// in:body
+	Body api.GeneratedReleaseNotes `json:"body"`
+}
+
 // PullRequest
 // swagger:response PullRequest
 type swaggerResponsePullRequest struct {
@@ -422,6 +436,363 @@ type swaggerRepoTasksList struct {
 	Body api.ActionTaskResponse `json:"body"`
 }
 
+// RepoActionsSettings
+// swagger:response RepoActionsSettings
+type swaggerResponseRepoActionsSettings struct {
+	// in:body
+	Body api.RepoActionsSettings `json:"body"`
+}
+
+// ActionsLocalConfig
+// swagger:response ActionsLocalConfig
+type swaggerResponseActionsLocalConfig struct {
+	// in:body
+	Body api.ActionsLocalConfig `json:"body"`
+}
+
+// ActionScheduleList
+// swagger:response ActionScheduleList
+type swaggerResponseActionScheduleList struct {
+	// in:body
+	Body []api.ActionSchedule `json:"body"`
+}
+
+// ActionUsageList
+// swagger:response ActionUsageList
+type swaggerResponseActionUsageList struct {
+	// in:body
+	Body []api.ActionUsage `json:"body"`
+}
+
+// ActionMinutesUsageList
+// swagger:response ActionMinutesUsageList
+type swaggerResponseActionMinutesUsageList struct {
+	// in:body
+	Body []api.ActionMinutesUsage `json:"body"`
+}
+
+// ActionBackfilledEventList
+// swagger:response ActionBackfilledEventList
+type swaggerResponseActionBackfilledEventList struct {
+	// in:body
+	Body []api.ActionBackfilledEvent `json:"body"`
+}
+
+// ActionTriggerSimulation
+// swagger:response ActionTriggerSimulation
+type swaggerResponseActionTriggerSimulation struct {
+	// in:body
+	Body api.ActionTriggerSimulation `json:"body"`
+}
+
+// ActionRunReplay
+// swagger:response ActionRunReplay
+type swaggerResponseActionRunReplay struct {
+	// in:body
+	Body api.ActionRunReplay `json:"body"`
+}
+
+// ActionFreezeWindow
+// swagger:response ActionFreezeWindow
+type swaggerResponseActionFreezeWindow struct {
+	// in:body
+	Body api.ActionFreezeWindow `json:"body"`
+}
+
+// ActionFreezeWindowList
+// swagger:response ActionFreezeWindowList
+type swaggerResponseActionFreezeWindowList struct {
+	// in:body
+	Body []api.ActionFreezeWindow `json:"body"`
+}
+
+// ActionDispatchEstimate
+// swagger:response ActionDispatchEstimate
+type swaggerResponseActionDispatchEstimate struct {
+	// in:body
+	Body api.ActionDispatchEstimate `json:"body"`
+}
+
+// ActionDispatchPreset
+// swagger:response ActionDispatchPreset
+type swaggerResponseActionDispatchPreset struct {
+	// in:body
+	Body api.ActionDispatchPreset `json:"body"`
+}
+
+// ActionDispatchPresetList
+// swagger:response ActionDispatchPresetList
+type swaggerResponseActionDispatchPresetList struct {
+	// in:body
+	Body []api.ActionDispatchPreset `json:"body"`
+}
+
+// ActionRunFilter
+// swagger:response ActionRunFilter
+type swaggerResponseActionRunFilter struct {
+	// in:body
+	Body api.ActionRunFilter `json:"body"`
+}
+
+// ActionRunFilterList
+// swagger:response ActionRunFilterList
+type swaggerResponseActionRunFilterList struct {
+	// in:body
+	Body []api.ActionRunFilter `json:"body"`
+}
+
+// ActionTaskErrorStats
+// swagger:response ActionTaskErrorStats
+type swaggerResponseActionTaskErrorStats struct {
+	// in:body
+	Body api.ActionTaskErrorStats `json:"body"`
+}
+
+// ActionStorageHealthList
+// swagger:response ActionStorageHealthList
+type swaggerResponseActionStorageHealthList struct {
+	// in:body
+	Body []api.ActionStorageHealth `json:"body"`
+}
+
+// ActionRunnerUpdateRollout
+// swagger:response ActionRunnerUpdateRollout
+type swaggerResponseActionRunnerUpdateRollout struct {
+	// in:body
+	Body api.ActionRunnerUpdateRollout `json:"body"`
+}
+
+// ActionBlockedRef
+// swagger:response ActionBlockedRef
+type swaggerResponseActionBlockedRef struct {
+	// in:body
+	Body api.ActionBlockedRef `json:"body"`
+}
+
+// ActionBlockedRefList
+// swagger:response ActionBlockedRefList
+type swaggerResponseActionBlockedRefList struct {
+	// in:body
+	Body []api.ActionBlockedRef `json:"body"`
+}
+
+// ActionConfigSnapshot
+// swagger:response ActionConfigSnapshot
+type swaggerResponseActionConfigSnapshot struct {
+	// in:body
+	Body api.ActionConfigSnapshot `json:"body"`
+}
+
+// ActionDeadLetter
+// swagger:response ActionDeadLetter
+type swaggerResponseActionDeadLetter struct {
+	// in:body
+	Body api.ActionDeadLetter `json:"body"`
+}
+
+// ActionDeadLetterList
+// swagger:response ActionDeadLetterList
+type swaggerResponseActionDeadLetterList struct {
+	// in:body
+	Body []api.ActionDeadLetter `json:"body"`
+}
+
+// ActionOutboxEvent
+// swagger:response ActionOutboxEvent
+type swaggerResponseActionOutboxEvent struct {
+	// in:body
+	Body api.ActionOutboxEvent `json:"body"`
+}
+
+// ActionOutboxEventList
+// swagger:response ActionOutboxEventList
+type swaggerResponseActionOutboxEventList struct {
+	// in:body
+	Body []api.ActionOutboxEvent `json:"body"`
+}
+
+// ActionRunCreationFailureList
+// swagger:response ActionRunCreationFailureList
+type swaggerResponseActionRunCreationFailureList struct {
+	// in:body
+	Body []api.ActionRunCreationFailure `json:"body"`
+}
+
+// ActionCommitGating
+// swagger:response ActionCommitGating
+type swaggerResponseActionCommitGating struct {
+	// in:body
+	Body api.ActionCommitGating `json:"body"`
+}
+
+// ActionsConfigImport
+// swagger:response ActionsConfigImport
+type swaggerResponseActionsConfigImport struct {
+	// in:body
+	Body api.ActionsConfigImport `json:"body"`
+}
+
+// ActionWorkflowLintResult
+// swagger:response ActionWorkflowLintResult
+type swaggerResponseActionWorkflowLintResult struct {
+	// in:body
+	Body api.ActionWorkflowLintResult `json:"body"`
+}
+
+// ActionRun
+// swagger:response ActionRun
+type swaggerRepoActionRun struct {
+	// in:body
+	Body api.ActionRun `json:"body"`
+}
+
+// ActionRunList
+// swagger:response ActionRunList
+type swaggerResponseActionRunList struct {
+	// in:body
+	Body []api.ActionRun `json:"body"`
+}
+
+// ActionComponentList
+// swagger:response ActionComponentList
+type swaggerResponseActionComponentList struct {
+	// in:body
+	Body []api.ActionComponent `json:"body"`
+}
+
+// ActionRunJobList
+// swagger:response ActionRunJobList
+type swaggerResponseActionRunJobList struct {
+	// in:body
+	Body []api.ActionRunJob `json:"body"`
+}
+
+// ActionArtifactList
+// swagger:response ActionArtifactList
+type swaggerResponseActionArtifactList struct {
+	// in:body
+	Body []api.ActionArtifact `json:"body"`
+}
+
+// ActionDownloadURL
+// swagger:response ActionDownloadURL
+type swaggerResponseActionDownloadURL struct {
+	// in:body
+	Body api.ActionDownloadURL `json:"body"`
+}
+
+// ActionRunComment
+// swagger:response ActionRunComment
+type swaggerRepoActionRunComment struct {
+	// in:body
+	Body api.ActionRunComment `json:"body"`
+}
+
+// ActionRunCommentList
+// swagger:response ActionRunCommentList
+type swaggerRepoActionRunCommentList struct {
+	// in:body
+	Body []api.ActionRunComment `json:"body"`
+}
+
+// ActionCodeScanningAlertList
+// swagger:response ActionCodeScanningAlertList
+type swaggerRepoActionCodeScanningAlertList struct {
+	// in:body
+	Body []api.ActionCodeScanningAlert `json:"body"`
+}
+
+// ActionCodeScanningUpload
+// swagger:response ActionCodeScanningUpload
+type swaggerRepoActionCodeScanningUpload struct {
+	// in:body
+	Body api.ActionCodeScanningUpload `json:"body"`
+}
+
+// ActionCodeScanningComparison
+// swagger:response ActionCodeScanningComparison
+type swaggerRepoActionCodeScanningComparison struct {
+	// in:body
+	Body api.ActionCodeScanningComparison `json:"body"`
+}
+
+// ActionDependencySnapshot
+// swagger:response ActionDependencySnapshot
+type swaggerRepoActionDependencySnapshot struct {
+	// in:body
+	Body api.ActionDependencySnapshot `json:"body"`
+}
+
+// ActionDependencyReview
+// swagger:response ActionDependencyReview
+type swaggerRepoActionDependencyReview struct {
+	// in:body
+	Body api.ActionDependencyReview `json:"body"`
+}
+
+// ActionLicenseScan
+// swagger:response ActionLicenseScan
+type swaggerRepoActionLicenseScan struct {
+	// in:body
+	Body api.ActionLicenseScan `json:"body"`
+}
+
+// ActionLicenseScanList
+// swagger:response ActionLicenseScanList
+type swaggerRepoActionLicenseScanList struct {
+	// in:body
+	Body []api.ActionLicenseScan `json:"body"`
+}
+
+// ActionEgressViolationList
+// swagger:response ActionEgressViolationList
+type swaggerRepoActionEgressViolationList struct {
+	// in:body
+	Body []api.ActionEgressViolation `json:"body"`
+}
+
+// ActionPackageRetentionRemovalList
+// swagger:response ActionPackageRetentionRemovalList
+type swaggerRepoActionPackageRetentionRemovalList struct {
+	// in:body
+	Body []api.ActionPackageRetentionRemoval `json:"body"`
+}
+
+// ActionTokenActivityList
+// swagger:response ActionTokenActivityList
+type swaggerRepoActionTokenActivityList struct {
+	// in:body
+	Body []api.ActionTokenActivity `json:"body"`
+}
+
+// ActionRunSummary
+// swagger:response ActionRunSummary
+type swaggerRepoActionRunSummary struct {
+	// in:body
+	Body api.ActionRunSummary `json:"body"`
+}
+
+// ActionRunBacklog
+// swagger:response ActionRunBacklog
+type swaggerRepoActionRunBacklog struct {
+	// in:body
+	Body api.ActionRunBacklog `json:"body"`
+}
+
+// ActionJobStructuredLogs
+// swagger:response ActionJobStructuredLogs
+type swaggerRepoActionJobStructuredLogs struct {
+	// in:body
+	Body api.ActionJobStructuredLogs `json:"body"`
+}
+
+// ActionRunDetails
+// swagger:response ActionRunDetails
+type swaggerRepoActionRunDetails struct {
+	// in:body
+	Body api.ActionRunDetails `json:"body"`
+}
+
 // swagger:response Compare
 type swaggerCompare struct {
 	// in:body
@@ -32,3 +32,10 @@ type swaggerResponseGeneralAttachmentSettings struct {
 	// in:body
 	Body api.GeneralAttachmentSettings `json:"body"`
 }
+
+// ActionDefaultEnvVariableList
+// swagger:response ActionDefaultEnvVariableList
+type swaggerResponseActionDefaultEnvVariableList struct {
+	// in:body
+	Body []api.ActionDefaultEnvVariable `json:"body"`
+}
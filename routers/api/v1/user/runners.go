@@ -24,3 +24,47 @@ func GetRegistrationToken(ctx *context.APIContext) {
 
 	shared.GetRegistrationToken(ctx, ctx.Doer.ID, 0)
 }
+
+// RotateRunnerToken replaces the token of a user runner
+func RotateRunnerToken(ctx *context.APIContext) {
+	// swagger:operation POST /user/actions/runners/{runner_id}/rotate-token user userRotateRunnerToken
+	// ---
+	// summary: Replace the token of a user's runner, the old token stops working immediately
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RunnerToken"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RotateRunnerToken(ctx, ctx.Doer.ID, 0)
+}
+
+// RevokeRunner invalidates the token of a user runner and fails the tasks it's running
+func RevokeRunner(ctx *context.APIContext) {
+	// swagger:operation POST /user/actions/runners/{runner_id}/revoke user userRevokeRunner
+	// ---
+	// summary: Revoke a user's runner, its token is invalidated and the tasks it's running are failed
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RevokeRunner(ctx, ctx.Doer.ID, 0)
+}
@@ -160,6 +160,33 @@ func GetUserHeatmapData(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, heatmap)
 }
 
+// GetUserAutomationHeatmapData is the handler to get the heatmap of a user's automation activity
+func GetUserAutomationHeatmapData(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/heatmap/automation user userGetAutomationHeatmapData
+	// ---
+	// summary: Get the heatmap of a user's automation activity, the successful runs of the workflows triggered by the user of the events configured by the administrator
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user to get
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserHeatmapData"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	heatmap, err := activities_model.GetUserAutomationHeatmapData(ctx, ctx.ContextUser, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserAutomationHeatmapData", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, heatmap)
+}
+
 func ListUserActivityFeeds(ctx *context.APIContext) {
 	// swagger:operation GET /users/{username}/activities/feeds user userListActivityFeeds
 	// ---
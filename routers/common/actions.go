@@ -0,0 +1,135 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+)
+
+// DownloadActionsRunJobLogs serves the logs of the latest attempt of the job,
+// the private sections are redacted if redact is true, e.g. for the users who aren't maintainers.
+func DownloadActionsRunJobLogs(ctx *context.Base, job *actions_model.ActionRunJob, redact bool) error {
+	if job.TaskID == 0 {
+		return util.NewNotExistErrorf("job is not started")
+	}
+	if err := job.LoadRun(ctx); err != nil {
+		return err
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		return err
+	}
+	if task.LogExpired {
+		return util.NewNotExistErrorf("logs have been cleaned up")
+	}
+
+	reader, err := actions.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	opts := &context.ServeHeaderOptions{
+		Filename:           job.LogsFilename(task.ID),
+		ContentLength:      &task.LogSize,
+		ContentType:        "text/plain",
+		ContentTypeCharset: "utf-8",
+		Disposition:        "attachment",
+	}
+	if !redact {
+		ctx.ServeContent(reader, opts)
+		return nil
+	}
+
+	// the length of the redacted logs is unknown
+	task.Job = job
+	if err := task.LoadAttributes(ctx); err != nil {
+		return err
+	}
+	stepStarts := make(container.Set[int64])
+	for _, step := range actions.FullSteps(task) {
+		stepStarts.Add(step.LogIndex)
+	}
+	opts.ContentLength = nil
+	ctx.SetServeHeaders(opts)
+	if err := actions.RedactPrivateLogs(ctx.Resp, reader, stepStarts); err != nil {
+		log.Error("RedactPrivateLogs: %v", err)
+	}
+	return nil
+}
+
+// DownloadActionsArtifact serves the artifact of the run as a zip file
+func DownloadActionsArtifact(ctx *context.Base, runID int64, artifactName string) error {
+	artifacts, err := actions_model.GetUploadedArtifactFiles(ctx, runID, artifactName)
+	if err != nil {
+		return err
+	}
+
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.zip; filename*=UTF-8''%s.zip", url.PathEscape(artifactName), artifactName))
+
+	// Artifacts using the v4 backend are stored as a single combined zip file per artifact on the backend
+	// The v4 backend enshures ContentEncoding is set to "application/zip", which is not the case for the old backend
+	if len(artifacts) == 1 && artifacts[0].IsSingleZip() {
+		art := artifacts[0]
+		if setting.Actions.ArtifactStorage.MinioConfig.ServeDirect {
+			u, err := storage.ActionsArtifacts.URL(art.StoragePath, art.ArtifactPath)
+			if u != nil && err == nil {
+				ctx.Redirect(u.String())
+				return nil
+			}
+		}
+		f, err := storage.ActionsArtifacts.Open(art.StoragePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, _ = io.Copy(ctx.Resp, f)
+		return nil
+	}
+
+	// Artifacts using the v1-v3 backend are stored as multiple individual files per artifact on the backend
+	// Those need to be zipped for download
+	writer := zip.NewWriter(ctx.Resp)
+	defer writer.Close()
+	for _, art := range artifacts {
+		f, err := storage.ActionsArtifacts.Open(art.StoragePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var r io.Reader = f
+		if art.ContentEncoding == "gzip" {
+			gr, err := gzip.NewReader(f)
+			if err != nil {
+				return err
+			}
+			defer gr.Close()
+			r = gr
+		}
+
+		w, err := writer.Create(art.ArtifactPath)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -8,14 +8,17 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 )
 
@@ -64,6 +67,216 @@ func GenerateActionsRunnerToken(ctx *context.PrivateContext) {
 	ctx.PlainText(http.StatusOK, token.Token)
 }
 
+// BackfillActionRuns synthesizes push events for the existing tags and commits of a repository
+func BackfillActionRuns(ctx *context.PrivateContext) {
+	var opts private.BackfillActionRunsRequest
+	rd := ctx.Req.Body
+	defer rd.Close()
+
+	if err := json.NewDecoder(rd).Decode(&opts); err != nil {
+		log.Error("JSON Decode failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
+	ownerName, repoName, _ := strings.Cut(opts.Repo, "/")
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, private.Response{
+			UserMsg: fmt.Sprintf("repository %s: %v", opts.Repo, err),
+		})
+		return
+	}
+	doer, err := user_model.GetUserByName(ctx, opts.Doer)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, private.Response{
+			UserMsg: fmt.Sprintf("user %s: %v", opts.Doer, err),
+		})
+		return
+	}
+
+	events, err := actions_service.BackfillRuns(ctx, repo, doer, &actions_service.BackfillOptions{
+		Tags:   opts.Tags,
+		Branch: opts.Branch,
+		Since:  opts.Since,
+		Until:  opts.Until,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSON(http.StatusBadRequest, private.Response{
+				UserMsg: err.Error(),
+			})
+			return
+		}
+		log.Error("BackfillRuns failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
+	var sb strings.Builder
+	for _, event := range events {
+		fmt.Fprintf(&sb, "%s %s\n", event.CommitID, event.Ref)
+	}
+	fmt.Fprintf(&sb, "%d push events have been synthesized", len(events))
+	ctx.PlainText(http.StatusOK, sb.String())
+}
+
+// ReplayActionRuns replays the events of the runs of a repository and reports the differences of their jobs
+func ReplayActionRuns(ctx *context.PrivateContext) {
+	var opts private.ReplayActionRunsRequest
+	rd := ctx.Req.Body
+	defer rd.Close()
+
+	if err := json.NewDecoder(rd).Decode(&opts); err != nil {
+		log.Error("JSON Decode failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
+	ownerName, repoName, _ := strings.Cut(opts.Repo, "/")
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, private.Response{
+			UserMsg: fmt.Sprintf("repository %s: %v", opts.Repo, err),
+		})
+		return
+	}
+
+	var results []*actions_service.ReplayedRun
+	if opts.Run > 0 {
+		var run *actions_model.ActionRun
+		run, err = actions_model.GetRunByIndex(ctx, repo.ID, opts.Run)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, private.Response{
+				UserMsg: fmt.Sprintf("run %d: %v", opts.Run, err),
+			})
+			return
+		}
+		var result *actions_service.ReplayedRun
+		if result, err = actions_service.ReplayRunEvent(ctx, run); err == nil {
+			results = append(results, result)
+		}
+	} else {
+		results, err = actions_service.ReplayRunEvents(ctx, repo, opts.Limit)
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrNotExist) {
+			ctx.JSON(http.StatusBadRequest, private.Response{
+				UserMsg: err.Error(),
+			})
+			return
+		}
+		log.Error("ReplayRunEvent failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
+	var sb strings.Builder
+	changed := 0
+	for _, result := range results {
+		run := result.Run
+		fmt.Fprintf(&sb, "#%d %s (%s %s): ", run.Index, run.WorkflowID, run.Event, base.ShortSha(run.CommitSHA))
+		if result.Changed() {
+			changed++
+		}
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(&sb, "error: %s\n", result.Error)
+		case !result.Triggered:
+			sb.WriteString("not triggered\n")
+		case len(result.Diffs) == 0:
+			sb.WriteString("unchanged\n")
+		default:
+			fmt.Fprintf(&sb, "%d jobs differ\n", len(result.Diffs))
+		}
+		for _, diff := range result.Diffs {
+			fmt.Fprintf(&sb, "  %s %s", diff.Change, diff.Name)
+			if len(diff.Fields) > 0 {
+				fmt.Fprintf(&sb, ": %s", strings.Join(diff.Fields, ", "))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(&sb, "%d runs have been replayed, %d of them differ", len(results), changed)
+	ctx.PlainText(http.StatusOK, sb.String())
+}
+
+// RunActionsLoadTest benchmarks Actions with synthetic runs in a repository picked by fake runners, and reports how the phases performed
+func RunActionsLoadTest(ctx *context.PrivateContext) {
+	var opts private.ActionsLoadTestRequest
+	rd := ctx.Req.Body
+	defer rd.Close()
+
+	if err := json.NewDecoder(rd).Decode(&opts); err != nil {
+		log.Error("JSON Decode failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
+	ownerName, repoName, _ := strings.Cut(opts.Repo, "/")
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, private.Response{
+			UserMsg: fmt.Sprintf("repository %s: %v", opts.Repo, err),
+		})
+		return
+	}
+	doer, err := user_model.GetUserByName(ctx, opts.Doer)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, private.Response{
+			UserMsg: fmt.Sprintf("user %s: %v", opts.Doer, err),
+		})
+		return
+	}
+
+	result, err := actions_service.RunLoadTest(ctx, repo, doer, &actions_service.LoadTestOptions{
+		Runs:       opts.Runs,
+		JobsPerRun: opts.JobsPerRun,
+		Runners:    opts.Runners,
+		LogLines:   opts.LogLines,
+		Keep:       opts.Keep,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSON(http.StatusBadRequest, private.Response{
+				UserMsg: err.Error(),
+			})
+			return
+		}
+		log.Error("RunLoadTest failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-16s %8s %7s %10s %10s %10s %10s %10s\n", "phase", "count", "errors", "duration", "ops/s", "p50", "p95", "max")
+	for _, p := range result.Phases {
+		fmt.Fprintf(&sb, "%-16s %8d %7d %10s %10.1f %10s %10s %10s\n", p.Name, p.Count, p.Errors,
+			p.Duration.Round(time.Millisecond), p.Throughput(), p.P50.Round(time.Microsecond), p.P95.Round(time.Microsecond), p.Max.Round(time.Microsecond))
+	}
+	for _, e := range result.Errors {
+		fmt.Fprintf(&sb, "error of %s\n", e)
+	}
+	if opts.Keep {
+		fmt.Fprintf(&sb, "the synthetic runs and the fake runners with the label %s have been kept", result.Label)
+	} else {
+		fmt.Fprintf(&sb, "the synthetic runs and the fake runners with the label %s have been deleted", result.Label)
+	}
+	ctx.PlainText(http.StatusOK, sb.String())
+}
+
 func parseScope(ctx *context.PrivateContext, scope string) (ownerID, repoID int64, err error) {
 	ownerID = 0
 	repoID = 0
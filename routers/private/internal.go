@@ -79,6 +79,9 @@ func Routes() *web.Route {
 	r.Post("/mail/send", SendEmail)
 	r.Post("/restore_repo", RestoreRepo)
 	r.Post("/actions/generate_actions_runner_token", GenerateActionsRunnerToken)
+	r.Post("/actions/backfill", BackfillActionRuns)
+	r.Post("/actions/replay", ReplayActionRuns)
+	r.Post("/actions/load-test", RunActionsLoadTest)
 
 	return r
 }
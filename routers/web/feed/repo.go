@@ -4,10 +4,12 @@
 package feed
 
 import (
+	"sort"
 	"time"
 
 	activities_model "code.gitea.io/gitea/models/activities"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/context"
 
 	"github.com/gorilla/feeds"
@@ -39,5 +41,24 @@ func ShowRepoFeed(ctx *context.Context, repo *repo_model.Repository, formatType
 		return
 	}
 
+	// the runs are only listed in the feed of the recent activities, not of a specific date
+	if ctx.FormString("date") == "" {
+		runItems, err := repoRunsFeedItems(ctx, repo)
+		if err != nil {
+			ctx.ServerError("repoRunsFeedItems", err)
+			return
+		}
+		if len(runItems) > 0 {
+			limit := max(len(feed.Items), setting.UI.FeedPagingNum)
+			feed.Items = append(feed.Items, runItems...)
+			sort.SliceStable(feed.Items, func(i, j int) bool {
+				return feed.Items[i].Created.After(feed.Items[j].Created)
+			})
+			if len(feed.Items) > limit {
+				feed.Items = feed.Items[:limit]
+			}
+		}
+	}
+
 	writeFeed(ctx, feed, formatType)
 }
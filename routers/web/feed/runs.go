@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package feed
+
+import (
+	"fmt"
+	"html/template"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/context"
+
+	"github.com/gorilla/feeds"
+)
+
+// repoRunsFeedItems returns the feed items of the completed runs of the repository,
+// which runs are included is configured by the Actions settings of the repository.
+func repoRunsFeedItems(ctx *context.Context, repo *repo_model.Repository) ([]*feeds.Item, error) {
+	if !ctx.Repo.CanRead(unit.TypeActions) {
+		return nil, nil
+	}
+	actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cfg := actionsUnit.ActionsConfig()
+
+	opts := actions_model.FindRunOptions{
+		ListOptions: db.ListOptions{PageSize: setting.UI.FeedPagingNum},
+		RepoID:      repo.ID,
+	}
+	switch cfg.GetFeedRuns() {
+	case repo_model.ActionsFeedRunsAll:
+		opts.Status = []actions_model.Status{actions_model.StatusSuccess, actions_model.StatusFailure, actions_model.StatusCancelled}
+	case repo_model.ActionsFeedRunsFailure:
+		opts.Status = []actions_model.Status{actions_model.StatusFailure}
+	default:
+		return nil, nil
+	}
+	if cfg.FeedRunsDefaultBranchOnly {
+		opts.Ref = git.BranchPrefix + repo.DefaultBranch
+	}
+
+	runs, err := db.Find[actions_model.ActionRun](db.WithReplica(ctx), opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := actions_model.RunList(runs).LoadTriggerUser(ctx); err != nil {
+		return nil, err
+	}
+
+	items := make([]*feeds.Item, 0, len(runs))
+	for _, run := range runs {
+		run.Repo = repo
+		link := run.HTMLURL()
+
+		var key string
+		switch run.Status {
+		case actions_model.StatusSuccess:
+			key = "action.run_succeeded"
+		case actions_model.StatusFailure:
+			key = "action.run_failed"
+		default:
+			key = "action.run_cancelled"
+		}
+		title := ctx.Locale.Tr(key, link, run.Index, run.WorkflowID, run.PrettyRef(), repo.HTMLURL(), repo.FullName())
+
+		items = append(items, &feeds.Item{
+			Title:       string(title),
+			Link:        &feeds.Link{Href: link},
+			Description: template.HTMLEscapeString(run.Title),
+			IsPermaLink: "false",
+			Author: &feeds.Author{
+				Name:  run.TriggerUser.DisplayName(),
+				Email: run.TriggerUser.GetEmail(),
+			},
+			Id:      fmt.Sprintf("run-%d: %s", run.ID, link),
+			Created: run.Stopped.AsTime(),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package feed
+
+import (
+	"fmt"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/contexttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoRunsFeedItems(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	ctx, _ := contexttest.MockContext(t, "user2/repo1.rss")
+	contexttest.LoadUser(t, ctx, 2)
+	contexttest.LoadRepo(t, ctx, 1)
+	repo := ctx.Repo.Repository
+
+	for i, run := range []*actions_model.ActionRun{
+		{Ref: "refs/heads/master", Status: actions_model.StatusSuccess},
+		{Ref: "refs/heads/master", Status: actions_model.StatusFailure},
+		{Ref: "refs/heads/feature", Status: actions_model.StatusFailure},
+		{Ref: "refs/heads/master", Status: actions_model.StatusRunning},
+	} {
+		run.Index = int64(1001 + i)
+		run.Title = "run"
+		run.RepoID = repo.ID
+		run.OwnerID = repo.OwnerID
+		run.WorkflowID = "test.yml"
+		run.TriggerUserID = 2
+		run.Event = "push"
+		run.Stopped = timeutil.TimeStamp(1700000000 + i)
+		require.NoError(t, db.Insert(ctx, run))
+	}
+
+	setFeedRuns := func(feedRuns repo_model.ActionsFeedRuns, defaultBranchOnly bool) {
+		actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+		require.NoError(t, err)
+		cfg := actionsUnit.ActionsConfig()
+		cfg.FeedRuns = feedRuns
+		cfg.FeedRunsDefaultBranchOnly = defaultBranchOnly
+		require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+		repo.Units = nil
+	}
+	runLinks := func() []string {
+		items, err := repoRunsFeedItems(ctx, repo)
+		require.NoError(t, err)
+		links := make([]string, 0, len(items))
+		for _, item := range items {
+			links = append(links, item.Link.Href)
+		}
+		return links
+	}
+	link := func(index int) string {
+		return fmt.Sprintf("%s/actions/runs/%d", repo.HTMLURL(), index)
+	}
+
+	assert.Empty(t, runLinks())
+
+	setFeedRuns(repo_model.ActionsFeedRunsAll, false)
+	assert.Equal(t, []string{link(1003), link(1002), link(1001)}, runLinks())
+
+	setFeedRuns(repo_model.ActionsFeedRunsFailure, false)
+	assert.Equal(t, []string{link(1003), link(1002)}, runLinks())
+
+	setFeedRuns(repo_model.ActionsFeedRunsFailure, true)
+	assert.Equal(t, []string{link(1002)}, runLinks())
+}
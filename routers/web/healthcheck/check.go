@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 )
 
 type status string
@@ -75,6 +76,7 @@ func Check(w http.ResponseWriter, r *http.Request) {
 	if setting.InstallLock {
 		statuses = append(statuses, checkDatabase(r.Context(), rsp.Checks))
 		statuses = append(statuses, checkCache(rsp.Checks))
+		statuses = append(statuses, checkStorages(rsp.Checks)...)
 	}
 	for _, s := range statuses {
 		if s != pass {
@@ -134,6 +136,24 @@ func checkCache(checks checks) status {
 	return st.Status
 }
 
+// storages reports the latest health checks of the storages, they aren't checked on every request
+func checkStorages(checks checks) []status {
+	var statuses []status
+	for _, health := range storage.GetHealthStatuses() {
+		st := componentStatus{
+			Status: pass,
+			Time:   health.Checked.UTC().Format(time.RFC3339),
+		}
+		// the error isn't exposed since the endpoint is public, it's logged and visible to the admins
+		if !health.Healthy {
+			st.Status = fail
+		}
+		checks["storage:"+health.Name] = []componentStatus{st}
+		statuses = append(statuses, st.Status)
+	}
+	return statuses
+}
+
 func getCheckTime() string {
 	return time.Now().UTC().Format(time.RFC3339)
 }
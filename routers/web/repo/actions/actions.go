@@ -5,6 +5,7 @@ package actions
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -18,9 +19,11 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/repo"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
 
 	"github.com/nektos/act/pkg/model"
 )
@@ -31,8 +34,9 @@ const (
 )
 
 type Workflow struct {
-	Entry  git.TreeEntry
-	ErrMsg string
+	Entry   git.TreeEntry
+	ErrMsg  string
+	LintMsg string
 }
 
 // MustEnableActions check if actions are enabled in settings
@@ -86,8 +90,10 @@ func List(ctx *context.Context) {
 			return
 		}
 		allRunnerLabels := make(container.Set[string])
+		allRunnerOSes := make(container.Set[string])
 		for _, r := range runners {
 			allRunnerLabels.AddMultiple(r.AgentLabels...)
+			allRunnerOSes.AddMultiple(actions_model.LabelsOS(r.AgentLabels)...)
 		}
 
 		workflows = make([]Workflow, 0, len(entries))
@@ -98,6 +104,15 @@ func List(ctx *context.Context) {
 				ctx.ServerError("GetContentFromEntry", err)
 				return
 			}
+			var lintMsgs []string
+			for _, f := range actions.LintWorkflow(content) {
+				if f.Rule != actions.LintRuleInvalidSyntax {
+					lintMsgs = append(lintMsgs, fmt.Sprintf("%d:%d %s", f.Line, f.Column, f.Message))
+				}
+			}
+			if len(lintMsgs) > 0 {
+				workflow.LintMsg = ctx.Locale.TrString("actions.runs.deprecated_syntax_helper", strings.Join(lintMsgs, "; "))
+			}
 			wf, err := model.ReadWorkflow(bytes.NewReader(content))
 			if err != nil {
 				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.invalid_workflow_helper", err.Error())
@@ -112,6 +127,13 @@ func List(ctx *context.Context) {
 					hasJobWithoutNeeds = true
 				}
 				runsOnList := j.RunsOn()
+				if jobOS, err := actions_model.JobOS(runsOnList); err != nil {
+					workflow.ErrMsg = ctx.Locale.TrString("actions.runs.conflicting_os_helper", strings.Join(runsOnList, ", "))
+					break
+				} else if jobOS != "" && len(allRunnerOSes) > 0 && !allRunnerOSes.Contains(jobOS) {
+					workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_matching_os_runner_helper", jobOS)
+					break
+				}
 				for _, ro := range runsOnList {
 					if strings.Contains(ro, "${{") {
 						// Skip if it contains expressions.
@@ -145,21 +167,68 @@ func List(ctx *context.Context) {
 	workflow := ctx.FormString("workflow")
 	actorID := ctx.FormInt64("actor")
 	status := ctx.FormInt("status")
+	branch := ctx.FormString("branch")
+	component := ctx.FormString("component")
+
+	// a filter saved by the doer fills the filters which aren't given
+	filterName := ctx.FormString("filter")
+	if filterName != "" && ctx.IsSigned {
+		filter, err := actions_model.GetRunFilterByName(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, filterName)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				ctx.NotFound("GetRunFilterByName", err)
+			} else {
+				ctx.ServerError("GetRunFilterByName", err)
+			}
+			return
+		}
+		if workflow == "" {
+			workflow = filter.WorkflowID
+		}
+		if actorID == 0 {
+			actorID = filter.ActorID
+		}
+		if status == 0 {
+			status = int(filter.Status)
+		}
+		if branch == "" {
+			branch = filter.Branch
+		}
+	}
+	if ctx.IsSigned {
+		filters, err := actions_model.GetRunFilters(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID)
+		if err != nil {
+			ctx.ServerError("GetRunFilters", err)
+			return
+		}
+		ctx.Data["RunFilters"] = filters
+	}
+	ctx.Data["CurFilter"] = filterName
 	ctx.Data["CurWorkflow"] = workflow
+	ctx.Data["CurBranch"] = branch
+	ctx.Data["CurComponent"] = component
 
 	actionsConfig := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions).ActionsConfig()
 	ctx.Data["ActionsConfig"] = actionsConfig
 
-	if len(workflow) > 0 && ctx.Repo.IsAdmin() {
+	if len(workflow) > 0 && ctx.Repo.Permission.CanManageActions() {
 		ctx.Data["AllowDisableOrEnableWorkflow"] = true
 		ctx.Data["CurWorkflowDisabled"] = actionsConfig.IsWorkflowDisabled(workflow)
 	}
 
 	// if status or actor query param is not given to frontend href, (href="/<repoLink>/actions")
 	// they will be 0 by default, which indicates get all status or actors
+	// only the failures which haven't been acknowledged are listed when filtering by "acknowledged=false"
+	acknowledged := ctx.FormOptionalBool("acknowledged")
+	unacknowledged := acknowledged.Has() && !acknowledged.Value()
+	if unacknowledged {
+		status = int(actions_model.StatusFailure)
+	}
 	ctx.Data["CurActor"] = actorID
 	ctx.Data["CurStatus"] = status
-	if actorID > 0 || status > int(actions_model.StatusUnknown) {
+	ctx.Data["CurUnacknowledged"] = unacknowledged
+	ctx.Data["StatusFailure"] = int(actions_model.StatusFailure)
+	if actorID > 0 || status > int(actions_model.StatusUnknown) || branch != "" || component != "" {
 		ctx.Data["IsFiltered"] = true
 	}
 
@@ -171,14 +240,21 @@ func List(ctx *context.Context) {
 		RepoID:        ctx.Repo.Repository.ID,
 		WorkflowID:    workflow,
 		TriggerUserID: actorID,
+		Component:     component,
+	}
+	if branch != "" {
+		opts.Ref = git.RefNameFromBranch(branch).String()
 	}
 
 	// if status is not StatusUnknown, it means user has selected a status filter
 	if actions_model.Status(status) != actions_model.StatusUnknown {
 		opts.Status = []actions_model.Status{actions_model.Status(status)}
 	}
+	if unacknowledged {
+		opts.Acknowledged = optional.Some(false)
+	}
 
-	runs, total, err := db.FindAndCount[actions_model.ActionRun](ctx, opts)
+	runs, total, err := db.FindAndCount[actions_model.ActionRun](db.WithReplica(ctx), opts)
 	if err != nil {
 		ctx.ServerError("FindAndCount", err)
 		return
@@ -195,7 +271,7 @@ func List(ctx *context.Context) {
 
 	ctx.Data["Runs"] = runs
 
-	actors, err := actions_model.GetActors(ctx, ctx.Repo.Repository.ID)
+	actors, err := actions_model.GetActors(db.WithReplica(ctx), ctx.Repo.Repository.ID)
 	if err != nil {
 		ctx.ServerError("GetActors", err)
 		return
@@ -209,8 +285,20 @@ func List(ctx *context.Context) {
 	pager.AddParamString("workflow", workflow)
 	pager.AddParamString("actor", fmt.Sprint(actorID))
 	pager.AddParamString("status", fmt.Sprint(status))
+	if branch != "" {
+		pager.AddParamString("branch", branch)
+	}
+	if component != "" {
+		pager.AddParamString("component", component)
+	}
+	if unacknowledged {
+		pager.AddParamString("acknowledged", "false")
+	}
 	ctx.Data["Page"] = pager
 	ctx.Data["HasWorkflowsOrRuns"] = len(workflows) > 0 || len(runs) > 0
+	if len(workflows) == 0 && setting.Actions.DefaultWorkflowsMode == setting.DefaultWorkflowsModeSuggest {
+		ctx.Data["SuggestedWorkflows"] = repo_service.GetDefaultWorkflows(ctx.Repo.Repository)
+	}
 
 	ctx.HTML(http.StatusOK, tplListActions)
 }
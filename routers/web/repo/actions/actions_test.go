@@ -0,0 +1,72 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/http"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	context_module "code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/contexttest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireRepoActionsAdmin(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+	require.NoError(t, db.Insert(db.DefaultContext, &repo_model.RepoUnit{RepoID: 4, Type: unit_model.TypeActions, Config: &repo_model.ActionsConfig{}}))
+
+	// mockContext mocks the request of a member of a team with the read access to the code and the access to the Actions,
+	// see access.TestGetUserRepoPermissionOfActionsTeams
+	mockContext := func(reqPath string, actionsMode perm_model.AccessMode) (*context_module.Context, *http.Response) {
+		ctx, resp := contexttest.MockContext(t, reqPath)
+		contexttest.LoadUser(t, ctx, 4)
+		ctx.IsSigned = true
+		contexttest.LoadRepo(t, ctx, 4)
+		perm := access_model.Permission{AccessMode: perm_model.AccessModeRead}
+		perm.SetUnitsWithDefaultAccessMode(ctx.Repo.Repository.Units, perm_model.AccessModeRead)
+		perm.SetUnitAccessMode(unit_model.TypeActions, actionsMode)
+		ctx.Repo.Permission = perm
+		context_module.RequireRepoActionsAdmin()(ctx)
+		return ctx, resp.Result()
+	}
+
+	t.Run("WorkflowEnableDisable", func(t *testing.T) {
+		ctx, resp := mockContext("POST /user5/repo4/actions/disable?workflow=test.yml", perm_model.AccessModeRead)
+		assert.True(t, ctx.Written())
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		ctx, _ = mockContext("POST /user5/repo4/actions/disable?workflow=test.yml", perm_model.AccessModeAdmin)
+		require.False(t, ctx.Written())
+		DisableWorkflowFile(ctx)
+		cfgUnit := unittest.AssertExistsAndLoadBean(t, &repo_model.RepoUnit{RepoID: 4, Type: unit_model.TypeActions})
+		assert.True(t, cfgUnit.ActionsConfig().IsWorkflowDisabled("test.yml"))
+
+		ctx, _ = mockContext("POST /user5/repo4/actions/enable?workflow=test.yml", perm_model.AccessModeAdmin)
+		require.False(t, ctx.Written())
+		EnableWorkflowFile(ctx)
+		cfgUnit = unittest.AssertExistsAndLoadBean(t, &repo_model.RepoUnit{RepoID: 4, Type: unit_model.TypeActions})
+		assert.False(t, cfgUnit.ActionsConfig().IsWorkflowDisabled("test.yml"))
+	})
+
+	t.Run("DeleteRun", func(t *testing.T) {
+		ctx, resp := mockContext("POST /user5/repo4/actions/runs/187/delete", perm_model.AccessModeWrite)
+		assert.True(t, ctx.Written())
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		ctx, _ = mockContext("POST /user5/repo4/actions/runs/187/delete", perm_model.AccessModeAdmin)
+		require.False(t, ctx.Written())
+		ctx.SetParams("run", "187")
+		DeleteRun(ctx)
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{ID: 791})
+	})
+}
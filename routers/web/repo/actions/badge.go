@@ -7,10 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"path/filepath"
-	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/badge"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/context"
@@ -25,32 +25,110 @@ func GetWorkflowBadge(ctx *context.Context) {
 	branchRef := fmt.Sprintf("refs/heads/%s", branch)
 	event := ctx.Req.URL.Query().Get("event")
 
-	badge, err := getWorkflowBadge(ctx, workflowFile, branchRef, event)
-	if err != nil {
-		ctx.ServerError("GetWorkflowBadge", err)
+	cfg := &repo_model.ActionsConfig{}
+	if cfgUnit, err := ctx.Repo.Repository.GetUnit(ctx, unit.TypeActions); err == nil {
+		cfg = cfgUnit.ActionsConfig()
+	} else if !repo_model.IsErrUnitTypeNotExist(err) {
+		ctx.ServerError("GetUnit", err)
 		return
 	}
 
-	ctx.Data["Badge"] = badge
-	ctx.RespHeader().Set("Content-Type", "image/svg+xml")
-	ctx.HTML(http.StatusOK, "shared/actions/runner_badge")
+	run, err := actions_model.GetWorkflowLatestRun(ctx, ctx.Repo.Repository.ID, workflowFile, branchRef, event)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.ServerError("GetWorkflowLatestRun", err)
+		return
+	}
+	var status actions_model.Status
+	if run != nil {
+		status = run.Status
+	}
+	renderStatusBadge(ctx, cfg.GetBadgeLabel(workflowFile), status, run != nil)
 }
 
-func getWorkflowBadge(ctx *context.Context, workflowFile, branchName, event string) (badge.Badge, error) {
-	extension := filepath.Ext(workflowFile)
-	workflowName := strings.TrimSuffix(workflowFile, extension)
+// GetWorkflowJobBadge renders the badge of the latest status of a job of the workflow
+func GetWorkflowJobBadge(ctx *context.Context) {
+	workflowFile := ctx.Params("workflow_name")
+	jobName := ctx.Params("job_name")
+	branch := ctx.Req.URL.Query().Get("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+	branchRef := fmt.Sprintf("refs/heads/%s", branch)
+	event := ctx.Req.URL.Query().Get("event")
+
+	status, err := actions_model.GetWorkflowLatestJobStatus(ctx, ctx.Repo.Repository.ID, workflowFile, branchRef, event, jobName)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.ServerError("GetWorkflowLatestJobStatus", err)
+		return
+	}
+	renderStatusBadge(ctx, jobName, status, err == nil)
+}
 
-	run, err := actions_model.GetWorkflowLatestRun(ctx, ctx.Repo.Repository.ID, workflowFile, branchName, event)
+// GetComponentBadge renders the badge of the latest status of a component of the monorepo,
+// the component is given by the query since it could contain slashes
+func GetComponentBadge(ctx *context.Context) {
+	component := ctx.FormTrim("component")
+	if component == "" {
+		ctx.NotFound("GetComponentBadge", nil)
+		return
+	}
+	branch := ctx.Req.URL.Query().Get("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+	branchRef := fmt.Sprintf("refs/heads/%s", branch)
+	event := ctx.Req.URL.Query().Get("event")
+
+	status, err := actions_model.GetComponentLatestStatus(ctx, ctx.Repo.Repository.ID, component, branchRef, event)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.ServerError("GetComponentLatestStatus", err)
+		return
+	}
+	renderStatusBadge(ctx, component, status, err == nil)
+}
+
+// renderStatusBadge renders the badge of the status in the badge theme of the repository,
+// the label, style and colors could be overridden by the query like the badges of shields.io
+func renderStatusBadge(ctx *context.Context, label string, status actions_model.Status, hasStatus bool) {
+	theme, err := actions_model.GetBadgeTheme(ctx, ctx.Repo.Repository)
 	if err != nil {
-		if errors.Is(err, util.ErrNotExist) {
-			return badge.GenerateBadge(workflowName, "no status", badge.DefaultColor), nil
+		ctx.ServerError("GetBadgeTheme", err)
+		return
+	}
+	if theme == nil {
+		theme = &repo_model.ActionsBadgeTheme{}
+	}
+
+	message, color := "no status", badge.DefaultColor
+	if hasStatus {
+		message = "unknown status"
+		if c, ok := badge.StatusColorMap[status]; ok {
+			message, color = status.String(), c
+			if c, ok := badge.NormalizeColor(theme.Colors[message]); ok {
+				color = c
+			}
 		}
-		return badge.Badge{}, err
+	}
+	opts := badge.Options{
+		Style:      theme.Style,
+		LabelColor: theme.LabelColor,
+		Logo:       theme.Logo,
 	}
 
-	color, ok := badge.StatusColorMap[run.Status]
-	if !ok {
-		return badge.GenerateBadge(workflowName, "unknown status", badge.DefaultColor), nil
+	if v := ctx.FormTrim("label"); v != "" {
+		label = v
+	}
+	if v := ctx.FormTrim("style"); badge.IsValidStyle(v) {
+		opts.Style = v
 	}
-	return badge.GenerateBadge(workflowName, run.Status.String(), color), nil
+	if v := ctx.FormTrim("labelColor"); v != "" {
+		opts.LabelColor = v
+	}
+	if c, ok := badge.NormalizeColor(ctx.FormTrim("color")); ok {
+		color = c
+	}
+
+	ctx.Data["Badge"] = badge.GenerateStyledBadge(label, message, color, opts)
+	ctx.RespHeader().Set("Content-Type", "image/svg+xml")
+	ctx.HTML(http.StatusOK, "shared/actions/runner_badge")
 }
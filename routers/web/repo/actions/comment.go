@@ -0,0 +1,152 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	context_module "code.gitea.io/gitea/services/context"
+)
+
+type CommentsViewResponse struct {
+	Comments []*CommentsViewItem `json:"comments"`
+	CanPost  bool                `json:"canPost"`
+}
+
+type CommentsViewItem struct {
+	ID        int64    `json:"id"`
+	JobID     int64    `json:"jobID"`
+	JobName   string   `json:"jobName"` // empty if the comment is about the whole run
+	Poster    ViewUser `json:"poster"`
+	Content   string   `json:"content"` // rendered HTML
+	Created   int64    `json:"created"`
+	CanDelete bool     `json:"canDelete"`
+}
+
+// CommentsView lists the comments of a run
+func CommentsView(ctx *context_module.Context) {
+	run := getRunByIndexForComments(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	comments, err := db.Find[actions_model.ActionRunComment](ctx, actions_model.FindRunCommentsOptions{RunID: run.ID})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := actions_model.RunCommentList(comments).LoadPosters(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	jobNames := make(map[int64]string, len(jobs))
+	for _, job := range jobs {
+		jobNames[job.ID] = job.Name
+	}
+
+	resp := &CommentsViewResponse{
+		Comments: make([]*CommentsViewItem, 0, len(comments)),
+		CanPost:  ctx.IsSigned,
+	}
+	canWrite := ctx.Repo.CanWrite(unit.TypeActions)
+	for _, c := range comments {
+		rendered, err := markdown.RenderString(&markup.RenderContext{
+			Links: markup.Links{
+				Base: ctx.Repo.RepoLink,
+			},
+			Metas: ctx.Repo.Repository.ComposeMetas(ctx),
+			Ctx:   ctx,
+		}, c.Content)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
+		resp.Comments = append(resp.Comments, &CommentsViewItem{
+			ID:      c.ID,
+			JobID:   c.JobID,
+			JobName: jobNames[c.JobID],
+			Poster: ViewUser{
+				DisplayName: c.Poster.GetDisplayName(),
+				Link:        c.Poster.HomeLink(),
+			},
+			Content:   string(rendered),
+			Created:   int64(c.Created),
+			CanDelete: actions_service.CanModifyRunComment(ctx.Doer, c, canWrite),
+		})
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// CommentsPost creates a comment on a run, or on a job of the run if "job_id" is provided
+func CommentsPost(ctx *context_module.Context) {
+	run := getRunByIndexForComments(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if _, err := actions_service.CreateRunComment(ctx, ctx.Doer, run, ctx.FormInt64("job_id"), ctx.FormString("content")); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSONError(err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+// CommentDelete deletes a comment of a run, only the poster and the users who can write Actions are allowed
+func CommentDelete(ctx *context_module.Context) {
+	run := getRunByIndexForComments(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	comment, err := actions_model.GetRunCommentByID(ctx, run.ID, ctx.ParamsInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !actions_service.CanModifyRunComment(ctx.Doer, comment, ctx.Repo.CanWrite(unit.TypeActions)) {
+		ctx.Error(http.StatusForbidden, "no permission")
+		return
+	}
+
+	if err := actions_model.DeleteRunComment(ctx, comment.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+func getRunByIndexForComments(ctx *context_module.Context) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return nil
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return nil
+	}
+	run.Repo = ctx.Repo.Repository
+	return run
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	context_module "code.gitea.io/gitea/services/context"
+)
+
+// getRunIssueLink returns the link of the issue opened for the failure of the run, or empty if there isn't one
+func getRunIssueLink(ctx *context_module.Context, run *actions_model.ActionRun) (string, error) {
+	ri, err := actions_model.GetRunIssueByRunID(ctx, run.ID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	issue, err := issues_model.GetIssueByID(ctx, ri.IssueID)
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	issue.Repo = ctx.Repo.Repository
+	return issue.Link(), nil
+}
+
+// OpenIssue opens an issue to track the failure of a run, and redirects to the issue
+func OpenIssue(ctx *context_module.Context) {
+	if !ctx.Repo.CanRead(unit.TypeIssues) {
+		ctx.Error(http.StatusForbidden, "no permission to open issues")
+		return
+	}
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	issue, err := actions_service.CreateIssueFromRun(ctx, ctx.Doer, run, ctx.FormBool("auto_close"))
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.JSONError(err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSONRedirect(issue.Link())
+}
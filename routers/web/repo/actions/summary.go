@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	context_module "code.gitea.io/gitea/services/context"
+)
+
+type SummaryViewResponse struct {
+	Summary string `json:"summary"` // rendered HTML, empty if no job has written a summary
+}
+
+// SummaryView renders the summary of a run aggregated from the summaries written by its jobs
+func SummaryView(ctx *context_module.Context) {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := &SummaryViewResponse{}
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.JSON(http.StatusOK, resp)
+		return
+	}
+
+	summary, err := actions_service.GetRunSummary(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if summary != "" {
+		rendered, err := markdown.RenderString(&markup.RenderContext{
+			Links: markup.Links{
+				Base: ctx.Repo.RepoLink,
+			},
+			Metas: ctx.Repo.Repository.ComposeMetas(ctx),
+			Ctx:   ctx,
+		}, summary)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
+		resp.Summary = string(rendered)
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
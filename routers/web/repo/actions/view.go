@@ -4,12 +4,9 @@
 package actions
 
 import (
-	"archive/zip"
-	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -17,20 +14,19 @@ import (
 	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/base"
-	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/modules/storage"
-	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/common"
 	actions_service "code.gitea.io/gitea/services/actions"
 	context_module "code.gitea.io/gitea/services/context"
-
-	"xorm.io/builder"
 )
 
 func View(ctx *context_module.Context) {
@@ -41,10 +37,17 @@ func View(ctx *context_module.Context) {
 	ctx.Data["JobIndex"] = jobIndex
 	ctx.Data["ActionsURL"] = ctx.Repo.RepoLink + "/actions"
 
-	if getRunJobs(ctx, runIndex, jobIndex); ctx.Written() {
+	current, _ := getRunJobs(ctx, runIndex, jobIndex)
+	if ctx.Written() {
 		return
 	}
 
+	if ctx.IsSigned {
+		if err := activities_model.SetActionRunReadBy(ctx, current.RunID, ctx.Doer.ID); err != nil {
+			log.Error("SetActionRunReadBy: %v", err)
+		}
+	}
+
 	ctx.HTML(http.StatusOK, tplViewActions)
 }
 
@@ -66,12 +69,19 @@ type ViewResponse struct {
 			CanApprove        bool       `json:"canApprove"` // the run needs an approval and the doer has permission to approve
 			CanRerun          bool       `json:"canRerun"`
 			CanDeleteArtifact bool       `json:"canDeleteArtifact"`
+			CanAcknowledge    bool       `json:"canAcknowledge"`    // the run has failed and the doer has permission to acknowledge it
+			CanOpenIssue      bool       `json:"canOpenIssue"`      // the run has failed and the doer has permission to open an issue for it
+			CanRedeliverEvent bool       `json:"canRedeliverEvent"` // the run is triggered by an event and the doer has permission to deliver it again
+			CanDelete         bool       `json:"canDelete"`         // the run is done and the doer administers the Actions of the repository
+			IssueLink         string     `json:"issueLink"`         // the link of the issue opened for the failure, empty if there isn't one
 			Done              bool       `json:"done"`
 			WorkflowID        string     `json:"workflowID"`
 			WorkflowLink      string     `json:"workflowLink"`
 			IsSchedule        bool       `json:"isSchedule"`
 			Jobs              []*ViewJob `json:"jobs"`
 			Commit            ViewCommit `json:"commit"`
+			// Acknowledgement is nil if the failure of the run hasn't been acknowledged
+			Acknowledgement *ViewAcknowledgement `json:"acknowledgement"`
 		} `json:"run"`
 		CurrentJob struct {
 			Title  string         `json:"title"`
@@ -89,7 +99,15 @@ type ViewJob struct {
 	Name     string `json:"name"`
 	Status   string `json:"status"`
 	CanRerun bool   `json:"canRerun"`
-	Duration string `json:"duration"`
+	// CanRerunOnSameRunner means the job has been executed by a runner, so it can be re-run on the same runner
+	CanRerunOnSameRunner bool   `json:"canRerunOnSameRunner"`
+	Duration             string `json:"duration"`
+}
+
+type ViewAcknowledgement struct {
+	User    ViewUser `json:"user"`
+	Comment string   `json:"comment"`
+	Time    int64    `json:"time"`
 }
 
 type ViewCommit struct {
@@ -133,7 +151,7 @@ func ViewPost(ctx *context_module.Context) {
 	runIndex := ctx.ParamsInt64("run")
 	jobIndex := ctx.ParamsInt64("job")
 
-	current, jobs := getRunJobs(ctx, runIndex, jobIndex)
+	current, jobs := getRunJobsCached(ctx, runIndex, jobIndex)
 	if ctx.Written() {
 		return
 	}
@@ -147,10 +165,14 @@ func ViewPost(ctx *context_module.Context) {
 
 	resp.State.Run.Title = run.Title
 	resp.State.Run.Link = run.Link()
-	resp.State.Run.CanCancel = !run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanApprove = run.NeedApproval && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanRerun = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanCancel = !run.Status.IsDone() && !run.IsExternal() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanApprove = run.NeedApproval && !run.IsExternal() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanRerun = run.Status.IsDone() && !run.IsExternal() && ctx.Repo.CanWrite(unit.TypeActions)
 	resp.State.Run.CanDeleteArtifact = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanAcknowledge = run.CanBeAcknowledged() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanOpenIssue = run.Status == actions_model.StatusFailure && ctx.Repo.CanWrite(unit.TypeActions) && ctx.Repo.CanRead(unit.TypeIssues)
+	resp.State.Run.CanRedeliverEvent = run.CanRedeliverEvent() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanDelete = run.Status.IsDone() && ctx.Repo.Permission.CanManageActions()
 	resp.State.Run.Done = run.Status.IsDone()
 	resp.State.Run.WorkflowID = run.WorkflowID
 	resp.State.Run.WorkflowLink = run.WorkflowLink()
@@ -158,12 +180,14 @@ func ViewPost(ctx *context_module.Context) {
 	resp.State.Run.Jobs = make([]*ViewJob, 0, len(jobs)) // marshal to '[]' instead fo 'null' in json
 	resp.State.Run.Status = run.Status.String()
 	for _, v := range jobs {
+		canRerun := v.Status.IsDone() && !v.IsExternal && ctx.Repo.CanWrite(unit.TypeActions)
 		resp.State.Run.Jobs = append(resp.State.Run.Jobs, &ViewJob{
-			ID:       v.ID,
-			Name:     v.Name,
-			Status:   v.Status.String(),
-			CanRerun: v.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions),
-			Duration: v.Duration().String(),
+			ID:                   v.ID,
+			Name:                 v.Name,
+			Status:               v.Status.String(),
+			CanRerun:             canRerun,
+			CanRerunOnSameRunner: canRerun && v.TaskID != 0,
+			Duration:             v.Duration().String(),
 		})
 	}
 
@@ -182,6 +206,34 @@ func ViewPost(ctx *context_module.Context) {
 		Branch:   branch,
 	}
 
+	if run.IsAcknowledged() {
+		acknowledger, err := user_model.GetPossibleUserByID(ctx, run.AcknowledgedBy)
+		if err != nil {
+			if !errors.Is(err, util.ErrNotExist) {
+				ctx.Error(http.StatusInternalServerError, err.Error())
+				return
+			}
+			acknowledger = user_model.NewGhostUser()
+		}
+		resp.State.Run.Acknowledgement = &ViewAcknowledgement{
+			User: ViewUser{
+				DisplayName: acknowledger.GetDisplayName(),
+				Link:        acknowledger.HomeLink(),
+			},
+			Comment: run.AcknowledgedComment,
+			Time:    int64(run.Acknowledged),
+		}
+	}
+
+	if resp.State.Run.CanOpenIssue {
+		issueLink, err := getRunIssueLink(ctx, run)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
+		resp.State.Run.IssueLink = issueLink
+	}
+
 	var task *actions_model.ActionTask
 	if current.TaskID > 0 {
 		var err error
@@ -191,6 +243,10 @@ func ViewPost(ctx *context_module.Context) {
 			return
 		}
 		task.Job = current
+		if task.Steps, err = actions_model.GetTaskStepsCached(ctx, task); err != nil {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
 		if err := task.LoadAttributes(ctx); err != nil {
 			ctx.Error(http.StatusInternalServerError, err.Error())
 			return
@@ -199,13 +255,35 @@ func ViewPost(ctx *context_module.Context) {
 
 	resp.State.CurrentJob.Title = current.Name
 	resp.State.CurrentJob.Detail = current.Status.LocaleString(ctx.Locale)
-	if run.NeedApproval {
+	if len(run.MissingRequirements) > 0 {
+		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.runs.missing_requirements_desc", strings.Join(run.MissingRequirements, ", "))
+	} else if run.NeedApproval {
 		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.need_approval_desc")
+	} else if run.IsExternal() {
+		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.runs.external_desc", run.ExternalSystem)
+	} else if current.PreflightError != "" {
+		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.runs.preflight_failed", current.PreflightError)
+	} else if held, err := actions_model.IsRunHeldByConcurrency(ctx, run); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	} else if held {
+		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.runs.concurrency_pending_desc", run.ConcurrencyGroup)
+	} else if w, err := actions_model.GetFreezeWindowOfJob(ctx, current); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	} else if w != nil {
+		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.runs.frozen_desc", w.Name, w.EndUnix.AsLocalTime().Format(time.RFC3339))
+		if w.Reason != "" {
+			resp.State.CurrentJob.Detail += " " + w.Reason
+		}
 	}
 	resp.State.CurrentJob.Steps = make([]*ViewJobStep, 0) // marshal to '[]' instead fo 'null' in json
 	resp.Logs.StepsLog = make([]*ViewStepLog, 0)          // marshal to '[]' instead fo 'null' in json
 	if task != nil {
 		steps := actions.FullSteps(task)
+		canViewDetails := actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer)
+		// the private sections of the logs are only visible to the maintainers
+		canViewPrivate := ctx.Repo.CanWrite(unit.TypeActions)
 
 		for _, v := range steps {
 			resp.State.CurrentJob.Steps = append(resp.State.CurrentJob.Steps, &ViewJobStep{
@@ -222,6 +300,27 @@ func ViewPost(ctx *context_module.Context) {
 
 			step := steps[cursor.Step]
 
+			if !canViewDetails || task.LogExpired {
+				// the logs are hidden from anonymous users or have been removed by the log retention, show a message instead of the logs once
+				if cursor.Cursor == 0 {
+					message := ctx.Locale.TrString("actions.runs.expire_log_message")
+					if !canViewDetails {
+						message = ctx.Locale.TrString("actions.runs.anonymous_log_message")
+					}
+					resp.Logs.StepsLog = append(resp.Logs.StepsLog, &ViewStepLog{
+						Step:   cursor.Step,
+						Cursor: 1,
+						Lines: []*ViewStepLogLine{{
+							Index:     1,
+							Message:   message,
+							Timestamp: float64(task.Updated.AsTime().UnixNano()) / float64(time.Second),
+						}},
+						Started: int64(step.Started),
+					})
+				}
+				continue
+			}
+
 			logLines := make([]*ViewStepLogLine, 0) // marshal to '[]' instead fo 'null' in json
 
 			index := step.LogIndex + cursor.Cursor
@@ -237,12 +336,24 @@ func ViewPost(ctx *context_module.Context) {
 			if validCursor {
 				length := step.LogLength - cursor.Cursor
 				offset := task.LogIndexes[index]
+				if !canViewPrivate {
+					// read from the start of the step to know whether the cursor is in a private section
+					length = step.LogLength
+					offset = task.LogIndexes[step.LogIndex]
+				}
 				var err error
 				logRows, err := actions.ReadLogs(ctx, task.LogInStorage, task.LogFilename, offset, length)
 				if err != nil {
 					ctx.Error(http.StatusInternalServerError, err.Error())
 					return
 				}
+				if !canViewPrivate {
+					sections := &actions.PrivateLogSections{}
+					for _, row := range logRows {
+						row.Content = sections.Redact(row.Content)
+					}
+					logRows = logRows[min(cursor.Cursor, int64(len(logRows))):]
+				}
 
 				for i, row := range logRows {
 					logLines = append(logLines, &ViewStepLogLine{
@@ -267,6 +378,7 @@ func ViewPost(ctx *context_module.Context) {
 
 // Rerun will rerun jobs in the given run
 // If jobIndexStr is a blank string, it means rerun all jobs
+// The "runner" form value can be "prefer" or "require" to pin the jobs to the runners which executed their previous attempts
 func Rerun(ctx *context_module.Context) {
 	runIndex := ctx.ParamsInt64("run")
 	jobIndexStr := ctx.Params("job")
@@ -274,6 +386,7 @@ func Rerun(ctx *context_module.Context) {
 	if jobIndexStr != "" {
 		jobIndex, _ = strconv.ParseInt(jobIndexStr, 10, 64)
 	}
+	pinning := actions_model.ParseRunnerPinning(ctx.FormString("runner"))
 
 	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
 	if err != nil {
@@ -281,6 +394,11 @@ func Rerun(ctx *context_module.Context) {
 		return
 	}
 
+	if run.IsExternal() {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.external_readonly"))
+		return
+	}
+
 	// can not rerun job when workflow is disabled
 	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
 	cfg := cfgUnit.ActionsConfig()
@@ -289,74 +407,42 @@ func Rerun(ctx *context_module.Context) {
 		return
 	}
 
-	// reset run's start and stop time when it is done
-	if run.Status.IsDone() {
-		run.PreviousDuration = run.Duration()
-		run.Started = 0
-		run.Stopped = 0
-		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration"); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
-	}
-
 	job, jobs := getRunJobs(ctx, runIndex, jobIndex)
 	if ctx.Written() {
 		return
 	}
-
 	if jobIndexStr == "" { // rerun all jobs
-		for _, j := range jobs {
-			// if the job has needs, it should be set to "blocked" status to wait for other jobs
-			shouldBlock := len(j.Needs) > 0
-			if err := rerunJob(ctx, j, shouldBlock); err != nil {
+		job = nil
+	}
+
+	if pinning == actions_model.RunnerPinningRequire {
+		rerunJobs := jobs
+		if job != nil {
+			rerunJobs = actions_service.GetAllRerunJobs(job, jobs)
+		}
+		// fail fast instead of leaving the jobs waiting for a runner which can't pick them
+		for _, j := range rerunJobs {
+			if !j.Status.IsDone() {
+				continue
+			}
+			if ok, err := actions_model.IsPreviousRunnerAvailable(ctx, j); err != nil {
 				ctx.Error(http.StatusInternalServerError, err.Error())
 				return
+			} else if !ok {
+				ctx.JSONError(ctx.Locale.Tr("actions.runs.pinned_runner_unavailable", j.Name))
+				return
 			}
 		}
-		ctx.JSON(http.StatusOK, struct{}{})
-		return
 	}
 
-	rerunJobs := actions_service.GetAllRerunJobs(job, jobs)
-
-	for _, j := range rerunJobs {
-		// jobs other than the specified one should be set to "blocked" status
-		shouldBlock := j.JobID != job.JobID
-		if err := rerunJob(ctx, j, shouldBlock); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
+	if err := actions_service.RerunJobs(ctx, run, jobs, job, &actions_service.RerunOptions{Pinning: pinning}); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
 	}
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
-func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob, shouldBlock bool) error {
-	status := job.Status
-	if !status.IsDone() {
-		return nil
-	}
-
-	job.TaskID = 0
-	job.Status = actions_model.StatusWaiting
-	if shouldBlock {
-		job.Status = actions_model.StatusBlocked
-	}
-	job.Started = 0
-	job.Stopped = 0
-
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
-		return err
-	}); err != nil {
-		return err
-	}
-
-	actions_service.CreateCommitStatus(ctx, job)
-	return nil
-}
-
 func Logs(ctx *context_module.Context) {
 	runIndex := ctx.ParamsInt64("run")
 	jobIndex := ctx.ParamsInt64("job")
@@ -365,83 +451,43 @@ func Logs(ctx *context_module.Context) {
 	if ctx.Written() {
 		return
 	}
-	if job.TaskID == 0 {
-		ctx.Error(http.StatusNotFound, "job is not started")
-		return
-	}
-
-	err := job.LoadRun(ctx)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.Error(http.StatusNotFound, "logs are only visible to signed-in users")
 		return
 	}
 
-	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
-		return
-	}
-	if task.LogExpired {
-		ctx.Error(http.StatusNotFound, "logs have been cleaned up")
-		return
-	}
-
-	reader, err := actions.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
-		return
-	}
-	defer reader.Close()
-
-	workflowName := job.Run.WorkflowID
-	if p := strings.Index(workflowName, "."); p > 0 {
-		workflowName = workflowName[0:p]
+	// the private sections of the logs are redacted for the users who aren't maintainers
+	if err := common.DownloadActionsRunJobLogs(ctx.Base, job, !ctx.Repo.CanWrite(unit.TypeActions)); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+		} else {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+		}
 	}
-	ctx.ServeContent(reader, &context_module.ServeHeaderOptions{
-		Filename:           fmt.Sprintf("%v-%v-%v.log", workflowName, job.Name, task.ID),
-		ContentLength:      &task.LogSize,
-		ContentType:        "text/plain",
-		ContentTypeCharset: "utf-8",
-		Disposition:        "attachment",
-	})
 }
 
 func Cancel(ctx *context_module.Context) {
 	runIndex := ctx.ParamsInt64("run")
 
-	_, jobs := getRunJobs(ctx, runIndex, -1)
+	current, _ := getRunJobs(ctx, runIndex, -1)
 	if ctx.Written() {
 		return
 	}
+	if current.Run.IsExternal() {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.external_readonly"))
+		return
+	}
 
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		for _, job := range jobs {
-			status := job.Status
-			if status.IsDone() {
-				continue
-			}
-			if job.TaskID == 0 {
-				job.Status = actions_model.StatusCancelled
-				job.Stopped = timeutil.TimeStampNow()
-				n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
-				if err != nil {
-					return err
-				}
-				if n == 0 {
-					return fmt.Errorf("job has changed, try again")
-				}
-				continue
-			}
-			if err := actions_model.StopTask(ctx, job.TaskID, actions_model.StatusCancelled); err != nil {
-				return err
-			}
-		}
-		return nil
-	}); err != nil {
+	if err := actions_model.CancelRun(ctx, current.RunID); err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, current.RunID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
 	actions_service.CreateCommitStatus(ctx, jobs...)
 
 	ctx.JSON(http.StatusOK, struct{}{})
@@ -455,14 +501,39 @@ func Approve(ctx *context_module.Context) {
 		return
 	}
 	run := current.Run
+	if run.IsExternal() {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.external_readonly"))
+		return
+	}
 	doer := ctx.Doer
 
+	missing, err := actions_service.CheckMissingRequirements(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(missing) > 0 {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.missing_requirements_desc", strings.Join(missing, ", ")))
+		return
+	}
+
+	// the jobs of the run held by its concurrency group are unblocked once the group is free
+	held, err := actions_model.IsRunHeldByConcurrency(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
 		run.NeedApproval = false
 		run.ApprovedBy = doer.ID
-		if err := actions_model.UpdateRun(ctx, run, "need_approval", "approved_by"); err != nil {
+		run.MissingRequirements = nil
+		if err := actions_model.UpdateRun(ctx, run, "need_approval", "approved_by", "missing_requirements"); err != nil {
 			return err
 		}
+		if held {
+			return nil
+		}
 		for _, job := range jobs {
 			if len(job.Needs) == 0 && job.Status.IsBlocked() {
 				job.Status = actions_model.StatusWaiting
@@ -483,10 +554,116 @@ func Approve(ctx *context_module.Context) {
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
+// Acknowledge acknowledges the failure of a run as known or won't fix, with an optional comment
+func Acknowledge(ctx *context_module.Context) {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !run.CanBeAcknowledged() {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.acknowledge_not_failed"))
+		return
+	}
+
+	comment, _ := util.SplitStringAtByteN(strings.TrimSpace(ctx.FormString("comment")), 1024)
+	if err := actions_model.AcknowledgeRun(ctx, run, ctx.Doer.ID, comment); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+// Unacknowledge withdraws the acknowledgement of a failed run
+func Unacknowledge(ctx *context_module.Context) {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := actions_model.UnacknowledgeRun(ctx, run); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+// RedeliverEvent delivers the event which triggered the run again to trigger the workflows as new runs
+func RedeliverEvent(ctx *context_module.Context) {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := actions_service.RedeliverRunEvent(ctx, run); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSONError(err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.runs.redeliver_event_success"))
+	ctx.JSONRedirect(ctx.Repo.RepoLink + "/actions")
+}
+
+// DeleteRun deletes the run with its logs and artifacts
+func DeleteRun(ctx *context_module.Context) {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := actions_service.DeleteRun(ctx, run); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSONError(err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.runs.delete_success"))
+	ctx.JSONRedirect(ctx.Repo.RepoLink + "/actions")
+}
+
 // getRunJobs gets the jobs of runIndex, and returns jobs[jobIndex], jobs.
 // Any error will be written to the ctx.
 // It never returns a nil job of an empty jobs, if the jobIndex is out of range, it will be treated as 0.
 func getRunJobs(ctx *context_module.Context, runIndex, jobIndex int64) (*actions_model.ActionRunJob, []*actions_model.ActionRunJob) {
+	return loadRunJobs(ctx, runIndex, jobIndex, func(ctx context.Context, run *actions_model.ActionRun) ([]*actions_model.ActionRunJob, error) {
+		return actions_model.GetRunJobsByRunID(ctx, run.ID)
+	})
+}
+
+// getRunJobsCached is getRunJobs with the jobs from the cache, for the views polled by the frontend, not for changing the jobs
+func getRunJobsCached(ctx *context_module.Context, runIndex, jobIndex int64) (*actions_model.ActionRunJob, []*actions_model.ActionRunJob) {
+	return loadRunJobs(ctx, runIndex, jobIndex, actions_model.GetRunJobsByRunIDCached)
+}
+
+func loadRunJobs(ctx *context_module.Context, runIndex, jobIndex int64, getJobs func(ctx context.Context, run *actions_model.ActionRun) ([]*actions_model.ActionRunJob, error)) (*actions_model.ActionRunJob, []*actions_model.ActionRunJob) {
 	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
@@ -498,7 +675,7 @@ func getRunJobs(ctx *context_module.Context, runIndex, jobIndex int64) (*actions
 	}
 	run.Repo = ctx.Repo.Repository
 
-	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	jobs, err := getJobs(ctx, run)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return nil, nil
@@ -539,6 +716,10 @@ func ArtifactsView(ctx *context_module.Context) {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.JSON(http.StatusOK, ArtifactsViewResponse{Artifacts: []*ArtifactsViewItem{}})
+		return
+	}
 	artifacts, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
@@ -587,6 +768,10 @@ func ArtifactsDeleteView(ctx *context_module.Context) {
 func ArtifactsDownloadView(ctx *context_module.Context) {
 	runIndex := ctx.ParamsInt64("run")
 	artifactName := ctx.Params("artifact_name")
+	if !actions_service.CanViewRunDetails(ctx, ctx.Repo.Repository, ctx.Doer) {
+		ctx.Error(http.StatusNotFound, "artifacts are only visible to signed-in users")
+		return
+	}
 
 	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
 	if err != nil {
@@ -598,80 +783,11 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 		return
 	}
 
-	artifacts, err := db.Find[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
-		RunID:        run.ID,
-		ArtifactName: artifactName,
-	})
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
-		return
-	}
-	if len(artifacts) == 0 {
-		ctx.Error(http.StatusNotFound, "artifact not found")
-		return
-	}
-
-	// if artifacts status is not uploaded-confirmed, treat it as not found
-	for _, art := range artifacts {
-		if art.Status != int64(actions_model.ArtifactStatusUploadConfirmed) {
-			ctx.Error(http.StatusNotFound, "artifact not found")
-			return
-		}
-	}
-
-	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.zip; filename*=UTF-8''%s.zip", url.PathEscape(artifactName), artifactName))
-
-	// Artifacts using the v4 backend are stored as a single combined zip file per artifact on the backend
-	// The v4 backend enshures ContentEncoding is set to "application/zip", which is not the case for the old backend
-	if len(artifacts) == 1 && artifacts[0].ArtifactName+".zip" == artifacts[0].ArtifactPath && artifacts[0].ContentEncoding == "application/zip" {
-		art := artifacts[0]
-		if setting.Actions.ArtifactStorage.MinioConfig.ServeDirect {
-			u, err := storage.ActionsArtifacts.URL(art.StoragePath, art.ArtifactPath)
-			if u != nil && err == nil {
-				ctx.Redirect(u.String())
-				return
-			}
-		}
-		f, err := storage.ActionsArtifacts.Open(art.StoragePath)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
-		_, _ = io.Copy(ctx.Resp, f)
-		return
-	}
-
-	// Artifacts using the v1-v3 backend are stored as multiple individual files per artifact on the backend
-	// Those need to be zipped for download
-	writer := zip.NewWriter(ctx.Resp)
-	defer writer.Close()
-	for _, art := range artifacts {
-		f, err := storage.ActionsArtifacts.Open(art.StoragePath)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
-
-		var r io.ReadCloser
-		if art.ContentEncoding == "gzip" {
-			r, err = gzip.NewReader(f)
-			if err != nil {
-				ctx.Error(http.StatusInternalServerError, err.Error())
-				return
-			}
+	if err := common.DownloadActionsArtifact(ctx.Base, run.ID, artifactName); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
 		} else {
-			r = f
-		}
-		defer r.Close()
-
-		w, err := writer.Create(art.ArtifactPath)
-		if err != nil {
 			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
-		if _, err := io.Copy(w, r); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
 		}
 	}
 }
@@ -704,6 +820,10 @@ func disableOrEnableWorkflowFile(ctx *context_module.Context, isEnable bool) {
 		ctx.ServerError("UpdateRepoUnit", err)
 		return
 	}
+	if err := actions_model.RecordConfigRevision(ctx, ctx.Repo.Repository.ID, cfg); err != nil {
+		ctx.ServerError("RecordConfigRevision", err)
+		return
+	}
 
 	if isEnable {
 		ctx.Flash.Success(ctx.Tr("actions.workflow.enable_success", workflow))
@@ -177,6 +177,8 @@ func editFile(ctx *context.Context, isNewFile bool) {
 	} else {
 		// Append filename from query, or empty string to allow user name the new file.
 		treeNames = append(treeNames, fileName)
+		// Prefill the content from query, it's also compatible with GitHub
+		ctx.Data["FileContent"] = ctx.FormString("value")
 	}
 
 	ctx.Data["TreeNames"] = treeNames
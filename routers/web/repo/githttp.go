@@ -30,6 +30,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	repo_service "code.gitea.io/gitea/services/repository"
 
@@ -194,24 +195,41 @@ func httpBase(ctx *context.Context) *serviceHandler {
 					ctx.ServerError("GetTaskByID", err)
 					return nil
 				}
+				var taskAccessMode perm.AccessMode
 				if task.RepoID != repo.ID {
-					ctx.PlainText(http.StatusForbidden, "User permission denied")
-					return nil
-				}
-
-				if task.IsForkPullRequest {
-					if accessMode > perm.AccessModeRead {
+					// the repositories of the submodules could be cloned for the recursive checkouts if the repository of the run allows
+					canReadSubmodule := false
+					if isPull {
+						canReadSubmodule, err = actions_service.CanTaskReadSubmodule(ctx, task, repo)
+						if err != nil {
+							ctx.ServerError("CanTaskReadSubmodule", err)
+							return nil
+						}
+					}
+					if !canReadSubmodule {
+						anomaly := actions_model.TokenAnomalyOtherRepo
+						if !isPull {
+							anomaly = actions_model.TokenAnomalyWriteOtherRepo
+						}
+						if err := actions_service.RecordTokenActivity(ctx, task.ID, ctx.Req, repo.ID, http.StatusForbidden, anomaly); err != nil {
+							log.Error("RecordTokenActivity of task %d: %v", task.ID, err)
+						}
 						ctx.PlainText(http.StatusForbidden, "User permission denied")
 						return nil
 					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeRead))
+					taskAccessMode = perm.AccessModeRead
 				} else {
-					if accessMode > perm.AccessModeWrite {
-						ctx.PlainText(http.StatusForbidden, "User permission denied")
+					taskAccessMode, err = task.TokenAccessMode(ctx)
+					if err != nil {
+						ctx.ServerError("TokenAccessMode", err)
 						return nil
 					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeWrite))
 				}
+				if accessMode > taskAccessMode {
+					ctx.PlainText(http.StatusForbidden, "User permission denied")
+					return nil
+				}
+				environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, taskAccessMode))
 			} else {
 				p, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
 				if err != nil {
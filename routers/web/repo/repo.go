@@ -246,16 +246,17 @@ func CreatePost(ctx *context.Context) {
 	var err error
 	if form.RepoTemplate > 0 {
 		opts := repo_service.GenerateRepoOptions{
-			Name:            form.RepoName,
-			Description:     form.Description,
-			Private:         form.Private,
-			GitContent:      form.GitContent,
-			Topics:          form.Topics,
-			GitHooks:        form.GitHooks,
-			Webhooks:        form.Webhooks,
-			Avatar:          form.Avatar,
-			IssueLabels:     form.Labels,
-			ProtectedBranch: form.ProtectedBranch,
+			Name:              form.RepoName,
+			Description:       form.Description,
+			Private:           form.Private,
+			GitContent:        form.GitContent,
+			Topics:            form.Topics,
+			GitHooks:          form.GitHooks,
+			Webhooks:          form.Webhooks,
+			Avatar:            form.Avatar,
+			IssueLabels:       form.Labels,
+			ProtectedBranch:   form.ProtectedBranch,
+			WorkflowSchedules: form.WorkflowSchedules,
 		}
 
 		if !opts.IsValid() {
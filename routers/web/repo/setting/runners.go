@@ -122,10 +122,25 @@ func Runners(ctx *context.Context) {
 	if rCtx.IsRepo {
 		opts.RepoID = rCtx.RepoID
 		opts.WithAvailable = true
+		policy, err := actions_model.GetRunnerSharingPolicy(ctx, ctx.Repo.Repository.OwnerID)
+		if err != nil {
+			ctx.ServerError("GetRunnerSharingPolicy", err)
+			return
+		}
+		ctx.Data["RepoRunnersDisallowed"] = !policy.AllowsRepoRunners()
 	} else if rCtx.IsOrg || rCtx.IsUser {
 		opts.OwnerID = rCtx.OwnerID
 		opts.WithAvailable = true
 	}
+	if rCtx.IsOrg || rCtx.IsUser {
+		policy, err := actions_model.GetRunnerSharingPolicy(ctx, rCtx.OwnerID)
+		if err != nil {
+			ctx.ServerError("GetRunnerSharingPolicy", err)
+			return
+		}
+		ctx.Data["RunnerSharingPolicy"] = policy
+		ctx.Data["RunnerSharingPolicies"] = actions_model.RunnerSharingPolicies
+	}
 	actions_shared.RunnersList(ctx, opts)
 
 	ctx.HTML(http.StatusOK, rCtx.RunnersTemplate)
@@ -182,6 +197,33 @@ func RunnerDeletePost(ctx *context.Context) {
 	actions_shared.RunnerDeletePost(ctx, ctx.ParamsInt64(":runnerid"), rCtx.RedirectLink, rCtx.RedirectLink+url.PathEscape(ctx.Params(":runnerid")))
 }
 
+// RunnerSharingPolicyPost response for changing the runner sharing policy of an organization or a user
+func RunnerSharingPolicyPost(ctx *context.Context) {
+	rCtx, err := getRunnersCtx(ctx)
+	if err != nil {
+		ctx.ServerError("getRunnersCtx", err)
+		return
+	}
+	if !rCtx.IsOrg && !rCtx.IsUser {
+		ctx.NotFound("RunnerSharingPolicyPost", nil)
+		return
+	}
+
+	policy, ok := actions_model.ParseRunnerSharingPolicy(ctx.FormString("policy"))
+	if !ok {
+		ctx.Flash.Error(ctx.Tr("actions.runners.sharing_policy.invalid"))
+		ctx.Redirect(rCtx.RedirectLink)
+		return
+	}
+	if err := actions_model.SetRunnerSharingPolicy(ctx, rCtx.OwnerID, policy); err != nil {
+		ctx.ServerError("SetRunnerSharingPolicy", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.runners.sharing_policy.update_success"))
+	ctx.Redirect(rCtx.RedirectLink)
+}
+
 func RedirectToDefaultSetting(ctx *context.Context) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/actions/runners")
 }
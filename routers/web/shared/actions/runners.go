@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
 )
@@ -73,6 +74,7 @@ func RunnerDetails(ctx *context.Context, page int, runnerID, ownerID, repoID int
 	}
 
 	ctx.Data["Runner"] = runner
+	ctx.Data["Executors"] = actions_service.ExecutorNames()
 
 	opts := actions_model.FindTaskOptions{
 		ListOptions: db.ListOptions{
@@ -115,8 +117,16 @@ func RunnerDetailsEditPost(ctx *context.Context, runnerID, ownerID, repoID int64
 
 	form := web.GetForm(ctx).(*forms.EditRunnerForm)
 	runner.Description = form.Description
+	if form.Executor != "" {
+		if _, ok := actions_service.GetExecutor(form.Executor); !ok {
+			ctx.Flash.Error(ctx.Tr("actions.runners.executor_not_found", form.Executor))
+			ctx.Redirect(redirectTo)
+			return
+		}
+	}
+	runner.Executor = form.Executor
 
-	err = actions_model.UpdateRunner(ctx, runner, "description")
+	err = actions_model.UpdateRunner(ctx, runner, "description", "executor")
 	if err != nil {
 		log.Warn("RunnerDetailsEditPost.UpdateRunner failed: %v, url: %s", err, ctx.Req.URL)
 		ctx.Flash.Warning(ctx.Tr("actions.runners.update_runner_failed"))
@@ -159,6 +159,13 @@ func getNotifications(ctx *context.Context) {
 		return
 	}
 	notifications = notifications.Without(failures)
+
+	failures, err = notifications.LoadRuns(ctx)
+	if err != nil {
+		ctx.ServerError("LoadRuns", err)
+		return
+	}
+	notifications = notifications.Without(failures)
 	failCount += len(failures)
 
 	if failCount > 0 {
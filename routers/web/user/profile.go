@@ -70,6 +70,14 @@ func userProfile(ctx *context.Context) {
 		}
 		ctx.Data["HeatmapData"] = data
 		ctx.Data["HeatmapTotalContributions"] = activities_model.GetTotalContributionsInHeatmap(data)
+
+		automationData, err := activities_model.GetUserAutomationHeatmapData(ctx, ctx.ContextUser, ctx.Doer)
+		if err != nil {
+			ctx.ServerError("GetUserAutomationHeatmapData", err)
+			return
+		}
+		ctx.Data["AutomationHeatmapData"] = automationData
+		ctx.Data["AutomationHeatmapTotalRuns"] = activities_model.GetTotalContributionsInHeatmap(automationData)
 	}
 
 	profileDbRepo, profileGitRepo, profileReadmeBlob, profileClose := shared_user.FindUserProfileReadme(ctx, ctx.Doer)
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/auth/source/db"
 	"code.gitea.io/gitea/services/auth/source/smtp"
@@ -176,6 +177,22 @@ func EmailPost(ctx *context.Context) {
 		return
 	}
 
+	// Set how often to receive the digest of the failed and flaky workflows
+	if ctx.FormString("_method") == "ACTIONS_FAILURE_DIGEST" {
+		frequency := ctx.FormString("frequency")
+		if !actions_service.IsValidFailureDigest(frequency) {
+			ctx.ServerError("SetActionsFailureDigest", errors.New("option unrecognized"))
+			return
+		}
+		if err := user_model.SetUserSetting(ctx, ctx.Doer.ID, user_model.SettingsKeyActionsFailureDigest, frequency); err != nil {
+			ctx.ServerError("SetUserSetting", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("settings.email_preference_set_success"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/account")
+		return
+	}
+
 	if ctx.HasError() {
 		loadAccountData(ctx)
 
@@ -316,6 +333,15 @@ func loadAccountData(ctx *context.Context) {
 	}
 	ctx.Data["Emails"] = emails
 	ctx.Data["EmailNotificationsPreference"] = ctx.Doer.EmailNotificationsPreference
+	if setting.Actions.Enabled {
+		frequency, err := user_model.GetUserSetting(ctx, ctx.Doer.ID, user_model.SettingsKeyActionsFailureDigest)
+		if err != nil {
+			ctx.ServerError("GetUserSetting", err)
+			return
+		}
+		ctx.Data["EnableActionsFailureDigest"] = true
+		ctx.Data["ActionsFailureDigest"] = frequency
+	}
 	ctx.Data["ActivationsPending"] = pendingActivation
 	ctx.Data["CanAddEmails"] = !pendingActivation || !setting.Service.RegisterEmailConfirm
 	ctx.Data["UserDisabledFeatures"] = user_model.DisabledFeaturesWithLoginType(ctx.Doer)
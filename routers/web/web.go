@@ -630,6 +630,7 @@ func registerRoutes(m *web.Route) {
 		m.Group("/actions", func() {
 			m.Get("", user_setting.RedirectToDefaultSetting)
 			addSettingsRunnersRoutes()
+			m.Post("/runners/sharing_policy", repo_setting.RunnerSharingPolicyPost)
 			addSettingsSecretsRoutes()
 			addSettingsVariablesRoutes()
 		}, actions.MustEnableActions)
@@ -814,6 +815,7 @@ func registerRoutes(m *web.Route) {
 	reqRepoProjectsWriter := context.RequireRepoWriter(unit.TypeProjects)
 	reqRepoActionsReader := context.RequireRepoReader(unit.TypeActions)
 	reqRepoActionsWriter := context.RequireRepoWriter(unit.TypeActions)
+	reqRepoActionsAdmin := context.RequireRepoActionsAdmin()
 
 	reqPackageAccess := func(accessMode perm.AccessMode) func(ctx *context.Context) {
 		return func(ctx *context.Context) {
@@ -929,6 +931,7 @@ func registerRoutes(m *web.Route) {
 				m.Group("/actions", func() {
 					m.Get("", org_setting.RedirectToDefaultSetting)
 					addSettingsRunnersRoutes()
+					m.Post("/runners/sharing_policy", repo_setting.RunnerSharingPolicyPost)
 					addSettingsSecretsRoutes()
 					addSettingsVariablesRoutes()
 				}, actions.MustEnableActions)
@@ -1105,12 +1108,6 @@ func registerRoutes(m *web.Route) {
 				m.Post("/{lid}/unlock", repo_setting.LFSUnlock)
 			})
 		})
-		m.Group("/actions", func() {
-			m.Get("", repo_setting.RedirectToDefaultSetting)
-			addSettingsRunnersRoutes()
-			addSettingsSecretsRoutes()
-			addSettingsVariablesRoutes()
-		}, actions.MustEnableActions)
 		// the follow handler must be under "settings", otherwise this incomplete repo can't be accessed
 		m.Group("/migrate", func() {
 			m.Post("/retry", repo.MigrateRetryPost)
@@ -1122,6 +1119,18 @@ func registerRoutes(m *web.Route) {
 	)
 	// end "/{username}/{reponame}/settings"
 
+	// the Actions could be administered without administering the repository, see access.Permission.CanManageActions
+	m.Group("/{username}/{reponame}/settings/actions", func() {
+		m.Get("", repo_setting.RedirectToDefaultSetting)
+		addSettingsRunnersRoutes()
+		addSettingsSecretsRoutes()
+		addSettingsVariablesRoutes()
+	},
+		reqSignIn, context.RepoAssignment, reqRepoActionsAdmin, context.RepoRef(),
+		ctxDataSet("PageIsRepoSettings", true, "LFSStartServer", setting.LFS.StartServer), actions.MustEnableActions,
+	)
+	// end "/{username}/{reponame}/settings/actions"
+
 	// user/org home, including rss feeds
 	m.Get("/{username}/{reponame}", ignSignIn, context.RepoAssignment, context.RepoRef(), repo.SetEditorconfigIfExists, repo.Home)
 
@@ -1377,8 +1386,8 @@ func registerRoutes(m *web.Route) {
 
 	m.Group("/{username}/{reponame}/actions", func() {
 		m.Get("", actions.List)
-		m.Post("/disable", reqRepoAdmin, actions.DisableWorkflowFile)
-		m.Post("/enable", reqRepoAdmin, actions.EnableWorkflowFile)
+		m.Post("/disable", reqRepoActionsAdmin, actions.DisableWorkflowFile)
+		m.Post("/enable", reqRepoActionsAdmin, actions.EnableWorkflowFile)
 
 		m.Group("/runs/{run}", func() {
 			m.Combo("").
@@ -1393,14 +1402,27 @@ func registerRoutes(m *web.Route) {
 			})
 			m.Post("/cancel", reqRepoActionsWriter, actions.Cancel)
 			m.Post("/approve", reqRepoActionsWriter, actions.Approve)
+			m.Post("/acknowledge", reqRepoActionsWriter, actions.Acknowledge)
+			m.Post("/unacknowledge", reqRepoActionsWriter, actions.Unacknowledge)
+			m.Post("/issue", reqRepoActionsWriter, actions.OpenIssue)
+			m.Post("/redeliver", reqRepoActionsWriter, actions.RedeliverEvent)
+			m.Post("/delete", reqRepoActionsAdmin, context.RepoMustNotBeArchived(), actions.DeleteRun)
 			m.Get("/artifacts", actions.ArtifactsView)
+			m.Get("/summary", actions.SummaryView)
+			m.Group("/comments", func() {
+				m.Get("", actions.CommentsView)
+				m.Post("", reqSignIn, context.RepoMustNotBeArchived(), actions.CommentsPost)
+				m.Post("/{id}/delete", reqSignIn, context.RepoMustNotBeArchived(), actions.CommentDelete)
+			})
 			m.Get("/artifacts/{artifact_name}", actions.ArtifactsDownloadView)
 			m.Delete("/artifacts/{artifact_name}", actions.ArtifactsDeleteView)
 			m.Post("/rerun", reqRepoActionsWriter, actions.Rerun)
 		})
 		m.Group("/workflows/{workflow_name}", func() {
 			m.Get("/badge.svg", actions.GetWorkflowBadge)
+			m.Get("/jobs/{job_name}/badge.svg", actions.GetWorkflowJobBadge)
 		})
+		m.Get("/components/badge.svg", actions.GetComponentBadge)
 	}, ignSignIn, context.RepoAssignment, reqRepoActionsReader, actions.MustEnableActions)
 	// end "/{username}/{reponame}/actions"
 
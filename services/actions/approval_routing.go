@@ -0,0 +1,120 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	org_model "code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// GetRunApprovers returns the users who are asked to approve the run, and whether they are the code owners.
+// For the runs of pull requests, they are the code owners of the changed files by CODEOWNERS who could approve the run.
+// Otherwise, or if none of the code owners could approve it, they are the users who could write Actions of the repository.
+func GetRunApprovers(ctx context.Context, run *actions_model.ActionRun) ([]*user_model.User, bool, error) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, false, err
+	}
+
+	owners, err := getRunCodeOwners(ctx, run)
+	if err != nil {
+		// the maintainers are asked instead, rather than nobody
+		log.Warn("getRunCodeOwners of run %d: %v", run.ID, err)
+	}
+	if approvers, err := filterRunApprovers(ctx, run, owners); err != nil {
+		return nil, false, err
+	} else if len(approvers) > 0 {
+		return approvers, true, nil
+	}
+
+	maintainers, err := repo_model.GetRepoAssignees(ctx, run.Repo)
+	if err != nil {
+		return nil, false, err
+	}
+	approvers, err := filterRunApprovers(ctx, run, maintainers)
+	return approvers, false, err
+}
+
+// getRunCodeOwners returns the users owning the files changed by the pull request of the run, including the members of the owning teams
+func getRunCodeOwners(ctx context.Context, run *actions_model.ActionRun) ([]*user_model.User, error) {
+	if !run.IsForkPullRequest {
+		return nil, nil
+	}
+	eventPayload, err := run.GetEventPayload()
+	if err != nil {
+		return nil, err
+	}
+	var payload api.PullRequestPayload
+	if err := json.Unmarshal([]byte(eventPayload), &payload); err != nil {
+		return nil, err
+	}
+	if payload.PullRequest == nil {
+		return nil, fmt.Errorf("no pull request in the payload of event %s", run.Event)
+	}
+	pr, err := issues_model.GetPullRequestByIndex(ctx, run.RepoID, payload.PullRequest.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	users, teams, err := issue_service.GetPullRequestCodeOwners(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+	owners := make([]*user_model.User, 0, len(users))
+	for _, u := range users {
+		owners = append(owners, u)
+	}
+	for _, t := range teams {
+		members, err := org_model.GetTeamMembers(ctx, &org_model.SearchMembersOptions{TeamID: t.ID})
+		if err != nil {
+			return nil, err
+		}
+		owners = append(owners, members...)
+	}
+	return owners, nil
+}
+
+// filterRunApprovers returns the users who could approve the run, the user triggering it can't approve it
+func filterRunApprovers(ctx context.Context, run *actions_model.ActionRun, users []*user_model.User) ([]*user_model.User, error) {
+	visited := make(container.Set[int64], len(users))
+	approvers := make([]*user_model.User, 0, len(users))
+	for _, u := range users {
+		if u.ID == run.TriggerUserID || !u.IsActive || u.ProhibitLogin || !visited.Add(u.ID) {
+			continue
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, run.Repo, u)
+		if err != nil {
+			return nil, err
+		}
+		if perm.CanWrite(unit_model.TypeActions) {
+			approvers = append(approvers, u)
+		}
+	}
+	return approvers, nil
+}
+
+// notifyRunApprovers asks the approvers of the run which needs an approval to approve it
+func notifyRunApprovers(ctx context.Context, run *actions_model.ActionRun) {
+	approvers, isCodeOwners, err := GetRunApprovers(ctx, run)
+	if err != nil {
+		log.Error("GetRunApprovers of run %d: %v", run.ID, err)
+		return
+	}
+	if err := mailer.MailActionRunApprovalRequest(ctx, run, approvers, isCodeOwners); err != nil {
+		log.Error("MailActionRunApprovalRequest of run %d: %v", run.ID, err)
+	}
+}
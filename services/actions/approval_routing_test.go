@@ -0,0 +1,58 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunApprovers(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	approverIDs := func(run *actions_model.ActionRun) ([]int64, bool) {
+		approvers, isCodeOwners, err := GetRunApprovers(ctx, run)
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(approvers))
+		for _, u := range approvers {
+			ids = append(ids, u.ID)
+		}
+		return ids, isCodeOwners
+	}
+
+	// the maintainers are asked for the runs not of pull requests, except the user triggering the run
+	run := &actions_model.ActionRun{RepoID: repo.ID, OwnerID: repo.OwnerID, Index: 1, TriggerUserID: 4, Status: actions_model.StatusBlocked}
+	require.NoError(t, db.Insert(ctx, run))
+	ids, isCodeOwners := approverIDs(run)
+	assert.False(t, isCodeOwners)
+	assert.ElementsMatch(t, []int64{2}, ids)
+
+	run = &actions_model.ActionRun{RepoID: repo.ID, OwnerID: repo.OwnerID, Index: 2, TriggerUserID: 2, Status: actions_model.StatusBlocked}
+	require.NoError(t, db.Insert(ctx, run))
+	ids, _ = approverIDs(run)
+	assert.Empty(t, ids)
+
+	// only the users who could write Actions could approve
+	users := []*user_model.User{
+		unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}),
+		unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4}),
+		unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}),
+	}
+	run.TriggerUserID = 8
+	approvers, err := filterRunApprovers(ctx, run, users)
+	require.NoError(t, err)
+	if assert.Len(t, approvers, 1) {
+		assert.EqualValues(t, 2, approvers[0].ID)
+	}
+}
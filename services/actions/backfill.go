@@ -0,0 +1,167 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+
+	"github.com/gobwas/glob"
+)
+
+// maxBackfillEvents is the max number of the push events synthesized by a backfill
+const maxBackfillEvents = 100
+
+// BackfillOptions are the existing tags and commits to synthesize push events for
+type BackfillOptions struct {
+	Tags   []string // glob patterns of the tags, like "v1.*"
+	Branch string   // the branch of the commits, no commits are backfilled if it's empty
+	Since  string   // the commits of Branch after this commit are backfilled, it's required if Branch is set
+	Until  string   // the last commit of Branch to backfill, it's the head of Branch if empty
+}
+
+// BackfilledEvent is a push event synthesized for an existing tag or commit
+type BackfilledEvent struct {
+	Ref      string
+	CommitID string
+}
+
+// BackfillRuns synthesizes push events for the existing tags and commits as if they were pushed by the doer,
+// so their workflows could be triggered without pushing them again, e.g. after enabling Actions on an old repository.
+// The backfilled runs don't cancel each other, and the schedules of the repository aren't changed.
+func BackfillRuns(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, opts *BackfillOptions) ([]*BackfilledEvent, error) {
+	if len(opts.Tags) == 0 && opts.Branch == "" {
+		return nil, util.NewInvalidArgumentErrorf("tags or a branch are required")
+	}
+	if opts.Branch != "" && opts.Since == "" {
+		return nil, util.NewInvalidArgumentErrorf("the commit to backfill the branch since is required")
+	}
+	if repo.IsEmpty || repo.IsArchived {
+		return nil, util.NewInvalidArgumentErrorf("repository %s is empty or archived", repo.FullName())
+	}
+	if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return nil, util.NewInvalidArgumentErrorf("actions of repository %s are disabled", repo.FullName())
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	type backfillCommit struct {
+		ref    git.RefName
+		before string
+		commit *git.Commit
+	}
+	var commits []*backfillCommit
+
+	if len(opts.Tags) > 0 {
+		globs := make([]glob.Glob, 0, len(opts.Tags))
+		for _, pattern := range opts.Tags {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				return nil, util.NewInvalidArgumentErrorf("invalid tag pattern %q: %v", pattern, err)
+			}
+			globs = append(globs, g)
+		}
+		tags, err := gitRepo.GetTags(0, 0)
+		if err != nil {
+			return nil, fmt.Errorf("GetTags: %w", err)
+		}
+		for _, tag := range tags {
+			if !slices.ContainsFunc(globs, func(g glob.Glob) bool { return g.Match(tag) }) {
+				continue
+			}
+			commit, err := gitRepo.GetTagCommit(tag)
+			if err != nil {
+				return nil, fmt.Errorf("GetTagCommit %s: %w", tag, err)
+			}
+			// a tag is pushed as a new ref
+			commits = append(commits, &backfillCommit{
+				ref:    git.RefNameFromTag(tag),
+				before: commit.ID.Type().EmptyObjectID().String(),
+				commit: commit,
+			})
+		}
+	}
+
+	if opts.Branch != "" {
+		if !gitRepo.IsBranchExist(opts.Branch) {
+			return nil, util.NewInvalidArgumentErrorf("branch %s doesn't exist", opts.Branch)
+		}
+		until := opts.Until
+		if until == "" {
+			until = git.BranchPrefix + opts.Branch
+		}
+		branchCommits, err := gitRepo.CommitsBetweenIDs(until, opts.Since)
+		if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("commits between %s and %s: %v", opts.Since, until, err)
+		}
+		// the commits are pushed one by one from the oldest one
+		for i := len(branchCommits) - 1; i >= 0; i-- {
+			commit := branchCommits[i]
+			before := commit.ID.Type().EmptyObjectID().String()
+			if parent, err := commit.ParentID(0); err == nil {
+				before = parent.String()
+			}
+			commits = append(commits, &backfillCommit{
+				ref:    git.RefNameFromBranch(opts.Branch),
+				before: before,
+				commit: commit,
+			})
+		}
+	}
+
+	if len(commits) > maxBackfillEvents {
+		return nil, util.NewInvalidArgumentErrorf("%d commits match, at most %d commits could be backfilled at once", len(commits), maxBackfillEvents)
+	}
+
+	ctx = withMethod(ctx, "BackfillRuns")
+	apiPusher := convert.ToUser(ctx, doer, nil)
+	apiRepo := convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm_model.AccessModeOwner})
+	events := make([]*BackfilledEvent, 0, len(commits))
+	for _, c := range commits {
+		pushCommits := repository.GitToPushCommits([]*git.Commit{c.commit})
+		pushCommits.HeadCommit = repository.CommitToPushCommit(c.commit)
+		apiCommits, apiHeadCommit, err := pushCommits.ToAPIPayloadCommits(ctx, repo.RepoPath(), repo.HTMLURL())
+		if err != nil {
+			return nil, fmt.Errorf("ToAPIPayloadCommits: %w", err)
+		}
+
+		input := newNotifyInput(repo, doer, webhook_module.HookEventPush).
+			WithRef(c.ref.String()).
+			WithCommitID(c.commit.ID.String()).
+			WithPayload(&api.PushPayload{
+				Ref:        c.ref.String(),
+				Before:     c.before,
+				After:      c.commit.ID.String(),
+				Commits:    apiCommits,
+				HeadCommit: apiHeadCommit,
+				Repo:       apiRepo,
+				Pusher:     apiPusher,
+				Sender:     apiPusher,
+			}).
+			WithBackfill()
+		if err := pushNotifyInput(ctx, input); err != nil {
+			return nil, err
+		}
+		events = append(events, &BackfilledEvent{Ref: c.ref.String(), CommitID: c.commit.ID.String()})
+	}
+	return events, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillRuns(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	events, err := BackfillRuns(ctx, repo, doer, &BackfillOptions{
+		Tags:   []string{"v1.*", "v2.*"},
+		Branch: "branch2",
+		Since:  "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+	})
+	require.NoError(t, err)
+	// the commits of the branch are pushed from the oldest one
+	assert.Equal(t, []*BackfilledEvent{
+		{Ref: "refs/tags/v1.1", CommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d"},
+		{Ref: "refs/heads/branch2", CommitID: "5c050d3b6d2db231ab1f64e324f1b6b9a0b181c2"},
+		{Ref: "refs/heads/branch2", CommitID: "985f0301dba5e7b34be866819cd15ad3d8f508ee"},
+	}, events)
+
+	events, err = BackfillRuns(ctx, repo, doer, &BackfillOptions{
+		Branch: "branch2",
+		Since:  "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		Until:  "5c050d3b6d2db231ab1f64e324f1b6b9a0b181c2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*BackfilledEvent{
+		{Ref: "refs/heads/branch2", CommitID: "5c050d3b6d2db231ab1f64e324f1b6b9a0b181c2"},
+	}, events)
+
+	_, err = BackfillRuns(ctx, repo, doer, &BackfillOptions{})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = BackfillRuns(ctx, repo, doer, &BackfillOptions{Branch: "branch2"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = BackfillRuns(ctx, repo, doer, &BackfillOptions{Branch: "no-such-branch", Since: "65f1bf27bc3bf70f64657658635e66094edbcb4d"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = BackfillRuns(ctx, repo, doer, &BackfillOptions{Tags: []string{"v[1"}})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// notifyChatOpsCommands dispatches the workflows mapped to the slash commands in a comment of a pull request,
+// the commands which aren't configured by the repository or can't be run by the commenter are ignored.
+func notifyChatOpsCommands(ctx context.Context, doer *user_model.User, comment *issues_model.Comment) {
+	commands := actions_module.ParseChatOpsCommands(comment.Content)
+	if len(commands) == 0 {
+		return
+	}
+
+	if err := comment.LoadIssue(ctx); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	issue := comment.Issue
+	if !issue.IsPull {
+		return
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		log.Error("LoadRepo: %v", err)
+		return
+	}
+	repo := issue.Repo
+	actionsUnit, err := repo.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		if !repo_model.IsErrUnitTypeNotExist(err) {
+			log.Error("GetUnit: %v", err)
+		}
+		return
+	}
+	cfg := actionsUnit.ActionsConfig()
+	if len(cfg.ChatOpsCommands) == 0 {
+		return
+	}
+
+	permission, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		log.Error("GetUserRepoPermission: %v", err)
+		return
+	}
+	if err := issue.LoadPullRequest(ctx); err != nil {
+		log.Error("LoadPullRequest: %v", err)
+		return
+	}
+
+	for _, command := range commands {
+		c := cfg.GetChatOpsCommand(command.Name)
+		if c == nil {
+			continue
+		}
+		if !canRunChatOpsCommand(permission, c) {
+			log.Trace("user %d isn't allowed to run command %q of repo %d", doer.ID, c.Name, repo.ID)
+			continue
+		}
+
+		inputs := map[string]string{}
+		if c.ArgsInput != "" && command.Args != "" {
+			inputs[c.ArgsInput] = command.Args
+		}
+		newNotifyInput(repo, doer, webhook_module.HookEventWorkflowDispatch).
+			WithPayload(&api.WorkflowDispatchPayload{
+				Workflow:   c.WorkflowID,
+				Ref:        issue.PullRequest.GetGitRefName(),
+				Inputs:     inputs,
+				Repository: convert.ToRepo(ctx, repo, permission),
+				Sender:     convert.ToUser(ctx, doer, nil),
+			}).
+			WithPullRequest(issue.PullRequest).
+			Notify(ctx)
+	}
+}
+
+// canRunChatOpsCommand returns whether the user with the permission could run the slash command
+func canRunChatOpsCommand(permission access_model.Permission, c *repo_model.ActionsChatOpsCommand) bool {
+	if c.RequireAdmin {
+		return permission.IsAdmin()
+	}
+	return permission.CanWrite(unit_model.TypeActions)
+}
+
+// prepareWorkflowDispatchInputs checks the inputs of the payload against the inputs declared by the workflow,
+// and fills the default values of the inputs which aren't provided
+func prepareWorkflowDispatchInputs(content []byte, payload *api.WorkflowDispatchPayload) error {
+	if actions_module.IsEventBlocked(string(webhook_module.HookEventWorkflowDispatch)) {
+		return fmt.Errorf("workflow_dispatch is blocked by the instance")
+	}
+	wf, err := model.ReadWorkflow(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("ReadWorkflow: %w", err)
+	}
+	dispatch := wf.WorkflowDispatchConfig()
+	if dispatch == nil {
+		return fmt.Errorf("workflow %q isn't triggered by workflow_dispatch", payload.Workflow)
+	}
+
+	for name := range payload.Inputs {
+		if _, ok := dispatch.Inputs[name]; !ok {
+			return fmt.Errorf("workflow %q doesn't have input %q", payload.Workflow, name)
+		}
+	}
+
+	names := make([]string, 0, len(dispatch.Inputs))
+	for name := range dispatch.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inputs := make(map[string]string, len(dispatch.Inputs))
+	for _, name := range names {
+		input := dispatch.Inputs[name]
+		value := payload.Inputs[name]
+		if value == "" {
+			value = input.Default
+		}
+		switch {
+		case value == "" && input.Required:
+			return fmt.Errorf("input %q of workflow %q is required", name, payload.Workflow)
+		case value != "" && input.Type == "choice" && !slices.Contains(input.Options, value):
+			return fmt.Errorf("input %q of workflow %q must be one of %v", name, payload.Workflow, input.Options)
+		case value != "" && input.Type == "boolean" && value != "true" && value != "false":
+			return fmt.Errorf("input %q of workflow %q must be true or false", name, payload.Workflow)
+		}
+		inputs[name] = value
+	}
+	payload.Inputs = inputs
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareWorkflowDispatchInputs(t *testing.T) {
+	content := []byte(`
+name: deploy
+on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: choice
+        options: [staging, production]
+        required: true
+      dry_run:
+        type: boolean
+        default: "false"
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`)
+
+	kases := []struct {
+		name    string
+		inputs  map[string]string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			inputs: map[string]string{"environment": "staging"},
+			want:   map[string]string{"environment": "staging", "dry_run": "false"},
+		},
+		{
+			name:   "all inputs",
+			inputs: map[string]string{"environment": "production", "dry_run": "true"},
+			want:   map[string]string{"environment": "production", "dry_run": "true"},
+		},
+		{
+			name:    "missing required input",
+			inputs:  map[string]string{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid option",
+			inputs:  map[string]string{"environment": "qa"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid boolean",
+			inputs:  map[string]string{"environment": "staging", "dry_run": "yes"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown input",
+			inputs:  map[string]string{"environment": "staging", "region": "eu"},
+			wantErr: true,
+		},
+	}
+	for _, kase := range kases {
+		t.Run(kase.name, func(t *testing.T) {
+			payload := &api.WorkflowDispatchPayload{Workflow: "deploy.yml", Inputs: kase.inputs}
+			err := prepareWorkflowDispatchInputs(content, payload)
+			if kase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, kase.want, payload.Inputs)
+		})
+	}
+
+	t.Run("not dispatchable", func(t *testing.T) {
+		payload := &api.WorkflowDispatchPayload{Workflow: "push.yml"}
+		assert.Error(t, prepareWorkflowDispatchInputs([]byte("on: push\njobs:\n  a:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"), payload))
+	})
+
+	t.Run("blocked", func(t *testing.T) {
+		defer test.MockVariableValue(&setting.Actions.BlockedEvents, []string{"workflow_dispatch"})()
+		payload := &api.WorkflowDispatchPayload{Workflow: "deploy.yml", Inputs: map[string]string{"environment": "staging"}}
+		assert.Error(t, prepareWorkflowDispatchInputs(content, payload))
+	})
+}
+
+func TestCanRunChatOpsCommand(t *testing.T) {
+	writer := access_model.Permission{}
+	writer.SetUnitsWithDefaultAccessMode([]*repo_model.RepoUnit{{Type: unit_model.TypeActions}}, perm_model.AccessModeWrite)
+	reader := access_model.Permission{}
+	reader.SetUnitsWithDefaultAccessMode([]*repo_model.RepoUnit{{Type: unit_model.TypeActions}}, perm_model.AccessModeRead)
+	admin := access_model.Permission{AccessMode: perm_model.AccessModeAdmin}
+	admin.SetUnitsWithDefaultAccessMode([]*repo_model.RepoUnit{{Type: unit_model.TypeActions}}, perm_model.AccessModeAdmin)
+
+	retest := &repo_model.ActionsChatOpsCommand{Name: "retest", WorkflowID: "test.yml"}
+	deploy := &repo_model.ActionsChatOpsCommand{Name: "deploy", WorkflowID: "deploy.yml", RequireAdmin: true}
+
+	assert.True(t, canRunChatOpsCommand(writer, retest))
+	assert.False(t, canRunChatOpsCommand(reader, retest))
+	assert.True(t, canRunChatOpsCommand(admin, retest))
+	assert.False(t, canRunChatOpsCommand(writer, deploy))
+	assert.True(t, canRunChatOpsCommand(admin, deploy))
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	lfs_service "code.gitea.io/gitea/services/lfs"
+)
+
+// prepareCheckout sets the checkout hints declared by the workflow to the new run
+func prepareCheckout(run *actions_model.ActionRun, content []byte) error {
+	checkout, err := actions_module.ParseWorkflowCheckout(content)
+	if err != nil || checkout == nil {
+		return err
+	}
+	run.Checkout = &actions_model.RunCheckout{
+		Depth:  checkout.Depth,
+		Sparse: checkout.Sparse,
+		Filter: checkout.Filter,
+	}
+	return nil
+}
+
+// checkoutContext returns the checkout hints of the run for the runners, see generateTaskContext.
+// The bundle and the pack of the commit of the run could be downloaded with the token of the task instead of cloning the repository.
+func checkoutContext(run *actions_model.ActionRun) map[string]any {
+	apiLink := run.APIURL()
+	checkout := map[string]any{
+		"depth":        0,
+		"sparse":       []any{},
+		"filter":       "",
+		"bundle_url":   apiLink + "/bundle",
+		"packfile_url": apiLink + "/packfile",
+	}
+	if run.Checkout != nil {
+		sparse := make([]any, 0, len(run.Checkout.Sparse))
+		for _, p := range run.Checkout.Sparse {
+			sparse = append(sparse, p)
+		}
+		checkout["depth"] = run.Checkout.Depth
+		checkout["sparse"] = sparse
+		checkout["filter"] = run.Checkout.Filter
+	}
+	return checkout
+}
+
+// lfsContext returns the LFS server of the repository of the task with the authorization header for the runners, see generateTaskContext.
+// The token is created for the task by the LFS server, so the jobs could fetch the LFS objects without the access tokens of users.
+func lfsContext(ctx context.Context, t *actions_model.ActionTask) map[string]any {
+	if !setting.LFS.StartServer {
+		return map[string]any{"url": "", "authorization": ""}
+	}
+	token, err := lfs_service.CreateActionsTaskToken(ctx, t)
+	if err != nil {
+		log.Error("CreateActionsTaskToken of task %d: %v", t.ID, err)
+		return map[string]any{"url": "", "authorization": ""}
+	}
+	return map[string]any{
+		"url":           t.Job.Run.Repo.HTMLURL() + ".git/info/lfs",
+		"authorization": "Bearer " + token,
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckoutContext(t *testing.T) {
+	run := &actions_model.ActionRun{Index: 3, Repo: &repo_model.Repository{OwnerName: "user2", Name: "repo1"}}
+	checkout := checkoutContext(run)
+	assert.Equal(t, 0, checkout["depth"])
+	assert.Equal(t, setting.AppURL+"api/v1/repos/user2/repo1/actions/runs/3/packfile", checkout["packfile_url"])
+
+	require.NoError(t, prepareCheckout(run, []byte("# checkout:\n#   depth: 1\n#   sparse: services/api\non: push\n")))
+	checkout = checkoutContext(run)
+	assert.Equal(t, 1, checkout["depth"])
+	assert.Equal(t, []any{"services/api"}, checkout["sparse"])
+
+	assert.Error(t, prepareCheckout(run, []byte("# checkout:\n#   filter: unknown\non: push\n")))
+}
@@ -5,21 +5,254 @@ package actions
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // Cleanup removes expired actions logs, data and artifacts
 func Cleanup(taskCtx context.Context, olderThan time.Duration) error {
-	// TODO: clean up expired actions logs
+	retentions, err := repo_model.GetActionsConfigsOverridingRetention(taskCtx)
+	if err != nil {
+		return fmt.Errorf("GetActionsConfigsOverridingRetention: %w", err)
+	}
+
+	// clean up expired actions runs, before the logs so the logs of the deleted runs needn't to be expired
+	if err := CleanupRuns(taskCtx, retentions); err != nil {
+		log.Error("Cannot clean up actions runs: %v", err)
+	}
+
+	// clean up the runs which have expired regardless of the retentions, like the ones of the closed pull requests
+	if err := CleanupExpiredRuns(taskCtx); err != nil {
+		log.Error("Cannot clean up expired actions runs: %v", err)
+	}
+
+	// clean up expired actions logs
+	if err := CleanupLogs(taskCtx, retentions); err != nil {
+		log.Error("Cannot clean up actions logs: %v", err)
+	}
+
+	// clean up the handoff blobs of the runs which have been done for longer than the artifact retention
+	if err := CleanupHandoffBlobs(taskCtx); err != nil {
+		log.Error("Cannot clean up handoff blobs: %v", err)
+	}
+
+	// clean up the records of the runs which weren't created
+	if err := CleanupRunCreationFailures(taskCtx); err != nil {
+		log.Error("Cannot clean up the records of the runs which weren't created: %v", err)
+	}
+
+	// clean up the deliveries of the events which can't be duplicated anymore
+	if _, err := actions.DeleteRunDeliveriesBefore(taskCtx, timeutil.TimeStampNow().AddDuration(-actions.RunDeliveryWindow)); err != nil {
+		log.Error("Cannot clean up the deliveries of the events: %v", err)
+	}
 
 	// clean up expired artifacts
 	return CleanupArtifacts(taskCtx)
 }
 
+// cleanupLogsBatchSize is the batch size of cleaning up logs
+const cleanupLogsBatchSize = 100
+
+// retentionScope is the repositories sharing a retention period
+type retentionScope struct {
+	days           int64
+	repoID         int64   // 0 means all repositories except excludeRepoIDs
+	excludeRepoIDs []int64 // the repositories overriding the retention period
+}
+
+// retentionScopes returns the scopes of the retention periods: one for each repository with its own,
+// then the instance's one for the other repositories. The instance's scope is skipped if days is 0.
+// repoDays returns the retention days of the repository, 0 if it doesn't override the instance's one.
+func retentionScopes(days int64, retentions map[int64]*repo_model.ActionsConfig, repoDays func(*repo_model.ActionsConfig) int64) []retentionScope {
+	scopes := make([]retentionScope, 0, len(retentions)+1)
+	excludeRepoIDs := make([]int64, 0, len(retentions))
+	for repoID, cfg := range retentions {
+		if d := repoDays(cfg); d > 0 && d != days {
+			excludeRepoIDs = append(excludeRepoIDs, repoID)
+			scopes = append(scopes, retentionScope{days: d, repoID: repoID})
+		}
+	}
+	if days > 0 {
+		scopes = append(scopes, retentionScope{days: days, excludeRepoIDs: excludeRepoIDs})
+	}
+	return scopes
+}
+
+func (s retentionScope) options() actions.RetentionOptions {
+	return actions.RetentionOptions{
+		OlderThan:      timeutil.TimeStampNow().AddDuration(-time.Duration(s.days) * 24 * time.Hour),
+		RepoID:         s.repoID,
+		ExcludeRepoIDs: s.excludeRepoIDs,
+		Limit:          cleanupLogsBatchSize,
+	}
+}
+
+// CleanupLogs removes the logs of the tasks stopped before the retention period and marks them expired,
+// the tasks, jobs and runs are kept with their status and durations.
+// The repositories in retentions could have their own retention periods.
+func CleanupLogs(ctx context.Context, retentions map[int64]*repo_model.ActionsConfig) error {
+	count := 0
+	for _, scope := range retentionScopes(setting.Actions.LogRetentionDays, retentions, func(cfg *repo_model.ActionsConfig) int64 {
+		return cfg.LogRetentionDays
+	}) {
+		opts := scope.options()
+		for {
+			tasks, err := actions.FindOldTasksToExpire(ctx, opts)
+			if err != nil {
+				return fmt.Errorf("find old tasks: %w", err)
+			}
+			for _, task := range tasks {
+				opts.AfterID = task.ID
+				if err := actions_module.RemoveLogs(ctx, task.LogInStorage, task.LogFilename); err != nil {
+					log.Error("Cannot remove logs of task %d: %v", task.ID, err)
+					continue
+				}
+				task.LogIndexes = nil
+				task.LogExpired = true
+				if err := actions.UpdateTask(ctx, task, "log_indexes", "log_expired"); err != nil {
+					log.Error("Cannot set logs of task %d expired: %v", task.ID, err)
+					continue
+				}
+				count++
+			}
+			if len(tasks) < opts.Limit {
+				break
+			}
+		}
+	}
+	log.Info("Removed logs of %d tasks", count)
+	return nil
+}
+
+// CleanupRuns deletes the runs stopped before the retention period with their jobs, tasks, logs and artifacts.
+// The runs are kept forever if the retention period is 0.
+// The repositories in retentions could have their own retention periods.
+func CleanupRuns(ctx context.Context, retentions map[int64]*repo_model.ActionsConfig) error {
+	count := 0
+	for _, scope := range retentionScopes(setting.Actions.RunRetentionDays, retentions, func(cfg *repo_model.ActionsConfig) int64 {
+		return cfg.RunRetentionDays
+	}) {
+		opts := scope.options()
+		for {
+			runs, err := actions.FindOldRunsToDelete(ctx, opts)
+			if err != nil {
+				return fmt.Errorf("find old runs: %w", err)
+			}
+			for _, run := range runs {
+				opts.AfterID = run.ID
+				if err := deleteRun(ctx, run); err != nil {
+					log.Error("Cannot delete run %d: %v", run.ID, err)
+					continue
+				}
+				count++
+			}
+			if len(runs) < opts.Limit {
+				break
+			}
+		}
+	}
+	log.Info("Deleted %d runs", count)
+	return nil
+}
+
+// CleanupExpiredRuns deletes the done runs which have expired regardless of the retentions,
+// like the ones of the pull requests closed without merging, see actions.SetPullRequestRunsExpired
+func CleanupExpiredRuns(ctx context.Context) error {
+	count := 0
+	opts := actions.RetentionOptions{OlderThan: timeutil.TimeStampNow(), Limit: cleanupLogsBatchSize}
+	for {
+		runs, err := actions.FindExpiredRunsToDelete(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("find expired runs: %w", err)
+		}
+		for _, run := range runs {
+			opts.AfterID = run.ID
+			if err := deleteRun(ctx, run); err != nil {
+				log.Error("Cannot delete run %d: %v", run.ID, err)
+				continue
+			}
+			count++
+		}
+		if len(runs) < opts.Limit {
+			break
+		}
+	}
+	log.Info("Deleted %d expired runs", count)
+	return nil
+}
+
+// DeleteRun deletes the run with its data and files, the run must be done, so the runs which aren't should be cancelled before
+func DeleteRun(ctx context.Context, run *actions.ActionRun) error {
+	if !run.Status.IsDone() {
+		return util.NewInvalidArgumentErrorf("run %d isn't done", run.ID)
+	}
+	return deleteRun(ctx, run)
+}
+
+// deleteRun deletes the run with its data, then removes its files from the storages
+func deleteRun(ctx context.Context, run *actions.ActionRun) error {
+	jobs, err := actions.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+	var tasks []*actions.ActionTask
+	if len(jobs) > 0 {
+		jobIDs := make([]int64, 0, len(jobs))
+		for _, job := range jobs {
+			jobIDs = append(jobIDs, job.ID)
+		}
+		if err := db.GetEngine(ctx).In("job_id", jobIDs).Find(&tasks); err != nil {
+			return fmt.Errorf("find tasks: %w", err)
+		}
+	}
+	artifacts, err := db.Find[actions.ActionArtifact](ctx, actions.FindArtifactsOptions{RunID: run.ID})
+	if err != nil {
+		return fmt.Errorf("find artifacts: %w", err)
+	}
+	handoffBlobs, err := db.Find[actions.ActionHandoffBlob](ctx, actions.FindHandoffBlobOptions{RunID: run.ID})
+	if err != nil {
+		return fmt.Errorf("find handoff blobs: %w", err)
+	}
+
+	if err := actions.DeleteRun(ctx, run); err != nil {
+		return err
+	}
+
+	// the records have been deleted, so the files are removed even if some of them fail
+	for _, task := range tasks {
+		if task.LogExpired {
+			continue
+		}
+		if err := actions_module.RemoveLogs(ctx, task.LogInStorage, task.LogFilename); err != nil {
+			log.Error("Cannot remove logs of task %d: %v", task.ID, err)
+		}
+	}
+	for _, artifact := range artifacts {
+		if artifact.Status == int64(actions.ArtifactStatusExpired) || artifact.Status == int64(actions.ArtifactStatusDeleted) {
+			continue
+		}
+		if err := storage.ActionsArtifacts.Delete(artifact.StoragePath); err != nil {
+			log.Error("Cannot delete artifact %d: %v", artifact.ID, err)
+		}
+	}
+	for _, blob := range handoffBlobs {
+		if err := storage.ActionsArtifacts.Delete(blob.StoragePath); err != nil {
+			log.Error("Cannot delete handoff blob %d: %v", blob.ID, err)
+		}
+	}
+	return nil
+}
+
 // CleanupArtifacts removes expired add need-deleted artifacts and set records expired status
 func CleanupArtifacts(taskCtx context.Context) error {
 	if err := cleanExpiredArtifacts(taskCtx); err != nil {
@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionScopes(t *testing.T) {
+	retentions := map[int64]*repo_model.ActionsConfig{
+		1: {RunRetentionDays: 7},
+		2: {LogRetentionDays: 7},
+	}
+	getDays := func(cfg *repo_model.ActionsConfig) int64 { return cfg.RunRetentionDays }
+
+	// the runs are kept forever by default, only the repository with its own retention is cleaned up
+	scopes := retentionScopes(0, retentions, getDays)
+	assert.Equal(t, []retentionScope{{days: 7, repoID: 1}}, scopes)
+
+	scopes = retentionScopes(30, retentions, getDays)
+	assert.Equal(t, []retentionScope{{days: 7, repoID: 1}, {days: 30, excludeRepoIDs: []int64{1}}}, scopes)
+}
+
+func TestCleanupRuns(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// the runs are kept forever by default
+	require.NoError(t, CleanupRuns(ctx, nil))
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+
+	// the repository keeps its runs longer than the instance
+	defer test.MockVariableValue(&setting.Actions.RunRetentionDays, int64(30))()
+	require.NoError(t, CleanupRuns(ctx, map[int64]*repo_model.ActionsConfig{4: {RunRetentionDays: 100000}}))
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+
+	require.NoError(t, CleanupRuns(ctx, nil))
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRun{ID: 791})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRun{ID: 792})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRunJob{RunID: 791})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionTask{ID: 47})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionTaskStep{TaskID: 47})
+}
+
+func TestCleanupLogs(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	defer test.MockVariableValue(&setting.Actions.LogRetentionDays, int64(365))()
+
+	// the repository keeps its logs longer than the instance
+	require.NoError(t, CleanupLogs(ctx, map[int64]*repo_model.ActionsConfig{4: {LogRetentionDays: 100000}}))
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	assert.False(t, task.LogExpired)
+
+	require.NoError(t, CleanupLogs(ctx, nil))
+	for _, id := range []int64{47, 48} {
+		task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: id})
+		assert.True(t, task.LogExpired)
+		assert.Empty(t, task.LogIndexes)
+	}
+	// only the logs are removed, the runs are kept with their jobs
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+
+	// the expired tasks aren't found again
+	tasks, err := actions_model.FindOldTasksToExpire(ctx, actions_model.RetentionOptions{OlderThan: timeutil.TimeStampNow(), Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestCleanupExpiredRuns(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	_, err := db.GetEngine(ctx).Exec("UPDATE `action_run` SET ref = ? WHERE id = ?", "refs/pull/9/head", 791)
+	require.NoError(t, err)
+	require.NoError(t, actions_model.SetPullRequestRunsExpired(ctx, 4, "refs/pull/9/head", timeutil.TimeStampNow().Add(-1)))
+
+	require.NoError(t, CleanupExpiredRuns(ctx))
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRun{ID: 791})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRunJob{RunID: 791})
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 792})
+}
+
+func TestDeleteRun(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	require.NoError(t, DeleteRun(ctx, run))
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRun{ID: 791})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRunJob{RunID: 791})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionTask{ID: 47})
+
+	// the runs which aren't done should be cancelled before
+	run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 792})
+	run.Status = actions_model.StatusRunning
+	assert.ErrorIs(t, DeleteRun(ctx, run), util.ErrInvalidArgument)
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 792})
+}
@@ -18,10 +18,11 @@ import (
 
 // StopZombieTasks stops the task which have running status, but haven't been updated for a long time
 func StopZombieTasks(ctx context.Context) error {
+	// the runners don't update the tasks when they are lost
 	return stopTasks(ctx, actions_model.FindTaskOptions{
 		Status:        actions_model.StatusRunning,
 		UpdatedBefore: timeutil.TimeStamp(time.Now().Add(-setting.Actions.ZombieTaskTimeout).Unix()),
-	})
+	}, actions_model.TaskErrorClassInfrastructure)
 }
 
 // StopEndlessTasks stops the tasks which have running status and continuous updates, but don't end for a long time
@@ -29,10 +30,10 @@ func StopEndlessTasks(ctx context.Context) error {
 	return stopTasks(ctx, actions_model.FindTaskOptions{
 		Status:        actions_model.StatusRunning,
 		StartedBefore: timeutil.TimeStamp(time.Now().Add(-setting.Actions.EndlessTaskTimeout).Unix()),
-	})
+	}, actions_model.TaskErrorClassUser)
 }
 
-func stopTasks(ctx context.Context, opts actions_model.FindTaskOptions) error {
+func stopTasks(ctx context.Context, opts actions_model.FindTaskOptions, errorClass string) error {
 	tasks, err := db.Find[actions_model.ActionTask](ctx, opts)
 	if err != nil {
 		return fmt.Errorf("find tasks: %w", err)
@@ -41,18 +42,25 @@ func stopTasks(ctx context.Context, opts actions_model.FindTaskOptions) error {
 	jobs := make([]*actions_model.ActionRunJob, 0, len(tasks))
 	for _, task := range tasks {
 		if err := db.WithTx(ctx, func(ctx context.Context) error {
-			if err := actions_model.StopTask(ctx, task.ID, actions_model.StatusFailure); err != nil {
+			if err := actions_model.StopTaskWithErrorClass(ctx, task.ID, actions_model.StatusFailure, errorClass); err != nil {
 				return err
 			}
-			if err := task.LoadJob(ctx); err != nil {
-				return err
-			}
-			jobs = append(jobs, task.Job)
-			return nil
+			return task.LoadJob(ctx)
 		}); err != nil {
 			log.Warn("Cannot stop task %v: %v", task.ID, err)
 			continue
 		}
+		retried := false
+		if errorClass == actions_model.TaskErrorClassInfrastructure {
+			// the runner is lost, the spot runners could disappear at any time
+			if retried, err = retryJobLostWithSpotRunner(ctx, task); err != nil {
+				log.Warn("Cannot retry job of task %v lost with spot runner: %v", task.ID, err)
+			}
+		}
+		if !retried {
+			// the retried job is waiting again, its status has been created by the retry
+			jobs = append(jobs, task.Job)
+		}
 
 		remove, err := actions.TransferLogs(ctx, task.LogFilename)
 		if err != nil {
@@ -0,0 +1,57 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// handleClosedPullRequestRuns cancels the runs of the pull request closed without merging and shortens their retention,
+// or keeps them by the retentions again if it's reopened, as configured by the repository,
+// see repo_model.ActionsConfig.ClosedPullRequestRuns and ClosedPullRequestRetentionDays.
+// The runs of the pull_request_target events are on the base branch, so they aren't affected.
+func handleClosedPullRequestRuns(ctx context.Context, repo *repo_model.Repository, pr *issues_model.PullRequest, isClosed bool) {
+	if pr.HasMerged {
+		return
+	}
+	actionsUnit, err := repo.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		if !repo_model.IsErrUnitTypeNotExist(err) {
+			log.Error("GetUnit: %v", err)
+		}
+		return
+	}
+	cfg := actionsUnit.ActionsConfig()
+	ref := pr.GetGitRefName()
+
+	if !isClosed {
+		if err := actions_model.SetPullRequestRunsExpired(ctx, repo.ID, ref, 0); err != nil {
+			log.Error("SetPullRequestRunsExpired: %v", err)
+		}
+		return
+	}
+
+	if cancel := cfg.GetClosedPullRequestRuns(); cancel != repo_model.ActionsClosedPullRequestRunsNone {
+		jobs, err := actions_model.CancelPullRequestRuns(ctx, repo.ID, ref, cancel == repo_model.ActionsClosedPullRequestRunsAll)
+		if err != nil {
+			log.Error("CancelPullRequestRuns: %v", err)
+		} else {
+			CreateCommitStatus(ctx, jobs...)
+		}
+	}
+	if days := cfg.ClosedPullRequestRetentionDays; days > 0 {
+		expired := timeutil.TimeStampNow().AddDuration(time.Duration(days) * 24 * time.Hour)
+		if err := actions_model.SetPullRequestRunsExpired(ctx, repo.ID, ref, expired); err != nil {
+			log.Error("SetPullRequestRunsExpired: %v", err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+)
+
+// CommitGatingState is what happened to a workflow expected to run for a commit
+type CommitGatingState string
+
+const (
+	CommitGatingRan                    CommitGatingState = "ran"
+	CommitGatingSkippedByPathFilter    CommitGatingState = "skipped_by_path_filter"    // the push matches the branches and tags filters but not the paths filters
+	CommitGatingSkippedByCommitMessage CommitGatingState = "skipped_by_commit_message" // see [actions] SKIP_WORKFLOW_STRINGS
+	CommitGatingDisabled               CommitGatingState = "disabled"                  // the workflow is disabled in the repository
+	CommitGatingFailedToTrigger        CommitGatingState = "failed_to_trigger"         // the run wasn't created, see ActionRunCreationFailure
+	// the push matches the workflow but neither a run nor a failure was recorded,
+	// like the commit wasn't the head of a push or Actions were disabled when it was pushed
+	CommitGatingMissing CommitGatingState = "missing"
+)
+
+// CommitGatingWorkflow is a workflow expected to run for a commit, or which has run or failed to be triggered for it
+type CommitGatingWorkflow struct {
+	WorkflowID string
+	Event      string // empty if the workflow is invalid, so its events are unknown
+	State      CommitGatingState
+	Run        *actions_model.ActionRun                // the latest run of the workflow for the event, only if the state is ran
+	Failure    *actions_model.ActionRunCreationFailure // the latest failure of the workflow for the event, only if the state is failed_to_trigger
+}
+
+// CommitGating is the summary of the workflows of a commit, to debug the required checks of the commit
+type CommitGating struct {
+	Ref       string
+	CommitID  string
+	Workflows []*CommitGatingWorkflow
+}
+
+// GetCommitGating reports every workflow expected to run for the push of the commit to the ref, whether it ran and why it didn't,
+// followed by the runs and the failures of the other events of the commit, like its pull requests.
+// The ref is the default branch if it's empty. The push is regarded as of the commit alone, so the paths filters are matched
+// with the files changed since its first parent.
+func GetCommitGating(ctx context.Context, repo *repo_model.Repository, ref, sha string) (*CommitGating, error) {
+	if repo.IsEmpty {
+		return nil, util.NewInvalidArgumentErrorf("repository %s is empty", repo.FullName())
+	}
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, util.NewNotExistErrorf("commit %s doesn't exist", sha)
+		}
+		return nil, fmt.Errorf("GetCommit: %w", err)
+	}
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	gating := &CommitGating{
+		Ref:       fullRefName(gitRepo, ref),
+		CommitID:  commit.ID.String(),
+		Workflows: []*CommitGatingWorkflow{},
+	}
+
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		RepoID:    repo.ID,
+		CommitSHA: gating.CommitID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FindRuns: %w", err)
+	}
+	for _, run := range runs {
+		run.Repo = repo
+	}
+	failures, err := db.Find[actions_model.ActionRunCreationFailure](ctx, actions_model.FindRunCreationFailuresOptions{
+		RepoID:    repo.ID,
+		CommitSHA: gating.CommitID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FindRunCreationFailures: %w", err)
+	}
+	// both are the latest first
+	usedRuns := make(map[int64]bool, len(runs))
+	usedFailures := make(map[int64]bool, len(failures))
+	latestPushRun := func(workflowID string) *actions_model.ActionRun {
+		for _, run := range runs {
+			if run.WorkflowID == workflowID && run.TriggerEvent == string(webhook_module.HookEventPush) && run.Ref == gating.Ref {
+				return run
+			}
+		}
+		return nil
+	}
+	latestPushFailure := func(workflowID string) *actions_model.ActionRunCreationFailure {
+		for _, f := range failures {
+			// the failures of no workflows are of the whole event, like the actor is blocked
+			if (f.WorkflowID == workflowID || f.WorkflowID == "") && f.Event == webhook_module.HookEventPush && f.Ref == gating.Ref {
+				return f
+			}
+		}
+		return nil
+	}
+
+	var actionsConfig *repo_model.ActionsConfig
+	if err := repo.LoadUnits(ctx); err != nil {
+		return nil, err
+	}
+	if actionsUnit, err := repo.GetUnit(ctx, unit_model.TypeActions); err == nil {
+		actionsConfig = actionsUnit.ActionsConfig()
+	}
+	input := newNotifyInput(repo, nil, webhook_module.HookEventPush).WithRef(gating.Ref)
+	skipped := skipWorkflows(input, commit)
+	before := commit.ID.Type().EmptyObjectID().String()
+	if parent, err := commit.ParentID(0); err == nil {
+		before = parent.String()
+	}
+	payload := &api.PushPayload{Ref: gating.Ref, Before: before, After: gating.CommitID}
+
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return nil, fmt.Errorf("ListWorkflows: %w", err)
+	}
+	for _, entry := range entries {
+		content, err := actions_module.GetContentFromEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("GetContentFromEntry: %w", err)
+		}
+		events, err := actions_module.GetEventsFromContent(content)
+		if err != nil {
+			// it's unknown whether the workflow would be triggered, it's reported since it couldn't run for any events
+			wf := &CommitGatingWorkflow{WorkflowID: entry.Name(), State: CommitGatingFailedToTrigger}
+			for _, f := range failures {
+				if f.WorkflowID == entry.Name() && f.Reason == actions_model.RunCreationFailureInvalidWorkflow {
+					if wf.Failure == nil {
+						wf.Failure = f
+					}
+					usedFailures[f.ID] = true
+				}
+			}
+			if wf.Failure == nil {
+				wf.Failure = &actions_model.ActionRunCreationFailure{
+					RepoID:     repo.ID,
+					WorkflowID: entry.Name(),
+					Ref:        gating.Ref,
+					CommitSHA:  gating.CommitID,
+					Reason:     actions_model.RunCreationFailureInvalidWorkflow,
+					Message:    err.Error(),
+				}
+			}
+			gating.Workflows = append(gating.Workflows, wf)
+			continue
+		}
+
+		for _, evt := range events {
+			if evt.Name != actions_module.GithubEventPush {
+				continue
+			}
+			matched, filteredByPaths := actions_module.MatchPushEvent(commit, payload, evt)
+			wf := &CommitGatingWorkflow{WorkflowID: entry.Name(), Event: string(webhook_module.HookEventPush)}
+			if run := latestPushRun(entry.Name()); run != nil {
+				wf.State = CommitGatingRan
+				wf.Run = run
+				usedRuns[run.ID] = true
+			} else if f := latestPushFailure(entry.Name()); f != nil {
+				wf.State = CommitGatingFailedToTrigger
+				wf.Failure = f
+				usedFailures[f.ID] = true
+			} else if !matched {
+				if !filteredByPaths {
+					// the workflow isn't expected to run
+					break
+				}
+				wf.State = CommitGatingSkippedByPathFilter
+			} else if actionsConfig != nil && actionsConfig.IsWorkflowDisabled(entry.Name()) {
+				wf.State = CommitGatingDisabled
+			} else if skipped {
+				wf.State = CommitGatingSkippedByCommitMessage
+			} else {
+				wf.State = CommitGatingMissing
+			}
+			gating.Workflows = append(gating.Workflows, wf)
+			break
+		}
+	}
+
+	// the runs and the failures of the other events, or of the workflows which have been changed or removed since the commit
+	seen := make(map[[2]string]bool)
+	for _, wf := range gating.Workflows {
+		seen[[2]string{wf.WorkflowID, wf.Event}] = true
+	}
+	for _, run := range runs {
+		key := [2]string{run.WorkflowID, run.TriggerEvent}
+		if usedRuns[run.ID] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		gating.Workflows = append(gating.Workflows, &CommitGatingWorkflow{
+			WorkflowID: run.WorkflowID,
+			Event:      run.TriggerEvent,
+			State:      CommitGatingRan,
+			Run:        run,
+		})
+	}
+	for _, f := range failures {
+		key := [2]string{f.WorkflowID, string(f.Event)}
+		if usedFailures[f.ID] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		gating.Workflows = append(gating.Workflows, &CommitGatingWorkflow{
+			WorkflowID: f.WorkflowID,
+			Event:      string(f.Event),
+			State:      CommitGatingFailedToTrigger,
+			Failure:    f,
+		})
+	}
+	return gating, nil
+}
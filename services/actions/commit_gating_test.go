@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCommitGating(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	const sha = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	gating, err := GetCommitGating(ctx, repo, "", sha)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/master", gating.Ref)
+	assert.Equal(t, sha, gating.CommitID)
+	assert.Empty(t, gating.Workflows)
+
+	// the commit has no workflows, so only the recorded runs and failures are reported
+	require.NoError(t, db.Insert(ctx, &actions_model.ActionRun{
+		RepoID:       1,
+		OwnerID:      repo.OwnerID,
+		Index:        1000,
+		WorkflowID:   "test.yaml",
+		Ref:          "refs/heads/master",
+		CommitSHA:    sha,
+		TriggerEvent: string(webhook_module.HookEventPush),
+		Status:       actions_model.StatusSuccess,
+	}))
+	require.NoError(t, actions_model.InsertRunCreationFailure(ctx, &actions_model.ActionRunCreationFailure{
+		RepoID:     1,
+		WorkflowID: "pr.yaml",
+		Event:      webhook_module.HookEventPullRequest,
+		Ref:        "refs/pull/1/head",
+		CommitSHA:  sha,
+		Reason:     actions_model.RunCreationFailureQuotaExceeded,
+		Message:    "too many runs",
+	}))
+	gating, err = GetCommitGating(ctx, repo, "master", sha)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/master", gating.Ref)
+	if assert.Len(t, gating.Workflows, 2) {
+		assert.Equal(t, "test.yaml", gating.Workflows[0].WorkflowID)
+		assert.Equal(t, CommitGatingRan, gating.Workflows[0].State)
+		assert.Equal(t, actions_model.StatusSuccess, gating.Workflows[0].Run.Status)
+		assert.Equal(t, "pr.yaml", gating.Workflows[1].WorkflowID)
+		assert.Equal(t, "pull_request", gating.Workflows[1].Event)
+		assert.Equal(t, CommitGatingFailedToTrigger, gating.Workflows[1].State)
+		assert.Equal(t, actions_model.RunCreationFailureQuotaExceeded, gating.Workflows[1].Failure.Reason)
+	}
+
+	_, err = GetCommitGating(ctx, repo, "", "0000000000000000000000000000000000000001")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
@@ -11,6 +11,8 @@ import (
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	git "code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
@@ -29,6 +31,8 @@ func CreateCommitStatus(ctx context.Context, jobs ...*actions_model.ActionRunJob
 			log.Error("Failed to create commit status for job %d: %v", job.ID, err)
 		}
 	}
+	// it's called whenever the statuses of jobs change, so the events of the jobs are published here too
+	publishJobEvents(ctx, jobs...)
 }
 
 func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) error {
@@ -37,6 +41,14 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 	}
 
 	run := job.Run
+	cfg := &repo_model.ActionsConfig{}
+	if actionsUnit, err := run.Repo.GetUnit(ctx, unit_model.TypeActions); err == nil {
+		cfg = actionsUnit.ActionsConfig()
+	}
+	if cfg.IsWorkflowStatusExcluded(run.WorkflowID) {
+		// the workflow is informational, its jobs shouldn't affect the checks of the commit
+		return nil
+	}
 
 	var (
 		sha   string
@@ -69,13 +81,20 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 		event = string(run.Event)
 		sha = run.CommitSHA
 	default:
-		return nil
+		if !run.IsExternal() {
+			return nil
+		}
+		// the statuses of external runs are reported against the commit and named after the external system
+		event = run.ExternalSystem
+		sha = run.CommitSHA
 	}
 
 	repo := run.Repo
 	// TODO: store workflow name as a field in ActionRun to avoid parsing
 	runName := path.Base(run.WorkflowID)
-	if wfs, err := jobparser.Parse(job.WorkflowPayload); err == nil && len(wfs) > 0 {
+	if run.IsExternal() {
+		runName = run.Title
+	} else if wfs, err := jobparser.Parse(job.WorkflowPayload); err == nil && len(wfs) > 0 {
 		runName = wfs[0].Name
 	}
 	ctxname := fmt.Sprintf("%s / %s (%s)", runName, job.Name, event)
@@ -123,16 +142,22 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 	if err != nil {
 		return fmt.Errorf("HashTypeInterfaceFromHashString: %w", err)
 	}
-	if err := commitstatus_service.CreateCommitStatus(ctx, repo, creator, commitID.String(), &git_model.CommitStatus{
+	targetURL, peerTargetURL := run.JobLink(index), run.HTMLURL()+actions_model.JobPath(index)
+	if job.ExternalURL != "" {
+		targetURL, peerTargetURL = job.ExternalURL, job.ExternalURL
+	}
+	status := &git_model.CommitStatus{
 		SHA:         sha,
-		TargetURL:   fmt.Sprintf("%s/jobs/%d", run.Link(), index),
+		TargetURL:   targetURL,
 		Description: description,
 		Context:     ctxname,
 		CreatorID:   creator.ID,
 		State:       state,
-	}); err != nil {
+	}
+	if err := commitstatus_service.CreateCommitStatus(ctx, repo, creator, commitID.String(), status); err != nil {
 		return fmt.Errorf("NewCommitStatus: %w", err)
 	}
+	pushFederatedStatus(repo, cfg, sha, peerTargetURL, status)
 
 	return nil
 }
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCommitStatusOfExcludedWorkflow(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	const sha = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	createJob := func(t *testing.T, index int64, workflowID string) *actions_model.ActionRunJob {
+		run := &actions_model.ActionRun{
+			Index:         index,
+			Title:         "release",
+			RepoID:        repo.ID,
+			OwnerID:       repo.OwnerID,
+			WorkflowID:    workflowID,
+			TriggerUserID: 2,
+			Ref:           "refs/tags/v1.0",
+			CommitSHA:     sha,
+			Event:         webhook_module.HookEventRelease,
+			Status:        actions_model.StatusSuccess,
+		}
+		require.NoError(t, db.Insert(ctx, run))
+		job := &actions_model.ActionRunJob{
+			RunID:     run.ID,
+			RepoID:    repo.ID,
+			OwnerID:   repo.OwnerID,
+			CommitSHA: sha,
+			Name:      "build",
+			JobID:     "build",
+			Status:    actions_model.StatusSuccess,
+		}
+		require.NoError(t, db.Insert(ctx, job))
+		return job
+	}
+	countStatuses := func(t *testing.T, context string) int64 {
+		count, err := db.GetEngine(ctx).Where("repo_id = ? AND sha = ? AND context = ?", repo.ID, sha, context).Count(new(git_model.CommitStatus))
+		require.NoError(t, err)
+		return count
+	}
+
+	actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	require.NoError(t, err)
+	actionsUnit.ActionsConfig().StatusExcludedWorkflows = []string{"informational.yml"}
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+
+	assert.NoError(t, createCommitStatus(ctx, createJob(t, 1001, "informational.yml")))
+	assert.EqualValues(t, 0, countStatuses(t, "informational.yml / build (release)"))
+
+	assert.NoError(t, createCommitStatus(ctx, createJob(t, 1002, "release.yml")))
+	assert.EqualValues(t, 1, countStatuses(t, "release.yml / build (release)"))
+}
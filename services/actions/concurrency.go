@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// prepareConcurrency evaluates the workflow-level concurrency of the new run,
+// and cancels the runs of its group which shouldn't continue, see actions_model.CancelConcurrentRuns.
+func prepareConcurrency(ctx context.Context, run *actions_model.ActionRun, content []byte, vars map[string]string) error {
+	concurrency, err := actions_module.ParseWorkflowConcurrency(content)
+	if err != nil || concurrency == nil {
+		return err
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+
+	gitCtx, event, err := newConcurrencyGithubContext(run)
+	if err != nil {
+		return err
+	}
+	inputs, _ := event["inputs"].(map[string]any)
+	run.ConcurrencyGroup, run.ConcurrencyCancel, err = concurrency.Evaluate(gitCtx, vars, inputs)
+	if err != nil {
+		return err
+	}
+	if err := actions_model.CancelConcurrentRuns(ctx, run); err != nil {
+		return fmt.Errorf("CancelConcurrentRuns: %w", err)
+	}
+	return nil
+}
+
+// newConcurrencyGithubContext returns the github context of the run which is available before the run is inserted,
+// it's a subset of the context of the tasks, see generateTaskContext.
+func newConcurrencyGithubContext(run *actions_model.ActionRun) (*model.GithubContext, map[string]any, error) {
+	event := map[string]any{}
+	payload, err := run.GetEventPayload()
+	if err != nil {
+		return nil, nil, err
+	}
+	_ = json.Unmarshal([]byte(payload), &event)
+
+	eventName := run.TriggerEvent
+	if eventName == "" {
+		eventName = run.Event.Event()
+	}
+	var baseRef, headRef string
+	ref, sha := run.Ref, run.CommitSHA
+	if pullPayload, err := run.GetPullRequestEventPayload(); err == nil && pullPayload.PullRequest != nil && pullPayload.PullRequest.Base != nil && pullPayload.PullRequest.Head != nil {
+		baseRef = pullPayload.PullRequest.Base.Ref
+		headRef = pullPayload.PullRequest.Head.Ref
+		if run.TriggerEvent == actions_module.GithubEventPullRequestTarget {
+			ref = git.BranchPrefix + pullPayload.PullRequest.Base.Name
+			sha = pullPayload.PullRequest.Base.Sha
+		}
+	}
+	refName := git.RefName(ref)
+
+	return &model.GithubContext{
+		Event:           event,
+		Workflow:        run.WorkflowID,
+		Actor:           run.TriggerUser.Name,
+		Repository:      run.Repo.OwnerName + "/" + run.Repo.Name,
+		RepositoryOwner: run.Repo.OwnerName,
+		EventName:       eventName,
+		Sha:             sha,
+		Ref:             ref,
+		RefName:         refName.ShortName(),
+		RefType:         refName.RefType(),
+		HeadRef:         headRef,
+		BaseRef:         baseRef,
+		ServerURL:       setting.AppURL,
+		APIURL:          setting.AppURL + "api/v1",
+	}, event, nil
+}
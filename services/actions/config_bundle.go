@@ -0,0 +1,208 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/convert"
+	secret_service "code.gitea.io/gitea/services/secrets"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigBundleVersion is the version of the format of the Actions configuration bundles
+const ConfigBundleVersion = 1
+
+// ExportConfigBundle exports the Actions configuration of the repository: its settings if Actions are enabled,
+// its variables and its dispatch presets. The secrets and the federation aren't exported.
+func ExportConfigBundle(ctx context.Context, repo *repo_model.Repository) (*api.ActionsConfigBundle, error) {
+	bundle := &api.ActionsConfigBundle{
+		Version:         ConfigBundleVersion,
+		Repository:      repo.FullName(),
+		ExportedAt:      util.ToPointer(time.Now().UTC().Truncate(time.Second)),
+		Variables:       []*api.ActionsConfigBundleVariable{},
+		DispatchPresets: []*api.ActionsConfigBundleDispatchPreset{},
+	}
+
+	actionsUnit, err := repo.GetUnit(ctx, unit_model.TypeActions)
+	if err == nil {
+		bundle.Settings = convert.ToActionsConfigBundleSettings(actionsUnit.ActionsConfig())
+	} else if !repo_model.IsErrUnitTypeNotExist(err) {
+		return nil, fmt.Errorf("GetUnit: %w", err)
+	}
+
+	variables, err := actions_model.FindVariables(ctx, actions_model.FindVariablesOpts{RepoID: repo.ID})
+	if err != nil {
+		return nil, fmt.Errorf("FindVariables: %w", err)
+	}
+	slices.SortFunc(variables, func(a, b *actions_model.ActionVariable) int { return strings.Compare(a.Name, b.Name) })
+	for _, v := range variables {
+		bundle.Variables = append(bundle.Variables, &api.ActionsConfigBundleVariable{Name: v.Name, Value: v.Data})
+	}
+
+	presets, err := actions_model.GetDispatchPresets(ctx, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("GetDispatchPresets: %w", err)
+	}
+	for _, p := range presets {
+		bundle.DispatchPresets = append(bundle.DispatchPresets, &api.ActionsConfigBundleDispatchPreset{
+			Name:       p.Name,
+			WorkflowID: p.WorkflowID,
+			Ref:        p.Ref,
+			Inputs:     p.Inputs,
+		})
+	}
+	return bundle, nil
+}
+
+// MarshalConfigBundle encodes the bundle as a YAML document with the same keys as the API,
+// the settings which are null are left out since the import keeps them anyway
+func MarshalConfigBundle(bundle *api.ActionsConfigBundle) ([]byte, error) {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is YAML, decoding it into a node keeps the order of the fields
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	toBlockYAML(&node)
+	return yaml.Marshal(&node)
+}
+
+// toBlockYAML drops the null values of the mappings and resets the flow style of the nodes decoded from JSON
+func toBlockYAML(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.MappingNode {
+		content := make([]*yaml.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i+1].Tag != "!!null" {
+				content = append(content, node.Content[i], node.Content[i+1])
+			}
+		}
+		node.Content = content
+	}
+	for _, n := range node.Content {
+		toBlockYAML(n)
+	}
+}
+
+// UnmarshalConfigBundle decodes a bundle encoded by MarshalConfigBundle, or written by hand with the same keys
+func UnmarshalConfigBundle(data []byte) (*api.ActionsConfigBundle, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid bundle: %v", err)
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return nil, util.NewInvalidArgumentErrorf("invalid bundle: it must be a mapping")
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid bundle: %v", err)
+	}
+	bundle := &api.ActionsConfigBundle{}
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid bundle: %v", err)
+	}
+	if bundle.Version != ConfigBundleVersion {
+		return nil, util.NewInvalidArgumentErrorf("unsupported version %d of the bundle, only %d is supported", bundle.Version, ConfigBundleVersion)
+	}
+	return bundle, nil
+}
+
+// ImportConfigBundle imports the variables and the dispatch presets of the bundle into the repository,
+// the settings are imported by the caller with the same checks as editing them.
+// The variables are checked before any of them is written, while a preset which couldn't dispatch its workflow
+// in the repository is skipped, since the workflows may be pushed after the import.
+func ImportConfigBundle(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, bundle *api.ActionsConfigBundle) (*api.ActionsConfigImport, error) {
+	res := &api.ActionsConfigImport{
+		VariablesCreated:       []string{},
+		VariablesUpdated:       []string{},
+		DispatchPresetsSaved:   []string{},
+		DispatchPresetsSkipped: []*api.ActionsConfigImportSkipped{},
+	}
+
+	names := make(map[string]bool, len(bundle.Variables))
+	for _, v := range bundle.Variables {
+		if v == nil {
+			return nil, util.NewInvalidArgumentErrorf("the variables of the bundle can't be null")
+		}
+		if err := secret_service.ValidateName(v.Name); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid variable %q: %v", v.Name, err)
+		}
+		if err := envNameCIRegexMatch(v.Name); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid variable %q: %v", v.Name, err)
+		}
+		name := strings.ToUpper(v.Name)
+		if names[name] {
+			return nil, util.NewInvalidArgumentErrorf("duplicate variable %q", name)
+		}
+		names[name] = true
+	}
+	for _, p := range bundle.DispatchPresets {
+		if p == nil || p.Name == "" || p.WorkflowID == "" {
+			return nil, util.NewInvalidArgumentErrorf("the name and the workflow of a dispatch preset are required")
+		}
+		if len(p.Name) > 255 || len(p.WorkflowID) > 255 || len(p.Ref) > 255 {
+			return nil, util.NewInvalidArgumentErrorf("the name, the workflow and the ref of dispatch preset %q can't be longer than 255 characters", p.Name)
+		}
+	}
+
+	existing, err := actions_model.FindVariables(ctx, actions_model.FindVariablesOpts{RepoID: repo.ID})
+	if err != nil {
+		return nil, fmt.Errorf("FindVariables: %w", err)
+	}
+	existingByName := make(map[string]*actions_model.ActionVariable, len(existing))
+	for _, v := range existing {
+		existingByName[v.Name] = v
+	}
+	for _, v := range bundle.Variables {
+		name := strings.ToUpper(v.Name)
+		if old, ok := existingByName[name]; ok {
+			if old.Data == util.ReserveLineBreakForTextarea(v.Value) {
+				continue
+			}
+			if _, err := UpdateVariable(ctx, old.ID, name, v.Value); err != nil {
+				return nil, fmt.Errorf("UpdateVariable: %w", err)
+			}
+			res.VariablesUpdated = append(res.VariablesUpdated, name)
+			continue
+		}
+		if _, err := CreateVariable(ctx, 0, repo.ID, name, v.Value); err != nil {
+			return nil, fmt.Errorf("CreateVariable: %w", err)
+		}
+		res.VariablesCreated = append(res.VariablesCreated, name)
+	}
+
+	for _, p := range bundle.DispatchPresets {
+		if _, err := SaveDispatchPreset(ctx, repo, doer, &actions_model.ActionDispatchPreset{
+			Name:       p.Name,
+			WorkflowID: p.WorkflowID,
+			Ref:        p.Ref,
+			Inputs:     p.Inputs,
+		}); err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				res.DispatchPresetsSkipped = append(res.DispatchPresetsSkipped, &api.ActionsConfigImportSkipped{Name: p.Name, Reason: err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("SaveDispatchPreset: %w", err)
+		}
+		res.DispatchPresetsSaved = append(res.DispatchPresetsSaved, p.Name)
+	}
+	return res, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigBundleYAML(t *testing.T) {
+	bundle := &api.ActionsConfigBundle{
+		Version:    ConfigBundleVersion,
+		Repository: "user2/repo1",
+		Settings: &api.EditRepoActionsSettingsOption{
+			DefaultTokenPermissions: util.ToPointer("read"),
+			AllowedActions:          []string{"actions/*"},
+		},
+		Variables: []*api.ActionsConfigBundleVariable{
+			{Name: "VERSION", Value: "123"},
+			{Name: "ENABLED", Value: "true"},
+			{Name: "NOTES", Value: "line 1\nline 2"},
+		},
+	}
+	data, err := MarshalConfigBundle(bundle)
+	require.NoError(t, err)
+	assert.Equal(t, `version: 1
+repository: user2/repo1
+settings:
+    default_token_permissions: read
+    allowed_actions:
+        - actions/*
+variables:
+    - name: VERSION
+      value: "123"
+    - name: ENABLED
+      value: "true"
+    - name: NOTES
+      value: |-
+        line 1
+        line 2
+`, string(data))
+
+	decoded, err := UnmarshalConfigBundle(data)
+	require.NoError(t, err)
+	assert.Equal(t, bundle, decoded)
+
+	_, err = UnmarshalConfigBundle([]byte("version: 2\n"))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = UnmarshalConfigBundle([]byte("- version: 1\n"))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = UnmarshalConfigBundle([]byte("version: 1\nvariables: 1\n"))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestImportConfigBundle(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	_, err := CreateVariable(ctx, 0, repo.ID, "REGION", "us")
+	require.NoError(t, err)
+
+	// nothing is written if a variable is invalid
+	_, err = ImportConfigBundle(ctx, repo, doer, &api.ActionsConfigBundle{
+		Version: ConfigBundleVersion,
+		Variables: []*api.ActionsConfigBundleVariable{
+			{Name: "STAGE", Value: "prod"},
+			{Name: "GITEA_TOKEN", Value: "x"},
+		},
+	})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	unittest.AssertNotExistsBean(t, &actions_model.ActionVariable{RepoID: repo.ID, Name: "STAGE"})
+
+	res, err := ImportConfigBundle(ctx, repo, doer, &api.ActionsConfigBundle{
+		Version: ConfigBundleVersion,
+		Variables: []*api.ActionsConfigBundleVariable{
+			{Name: "region", Value: "eu"},
+			{Name: "STAGE", Value: "prod"},
+		},
+		// repository 1 has no workflows, so the preset is skipped
+		DispatchPresets: []*api.ActionsConfigBundleDispatchPreset{
+			{Name: "deploy", WorkflowID: "deploy.yml"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"STAGE"}, res.VariablesCreated)
+	assert.Equal(t, []string{"REGION"}, res.VariablesUpdated)
+	assert.Empty(t, res.DispatchPresetsSaved)
+	if assert.Len(t, res.DispatchPresetsSkipped, 1) {
+		assert.Equal(t, "deploy", res.DispatchPresetsSkipped[0].Name)
+	}
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionVariable{RepoID: repo.ID, Name: "REGION", Data: "eu"})
+	unittest.AssertNotExistsBean(t, &actions_model.ActionDispatchPreset{RepoID: repo.ID, Name: "deploy"})
+
+	bundle, err := ExportConfigBundle(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, []*api.ActionsConfigBundleVariable{
+		{Name: "REGION", Value: "eu"},
+		{Name: "STAGE", Value: "prod"},
+	}, bundle.Variables)
+	assert.Equal(t, "user2/repo1", bundle.Repository)
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ConfigSnapshot is the Actions configuration of a repository as of a time
+type ConfigSnapshot struct {
+	At timeutil.TimeStamp
+	// the commit of the default branch at the time, nil if the branch had no commits then
+	Commit    *git.Commit
+	Workflows []*SnapshotWorkflow
+	// the revision of the settings in effect at the time, nil if they weren't recorded yet
+	Revision *actions_model.ActionConfigRevision
+	// when the settings began to be recorded, 0 if they never were
+	RecordedSince timeutil.TimeStamp
+	// the freeze windows of the instance and of the owner active at the time, the deleted ones are gone
+	FreezeWindows []*actions_model.ActionFreezeWindow
+}
+
+// SnapshotWorkflow is a workflow of the default branch as of the time of a ConfigSnapshot
+type SnapshotWorkflow struct {
+	WorkflowID string
+	Events     []string
+	Schedules  []string // the cron expressions of the schedule event
+	Disabled   bool     // only known if the settings were recorded at the time
+	Error      string   // the workflow was invalid
+}
+
+// GetConfigSnapshot reconstructs the Actions configuration of the repository as of the time from the history:
+// the workflows and the schedules of the commit of the default branch then, the settings of the repository then,
+// and the freeze windows active then. The settings of the instance in app.ini aren't historical, so they aren't included.
+func GetConfigSnapshot(ctx context.Context, repo *repo_model.Repository, at time.Time) (*ConfigSnapshot, error) {
+	snapshot := &ConfigSnapshot{
+		At:            timeutil.TimeStamp(at.Unix()),
+		Workflows:     []*SnapshotWorkflow{},
+		FreezeWindows: []*actions_model.ActionFreezeWindow{},
+	}
+
+	revision, err := actions_model.GetConfigRevisionAt(ctx, repo.ID, snapshot.At)
+	if err != nil {
+		return nil, fmt.Errorf("GetConfigRevisionAt: %w", err)
+	}
+	snapshot.Revision = revision
+	first, err := actions_model.GetFirstConfigRevision(ctx, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("GetFirstConfigRevision: %w", err)
+	}
+	if first != nil {
+		snapshot.RecordedSince = first.CreatedUnix
+	}
+
+	windows, err := db.Find[actions_model.ActionFreezeWindow](ctx, actions_model.FindFreezeWindowsOptions{
+		AllScope: true,
+		ActiveAt: snapshot.At,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FindFreezeWindows: %w", err)
+	}
+	for _, w := range windows {
+		if w.OwnerID == 0 || w.OwnerID == repo.OwnerID {
+			snapshot.FreezeWindows = append(snapshot.FreezeWindows, w)
+		}
+	}
+
+	if repo.IsEmpty {
+		return snapshot, nil
+	}
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommitBefore(repo.DefaultBranch, at)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return snapshot, nil
+		}
+		return nil, fmt.Errorf("GetCommitBefore: %w", err)
+	}
+	snapshot.Commit = commit
+
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return nil, fmt.Errorf("ListWorkflows: %w", err)
+	}
+	var cfg *repo_model.ActionsConfig
+	if revision != nil {
+		cfg = revision.ActionsConfig()
+	}
+	for _, entry := range entries {
+		wf := &SnapshotWorkflow{
+			WorkflowID: entry.Name(),
+			Events:     []string{},
+			Schedules:  []string{},
+			Disabled:   cfg != nil && cfg.IsWorkflowDisabled(entry.Name()),
+		}
+		snapshot.Workflows = append(snapshot.Workflows, wf)
+
+		content, err := actions_module.GetContentFromEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("GetContentFromEntry: %w", err)
+		}
+		events, err := actions_module.GetEventsFromContent(content)
+		if err != nil {
+			log.Trace("invalid workflow %q of repository %s: %v", entry.Name(), repo.FullName(), err)
+			wf.Error = err.Error()
+			continue
+		}
+		for _, evt := range events {
+			wf.Events = append(wf.Events, evt.Name)
+			for _, schedule := range evt.Schedules() {
+				if cron := schedule["cron"]; cron != "" {
+					wf.Schedules = append(wf.Schedules, cron)
+				}
+			}
+		}
+	}
+	return snapshot, nil
+}
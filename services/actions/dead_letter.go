@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// insertDeadLetter stores the item whose workflows couldn't be triggered as a dead letter, so it could be inspected and retried by the admins
+func insertDeadLetter(ctx context.Context, item *notifyInputItem, cause error) {
+	content, err := json.Marshal(item)
+	if err != nil {
+		log.Error("Failed to marshal the %q event of repo %d as a dead letter: %v", item.Event, item.RepoID, err)
+		return
+	}
+	if err := actions_model.InsertDeadLetter(ctx, &actions_model.ActionDeadLetter{
+		RepoID:    item.RepoID,
+		Method:    item.Method,
+		Event:     item.Event,
+		Ref:       item.Ref,
+		CommitSHA: item.CommitID,
+		Item:      string(content),
+		Error:     cause.Error(),
+	}); err != nil {
+		log.Error("Failed to store the %q event of repo %d as a dead letter: %v", item.Event, item.RepoID, err)
+	}
+}
+
+// RetryDeadLetter handles the event of the dead letter again, it's deleted if the workflows are triggered,
+// otherwise the error of the attempt is recorded and returned as an invalid argument.
+// The runs created by the failed attempts aren't created again for the push events and the synchronized pull requests
+// if it's retried in actions_model.RunDeliveryWindow, since their runs are deduplicated.
+func RetryDeadLetter(ctx context.Context, letter *actions_model.ActionDeadLetter) error {
+	item := &notifyInputItem{}
+	if err := json.Unmarshal([]byte(letter.Item), item); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if cause := handleNotifyInputItem(ctx, item); cause != nil {
+		if err := actions_model.UpdateDeadLetterFailure(ctx, letter, cause.Error()); err != nil {
+			return err
+		}
+		return util.NewInvalidArgumentErrorf("retry of dead letter %d failed: %v", letter.ID, cause)
+	}
+	return actions_model.DeleteDeadLetter(ctx, letter.ID)
+}
+
+// GetDeadLetterPayload returns the payload of the event of the dead letter in JSON, empty if the event had none
+func GetDeadLetterPayload(letter *actions_model.ActionDeadLetter) (string, error) {
+	item := &notifyInputItem{}
+	if err := json.Unmarshal([]byte(letter.Item), item); err != nil {
+		return "", fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return string(item.Payload), nil
+}
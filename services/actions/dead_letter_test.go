@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetter(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// the repository of the event doesn't exist
+	item := &notifyInputItem{
+		Method:  "PushCommits",
+		RepoID:  10000,
+		DoerID:  user_model.ActionsUserID,
+		Event:   webhook_module.HookEventPush,
+		Ref:     "refs/heads/master",
+		Payload: []byte(`{"ref":"refs/heads/master"}`),
+	}
+	err := handleNotifyInputItem(ctx, item)
+	require.Error(t, err)
+	insertDeadLetter(ctx, item, err)
+
+	letters, err := db.Find[actions_model.ActionDeadLetter](ctx, actions_model.FindDeadLettersOptions{RepoID: 10000})
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	letter := letters[0]
+	assert.Equal(t, webhook_module.HookEventPush, letter.Event)
+	assert.Contains(t, letter.Error, "GetRepositoryByID")
+	payload, err := GetDeadLetterPayload(letter)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ref":"refs/heads/master"}`, payload)
+
+	err = RetryDeadLetter(ctx, letter)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	letter, err = actions_model.GetDeadLetterByID(ctx, letter.ID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, letter.Attempts)
+
+	// the event is handled once the repository is right
+	item.RepoID = 4
+	content, err := json.Marshal(item)
+	require.NoError(t, err)
+	letter.Item = string(content)
+	require.NoError(t, RetryDeadLetter(ctx, letter))
+	_, err = actions_model.GetDeadLetterByID(ctx, letter.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// dispatchEstimateSampleRuns is how many of the latest successful runs of the workflow the durations are averaged over
+const dispatchEstimateSampleRuns = 20
+
+// DispatchEstimateOptions is the dispatch to estimate
+type DispatchEstimateOptions struct {
+	WorkflowID string
+	Ref        string // the default branch if it's empty, a branch or tag name is also accepted
+	Inputs     map[string]string
+}
+
+// DispatchEstimateJob is a job of the workflow with its matrix expanded
+type DispatchEstimateJob struct {
+	JobID      string
+	RunsOn     []string
+	MatrixSize int
+	// the average duration of the job in the sampled runs, 0 if it hasn't succeeded in them
+	AverageDuration time.Duration
+	Minutes         float64 // the average minutes multiplied by the matrix size
+	WeightedMinutes float64 // the minutes multiplied by the weight of the runners, see RunnerWeight
+}
+
+// DispatchEstimate is the estimated impact of dispatching a workflow, before the dispatch is confirmed
+type DispatchEstimate struct {
+	WorkflowID string
+	Ref        string
+	CommitID   string
+	JobCount   int // the number of the jobs which would be created, with their matrices expanded
+	Jobs       []*DispatchEstimateJob
+	// the number of the latest successful runs of the workflow the durations are averaged over, 0 if there isn't any
+	SampledRuns        int
+	AverageRunDuration time.Duration
+	Minutes            float64
+	WeightedMinutes    float64
+}
+
+// EstimateDispatch estimates how many jobs dispatching the workflow would create, how long it would take and how many minutes it would cost,
+// by the durations of the latest successful runs of the workflow, without dispatching it.
+// The matrices are expanded without the inputs, since they aren't available to them.
+func EstimateDispatch(ctx context.Context, repo *repo_model.Repository, opts *DispatchEstimateOptions) (*DispatchEstimate, error) {
+	if repo.IsEmpty {
+		return nil, util.NewInvalidArgumentErrorf("repository %s is empty", repo.FullName())
+	}
+	if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return nil, util.NewInvalidArgumentErrorf("actions of repository %s are disabled", repo.FullName())
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	ref := opts.Ref
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	ref = fullRefName(gitRepo, ref)
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, util.NewNotExistErrorf("ref %s doesn't exist", ref)
+		}
+		return nil, fmt.Errorf("GetCommit: %w", err)
+	}
+	content, err := getWorkflowContent(commit, opts.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, util.NewNotExistErrorf("workflow %q doesn't exist on %s", opts.WorkflowID, ref)
+	}
+	if err := prepareWorkflowDispatchInputs(content, &api.WorkflowDispatchPayload{
+		Workflow: opts.WorkflowID,
+		Inputs:   maps.Clone(opts.Inputs),
+	}); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("%v", err)
+	}
+
+	vars, err := actions_model.GetVariablesOfRun(ctx, &actions_model.ActionRun{RepoID: repo.ID, OwnerID: repo.OwnerID, Repo: repo})
+	if err != nil {
+		return nil, fmt.Errorf("GetVariablesOfRun: %w", err)
+	}
+	workflows, err := jobparser.Parse(content, jobparser.WithVars(vars))
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid workflow %q: %v", opts.WorkflowID, err)
+	}
+
+	estimate := &DispatchEstimate{
+		WorkflowID: opts.WorkflowID,
+		Ref:        ref,
+		CommitID:   commit.ID.String(),
+		JobCount:   len(workflows),
+		Jobs:       []*DispatchEstimateJob{},
+	}
+	jobs := make(map[string]*DispatchEstimateJob, len(workflows))
+	for _, wf := range workflows {
+		id, job := wf.Job()
+		if j, ok := jobs[id]; ok {
+			j.MatrixSize++
+			continue
+		}
+		j := &DispatchEstimateJob{JobID: id, RunsOn: job.RunsOn(), MatrixSize: 1}
+		jobs[id] = j
+		estimate.Jobs = append(estimate.Jobs, j)
+	}
+
+	if err := estimateDispatchDurations(ctx, repo.ID, estimate); err != nil {
+		return nil, err
+	}
+	for _, j := range estimate.Jobs {
+		j.Minutes = j.AverageDuration.Minutes() * float64(j.MatrixSize)
+		j.WeightedMinutes = j.Minutes * RunnerWeight(j.RunsOn)
+		estimate.Minutes += j.Minutes
+		estimate.WeightedMinutes += j.WeightedMinutes
+	}
+	return estimate, nil
+}
+
+// estimateDispatchDurations fills the average durations of the run and its jobs by the latest successful runs of the workflow
+func estimateDispatchDurations(ctx context.Context, repoID int64, estimate *DispatchEstimate) error {
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		ListOptions: db.ListOptions{PageSize: dispatchEstimateSampleRuns},
+		RepoID:      repoID,
+		WorkflowID:  estimate.WorkflowID,
+		Status:      []actions_model.Status{actions_model.StatusSuccess},
+	})
+	if err != nil {
+		return fmt.Errorf("FindRuns: %w", err)
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+
+	runIDs := make([]int64, 0, len(runs))
+	var total time.Duration
+	for _, run := range runs {
+		runIDs = append(runIDs, run.ID)
+		total += run.Duration()
+	}
+	estimate.SampledRuns = len(runs)
+	estimate.AverageRunDuration = total / time.Duration(len(runs))
+
+	runJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		RunIDs:   runIDs,
+		Statuses: []actions_model.Status{actions_model.StatusSuccess},
+	})
+	if err != nil {
+		return fmt.Errorf("FindRunJobs: %w", err)
+	}
+	durations := make(map[string]time.Duration, len(estimate.Jobs))
+	counts := make(map[string]int64, len(estimate.Jobs))
+	for _, job := range runJobs {
+		durations[job.JobID] += job.Duration()
+		counts[job.JobID]++
+	}
+	for _, j := range estimate.Jobs {
+		if counts[j.JobID] > 0 {
+			j.AverageDuration = durations[j.JobID] / time.Duration(counts[j.JobID])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateDispatch(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	_, err := EstimateDispatch(ctx, repo, &DispatchEstimateOptions{WorkflowID: "deploy.yml"})
+	assert.ErrorIs(t, err, util.ErrNotExist)
+	_, err = EstimateDispatch(ctx, repo, &DispatchEstimateOptions{WorkflowID: "deploy.yml", Ref: "no-such-branch"})
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
+
+func TestEstimateDispatchDurations(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// both successful runs of the workflow and their jobs took 98 seconds
+	estimate := &DispatchEstimate{
+		WorkflowID: "artifact.yaml",
+		Jobs:       []*DispatchEstimateJob{{JobID: "job_2", MatrixSize: 2}, {JobID: "job_new", MatrixSize: 1}},
+	}
+	require.NoError(t, estimateDispatchDurations(ctx, 4, estimate))
+	assert.Equal(t, 2, estimate.SampledRuns)
+	assert.Equal(t, 98*time.Second, estimate.AverageRunDuration)
+	assert.Equal(t, 98*time.Second, estimate.Jobs[0].AverageDuration)
+	// the jobs which haven't run yet have no history
+	assert.Zero(t, estimate.Jobs[1].AverageDuration)
+
+	// the workflows which have never succeeded have no history
+	estimate = &DispatchEstimate{WorkflowID: "deploy.yml", Jobs: []*DispatchEstimateJob{{JobID: "job_2", MatrixSize: 1}}}
+	require.NoError(t, estimateDispatchDurations(ctx, 4, estimate))
+	assert.Zero(t, estimate.SampledRuns)
+	assert.Zero(t, estimate.Jobs[0].AverageDuration)
+}
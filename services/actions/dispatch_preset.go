@@ -0,0 +1,116 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// DispatchPreset dispatches the workflow of the preset with its inputs as the doer.
+// The inputs are checked against the workflow on the ref of the preset before the dispatch is queued,
+// so a preset which is out of date with the workflow fails immediately.
+func DispatchPreset(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, preset *actions_model.ActionDispatchPreset) error {
+	input, err := newNotifyInputOfPreset(ctx, repo, doer, preset)
+	if err != nil {
+		return err
+	}
+	return pushNotifyInput(withMethod(ctx, "DispatchPreset"), input)
+}
+
+// SaveDispatchPreset creates or replaces the dispatch preset of the repository,
+// it's refused if the preset couldn't dispatch the workflow on its ref.
+func SaveDispatchPreset(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, preset *actions_model.ActionDispatchPreset) (created bool, err error) {
+	if _, err := newNotifyInputOfPreset(ctx, repo, doer, preset); err != nil {
+		return false, err
+	}
+	preset.RepoID = repo.ID
+	preset.CreatorID = doer.ID
+	return actions_model.SaveDispatchPreset(ctx, preset)
+}
+
+func newNotifyInputOfPreset(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, preset *actions_model.ActionDispatchPreset) (*notifyInput, error) {
+	if repo.IsEmpty || repo.IsArchived {
+		return nil, util.NewInvalidArgumentErrorf("repository %s is empty or archived", repo.FullName())
+	}
+	if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return nil, util.NewInvalidArgumentErrorf("actions of repository %s are disabled", repo.FullName())
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	ref := preset.Ref
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	ref = fullRefName(gitRepo, ref)
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, util.NewInvalidArgumentErrorf("ref %s of dispatch preset %q doesn't exist", ref, preset.Name)
+		}
+		return nil, fmt.Errorf("GetCommit: %w", err)
+	}
+
+	content, err := getWorkflowContent(commit, preset.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, util.NewInvalidArgumentErrorf("workflow %q of dispatch preset %q doesn't exist on %s", preset.WorkflowID, preset.Name, ref)
+	}
+
+	payload := &api.WorkflowDispatchPayload{
+		Workflow:   preset.WorkflowID,
+		Ref:        ref,
+		Inputs:     maps.Clone(preset.Inputs),
+		Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm_model.AccessModeOwner}),
+		Sender:     convert.ToUser(ctx, doer, nil),
+	}
+	if err := prepareWorkflowDispatchInputs(content, payload); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("dispatch preset %q: %v", preset.Name, err)
+	}
+
+	return newNotifyInput(repo, doer, webhook_module.HookEventWorkflowDispatch).
+		WithRef(ref).
+		WithCommitID(commit.ID.String()).
+		WithPayload(payload), nil
+}
+
+// getWorkflowContent returns the content of the workflow on the commit, or nil if it doesn't exist
+func getWorkflowContent(commit *git.Commit, workflowID string) ([]byte, error) {
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return nil, fmt.Errorf("ListWorkflows: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == workflowID {
+			content, err := actions_module.GetContentFromEntry(entry)
+			if err != nil {
+				return nil, fmt.Errorf("GetContentFromEntry: %w", err)
+			}
+			return content, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveDispatchPresetOfMissingWorkflow(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	// the presets which couldn't dispatch their workflows are refused
+	_, err := SaveDispatchPreset(ctx, repo, doer, &actions_model.ActionDispatchPreset{Name: "deploy", WorkflowID: "deploy.yml"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = SaveDispatchPreset(ctx, repo, doer, &actions_model.ActionDispatchPreset{Name: "deploy", WorkflowID: "deploy.yml", Ref: "no-such-branch"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	unittest.AssertNotExistsBean(t, &actions_model.ActionDispatchPreset{RepoID: repo.ID, Name: "deploy"})
+
+	err = DispatchPreset(ctx, repo, doer, &actions_model.ActionDispatchPreset{RepoID: repo.ID, Name: "deploy", WorkflowID: "deploy.yml"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
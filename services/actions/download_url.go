@@ -0,0 +1,138 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// DownloadRouteBase is the route of the downloads signed by Gitea, the kind and the id of the download follow it
+const DownloadRouteBase = "/api/actions/downloads"
+
+// The kinds of the downloads signed by Gitea
+const (
+	DownloadKindArtifacts = "artifacts" // the id is the run, the name of the artifact is the "name" query parameter
+	DownloadKindLogs      = "logs"      // the id is the job, the private sections are redacted if the "redact" query parameter is true
+)
+
+// DownloadURL is a link to download an artifact or the logs of a job without authentication, until it expires
+type DownloadURL struct {
+	URL     string
+	Expires time.Time
+}
+
+// presignedStorage is implemented by the object storages which could generate the links to download the files directly
+type presignedStorage interface {
+	PresignedURL(path, name string, expiry time.Duration) (*url.URL, error)
+}
+
+// GetArtifactDownloadURL returns a link to download the artifact of the run as a zip file, which expires after setting.Actions.DownloadURLExpiry.
+// It's presigned by the storage if it serves the files directly, so the download bypasses Gitea, otherwise it's signed by Gitea.
+func GetArtifactDownloadURL(ctx context.Context, run *actions_model.ActionRun, artifactName string) (*DownloadURL, error) {
+	artifacts, err := actions_model.GetUploadedArtifactFiles(ctx, run.ID, artifactName)
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(setting.Actions.DownloadURLExpiry)
+	// the files of the artifacts uploaded by the v1-v3 backend are zipped by Gitea
+	if len(artifacts) == 1 && artifacts[0].IsSingleZip() && setting.Actions.ArtifactStorage.MinioConfig.ServeDirect {
+		if u := presignDownloadURL(storage.ActionsArtifacts, artifacts[0].StoragePath, artifacts[0].ArtifactPath, expires); u != nil {
+			return u, nil
+		}
+	}
+	return signDownloadURL(DownloadKindArtifacts, run.ID, artifactName, expires), nil
+}
+
+// GetJobLogsDownloadURL returns a link to download the logs of the latest attempt of the job, like GetArtifactDownloadURL.
+// The redacted logs are always served by Gitea.
+func GetJobLogsDownloadURL(ctx context.Context, job *actions_model.ActionRunJob, redact bool) (*DownloadURL, error) {
+	if job.TaskID == 0 {
+		return nil, util.NewNotExistErrorf("job is not started")
+	}
+	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.LogExpired {
+		return nil, util.NewNotExistErrorf("logs have been cleaned up")
+	}
+	expires := time.Now().Add(setting.Actions.DownloadURLExpiry)
+	if !redact && task.LogInStorage && setting.Actions.LogStorage.MinioConfig.ServeDirect {
+		if err := job.LoadRun(ctx); err != nil {
+			return nil, err
+		}
+		if u := presignDownloadURL(storage.Actions, task.LogFilename, job.LogsFilename(task.ID), expires); u != nil {
+			return u, nil
+		}
+	}
+	return signDownloadURL(DownloadKindLogs, job.ID, strconv.FormatBool(redact), expires), nil
+}
+
+func presignDownloadURL(s storage.ObjectStorage, path, name string, expires time.Time) *DownloadURL {
+	ps, ok := s.(presignedStorage)
+	if !ok {
+		return nil
+	}
+	u, err := ps.PresignedURL(path, name, time.Until(expires))
+	if err != nil {
+		// the download is served by Gitea instead
+		log.Warn("PresignedURL of %q: %v", path, err)
+		return nil
+	}
+	return &DownloadURL{URL: u.String(), Expires: expires}
+}
+
+// signDownloadURL returns the link to the download served by Gitea, the arg is the name of the artifact or whether the logs are redacted
+func signDownloadURL(kind string, id int64, arg string, expires time.Time) *DownloadURL {
+	query := url.Values{}
+	switch kind {
+	case DownloadKindArtifacts:
+		query.Set("name", arg)
+	case DownloadKindLogs:
+		query.Set("redact", arg)
+	}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("sig", base64.RawURLEncoding.EncodeToString(buildDownloadSignature(kind, id, arg, expires.Unix())))
+	return &DownloadURL{
+		URL:     fmt.Sprintf("%s%s/%s/%d?%s", strings.TrimSuffix(setting.AppURL, "/"), DownloadRouteBase, kind, id, query.Encode()),
+		Expires: expires,
+	}
+}
+
+func buildDownloadSignature(kind string, id int64, arg string, expires int64) []byte {
+	mac := hmac.New(sha256.New, setting.GetGeneralTokenSigningSecret())
+	_, _ = fmt.Fprintf(mac, "%s\n%d\n%s\n%d", kind, id, arg, expires)
+	return mac.Sum(nil)
+}
+
+// VerifyDownloadSignature checks the link to the download signed by Gitea, see signDownloadURL,
+// the error is util.ErrPermissionDenied if the signature is invalid or has expired
+func VerifyDownloadSignature(kind string, id int64, arg, expires, sig string) error {
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return util.NewPermissionDeniedErrorf("invalid expiry")
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(decoded, buildDownloadSignature(kind, id, arg, expiresUnix)) {
+		return util.NewPermissionDeniedErrorf("invalid signature")
+	}
+	if time.Now().Unix() > expiresUnix {
+		return util.NewPermissionDeniedErrorf("the link has expired")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignDownloadURL(t *testing.T) {
+	defer test.MockVariableValue(&setting.AppURL, "https://gitea.example.com/")()
+
+	expires := time.Now().Add(time.Minute)
+	u := signDownloadURL(DownloadKindArtifacts, 12, "my artifact", expires)
+	assert.Equal(t, expires, u.Expires)
+	assert.True(t, strings.HasPrefix(u.URL, "https://gitea.example.com/api/actions/downloads/artifacts/12?"), u.URL)
+
+	parsed, err := url.Parse(u.URL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, "my artifact", query.Get("name"))
+	assert.NoError(t, VerifyDownloadSignature(DownloadKindArtifacts, 12, "my artifact", query.Get("expires"), query.Get("sig")))
+
+	// the signature doesn't fit another download
+	assert.ErrorIs(t, VerifyDownloadSignature(DownloadKindArtifacts, 13, "my artifact", query.Get("expires"), query.Get("sig")), util.ErrPermissionDenied)
+	assert.ErrorIs(t, VerifyDownloadSignature(DownloadKindArtifacts, 12, "other", query.Get("expires"), query.Get("sig")), util.ErrPermissionDenied)
+	assert.ErrorIs(t, VerifyDownloadSignature(DownloadKindLogs, 12, "my artifact", query.Get("expires"), query.Get("sig")), util.ErrPermissionDenied)
+	// the expiry can't be extended
+	later := strconv.FormatInt(expires.Add(time.Hour).Unix(), 10)
+	assert.ErrorIs(t, VerifyDownloadSignature(DownloadKindArtifacts, 12, "my artifact", later, query.Get("sig")), util.ErrPermissionDenied)
+
+	// expired
+	u = signDownloadURL(DownloadKindLogs, 12, "true", time.Now().Add(-time.Minute))
+	parsed, err = url.Parse(u.URL)
+	require.NoError(t, err)
+	query = parsed.Query()
+	assert.Equal(t, "true", query.Get("redact"))
+	assert.ErrorIs(t, VerifyDownloadSignature(DownloadKindLogs, 12, "true", query.Get("expires"), query.Get("sig")), util.ErrPermissionDenied)
+}
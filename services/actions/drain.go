@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"sync"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// runnerUpdates tracks the in-flight updates of tasks and logs from the runners,
+// so the shutdown waits for them to be acknowledged instead of cutting them off at the hammer.
+var runnerUpdates = struct {
+	sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+}{}
+
+// BeginRunnerUpdate marks the start of an update of a task or its logs from a runner,
+// done must be called once the update has been handled.
+// It returns false when the server is shutting down, the runner should retry the update later.
+func BeginRunnerUpdate() (done func(), ok bool) {
+	runnerUpdates.Lock()
+	defer runnerUpdates.Unlock()
+	if runnerUpdates.draining {
+		return nil, false
+	}
+	runnerUpdates.wg.Add(1)
+	return runnerUpdates.wg.Done, true
+}
+
+// IsDrainingRunners returns whether the server is shutting down, no tasks should be assigned to the runners any longer,
+// since they could be lost if the responses are cut off.
+func IsDrainingRunners() bool {
+	runnerUpdates.Lock()
+	defer runnerUpdates.Unlock()
+	return runnerUpdates.draining
+}
+
+// drainRunnerUpdates waits for the in-flight updates from the runners once the server starts shutting down,
+// it's run within the running server wait group of the graceful manager, so the hammer waits for it.
+func drainRunnerUpdates(ctx context.Context) {
+	<-ctx.Done()
+	if !waitRunnerUpdates(graceful.GetManager().IsHammer()) {
+		log.Warn("Actions: the in-flight updates from the runners haven't been done before the hammer")
+	}
+}
+
+// waitRunnerUpdates stops accepting new updates and waits for the in-flight ones,
+// it returns false if the hammer comes first.
+func waitRunnerUpdates(hammer <-chan struct{}) bool {
+	runnerUpdates.Lock()
+	runnerUpdates.draining = true
+	runnerUpdates.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		runnerUpdates.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-hammer:
+		return false
+	}
+}
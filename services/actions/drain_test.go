@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitRunnerUpdates(t *testing.T) {
+	defer func() {
+		runnerUpdates.draining = false
+	}()
+
+	done, ok := BeginRunnerUpdate()
+	require.True(t, ok)
+	assert.False(t, IsDrainingRunners())
+
+	drained := make(chan bool)
+	go func() {
+		drained <- waitRunnerUpdates(make(chan struct{}))
+	}()
+	assert.Eventually(t, IsDrainingRunners, time.Second, 10*time.Millisecond)
+
+	// the new updates are rejected while the in-flight one is waited for
+	_, ok = BeginRunnerUpdate()
+	assert.False(t, ok)
+	select {
+	case <-drained:
+		assert.Fail(t, "drained before the in-flight update is done")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	done()
+	assert.True(t, <-drained)
+
+	// the hammer stops waiting
+	runnerUpdates.draining = false
+	done, ok = BeginRunnerUpdate()
+	require.True(t, ok)
+	defer done()
+	hammer := make(chan struct{})
+	close(hammer)
+	assert.False(t, waitRunnerUpdates(hammer))
+}
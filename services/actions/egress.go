@@ -0,0 +1,103 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// prepareEgress sets the egress policy of the new run, the domains declared by the workflow are restricted by
+// the egress allowlist of the owner, and the allowlist applies if the workflow doesn't declare any.
+// If the declaration is invalid, the allowlist of the owner still applies and the error is returned.
+func prepareEgress(ctx context.Context, run *actions_model.ActionRun, content []byte) error {
+	allowed, err := actions_model.GetEgressAllowlist(ctx, run.OwnerID)
+	if err != nil {
+		return fmt.Errorf("GetEgressAllowlist: %w", err)
+	}
+	declared, declaredErr := actions_module.ParseWorkflowEgress(content)
+	switch {
+	case declaredErr != nil || declared == nil:
+		declared = allowed
+	case allowed != nil:
+		declared = actions_module.RestrictEgressDomains(declared, allowed)
+	}
+	if declared != nil {
+		run.Egress = &actions_model.RunEgress{Allow: declared}
+	}
+	return declaredErr
+}
+
+// egressContext returns the egress policy of the run for the runners which could enforce it, see generateTaskContext.
+// The runners report the blocked connections to the url with the token of the task.
+func egressContext(run *actions_model.ActionRun) map[string]any {
+	allow := []any{}
+	if run.Egress != nil {
+		for _, domain := range run.Egress.Allow {
+			allow = append(allow, domain)
+		}
+	}
+	return map[string]any{
+		"restricted": run.Egress != nil,
+		"allow":      allow,
+		"report_url": run.APIURL() + "/egress-violations",
+	}
+}
+
+// EgressConnection is a connection of a task blocked by the runner
+type EgressConnection struct {
+	Host  string
+	Port  int
+	Count int64 // how many times it was blocked
+}
+
+// RecordEgressViolations records the connections of the running task blocked by the runner as the violations of the egress policy of its run,
+// the connections allowed by the policy are ignored. It returns the recorded violations.
+func RecordEgressViolations(ctx context.Context, task *actions_model.ActionTask, connections []*EgressConnection) ([]*actions_model.ActionEgressViolation, error) {
+	if task.Status.IsDone() {
+		return nil, util.NewInvalidArgumentErrorf("task %d is done", task.ID)
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		return nil, err
+	}
+	if err := task.Job.LoadRun(ctx); err != nil {
+		return nil, err
+	}
+	run := task.Job.Run
+	if run.Egress == nil {
+		return nil, util.NewInvalidArgumentErrorf("run %d has no egress policy", run.ID)
+	}
+
+	violations := make([]*actions_model.ActionEgressViolation, 0, len(connections))
+	for _, c := range connections {
+		host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(c.Host)), ".")
+		if host == "" || len(host) > 255 {
+			return nil, util.NewInvalidArgumentErrorf("invalid host %q", c.Host)
+		}
+		if c.Port < 0 || c.Port > 65535 {
+			return nil, util.NewInvalidArgumentErrorf("invalid port %d", c.Port)
+		}
+		if actions_module.MatchEgressDomain(run.Egress.Allow, host) {
+			continue
+		}
+		violation := &actions_model.ActionEgressViolation{
+			RepoID: run.RepoID,
+			RunID:  run.ID,
+			TaskID: task.ID,
+			Host:   host,
+			Port:   c.Port,
+			Count:  c.Count,
+		}
+		if err := actions_model.RecordEgressViolation(ctx, violation); err != nil {
+			return nil, err
+		}
+		violations = append(violations, violation)
+	}
+	return violations, nil
+}
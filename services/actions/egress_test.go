@@ -0,0 +1,96 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareEgress(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	declared := []byte("# egress:\n#   allow: [github.com, \"*.npmjs.org\", example.com]\non: push\n")
+
+	// the workflow declares the domains and the owner has no allowlist
+	run := &actions_model.ActionRun{OwnerID: 2}
+	require.NoError(t, prepareEgress(ctx, run, declared))
+	require.NotNil(t, run.Egress)
+	assert.Equal(t, []string{"github.com", "*.npmjs.org", "example.com"}, run.Egress.Allow)
+
+	// no egress policy if neither declares
+	run = &actions_model.ActionRun{OwnerID: 2}
+	require.NoError(t, prepareEgress(ctx, run, []byte("on: push\n")))
+	assert.Nil(t, run.Egress)
+
+	require.NoError(t, actions_model.SetEgressAllowlist(ctx, 2, []string{"github.com", "*.npmjs.org"}))
+	defer func() {
+		require.NoError(t, actions_model.SetEgressAllowlist(ctx, 2, nil))
+	}()
+
+	// the declared domains are restricted by the allowlist of the owner
+	run = &actions_model.ActionRun{OwnerID: 2}
+	require.NoError(t, prepareEgress(ctx, run, declared))
+	require.NotNil(t, run.Egress)
+	assert.Equal(t, []string{"github.com", "*.npmjs.org"}, run.Egress.Allow)
+
+	// the allowlist of the owner applies if the workflow doesn't declare any
+	run = &actions_model.ActionRun{OwnerID: 2}
+	require.NoError(t, prepareEgress(ctx, run, []byte("on: push\n")))
+	require.NotNil(t, run.Egress)
+	assert.Equal(t, []string{"github.com", "*.npmjs.org"}, run.Egress.Allow)
+
+	// the allowlist of the owner still applies if the declaration is invalid
+	run = &actions_model.ActionRun{OwnerID: 2}
+	assert.Error(t, prepareEgress(ctx, run, []byte("# egress:\n#   allow: [\"not a domain\"]\non: push\n")))
+	require.NotNil(t, run.Egress)
+	assert.Equal(t, []string{"github.com", "*.npmjs.org"}, run.Egress.Allow)
+}
+
+func TestRecordEgressViolations(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+
+	// the run of task 47 has no egress policy
+	_, err := RecordEgressViolations(ctx, task, []*EgressConnection{{Host: "example.com", Port: 443}})
+	assert.Error(t, err)
+
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	run.Egress = &actions_model.RunEgress{Allow: []string{"github.com", "*.npmjs.org"}}
+	require.NoError(t, actions_model.UpdateRun(ctx, run, "egress"))
+	task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+
+	violations, err := RecordEgressViolations(ctx, task, []*EgressConnection{
+		{Host: "github.com", Port: 443},
+		{Host: "Example.COM.", Port: 443, Count: 2},
+		{Host: "registry.npmjs.org", Port: 443},
+	})
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "example.com", violations[0].Host)
+	assert.EqualValues(t, 2, violations[0].Count)
+
+	// the same connection is recorded once with how many times it was blocked
+	_, err = RecordEgressViolations(ctx, task, []*EgressConnection{{Host: "example.com", Port: 443}})
+	require.NoError(t, err)
+	violations, err = actions_model.FindEgressViolationsByRunID(ctx, run.ID)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.EqualValues(t, 3, violations[0].Count)
+	assert.EqualValues(t, task.ID, violations[0].TaskID)
+
+	_, err = RecordEgressViolations(ctx, task, []*EgressConnection{{Host: "example.com", Port: 65536}})
+	assert.Error(t, err)
+	_, err = RecordEgressViolations(ctx, task, []*EgressConnection{{Host: " "}})
+	assert.Error(t, err)
+}
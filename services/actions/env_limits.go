@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	secret_model "code.gitea.io/gitea/models/secret"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// preflightCheckEnvLimits checks the secrets and variables provided to the jobs don't exceed the limits of the instance,
+// it's always enabled since the runners may crash with oversized environments.
+const preflightCheckEnvLimits = "env_limits"
+
+// preflightEnvLimits checks the secrets and variables which every job of the run would get don't exceed the limits
+// of setting.Actions, all jobs get the same ones, so they fail together.
+func preflightEnvLimits(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	if setting.Actions.MaxJobSecrets <= 0 && setting.Actions.MaxJobSecretsSize <= 0 &&
+		setting.Actions.MaxJobVariables <= 0 && setting.Actions.MaxJobVariablesSize <= 0 {
+		return nil, nil
+	}
+
+	refProtected, err := isRunRefProtected(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := secret_model.GetSecretsOfRun(ctx, run, refProtected)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := actions_model.GetVariablesOfRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := checkEnvLimits(secrets, vars)
+	if msg == "" {
+		return nil, nil
+	}
+	errs := make(map[string]string, len(jobs))
+	for _, job := range jobs {
+		id, _ := job.Job()
+		errs[id] = msg
+	}
+	return errs, nil
+}
+
+// checkEnvLimits returns why the secrets and variables exceed the limits, or empty if they don't
+func checkEnvLimits(secrets, vars map[string]string) string {
+	if limit := setting.Actions.MaxJobSecrets; limit > 0 && int64(len(secrets)) > limit {
+		return fmt.Sprintf("the job would get %d secrets, more than the limit %d of the instance", len(secrets), limit)
+	}
+	if limit := setting.Actions.MaxJobSecretsSize; limit > 0 && envSize(secrets) > limit {
+		return fmt.Sprintf("the secrets of the job would be %s, more than the limit %s of the instance", base.FileSize(envSize(secrets)), base.FileSize(limit))
+	}
+	if limit := setting.Actions.MaxJobVariables; limit > 0 && int64(len(vars)) > limit {
+		return fmt.Sprintf("the job would get %d variables, more than the limit %d of the instance", len(vars), limit)
+	}
+	if limit := setting.Actions.MaxJobVariablesSize; limit > 0 && envSize(vars) > limit {
+		return fmt.Sprintf("the variables of the job would be %s, more than the limit %s of the instance", base.FileSize(envSize(vars)), base.FileSize(limit))
+	}
+	return ""
+}
+
+// envSize returns the total bytes of the names and values
+func envSize(env map[string]string) int64 {
+	var size int64
+	for k, v := range env {
+		size += int64(len(k) + len(v))
+	}
+	return size
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// The lifecycle events of runs and jobs published to the message queue
+const (
+	LifecycleEventRunCreated   = "run.created"
+	LifecycleEventRunCompleted = "run.completed"
+	LifecycleEventJobUpdated   = "job.updated"
+)
+
+// eventPublisher publishes the lifecycle events to a message queue
+type eventPublisher interface {
+	Publish(ctx context.Context, subject string, payload []byte) error
+	Close() error
+}
+
+// publishedEvent is an event waiting to be published
+type publishedEvent struct {
+	Subject string
+	Payload []byte
+}
+
+var (
+	lifecycleEventQueue *queue.WorkerPoolQueue[*publishedEvent]
+	lifecyclePublisher  eventPublisher
+)
+
+func initEventPublisher() error {
+	var err error
+	switch setting.ActionsEvents.Publisher {
+	case "":
+		return nil
+	case setting.ActionsEventsPublisherNATS:
+		lifecyclePublisher, err = newNATSPublisher(setting.ActionsEvents.ConnStr)
+	case setting.ActionsEventsPublisherRedis:
+		lifecyclePublisher, err = newRedisPublisher(setting.ActionsEvents.ConnStr, setting.ActionsEvents.Subject)
+	default:
+		err = fmt.Errorf("unsupported publisher %q", setting.ActionsEvents.Publisher)
+	}
+	if err != nil {
+		return err
+	}
+
+	lifecycleEventQueue = queue.CreateSimpleQueue(graceful.GetManager().ShutdownContext(), "actions_events", lifecycleEventQueueHandler)
+	if lifecycleEventQueue == nil {
+		return errors.New("unable to create actions_events queue")
+	}
+	go graceful.GetManager().RunWithCancel(lifecycleEventQueue)
+	graceful.GetManager().RunAtTerminate(func() {
+		if err := lifecyclePublisher.Close(); err != nil {
+			log.Error("Close the publisher of actions events: %v", err)
+		}
+	})
+	return nil
+}
+
+func lifecycleEventQueueHandler(items ...*publishedEvent) []*publishedEvent {
+	ctx := graceful.GetManager().ShutdownContext()
+	var ret []*publishedEvent
+	for _, event := range items {
+		if err := lifecyclePublisher.Publish(ctx, event.Subject, event.Payload); err != nil {
+			log.Error("Failed to publish actions event %s: %v", event.Subject, err)
+			ret = append(ret, event)
+		}
+	}
+	return ret
+}
+
+// lifecycleEventSubject returns the subject like "gitea.actions.owner.repo.run.created",
+// the dots in the names are replaced since they separate the tokens of NATS subjects
+func lifecycleEventSubject(run *actions_model.ActionRun, event string) string {
+	escape := strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_")
+	return strings.Join([]string{
+		setting.ActionsEvents.Subject,
+		escape.Replace(run.Repo.OwnerName),
+		escape.Replace(run.Repo.Name),
+		event,
+	}, ".")
+}
+
+// publishRunEvent publishes an event of the run with all its jobs
+func publishRunEvent(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob, event string) error {
+	if lifecycleEventQueue == nil {
+		return nil
+	}
+	return pushLifecycleEvent(ctx, run, jobs, nil, event)
+}
+
+// publishJobEvents publishes the events of the changed jobs
+func publishJobEvents(ctx context.Context, jobs ...*actions_model.ActionRunJob) {
+	if lifecycleEventQueue == nil {
+		return
+	}
+	for _, job := range jobs {
+		if err := job.LoadAttributes(ctx); err != nil {
+			log.Error("Failed to load run of job %d: %v", job.ID, err)
+			continue
+		}
+		if err := pushLifecycleEvent(ctx, job.Run, []*actions_model.ActionRunJob{job}, job, LifecycleEventJobUpdated); err != nil {
+			log.Error("Failed to publish the event of job %d: %v", job.ID, err)
+		}
+	}
+}
+
+func pushLifecycleEvent(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob, job *actions_model.ActionRunJob, event string) error {
+	apiRun, err := convert.ToActionRun(ctx, run, jobs)
+	if err != nil {
+		return fmt.Errorf("ToActionRun: %w", err)
+	}
+	payload := &api.ActionLifecycleEvent{
+		Event:     event,
+		Run:       apiRun,
+		Timestamp: time.Now(),
+	}
+	if job != nil && len(apiRun.Jobs) > 0 {
+		payload.Job = apiRun.Jobs[0]
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return lifecycleEventQueue.Push(&publishedEvent{
+		Subject: lifecycleEventSubject(run, event),
+		Payload: data,
+	})
+}
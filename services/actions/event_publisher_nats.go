@@ -0,0 +1,107 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	natsDialTimeout = 10 * time.Second
+	// natsWriteTimeout is the deadline of every write to the server, the client reconnects if it's exceeded
+	natsWriteTimeout = 10 * time.Second
+)
+
+// natsFlushTimeout is how long a publishing waits for the server to process the event,
+// a stalled server fails the publishing in time instead of blocking the queue
+var natsFlushTimeout = 10 * time.Second
+
+// natsPublisher publishes the events to a NATS server, it connects lazily,
+// and connects again on the next publishing after the connection is dropped
+type natsPublisher struct {
+	connStr string
+
+	mu   sync.Mutex
+	conn *nats.Conn
+}
+
+func newNATSPublisher(connStr string) (*natsPublisher, error) {
+	u, err := url.Parse(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection string: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported scheme %q of the NATS connection string", u.Scheme)
+	}
+	return &natsPublisher{connStr: connStr}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	conn, err := p.getConn()
+	if err != nil {
+		return fmt.Errorf("connect to NATS server: %w", err)
+	}
+	if err := conn.Publish(subject, payload); err != nil {
+		return err
+	}
+
+	// wait for the server to process the event, so the event is published again if the server has stalled
+	ctx, cancel := context.WithTimeout(ctx, natsFlushTimeout)
+	defer cancel()
+	if err := conn.FlushWithContext(ctx); err != nil {
+		p.dropConn(conn)
+		return fmt.Errorf("flush to NATS server: %w", err)
+	}
+	return nil
+}
+
+func (p *natsPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+	return nil
+}
+
+// getConn returns the connection to the server, and connects if there isn't one
+func (p *natsPublisher) getConn() (*nats.Conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil && !p.conn.IsClosed() {
+		return p.conn, nil
+	}
+	conn, err := nats.Connect(p.connStr,
+		nats.Name("gitea"),
+		nats.Timeout(natsDialTimeout),
+		nats.FlusherTimeout(natsWriteTimeout),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			log.Error("NATS server reports an error: %v", err)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.conn = conn
+	return conn, nil
+}
+
+// dropConn closes the connection, the next publishing connects again
+func (p *natsPublisher) dropConn(conn *nats.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conn.Close()
+	if p.conn == conn {
+		p.conn = nil
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/nosql"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisStreamMaxLen is the approximate length the Redis stream is trimmed to, so it doesn't grow without consumers
+const redisStreamMaxLen = 100000
+
+// redisPublisher appends the events to a Redis stream, the consumers could read it with consumer groups
+type redisPublisher struct {
+	client redis.UniversalClient
+	stream string
+}
+
+func newRedisPublisher(connStr, stream string) (*redisPublisher, error) {
+	return &redisPublisher{
+		client: nosql.GetManager().GetRedisClient(connStr),
+		stream: stream,
+	}, nil
+}
+
+func (p *redisPublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: redisStreamMaxLen,
+		Approx: true,
+		Values: map[string]any{"subject": subject, "payload": payload},
+	}).Err()
+}
+
+func (p *redisPublisher) Close() error {
+	return p.client.Close()
+}
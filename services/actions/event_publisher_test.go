@@ -0,0 +1,145 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleEventSubject(t *testing.T) {
+	run := &actions_model.ActionRun{Repo: &repo_model.Repository{OwnerName: "user2", Name: "repo.js"}}
+	assert.Equal(t, "gitea.actions.user2.repo_js.job.updated", lifecycleEventSubject(run, LifecycleEventJobUpdated))
+}
+
+// serveNATS accepts the connections of the client like a NATS server, handle is called with the lines sent by the client
+// after the connection is set up, and the PINGs are answered unless it returns false
+func serveNATS(listener net.Listener, handle func(conn int, line string) bool) {
+	for i := 0; ; i++ {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func(i int) {
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			_, _ = conn.Write([]byte("INFO {\"server_id\":\"test\",\"max_payload\":1048576,\"auth_required\":true}\r\n"))
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				line = strings.TrimSpace(line)
+				if !handle(i, line) {
+					continue
+				}
+				if line == "PING" {
+					_, _ = conn.Write([]byte("PONG\r\n"))
+				}
+			}
+		}(i)
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	var mu sync.Mutex
+	var lines []string
+	go serveNATS(listener, func(_ int, line string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, line)
+		return true
+	})
+
+	p, err := newNATSPublisher("nats://s3cr3t@" + listener.Addr().String())
+	require.NoError(t, err)
+	defer p.Close()
+	require.NoError(t, p.Publish(context.Background(), "gitea.actions.user2.repo1.run.created", []byte(`{"event":"run.created"}`)))
+
+	mu.Lock()
+	defer mu.Unlock()
+	connectJSON, ok := strings.CutPrefix(lines[0], "CONNECT ")
+	require.True(t, ok, lines[0])
+	var opts struct {
+		Name      string `json:"name"`
+		AuthToken string `json:"auth_token"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(connectJSON), &opts))
+	assert.Equal(t, "gitea", opts.Name)
+	assert.Equal(t, "s3cr3t", opts.AuthToken)
+	// the event is flushed by a PING after it's published
+	assert.Equal(t, []string{"PING", "PUB gitea.actions.user2.repo1.run.created 23", `{"event":"run.created"}`, "PING"}, lines[1:])
+}
+
+func TestNATSPublisherStalledServer(t *testing.T) {
+	defer test.MockVariableValue(&natsFlushTimeout, 200*time.Millisecond)()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	var published atomic.Int32
+	go serveNATS(listener, func(conn int, line string) bool {
+		if strings.HasPrefix(line, "PUB ") {
+			published.Add(1)
+		}
+		// the first connection stalls after it's set up, it doesn't answer the PINGs flushing the events
+		return conn > 0 || published.Load() == 0
+	})
+
+	p, err := newNATSPublisher("nats://" + listener.Addr().String())
+	require.NoError(t, err)
+	defer p.Close()
+
+	start := time.Now()
+	err = p.Publish(context.Background(), "gitea.actions.user2.repo1.run.created", []byte(`{}`))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// the stalled connection is dropped, and the event is published again with a new connection
+	require.NoError(t, p.Publish(context.Background(), "gitea.actions.user2.repo1.run.created", []byte(`{}`)))
+	assert.EqualValues(t, 2, published.Load())
+}
+
+func TestNATSPublisherAuthorizationViolation(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("INFO {\"max_payload\":1048576,\"auth_required\":true}\r\n"))
+		_, _ = bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte("-ERR 'Authorization Violation'\r\n"))
+	}()
+
+	p, err := newNATSPublisher("nats://user:wrong@" + listener.Addr().String())
+	require.NoError(t, err)
+	err = p.Publish(context.Background(), "gitea.actions.user2.repo1.run.created", []byte(`{}`))
+	assert.ErrorContains(t, err, "Authorization Violation")
+
+	_, err = newNATSPublisher("http://127.0.0.1:4222")
+	assert.Error(t, err)
+}
@@ -0,0 +1,326 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/auth/source/oauth2"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// The files of an evidence bundle
+const (
+	EvidenceManifestFile  = "manifest.json"
+	EvidenceSignatureFile = "manifest.json.jwt" // the JWT signed by the key of the OAuth2 provider, see EvidenceClaims
+)
+
+// EvidenceManifest describes what a run executed and produced, for audits
+type EvidenceManifest struct {
+	Version    int                  `json:"version"`
+	Generated  time.Time            `json:"generated_at"`
+	Repository string               `json:"repository"`
+	Run        *EvidenceRun         `json:"run"`
+	Jobs       []*EvidenceJob       `json:"jobs"`
+	Artifacts  []*EvidenceArtifact  `json:"artifacts"`
+	Approval   *EvidenceRunApproval `json:"approval"`
+}
+
+// EvidenceRun describes the run of an evidence bundle
+type EvidenceRun struct {
+	ID          int64     `json:"id"`
+	Number      int64     `json:"number"`
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	WorkflowID  string    `json:"workflow_id"`
+	Event       string    `json:"event"`
+	Ref         string    `json:"ref"`
+	CommitSHA   string    `json:"commit_sha"`
+	TriggerUser string    `json:"trigger_user"`
+	Status      string    `json:"status"`
+	Created     time.Time `json:"created_at"`
+	Started     time.Time `json:"started_at"`
+	Stopped     time.Time `json:"stopped_at"`
+}
+
+// EvidenceRunApproval describes whether the run needed an approval to start, and who approved it
+type EvidenceRunApproval struct {
+	Required   bool   `json:"required"`
+	ApprovedBy string `json:"approved_by,omitempty"`
+}
+
+// EvidenceJob describes a job of the run and its latest attempt
+type EvidenceJob struct {
+	ID      int64    `json:"id"`
+	JobID   string   `json:"job_id"`
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Attempt int64    `json:"attempt"`
+	RunsOn  []string `json:"runs_on"`
+	Runner  string   `json:"runner,omitempty"`
+	// the access of the token of the job to the repository, read or write
+	TokenPermissions string `json:"token_permissions,omitempty"`
+	// the workflow definition of the job, it's in the bundle
+	Workflow *EvidenceFile `json:"workflow"`
+	// the digest of the logs, null if the job hasn't run
+	Logs    *EvidenceLogs `json:"logs"`
+	Started time.Time     `json:"started_at"`
+	Stopped time.Time     `json:"stopped_at"`
+}
+
+// EvidenceFile is the digest of a file
+type EvidenceFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// EvidenceLogs is the digest of the logs of a job, the logs aren't in the bundle
+type EvidenceLogs struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+	// the logs have been cleaned up by the retention policy, so they couldn't be digested
+	Expired bool `json:"expired"`
+}
+
+// EvidenceArtifact is the digest of a file of an artifact uploaded by the run, the files aren't in the bundle
+type EvidenceArtifact struct {
+	Name string `json:"name"`
+	EvidenceFile
+	ContentEncoding string `json:"content_encoding,omitempty"` // the digest is of the stored file, e.g. it's gzipped
+}
+
+// EvidenceClaims are the claims of the signature of an evidence bundle, the subject is the URL of the run
+type EvidenceClaims struct {
+	ManifestSHA256 string `json:"manifest_sha256"`
+	jwt.RegisteredClaims
+}
+
+// WriteEvidenceBundle writes the evidence bundle of the run as a zip file, which contains the manifest,
+// the workflow definitions of the jobs, and the signature of the manifest.
+func WriteEvidenceBundle(ctx context.Context, run *actions_model.ActionRun, w io.Writer) error {
+	if !run.Status.IsDone() {
+		return util.NewInvalidArgumentErrorf("run %d isn't done", run.ID)
+	}
+	manifest, workflows, err := buildEvidenceManifest(ctx, run)
+	if err != nil {
+		return err
+	}
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	signature, err := signEvidenceManifest(manifestContent, manifest.Run.URL, manifest.Generated)
+	if err != nil {
+		return fmt.Errorf("signEvidenceManifest: %w", err)
+	}
+
+	writer := zip.NewWriter(w)
+	write := func(name string, content []byte) error {
+		f, err := writer.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(content)
+		return err
+	}
+	if err := write(EvidenceManifestFile, manifestContent); err != nil {
+		return err
+	}
+	if err := write(EvidenceSignatureFile, []byte(signature)); err != nil {
+		return err
+	}
+	for _, job := range manifest.Jobs {
+		if err := write(job.Workflow.Path, workflows[job.ID]); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// buildEvidenceManifest returns the manifest of the run and the workflow definitions of its jobs
+func buildEvidenceManifest(ctx context.Context, run *actions_model.ActionRun) (*EvidenceManifest, map[int64][]byte, error) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, nil, err
+	}
+	manifest := &EvidenceManifest{
+		Version:    1,
+		Generated:  time.Now().UTC(),
+		Repository: run.Repo.FullName(),
+		Run: &EvidenceRun{
+			ID:          run.ID,
+			Number:      run.Index,
+			URL:         run.HTMLURL(),
+			Title:       run.Title,
+			WorkflowID:  run.WorkflowID,
+			Event:       string(run.Event),
+			Ref:         run.Ref,
+			CommitSHA:   run.CommitSHA,
+			TriggerUser: run.TriggerUser.Name,
+			Status:      run.Status.String(),
+			Created:     run.Created.AsTime().UTC(),
+			Started:     run.Started.AsTime().UTC(),
+			Stopped:     run.Stopped.AsTime().UTC(),
+		},
+		Approval:  &EvidenceRunApproval{Required: run.NeedApproval || run.ApprovedBy > 0},
+		Jobs:      []*EvidenceJob{},
+		Artifacts: []*EvidenceArtifact{},
+	}
+	if run.ApprovedBy > 0 {
+		approver, err := user_model.GetPossibleUserByID(ctx, run.ApprovedBy)
+		if err != nil {
+			return nil, nil, err
+		}
+		manifest.Approval.ApprovedBy = approver.Name
+	}
+
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	workflows := make(map[int64][]byte, len(jobs))
+	for _, job := range jobs {
+		workflows[job.ID] = job.WorkflowPayload
+		sum := sha256.Sum256(job.WorkflowPayload)
+		evidence := &EvidenceJob{
+			ID:      job.ID,
+			JobID:   job.JobID,
+			Name:    job.Name,
+			Status:  job.Status.String(),
+			Attempt: job.Attempt,
+			RunsOn:  job.RunsOn,
+			Workflow: &EvidenceFile{
+				Path:   fmt.Sprintf("workflows/%d.yml", job.ID),
+				Size:   int64(len(job.WorkflowPayload)),
+				SHA256: hex.EncodeToString(sum[:]),
+			},
+			Started: job.Started.AsTime().UTC(),
+			Stopped: job.Stopped.AsTime().UTC(),
+		}
+		if job.TaskID > 0 {
+			if err := fillEvidenceTask(ctx, evidence, job.TaskID); err != nil {
+				return nil, nil, err
+			}
+		}
+		manifest.Jobs = append(manifest.Jobs, evidence)
+	}
+
+	artifacts, err := db.Find[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
+		RunID:  run.ID,
+		Status: int(actions_model.ArtifactStatusUploadConfirmed),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, artifact := range artifacts {
+		size, digest, err := digestStoredFile(storage.ActionsArtifacts, artifact.StoragePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("digest artifact %q: %w", artifact.ArtifactPath, err)
+		}
+		manifest.Artifacts = append(manifest.Artifacts, &EvidenceArtifact{
+			Name:            artifact.ArtifactName,
+			EvidenceFile:    EvidenceFile{Path: artifact.ArtifactPath, Size: size, SHA256: digest},
+			ContentEncoding: artifact.ContentEncoding,
+		})
+	}
+	return manifest, workflows, nil
+}
+
+// fillEvidenceTask fills the runner, the token permissions and the digest of the logs of the latest attempt of the job
+func fillEvidenceTask(ctx context.Context, evidence *EvidenceJob, taskID int64) error {
+	task, err := actions_model.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if runner, err := actions_model.GetRunnerByID(ctx, task.RunnerID); err == nil {
+		evidence.Runner = runner.Name
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return err
+	}
+	mode, err := task.TokenAccessMode(ctx)
+	if err != nil {
+		return err
+	}
+	evidence.TokenPermissions = mode.ToString()
+
+	evidence.Logs = &EvidenceLogs{Size: task.LogSize, Expired: task.LogExpired}
+	if task.LogExpired {
+		return nil
+	}
+	reader, err := actions.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
+	if err != nil {
+		return fmt.Errorf("OpenLogs: %w", err)
+	}
+	defer reader.Close()
+	h := sha256.New()
+	if evidence.Logs.Size, err = io.Copy(h, reader); err != nil {
+		return err
+	}
+	evidence.Logs.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+func digestStoredFile(s storage.ObjectStorage, path string) (int64, string, error) {
+	f, err := s.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func signEvidenceManifest(manifest []byte, runURL string, generated time.Time) (string, error) {
+	sum := sha256.Sum256(manifest)
+	claims := &EvidenceClaims{
+		ManifestSHA256: hex.EncodeToString(sum[:]),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   setting.AppURL,
+			Subject:  runURL,
+			IssuedAt: jwt.NewNumericDate(generated),
+		},
+	}
+	token := jwt.NewWithClaims(oauth2.DefaultSigningKey.SigningMethod(), claims)
+	oauth2.DefaultSigningKey.PreProcessToken(token)
+	return token.SignedString(oauth2.DefaultSigningKey.SignKey())
+}
+
+// VerifyEvidenceSignature checks that the signature of an evidence bundle is signed by this instance for the manifest
+func VerifyEvidenceSignature(manifest []byte, signature string) (*EvidenceClaims, error) {
+	claims := &EvidenceClaims{}
+	_, err := jwt.ParseWithClaims(signature, claims, func(token *jwt.Token) (any, error) {
+		if token.Method == nil || token.Method.Alg() != oauth2.DefaultSigningKey.SigningMethod().Alg() {
+			return nil, fmt.Errorf("unexpected signing algo: %v", token.Header["alg"])
+		}
+		return oauth2.DefaultSigningKey.VerifyKey(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(manifest)
+	if claims.ManifestSHA256 != hex.EncodeToString(sum[:]) {
+		return nil, fmt.Errorf("the signature isn't for the manifest")
+	}
+	return claims, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/auth/source/oauth2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEvidenceBundle(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	key, err := oauth2.CreateJWTSigningKey("HS256", []byte("evidence-secret"))
+	require.NoError(t, err)
+	defer test.MockVariableValue(&oauth2.DefaultSigningKey, key)()
+	logs, err := storage.NewLocalStorage(context.Background(), &setting.Storage{Path: t.TempDir()})
+	require.NoError(t, err)
+	artifacts, err := storage.NewLocalStorage(context.Background(), &setting.Storage{Path: t.TempDir()})
+	require.NoError(t, err)
+	defer test.MockVariableValue(&storage.Actions, logs)()
+	defer test.MockVariableValue(&storage.ActionsArtifacts, artifacts)()
+
+	digest := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+	_, err = logs.Save("artifact-test2/2f/47.log", strings.NewReader("the logs"), -1)
+	require.NoError(t, err)
+	_, err = artifacts.Save("791/report.txt", strings.NewReader("the report"), -1)
+	require.NoError(t, err)
+	require.NoError(t, db.Insert(ctx, &actions_model.ActionArtifact{
+		RunID: 791, RepoID: 4, OwnerID: 1, CommitSHA: "c2d72f548424103f01ee1dc02889c1e2bff816b0",
+		ArtifactName: "report", ArtifactPath: "report.txt", StoragePath: "791/report.txt", FileSize: 10,
+		Status: int64(actions_model.ArtifactStatusUploadConfirmed),
+	}))
+	_, err = db.GetEngine(ctx).ID(192).Cols("workflow_payload").Update(&actions_model.ActionRunJob{WorkflowPayload: []byte("name: test\n")})
+	require.NoError(t, err)
+
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteEvidenceBundle(ctx, run, buf))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+	}
+	assert.Len(t, files, 3)
+	assert.Equal(t, "name: test\n", string(files["workflows/192.yml"]))
+
+	manifest := &EvidenceManifest{}
+	require.NoError(t, json.Unmarshal(files[EvidenceManifestFile], manifest))
+	assert.Equal(t, "user5/repo4", manifest.Repository)
+	assert.EqualValues(t, 187, manifest.Run.Number)
+	assert.Equal(t, "success", manifest.Run.Status)
+	assert.False(t, manifest.Approval.Required)
+	if assert.Len(t, manifest.Jobs, 1) {
+		job := manifest.Jobs[0]
+		assert.Equal(t, "job_2", job.JobID)
+		assert.Equal(t, digest("name: test\n"), job.Workflow.SHA256)
+		assert.Equal(t, &EvidenceLogs{Size: 8, SHA256: digest("the logs")}, job.Logs)
+		assert.NotEmpty(t, job.TokenPermissions)
+	}
+	if assert.Len(t, manifest.Artifacts, 1) {
+		assert.Equal(t, "report", manifest.Artifacts[0].Name)
+		assert.Equal(t, EvidenceFile{Path: "report.txt", Size: 10, SHA256: digest("the report")}, manifest.Artifacts[0].EvidenceFile)
+	}
+
+	claims, err := VerifyEvidenceSignature(files[EvidenceManifestFile], string(files[EvidenceSignatureFile]))
+	require.NoError(t, err)
+	assert.Equal(t, run.HTMLURL(), claims.Subject)
+	_, err = VerifyEvidenceSignature(append(files[EvidenceManifestFile], ' '), string(files[EvidenceSignatureFile]))
+	assert.Error(t, err)
+
+	// the evidence of a run isn't complete until the run is done
+	run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	run.Status = actions_model.StatusRunning
+	assert.ErrorIs(t, WriteEvidenceBundle(ctx, run, io.Discard), util.ErrInvalidArgument)
+}
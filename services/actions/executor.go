@@ -0,0 +1,124 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+// Executor executes the tasks of runners which are driven by the server.
+// By default, a runner is an act_runner which polls tasks and reports their states via the runner API by itself,
+// but a runner can be configured to use an executor instead, e.g. a backend which runs tasks as Kubernetes Jobs.
+//
+// The executor reports the progress of a task with UpdateTaskByState and AppendTaskLogs,
+// just like act_runner does via the runner API, and it should stop the task once UpdateTaskByState returns a done task.
+type Executor interface {
+	// Name returns the unique name of the executor, it's stored in the runners using the executor
+	Name() string
+	// Execute starts executing the task picked for the runner, it should return once the task has been started.
+	Execute(ctx context.Context, runner *actions_model.ActionRunner, task *runnerv1.Task) error
+}
+
+var (
+	executorsMu sync.RWMutex
+	executors   = map[string]Executor{}
+)
+
+// RegisterExecutor registers an executor, it should be called before Init
+func RegisterExecutor(executor Executor) {
+	executorsMu.Lock()
+	defer executorsMu.Unlock()
+	if _, ok := executors[executor.Name()]; ok {
+		panic(fmt.Sprintf("executor %q has been registered", executor.Name()))
+	}
+	executors[executor.Name()] = executor
+}
+
+// GetExecutor returns the registered executor of the name
+func GetExecutor(name string) (Executor, bool) {
+	executorsMu.RLock()
+	defer executorsMu.RUnlock()
+	executor, ok := executors[name]
+	return executor, ok
+}
+
+// ExecutorNames returns the names of all registered executors
+func ExecutorNames() []string {
+	executorsMu.RLock()
+	defer executorsMu.RUnlock()
+	names := make([]string, 0, len(executors))
+	for name := range executors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maxExecutorTasksPerTick is the max number of tasks dispatched to the executor of a runner in one tick,
+// so a runner with many waiting jobs can't hold up the others, the rest will be dispatched in the next ticks.
+const maxExecutorTasksPerTick = 10
+
+// DispatchExecutorTasks picks tasks for the runners driven by executors, and hands the tasks over to the executors
+func DispatchExecutorTasks(ctx context.Context) error {
+	if len(ExecutorNames()) == 0 {
+		return nil
+	}
+
+	runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
+		WithExecutor: true,
+	})
+	if err != nil {
+		return fmt.Errorf("find runners: %w", err)
+	}
+
+	for _, runner := range runners {
+		executor, ok := GetExecutor(runner.Executor)
+		if !ok {
+			log.Warn("Executor %q of runner %d isn't registered", runner.Executor, runner.ID)
+			continue
+		}
+
+		// the runner is online as long as its executor is available
+		runner.LastOnline = timeutil.TimeStampNow()
+		if err := actions_model.UpdateRunner(ctx, runner, "last_online"); err != nil {
+			log.Error("Failed to update runner %d: %v", runner.ID, err)
+		}
+
+		dispatchRunnerTasks(ctx, executor, runner, PickTask)
+	}
+	return nil
+}
+
+// dispatchRunnerTasks picks tasks for the runner and hands them over to the executor, it returns the number of dispatched tasks
+func dispatchRunnerTasks(ctx context.Context, executor Executor, runner *actions_model.ActionRunner,
+	pick func(context.Context, *actions_model.ActionRunner) (*runnerv1.Task, bool, error),
+) int {
+	for i := 0; i < maxExecutorTasksPerTick; i++ {
+		task, ok, err := pick(ctx, runner)
+		if err != nil {
+			log.Error("Failed to pick task for runner %d: %v", runner.ID, err)
+			return i
+		} else if !ok {
+			return i
+		}
+		if err := executor.Execute(ctx, runner, task); err != nil {
+			log.Error("Executor %q failed to execute task %d: %v", executor.Name(), task.Id, err)
+			if err := actions_model.StopTaskWithErrorClass(ctx, task.Id, actions_model.StatusFailure, actions_model.TaskErrorClassInfrastructure); err != nil {
+				log.Error("Failed to stop task %d: %v", task.Id, err)
+			}
+			return i
+		}
+	}
+	return maxExecutorTasksPerTick
+}
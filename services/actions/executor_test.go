@@ -0,0 +1,132 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/test"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExecutor struct {
+	name     string
+	err      error // the error of executing a task
+	executed []int64
+}
+
+func (e *fakeExecutor) Name() string {
+	return e.name
+}
+
+func (e *fakeExecutor) Execute(_ context.Context, _ *actions_model.ActionRunner, task *runnerv1.Task) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.executed = append(e.executed, task.Id)
+	return nil
+}
+
+func TestRegisterExecutor(t *testing.T) {
+	defer test.MockVariableValue(&executors, map[string]Executor{})()
+
+	RegisterExecutor(&fakeExecutor{name: "kubernetes"})
+	RegisterExecutor(&fakeExecutor{name: "fake"})
+	assert.Equal(t, []string{"fake", "kubernetes"}, ExecutorNames())
+	executor, ok := GetExecutor("kubernetes")
+	assert.True(t, ok)
+	assert.Equal(t, "kubernetes", executor.Name())
+	_, ok = GetExecutor("docker")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		RegisterExecutor(&fakeExecutor{name: "fake"})
+	})
+}
+
+func TestDispatchRunnerTasks(t *testing.T) {
+	runner := &actions_model.ActionRunner{ID: 1, Executor: "fake"}
+	newPicker := func(waiting int) func(context.Context, *actions_model.ActionRunner) (*runnerv1.Task, bool, error) {
+		var picked int64
+		return func(context.Context, *actions_model.ActionRunner) (*runnerv1.Task, bool, error) {
+			if int(picked) >= waiting {
+				return nil, false, nil
+			}
+			picked++
+			return &runnerv1.Task{Id: picked}, true, nil
+		}
+	}
+
+	t.Run("all waiting tasks", func(t *testing.T) {
+		executor := &fakeExecutor{name: "fake"}
+		assert.Equal(t, 3, dispatchRunnerTasks(context.Background(), executor, runner, newPicker(3)))
+		assert.Equal(t, []int64{1, 2, 3}, executor.executed)
+	})
+
+	t.Run("capped per tick", func(t *testing.T) {
+		executor := &fakeExecutor{name: "fake"}
+		assert.Equal(t, maxExecutorTasksPerTick, dispatchRunnerTasks(context.Background(), executor, runner, newPicker(maxExecutorTasksPerTick*3)))
+		assert.Len(t, executor.executed, maxExecutorTasksPerTick)
+	})
+}
+
+func TestDispatchRunnerTasksExecuteError(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// task 47 of job 192 has been picked for the runner
+	_, err := db.GetEngine(ctx).Exec("UPDATE action_run_job SET status = ? WHERE id = 192", actions_model.StatusRunning)
+	require.NoError(t, err)
+	_, err = db.GetEngine(ctx).Exec("UPDATE action_run SET status = ? WHERE id = 791", actions_model.StatusRunning)
+	require.NoError(t, err)
+	picked := false
+	pick := func(context.Context, *actions_model.ActionRunner) (*runnerv1.Task, bool, error) {
+		if picked {
+			return nil, false, nil
+		}
+		picked = true
+		return &runnerv1.Task{Id: 47}, true, nil
+	}
+
+	// the task which couldn't be started fails as an infrastructure error, and the runner stops dispatching in this tick
+	executor := &fakeExecutor{name: "fake", err: errors.New("no nodes available")}
+	assert.Equal(t, 0, dispatchRunnerTasks(ctx, executor, &actions_model.ActionRunner{ID: 1, Executor: "fake"}, pick))
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	assert.Equal(t, actions_model.StatusFailure, task.Status)
+	assert.Equal(t, actions_model.TaskErrorClassInfrastructure, task.ErrorClass)
+}
+
+func TestDispatchExecutorTasks(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	defer test.MockVariableValue(&executors, map[string]Executor{})()
+
+	// nothing is done without executors
+	require.NoError(t, DispatchExecutorTasks(ctx))
+
+	executor := &fakeExecutor{name: "fake"}
+	RegisterExecutor(executor)
+	driven := &actions_model.ActionRunner{UUID: "executor-driven", Name: "driven", TokenHash: "executor-driven", AgentLabels: []string{"executor-test"}, Executor: "fake"}
+	unknown := &actions_model.ActionRunner{UUID: "executor-unknown", Name: "unknown", TokenHash: "executor-unknown", AgentLabels: []string{"executor-test"}, Executor: "docker"}
+	require.NoError(t, db.Insert(ctx, driven, unknown))
+
+	require.NoError(t, DispatchExecutorTasks(ctx))
+	// no jobs run on the labels of the runners
+	assert.Empty(t, executor.executed)
+	// the runners are online as long as their executors are registered
+	assert.NotZero(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{ID: driven.ID}).LastOnline)
+	assert.Zero(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{ID: unknown.ID}).LastOnline)
+
+	// the runners have no fixtures, so they would be left for the other tests
+	_, err := db.GetEngine(ctx).Exec("DELETE FROM action_runner WHERE id IN (?, ?)", driven.ID, unknown.ID)
+	require.NoError(t, err)
+}
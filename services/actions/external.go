@@ -0,0 +1,164 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ExternalJobOptions represents a job reported by an external CI system
+type ExternalJobOptions struct {
+	Name   string
+	Status actions_model.Status
+	URL    string
+}
+
+// ExternalRunOptions represents a run reported by an external CI system
+type ExternalRunOptions struct {
+	Name      string
+	System    string
+	URL       string
+	Ref       string
+	CommitSHA string
+	Jobs      []*ExternalJobOptions
+}
+
+// CreateExternalRun creates a run reported by an external CI system,
+// its jobs are reported as commit statuses like the jobs of Gitea Actions.
+func CreateExternalRun(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, opts *ExternalRunOptions) (*actions_model.ActionRun, error) {
+	if opts.System == "" {
+		return nil, util.NewInvalidArgumentErrorf("the external system of the run is required")
+	}
+	if len(opts.Jobs) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("an external run requires at least one job")
+	}
+	if err := validateExternalJobs(opts.Jobs); err != nil {
+		return nil, err
+	}
+
+	run := &actions_model.ActionRun{
+		Title:          opts.Name,
+		RepoID:         repo.ID,
+		Repo:           repo,
+		OwnerID:        repo.OwnerID,
+		WorkflowID:     opts.Name,
+		TriggerUserID:  doer.ID,
+		TriggerUser:    doer,
+		Ref:            opts.Ref,
+		CommitSHA:      opts.CommitSHA,
+		ExternalSystem: opts.System,
+		ExternalURL:    opts.URL,
+	}
+	jobs := make([]*actions_model.ActionRunJob, 0, len(opts.Jobs))
+	for _, v := range opts.Jobs {
+		jobs = append(jobs, &actions_model.ActionRunJob{
+			Name:        v.Name,
+			Status:      v.Status,
+			ExternalURL: v.URL,
+		})
+	}
+	if err := actions_model.InsertExternalRun(ctx, run, jobs); err != nil {
+		return nil, fmt.Errorf("InsertExternalRun: %w", err)
+	}
+
+	if err := EmitOutboxEvents(ctx, run.ID); err != nil {
+		log.Error("EmitOutboxEvents: %v", err)
+	}
+	return run, nil
+}
+
+// validateExternalJobs checks the names of the jobs are unique, since the jobs of an external run are identified by their names
+func validateExternalJobs(jobs []*ExternalJobOptions) error {
+	names := make(container.Set[string], len(jobs))
+	for _, v := range jobs {
+		if !names.Add(v.Name) {
+			return util.NewInvalidArgumentErrorf("duplicate job name %q", v.Name)
+		}
+	}
+	return nil
+}
+
+// UpdateExternalRun updates the link of an external run and the statuses of its jobs,
+// the jobs which don't exist yet will be added to the run.
+func UpdateExternalRun(ctx context.Context, run *actions_model.ActionRun, url string, jobs []*ExternalJobOptions) error {
+	if !run.IsExternal() {
+		return util.NewInvalidArgumentErrorf("run %d isn't an external run", run.Index)
+	}
+	if err := validateExternalJobs(jobs); err != nil {
+		return err
+	}
+
+	var changed []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if url != "" && url != run.ExternalURL {
+			run.ExternalURL = url
+			if err := actions_model.UpdateRun(ctx, run, "external_url"); err != nil {
+				return err
+			}
+		}
+
+		existing, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+		if err != nil {
+			return err
+		}
+		byName := make(map[string]*actions_model.ActionRunJob, len(existing))
+		for _, job := range existing {
+			byName[job.Name] = job
+		}
+
+		for _, v := range jobs {
+			job, ok := byName[v.Name]
+			if !ok {
+				job = &actions_model.ActionRunJob{
+					Name:        v.Name,
+					Status:      v.Status,
+					ExternalURL: v.URL,
+				}
+				if err := actions_model.InsertExternalRunJob(ctx, run, job); err != nil {
+					return err
+				}
+				byName[job.Name] = job
+				changed = append(changed, job)
+				continue
+			}
+			if job.Status == v.Status && (v.URL == "" || job.ExternalURL == v.URL) {
+				continue
+			}
+			if job.Status.IsDone() && !v.Status.IsDone() {
+				// the job has been restarted in the external system
+				job.Started = 0
+				job.Stopped = 0
+			}
+			job.Status = v.Status
+			if v.URL != "" {
+				job.ExternalURL = v.URL
+			}
+			actions_model.SetExternalJobTimes(job)
+			if _, err := actions_model.UpdateRunJob(ctx, job, nil, "status", "started", "stopped", "external_url"); err != nil {
+				return err
+			}
+			changed = append(changed, job)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	CreateCommitStatus(ctx, changed...)
+	if len(changed) > 0 {
+		if err := EmitOutboxEvents(ctx, run.ID); err != nil {
+			log.Error("EmitOutboxEvents: %v", err)
+		}
+	}
+	return nil
+}
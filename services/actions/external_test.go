@@ -0,0 +1,45 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalRunDuplicateJobNames(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	jobs := []*ExternalJobOptions{
+		{Name: "build", Status: actions_model.StatusSuccess},
+		{Name: "test", Status: actions_model.StatusRunning},
+		{Name: "build", Status: actions_model.StatusFailure},
+	}
+
+	_, err := CreateExternalRun(db.DefaultContext, repo, doer, &ExternalRunOptions{
+		Name:      "ci",
+		System:    "jenkins",
+		Ref:       "refs/heads/master",
+		CommitSHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		Jobs:      jobs,
+	})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID, ExternalSystem: "jenkins"})
+
+	run := &actions_model.ActionRun{RepoID: repo.ID, ExternalSystem: "jenkins"}
+	assert.ErrorIs(t, UpdateExternalRun(db.DefaultContext, run, "", jobs), util.ErrInvalidArgument)
+
+	assert.NoError(t, validateExternalJobs(jobs[:2]))
+	assert.NoError(t, validateExternalJobs(nil))
+}
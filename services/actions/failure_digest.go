@@ -0,0 +1,133 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// How often the users receive the digest of the failed and flaky workflows, empty means never
+const (
+	FailureDigestDaily  = "daily"
+	FailureDigestWeekly = "weekly"
+)
+
+var failureDigestPeriods = map[string]time.Duration{
+	FailureDigestDaily:  24 * time.Hour,
+	FailureDigestWeekly: 7 * 24 * time.Hour,
+}
+
+// failureDigestDrift is how much earlier than the end of the period a digest could be sent,
+// so the digest isn't delayed a whole period if the cron task runs a little earlier than last time
+const failureDigestDrift = time.Hour
+
+// IsValidFailureDigest returns whether it's a valid frequency of the digest, empty means the digest is disabled
+func IsValidFailureDigest(frequency string) bool {
+	_, ok := failureDigestPeriods[frequency]
+	return ok || frequency == ""
+}
+
+// SendFailureDigests sends the digests of the failed and flaky workflows to the users who opted in and whose periods have passed.
+// A digest only includes the workflows of the repositories the user could write Actions of.
+func SendFailureDigests(ctx context.Context) error {
+	if setting.MailService == nil {
+		return nil
+	}
+	subscriptions, err := user_model.FindSettingsByKey(ctx, user_model.SettingsKeyActionsFailureDigest)
+	if err != nil {
+		return fmt.Errorf("FindSettingsByKey: %w", err)
+	}
+
+	now := time.Now()
+	failuresOfPeriods := make(map[string][]*actions_model.WorkflowFailures, len(failureDigestPeriods))
+	repos := make(map[int64]*repo_model.Repository)
+	for _, subscription := range subscriptions {
+		period, ok := failureDigestPeriods[subscription.SettingValue]
+		if !ok {
+			continue
+		}
+		sent, err := user_model.GetUserSetting(ctx, subscription.UserID, user_model.SettingsKeyActionsFailureDigestSent, "0")
+		if err != nil {
+			return fmt.Errorf("GetUserSetting: %w", err)
+		}
+		if sentUnix, _ := strconv.ParseInt(sent, 10, 64); now.Sub(time.Unix(sentUnix, 0)) < period-failureDigestDrift {
+			continue
+		}
+
+		failures, ok := failuresOfPeriods[subscription.SettingValue]
+		if !ok {
+			failures, err = actions_model.GetWorkflowFailures(ctx, timeutil.TimeStamp(now.Add(-period).Unix()))
+			if err != nil {
+				return fmt.Errorf("GetWorkflowFailures: %w", err)
+			}
+			failuresOfPeriods[subscription.SettingValue] = failures
+		}
+
+		if err := sendFailureDigest(ctx, subscription.UserID, subscription.SettingValue == FailureDigestWeekly, failures, repos); err != nil {
+			log.Error("Cannot send the digest of the failed workflows to user %d: %v", subscription.UserID, err)
+			continue
+		}
+		if err := user_model.SetUserSetting(ctx, subscription.UserID, user_model.SettingsKeyActionsFailureDigestSent, strconv.FormatInt(now.Unix(), 10)); err != nil {
+			return fmt.Errorf("SetUserSetting: %w", err)
+		}
+	}
+	return nil
+}
+
+// sendFailureDigest sends the failures of the repositories which the user could write Actions of to the user,
+// nothing is sent if there isn't any. repos caches the repositories among the users.
+func sendFailureDigest(ctx context.Context, userID int64, weekly bool, failures []*actions_model.WorkflowFailures, repos map[int64]*repo_model.Repository) error {
+	user, err := user_model.GetUserByID(ctx, userID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive || user.ProhibitLogin || user.EmailNotificationsPreference == user_model.EmailNotificationsDisabled {
+		return nil
+	}
+
+	items := make([]*mailer.ActionsFailureDigestItem, 0, len(failures))
+	for _, f := range failures {
+		repo, ok := repos[f.RepoID]
+		if !ok {
+			repo, err = repo_model.GetRepositoryByID(ctx, f.RepoID)
+			if err != nil && !errors.Is(err, util.ErrNotExist) {
+				return err
+			}
+			repos[f.RepoID] = repo
+		}
+		if repo == nil {
+			continue
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, user)
+		if err != nil {
+			return err
+		}
+		if !perm.CanWrite(unit.TypeActions) {
+			continue
+		}
+		items = append(items, &mailer.ActionsFailureDigestItem{Repo: repo, Failures: f})
+	}
+	if len(items) > 0 {
+		mailer.SendActionsFailureDigestMail(user, weekly, items)
+	}
+	return nil
+}
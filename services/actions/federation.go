@@ -0,0 +1,206 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
+)
+
+// FederationTokenHeader is the header of the federation token of the statuses pushed by the peer instances
+const FederationTokenHeader = "X-Gitea-Federation-Token"
+
+// federationRequestTimeout is the timeout of pushing a status to a peer instance
+const federationRequestTimeout = 30 * time.Second
+
+// federatedStatus is a status of a job to push to the federation peer of the repository
+type federatedStatus struct {
+	RepoID int64
+	Status *api.FederatedActionStatusOption
+}
+
+var federationQueue *queue.WorkerPoolQueue[*federatedStatus]
+
+// ParseFederationPeerURL returns the API endpoint of the peer instance receiving the statuses
+// and the full name of the repository on it, by the URL of the repository on the peer instance
+func ParseFederationPeerURL(rawURL string) (endpoint, repoFullName string, err error) {
+	u, err := url.Parse(strings.TrimSuffix(rawURL, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", util.NewInvalidArgumentErrorf("invalid url of federation peer %q", rawURL)
+	}
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", util.NewInvalidArgumentErrorf("url of federation peer %q isn't the url of a repository", rawURL)
+	}
+	repoFullName = strings.TrimSuffix(parts[len(parts)-2]+"/"+parts[len(parts)-1], ".git")
+	u.Path = strings.Join(append(parts[:len(parts)-2], "api/v1/repos/actions/federation/statuses"), "/")
+	if !strings.HasPrefix(u.Path, "/") {
+		u.Path = "/" + u.Path
+	}
+	return u.String(), repoFullName, nil
+}
+
+// EncryptFederationToken encrypts the federation token of the peer by the secret key to store it
+func EncryptFederationToken(token string) (string, error) {
+	return secret.EncryptSecret(setting.SecretKey, token)
+}
+
+// pushFederatedStatus queues the status to push to the federation peer of the repository, if it has one,
+// the target url must be absolute since the status is shown by the peer instance
+func pushFederatedStatus(repo *repo_model.Repository, cfg *repo_model.ActionsConfig, sha, targetURL string, status *git_model.CommitStatus) {
+	if cfg.FederationPeer == nil || federationQueue == nil {
+		return
+	}
+	if err := federationQueue.Push(&federatedStatus{
+		RepoID: repo.ID,
+		Status: &api.FederatedActionStatusOption{
+			SHA:         sha,
+			State:       status.State,
+			Context:     status.Context,
+			TargetURL:   targetURL,
+			Description: status.Description,
+		},
+	}); err != nil {
+		log.Error("Failed to queue the status of commit %s of repo %d for the federation peer: %v", sha, repo.ID, err)
+	}
+}
+
+func federationQueueHandler(items ...*federatedStatus) []*federatedStatus {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, item := range items {
+		// it isn't retried, the next status of the job will be pushed anyway
+		if err := sendFederatedStatus(ctx, item); err != nil {
+			log.Error("Failed to push the status of commit %s of repo %d to the federation peer: %v", item.Status.SHA, item.RepoID, err)
+		}
+	}
+	return nil
+}
+
+// sendFederatedStatus pushes the status to the federation peer of the repository with its token
+func sendFederatedStatus(ctx context.Context, item *federatedStatus) error {
+	repo, err := repo_model.GetRepositoryByID(ctx, item.RepoID)
+	if err != nil {
+		return err
+	}
+	actionsUnit, err := repo.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		return err
+	}
+	peer := actionsUnit.ActionsConfig().FederationPeer
+	if peer == nil {
+		// it has been disabled since the status was queued
+		return nil
+	}
+	endpoint, repoFullName, err := ParseFederationPeerURL(peer.URL)
+	if err != nil {
+		return err
+	}
+	token, err := secret.DecryptSecret(setting.SecretKey, peer.TokenEncrypted)
+	if err != nil {
+		return fmt.Errorf("decrypt federation token: %w", err)
+	}
+
+	status := *item.Status
+	status.Repository = repoFullName
+	body, err := json.Marshal(&status)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, federationRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FederationTokenHeader, token)
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: proxy.Proxy()},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// ReceiveFederatedStatus creates the status of a job pushed by a peer instance which has run it,
+// if the repository accepts the statuses of the peers with the token, see repo_model.ActionsConfig.VerifyFederationToken.
+// The status is created by the actions user, like the statuses of the jobs run by the instance.
+func ReceiveFederatedStatus(ctx context.Context, token string, opts *api.FederatedActionStatusOption) error {
+	ownerName, repoName, ok := strings.Cut(opts.Repository, "/")
+	if !ok {
+		return util.NewInvalidArgumentErrorf("invalid repository %q", opts.Repository)
+	}
+	switch opts.State {
+	case api.CommitStatusPending, api.CommitStatusSuccess, api.CommitStatusError, api.CommitStatusFailure, api.CommitStatusWarning:
+	default:
+		return util.NewInvalidArgumentErrorf("invalid state %q", opts.State)
+	}
+	if _, err := git.NewIDFromString(opts.SHA); err != nil {
+		return util.NewInvalidArgumentErrorf("invalid sha %q", opts.SHA)
+	}
+
+	// the repositories which don't exist are denied like the wrong tokens, so the private repositories aren't revealed
+	denied := util.NewPermissionDeniedErrorf("the federation token isn't accepted by repository %s", opts.Repository)
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			return denied
+		}
+		return err
+	}
+	actionsUnit, err := repo.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return denied
+		}
+		return err
+	}
+	if !actionsUnit.ActionsConfig().VerifyFederationToken(token) {
+		return denied
+	}
+
+	creator := user_model.NewActionsUser()
+	if err := commitstatus_service.CreateCommitStatus(ctx, repo, creator, opts.SHA, &git_model.CommitStatus{
+		SHA:         opts.SHA,
+		TargetURL:   opts.TargetURL,
+		Description: opts.Description,
+		Context:     opts.Context,
+		CreatorID:   creator.ID,
+		State:       opts.State,
+	}); err != nil {
+		if errors.As(err, &git.ErrNotExist{}) {
+			return util.NewNotExistErrorf("commit %s doesn't exist in repository %s", opts.SHA, opts.Repository)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFederationPeerURL(t *testing.T) {
+	endpoint, name, err := ParseFederationPeerURL("https://gitea.example.com/owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitea.example.com/api/v1/repos/actions/federation/statuses", endpoint)
+	assert.Equal(t, "owner/repo", name)
+
+	// the instance is served under a sub path
+	endpoint, name, err = ParseFederationPeerURL("https://example.com/gitea/owner/repo.git/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/gitea/api/v1/repos/actions/federation/statuses", endpoint)
+	assert.Equal(t, "owner/repo", name)
+
+	for _, u := range []string{"", "ftp://example.com/owner/repo", "https://example.com/owner", "owner/repo"} {
+		_, _, err := ParseFederationPeerURL(u)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument, u)
+	}
+}
+
+func TestFederatedStatus(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	defer test.MockVariableValue(&setting.SecretKey, "secret")()
+
+	const (
+		sha   = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+		token = "0123456789abcdef0123"
+	)
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	require.NoError(t, err)
+
+	status := &api.FederatedActionStatusOption{
+		Repository: "user2/repo1",
+		SHA:        sha,
+		State:      api.CommitStatusSuccess,
+		Context:    "ci / build (push)",
+		TargetURL:  "https://ci.example.com/user2/repo1/actions/runs/1/jobs/0",
+	}
+	// the statuses aren't accepted until the repository has a federation token
+	assert.ErrorIs(t, ReceiveFederatedStatus(ctx, token, status), util.ErrPermissionDenied)
+
+	actionsUnit.ActionsConfig().FederationTokenHash = repo_model.HashFederationToken(token)
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+	assert.ErrorIs(t, ReceiveFederatedStatus(ctx, "wrong", status), util.ErrPermissionDenied)
+	assert.ErrorIs(t, ReceiveFederatedStatus(ctx, token, &api.FederatedActionStatusOption{Repository: "user2/missing", SHA: sha, State: api.CommitStatusSuccess}), util.ErrPermissionDenied)
+	assert.ErrorIs(t, ReceiveFederatedStatus(ctx, token, &api.FederatedActionStatusOption{Repository: "user2/repo1", SHA: sha, State: "unknown"}), util.ErrInvalidArgument)
+
+	// the peer instance is the same instance in the test
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/repos/actions/federation/statuses", r.URL.Path)
+		opts := &api.FederatedActionStatusOption{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(opts))
+		if err := ReceiveFederatedStatus(ctx, r.Header.Get(FederationTokenHeader), opts); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	encrypted, err := EncryptFederationToken(token)
+	require.NoError(t, err)
+	actionsUnit.ActionsConfig().FederationPeer = &repo_model.ActionsFederationPeer{URL: server.URL + "/user2/repo1", TokenEncrypted: encrypted}
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+
+	require.NoError(t, sendFederatedStatus(ctx, &federatedStatus{RepoID: repo.ID, Status: status}))
+	created := unittest.AssertExistsAndLoadBean(t, &git_model.CommitStatus{RepoID: repo.ID, SHA: sha, Context: "ci / build (push)"})
+	assert.Equal(t, api.CommitStatusSuccess, created.State)
+	assert.Equal(t, status.TargetURL, created.TargetURL)
+
+	// the peer stops accepting the statuses
+	actionsUnit.ActionsConfig().FederationTokenHash = ""
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+	assert.Error(t, sendFederatedStatus(ctx, &federatedStatus{RepoID: repo.ID, Status: status}))
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ReleaseFreezeWindows releases the jobs frozen by the windows which have ended,
+// the runners are signaled so they pick the jobs at once instead of waiting for the next job to be queued.
+func ReleaseFreezeWindows(ctx context.Context) error {
+	windows, err := db.Find[actions_model.ActionFreezeWindow](ctx, actions_model.FindFreezeWindowsOptions{
+		AllScope: true,
+		EndedAt:  timeutil.TimeStampNow(),
+		Released: optional.Some(false),
+	})
+	if err != nil {
+		return err
+	}
+	for _, w := range windows {
+		if err := actions_model.ReleaseFreezeWindow(ctx, w); err != nil {
+			return err
+		}
+		log.Info("The jobs frozen by freeze window %q of owner %d are released", w.Name, w.OwnerID)
+	}
+	return nil
+}
+
+// DeleteFreezeWindow deletes the freeze window of the owner and releases the jobs it froze,
+// the owner is 0 for the windows of the instance.
+func DeleteFreezeWindow(ctx context.Context, ownerID, id int64) error {
+	w, err := actions_model.GetFreezeWindowByID(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if err := actions_model.DeleteFreezeWindow(ctx, ownerID, id); err != nil {
+		return err
+	}
+	if w.Released {
+		return nil
+	}
+	return actions_model.ReleaseFreezeWindow(ctx, w)
+}
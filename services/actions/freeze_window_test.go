@@ -0,0 +1,36 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseFreezeWindows(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	now := timeutil.TimeStampNow()
+
+	ended := &actions_model.ActionFreezeWindow{Name: "ended", Labels: []string{"deploy-*"}, StartUnix: now - 7200, EndUnix: now - 3600}
+	active := &actions_model.ActionFreezeWindow{Name: "active", Labels: []string{"deploy-*"}, StartUnix: now - 3600, EndUnix: now + 3600}
+	// the windows which have ended can't be created, so they're inserted directly
+	require.NoError(t, db.Insert(ctx, ended))
+	require.NoError(t, db.Insert(ctx, active))
+
+	require.NoError(t, ReleaseFreezeWindows(ctx))
+	assert.True(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionFreezeWindow{ID: ended.ID}).Released)
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionFreezeWindow{ID: active.ID}).Released)
+
+	// the windows deleted before they end release their jobs at once
+	require.NoError(t, DeleteFreezeWindow(ctx, 0, active.ID))
+	unittest.AssertNotExistsBean(t, &actions_model.ActionFreezeWindow{ID: active.ID})
+}
@@ -0,0 +1,277 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
+)
+
+// MaxHandoffBlobSize is the max size of a handoff blob, they are meant to be small transient files
+const MaxHandoffBlobSize = 512 * 1024 * 1024
+
+var handoffBlobNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,254}$`)
+
+// handoffChunkSize is the size of the plaintext chunks of a blob, every chunk is sealed with AES-GCM on its own,
+// so a blob can be encrypted and decrypted as a stream without loading it into memory.
+const handoffChunkSize = 64 * 1024
+
+// handoffAEAD returns the AES-GCM cipher of the blobs of the run, every run has its own key derived from the secret key
+func handoffAEAD(runID int64) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(setting.SecretKey + ":actions_handoff:" + strconv.FormatInt(runID, 10)))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// handoffChunkNonce returns the nonce of the chunk, it's the random nonce of the blob xor the index of the chunk
+func handoffChunkNonce(dst, nonce []byte, index uint64) []byte {
+	dst = append(dst[:0], nonce...)
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], index)
+	for i := range counter {
+		dst[len(dst)-len(counter)+i] ^= counter[i]
+	}
+	return dst
+}
+
+// handoffChunkAdditionalData marks the last chunk, so a truncated blob can't be decrypted
+func handoffChunkAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// handoffEncryptReader reads the plaintext from the source and returns the sealed chunks,
+// the last chunk is always shorter than handoffChunkSize, it's empty if the size of the plaintext is a multiple of handoffChunkSize.
+type handoffEncryptReader struct {
+	aead   cipher.AEAD
+	nonce  []byte
+	src    io.Reader
+	index  uint64
+	plain  []byte
+	sealed []byte
+	pos    int
+	done   bool
+}
+
+func newHandoffEncryptReader(aead cipher.AEAD, nonce []byte, src io.Reader) *handoffEncryptReader {
+	return &handoffEncryptReader{
+		aead:   aead,
+		nonce:  nonce,
+		src:    src,
+		plain:  make([]byte, handoffChunkSize),
+		sealed: make([]byte, 0, handoffChunkSize+aead.Overhead()),
+	}
+}
+
+func (r *handoffEncryptReader) Read(p []byte) (int, error) {
+	for r.pos == len(r.sealed) {
+		if r.done {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(r.src, r.plain)
+		last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !last {
+			return 0, err
+		}
+		nonce := handoffChunkNonce(make([]byte, 0, len(r.nonce)), r.nonce, r.index)
+		r.sealed = r.aead.Seal(r.sealed[:0], nonce, r.plain[:n], handoffChunkAdditionalData(last))
+		r.pos = 0
+		r.index++
+		r.done = last
+	}
+	n := copy(p, r.sealed[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// handoffDecryptReader reads the sealed chunks from the source and returns the plaintext
+type handoffDecryptReader struct {
+	aead   cipher.AEAD
+	nonce  []byte
+	src    io.Reader
+	index  uint64
+	sealed []byte
+	plain  []byte
+	pos    int
+	done   bool
+}
+
+func newHandoffDecryptReader(aead cipher.AEAD, nonce []byte, src io.Reader) *handoffDecryptReader {
+	return &handoffDecryptReader{
+		aead:   aead,
+		nonce:  nonce,
+		src:    src,
+		sealed: make([]byte, handoffChunkSize+aead.Overhead()),
+		plain:  make([]byte, 0, handoffChunkSize),
+	}
+}
+
+func (r *handoffDecryptReader) Read(p []byte) (int, error) {
+	for r.pos == len(r.plain) {
+		if r.done {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(r.src, r.sealed)
+		if errors.Is(err, io.EOF) {
+			return 0, errors.New("handoff blob is truncated")
+		}
+		last := errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !last {
+			return 0, err
+		}
+		nonce := handoffChunkNonce(make([]byte, 0, len(r.nonce)), r.nonce, r.index)
+		r.plain, err = r.aead.Open(r.plain[:0], nonce, r.sealed[:n], handoffChunkAdditionalData(last))
+		if err != nil {
+			return 0, fmt.Errorf("decrypt chunk %d of handoff blob: %w", r.index, err)
+		}
+		r.pos = 0
+		r.index++
+		r.done = last
+	}
+	n := copy(p, r.plain[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// UploadHandoffBlob encrypts the content and saves it as a handoff blob of the run of the task,
+// a blob with the same name will be replaced.
+func UploadHandoffBlob(ctx context.Context, task *actions_model.ActionTask, name string, r io.Reader) (*actions_model.ActionHandoffBlob, error) {
+	if !handoffBlobNamePattern.MatchString(name) {
+		return nil, util.NewInvalidArgumentErrorf("invalid handoff blob name %q", name)
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		return nil, err
+	}
+
+	aead, err := handoffAEAD(task.Job.RunID)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	storagePath := fmt.Sprintf("handoff/%d/%d/%s", task.Job.RunID%255, task.Job.RunID, gouuid.New().String())
+	limited := &io.LimitedReader{R: r, N: MaxHandoffBlobSize + 1}
+	if _, err := storage.ActionsArtifacts.Save(storagePath, io.MultiReader(bytes.NewReader(nonce), newHandoffEncryptReader(aead, nonce, limited)), -1); err != nil {
+		return nil, fmt.Errorf("save handoff blob: %w", err)
+	}
+	if limited.N <= 0 {
+		if err := storage.ActionsArtifacts.Delete(storagePath); err != nil {
+			log.Error("Failed to delete handoff blob %q: %v", storagePath, err)
+		}
+		return nil, util.NewInvalidArgumentErrorf("handoff blob %q is larger than %d bytes", name, MaxHandoffBlobSize)
+	}
+
+	blob := &actions_model.ActionHandoffBlob{
+		RunID:       task.Job.RunID,
+		RepoID:      task.RepoID,
+		Name:        name,
+		JobID:       task.JobID,
+		StoragePath: storagePath,
+		Size:        MaxHandoffBlobSize + 1 - limited.N,
+	}
+	replaced, err := actions_model.UpsertHandoffBlob(ctx, blob)
+	if err != nil {
+		if err := storage.ActionsArtifacts.Delete(storagePath); err != nil {
+			log.Error("Failed to delete handoff blob %q: %v", storagePath, err)
+		}
+		return nil, err
+	}
+	if replaced != "" {
+		if err := storage.ActionsArtifacts.Delete(replaced); err != nil {
+			log.Error("Failed to delete replaced handoff blob %q: %v", replaced, err)
+		}
+	}
+	return blob, nil
+}
+
+type handoffBlobReader struct {
+	io.Reader
+	io.Closer
+}
+
+// OpenHandoffBlob opens the decrypted content of the handoff blob of the run
+func OpenHandoffBlob(ctx context.Context, runID int64, name string) (*actions_model.ActionHandoffBlob, io.ReadCloser, error) {
+	blob, err := actions_model.GetHandoffBlob(ctx, runID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := storage.ActionsArtifacts.Open(blob.StoragePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := handoffAEAD(runID)
+	if err != nil {
+		obj.Close()
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(obj, nonce); err != nil {
+		obj.Close()
+		return nil, nil, fmt.Errorf("read nonce of handoff blob: %w", err)
+	}
+	return blob, handoffBlobReader{Reader: newHandoffDecryptReader(aead, nonce, obj), Closer: obj}, nil
+}
+
+// deleteHandoffBlobBatchSize is the batch size of deleting handoff blobs
+const deleteHandoffBlobBatchSize = 100
+
+// DeleteHandoffBlobs deletes the handoff blobs matching the options
+func DeleteHandoffBlobs(ctx context.Context, opts actions_model.FindHandoffBlobOptions) error {
+	opts.ListOptions = db.ListOptions{Page: 1, PageSize: deleteHandoffBlobBatchSize}
+	for {
+		blobs, err := db.Find[actions_model.ActionHandoffBlob](ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, blob := range blobs {
+			if err := storage.ActionsArtifacts.Delete(blob.StoragePath); err != nil {
+				log.Error("Failed to delete handoff blob %d: %v", blob.ID, err)
+			}
+			if _, err := db.DeleteByID[actions_model.ActionHandoffBlob](ctx, blob.ID); err != nil {
+				return err
+			}
+		}
+		if len(blobs) < deleteHandoffBlobBatchSize {
+			return nil
+		}
+	}
+}
+
+// CleanupHandoffBlobs deletes the handoff blobs of the runs which have been done for longer than the artifact retention,
+// they are kept until then so the jobs which are re-run can still download the blobs uploaded by the jobs they need.
+func CleanupHandoffBlobs(ctx context.Context) error {
+	return DeleteHandoffBlobs(ctx, actions_model.FindHandoffBlobOptions{
+		RunDoneBefore: timeutil.TimeStampNow().AddDuration(-time.Duration(setting.Actions.ArtifactRetentionDays) * 24 * time.Hour),
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sealHandoffBlob(t *testing.T, runID int64, nonce, content []byte) []byte {
+	aead, err := handoffAEAD(runID)
+	require.NoError(t, err)
+	sealed, err := io.ReadAll(newHandoffEncryptReader(aead, nonce, bytes.NewReader(content)))
+	require.NoError(t, err)
+	return sealed
+}
+
+func openHandoffBlob(runID int64, nonce, sealed []byte) ([]byte, error) {
+	aead, err := handoffAEAD(runID)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(newHandoffDecryptReader(aead, nonce, bytes.NewReader(sealed)))
+}
+
+func TestHandoffEncryption(t *testing.T) {
+	nonce := make([]byte, 12)
+	_, err := rand.Read(nonce)
+	require.NoError(t, err)
+
+	for _, size := range []int{0, 1, handoffChunkSize - 1, handoffChunkSize, handoffChunkSize + 1, 3 * handoffChunkSize} {
+		content := make([]byte, size)
+		_, err := rand.Read(content)
+		require.NoError(t, err)
+
+		sealed := sealHandoffBlob(t, 1, nonce, content)
+		opened, err := openHandoffBlob(1, nonce, sealed)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, content, opened, "size %d", size)
+
+		// the key of another run can't open the blob
+		_, err = openHandoffBlob(2, nonce, sealed)
+		assert.Error(t, err, "size %d", size)
+	}
+}
+
+func TestHandoffEncryptionTampered(t *testing.T) {
+	nonce := make([]byte, 12)
+	content := bytes.Repeat([]byte("a"), 2*handoffChunkSize+10)
+	sealed := sealHandoffBlob(t, 1, nonce, content)
+
+	tampered := bytes.Clone(sealed)
+	tampered[10] ^= 1
+	_, err := openHandoffBlob(1, nonce, tampered)
+	assert.Error(t, err)
+
+	// drop the last chunk, the blob ends with a full chunk which isn't marked as the last one
+	chunk := handoffChunkSize + 16
+	_, err = openHandoffBlob(1, nonce, sealed[:2*chunk])
+	assert.Error(t, err)
+
+	// the chunks can't be reordered
+	reordered := append(bytes.Clone(sealed[chunk:2*chunk]), sealed[:chunk]...)
+	reordered = append(reordered, sealed[2*chunk:]...)
+	_, err = openHandoffBlob(1, nonce, reordered)
+	assert.Error(t, err)
+}
@@ -13,14 +13,51 @@ import (
 
 func Init() {
 	if !setting.Actions.Enabled {
+		markActionUsagesIndexOutdated(graceful.GetManager().ShutdownContext())
 		return
 	}
 
+	if err := checkStoragesAtStart(); err != nil {
+		log.Fatal("Unable to use the storages of actions: %v", err)
+	}
+
 	jobEmitterQueue = queue.CreateUniqueQueue(graceful.GetManager().ShutdownContext(), "actions_ready_job", jobEmitterQueueHandler)
 	if jobEmitterQueue == nil {
 		log.Fatal("Unable to create actions_ready_job queue")
 	}
 	go graceful.GetManager().RunWithCancel(jobEmitterQueue)
 
+	notifyQueue = queue.CreateSimpleQueue(graceful.GetManager().ShutdownContext(), "actions_notify", notifyQueueHandler)
+	if notifyQueue == nil {
+		log.Fatal("Unable to create actions_notify queue")
+	}
+	go graceful.GetManager().RunWithCancel(notifyQueue)
+
+	outboxQueue = queue.CreateUniqueQueue(graceful.GetManager().ShutdownContext(), "actions_outbox", outboxQueueHandler)
+	if outboxQueue == nil {
+		log.Fatal("Unable to create actions_outbox queue")
+	}
+	go graceful.GetManager().RunWithCancel(outboxQueue)
+	if err := EmitPendingOutboxEvents(graceful.GetManager().ShutdownContext()); err != nil {
+		log.Error("EmitPendingOutboxEvents: %v", err)
+	}
+
+	federationQueue = queue.CreateSimpleQueue(graceful.GetManager().ShutdownContext(), "actions_federation", federationQueueHandler)
+	if federationQueue == nil {
+		log.Fatal("Unable to create actions_federation queue")
+	}
+	go graceful.GetManager().RunWithCancel(federationQueue)
+
+	if err := initEventPublisher(); err != nil {
+		log.Fatal("Unable to init the publisher of actions events: %v", err)
+	}
+
 	notify_service.RegisterNotifier(NewNotifier())
+
+	// elect the leader before the cron tasks start
+	renewLeadership(graceful.GetManager().ShutdownContext())
+	go graceful.GetManager().RunWithShutdownContext(runLeaderElection)
+
+	go graceful.GetManager().RunWithShutdownContext(rebuildActionUsagesIndexIfOutdated)
+	go graceful.GetManager().RunWithShutdownContext(drainRunnerUpdates)
 }
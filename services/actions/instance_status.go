@@ -0,0 +1,109 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+const (
+	instanceStatusCacheKey = "actions:instance_status"
+	// instanceStatusCacheTTL is how long the status is cached in seconds, it could be requested by anyone frequently
+	instanceStatusCacheTTL = 10
+)
+
+// GetInstanceStatus returns the status of Actions of the instance, so the users could tell whether the jobs are slow for everyone.
+// It's cached for a few seconds.
+func GetInstanceStatus(ctx context.Context) (*api.ActionsStatus, error) {
+	status := &api.ActionsStatus{}
+	c := cache.GetCache()
+	if c != nil {
+		if exist, err := c.GetJSON(instanceStatusCacheKey, status); err != nil {
+			log.Warn("GetJSON %s: %v", instanceStatusCacheKey, err.ToError())
+		} else if exist {
+			// the instance state isn't shared by the instances, it's always the one of this instance
+			status.Draining = IsDrainingRunners()
+			return status, nil
+		}
+	}
+
+	status, err := loadInstanceStatus(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		if err := c.PutJSON(instanceStatusCacheKey, status, instanceStatusCacheTTL); err != nil {
+			log.Warn("PutJSON %s: %v", instanceStatusCacheKey, err)
+		}
+	}
+	return status, nil
+}
+
+func loadInstanceStatus(ctx context.Context, now time.Time) (*api.ActionsStatus, error) {
+	status := &api.ActionsStatus{
+		Enabled:     setting.Actions.Enabled,
+		Draining:    IsDrainingRunners(),
+		Maintenance: setting.Actions.MaintenanceMessage,
+		Queue:       []*api.ActionsQueueClass{},
+		Updated:     now,
+	}
+
+	var err error
+	if status.OnlineRunners, err = actions_model.CountOnlineRunners(ctx); err != nil {
+		return nil, fmt.Errorf("CountOnlineRunners: %w", err)
+	}
+	if status.RunningJobs, err = actions_model.CountRunningJobs(ctx); err != nil {
+		return nil, fmt.Errorf("CountRunningJobs: %w", err)
+	}
+	jobs, err := actions_model.GetQueuedRunJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetQueuedRunJobs: %w", err)
+	}
+	status.WaitingJobs = int64(len(jobs))
+	status.Queue = queueClasses(jobs, now)
+	// the alerts are evaluated without the ones firing, so all the exceeded thresholds are returned
+	status.Congested = len(evaluateQueueAlerts(jobs, now, map[string]bool{})) > 0
+
+	return status, nil
+}
+
+// queueClasses groups the waiting jobs by the labels they require, the deepest queue first
+func queueClasses(jobs []*actions_model.ActionRunJob, now time.Time) []*api.ActionsQueueClass {
+	classes := map[string]*api.ActionsQueueClass{}
+	for _, job := range jobs {
+		queued := job.Queued
+		if queued == 0 {
+			queued = job.Created
+		}
+		wait := max(int64(now.Sub(queued.AsTime()).Seconds()), 0)
+		for _, label := range job.RunsOn {
+			class, ok := classes[label]
+			if !ok {
+				class = &api.ActionsQueueClass{Label: label}
+				classes[label] = class
+			}
+			class.Waiting++
+			class.LongestWait = max(class.LongestWait, wait)
+		}
+	}
+
+	ret := make([]*api.ActionsQueueClass, 0, len(classes))
+	for _, class := range classes {
+		ret = append(ret, class)
+	}
+	slices.SortFunc(ret, func(a, b *api.ActionsQueueClass) int {
+		return cmp.Or(cmp.Compare(b.Waiting, a.Waiting), cmp.Compare(a.Label, b.Label))
+	})
+	return ret
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueClasses(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	jobs := []*actions_model.ActionRunJob{
+		{RunsOn: []string{"ubuntu-latest"}, Queued: timeutil.TimeStamp(now.Add(-time.Minute).Unix())},
+		{RunsOn: []string{"ubuntu-latest", "gpu"}, Queued: timeutil.TimeStamp(now.Add(-10 * time.Minute).Unix())},
+		{RunsOn: []string{"macos"}, Created: timeutil.TimeStamp(now.Add(-5 * time.Minute).Unix())},
+	}
+	assert.Equal(t, []*api.ActionsQueueClass{
+		{Label: "ubuntu-latest", Waiting: 2, LongestWait: 600},
+		{Label: "gpu", Waiting: 1, LongestWait: 600},
+		{Label: "macos", Waiting: 1, LongestWait: 300},
+	}, queueClasses(jobs, now))
+}
+
+func TestLoadInstanceStatus(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.Actions.MaintenanceMessage, "The runners are being upgraded")()
+	defer test.MockVariableValue(&setting.ActionsAlerts.QueueDepthThresholds, map[string]int64{setting.QueueAlertsAllLabels: 0})()
+
+	job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+	job.Status = actions_model.StatusWaiting
+	job.RunsOn = []string{"gpu"}
+	_, err := db.GetEngine(db.DefaultContext).ID(job.ID).Cols("status", "runs_on", "is_external").Update(job)
+	require.NoError(t, err)
+
+	status, err := loadInstanceStatus(db.DefaultContext, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "The runners are being upgraded", status.Maintenance)
+	assert.False(t, status.Draining)
+	assert.True(t, status.Congested)
+	assert.Positive(t, status.WaitingJobs)
+	if assert.NotEmpty(t, status.Queue) {
+		assert.Contains(t, status.Queue, &api.ActionsQueueClass{Label: "gpu", Waiting: 1, LongestWait: status.Queue[0].LongestWait})
+	}
+}
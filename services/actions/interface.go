@@ -25,4 +25,8 @@ type API interface {
 	UpdateVariable(*context.APIContext)
 	// GetRegistrationToken get registration token
 	GetRegistrationToken(*context.APIContext)
+	// RotateRunnerToken rotate the token of a runner
+	RotateRunnerToken(*context.APIContext)
+	// RevokeRunner revoke a runner
+	RevokeRunner(*context.APIContext)
 }
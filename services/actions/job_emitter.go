@@ -12,6 +12,7 @@ import (
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/queue"
 
 	"github.com/nektos/act/pkg/jobparser"
@@ -46,6 +47,17 @@ func jobEmitterQueueHandler(items ...*jobUpdate) []*jobUpdate {
 }
 
 func checkJobsOfRun(ctx context.Context, runID int64) error {
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	// the run is checked again once the earlier runs of its concurrency group are done, see releaseConcurrencyGroup
+	if held, err := actions_model.IsRunHeldByConcurrency(ctx, run); err != nil {
+		return err
+	} else if held {
+		return nil
+	}
+
 	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: runID})
 	if err != nil {
 		return err
@@ -72,6 +84,10 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 		return err
 	}
 	CreateCommitStatus(ctx, jobs...)
+	// the run could be done if the rest of its jobs have been skipped
+	if err := EmitOutboxEvents(ctx, runID); err != nil {
+		log.Error("EmitOutboxEvents: %v", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,119 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// jobHintFlakyMinCount is how many times a job must have passed only after being rerun in the sampled runs to be known as flaky
+const jobHintFlakyMinCount = 2
+
+// JobHint is the history of a job in the latest finished runs of the workflow on the same ref
+type JobHint struct {
+	SampledRuns int64 // the number of the sampled runs which have the job finished
+	Failed      int64
+	Flaky       int64 // the number of the sampled runs in which the job failed at first but passed after being rerun
+	// the average duration of the job in the sampled runs in which it succeeded, 0 if it hasn't succeeded in them
+	AverageDuration time.Duration
+}
+
+// FailureRate returns the ratio of the sampled runs in which the job failed
+func (h *JobHint) FailureRate() float64 {
+	if h.SampledRuns == 0 {
+		return 0
+	}
+	return float64(h.Failed) / float64(h.SampledRuns)
+}
+
+// IsFlaky returns whether the job is known to be flaky
+func (h *JobHint) IsFlaky() bool {
+	return h.Flaky >= jobHintFlakyMinCount
+}
+
+// GetRunJobHints returns the hints of the jobs of the run by their ids, drawn from the latest finished runs of the workflow on the same ref,
+// see [actions] JOB_HINTS_SAMPLE_RUNS. The jobs are matched by their job ids and names, since the jobs of a matrix share the job id.
+// It returns nil if the hints are disabled, and the jobs which never finished in the sampled runs have no hints.
+func GetRunJobHints(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) (map[int64]*JobHint, error) {
+	if setting.Actions.JobHintsSampleRuns <= 0 || run.IsExternal() {
+		return nil, nil
+	}
+
+	// one more run is sampled in case the run itself is finished
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		ListOptions: db.ListOptions{PageSize: int(setting.Actions.JobHintsSampleRuns) + 1},
+		RepoID:      run.RepoID,
+		WorkflowID:  run.WorkflowID,
+		Ref:         run.Ref,
+		Status:      []actions_model.Status{actions_model.StatusSuccess, actions_model.StatusFailure},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FindRuns: %w", err)
+	}
+	runIDs := make([]int64, 0, len(runs))
+	for _, r := range runs {
+		if r.ID != run.ID && int64(len(runIDs)) < setting.Actions.JobHintsSampleRuns {
+			runIDs = append(runIDs, r.ID)
+		}
+	}
+	if len(runIDs) == 0 {
+		return map[int64]*JobHint{}, nil
+	}
+
+	sampledJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		RunIDs:   runIDs,
+		Statuses: []actions_model.Status{actions_model.StatusSuccess, actions_model.StatusFailure},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FindRunJobs: %w", err)
+	}
+	sampledJobIDs := make([]int64, 0, len(sampledJobs))
+	for _, job := range sampledJobs {
+		sampledJobIDs = append(sampledJobIDs, job.ID)
+	}
+	retried, err := actions_model.GetJobIDsWithFailedTasks(ctx, sampledJobIDs)
+	if err != nil {
+		return nil, fmt.Errorf("GetJobIDsWithFailedTasks: %w", err)
+	}
+
+	type key struct{ jobID, name string }
+	hints := make(map[key]*JobHint, len(jobs))
+	durations := make(map[key]time.Duration, len(jobs))
+	for _, job := range sampledJobs {
+		k := key{job.JobID, job.Name}
+		h, ok := hints[k]
+		if !ok {
+			h = &JobHint{}
+			hints[k] = h
+		}
+		h.SampledRuns++
+		if job.Status == actions_model.StatusFailure {
+			h.Failed++
+			continue
+		}
+		if retried.Contains(job.ID) {
+			h.Flaky++
+		}
+		durations[k] += job.Duration()
+	}
+	for k, h := range hints {
+		if succeeded := h.SampledRuns - h.Failed; succeeded > 0 {
+			h.AverageDuration = durations[k] / time.Duration(succeeded)
+		}
+	}
+
+	ret := make(map[int64]*JobHint, len(jobs))
+	for _, job := range jobs {
+		if h, ok := hints[key{job.JobID, job.Name}]; ok {
+			ret[job.ID] = h
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunJobHints(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 792})
+	jobs := []*actions_model.ActionRunJob{unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 193})}
+
+	hints, err := GetRunJobHints(ctx, run, jobs)
+	require.NoError(t, err)
+	assert.Nil(t, hints)
+
+	defer test.MockVariableValue(&setting.Actions.JobHintsSampleRuns, 20)()
+
+	// the run itself isn't sampled, the job succeeded in run 791 in 98 seconds
+	hints, err = GetRunJobHints(ctx, run, jobs)
+	require.NoError(t, err)
+	require.Contains(t, hints, int64(193))
+	h := hints[193]
+	assert.EqualValues(t, 1, h.SampledRuns)
+	assert.Zero(t, h.FailureRate())
+	assert.Equal(t, 98*time.Second, h.AverageDuration)
+	assert.False(t, h.IsFlaky())
+
+	// the job of run 791 failed at first but passed after being rerun
+	_, err = db.GetEngine(ctx).ID(47).Cols("status").Update(&actions_model.ActionTask{Status: actions_model.StatusFailure})
+	require.NoError(t, err)
+	hints, err = GetRunJobHints(ctx, run, jobs)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, hints[193].Flaky)
+
+	// run 791 failed
+	_, err = db.GetEngine(ctx).ID(791).Cols("status").Update(&actions_model.ActionRun{Status: actions_model.StatusFailure})
+	require.NoError(t, err)
+	job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+	job.Status = actions_model.StatusFailure
+	_, err = db.GetEngine(ctx).ID(job.ID).Cols("status").Update(job)
+	require.NoError(t, err)
+	hints, err = GetRunJobHints(ctx, run, jobs)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, hints[193].Failed)
+	assert.InDelta(t, 1.0, hints[193].FailureRate(), 0.001)
+	assert.Zero(t, hints[193].AverageDuration)
+}
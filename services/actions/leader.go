@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	// leaderLeaseName is the name of the lease held by the instance running the Actions cron tasks
+	leaderLeaseName = "actions_leader"
+	// leaderLeaseTTL is how long the lease of a leader which has gone away blocks the other instances
+	leaderLeaseTTL = time.Minute
+	// leaderLeaseRenewInterval must be much shorter than leaderLeaseTTL, so the leader renews the lease before it expires
+	leaderLeaseRenewInterval = 20 * time.Second
+)
+
+var (
+	// instanceID identifies the instance among the instances sharing the database
+	instanceID = newInstanceID()
+	isLeader   atomic.Bool
+)
+
+func newInstanceID() string {
+	hostname, _ := os.Hostname()
+	suffix, _ := util.CryptoRandomString(8)
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), suffix)
+}
+
+// IsLeader returns whether the instance is the leader of the instances sharing the database,
+// the Actions cron tasks only run on the leader, so they don't race with each other or dispatch the same work twice.
+func IsLeader() bool {
+	return isLeader.Load()
+}
+
+// renewLeadership acquires or renews the lease of the leader, the leadership is given up if the lease can't be renewed
+func renewLeadership(ctx context.Context) {
+	acquired, err := actions_model.AcquireLease(ctx, leaderLeaseName, instanceID, leaderLeaseTTL)
+	if err != nil {
+		log.Error("AcquireLease: %v", err)
+		acquired = false
+	}
+	if was := isLeader.Swap(acquired); was != acquired {
+		if acquired {
+			log.Info("Actions: instance %s becomes the leader", instanceID)
+		} else {
+			log.Info("Actions: instance %s is no longer the leader", instanceID)
+		}
+	}
+}
+
+// runLeaderElection keeps renewing the lease of the leader until shutdown, then releases it for another instance to take over
+func runLeaderElection(ctx context.Context) {
+	ticker := time.NewTicker(leaderLeaseRenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if isLeader.Swap(false) {
+				if err := actions_model.ReleaseLease(graceful.GetManager().HammerContext(), leaderLeaseName, instanceID); err != nil {
+					log.Error("ReleaseLease: %v", err)
+				}
+			}
+			return
+		case <-ticker.C:
+			renewLeadership(ctx)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	git_model "code.gitea.io/gitea/models/git"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
+)
+
+// LicenseCheckStatusContext is the context of the commit statuses of the license scans,
+// so the protected branches could require it as a status check
+const LicenseCheckStatusContext = "actions / license-check"
+
+// SubmitLicenseScan checks the packages scanned by the running task against the license policy of the owner of the repository.
+// The result is reported as a commit status of the commit of the run, and the task fails when it's done if the scan fails.
+func SubmitLicenseScan(ctx context.Context, task *actions_model.ActionTask, packages []*actions_model.LicensedPackage) (*actions_model.ActionLicenseScan, error) {
+	if task.Status.IsDone() {
+		return nil, util.NewInvalidArgumentErrorf("task %d is done", task.ID)
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		return nil, err
+	}
+	if err := task.Job.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	run := task.Job.Run
+
+	policy, err := actions_model.GetLicensePolicy(ctx, run.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	violations := actions_model.CheckLicenses(policy, packages)
+	scan := &actions_model.ActionLicenseScan{
+		RepoID:     run.RepoID,
+		RunID:      run.ID,
+		TaskID:     task.ID,
+		Passed:     len(violations) == 0,
+		Packages:   len(packages),
+		Violations: violations,
+	}
+	if err := actions_model.InsertLicenseScan(ctx, scan); err != nil {
+		return nil, err
+	}
+
+	if err := createLicenseCheckStatus(ctx, run); err != nil {
+		log.Error("Failed to create the license check status of run %d: %v", run.ID, err)
+	}
+	return scan, nil
+}
+
+// createLicenseCheckStatus reports the license scans of the run as a commit status, it fails if any scan of the run fails
+func createLicenseCheckStatus(ctx context.Context, run *actions_model.ActionRun) error {
+	if run.CommitSHA == "" {
+		return nil
+	}
+	scans, err := actions_model.FindLicenseScansByRunID(ctx, run.ID)
+	if err != nil {
+		return err
+	}
+	packages, violations := 0, 0
+	for _, scan := range scans {
+		packages += scan.Packages
+		violations += len(scan.Violations)
+	}
+	state := api.CommitStatusSuccess
+	description := fmt.Sprintf("%d packages comply with the license policy", packages)
+	if violations > 0 {
+		state = api.CommitStatusFailure
+		description = fmt.Sprintf("%d of %d packages violate the license policy", violations, packages)
+	}
+
+	creator := user_model.NewActionsUser()
+	return commitstatus_service.CreateCommitStatus(ctx, run.Repo, creator, run.CommitSHA, &git_model.CommitStatus{
+		SHA:         run.CommitSHA,
+		TargetURL:   run.Link(),
+		Description: description,
+		Context:     LicenseCheckStatusContext,
+		CreatorID:   creator.ID,
+		State:       state,
+	})
+}
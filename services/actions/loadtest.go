@@ -0,0 +1,288 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	gouuid "github.com/google/uuid"
+	"github.com/nektos/act/pkg/jobparser"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxLoadTestJobs is the max number of the synthetic jobs of a load test
+	maxLoadTestJobs = 10000
+	// maxLoadTestRunners is the max number of the fake runners of a load test
+	maxLoadTestRunners = 100
+	// maxLoadTestLogLines is the max number of the log lines uploaded by each synthetic task
+	maxLoadTestLogLines = 10000
+	// loadTestWorkflowID is the workflow of the synthetic runs, it doesn't exist in the repository
+	loadTestWorkflowID = "gitea-load-test.yml"
+	// maxLoadTestEmptyPicks is how many times in a row a fake runner could find no job before it gives up,
+	// the picks fail when the runners compete for the same jobs
+	maxLoadTestEmptyPicks = 10
+)
+
+// LoadTestOptions are the synthetic runs of a load test and the fake runners which run them
+type LoadTestOptions struct {
+	Runs       int  // the number of the synthetic runs
+	JobsPerRun int  // the number of the jobs of each run, they don't need each other
+	Runners    int  // the number of the fake runners picking the jobs concurrently
+	LogLines   int  // the number of the log lines uploaded by each task, no logs are uploaded if it's 0
+	Keep       bool // keep the synthetic runs and the fake runners to inspect them, they're deleted at the end by default
+}
+
+// LoadTestPhase is how a phase of a load test performed
+type LoadTestPhase struct {
+	Name     string
+	Count    int           // the number of the operations which succeeded
+	Errors   int           // the number of the operations which failed
+	Duration time.Duration // the wall time of the phase
+	P50      time.Duration // the latencies of the operations which succeeded
+	P95      time.Duration
+	Max      time.Duration
+}
+
+// Throughput is how many operations succeeded per second
+func (p *LoadTestPhase) Throughput() float64 {
+	if p.Duration <= 0 {
+		return 0
+	}
+	return float64(p.Count) / p.Duration.Seconds()
+}
+
+// LoadTestResult is how the phases of a load test performed
+type LoadTestResult struct {
+	Label  string // the runs-on label of the synthetic jobs, only the fake runners have it
+	Phases []*LoadTestPhase
+	// the first error of each phase, the other errors are only logged
+	Errors []string
+}
+
+// loadTestRecorder records the latencies of the operations of a phase, it could be used concurrently
+type loadTestRecorder struct {
+	mu        sync.Mutex
+	phase     *LoadTestPhase
+	start     time.Time
+	latencies []time.Duration
+	err       error
+}
+
+func newLoadTestRecorder(name string) *loadTestRecorder {
+	return &loadTestRecorder{phase: &LoadTestPhase{Name: name}, start: time.Now()}
+}
+
+func (r *loadTestRecorder) record(start time.Time, err error) {
+	latency := time.Since(start)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.phase.Errors++
+		if r.err == nil {
+			r.err = err
+		}
+		log.Warn("Load test %s: %v", r.phase.Name, err)
+		return
+	}
+	r.phase.Count++
+	r.latencies = append(r.latencies, latency)
+}
+
+func (r *loadTestRecorder) finish(res *LoadTestResult) {
+	r.phase.Duration = time.Since(r.start)
+	slices.Sort(r.latencies)
+	if n := len(r.latencies); n > 0 {
+		r.phase.P50 = r.latencies[(n-1)*50/100]
+		r.phase.P95 = r.latencies[(n-1)*95/100]
+		r.phase.Max = r.latencies[n-1]
+	}
+	res.Phases = append(res.Phases, r.phase)
+	if r.err != nil {
+		res.Errors = append(res.Errors, fmt.Sprintf("%s: %v", r.phase.Name, r.err))
+	}
+}
+
+// RunLoadTest benchmarks the scheduler, the database and the log ingestion of the instance with synthetic runs in the repository.
+// The jobs of the runs have a label of their own, so only the fake runners of the test, which are registered to the repository, could pick them.
+// The fake runners pick the jobs like the real ones, upload the logs of the tasks and report them successful without running anything.
+// No events are triggered, no commit statuses are created and no notifications are sent for the synthetic runs.
+func RunLoadTest(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, opts *LoadTestOptions) (*LoadTestResult, error) {
+	if opts.Runs <= 0 || opts.JobsPerRun <= 0 || opts.Runners <= 0 || opts.LogLines < 0 {
+		return nil, util.NewInvalidArgumentErrorf("the runs, the jobs of each run and the runners must be positive")
+	}
+	if opts.Runs*opts.JobsPerRun > maxLoadTestJobs {
+		return nil, util.NewInvalidArgumentErrorf("a load test could have at most %d jobs", maxLoadTestJobs)
+	}
+	if opts.Runners > maxLoadTestRunners {
+		return nil, util.NewInvalidArgumentErrorf("a load test could have at most %d runners", maxLoadTestRunners)
+	}
+	if opts.LogLines > maxLoadTestLogLines {
+		return nil, util.NewInvalidArgumentErrorf("each task of a load test could upload at most %d log lines", maxLoadTestLogLines)
+	}
+	if repo.IsArchived {
+		return nil, util.NewInvalidArgumentErrorf("repository %s is archived", repo.FullName())
+	}
+	if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return nil, util.NewInvalidArgumentErrorf("actions of repository %s are disabled", repo.FullName())
+	}
+
+	suffix, err := util.CryptoRandomString(8)
+	if err != nil {
+		return nil, err
+	}
+	res := &LoadTestResult{Label: "gitea-load-test-" + strings.ToLower(suffix)}
+	workflows, err := jobparser.Parse(loadTestWorkflow(res.Label, opts.JobsPerRun))
+	if err != nil {
+		return nil, fmt.Errorf("parse the synthetic workflow: %w", err)
+	}
+
+	// the runs and the runners are removed at the end even if the test is cancelled halfway
+	var runs []*actions_model.ActionRun
+	var runners []*actions_model.ActionRunner
+	defer func() {
+		if opts.Keep {
+			return
+		}
+		cleanup := newLoadTestRecorder("cleanup")
+		ctx := context.WithoutCancel(ctx)
+		for _, run := range runs {
+			start := time.Now()
+			cleanup.record(start, deleteRun(ctx, run))
+		}
+		for _, runner := range runners {
+			start := time.Now()
+			cleanup.record(start, actions_model.DeleteRunner(ctx, runner.ID))
+		}
+		cleanup.finish(res)
+	}()
+
+	create := newLoadTestRecorder("create runs")
+	for i := 0; i < opts.Runs && ctx.Err() == nil; i++ {
+		run := &actions_model.ActionRun{
+			Title:         fmt.Sprintf("Load test %s #%d", res.Label, i+1),
+			RepoID:        repo.ID,
+			Repo:          repo,
+			OwnerID:       repo.OwnerID,
+			WorkflowID:    loadTestWorkflowID,
+			TriggerUserID: doer.ID,
+			Ref:           git.RefNameFromBranch(repo.DefaultBranch).String(),
+			CommitSHA:     git.Sha1ObjectFormat.EmptyObjectID().String(),
+			Event:         webhook_module.HookEventWorkflowDispatch,
+			TriggerEvent:  string(webhook_module.HookEventWorkflowDispatch),
+			EventPayload:  "{}",
+			Status:        actions_model.StatusWaiting,
+		}
+		start := time.Now()
+		err := actions_model.InsertRun(ctx, run, workflows)
+		create.record(start, err)
+		if err == nil {
+			runs = append(runs, run)
+		}
+	}
+	create.finish(res)
+
+	for i := 0; i < opts.Runners; i++ {
+		runner := &actions_model.ActionRunner{
+			UUID:        gouuid.New().String(),
+			Name:        fmt.Sprintf("%s-%d", res.Label, i+1),
+			RepoID:      repo.ID,
+			Description: "the fake runner of a load test, it doesn't run anything",
+			AgentLabels: []string{res.Label},
+		}
+		if err := runner.GenerateToken(); err != nil {
+			return res, err
+		}
+		if err := actions_model.CreateRunner(ctx, runner); err != nil {
+			return res, fmt.Errorf("CreateRunner: %w", err)
+		}
+		runners = append(runners, runner)
+	}
+
+	pick := newLoadTestRecorder("pick tasks")
+	upload := newLoadTestRecorder("upload logs")
+	report := newLoadTestRecorder("report results")
+	remaining := atomic.Int64{}
+	remaining.Store(int64(len(runs) * opts.JobsPerRun))
+	var wg sync.WaitGroup
+	for _, runner := range runners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for emptyPicks := 0; remaining.Load() > 0 && emptyPicks < maxLoadTestEmptyPicks && ctx.Err() == nil; {
+				start := time.Now()
+				task, ok, err := actions_model.CreateTaskForRunner(ctx, runner)
+				if err != nil || !ok {
+					if err != nil {
+						pick.record(start, err)
+					}
+					emptyPicks++
+					continue
+				}
+				pick.record(start, nil)
+				emptyPicks = 0
+				remaining.Add(-1)
+				runLoadTestTask(ctx, task, opts.LogLines, upload, report)
+			}
+		}()
+	}
+	wg.Wait()
+	pick.finish(res)
+	if opts.LogLines > 0 {
+		upload.finish(res)
+	}
+	report.finish(res)
+
+	return res, ctx.Err()
+}
+
+// runLoadTestTask uploads the logs of the task and reports it successful like a runner
+func runLoadTestTask(ctx context.Context, task *actions_model.ActionTask, logLines int, upload, report *loadTestRecorder) {
+	if logLines > 0 {
+		rows := make([]*runnerv1.LogRow, 0, logLines)
+		for i := 0; i < logLines; i++ {
+			rows = append(rows, &runnerv1.LogRow{
+				Time:    timestamppb.Now(),
+				Content: fmt.Sprintf("load test task %d, line %d", task.ID, i+1),
+			})
+		}
+		start := time.Now()
+		_, err := AppendTaskLogs(ctx, task.ID, 0, rows, true)
+		upload.record(start, err)
+	}
+
+	start := time.Now()
+	_, err := actions_model.UpdateTaskByState(ctx, &runnerv1.TaskState{
+		Id:        task.ID,
+		Result:    runnerv1.Result_RESULT_SUCCESS,
+		StoppedAt: timestamppb.Now(),
+	})
+	report.record(start, err)
+}
+
+// loadTestWorkflow generates the workflow of the synthetic runs, its jobs run on the label
+func loadTestWorkflow(label string, jobs int) []byte {
+	var sb strings.Builder
+	sb.WriteString("name: load test\non: workflow_dispatch\njobs:\n")
+	for i := 1; i <= jobs; i++ {
+		fmt.Fprintf(&sb, "  job-%d:\n    runs-on: %s\n    steps:\n      - run: echo load test\n", i, label)
+	}
+	return []byte(sb.String())
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLoadTest(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+
+	_, err := RunLoadTest(ctx, repo, doer, &LoadTestOptions{Runs: 0, JobsPerRun: 1, Runners: 1})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = RunLoadTest(ctx, repo, doer, &LoadTestOptions{Runs: maxLoadTestJobs, JobsPerRun: 2, Runners: 1})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	runs := unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID})
+	runners := unittest.GetCount(t, &actions_model.ActionRunner{})
+
+	res, err := RunLoadTest(ctx, repo, doer, &LoadTestOptions{Runs: 2, JobsPerRun: 3, Runners: 1, LogLines: 5})
+	require.NoError(t, err)
+	assert.Empty(t, res.Errors)
+	counts := map[string]int{}
+	for _, phase := range res.Phases {
+		counts[phase.Name] = phase.Count
+		assert.Zero(t, phase.Errors, phase.Name)
+	}
+	assert.Equal(t, map[string]int{
+		"create runs":    2,
+		"pick tasks":     6,
+		"upload logs":    6,
+		"report results": 6,
+		"cleanup":        3, // 2 runs and 1 runner
+	}, counts)
+
+	// nothing of the test is left
+	assert.Equal(t, runs, unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID}))
+	assert.Equal(t, runners, unittest.GetCount(t, &actions_model.ActionRunner{}))
+
+	// the runs could be kept to inspect them
+	res, err = RunLoadTest(ctx, repo, doer, &LoadTestOptions{Runs: 1, JobsPerRun: 2, Runners: 2, Keep: true})
+	require.NoError(t, err)
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: loadTestWorkflowID})
+	assert.Equal(t, actions_model.StatusSuccess, run.Status)
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{Name: res.Label + "-2", RepoID: repo.ID})
+	assert.Equal(t, runs+1, unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID}))
+
+	// the runners have no fixtures and they're soft deleted, so they would be left for the other tests
+	_, err = db.GetEngine(ctx).Exec("DELETE FROM action_runner WHERE repo_id = ?", repo.ID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// taskStepStarts returns the indexes of the first lines of the steps of the task in its logs
+func taskStepStarts(ctx context.Context, task *actions_model.ActionTask) (container.Set[int64], error) {
+	if err := task.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	stepStarts := make(container.Set[int64])
+	for _, step := range actions_module.FullSteps(task) {
+		stepStarts.Add(step.LogIndex)
+	}
+	return stepStarts, nil
+}
+
+// saveStructuredTaskLogs saves the structured logs of the task alongside its logs transferred to the storage
+func saveStructuredTaskLogs(ctx context.Context, task *actions_model.ActionTask) error {
+	stepStarts, err := taskStepStarts(ctx, task)
+	if err != nil {
+		return err
+	}
+	return actions_module.SaveStructuredLogs(task.LogFilename, stepStarts)
+}
+
+// ReadStructuredTaskLogs returns at most limit lines of the structured logs of the task from the line of the index from.
+// The saved structured logs are read if the logs have been transferred to the storage, or the logs are parsed.
+func ReadStructuredTaskLogs(ctx context.Context, task *actions_model.ActionTask, from, limit int64) ([]*actions_module.StructuredLogLine, error) {
+	if task.LogExpired {
+		return nil, util.NewNotExistErrorf("logs have been cleaned up")
+	}
+
+	lines := make([]*actions_module.StructuredLogLine, 0, min(limit, max(task.LogLength-from, 0)))
+	collect := func(line *actions_module.StructuredLogLine) bool {
+		if line.Index >= from {
+			lines = append(lines, line)
+		}
+		return int64(len(lines)) < limit
+	}
+	if limit <= 0 || from >= task.LogLength {
+		return lines, nil
+	}
+
+	if task.LogInStorage {
+		if found, err := actions_module.ReadStructuredLogs(task.LogFilename, collect); err != nil || found {
+			return lines, err
+		}
+	}
+
+	stepStarts, err := taskStepStarts(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := actions_module.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return lines, actions_module.ParseStructuredLogs(reader, stepStarts, collect)
+}
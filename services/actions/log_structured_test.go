@@ -0,0 +1,72 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/storage"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestReadStructuredTaskLogs(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	task.LogFilename = "structured-test/47.log"
+	task.LogInStorage = false
+	task.LogLength, task.LogSize, task.LogIndexes = 0, 0, nil
+	require.NoError(t, actions_model.UpdateTask(db.DefaultContext, task, "log_filename", "log_in_storage", "log_length", "log_size", "log_indexes"))
+
+	rows := func(contents ...string) []*runnerv1.LogRow {
+		ret := make([]*runnerv1.LogRow, 0, len(contents))
+		for _, c := range contents {
+			ret = append(ret, &runnerv1.LogRow{Time: timestamppb.New(time.Now()), Content: c})
+		}
+		return ret
+	}
+	texts := func(lines []*actions_module.StructuredLogLine) []string {
+		ret := make([]string, 0, len(lines))
+		for _, line := range lines {
+			var text string
+			for _, s := range line.Segments {
+				text += s.Text
+			}
+			ret = append(ret, line.Type+":"+text)
+		}
+		return ret
+	}
+
+	_, err := AppendTaskLogs(db.DefaultContext, task.ID, 0, rows("::group::Build", "\x1b[32mok\x1b[0m"), false)
+	require.NoError(t, err)
+	task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	lines, err := ReadStructuredTaskLogs(db.DefaultContext, task, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line:ok"}, texts(lines))
+
+	_, err = AppendTaskLogs(db.DefaultContext, task.ID, 2, rows("::endgroup::", "done"), true)
+	require.NoError(t, err)
+	task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	_, err = storage.Actions.Stat(task.LogFilename + actions_module.StructuredLogsSuffix)
+	require.NoError(t, err)
+	lines, err = ReadStructuredTaskLogs(db.DefaultContext, task, 0, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"group:Build", "line:ok", "endgroup:"}, texts(lines))
+	lines, err = ReadStructuredTaskLogs(db.DefaultContext, task, 4, 3)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+
+	require.NoError(t, actions_module.RemoveLogs(db.DefaultContext, true, task.LogFilename))
+	_, err = storage.Actions.Stat(task.LogFilename + actions_module.StructuredLogsSuffix)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// MinutesUsage is how many minutes the tasks of a repository ran in a period
+type MinutesUsage struct {
+	RepoID  int64
+	Minutes float64
+	// the minutes multiplied by the weights of the runners the tasks ran on, for the chargeback of the expensive runners
+	WeightedMinutes float64
+}
+
+// RunnerWeight returns the weight of the minutes ran on a runner with the labels,
+// it's the largest weight of the labels in [actions] RUNNER_LABEL_WEIGHTS, or 1 if none of the labels is weighted.
+func RunnerWeight(labels []string) float64 {
+	weight, weighted := 1.0, false
+	for _, label := range labels {
+		w, ok := setting.Actions.RunnerLabelWeights[label]
+		if ok && (!weighted || w > weight) {
+			weight, weighted = w, true
+		}
+	}
+	return weight
+}
+
+// GetMinutesUsages returns the minutes used by the repositories in the period, in the order of the repository ids.
+// The tasks keep being counted with the labels of their runners when the runners have been deleted,
+// but the changes of the labels of the runners apply to the tasks which ran before.
+func GetMinutesUsages(ctx context.Context, opts actions_model.FindTaskDurationsOptions) ([]*MinutesUsage, error) {
+	durations, err := actions_model.SumTaskDurations(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	runnerIDs := make(container.Set[int64], len(durations))
+	for _, duration := range durations {
+		runnerIDs.Add(duration.RunnerID)
+	}
+	runners, err := actions_model.GetRunnersByIDs(ctx, runnerIDs.Values())
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []*MinutesUsage
+	for _, duration := range durations {
+		if len(usages) == 0 || usages[len(usages)-1].RepoID != duration.RepoID {
+			usages = append(usages, &MinutesUsage{RepoID: duration.RepoID})
+		}
+		usage := usages[len(usages)-1]
+
+		weight := 1.0
+		if runner, ok := runners[duration.RunnerID]; ok {
+			weight = RunnerWeight(runner.AgentLabels)
+		}
+		minutes := float64(duration.Seconds) / 60
+		usage.Minutes += minutes
+		usage.WeightedMinutes += minutes * weight
+	}
+	return usages, nil
+}
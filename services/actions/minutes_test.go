@@ -0,0 +1,63 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerWeight(t *testing.T) {
+	defer test.MockVariableValue(&setting.Actions.RunnerLabelWeights, map[string]float64{"gpu": 10, "macos": 5, "spot": 0.5})()
+
+	assert.InDelta(t, 1, RunnerWeight([]string{"ubuntu-latest"}), 0)
+	assert.InDelta(t, 10, RunnerWeight([]string{"macos", "gpu"}), 0)
+	assert.InDelta(t, 0.5, RunnerWeight([]string{"ubuntu-latest", "spot"}), 0)
+}
+
+func TestGetMinutesUsages(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.Actions.RunnerLabelWeights, map[string]float64{"gpu": 10})()
+	ctx := db.DefaultContext
+
+	require.NoError(t, actions_model.CreateRunner(ctx, &actions_model.ActionRunner{
+		ID: 1001, UUID: "0f5b1d1e-4a0d-4c8e-9a53-1a1f4c7e1001", Name: "gpu", TokenHash: "minutes-test-gpu", AgentLabels: []string{"gpu"},
+	}))
+	require.NoError(t, actions_model.CreateRunner(ctx, &actions_model.ActionRunner{
+		ID: 1002, UUID: "0f5b1d1e-4a0d-4c8e-9a53-1a1f4c7e1002", Name: "linux", TokenHash: "minutes-test-linux", AgentLabels: []string{"ubuntu-latest"},
+	}))
+	require.NoError(t, db.Insert(ctx, []*actions_model.ActionTask{
+		{ID: 1001, RepoID: 1, OwnerID: 1001, RunnerID: 1001, Started: 1000, Stopped: 1600, TokenHash: "minutes-test-1"},
+		{ID: 1002, RepoID: 1, OwnerID: 1001, RunnerID: 1002, Started: 1000, Stopped: 1300, TokenHash: "minutes-test-2"},
+		{ID: 1003, RepoID: 2, OwnerID: 1001, RunnerID: 1002, Started: 2000, Stopped: 2120, TokenHash: "minutes-test-3"},
+		// stopped after the period
+		{ID: 1004, RepoID: 2, OwnerID: 1001, RunnerID: 1001, Started: 2000, Stopped: 5000, TokenHash: "minutes-test-4"},
+		// still running
+		{ID: 1005, RepoID: 2, OwnerID: 1001, RunnerID: 1001, Started: 2000, TokenHash: "minutes-test-5"},
+	}))
+	// the tasks of the deleted runners are still weighted
+	require.NoError(t, actions_model.DeleteRunner(ctx, 1001))
+
+	usages, err := GetMinutesUsages(ctx, actions_model.FindTaskDurationsOptions{
+		OwnerID:       1001,
+		StoppedSince:  1000,
+		StoppedBefore: 3000,
+	})
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+	assert.EqualValues(t, 1, usages[0].RepoID)
+	assert.InDelta(t, 15, usages[0].Minutes, 0.001)
+	assert.InDelta(t, 105, usages[0].WeightedMinutes, 0.001)
+	assert.EqualValues(t, 2, usages[1].RepoID)
+	assert.InDelta(t, 2, usages[1].Minutes, 0.001)
+	assert.InDelta(t, 2, usages[1].WeightedMinutes, 0.001)
+}
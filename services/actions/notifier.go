@@ -11,6 +11,7 @@ import (
 	perm_model "code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
@@ -118,6 +119,8 @@ func (n *actionsNotifier) IssueChangeStatus(ctx context.Context, doer *user_mode
 		} else {
 			apiPullRequest.Action = api.HookIssueReOpened
 		}
+		// before the runs of the event are created, so they aren't cancelled
+		handleClosedPullRequestRuns(ctx, issue.Repo, issue.PullRequest, isClosed)
 		newNotifyInputFromIssue(issue, webhook_module.HookEventPullRequest).
 			WithDoer(doer).
 			WithPayload(apiPullRequest).
@@ -244,6 +247,7 @@ func (n *actionsNotifier) CreateIssueComment(ctx context.Context, doer *user_mod
 
 	if issue.IsPull {
 		notifyIssueCommentChange(ctx, doer, comment, "", webhook_module.HookEventPullRequestComment, api.HookIssueCommentCreated)
+		notifyChatOpsCommands(ctx, doer, comment)
 		return
 	}
 	notifyIssueCommentChange(ctx, doer, comment, "", webhook_module.HookEventIssueComment, api.HookIssueCommentCreated)
@@ -396,6 +400,20 @@ func (n *actionsNotifier) ForkRepository(ctx context.Context, doer *user_model.U
 	}
 }
 
+// ChangeDefaultBranch reindexes the actions used by the workflows of the new default branch
+func (n *actionsNotifier) ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
+	if err := repo.LoadUnits(ctx); err != nil {
+		log.Error("LoadUnits: %v", err)
+		return
+	}
+	if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return
+	}
+	if err := IndexActionUsages(ctx, repo); err != nil {
+		log.Error("IndexActionUsages: %v", err)
+	}
+}
+
 func (n *actionsNotifier) PullRequestReview(ctx context.Context, pr *issues_model.PullRequest, review *issues_model.Review, _ *issues_model.Comment, _ []*user_model.User) {
 	ctx = withMethod(ctx, "PullRequestReview")
 
@@ -532,6 +550,7 @@ func (n *actionsNotifier) PushCommits(ctx context.Context, pusher *user_model.Us
 
 	newNotifyInput(repo, pusher, webhook_module.HookEventPush).
 		WithRef(opts.RefFullName.String()).
+		WithCommitID(opts.NewCommitID).
 		WithPayload(&api.PushPayload{
 			Ref:        opts.RefFullName.String(),
 			Before:     opts.OldCommitID,
@@ -554,6 +573,7 @@ func (n *actionsNotifier) CreateRef(ctx context.Context, pusher *user_model.User
 
 	newNotifyInput(repo, pusher, webhook_module.HookEventCreate).
 		WithRef(refFullName.String()).
+		WithCommitID(refID).
 		WithPayload(&api.CreatePayload{
 			Ref:     refFullName.String(),
 			Sha:     refID,
@@ -593,6 +613,7 @@ func (n *actionsNotifier) SyncPushCommits(ctx context.Context, pusher *user_mode
 
 	newNotifyInput(repo, pusher, webhook_module.HookEventPush).
 		WithRef(opts.RefFullName.String()).
+		WithCommitID(opts.NewCommitID).
 		WithPayload(&api.PushPayload{
 			Ref:          opts.RefFullName.String(),
 			Before:       opts.OldCommitID,
@@ -25,6 +25,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/services/convert"
 
@@ -66,8 +67,11 @@ type notifyInput struct {
 
 	// optional
 	Ref         string
+	CommitID    string // the commit of the ref when the event happened, the ref could have been updated when handling the event
 	Payload     api.Payloader
 	PullRequest *issues_model.PullRequest
+	IsBackfill  bool // the event is synthesized for an existing commit, see BackfillRuns
+	IsRedeliver bool // the event is delivered again on purpose, see RedeliverRunEvent
 }
 
 func newNotifyInput(repo *repo_model.Repository, doer *user_model.User, event webhook_module.HookEventType) *notifyInput {
@@ -93,6 +97,11 @@ func (input *notifyInput) WithRef(ref string) *notifyInput {
 	return input
 }
 
+func (input *notifyInput) WithCommitID(commitID string) *notifyInput {
+	input.CommitID = commitID
+	return input
+}
+
 func (input *notifyInput) WithPayload(payload api.Payloader) *notifyInput {
 	input.Payload = payload
 	return input
@@ -106,10 +115,26 @@ func (input *notifyInput) WithPullRequest(pr *issues_model.PullRequest) *notifyI
 	return input
 }
 
+func (input *notifyInput) WithBackfill() *notifyInput {
+	input.IsBackfill = true
+	return input
+}
+
+func (input *notifyInput) WithRedeliver() *notifyInput {
+	input.IsRedeliver = true
+	return input
+}
+
 func (input *notifyInput) Notify(ctx context.Context) {
 	log.Trace("execute %v for event %v whose doer is %v", getMethod(ctx), input.Event, input.Doer.Name)
 
-	if err := notify(ctx, input); err != nil {
+	if input.Doer.IsActions() {
+		// no need to push it to the queue, see notify
+		log.Debug("ignore executing %v for event %v whose doer is %v", getMethod(ctx), input.Event, input.Doer.Name)
+		return
+	}
+
+	if err := pushNotifyInput(ctx, input); err != nil {
 		log.Error("an error occurred while executing the %s actions method: %v", getMethod(ctx), err)
 	}
 }
@@ -143,39 +168,97 @@ func notify(ctx context.Context, input *notifyInput) error {
 	}
 	defer gitRepo.Close()
 
-	ref := input.Ref
-	if ref != input.Repo.DefaultBranch && actions_module.IsDefaultBranchWorkflow(input.Event) {
-		if ref != "" {
+	ref := input.workflowRef()
+	if ref != input.Ref {
+		if input.Ref != "" {
 			log.Warn("Event %q should only trigger workflows on the default branch, but its ref is %q. Will fall back to the default branch",
-				input.Event, ref)
+				input.Event, input.Ref)
+		} else {
+			log.Warn("Ref of event %q is empty, will fall back to the default branch", input.Event)
 		}
-		ref = input.Repo.DefaultBranch
-	}
-	if ref == "" {
-		log.Warn("Ref of event %q is empty, will fall back to the default branch", input.Event)
-		ref = input.Repo.DefaultBranch
 	}
 
-	// Get the commit object for the ref
-	commit, err := gitRepo.GetCommit(ref)
+	// Get the commit object of the event, or of the ref if it's unknown
+	commitID := input.CommitID
+	if commitID == "" {
+		commitID = ref
+	}
+	commit, err := gitRepo.GetCommit(commitID)
 	if err != nil {
 		return fmt.Errorf("gitRepo.GetCommit: %w", err)
 	}
 
+	if user_model.IsUserBlockedBy(ctx, input.Doer, input.Repo.OwnerID) {
+		log.Debug("repo %s: skipped the workflows of event %s because %s is blocked by the owner", input.Repo.RepoPath(), input.Event, input.Doer.Name)
+		recordRunCreationFailure(ctx, input, ref, commit.ID.String(), "", actions_model.RunCreationFailureActorBlocked,
+			fmt.Sprintf("%s is blocked by the owner of the repository", input.Doer.Name))
+		return nil
+	}
+
+	if payload, ok := input.Payload.(*api.PushPayload); ok && input.Event == webhook_module.HookEventPush && !input.IsBackfill {
+		lintWorkflowsOfPush(ctx, input.Repo, payload, commit)
+		if git.RefName(input.Ref).BranchName() == input.Repo.DefaultBranch {
+			indexActionUsages(ctx, input.Repo, commit)
+		}
+	}
+
 	if skipWorkflows(input, commit) {
 		return nil
 	}
 
-	var detectedWorkflows []*actions_module.DetectedWorkflow
+	if isCoalescedPush(gitRepo, input, commit) {
+		return nil
+	}
+
+	// the schedules are only updated by the latest commit of the default branch
+	shouldDetectSchedules := input.Event == webhook_module.HookEventPush && git.RefName(input.Ref).BranchName() == input.Repo.DefaultBranch && !input.IsBackfill
+	detectedWorkflows, schedules, rejected, err := detectTriggeredWorkflows(ctx, gitRepo, commit, input, shouldDetectSchedules)
+	if err != nil {
+		return err
+	}
+	for _, wf := range rejected {
+		reason := actions_model.RunCreationFailurePolicyRejected
+		if wf.Invalid {
+			reason = actions_model.RunCreationFailureInvalidWorkflow
+		}
+		recordRunCreationFailure(ctx, input, ref, commit.ID.String(), wf.EntryName, reason, wf.Err.Error())
+	}
+
+	if shouldDetectSchedules {
+		if err := handleSchedules(ctx, schedules, commit, input, ref); err != nil {
+			return err
+		}
+	}
+
+	return handleWorkflows(ctx, detectedWorkflows, commit, input, ref)
+}
+
+// workflowRef returns the ref whose workflows should be triggered by the event,
+// it's the default branch if the event only triggers the workflows on the default branch or has no ref.
+func (input *notifyInput) workflowRef() string {
+	if input.Ref == "" || (input.Ref != input.Repo.DefaultBranch && actions_module.IsDefaultBranchWorkflow(input.Event)) {
+		return input.Repo.DefaultBranch
+	}
+	return input.Ref
+}
+
+// detectTriggeredWorkflows returns the enabled workflows triggered by the event at the commit,
+// including the pull_request_target workflows of the base branch, and the schedules if shouldDetectSchedules
+func detectTriggeredWorkflows(
+	ctx context.Context,
+	gitRepo *git.Repository,
+	commit *git.Commit,
+	input *notifyInput,
+	shouldDetectSchedules bool,
+) (detectedWorkflows, schedules []*actions_module.DetectedWorkflow, rejected []*actions_module.RejectedWorkflow, err error) {
 	actionsConfig := input.Repo.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig()
-	shouldDetectSchedules := input.Event == webhook_module.HookEventPush && git.RefName(input.Ref).BranchName() == input.Repo.DefaultBranch
-	workflows, schedules, err := actions_module.DetectWorkflows(gitRepo, commit,
+	workflows, schedules, rejected, err := actions_module.DetectWorkflowsWithRejected(gitRepo, commit,
 		input.Event,
 		input.Payload,
 		shouldDetectSchedules,
 	)
 	if err != nil {
-		return fmt.Errorf("DetectWorkflows: %w", err)
+		return nil, nil, nil, fmt.Errorf("DetectWorkflows: %w", err)
 	}
 
 	log.Trace("repo %s with commit %s event %s find %d workflows and %d schedules",
@@ -186,27 +269,41 @@ func notify(ctx context.Context, input *notifyInput) error {
 		len(schedules),
 	)
 
+	dispatch, isDispatch := input.Payload.(*api.WorkflowDispatchPayload)
+	isDispatch = isDispatch && input.Event == webhook_module.HookEventWorkflowDispatch
+
 	for _, wf := range workflows {
 		if actionsConfig.IsWorkflowDisabled(wf.EntryName) {
 			log.Trace("repo %s has disable workflows %s", input.Repo.RepoPath(), wf.EntryName)
 			continue
 		}
 
+		if isDispatch {
+			// only the requested workflow is dispatched
+			if wf.EntryName != dispatch.Workflow {
+				continue
+			}
+			if err := prepareWorkflowDispatchInputs(wf.Content, dispatch); err != nil {
+				log.Warn("repo %s couldn't dispatch workflow %s: %v", input.Repo.RepoPath(), wf.EntryName, err)
+				return nil, nil, []*actions_module.RejectedWorkflow{{EntryName: wf.EntryName, Invalid: true, Err: err}}, nil
+			}
+		}
+
 		if wf.TriggerEvent.Name != actions_module.GithubEventPullRequestTarget {
 			detectedWorkflows = append(detectedWorkflows, wf)
 		}
 	}
 
-	if input.PullRequest != nil {
+	if input.PullRequest != nil && !isDispatch {
 		// detect pull_request_target workflows
 		baseRef := git.BranchPrefix + input.PullRequest.BaseBranch
 		baseCommit, err := gitRepo.GetCommit(baseRef)
 		if err != nil {
-			return fmt.Errorf("gitRepo.GetCommit: %w", err)
+			return nil, nil, nil, fmt.Errorf("gitRepo.GetCommit: %w", err)
 		}
 		baseWorkflows, _, err := actions_module.DetectWorkflows(gitRepo, baseCommit, input.Event, input.Payload, false)
 		if err != nil {
-			return fmt.Errorf("DetectWorkflows: %w", err)
+			return nil, nil, nil, fmt.Errorf("DetectWorkflows: %w", err)
 		}
 		if len(baseWorkflows) == 0 {
 			log.Trace("repo %s with commit %s couldn't find pull_request_target workflows", input.Repo.RepoPath(), baseCommit.ID)
@@ -219,13 +316,7 @@ func notify(ctx context.Context, input *notifyInput) error {
 		}
 	}
 
-	if shouldDetectSchedules {
-		if err := handleSchedules(ctx, schedules, commit, input, ref); err != nil {
-			return err
-		}
-	}
-
-	return handleWorkflows(ctx, detectedWorkflows, commit, input, ref)
+	return detectedWorkflows, schedules, rejected, nil
 }
 
 func skipWorkflows(input *notifyInput, commit *git.Commit) bool {
@@ -251,6 +342,96 @@ func skipWorkflows(input *notifyInput, commit *git.Commit) bool {
 	return false
 }
 
+// isCoalescedPush returns true if the branch of the push event has been pushed again,
+// the workflows will be triggered by the event of the latest push, so the rapid successive pushes are coalesced.
+func isCoalescedPush(gitRepo *git.Repository, input *notifyInput, commit *git.Commit) bool {
+	if !setting.Actions.CoalescePushes || input.Event != webhook_module.HookEventPush || input.IsBackfill || !git.RefName(input.Ref).IsBranch() {
+		return false
+	}
+	headID, err := gitRepo.GetRefCommitID(input.Ref)
+	if err != nil {
+		// the branch could have been deleted, let the event be handled as usual
+		return false
+	}
+	if headID != commit.ID.String() {
+		log.Debug("repo %s: skipped run for commit %s because %s has been pushed to %s", input.Repo.RepoPath(), commit.ID, headID, input.Ref)
+		return true
+	}
+	return false
+}
+
+// throttleWorkflows truncates the workflows to the runs the repository could still create in the current minute,
+// so an import script pushing hundreds of refs won't flood the queue, see setting.Actions.MaxRunsPerMinute
+func throttleWorkflows(ctx context.Context, repo *repo_model.Repository, workflows []*actions_module.DetectedWorkflow) ([]*actions_module.DetectedWorkflow, error) {
+	limit := setting.Actions.MaxRunsPerMinute
+	if limit <= 0 || len(workflows) == 0 {
+		return workflows, nil
+	}
+	count, err := actions_model.CountRunsCreatedSince(ctx, repo.ID, timeutil.TimeStampNow().Add(-60))
+	if err != nil {
+		return nil, fmt.Errorf("CountRunsCreatedSince: %w", err)
+	}
+	remaining := max(limit-count, 0)
+	if int64(len(workflows)) <= remaining {
+		return workflows, nil
+	}
+	for _, dwf := range workflows[remaining:] {
+		log.Warn("repo %s: skipped run of workflow %s because the repository has created %d runs in the last minute", repo.RepoPath(), dwf.EntryName, count)
+	}
+	return workflows[:remaining], nil
+}
+
+// isDeduplicatedEvent returns true if the runs of the event are identified by the delivery of the event,
+// so the event delivered twice, e.g. by the racing syncs of a mirror, shouldn't create the runs twice, see actions_model.MarkDuplicateRun.
+// The other events, like comments, could trigger the same workflow on the same commit many times.
+func isDeduplicatedEvent(input *notifyInput) bool {
+	if input.IsRedeliver {
+		return false
+	}
+	return input.Event == webhook_module.HookEventPush || input.Event == webhook_module.HookEventPullRequestSync
+}
+
+// deliveryBefore returns the commit of the ref before the event if it's known, see actions_model.MarkDuplicateRun
+func (input *notifyInput) deliveryBefore() string {
+	if payload, ok := input.Payload.(*api.PushPayload); ok {
+		return payload.Before
+	}
+	return ""
+}
+
+// supersedePullRequestRuns cancels the runs of the previous head commits of the synchronized pull request,
+// as configured by the repository, see repo_model.ActionsConfig.SupersedePullRequestRuns
+func supersedePullRequestRuns(ctx context.Context, repo *repo_model.Repository, ref, headCommitSHA string) {
+	supersede := repo.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig().GetSupersedePullRequestRuns()
+	if supersede == repo_model.ActionsSupersedePullRequestRunsNone {
+		return
+	}
+	jobs, err := actions_model.CancelOutdatedPullRequestRuns(ctx, repo.ID, ref, headCommitSHA, supersede == repo_model.ActionsSupersedePullRequestRunsAll)
+	if err != nil {
+		log.Error("CancelOutdatedPullRequestRuns: %v", err)
+		return
+	}
+	CreateCommitStatus(ctx, jobs...)
+}
+
+// cancelExcessQueuedRuns cancels the oldest queued runs of the repository not of the default branch as superseded
+// if the repository has queued more runs than the limit, see setting.Actions.MaxQueuedRunsPerRepo
+func cancelExcessQueuedRuns(ctx context.Context, repo *repo_model.Repository) {
+	limit := setting.Actions.MaxQueuedRunsPerRepo
+	if limit <= 0 {
+		return
+	}
+	jobs, err := actions_model.CancelExcessQueuedRuns(ctx, repo.ID, git.BranchPrefix+repo.DefaultBranch, limit)
+	if err != nil {
+		log.Error("CancelExcessQueuedRuns: %v", err)
+		return
+	}
+	if len(jobs) > 0 {
+		log.Info("repo %s: cancelled the jobs of the oldest queued runs as superseded because more than %d runs are queued", repo.RepoPath(), limit)
+	}
+	CreateCommitStatus(ctx, jobs...)
+}
+
 func handleWorkflows(
 	ctx context.Context,
 	detectedWorkflows []*actions_module.DetectedWorkflow,
@@ -258,11 +439,28 @@ func handleWorkflows(
 	input *notifyInput,
 	ref string,
 ) error {
+	// the runs of the previous head commits are outdated even if the new one triggers no workflows
+	if input.Event == webhook_module.HookEventPullRequestSync && input.PullRequest != nil && !input.IsBackfill {
+		supersedePullRequestRuns(ctx, input.Repo, ref, commit.ID.String())
+	}
+
 	if len(detectedWorkflows) == 0 {
 		log.Trace("repo %s with commit %s couldn't find workflows", input.Repo.RepoPath(), commit.ID)
 		return nil
 	}
 
+	if !input.IsBackfill {
+		throttled, err := throttleWorkflows(ctx, input.Repo, detectedWorkflows)
+		if err != nil {
+			return err
+		}
+		for _, dwf := range detectedWorkflows[len(throttled):] {
+			recordRunCreationFailure(ctx, input, ref, commit.ID.String(), dwf.EntryName, actions_model.RunCreationFailureQuotaExceeded,
+				fmt.Sprintf("the repository has created more than %d runs in the last minute", setting.Actions.MaxRunsPerMinute))
+		}
+		detectedWorkflows = throttled
+	}
+
 	p, err := json.Marshal(input.Payload)
 	if err != nil {
 		return fmt.Errorf("json.Marshal: %w", err)
@@ -283,25 +481,42 @@ func handleWorkflows(
 		}
 	}
 
+	runs := make([]*actions_model.RunWithJobs, 0, len(detectedWorkflows))
 	for _, dwf := range detectedWorkflows {
 		run := &actions_model.ActionRun{
-			Title:             strings.SplitN(commit.CommitMessage, "\n", 2)[0],
-			RepoID:            input.Repo.ID,
-			OwnerID:           input.Repo.OwnerID,
-			WorkflowID:        dwf.EntryName,
-			TriggerUserID:     input.Doer.ID,
-			Ref:               ref,
-			CommitSHA:         commit.ID.String(),
-			IsForkPullRequest: isForkPullRequest,
-			Event:             input.Event,
-			EventPayload:      string(p),
-			TriggerEvent:      dwf.TriggerEvent.Name,
-			Status:            actions_model.StatusWaiting,
+			Title:               strings.SplitN(commit.CommitMessage, "\n", 2)[0],
+			RepoID:              input.Repo.ID,
+			OwnerID:             input.Repo.OwnerID,
+			WorkflowID:          dwf.EntryName,
+			TriggerUserID:       input.Doer.ID,
+			Ref:                 ref,
+			CommitSHA:           commit.ID.String(),
+			IsForkPullRequest:   isForkPullRequest,
+			Event:               input.Event,
+			EventPayload:        string(p),
+			EventPayloadVersion: actions_model.EventPayloadVersion,
+			TriggerEvent:        dwf.TriggerEvent.Name,
+			Component:           actions_module.ParseWorkflowComponent(dwf.Content, dwf.TriggerEvent),
+			Status:              actions_model.StatusWaiting,
+		}
+
+		if isDeduplicatedEvent(input) {
+			duplicate, err := actions_model.MarkDuplicateRun(ctx, run, input.deliveryBefore())
+			if err != nil {
+				log.Error("MarkDuplicateRun: %v", err)
+				recordRunCreationFailure(ctx, input, ref, run.CommitSHA, dwf.EntryName, actions_model.RunCreationFailureInternalError, runCreationFailureInternalMessage)
+				continue
+			}
+			if duplicate {
+				log.Info("repo %s: skipped duplicate run of workflow %s for event %s of commit %s", input.Repo.RepoPath(), dwf.EntryName, input.Event, run.CommitSHA)
+				continue
+			}
 		}
 
 		need, err := ifNeedApproval(ctx, run, input.Repo, input.Doer)
 		if err != nil {
 			log.Error("check if need approval for repo %d with user %d: %v", input.Repo.ID, input.Doer.ID, err)
+			recordRunCreationFailure(ctx, input, ref, run.CommitSHA, dwf.EntryName, actions_model.RunCreationFailureInternalError, runCreationFailureInternalMessage)
 			continue
 		}
 
@@ -309,24 +524,35 @@ func handleWorkflows(
 
 		if err := run.LoadAttributes(ctx); err != nil {
 			log.Error("LoadAttributes: %v", err)
+			recordRunCreationFailure(ctx, input, ref, run.CommitSHA, dwf.EntryName, actions_model.RunCreationFailureInternalError, runCreationFailureInternalMessage)
 			continue
 		}
 
 		vars, err := actions_model.GetVariablesOfRun(ctx, run)
 		if err != nil {
 			log.Error("GetVariablesOfRun: %v", err)
+			recordRunCreationFailure(ctx, input, ref, run.CommitSHA, dwf.EntryName, actions_model.RunCreationFailureInternalError, runCreationFailureInternalMessage)
 			continue
 		}
 
 		jobs, err := jobparser.Parse(dwf.Content, jobparser.WithVars(vars))
 		if err != nil {
 			log.Error("jobparser.Parse: %v", err)
+			recordRunCreationFailure(ctx, input, ref, run.CommitSHA, dwf.EntryName, actions_model.RunCreationFailureInvalidWorkflow, err.Error())
 			continue
 		}
 
-		// cancel running jobs if the event is push or pull_request_sync
-		if run.Event == webhook_module.HookEventPush ||
-			run.Event == webhook_module.HookEventPullRequestSync {
+		// the workflow-level failures below don't stop the run from being created, every job of it fails
+		// with the failure as its preflight error instead, so the failure is visible to the users, see addPreflightError
+		requirementsErr := checkRequirements(ctx, run, dwf.Content, vars)
+		if requirementsErr != nil {
+			log.Warn("checkRequirements of workflow %q: %v", dwf.EntryName, requirementsErr)
+		}
+
+		// cancel running jobs if the event is push or pull_request_sync,
+		// the runs backfilled for the commits of a branch shouldn't cancel each other
+		if (run.Event == webhook_module.HookEventPush ||
+			run.Event == webhook_module.HookEventPullRequestSync) && !input.IsBackfill {
 			if err := actions_model.CancelPreviousJobs(
 				ctx,
 				run.RepoID,
@@ -338,17 +564,80 @@ func handleWorkflows(
 			}
 		}
 
-		if err := actions_model.InsertRun(ctx, run, jobs); err != nil {
-			log.Error("InsertRun: %v", err)
+		concurrencyErr := prepareConcurrency(ctx, run, dwf.Content, vars)
+		if concurrencyErr != nil {
+			log.Warn("prepareConcurrency of workflow %q: %v", dwf.EntryName, concurrencyErr)
+		}
+
+		preflightErrs, err := preflight(ctx, run, dwf.Content, jobs)
+		if err != nil {
+			log.Error("preflight: %v", err)
+			recordRunCreationFailure(ctx, input, ref, run.CommitSHA, dwf.EntryName, actions_model.RunCreationFailureInternalError, runCreationFailureInternalMessage)
 			continue
 		}
+		if requirementsErr != nil {
+			addPreflightError(preflightErrs, jobs, fmt.Sprintf("failed to check the requirements of the workflow: %v", requirementsErr))
+		}
+		if concurrencyErr != nil {
+			addPreflightError(preflightErrs, jobs, fmt.Sprintf("invalid concurrency of the workflow: %v", concurrencyErr))
+		}
+		permissions, err := actions_module.ParseJobPermissions(dwf.Content)
+		if err != nil {
+			log.Warn("ParseJobPermissions of workflow %q: %v", dwf.EntryName, err)
+			addPreflightError(preflightErrs, jobs, fmt.Sprintf("invalid permissions of the workflow: %v", err))
+		}
+		environments, err := actions_module.ParseJobEnvironments(dwf.Content)
+		if err != nil {
+			log.Warn("ParseJobEnvironments of workflow %q: %v", dwf.EntryName, err)
+			addPreflightError(preflightErrs, jobs, fmt.Sprintf("invalid environments of the workflow: %v", err))
+		}
+		if err := prepareCheckout(run, dwf.Content); err != nil {
+			log.Warn("prepareCheckout of workflow %q: %v", dwf.EntryName, err)
+			addPreflightError(preflightErrs, jobs, fmt.Sprintf("invalid checkout hints of the workflow: %v", err))
+		}
+		if err := prepareEgress(ctx, run, dwf.Content); err != nil {
+			log.Warn("prepareEgress of workflow %q: %v", dwf.EntryName, err)
+			addPreflightError(preflightErrs, jobs, fmt.Sprintf("invalid egress of the workflow: %v", err))
+		}
+
+		runs = append(runs, &actions_model.RunWithJobs{Run: run, Jobs: jobs, PreflightErrors: preflightErrs, TokenPermissions: permissions, Environments: environments})
+	}
 
-		alljobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	// reserve the indexes of all the runs triggered by the event at once, so the event contends the index record only once,
+	// every run is still inserted in a transaction of its own, so a failed run doesn't affect the others
+	if len(runs) > 1 {
+		firstIndex, err := actions_model.ReserveRunIndexes(ctx, input.Repo.ID, int64(len(runs)))
 		if err != nil {
-			log.Error("FindRunJobs: %v", err)
+			// not fatal, the runs allocate their indexes one by one
+			log.Error("ReserveRunIndexes: %v", err)
+		} else {
+			for i, r := range runs {
+				r.Run.Index = firstIndex + int64(i)
+			}
+		}
+	}
+
+	runIDs := make([]int64, 0, len(runs))
+	for _, r := range runs {
+		if err := actions_model.InsertRunWithJobs(ctx, r); err != nil {
+			log.Error("InsertRun: %v", err)
+			recordRunCreationFailure(ctx, input, ref, r.Run.CommitSHA, r.Run.WorkflowID, actions_model.RunCreationFailureInternalError, runCreationFailureInternalMessage)
 			continue
 		}
-		CreateCommitStatus(ctx, alljobs...)
+		runIDs = append(runIDs, r.Run.ID)
+
+		if r.Run.NeedApproval {
+			notifyRunApprovers(ctx, r.Run)
+		}
+	}
+
+	if len(runIDs) > 0 {
+		cancelExcessQueuedRuns(ctx, input.Repo)
+	}
+
+	if err := EmitOutboxEvents(ctx, runIDs...); err != nil {
+		// the events are still in the outbox and will be processed later
+		log.Error("EmitOutboxEvents: %v", err)
 	}
 	return nil
 }
@@ -413,6 +702,12 @@ func ifNeedApproval(ctx context.Context, run *actions_model.ActionRun, repo *rep
 		return true, nil
 	}
 
+	approval := repo.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig().GetForkPullRequestApproval()
+	if approval == repo_model.ActionsForkPullRequestApprovalNever {
+		log.Trace("do not need approval because repo %d doesn't require approval for pull requests from forks", repo.ID)
+		return false, nil
+	}
+
 	// don't need approval if the user can write
 	if perm, err := access_model.GetUserRepoPermission(ctx, repo, user); err != nil {
 		return false, fmt.Errorf("GetUserRepoPermission: %w", err)
@@ -421,6 +716,11 @@ func ifNeedApproval(ctx context.Context, run *actions_model.ActionRun, repo *rep
 		return false, nil
 	}
 
+	if approval == repo_model.ActionsForkPullRequestApprovalAlways {
+		log.Trace("need approval because repo %d requires approval for all pull requests from forks", repo.ID)
+		return true, nil
+	}
+
 	// don't need approval if the user has been approved before
 	if count, err := db.Count[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
 		RepoID:        repo.ID,
@@ -488,17 +788,18 @@ func handleSchedules(
 		}
 
 		run := &actions_model.ActionSchedule{
-			Title:         strings.SplitN(commit.CommitMessage, "\n", 2)[0],
-			RepoID:        input.Repo.ID,
-			OwnerID:       input.Repo.OwnerID,
-			WorkflowID:    dwf.EntryName,
-			TriggerUserID: user_model.ActionsUserID,
-			Ref:           ref,
-			CommitSHA:     commit.ID.String(),
-			Event:         input.Event,
-			EventPayload:  string(p),
-			Specs:         schedules,
-			Content:       dwf.Content,
+			Title:               strings.SplitN(commit.CommitMessage, "\n", 2)[0],
+			RepoID:              input.Repo.ID,
+			OwnerID:             input.Repo.OwnerID,
+			WorkflowID:          dwf.EntryName,
+			TriggerUserID:       user_model.ActionsUserID,
+			Ref:                 ref,
+			CommitSHA:           commit.ID.String(),
+			Event:               input.Event,
+			EventPayload:        string(p),
+			EventPayloadVersion: actions_model.EventPayloadVersion,
+			Specs:               schedules,
+			Content:             dwf.Content,
 		}
 		crons = append(crons, run)
 	}
@@ -0,0 +1,230 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+)
+
+// notifyQueue decouples detecting workflows and inserting runs from the notification handlers,
+// so a burst of events or a slow database won't delay the responses of web requests and API calls.
+var notifyQueue *queue.WorkerPoolQueue[*notifyInputItem]
+
+// notifyInputItem is the serializable form of notifyInput to be pushed to notifyQueue
+type notifyInputItem struct {
+	Method        string
+	RepoID        int64
+	DoerID        int64
+	Event         webhook_module.HookEventType
+	Ref           string
+	CommitID      string
+	Payload       []byte
+	PullRequestID int64
+	IsBackfill    bool
+	IsRedeliver   bool
+}
+
+func newNotifyInputItem(ctx context.Context, input *notifyInput) (*notifyInputItem, error) {
+	item := &notifyInputItem{
+		Method:      getMethod(ctx),
+		RepoID:      input.Repo.ID,
+		DoerID:      input.Doer.ID,
+		Event:       input.Event,
+		Ref:         input.Ref,
+		CommitID:    input.CommitID,
+		IsBackfill:  input.IsBackfill,
+		IsRedeliver: input.IsRedeliver,
+	}
+	if item.CommitID == "" {
+		// resolve the commit now, since the ref could have been updated when the item is handled,
+		// then the run would be created for a commit which doesn't match the payload
+		commitID, err := resolveCommitID(input)
+		if err != nil {
+			// it's not fatal, the commit will be resolved when the item is handled
+			log.Warn("Failed to resolve the commit of event %q in repo %d: %v", input.Event, input.Repo.ID, err)
+		}
+		item.CommitID = commitID
+	}
+	if input.Payload != nil {
+		p, err := json.Marshal(input.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+		item.Payload = p
+	}
+	if input.PullRequest != nil {
+		item.PullRequestID = input.PullRequest.ID
+	}
+	return item, nil
+}
+
+func (item *notifyInputItem) toNotifyInput(ctx context.Context) (*notifyInput, error) {
+	repo, err := repo_model.GetRepositoryByID(ctx, item.RepoID)
+	if err != nil {
+		return nil, fmt.Errorf("GetRepositoryByID: %w", err)
+	}
+	doer, err := user_model.GetPossibleUserByID(ctx, item.DoerID)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			return nil, fmt.Errorf("GetPossibleUserByID: %w", err)
+		}
+		// the doer has been deleted after the event
+		doer = user_model.NewGhostUser()
+	}
+	input := newNotifyInput(repo, doer, item.Event).WithRef(item.Ref).WithCommitID(item.CommitID)
+	input.IsBackfill = item.IsBackfill
+	input.IsRedeliver = item.IsRedeliver
+
+	if len(item.Payload) > 0 {
+		payload := newPayloadOfEvent(item.Event)
+		if payload == nil {
+			return nil, fmt.Errorf("unsupported payload of event %q", item.Event)
+		}
+		if err := json.Unmarshal(item.Payload, payload); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		input.WithPayload(payload)
+	}
+
+	if item.PullRequestID > 0 {
+		pr, err := issues_model.GetPullRequestByID(ctx, item.PullRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("GetPullRequestByID: %w", err)
+		}
+		if err := pr.LoadIssue(ctx); err != nil {
+			return nil, fmt.Errorf("LoadIssue: %w", err)
+		}
+		input.WithPullRequest(pr)
+	}
+	return input, nil
+}
+
+// resolveCommitID returns the commit of the ref whose workflows will be triggered by the event,
+// or empty if the workflows won't be detected at all
+func resolveCommitID(input *notifyInput) (string, error) {
+	if input.Repo.IsEmpty || input.Repo.IsArchived {
+		return "", nil
+	}
+	gitRepo, err := gitrepo.OpenRepository(context.Background(), input.Repo)
+	if err != nil {
+		return "", fmt.Errorf("git.OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(input.workflowRef())
+	if err != nil {
+		return "", fmt.Errorf("gitRepo.GetCommit: %w", err)
+	}
+	return commit.ID.String(), nil
+}
+
+// newPayloadOfEvent returns an empty payload of the type sent with the event
+func newPayloadOfEvent(event webhook_module.HookEventType) api.Payloader {
+	switch event {
+	case webhook_module.HookEventCreate:
+		return &api.CreatePayload{}
+	case webhook_module.HookEventDelete:
+		return &api.DeletePayload{}
+	case webhook_module.HookEventFork:
+		return &api.ForkPayload{}
+	case webhook_module.HookEventPush:
+		return &api.PushPayload{}
+	case webhook_module.HookEventIssues,
+		webhook_module.HookEventIssueAssign,
+		webhook_module.HookEventIssueLabel,
+		webhook_module.HookEventIssueMilestone:
+		return &api.IssuePayload{}
+	case webhook_module.HookEventIssueComment,
+		webhook_module.HookEventPullRequestComment:
+		return &api.IssueCommentPayload{}
+	case webhook_module.HookEventPullRequest,
+		webhook_module.HookEventPullRequestSync,
+		webhook_module.HookEventPullRequestAssign,
+		webhook_module.HookEventPullRequestLabel,
+		webhook_module.HookEventPullRequestMilestone,
+		webhook_module.HookEventPullRequestReviewRequest,
+		webhook_module.HookEventPullRequestReviewApproved,
+		webhook_module.HookEventPullRequestReviewRejected,
+		webhook_module.HookEventPullRequestReviewComment:
+		return &api.PullRequestPayload{}
+	case webhook_module.HookEventWiki:
+		return &api.WikiPayload{}
+	case webhook_module.HookEventRepository:
+		return &api.RepositoryPayload{}
+	case webhook_module.HookEventRelease:
+		return &api.ReleasePayload{}
+	case webhook_module.HookEventPackage:
+		return &api.PackagePayload{}
+	case webhook_module.HookEventWorkflowDispatch:
+		return &api.WorkflowDispatchPayload{}
+	}
+	return nil
+}
+
+// pushNotifyInput pushes the input to notifyQueue, or handles it directly if the queue isn't available
+func pushNotifyInput(ctx context.Context, input *notifyInput) error {
+	if notifyQueue == nil {
+		return notify(ctx, input)
+	}
+	item, err := newNotifyInputItem(ctx, input)
+	if err != nil {
+		return err
+	}
+	if err := notifyQueue.Push(item); err != nil && !errors.Is(err, queue.ErrAlreadyInQueue) {
+		return fmt.Errorf("push to notify queue: %w", err)
+	}
+	return nil
+}
+
+func notifyQueueHandler(items ...*notifyInputItem) []*notifyInputItem {
+	return handleNotifyInputItems(graceful.GetManager().ShutdownContext(), items)
+}
+
+// handleNotifyInputItems handles the items, the ones which fail are stored as dead letters,
+// and the ones interrupted by the shutdown are returned to be handled again after the restart
+func handleNotifyInputItems(ctx context.Context, items []*notifyInputItem) []*notifyInputItem {
+	var unhandled []*notifyInputItem
+	for _, item := range items {
+		err := handleNotifyInputItem(ctx, item)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			// shutting down, the item is handled again after the restart
+			unhandled = append(unhandled, item)
+			continue
+		}
+		log.Error("an error occurred while executing the %s actions method for repo %d: %v", item.Method, item.RepoID, err)
+		insertDeadLetter(ctx, item, err)
+	}
+	return unhandled
+}
+
+// handleNotifyInputItem restores the input of the item and triggers the workflows, a panic is returned as an error with the stack
+func handleNotifyInputItem(ctx context.Context, item *notifyInputItem) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, log.Stack(2))
+		}
+	}()
+	ctx = withMethod(ctx, item.Method)
+	input, err := item.toNotifyInput(ctx)
+	if err != nil {
+		return fmt.Errorf("restore the input: %w", err)
+	}
+	return notify(ctx, input)
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyInputItem(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: 1})
+
+	// roundTrip serializes the item like the queue, and restores the input from it
+	roundTrip := func(t *testing.T, input *notifyInput) (*notifyInputItem, *notifyInput) {
+		item, err := newNotifyInputItem(ctx, input)
+		require.NoError(t, err)
+		data, err := json.Marshal(item)
+		require.NoError(t, err)
+		var decoded notifyInputItem
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, item, &decoded)
+		restored, err := decoded.toNotifyInput(ctx)
+		require.NoError(t, err)
+		return &decoded, restored
+	}
+
+	t.Run("Push", func(t *testing.T) {
+		input := newNotifyInput(repo, doer, webhook_module.HookEventPush).
+			WithRef("refs/heads/master").
+			WithPayload(&api.PushPayload{Ref: "refs/heads/master", Before: "4a357436d925b5c974181ff12a994538ddc5a269"})
+		item, restored := roundTrip(t, input)
+
+		// the commit of the ref is resolved when the item is pushed to the queue
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", item.CommitID)
+		assert.Equal(t, item.CommitID, restored.CommitID)
+		assert.Equal(t, repo.ID, restored.Repo.ID)
+		assert.Equal(t, doer.ID, restored.Doer.ID)
+		assert.Equal(t, "refs/heads/master", restored.Ref)
+		assert.Nil(t, restored.PullRequest)
+		payload, ok := restored.Payload.(*api.PushPayload)
+		require.True(t, ok)
+		assert.Equal(t, "4a357436d925b5c974181ff12a994538ddc5a269", payload.Before)
+	})
+
+	t.Run("PullRequest", func(t *testing.T) {
+		input := newNotifyInput(repo, doer, webhook_module.HookEventPullRequestSync).
+			WithCommitID("985f0301dba5e7b34be866819cd15ad3d8f508ee").
+			WithPayload(&api.PullRequestPayload{Action: api.HookIssueSynchronized, Index: pr.Index}).
+			WithPullRequest(pr).
+			WithRedeliver()
+		item, restored := roundTrip(t, input)
+
+		// the commit given by the event isn't resolved again
+		assert.Equal(t, "985f0301dba5e7b34be866819cd15ad3d8f508ee", item.CommitID)
+		assert.True(t, restored.IsRedeliver)
+		payload, ok := restored.Payload.(*api.PullRequestPayload)
+		require.True(t, ok)
+		assert.Equal(t, api.HookIssueSynchronized, payload.Action)
+		assert.Equal(t, pr.Index, payload.Index)
+		require.NotNil(t, restored.PullRequest)
+		assert.Equal(t, pr.ID, restored.PullRequest.ID)
+		assert.NotNil(t, restored.PullRequest.Issue)
+	})
+
+	t.Run("IssueComment", func(t *testing.T) {
+		input := newNotifyInput(repo, doer, webhook_module.HookEventIssueComment).
+			WithPayload(&api.IssueCommentPayload{Action: api.HookIssueCommentCreated, Comment: &api.Comment{Body: "hello"}})
+		_, restored := roundTrip(t, input)
+		payload, ok := restored.Payload.(*api.IssueCommentPayload)
+		require.True(t, ok)
+		assert.Equal(t, "hello", payload.Comment.Body)
+	})
+
+	t.Run("GhostDoer", func(t *testing.T) {
+		item := &notifyInputItem{RepoID: repo.ID, DoerID: 10000, Event: webhook_module.HookEventPush}
+		restored, err := item.toNotifyInput(ctx)
+		require.NoError(t, err)
+		assert.True(t, restored.Doer.IsGhost())
+	})
+
+	t.Run("UnsupportedPayload", func(t *testing.T) {
+		item := &notifyInputItem{RepoID: repo.ID, DoerID: doer.ID, Event: webhook_module.HookEventSchedule, Payload: []byte("{}")}
+		_, err := item.toNotifyInput(ctx)
+		assert.ErrorContains(t, err, "unsupported payload")
+	})
+}
+
+func TestHandleNotifyInputItems(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	// the repository of the event doesn't exist
+	item := &notifyInputItem{
+		Method: "PushCommits",
+		RepoID: 10001,
+		DoerID: user_model.ActionsUserID,
+		Event:  webhook_module.HookEventPush,
+		Ref:    "refs/heads/master",
+	}
+
+	t.Run("Shutdown", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(db.DefaultContext)
+		cancel()
+		unhandled := handleNotifyInputItems(ctx, []*notifyInputItem{item})
+		assert.Equal(t, []*notifyInputItem{item}, unhandled)
+		unittest.AssertNotExistsBean(t, &actions_model.ActionDeadLetter{RepoID: item.RepoID})
+	})
+
+	t.Run("DeadLetter", func(t *testing.T) {
+		unhandled := handleNotifyInputItems(db.DefaultContext, []*notifyInputItem{item})
+		assert.Empty(t, unhandled)
+		letter := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionDeadLetter{RepoID: item.RepoID})
+		assert.Equal(t, "PushCommits", letter.Method)
+		assert.Contains(t, letter.Error, "GetRepositoryByID")
+	})
+}
@@ -0,0 +1,273 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
+	notify_service "code.gitea.io/gitea/services/notify"
+	container_service "code.gitea.io/gitea/services/packages/container"
+)
+
+const (
+	// maxOutboxEventAttempts is the max attempts of processing an outbox event, it's kept as dead after that
+	maxOutboxEventAttempts = 10
+	// outboxEventMinBackoff and outboxEventMaxBackoff are the bounds of the exponential backoff of the failed outbox events,
+	// they're retried by the sweep of the cron task, so they aren't retried sooner than its schedule
+	outboxEventMinBackoff = time.Minute
+	outboxEventMaxBackoff = 6 * time.Hour
+)
+
+var outboxQueue *queue.WorkerPoolQueue[int64]
+
+// EmitOutboxEvents pushes the pending outbox events of the runs to the queue to process their side effects
+func EmitOutboxEvents(ctx context.Context, runIDs ...int64) error {
+	for _, runID := range runIDs {
+		events, err := db.Find[actions_model.ActionOutboxEvent](ctx, actions_model.FindOutboxEventOptions{
+			RunID:  runID,
+			IsDead: optional.Some(false),
+		})
+		if err != nil {
+			return fmt.Errorf("find outbox events of run %d: %w", runID, err)
+		}
+		if err := pushOutboxEvents(events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outboxEventSweepDelay is how old an outbox event must be to be swept by EmitPendingOutboxEvents,
+// the newer ones are probably being emitted by the changes which have inserted them.
+const outboxEventSweepDelay = time.Minute
+
+// EmitPendingOutboxEvents pushes the pending outbox events to the queue,
+// including the ones left by a crash between the commit of a change and the processing of its side effects,
+// the ones of the changes which don't emit their events, e.g. runs done by cancelling, and the failed ones whose backoff has passed.
+// It's called at start and periodically by the cron task.
+func EmitPendingOutboxEvents(ctx context.Context) error {
+	now := timeutil.TimeStampNow()
+	events, err := db.Find[actions_model.ActionOutboxEvent](ctx, actions_model.FindOutboxEventOptions{
+		CreatedBefore: now.AddDuration(-outboxEventSweepDelay),
+		DueBefore:     now,
+		IsDead:        optional.Some(false),
+	})
+	if err != nil {
+		return fmt.Errorf("find outbox events: %w", err)
+	}
+	return pushOutboxEvents(events)
+}
+
+func pushOutboxEvents(events []*actions_model.ActionOutboxEvent) error {
+	for _, event := range events {
+		if err := outboxQueue.Push(event.ID); err != nil && !errors.Is(err, queue.ErrAlreadyInQueue) {
+			return fmt.Errorf("push outbox event %d: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+func outboxQueueHandler(items ...int64) []int64 {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, id := range items {
+		// the failed event isn't pushed back, it's retried by the sweep after its backoff
+		if err := processOutboxEvent(ctx, id); err != nil {
+			log.Error("Failed to process outbox event %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+func processOutboxEvent(ctx context.Context, id int64) error {
+	event, err := actions_model.GetOutboxEventByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if event == nil || event.IsDead {
+		// it has been processed, or it's only retried by the admins
+		return nil
+	}
+	if event.NextAttemptUnix > timeutil.TimeStampNow() {
+		// it's backing off after a failure, the sweep pushes it again when it's due
+		return nil
+	}
+	if err := actions_model.IncreaseOutboxEventAttempts(ctx, event.ID); err != nil {
+		return err
+	}
+	event.Attempts++
+
+	var steps []outboxStep
+	switch event.Type {
+	case actions_model.OutboxEventRunCreated:
+		steps = runCreatedSteps
+	case actions_model.OutboxEventRunDone:
+		steps = runDoneSteps
+	default:
+		log.Warn("Unknown type %d of outbox event %d", event.Type, event.ID)
+	}
+	if err := runOutboxSteps(ctx, event, steps); err != nil {
+		return failOutboxEvent(ctx, event, err)
+	}
+
+	return actions_model.DeleteOutboxEvent(ctx, event.ID)
+}
+
+// failOutboxEvent records the error of the attempt of the outbox event, which is retried with an exponential backoff,
+// or kept as dead to be inspected and retried by the admins after maxOutboxEventAttempts, the error is returned
+func failOutboxEvent(ctx context.Context, event *actions_model.ActionOutboxEvent, cause error) error {
+	dead := event.Attempts >= maxOutboxEventAttempts
+	if dead {
+		log.Error("Outbox event %d of run %d is dead after %d attempts", event.ID, event.RunID, event.Attempts)
+	}
+	nextAttempt := timeutil.TimeStampNow().AddDuration(outboxEventBackoff(event.Attempts))
+	if err := actions_model.UpdateOutboxEventFailure(ctx, event, cause.Error(), nextAttempt, dead); err != nil {
+		log.Error("UpdateOutboxEventFailure: %v", err)
+	}
+	return cause
+}
+
+// outboxEventBackoff returns how long the outbox event waits after its failed attempts before the next one
+func outboxEventBackoff(attempts int) time.Duration {
+	backoff := outboxEventMinBackoff
+	for i := 1; i < attempts && backoff < outboxEventMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxEventMaxBackoff)
+}
+
+// RetryOutboxEvent makes the dead outbox event pending again and processes it, the steps done by its previous attempts aren't repeated
+func RetryOutboxEvent(ctx context.Context, event *actions_model.ActionOutboxEvent) error {
+	if err := actions_model.ResetOutboxEvent(ctx, event); err != nil {
+		return err
+	}
+	return pushOutboxEvents([]*actions_model.ActionOutboxEvent{event})
+}
+
+// outboxStep is a side effect of an outbox event of a run
+type outboxStep struct {
+	step actions_model.OutboxStep
+	do   func(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error
+}
+
+// runOutboxSteps runs the steps of the outbox event in order, the steps done by the previous attempts are skipped
+// and each step done is recorded at once, so retrying the event after a step has failed doesn't repeat the side effects of the previous steps
+func runOutboxSteps(ctx context.Context, event *actions_model.ActionOutboxEvent, steps []outboxStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	run, err := actions_model.GetRunByID(ctx, event.RunID)
+	if err != nil {
+		return fmt.Errorf("GetRunByID: %w", err)
+	}
+	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	if err != nil {
+		return fmt.Errorf("find jobs of run %d: %w", run.ID, err)
+	}
+	for _, step := range steps {
+		if event.DoneSteps&step.step != 0 {
+			continue
+		}
+		if err := step.do(ctx, run, jobs); err != nil {
+			return err
+		}
+		if err := actions_model.MarkOutboxEventStepDone(ctx, event, step.step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCreatedSteps are the side effects of a run which has been created
+var runCreatedSteps = []outboxStep{
+	{actions_model.OutboxStepCommitStatuses, createCommitStatusesOfJobs},
+	{actions_model.OutboxStepLifecycleEvent, func(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+		notifyRunsChanged(run.RepoID)
+		return publishRunEvent(ctx, run, jobs, LifecycleEventRunCreated)
+	}},
+	{actions_model.OutboxStepEmitJobs, func(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+		for _, job := range jobs {
+			if job.Status == actions_model.StatusSkipped {
+				// the jobs which need the disabled jobs are resolved by the job emitter
+				return EmitJobsIfReady(run.ID)
+			}
+		}
+		return nil
+	}},
+}
+
+// runDoneSteps are the side effects of a run which is done: it reports the final statuses of the jobs, which could have been missed if the process crashed,
+// closes the issues opened for the previous failures of the workflow if the run has passed,
+// creates the release of the tag of the run by the release automation of the repository,
+// removes the temporary images pushed to the scratch namespace of the run in the container registry,
+// and calls the post-run hook of the external systems.
+var runDoneSteps = []outboxStep{
+	{actions_model.OutboxStepCommitStatuses, createCommitStatusesOfJobs},
+	{actions_model.OutboxStepRunIssues, func(ctx context.Context, run *actions_model.ActionRun, _ []*actions_model.ActionRunJob) error {
+		return closeRunIssuesIfPassed(ctx, run.ID)
+	}},
+	{actions_model.OutboxStepRelease, func(ctx context.Context, run *actions_model.ActionRun, _ []*actions_model.ActionRunJob) error {
+		if err := createReleaseOfRun(ctx, run); err != nil {
+			return fmt.Errorf("create release of run %d: %w", run.ID, err)
+		}
+		return nil
+	}},
+	{actions_model.OutboxStepScratchImages, func(ctx context.Context, run *actions_model.ActionRun, _ []*actions_model.ActionRunJob) error {
+		if err := container_service.RemoveRunScratchImages(ctx, run.OwnerID, run.ID); err != nil {
+			return fmt.Errorf("remove scratch images of run %d: %w", run.ID, err)
+		}
+		return nil
+	}},
+	{actions_model.OutboxStepLifecycleEvent, func(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+		return publishRunEvent(ctx, run, jobs, LifecycleEventRunCompleted)
+	}},
+	{actions_model.OutboxStepPostRunHook, func(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+		// it isn't retried, since the external system could have handled it
+		if err := callPostRunHook(ctx, run, jobs); err != nil {
+			log.Error("Failed to call the post-run hook of run %d: %v", run.ID, err)
+		}
+		return nil
+	}},
+	{actions_model.OutboxStepNotification, func(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+		notify_service.ActionRunCompleted(ctx, run, jobs)
+		return nil
+	}},
+	{actions_model.OutboxStepConcurrencyGroup, func(ctx context.Context, run *actions_model.ActionRun, _ []*actions_model.ActionRunJob) error {
+		return releaseConcurrencyGroup(ctx, run)
+	}},
+}
+
+// createCommitStatusesOfJobs reports the statuses of the jobs, it does nothing for the statuses which have been reported
+func createCommitStatusesOfJobs(ctx context.Context, _ *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+	for _, job := range jobs {
+		if err := createCommitStatus(ctx, job); err != nil {
+			return fmt.Errorf("create commit status for job %d: %w", job.ID, err)
+		}
+	}
+	return nil
+}
+
+// releaseConcurrencyGroup starts the next run of the concurrency group of the run which is done
+func releaseConcurrencyGroup(ctx context.Context, run *actions_model.ActionRun) error {
+	if run.ConcurrencyGroup == "" {
+		return nil
+	}
+	next, err := actions_model.GetNextRunOfConcurrencyGroup(ctx, run.RepoID, run.ConcurrencyGroup)
+	if err != nil {
+		return fmt.Errorf("GetNextRunOfConcurrencyGroup: %w", err)
+	}
+	if next == nil {
+		return nil
+	}
+	return EmitJobsIfReady(next.ID)
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxEventBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, outboxEventBackoff(1))
+	assert.Equal(t, 2*time.Minute, outboxEventBackoff(2))
+	assert.Equal(t, 8*time.Minute, outboxEventBackoff(4))
+	assert.Equal(t, outboxEventMaxBackoff, outboxEventBackoff(maxOutboxEventAttempts))
+}
+
+func TestProcessOutboxEventSteps(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	calls := map[actions_model.OutboxStep]int{}
+	failRelease := true
+	newStep := func(step actions_model.OutboxStep) outboxStep {
+		return outboxStep{step, func(context.Context, *actions_model.ActionRun, []*actions_model.ActionRunJob) error {
+			calls[step]++
+			if step == actions_model.OutboxStepRelease && failRelease {
+				return errors.New("the release failed")
+			}
+			return nil
+		}}
+	}
+	defer test.MockVariableValue(&runDoneSteps, []outboxStep{
+		newStep(actions_model.OutboxStepCommitStatuses),
+		newStep(actions_model.OutboxStepRelease),
+		newStep(actions_model.OutboxStepNotification),
+	})()
+
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	require.NoError(t, actions_model.InsertOutboxEvent(ctx, run, actions_model.OutboxEventRunDone))
+	event := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionOutboxEvent{RunID: run.ID, Type: actions_model.OutboxEventRunDone})
+
+	// the step after the failed one isn't run, and the failed event backs off
+	assert.Error(t, processOutboxEvent(ctx, event.ID))
+	assert.Equal(t, map[actions_model.OutboxStep]int{actions_model.OutboxStepCommitStatuses: 1, actions_model.OutboxStepRelease: 1}, calls)
+	event = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionOutboxEvent{ID: event.ID})
+	assert.Equal(t, 1, event.Attempts)
+	assert.Equal(t, actions_model.OutboxStepCommitStatuses, event.DoneSteps)
+	assert.Equal(t, "the release failed", event.LastError)
+	assert.Greater(t, event.NextAttemptUnix, timeutil.TimeStampNow())
+	assert.False(t, event.IsDead)
+
+	// it isn't retried before its backoff has passed
+	assert.NoError(t, processOutboxEvent(ctx, event.ID))
+	assert.Equal(t, 1, calls[actions_model.OutboxStepRelease])
+	pending, err := db.Find[actions_model.ActionOutboxEvent](ctx, actions_model.FindOutboxEventOptions{RunID: run.ID, DueBefore: timeutil.TimeStampNow()})
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// the retry doesn't repeat the step done by the previous attempt
+	_, err = db.GetEngine(ctx).ID(event.ID).Cols("next_attempt_unix").Update(&actions_model.ActionOutboxEvent{NextAttemptUnix: 0})
+	require.NoError(t, err)
+	failRelease = false
+	assert.NoError(t, processOutboxEvent(ctx, event.ID))
+	assert.Equal(t, map[actions_model.OutboxStep]int{
+		actions_model.OutboxStepCommitStatuses: 1,
+		actions_model.OutboxStepRelease:        2,
+		actions_model.OutboxStepNotification:   1,
+	}, calls)
+	unittest.AssertNotExistsBean(t, &actions_model.ActionOutboxEvent{ID: event.ID})
+}
+
+func TestDeadOutboxEvent(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	released := 0
+	defer test.MockVariableValue(&runDoneSteps, []outboxStep{
+		{actions_model.OutboxStepRelease, func(context.Context, *actions_model.ActionRun, []*actions_model.ActionRunJob) error {
+			released++
+			return errors.New("the release failed")
+		}},
+	})()
+
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 792})
+	require.NoError(t, actions_model.InsertOutboxEvent(ctx, run, actions_model.OutboxEventRunDone))
+	event := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionOutboxEvent{RunID: run.ID, Type: actions_model.OutboxEventRunDone})
+	_, err := db.GetEngine(ctx).ID(event.ID).Cols("attempts").Update(&actions_model.ActionOutboxEvent{Attempts: maxOutboxEventAttempts - 1})
+	require.NoError(t, err)
+
+	// the event is kept as dead after the last attempt
+	assert.Error(t, processOutboxEvent(ctx, event.ID))
+	event, err = actions_model.GetDeadOutboxEventByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, maxOutboxEventAttempts, event.Attempts)
+	assert.Equal(t, "the release failed", event.LastError)
+
+	// it's only retried by the admins
+	_, err = db.GetEngine(ctx).ID(event.ID).Cols("next_attempt_unix").Update(&actions_model.ActionOutboxEvent{NextAttemptUnix: 0})
+	require.NoError(t, err)
+	assert.NoError(t, processOutboxEvent(ctx, event.ID))
+	assert.Equal(t, 1, released)
+	pending, err := db.Find[actions_model.ActionOutboxEvent](ctx, actions_model.FindOutboxEventOptions{RunID: run.ID, IsDead: optional.Some(false)})
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	require.NoError(t, actions_model.ResetOutboxEvent(ctx, event))
+	assert.Error(t, processOutboxEvent(ctx, event.ID))
+	assert.Equal(t, 2, released)
+	event = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionOutboxEvent{ID: event.ID})
+	assert.Equal(t, 1, event.Attempts)
+	assert.False(t, event.IsDead)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// RecordPackagePublished records the version of the package as published by the run, so the package retention of the repository applies to it.
+// The package must be linked to the repository of the run, and a version is only recorded for one run.
+func RecordPackagePublished(ctx context.Context, run *actions_model.ActionRun, packageType packages_model.Type, name, version string) (*packages_model.PackageVersion, error) {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, run.OwnerID, packageType, name, version)
+	if errors.Is(err, packages_model.ErrPackageNotExist) {
+		return nil, util.NewNotExistErrorf("version %s of %s package %s doesn't exist", version, packageType, name)
+	} else if err != nil {
+		return nil, err
+	}
+	p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
+	if err != nil {
+		return nil, err
+	}
+	if p.RepoID != run.RepoID {
+		return nil, util.NewInvalidArgumentErrorf("%s package %s isn't linked to the repository of the run", packageType, name)
+	}
+
+	runID := strconv.FormatInt(run.ID, 10)
+	return pv, db.WithTx(ctx, func(ctx context.Context) error {
+		pps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, packages_model.PropertyActionsRunID)
+		if err != nil {
+			return err
+		}
+		if len(pps) > 0 {
+			if pps[0].Value == runID {
+				return nil
+			}
+			return util.NewInvalidArgumentErrorf("version %s of %s package %s has been published by run %s", version, packageType, name, pps[0].Value)
+		}
+		for k, v := range map[string]string{
+			packages_model.PropertyActionsRepoID: strconv.FormatInt(run.RepoID, 10),
+			packages_model.PropertyActionsRunID:  runID,
+			packages_model.PropertyActionsRef:    run.Ref,
+		} {
+			if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindRunPackageVersions returns the package versions recorded as published by the run, the latest ones go first
+func FindRunPackageVersions(ctx context.Context, run *actions_model.ActionRun) ([]*packages_model.PackageVersion, error) {
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		OwnerID:    run.OwnerID,
+		Properties: map[string]string{packages_model.PropertyActionsRunID: strconv.FormatInt(run.ID, 10)},
+		IsInternal: optional.Some(false),
+		Sort:       packages_model.SortCreatedDesc,
+	})
+	return pvs, err
+}
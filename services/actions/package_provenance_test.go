@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordPackagePublished(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+
+	insertVersion := func(name string, repoID int64) {
+		p, err := packages_model.TryInsertPackage(db.DefaultContext, &packages_model.Package{
+			OwnerID:   run.OwnerID,
+			RepoID:    repoID,
+			Type:      packages_model.TypeGeneric,
+			Name:      name,
+			LowerName: name,
+		})
+		require.NoError(t, err)
+		_, err = packages_model.GetOrInsertVersion(db.DefaultContext, &packages_model.PackageVersion{
+			PackageID:    p.ID,
+			CreatorID:    run.OwnerID,
+			Version:      "1.0.0",
+			LowerVersion: "1.0.0",
+			MetadataJSON: "null",
+		})
+		require.NoError(t, err)
+	}
+	insertVersion("linked", run.RepoID)
+	insertVersion("unlinked", 0)
+
+	pv, err := RecordPackagePublished(db.DefaultContext, run, packages_model.TypeGeneric, "linked", "1.0.0")
+	require.NoError(t, err)
+	// recording it again for the same run is a no-op
+	_, err = RecordPackagePublished(db.DefaultContext, run, packages_model.TypeGeneric, "linked", "1.0.0")
+	require.NoError(t, err)
+	pps, err := packages_model.GetProperties(db.DefaultContext, packages_model.PropertyTypeVersion, pv.ID)
+	require.NoError(t, err)
+	assert.Len(t, pps, 3)
+
+	other := *run
+	other.ID = 792
+	_, err = RecordPackagePublished(db.DefaultContext, &other, packages_model.TypeGeneric, "linked", "1.0.0")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = RecordPackagePublished(db.DefaultContext, run, packages_model.TypeGeneric, "unlinked", "1.0.0")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = RecordPackagePublished(db.DefaultContext, run, packages_model.TypeGeneric, "linked", "2.0.0")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	pvs, err := FindRunPackageVersions(db.DefaultContext, run)
+	require.NoError(t, err)
+	if assert.Len(t, pvs, 1) {
+		assert.Equal(t, pv.ID, pvs[0].ID)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// preflightCheckPayloadSize checks the definitions of the jobs sent to the runners don't exceed the limit of the instance,
+// it's always enabled since the enormous generated workflows could exhaust the memory of the runners.
+const preflightCheckPayloadSize = "payload_size"
+
+// preflightPayloadSize checks the definition of every job, which is the workflow with only the job and its matrix expanded,
+// doesn't exceed setting.Actions.MaxJobPayloadSize
+func preflightPayloadSize(jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	limit := setting.Actions.MaxJobPayloadSize
+	if limit <= 0 {
+		return nil, nil
+	}
+	errs := map[string]string{}
+	for _, job := range jobs {
+		payload, err := job.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		if size := int64(len(payload)); size > limit {
+			id, _ := job.Job()
+			errs[id] = fmt.Sprintf("the definition of the job is %s, more than the limit %s of the instance", base.FileSize(size), base.FileSize(limit))
+		}
+	}
+	return errs, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// preflightCheckPolicies checks the jobs comply with the policies of the admins,
+// it's always enabled since it does nothing if there isn't any policy.
+const preflightCheckPolicies = "policies"
+
+// preflightPolicies checks the jobs comply with setting.ActionsPolicies, the jobs fail with the message of the first policy they violate.
+// The jobs also fail if a policy is invalid, so the admins could notice it.
+func preflightPolicies(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	if len(setting.ActionsPolicies) == 0 {
+		return nil, nil
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	gitCtx, _, err := newConcurrencyGithubContext(run)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, j := job.Job()
+		for _, policy := range setting.ActionsPolicies {
+			violated, err := actions_module.EvaluatePolicy(policy.Deny, gitCtx, j)
+			if err != nil {
+				errs[id] = fmt.Sprintf("the policy %q is invalid: %v", policy.Name, err)
+				break
+			}
+			if violated {
+				errs[id] = policy.Message
+				break
+			}
+		}
+	}
+	return errs, nil
+}
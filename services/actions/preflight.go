@@ -0,0 +1,335 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+var (
+	// expressionPattern matches the expressions, the secrets referenced outside them are plain text
+	expressionPattern = regexp.MustCompile(`(?s)\$\{\{(.*?)\}\}`)
+	// secretReferencePattern matches "secrets.NAME" and "secrets['NAME']" in an expression
+	secretReferencePattern = regexp.MustCompile(`\bsecrets(?:\.([A-Za-z_][A-Za-z0-9_]*)|\[\s*'([A-Za-z_][A-Za-z0-9_]*)'\s*\])`)
+)
+
+// builtinSecretNames are the secrets provided to every job, they don't need to be defined
+var builtinSecretNames = []string{"GITHUB_TOKEN", "GITEA_TOKEN"}
+
+// preflightCheckAllowedActions checks the actions used by the jobs are allowed by the repository,
+// it's always enabled since it's configured per repository.
+const preflightCheckAllowedActions = "allowed_actions"
+
+// preflightCheckPinnedActions checks the third-party actions used by the jobs are pinned to full commit SHAs,
+// it's always enabled since it's configured per instance and owner.
+const preflightCheckPinnedActions = "pinned_actions"
+
+// preflightCheckBlockedActions checks the actions used by the jobs aren't blocked by admins,
+// it's always enabled since the blocked refs are managed by admins.
+const preflightCheckBlockedActions = "blocked_actions"
+
+// preflightCheckRunnerOS checks the runs-on labels of the jobs don't imply conflicting operating systems,
+// it's always enabled since no runner could run such jobs.
+const preflightCheckRunnerOS = "runner_os"
+
+// preflightCheckLabelNamespaces checks the namespaced runs-on labels of the jobs belong to the owner of the repository,
+// it's always enabled since no runner could run such jobs.
+const preflightCheckLabelNamespaces = "label_namespaces"
+
+// preflight validates the jobs of a run with the checks enabled by setting.Actions.PreflightChecks,
+// it returns the errors of the jobs which don't pass the checks, keyed by job ids.
+func preflight(ctx context.Context, run *actions_model.ActionRun, content []byte, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	errs := map[string]string{}
+	for _, check := range append([]string{preflightCheckRunnerOS, preflightCheckLabelNamespaces, preflightCheckEnvLimits, preflightCheckPayloadSize, preflightCheckBlockedActions, preflightCheckAllowedActions, preflightCheckPinnedActions, preflightCheckPolicies, preflightCheckRunHook}, setting.Actions.PreflightChecks...) {
+		var checkErrs map[string]string
+		var err error
+		switch check {
+		case preflightCheckRunnerOS:
+			checkErrs = preflightRunnerOS(jobs)
+		case preflightCheckLabelNamespaces:
+			checkErrs, err = preflightLabelNamespaces(ctx, run, jobs)
+		case preflightCheckEnvLimits:
+			checkErrs, err = preflightEnvLimits(ctx, run, jobs)
+		case preflightCheckPayloadSize:
+			checkErrs, err = preflightPayloadSize(jobs)
+		case preflightCheckBlockedActions:
+			checkErrs, err = preflightBlockedActions(ctx, jobs)
+		case preflightCheckAllowedActions:
+			checkErrs, err = preflightAllowedActions(ctx, run, jobs)
+		case preflightCheckPinnedActions:
+			checkErrs, err = preflightPinnedActions(ctx, run, jobs)
+		case preflightCheckPolicies:
+			checkErrs, err = preflightPolicies(ctx, run, jobs)
+		case preflightCheckRunHook:
+			checkErrs, err = preflightRunHook(ctx, run, jobs)
+		case setting.PreflightCheckSecrets:
+			checkErrs, err = preflightSecrets(ctx, run, jobs)
+		case setting.PreflightCheckRunnerLabels:
+			checkErrs, err = preflightRunnerLabels(ctx, run, jobs)
+		case setting.PreflightCheckEnvironments:
+			checkErrs, err = preflightEnvironments(content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("preflight check %q: %w", check, err)
+		}
+		for id, msg := range checkErrs {
+			if _, ok := errs[id]; !ok {
+				errs[id] = msg
+			}
+		}
+	}
+	return errs, nil
+}
+
+// addPreflightError sets the error of a workflow-level failure as the preflight error of all jobs of the workflow,
+// it overrides the errors of the preflight checks since none of the jobs could run anyway
+func addPreflightError(errs map[string]string, jobs []*jobparser.SingleWorkflow, msg string) {
+	for _, job := range jobs {
+		id, _ := job.Job()
+		errs[id] = msg
+	}
+}
+
+// preflightRunnerOS checks the runs-on labels of the jobs don't imply conflicting operating systems
+func preflightRunnerOS(jobs []*jobparser.SingleWorkflow) map[string]string {
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, j := job.Job()
+		if _, err := actions_model.JobOS(j.RunsOn()); err != nil {
+			errs[id] = err.Error()
+		}
+	}
+	return errs
+}
+
+// preflightLabelNamespaces checks the namespaced runs-on labels of the jobs belong to the owner of the repository
+func preflightLabelNamespaces(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, j := job.Job()
+		// the expressions can't be evaluated here
+		runsOn := slices.DeleteFunc(j.RunsOn(), func(s string) bool { return strings.Contains(s, "${{") })
+		if err := actions_model.CheckLabelNamespaces(runsOn, run.Repo.OwnerName); err != nil {
+			errs[id] = err.Error()
+		}
+	}
+	return errs, nil
+}
+
+// preflightBlockedActions checks the actions used by the jobs and their steps aren't blocked by admins
+func preflightBlockedActions(ctx context.Context, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	blockedRefs, err := db.Find[actions_model.ActionBlockedRef](ctx, actions_model.FindBlockedRefsOptions{})
+	if err != nil || len(blockedRefs) == 0 {
+		return nil, err
+	}
+
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, j := job.Job()
+		uses := make([]string, 0, len(j.Steps)+1)
+		uses = append(uses, j.Uses)
+		for _, step := range j.Steps {
+			uses = append(uses, step.Uses)
+		}
+		var blocked []string
+		for _, u := range uses {
+			if !actions_module.IsRemoteAction(u) || slices.Contains(blocked, u) {
+				continue
+			}
+			name, ref := actions_module.SplitActionUses(u)
+			if slices.ContainsFunc(blockedRefs, func(b *actions_model.ActionBlockedRef) bool { return b.Matches(name, ref) }) {
+				blocked = append(blocked, u)
+			}
+		}
+		if len(blocked) > 0 {
+			errs[id] = fmt.Sprintf("actions %s are blocked by the administrators", strings.Join(blocked, ", "))
+		}
+	}
+	return errs, nil
+}
+
+// preflightAllowedActions checks the actions used by the steps of the jobs match the allowed actions of the repository
+func preflightAllowedActions(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	cfgUnit, err := run.Repo.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cfg := cfgUnit.ActionsConfig()
+	if len(cfg.AllowedActions) == 0 {
+		return nil, nil
+	}
+
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, j := job.Job()
+		var disallowed []string
+		for _, step := range j.Steps {
+			if step.Uses != "" && !cfg.IsActionAllowed(step.Uses) && !slices.Contains(disallowed, step.Uses) {
+				disallowed = append(disallowed, step.Uses)
+			}
+		}
+		if len(disallowed) > 0 {
+			errs[id] = fmt.Sprintf("actions %s are not allowed by the repository", strings.Join(disallowed, ", "))
+		}
+	}
+	return errs, nil
+}
+
+// preflightPinnedActions checks the third-party actions used by the steps of the jobs are pinned to full commit SHAs if it's required,
+// the actions of the owner of the repository aren't third-party ones.
+func preflightPinnedActions(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	required, err := actions_model.IsPinnedActionsRequired(ctx, run.Repo.OwnerID)
+	if err != nil || !required {
+		return nil, err
+	}
+
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, j := job.Job()
+		var unpinned []string
+		for _, step := range j.Steps {
+			if actions_module.IsActionPinned(step.Uses) || slices.Contains(unpinned, step.Uses) {
+				continue
+			}
+			if isActionOfOwner(step.Uses, run.Repo.OwnerName) {
+				continue
+			}
+			unpinned = append(unpinned, step.Uses)
+		}
+		if len(unpinned) > 0 {
+			errs[id] = fmt.Sprintf("actions %s must be pinned to full commit SHAs", strings.Join(unpinned, ", "))
+		}
+	}
+	return errs, nil
+}
+
+// isActionOfOwner returns whether the action of "uses" is hosted on this instance and belongs to the owner
+func isActionOfOwner(uses, ownerName string) bool {
+	if !strings.Contains(uses, "://") && setting.Actions.DefaultActionsURL.URL() != strings.TrimSuffix(setting.AppURL, "/") {
+		// the action will be fetched from another site
+		return false
+	}
+	return strings.EqualFold(actions_module.ActionOwner(uses, setting.AppURL), ownerName)
+}
+
+// preflightSecrets checks the secrets referenced by the jobs are defined in the repository or its owner
+func preflightSecrets(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	if !canAccessSecrets(run) {
+		return nil, nil
+	}
+
+	defined, err := definedSecretNames(ctx, run.OwnerID, run.RepoID)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, _ := job.Job()
+		payload, err := job.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		var missing []string
+		for _, name := range referencedSecretNames(payload) {
+			if !defined.Contains(name) && !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			errs[id] = fmt.Sprintf("secrets %s are not defined in the repository or its owner", strings.Join(missing, ", "))
+		}
+	}
+	return errs, nil
+}
+
+// referencedSecretNames returns the upper-cased names of the secrets referenced by the expressions in the payload,
+// except the builtin ones
+func referencedSecretNames(payload []byte) []string {
+	var names []string
+	for _, expr := range expressionPattern.FindAllSubmatch(payload, -1) {
+		for _, match := range secretReferencePattern.FindAllSubmatch(expr[1], -1) {
+			name := string(match[1])
+			if name == "" {
+				name = string(match[2])
+			}
+			name = strings.ToUpper(name)
+			if !slices.Contains(builtinSecretNames, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// preflightEnvironments checks the environments referenced by the jobs exist,
+// Gitea Actions doesn't support deployment environments, so no job referencing one could get its secrets and protection rules.
+func preflightEnvironments(content []byte) (map[string]string, error) {
+	envs, err := actions_module.ParseJobEnvironments(content)
+	if err != nil {
+		return nil, err
+	}
+	errs := make(map[string]string, len(envs))
+	for id, name := range envs {
+		errs[id] = fmt.Sprintf("environment %q doesn't exist, deployment environments are not supported", name)
+	}
+	return errs, nil
+}
+
+// preflightRunnerLabels checks there are runners, online or not, which could run the jobs with the required labels
+func preflightRunnerLabels(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
+		RepoID:        run.RepoID,
+		WithAvailable: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, j := job.Job()
+		runsOn := j.RunsOn()
+		if slices.ContainsFunc(runsOn, func(s string) bool { return strings.Contains(s, "${{") }) {
+			// the expressions can't be evaluated here
+			continue
+		}
+		if !slices.ContainsFunc(runners, func(runner *actions_model.ActionRunner) bool {
+			for _, label := range runsOn {
+				if !slices.Contains(runner.AgentLabels, label) {
+					return false
+				}
+			}
+			return actions_model.CheckRunnerOS(runner.AgentLabels, runsOn) == nil
+		}) {
+			errs[id] = fmt.Sprintf("no runner is available with labels %s", strings.Join(runsOn, ", "))
+		}
+	}
+	return errs, nil
+}
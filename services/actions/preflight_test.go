@@ -0,0 +1,197 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightRunnerOS(t *testing.T) {
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  conflicting:
+    runs-on: [windows-latest, macos-latest]
+    steps:
+      - run: echo hello
+  windows:
+    runs-on: [self-hosted, windows]
+    steps:
+      - run: echo hello
+`))
+	require.NoError(t, err)
+
+	errs := preflightRunnerOS(jobs)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs["conflicting"], "conflicting operating systems")
+}
+
+func TestAddPreflightError(t *testing.T) {
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`))
+	require.NoError(t, err)
+
+	errs := map[string]string{"build": "the runner labels conflict"}
+	addPreflightError(errs, jobs, "invalid concurrency of the workflow")
+	assert.Equal(t, map[string]string{
+		"build": "invalid concurrency of the workflow",
+		"test":  "invalid concurrency of the workflow",
+	}, errs)
+}
+
+func TestPreflightLabelNamespaces(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  own:
+    runs-on: [linux, user5/gpu]
+    steps:
+      - run: echo hello
+  other:
+    runs-on: [linux, user2/gpu]
+    steps:
+      - run: echo hello
+  expression:
+    runs-on: ${{ github.repository_owner }}/gpu
+    steps:
+      - run: echo hello
+`))
+	require.NoError(t, err)
+
+	// repo 4 is owned by user5
+	errs, err := preflightLabelNamespaces(db.DefaultContext, &actions_model.ActionRun{RepoID: 4}, jobs)
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs["other"], `label "user2/gpu" belongs to "user2"`)
+}
+
+func TestReferencedSecretNames(t *testing.T) {
+	payload := []byte(`
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    env:
+      TOKEN: ${{ secrets.deploy_token }}
+      BOTH: ${{ secrets.A || secrets['b'] }}
+    steps:
+      - run: echo "read secrets.NOT_AN_EXPRESSION from the docs"
+      - uses: actions/checkout@v4
+        with:
+          token: ${{ secrets.GITHUB_TOKEN }}
+      - run: curl -H "Authorization: ${{ secrets.GITEA_TOKEN }}" ${{
+          secrets.MULTI_LINE }}
+`)
+	assert.Equal(t, []string{"DEPLOY_TOKEN", "A", "B", "MULTI_LINE"}, referencedSecretNames(payload))
+}
+
+func TestPreflightEnvironments(t *testing.T) {
+	errs, err := preflightEnvironments([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make
+  deploy:
+    runs-on: ubuntu-latest
+    environment: production
+    steps:
+      - run: make deploy
+`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs["deploy"], `environment "production" doesn't exist`)
+}
+
+func TestCheckEnvLimits(t *testing.T) {
+	secrets := map[string]string{"A": "1234", "B": "5678"}
+	vars := map[string]string{"C": "1", "D": "2", "E": "3"}
+
+	defer test.MockVariableValue(&setting.Actions.MaxJobSecrets, 2)()
+	defer test.MockVariableValue(&setting.Actions.MaxJobSecretsSize, 10)()
+	defer test.MockVariableValue(&setting.Actions.MaxJobVariables, 3)()
+	defer test.MockVariableValue(&setting.Actions.MaxJobVariablesSize, 0)()
+	assert.Empty(t, checkEnvLimits(secrets, vars))
+
+	setting.Actions.MaxJobSecrets = 1
+	assert.Equal(t, "the job would get 2 secrets, more than the limit 1 of the instance", checkEnvLimits(secrets, vars))
+
+	setting.Actions.MaxJobSecrets = 0
+	setting.Actions.MaxJobSecretsSize = 9
+	assert.Equal(t, "the secrets of the job would be 10 B, more than the limit 9 B of the instance", checkEnvLimits(secrets, vars))
+
+	setting.Actions.MaxJobVariables = 2
+	assert.Equal(t, "the job would get 3 variables, more than the limit 2 of the instance", checkEnvLimits(nil, vars))
+}
+
+func TestPreflightPolicies(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.ActionsPolicies, []*setting.ActionsPolicy{
+		{Name: "require-timeout", Deny: "!job['timeout-minutes']", Message: "the jobs must set timeout-minutes"},
+		{Name: "no-privileged", Deny: "contains(job.container.options, '--privileged')", Message: "the jobs can't be privileged"},
+	})()
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  compliant:
+    runs-on: ubuntu-latest
+    timeout-minutes: 10
+    steps:
+      - run: echo hello
+  no-timeout:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+  privileged:
+    runs-on: ubuntu-latest
+    timeout-minutes: 10
+    container:
+      image: node:18
+      options: --privileged
+    steps:
+      - run: echo hello
+`))
+	require.NoError(t, err)
+
+	run := &actions_model.ActionRun{RepoID: 4, TriggerUserID: 5, Event: "push", Ref: "refs/heads/master"}
+	errs, err := preflightPolicies(db.DefaultContext, run, jobs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"no-timeout": "the jobs must set timeout-minutes",
+		"privileged": "the jobs can't be privileged",
+	}, errs)
+
+	setting.ActionsPolicies = []*setting.ActionsPolicy{{Name: "invalid", Deny: "job.name =="}}
+	errs, err = preflightPolicies(db.DefaultContext, run, jobs)
+	require.NoError(t, err)
+	assert.Len(t, errs, 3)
+	assert.Contains(t, errs["compliant"], `the policy "invalid" is invalid`)
+}
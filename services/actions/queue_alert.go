@@ -0,0 +1,166 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// The metrics of the queue of the waiting jobs which could be alerted
+const (
+	QueueAlertMetricWaitTime = "wait_time" // the seconds the longest waiting job has waited
+	QueueAlertMetricDepth    = "depth"     // the number of the waiting jobs
+)
+
+// QueueAlert is posted to the webhook when a threshold is exceeded, and again when it's resolved
+type QueueAlert struct {
+	Label     string `json:"label"`
+	Metric    string `json:"metric"`
+	Threshold int64  `json:"threshold"`
+	Value     int64  `json:"value"`
+	Firing    bool   `json:"firing"`
+}
+
+func (alert *QueueAlert) key() string {
+	return alert.Metric + "\x00" + alert.Label
+}
+
+func (alert *QueueAlert) String() string {
+	label := fmt.Sprintf("label %q", alert.Label)
+	if alert.Label == setting.QueueAlertsAllLabels {
+		label = "any label"
+	}
+	var msg string
+	if alert.Metric == QueueAlertMetricWaitTime {
+		msg = fmt.Sprintf("the jobs with %s have waited for runners for %s, the threshold is %s",
+			label, time.Duration(alert.Value)*time.Second, time.Duration(alert.Threshold)*time.Second)
+	} else {
+		msg = fmt.Sprintf("%d jobs with %s are waiting for runners, the threshold is %d", alert.Value, label, alert.Threshold)
+	}
+	if alert.Firing {
+		return "Actions queue alert: " + msg
+	}
+	return "Actions queue alert resolved: " + msg
+}
+
+var (
+	firingQueueAlerts   = map[string]bool{}
+	firingQueueAlertsMu sync.Mutex
+)
+
+// CheckQueueAlerts compares the waiting jobs with the thresholds of [actions.alerts],
+// the alerts are sent as system notices and to the webhook when they start firing and when they are resolved.
+func CheckQueueAlerts(ctx context.Context) error {
+	if len(setting.ActionsAlerts.QueueWaitThresholds) == 0 && len(setting.ActionsAlerts.QueueDepthThresholds) == 0 {
+		return nil
+	}
+	jobs, err := actions_model.GetQueuedRunJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("GetQueuedRunJobs: %w", err)
+	}
+
+	firingQueueAlertsMu.Lock()
+	defer firingQueueAlertsMu.Unlock()
+
+	for _, alert := range evaluateQueueAlerts(jobs, time.Now(), firingQueueAlerts) {
+		log.Warn("%s", alert)
+		if err := system_model.CreateNotice(ctx, system_model.NoticeTask, alert.String()); err != nil {
+			log.Error("CreateNotice: %v", err)
+		}
+		if setting.ActionsAlerts.WebhookURL != "" {
+			if err := postQueueAlert(ctx, alert); err != nil {
+				log.Error("Post the actions queue alert to %s: %v", setting.ActionsAlerts.WebhookURL, err)
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateQueueAlerts returns the alerts which start firing or are resolved, and updates the firing alerts
+func evaluateQueueAlerts(jobs []*actions_model.ActionRunJob, now time.Time, firing map[string]bool) []*QueueAlert {
+	var measured []*QueueAlert
+	for label, threshold := range setting.ActionsAlerts.QueueWaitThresholds {
+		alert := &QueueAlert{Label: label, Metric: QueueAlertMetricWaitTime, Threshold: int64(threshold.Seconds())}
+		for _, job := range jobs {
+			if !queueAlertMatches(label, job) {
+				continue
+			}
+			queued := job.Queued
+			if queued == 0 {
+				queued = job.Created
+			}
+			alert.Value = max(alert.Value, int64(now.Sub(queued.AsTime()).Seconds()))
+		}
+		measured = append(measured, alert)
+	}
+	for label, threshold := range setting.ActionsAlerts.QueueDepthThresholds {
+		alert := &QueueAlert{Label: label, Metric: QueueAlertMetricDepth, Threshold: threshold}
+		for _, job := range jobs {
+			if queueAlertMatches(label, job) {
+				alert.Value++
+			}
+		}
+		measured = append(measured, alert)
+	}
+
+	var changed []*QueueAlert
+	for _, alert := range measured {
+		alert.Firing = alert.Value > alert.Threshold
+		if alert.Firing != firing[alert.key()] {
+			changed = append(changed, alert)
+		}
+		if alert.Firing {
+			firing[alert.key()] = true
+		} else {
+			delete(firing, alert.key())
+		}
+	}
+	slices.SortFunc(changed, func(a, b *QueueAlert) int {
+		return cmp.Compare(a.key(), b.key())
+	})
+	return changed
+}
+
+func queueAlertMatches(label string, job *actions_model.ActionRunJob) bool {
+	return label == setting.QueueAlertsAllLabels || slices.Contains(job.RunsOn, label)
+}
+
+func postQueueAlert(ctx context.Context, alert *QueueAlert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, setting.ActionsAlerts.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{
+		Timeout:   time.Duration(setting.Webhook.DeliverTimeout) * time.Second,
+		Transport: &http.Transport{Proxy: proxy.Proxy()},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateQueueAlerts(t *testing.T) {
+	defer test.MockVariableValue(&setting.ActionsAlerts.QueueWaitThresholds, map[string]time.Duration{"gpu": 15 * time.Minute})()
+	defer test.MockVariableValue(&setting.ActionsAlerts.QueueDepthThresholds, map[string]int64{"*": 2})()
+
+	now := time.Unix(1700000000, 0)
+	jobs := []*actions_model.ActionRunJob{
+		{RunsOn: []string{"gpu"}, Queued: timeutil.TimeStamp(now.Add(-20 * time.Minute).Unix())},
+		{RunsOn: []string{"ubuntu-latest"}, Queued: timeutil.TimeStamp(now.Add(-time.Hour).Unix())},
+		// the jobs queued before the queue times were recorded fall back to their creation times
+		{RunsOn: []string{"gpu"}, Created: timeutil.TimeStamp(now.Add(-10 * time.Minute).Unix())},
+	}
+	firing := map[string]bool{}
+
+	alerts := evaluateQueueAlerts(jobs, now, firing)
+	assert.Equal(t, []*QueueAlert{
+		{Label: "*", Metric: QueueAlertMetricDepth, Threshold: 2, Value: 3, Firing: true},
+		{Label: "gpu", Metric: QueueAlertMetricWaitTime, Threshold: 900, Value: 1200, Firing: true},
+	}, alerts)
+
+	// the alerts which keep firing aren't sent again
+	assert.Empty(t, evaluateQueueAlerts(jobs, now, firing))
+
+	alerts = evaluateQueueAlerts(jobs[1:], now, firing)
+	assert.Equal(t, []*QueueAlert{
+		{Label: "*", Metric: QueueAlertMetricDepth, Threshold: 2, Value: 2, Firing: false},
+		{Label: "gpu", Metric: QueueAlertMetricWaitTime, Threshold: 900, Value: 600, Firing: false},
+	}, alerts)
+	assert.Equal(t, "Actions queue alert resolved: 2 jobs with any label are waiting for runners, the threshold is 2", alerts[0].String())
+	assert.Equal(t, `Actions queue alert resolved: the jobs with label "gpu" have waited for runners for 10m0s, the threshold is 15m0s`, alerts[1].String())
+	assert.Empty(t, firing)
+}
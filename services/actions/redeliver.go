@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// RedeliverRunEvent delivers the event which triggered the run again, the workflows matching the event are triggered as new runs.
+// It's useful when the workflow files were broken when the event happened, since the workflows are detected again
+// on the current commit of the ref, with the stored payload of the event and as the user who triggered the run.
+func RedeliverRunEvent(ctx context.Context, run *actions_model.ActionRun) error {
+	input, err := newNotifyInputOfRun(ctx, run)
+	if err != nil {
+		return err
+	}
+	return pushNotifyInput(withMethod(ctx, "RedeliverRunEvent"), input.WithRedeliver())
+}
+
+// newNotifyInputOfRun restores the input of the event which triggered the run
+func newNotifyInputOfRun(ctx context.Context, run *actions_model.ActionRun) (*notifyInput, error) {
+	if !run.CanRedeliverEvent() {
+		return nil, util.NewInvalidArgumentErrorf("the event of run %d can't be redelivered", run.Index)
+	}
+	payload := newPayloadOfEvent(run.Event)
+	if payload == nil {
+		return nil, util.NewInvalidArgumentErrorf("unsupported payload of event %q", run.Event)
+	}
+	content, err := run.GetEventPayload()
+	if err != nil {
+		return nil, fmt.Errorf("GetEventPayload: %w", err)
+	}
+	if err := json.Unmarshal([]byte(content), payload); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	doer, err := user_model.GetPossibleUserByID(ctx, run.TriggerUserID)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			return nil, fmt.Errorf("GetPossibleUserByID: %w", err)
+		}
+		// the doer has been deleted after the event
+		doer = user_model.NewGhostUser()
+	}
+	input := newNotifyInput(run.Repo, doer, run.Event).WithRef(run.Ref).WithPayload(payload)
+
+	pr, err := pullRequestOfPayload(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("get pull request of run %d: %w", run.ID, err)
+	}
+	if pr != nil {
+		input.WithPullRequest(pr)
+	}
+	return input, nil
+}
+
+// pullRequestOfPayload returns the pull request with its issue which the payload is about, or nil if it isn't about a pull request
+func pullRequestOfPayload(ctx context.Context, payload api.Payloader) (*issues_model.PullRequest, error) {
+	var pr *issues_model.PullRequest
+	var err error
+	switch p := payload.(type) {
+	case *api.PullRequestPayload:
+		if p.PullRequest != nil {
+			pr, err = issues_model.GetPullRequestByID(ctx, p.PullRequest.ID)
+		}
+	case *api.IssueCommentPayload:
+		if p.IsPull && p.Issue != nil {
+			pr, err = issues_model.GetPullRequestByIssueID(ctx, p.Issue.ID)
+		}
+	}
+	if err != nil || pr == nil {
+		return nil, err
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, fmt.Errorf("LoadIssue: %w", err)
+	}
+	return pr, nil
+}
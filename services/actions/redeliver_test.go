@@ -0,0 +1,65 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotifyInputOfRun(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	run := &actions_model.ActionRun{
+		RepoID:              1,
+		TriggerUserID:       2,
+		Ref:                 "refs/heads/master",
+		Event:               webhook_module.HookEventPush,
+		EventPayload:        `{"ref":"refs/heads/master","after":"65f1bf27bc3bf70f64657658635e66094edbcb4d"}`,
+		EventPayloadVersion: actions_model.EventPayloadVersion,
+	}
+	input, err := newNotifyInputOfRun(ctx, run)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, input.Repo.ID)
+	assert.EqualValues(t, 2, input.Doer.ID)
+	assert.Equal(t, "refs/heads/master", input.Ref)
+	assert.Empty(t, input.CommitID)
+	if assert.IsType(t, &api.PushPayload{}, input.Payload) {
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", input.Payload.(*api.PushPayload).After)
+	}
+	assert.Nil(t, input.PullRequest)
+
+	// the pull request of the event is restored
+	run = &actions_model.ActionRun{
+		RepoID:        1,
+		TriggerUserID: 2,
+		Ref:           "refs/pull/2/head",
+		Event:         webhook_module.HookEventPullRequest,
+		EventPayload:  `{"action":"opened","pull_request":{"id":1}}`,
+	}
+	input, err = newNotifyInputOfRun(ctx, run)
+	require.NoError(t, err)
+	if assert.NotNil(t, input.PullRequest) {
+		assert.EqualValues(t, 1, input.PullRequest.ID)
+		assert.NotNil(t, input.PullRequest.Issue)
+	}
+
+	// the runs of schedules and external CI systems aren't triggered by their stored events
+	run = &actions_model.ActionRun{RepoID: 1, TriggerUserID: 2, Event: webhook_module.HookEventPush, EventPayload: `{}`, ScheduleID: 1}
+	_, err = newNotifyInputOfRun(ctx, run)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	run = &actions_model.ActionRun{RepoID: 1, TriggerUserID: 2, ExternalSystem: "jenkins"}
+	_, err = newNotifyInputOfRun(ctx, run)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
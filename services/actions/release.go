@@ -0,0 +1,173 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	attachment_service "code.gitea.io/gitea/services/attachment"
+	release_service "code.gitea.io/gitea/services/release"
+)
+
+// createReleaseOfRun creates the release of the tag of the run by the release automation of the repository if the run has succeeded.
+// The tag-only release of the pushed tag becomes the release, while a release created by someone else is kept as it is.
+// It could be called again for the same run, the artifacts which have been attached are skipped.
+func createReleaseOfRun(ctx context.Context, run *actions_model.ActionRun) error {
+	ref := git.RefName(run.Ref)
+	if run.Status != actions_model.StatusSuccess || !ref.IsTag() {
+		return nil
+	}
+	if err := run.LoadRepo(ctx); err != nil {
+		return err
+	}
+	cfgUnit, err := run.Repo.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	automation := cfgUnit.ActionsConfig().ReleaseAutomation
+	tagName := ref.TagName()
+	if automation == nil || automation.WorkflowID != run.WorkflowID || !automation.MatchTag(tagName) {
+		return nil
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, run.Repo)
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	if commitID, err := gitRepo.GetTagCommitID(tagName); err != nil {
+		if git.IsErrNotExist(err) {
+			log.Warn("Skip the release of run %d, tag %s has been deleted", run.ID, tagName)
+			return nil
+		}
+		return err
+	} else if commitID != run.CommitSHA {
+		log.Warn("Skip the release of run %d, tag %s has been moved to %s", run.ID, tagName, commitID)
+		return nil
+	}
+
+	doer := user_model.NewActionsUser()
+	rel, err := repo_model.GetRelease(ctx, run.RepoID, tagName)
+	if err != nil && !repo_model.IsErrReleaseNotExist(err) {
+		return err
+	}
+	if rel == nil || rel.IsTag {
+		note := ""
+		if automation.GenerateNotes {
+			notes, err := release_service.GenerateReleaseNotes(ctx, run.Repo, gitRepo, release_service.GenerateReleaseNotesOptions{TagName: tagName})
+			if errors.Is(err, util.ErrInvalidArgument) {
+				// the configuration of the notes is broken, the release is created without notes instead of being retried
+				log.Warn("Failed to generate the notes of release %s of run %d: %v", tagName, run.ID, err)
+			} else if err != nil {
+				return fmt.Errorf("GenerateReleaseNotes: %w", err)
+			} else {
+				note = notes.Body
+			}
+		}
+		if rel == nil {
+			rel = &repo_model.Release{
+				RepoID:  run.RepoID,
+				TagName: tagName,
+				Target:  run.CommitSHA,
+			}
+		}
+		rel.Repo = run.Repo
+		rel.PublisherID = doer.ID
+		rel.Publisher = doer
+		rel.Title = tagName
+		rel.Note = note
+		rel.IsDraft = automation.Draft
+		rel.IsTag = false
+		if rel.ID == 0 {
+			err = release_service.CreateRelease(gitRepo, rel, nil, "")
+		} else {
+			err = release_service.UpdateRelease(ctx, doer, gitRepo, rel, nil, nil, nil)
+		}
+		if err != nil {
+			return err
+		}
+	} else if rel.PublisherID != doer.ID {
+		log.Debug("Skip the release of run %d, release %s has been created by someone else", run.ID, tagName)
+		return nil
+	}
+
+	return attachArtifactsToRelease(ctx, run, rel, automation)
+}
+
+// attachArtifactsToRelease attaches the artifacts of the run matching the automation to the release as zip files,
+// the ones uploaded by the v1-v3 backend aren't supported since they're stored as individual files
+func attachArtifactsToRelease(ctx context.Context, run *actions_model.ActionRun, rel *repo_model.Release, automation *repo_model.ActionsReleaseAutomation) error {
+	if len(automation.Artifacts) == 0 {
+		return nil
+	}
+	if err := repo_model.GetReleaseAttachments(ctx, rel); err != nil {
+		return err
+	}
+	attached := make(map[string]bool, len(rel.Attachments))
+	for _, attachment := range rel.Attachments {
+		attached[attachment.Name] = true
+	}
+
+	metas, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID)
+	if err != nil {
+		return err
+	}
+	for _, meta := range metas {
+		name := meta.ArtifactName + ".zip"
+		if attached[name] || !automation.MatchArtifact(meta.ArtifactName) {
+			continue
+		}
+		artifacts, err := actions_model.GetUploadedArtifactFiles(ctx, run.ID, meta.ArtifactName)
+		if errors.Is(err, util.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if len(artifacts) != 1 || !artifacts[0].IsSingleZip() {
+			log.Warn("Skip artifact %s of run %d for release %s, only the artifacts uploaded by actions/upload-artifact@v4 could be attached", meta.ArtifactName, run.ID, rel.TagName)
+			continue
+		}
+		art := artifacts[0]
+		if art.FileCompressedSize > setting.Attachment.MaxSize<<20 {
+			log.Warn("Skip artifact %s of run %d for release %s, it's larger than %d MB", meta.ArtifactName, run.ID, rel.TagName, setting.Attachment.MaxSize)
+			continue
+		}
+		if err := attachArtifact(ctx, rel, art, name); err != nil {
+			return fmt.Errorf("attach artifact %s: %w", meta.ArtifactName, err)
+		}
+	}
+	return nil
+}
+
+func attachArtifact(ctx context.Context, rel *repo_model.Release, art *actions_model.ActionArtifact, name string) error {
+	f, err := storage.ActionsArtifacts.Open(art.StoragePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = attachment_service.NewAttachment(ctx, &repo_model.Attachment{
+		RepoID:     rel.RepoID,
+		ReleaseID:  rel.ID,
+		UploaderID: user_model.ActionsUserID,
+		Name:       name,
+	}, f, art.FileCompressedSize)
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateReleaseOfRun(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	_, err := db.GetEngine(db.DefaultContext).Where("repo_id = ? AND type = ?", repo.ID, unit_model.TypeActions).Update(&repo_model.RepoUnit{
+		Config: &repo_model.ActionsConfig{ReleaseAutomation: &repo_model.ActionsReleaseAutomation{
+			WorkflowID:    "release.yaml",
+			TagPattern:    "v*",
+			GenerateNotes: true,
+		}},
+	})
+	require.NoError(t, err)
+
+	run := &actions_model.ActionRun{
+		ID:         10001,
+		RepoID:     repo.ID,
+		WorkflowID: "release.yaml",
+		Ref:        "refs/tags/v1.1",
+		CommitSHA:  "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		Status:     actions_model.StatusSuccess,
+	}
+
+	// the release created by someone else is kept
+	require.NoError(t, createReleaseOfRun(db.DefaultContext, run))
+	rel := unittest.AssertExistsAndLoadBean(t, &repo_model.Release{RepoID: repo.ID, TagName: "v1.1"})
+	assert.EqualValues(t, 2, rel.PublisherID)
+	assert.Equal(t, "testing-release", rel.Title)
+
+	// the tag-only release becomes the release
+	_, err = db.GetEngine(db.DefaultContext).ID(rel.ID).Cols("is_tag").Update(&repo_model.Release{IsTag: true})
+	require.NoError(t, err)
+
+	run.Status = actions_model.StatusFailure
+	require.NoError(t, createReleaseOfRun(db.DefaultContext, run))
+	assert.True(t, unittest.AssertExistsAndLoadBean(t, &repo_model.Release{ID: rel.ID}).IsTag)
+
+	run.Status = actions_model.StatusSuccess
+	run.WorkflowID = "test.yaml"
+	require.NoError(t, createReleaseOfRun(db.DefaultContext, run))
+	assert.True(t, unittest.AssertExistsAndLoadBean(t, &repo_model.Release{ID: rel.ID}).IsTag)
+
+	run.WorkflowID = "release.yaml"
+	require.NoError(t, createReleaseOfRun(db.DefaultContext, run))
+	rel = unittest.AssertExistsAndLoadBean(t, &repo_model.Release{ID: rel.ID})
+	assert.False(t, rel.IsTag)
+	assert.EqualValues(t, user_model.ActionsUserID, rel.PublisherID)
+	assert.Equal(t, "v1.1", rel.Title)
+	assert.Contains(t, rel.Note, "## What's Changed")
+}
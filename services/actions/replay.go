@@ -0,0 +1,133 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// ReplayedRun is the result of replaying the event of a run
+type ReplayedRun struct {
+	Run *actions_model.ActionRun
+	// whether the workflow of the run is still triggered by the event, all the jobs of the run are removed if it isn't
+	Triggered bool
+	Error     string // the workflow is triggered but its jobs couldn't be parsed, or the event couldn't be replayed
+	Jobs      []*actions_model.ActionRunJob
+	Diffs     []*actions_model.RunJobDiff
+}
+
+// Changed returns whether the replayed event would create different jobs than the run has
+func (r *ReplayedRun) Changed() bool {
+	return r.Error != "" || len(r.Diffs) > 0
+}
+
+// ReplayRunEvent replays the stored payload of the event of the run through the current detection and parsing of the workflows,
+// on the commit of the run, and compares the jobs it would create with the jobs of the run.
+// Nothing is created or dispatched to the runners, so it's safe to verify the changes of the parsers against the real events.
+// The jobs are parsed with the current variables and runs-on overrides, so the changes of them are reported as well.
+func ReplayRunEvent(ctx context.Context, run *actions_model.ActionRun) (*ReplayedRun, error) {
+	input, err := newNotifyInputOfRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	repo := input.Repo
+	if repo.IsEmpty {
+		return nil, util.NewInvalidArgumentErrorf("repository %s is empty", repo.FullName())
+	}
+	if err := repo.LoadUnits(ctx); err != nil {
+		return nil, err
+	}
+	if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return nil, util.NewInvalidArgumentErrorf("actions of repository %s are disabled", repo.FullName())
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(run.CommitSHA)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, util.NewNotExistErrorf("commit %s of run %d doesn't exist", run.CommitSHA, run.Index)
+		}
+		return nil, fmt.Errorf("GetCommit: %w", err)
+	}
+	input.WithCommitID(run.CommitSHA)
+
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return nil, fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+
+	result := &ReplayedRun{
+		Run:  run,
+		Jobs: []*actions_model.ActionRunJob{},
+	}
+	// the pull_request_target workflows are detected on the current head of the base branch, like when the event happened
+	workflows, _, _, err := detectTriggeredWorkflows(ctx, gitRepo, commit, input, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, wf := range workflows {
+		if wf.EntryName != run.WorkflowID || wf.TriggerEvent.Name != run.TriggerEvent {
+			continue
+		}
+		result.Triggered = true
+
+		vars, err := actions_model.GetVariablesOfRun(ctx, run)
+		if err != nil {
+			return nil, fmt.Errorf("GetVariablesOfRun: %w", err)
+		}
+		parsed, err := jobparser.Parse(wf.Content, jobparser.WithVars(vars))
+		if err != nil {
+			result.Error = err.Error()
+			return result, nil
+		}
+		if result.Jobs, err = actions_model.PlanRunJobs(ctx, run, parsed); err != nil {
+			return nil, fmt.Errorf("PlanRunJobs: %w", err)
+		}
+		break
+	}
+	result.Diffs = actions_model.DiffRunJobs(jobs, result.Jobs)
+	return result, nil
+}
+
+// ReplayRunEvents replays the events of the latest runs of the repository, see ReplayRunEvent.
+// The runs whose events couldn't be replayed, like the scheduled runs or the ones whose commits have gone, are reported with the errors.
+func ReplayRunEvents(ctx context.Context, repo *repo_model.Repository, limit int) ([]*ReplayedRun, error) {
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		ListOptions: db.ListOptions{PageSize: limit},
+		RepoID:      repo.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FindRuns: %w", err)
+	}
+	results := make([]*ReplayedRun, 0, len(runs))
+	for _, run := range runs {
+		run.Repo = repo
+		result, err := ReplayRunEvent(ctx, run)
+		if err != nil {
+			if !errors.Is(err, util.ErrInvalidArgument) && !errors.Is(err, util.ErrNotExist) {
+				return nil, fmt.Errorf("replay run %d: %w", run.ID, err)
+			}
+			result = &ReplayedRun{Run: run, Error: err.Error()}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	secret_model "code.gitea.io/gitea/models/secret"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/container"
+)
+
+const (
+	requiredSecretPrefix = "secrets."
+	requiredVarPrefix    = "vars."
+)
+
+// definedSecretNames returns the names of the secrets which the runs of the repository could access
+func definedSecretNames(ctx context.Context, ownerID, repoID int64) (container.Set[string], error) {
+	ownerSecrets, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{OwnerID: ownerID})
+	if err != nil {
+		return nil, err
+	}
+	repoSecrets, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{RepoID: repoID})
+	if err != nil {
+		return nil, err
+	}
+	names := container.SetOf("GITHUB_TOKEN", "GITEA_TOKEN")
+	for _, secret := range append(ownerSecrets, repoSecrets...) {
+		names.Add(secret.Name)
+	}
+	return names, nil
+}
+
+// canAccessSecrets returns whether the jobs of the run will get the secrets, see secret_model.GetSecretsOfTask
+func canAccessSecrets(run *actions_model.ActionRun) bool {
+	return !run.IsForkPullRequest || run.TriggerEvent == actions_module.GithubEventPullRequestTarget
+}
+
+// missingRequirements returns the secrets and variables required by the workflow but not defined for the run,
+// they are only names like "secrets.NAME" and "vars.NAME", never values.
+func missingRequirements(ctx context.Context, run *actions_model.ActionRun, requirements *actions_module.WorkflowRequirements, vars map[string]string) ([]string, error) {
+	if requirements.IsEmpty() {
+		return nil, nil
+	}
+
+	var missing []string
+	// the required secrets of fork pull requests are never checked, since they never get the secrets by design
+	if len(requirements.Secrets) > 0 && canAccessSecrets(run) {
+		secrets, err := definedSecretNames(ctx, run.OwnerID, run.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range requirements.Secrets {
+			if !secrets.Contains(name) {
+				missing = append(missing, requiredSecretPrefix+name)
+			}
+		}
+	}
+	for _, name := range requirements.Vars {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, requiredVarPrefix+name)
+		}
+	}
+	return missing, nil
+}
+
+// CheckMissingRequirements checks the missing requirements of the run again, and returns the ones still missing
+func CheckMissingRequirements(ctx context.Context, run *actions_model.ActionRun) ([]string, error) {
+	if len(run.MissingRequirements) == 0 {
+		return nil, nil
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	vars, err := actions_model.GetVariablesOfRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := &actions_module.WorkflowRequirements{}
+	for _, v := range run.MissingRequirements {
+		if name, ok := strings.CutPrefix(v, requiredSecretPrefix); ok {
+			requirements.Secrets = append(requirements.Secrets, name)
+		} else if name, ok := strings.CutPrefix(v, requiredVarPrefix); ok {
+			requirements.Vars = append(requirements.Vars, name)
+		}
+	}
+	return missingRequirements(ctx, run, requirements, vars)
+}
+
+// checkRequirements marks the run to need an approval if the requirements declared by the workflow are missing
+func checkRequirements(ctx context.Context, run *actions_model.ActionRun, content []byte, vars map[string]string) error {
+	requirements, err := actions_module.ParseWorkflowRequirements(content)
+	if err != nil {
+		return err
+	}
+	missing, err := missingRequirements(ctx, run, requirements, vars)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		run.NeedApproval = true
+		run.MissingRequirements = missing
+	}
+	return nil
+}
@@ -4,8 +4,16 @@
 package actions
 
 import (
+	"context"
+
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	secret_service "code.gitea.io/gitea/services/secrets"
+
+	"xorm.io/builder"
 )
 
 // GetAllRerunJobs get all jobs that need to be rerun when job should be rerun
@@ -36,3 +44,124 @@ func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.A
 
 	return rerunJobs
 }
+
+// RerunOptions are the options of re-running jobs
+type RerunOptions struct {
+	Pinning   actions_model.RunnerPinning
+	Overrides *actions_model.RunOverrides
+	// AvoidSpotRunners makes the specified job avoid the spot runners, since it was lost with one
+	AvoidSpotRunners bool
+}
+
+// RerunJobs reruns the job and the jobs depending on it, or all jobs of the run if job is nil
+func RerunJobs(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob, job *actions_model.ActionRunJob, opts *RerunOptions) error {
+	if opts.Overrides.IsEmpty() {
+		opts.Overrides = nil
+	} else if err := ValidateRunOverrides(run, opts.Overrides); err != nil {
+		return err
+	}
+
+	rerunJobs := jobs
+	if job != nil {
+		rerunJobs = GetAllRerunJobs(job, jobs)
+	}
+
+	// reset run's start and stop time when it is done
+	if run.Status.IsDone() {
+		run.PreviousDuration = run.Duration()
+		run.Started = 0
+		run.Stopped = 0
+		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration"); err != nil {
+			return err
+		}
+		// the acknowledgement is for the failure of the previous attempt
+		if err := actions_model.UnacknowledgeRun(ctx, run); err != nil {
+			return err
+		}
+	}
+
+	for _, j := range rerunJobs {
+		// when all jobs are rerun, the jobs which have needs should wait for other jobs,
+		// otherwise the jobs other than the specified one should
+		shouldBlock := len(j.Needs) > 0
+		if job != nil {
+			shouldBlock = j.JobID != job.JobID
+		}
+		jobOpts := opts
+		if opts.AvoidSpotRunners && j != job {
+			// the jobs depending on the lost one haven't run on the spot runners
+			o := *opts
+			o.AvoidSpotRunners = false
+			jobOpts = &o
+		}
+		if err := rerunJob(ctx, j, shouldBlock, jobOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rerunJob(ctx context.Context, job *actions_model.ActionRunJob, shouldBlock bool, opts *RerunOptions) error {
+	status := job.Status
+	if !status.IsDone() {
+		return nil
+	}
+
+	job.PinnedRunnerID = 0
+	job.RunnerPinning = actions_model.RunnerPinningNone
+	if opts.Pinning != actions_model.RunnerPinningNone && job.TaskID != 0 {
+		task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+		if err != nil {
+			return err
+		}
+		job.PinnedRunnerID = task.RunnerID
+		job.RunnerPinning = opts.Pinning
+	}
+
+	job.TaskID = 0
+	job.PreflightError = ""
+	job.Overrides = opts.Overrides
+	job.AvoidSpotRunners = opts.AvoidSpotRunners
+	job.Status = actions_model.StatusWaiting
+	if shouldBlock {
+		job.Status = actions_model.StatusBlocked
+	}
+	job.Started = 0
+	job.Stopped = 0
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped", "pinned_runner_id", "runner_pinning", "preflight_error", "overrides", "avoid_spot_runners")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	CreateCommitStatus(ctx, job)
+	return nil
+}
+
+// ValidateRunOverrides checks the overrides of re-running the run.
+// Any variable can be overridden, but only the inputs the run was dispatched with can be overridden.
+func ValidateRunOverrides(run *actions_model.ActionRun, overrides *actions_model.RunOverrides) error {
+	for name := range overrides.Vars {
+		if secret_service.ValidateName(name) != nil || envNameCIRegexMatch(name) != nil {
+			return util.NewInvalidArgumentErrorf("invalid variable name %q", name)
+		}
+	}
+	if len(overrides.Inputs) == 0 {
+		return nil
+	}
+	if run.Event != webhook_module.HookEventWorkflowDispatch {
+		return util.NewInvalidArgumentErrorf("only the inputs of a dispatched run can be overridden")
+	}
+	payload, err := run.GetWorkflowDispatchEventPayload()
+	if err != nil {
+		return err
+	}
+	for name := range overrides.Inputs {
+		if _, ok := payload.Inputs[name]; !ok {
+			return util.NewInvalidArgumentErrorf("the run isn't dispatched with input %q", name)
+		}
+	}
+	return nil
+}
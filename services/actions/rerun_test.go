@@ -7,8 +7,13 @@ import (
 	"testing"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetAllRerunJobs(t *testing.T) {
@@ -46,3 +51,38 @@ func TestGetAllRerunJobs(t *testing.T) {
 		assert.ElementsMatch(t, tc.rerunJobs, rerunJobs)
 	}
 }
+
+func TestRerunJobsWithOverrides(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	require.NoError(t, err)
+
+	// only the inputs of dispatched runs can be overridden
+	err = RerunJobs(ctx, run, jobs, nil, &RerunOptions{Overrides: &actions_model.RunOverrides{Inputs: map[string]string{"env": "staging"}}})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = RerunJobs(ctx, run, jobs, nil, &RerunOptions{Overrides: &actions_model.RunOverrides{Vars: map[string]string{"GITEA_TOKEN": "x"}}})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	overrides := &actions_model.RunOverrides{Vars: map[string]string{"DEPLOY_FLAG": "canary"}}
+	require.NoError(t, RerunJobs(ctx, run, jobs, nil, &RerunOptions{Overrides: overrides}))
+	job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+	assert.Equal(t, actions_model.StatusWaiting, job.Status)
+	assert.EqualValues(t, 0, job.TaskID)
+	assert.Equal(t, overrides, job.Overrides)
+}
+
+func TestValidateRunOverrides(t *testing.T) {
+	run := &actions_model.ActionRun{
+		Event:        webhook_module.HookEventWorkflowDispatch,
+		EventPayload: `{"workflow":"deploy.yml","ref":"refs/heads/main","inputs":{"env":"prod","dry_run":"false"}}`,
+	}
+	assert.NoError(t, ValidateRunOverrides(run, &actions_model.RunOverrides{
+		Vars:   map[string]string{"REGION": "eu-west"},
+		Inputs: map[string]string{"dry_run": "true"},
+	}))
+	assert.ErrorIs(t, ValidateRunOverrides(run, &actions_model.RunOverrides{Inputs: map[string]string{"force": "true"}}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateRunOverrides(run, &actions_model.RunOverrides{Vars: map[string]string{"CI_FLAG": "1"}}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateRunOverrides(run, &actions_model.RunOverrides{Vars: map[string]string{"1FLAG": "1"}}), util.ErrInvalidArgument)
+}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+)
+
+// CanViewRunDetails returns whether the user could see the logs, artifacts and summaries of the runs of the repository.
+// The users who could read the runs could see them, unless they are anonymous and the repository only shows them the metadata of the runs.
+func CanViewRunDetails(ctx context.Context, repo *repo_model.Repository, doer *user_model.User) bool {
+	if doer != nil {
+		return true
+	}
+	return repo.MustGetUnit(ctx, unit.TypeActions).ActionsConfig().GetAnonymousRunAccess() == repo_model.ActionsAnonymousRunAccessFull
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanViewRunDetails(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+
+	assert.True(t, CanViewRunDetails(ctx, repo, nil))
+	assert.True(t, CanViewRunDetails(ctx, repo, user))
+
+	actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	require.NoError(t, err)
+	actionsUnit.ActionsConfig().AnonymousRunAccess = repo_model.ActionsAnonymousRunAccessMetadata
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+
+	repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	assert.False(t, CanViewRunDetails(ctx, repo, nil))
+	assert.True(t, CanViewRunDetails(ctx, repo, user))
+}
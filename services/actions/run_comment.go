@@ -0,0 +1,88 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// CreateRunComment creates a comment on the run, or on a job of the run if jobID isn't 0,
+// and notifies the mentioned users and the participants of the run.
+func CreateRunComment(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, jobID int64, content string) (*actions_model.ActionRunComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, util.NewInvalidArgumentErrorf("the content of the comment is empty")
+	}
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	comment := &actions_model.ActionRunComment{
+		RepoID:   run.RepoID,
+		RunID:    run.ID,
+		JobID:    jobID,
+		PosterID: doer.ID,
+		Poster:   doer,
+		Content:  content,
+	}
+	if err := actions_model.CreateRunComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	mentions, err := findRunCommentMentions(ctx, run, content)
+	if err != nil {
+		return nil, err
+	}
+	notify_service.CreateActionRunComment(ctx, doer, run, comment, mentions)
+
+	return comment, nil
+}
+
+// findRunCommentMentions returns the users mentioned by the content who can read the run
+func findRunCommentMentions(ctx context.Context, run *actions_model.ActionRun, content string) ([]*user_model.User, error) {
+	names := references.FindAllMentionsMarkdown(content)
+	mentions := make([]*user_model.User, 0, len(names))
+	for _, name := range names {
+		u, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !u.IsActive || u.IsOrganization() {
+			continue
+		}
+		if !access_model.CheckRepoUnitUser(ctx, run.Repo, u, unit.TypeActions) {
+			continue
+		}
+		mentions = append(mentions, u)
+	}
+	return mentions, nil
+}
+
+// UpdateRunComment updates the content of the comment
+func UpdateRunComment(ctx context.Context, comment *actions_model.ActionRunComment, content string) error {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return util.NewInvalidArgumentErrorf("the content of the comment is empty")
+	}
+	comment.Content = content
+	return actions_model.UpdateRunCommentContent(ctx, comment)
+}
+
+// CanModifyRunComment returns whether the user can edit or delete the comment,
+// only the poster and the users who can write Actions of the repository are allowed.
+func CanModifyRunComment(doer *user_model.User, comment *actions_model.ActionRunComment, canWriteActions bool) bool {
+	return doer != nil && (doer.ID == comment.PosterID || canWriteActions || doer.IsAdmin)
+}
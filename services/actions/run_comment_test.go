@@ -0,0 +1,36 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRunCommentMentions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	mentionIDs := func(repoID int64, content string) []int64 {
+		run := &actions_model.ActionRun{RepoID: repoID, Repo: unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repoID})}
+		users, err := findRunCommentMentions(db.DefaultContext, run, content)
+		assert.NoError(t, err)
+		ids := make([]int64, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		return ids
+	}
+
+	// repo 1 has Actions enabled: org3 is an organization, user9 is inactive and nobody doesn't exist
+	assert.Equal(t, []int64{4, 5}, mentionIDs(1, "@user4 @org3 please look, cc @user9 @nobody @user5"))
+	assert.Empty(t, mentionIDs(1, "no mention, user4@example.com `@user4`"))
+	// repo 4 doesn't have Actions enabled, nobody can read its runs
+	assert.Empty(t, mentionIDs(4, "@user4 @user5"))
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// runCreationFailureRetention is how long the runs which weren't created are kept visible to the users
+const runCreationFailureRetention = 30 * 24 * time.Hour
+
+// runCreationFailureInternalMessage is the message of the internal errors, their details could leak the internals so they're only in the logs
+const runCreationFailureInternalMessage = "an internal error occurred, the details are in the logs of the instance"
+
+// recordRunCreationFailure records the run of the workflow triggered by the event which wasn't created, so the users could learn why it never appeared,
+// the workflow is empty if the failure isn't of a single workflow
+func recordRunCreationFailure(ctx context.Context, input *notifyInput, ref, commitSHA, workflowID string, reason actions_model.RunCreationFailureReason, message string) {
+	if err := actions_model.InsertRunCreationFailure(ctx, &actions_model.ActionRunCreationFailure{
+		RepoID:        input.Repo.ID,
+		WorkflowID:    workflowID,
+		Event:         input.Event,
+		Ref:           ref,
+		CommitSHA:     commitSHA,
+		TriggerUserID: input.Doer.ID,
+		Reason:        reason,
+		Message:       message,
+	}); err != nil {
+		log.Error("InsertRunCreationFailure of workflow %q of repo %d: %v", workflowID, input.Repo.ID, err)
+	}
+}
+
+// CleanupRunCreationFailures deletes the records of the runs which weren't created after the retention
+func CleanupRunCreationFailures(ctx context.Context) error {
+	n, err := actions_model.DeleteRunCreationFailuresBefore(ctx, timeutil.TimeStampNow().AddDuration(-runCreationFailureRetention))
+	if err != nil {
+		return fmt.Errorf("DeleteRunCreationFailuresBefore: %w", err)
+	}
+	if n > 0 {
+		log.Info("Deleted %d records of the runs which weren't created", n)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// preflightCheckRunHook asks the pre-run hook of the external systems whether the run could start,
+// it's always enabled since it does nothing if setting.ActionsHooks.PreRunURL is empty.
+const preflightCheckRunHook = "run_hook"
+
+// The hooks of the external systems called with the runs
+const (
+	RunHookPreRun  = "pre_run"  // before the jobs of a run are queued
+	RunHookPostRun = "post_run" // after a run is done
+)
+
+// RunHookPayload is posted to the hooks of the external systems
+type RunHookPayload struct {
+	Hook        string            `json:"hook"`
+	Repository  string            `json:"repository"`
+	WorkflowID  string            `json:"workflow_id"`
+	Event       string            `json:"event"`
+	Ref         string            `json:"ref"`
+	CommitSHA   string            `json:"commit_sha"`
+	TriggerUser string            `json:"trigger_user"`
+	Jobs        []*RunHookJobInfo `json:"jobs"`
+	// the run has been created only for the post-run hook
+	RunID     int64  `json:"run_id,omitempty"`
+	RunNumber int64  `json:"run_number,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// RunHookJobInfo is a job of the run posted to the hooks
+type RunHookJobInfo struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	RunsOn []string `json:"runs_on"`
+	Status string   `json:"status,omitempty"`
+}
+
+// RunHookResponse is answered by the hooks, Allow is only respected for the pre-run hook
+type RunHookResponse struct {
+	Allow   *bool  `json:"allow"` // the run is allowed if it's absent
+	Message string `json:"message"`
+}
+
+// preflightRunHook asks the pre-run hook whether the run could start, all the jobs fail if it's vetoed
+func preflightRunHook(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow) (map[string]string, error) {
+	if setting.ActionsHooks.PreRunURL == "" {
+		return nil, nil
+	}
+	payload, err := newRunHookPayload(ctx, RunHookPreRun, run)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		id, j := job.Job()
+		payload.Jobs = append(payload.Jobs, &RunHookJobInfo{ID: id, Name: j.Name, RunsOn: j.RunsOn()})
+	}
+
+	var msg string
+	resp, err := postRunHook(ctx, setting.ActionsHooks.PreRunURL, payload)
+	if err != nil {
+		if setting.ActionsHooks.FailOpen {
+			log.Warn("The pre-run hook failed, the run of workflow %q of repository %d starts anyway: %v", run.WorkflowID, run.RepoID, err)
+			return nil, nil
+		}
+		msg = fmt.Sprintf("the pre-run hook failed: %v", err)
+	} else if resp.Allow != nil && !*resp.Allow {
+		msg = "the run is vetoed by the pre-run hook"
+		if resp.Message != "" {
+			msg += ": " + resp.Message
+		}
+	} else {
+		return nil, nil
+	}
+
+	errs := map[string]string{}
+	for _, job := range jobs {
+		id, _ := job.Job()
+		errs[id] = msg
+	}
+	return errs, nil
+}
+
+// callPostRunHook tells the post-run hook the run is done, and comments the message answered by it on the run
+func callPostRunHook(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+	if setting.ActionsHooks.PostRunURL == "" {
+		return nil
+	}
+	payload, err := newRunHookPayload(ctx, RunHookPostRun, run)
+	if err != nil {
+		return err
+	}
+	payload.RunID = run.ID
+	payload.RunNumber = run.Index
+	payload.URL = run.HTMLURL()
+	payload.Status = run.Status.String()
+	for _, job := range jobs {
+		payload.Jobs = append(payload.Jobs, &RunHookJobInfo{ID: job.JobID, Name: job.Name, RunsOn: job.RunsOn, Status: job.Status.String()})
+	}
+
+	resp, err := postRunHook(ctx, setting.ActionsHooks.PostRunURL, payload)
+	if err != nil {
+		return fmt.Errorf("post-run hook: %w", err)
+	}
+	if resp.Message == "" {
+		return nil
+	}
+	_, err = CreateRunComment(ctx, user_model.NewActionsUser(), run, 0, resp.Message)
+	return err
+}
+
+func newRunHookPayload(ctx context.Context, hook string, run *actions_model.ActionRun) (*RunHookPayload, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	triggerUser, err := user_model.GetPossibleUserByID(ctx, run.TriggerUserID)
+	if err != nil {
+		return nil, err
+	}
+	return &RunHookPayload{
+		Hook:        hook,
+		Repository:  run.Repo.FullName(),
+		WorkflowID:  run.WorkflowID,
+		Event:       string(run.Event),
+		Ref:         run.Ref,
+		CommitSHA:   run.CommitSHA,
+		TriggerUser: triggerUser.Name,
+		Jobs:        []*RunHookJobInfo{},
+	}, nil
+}
+
+// postRunHook posts the payload to the hook, signed by setting.ActionsHooks.Secret like the webhooks
+func postRunHook(ctx context.Context, url string, payload *RunHookPayload) (*RunHookResponse, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, setting.ActionsHooks.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gitea-Hook", payload.Hook)
+	if setting.ActionsHooks.Secret != "" {
+		sig := hmac.New(sha256.New, []byte(setting.ActionsHooks.Secret))
+		_, _ = sig.Write(body)
+		req.Header.Set("X-Gitea-Signature", hex.EncodeToString(sig.Sum(nil)))
+	}
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: proxy.Proxy()},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	ret := &RunHookResponse{}
+	// the hooks could answer nothing, the run is allowed and not annotated then
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return ret, nil
+	}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return ret, nil
+}
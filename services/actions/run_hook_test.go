@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHooks(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	var payload RunHookPayload
+	var answer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sig := hmac.New(sha256.New, []byte("secret"))
+		_, _ = sig.Write(body)
+		if r.Header.Get("X-Gitea-Signature") != hex.EncodeToString(sig.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		payload = RunHookPayload{}
+		_ = json.Unmarshal(body, &payload)
+		_, _ = w.Write([]byte(answer))
+	}))
+	defer server.Close()
+	defer test.MockVariableValue(&setting.ActionsHooks.PreRunURL, server.URL)()
+	defer test.MockVariableValue(&setting.ActionsHooks.PostRunURL, server.URL)()
+	defer test.MockVariableValue(&setting.ActionsHooks.Secret, "secret")()
+
+	jobs, err := jobparser.Parse([]byte(`
+name: test
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`))
+	require.NoError(t, err)
+	run := &actions_model.ActionRun{RepoID: 4, TriggerUserID: 5, WorkflowID: "test.yaml", Ref: "refs/heads/master"}
+
+	// the run is allowed if the hook answers nothing
+	errs, err := preflightRunHook(ctx, run, jobs)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Equal(t, RunHookPreRun, payload.Hook)
+	assert.Equal(t, "user5/repo4", payload.Repository)
+	assert.Equal(t, "user5", payload.TriggerUser)
+	require.Len(t, payload.Jobs, 1)
+	assert.Equal(t, []string{"ubuntu-latest"}, payload.Jobs[0].RunsOn)
+
+	answer = `{"allow": false, "message": "not on Fridays"}`
+	errs, err = preflightRunHook(ctx, run, jobs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"build": "the run is vetoed by the pre-run hook: not on Fridays"}, errs)
+
+	// the run fails if the hook fails, unless it fails open
+	setting.ActionsHooks.Secret = "wrong"
+	errs, err = preflightRunHook(ctx, run, jobs)
+	require.NoError(t, err)
+	assert.Contains(t, errs["build"], "the pre-run hook failed")
+	setting.ActionsHooks.FailOpen = true
+	defer func() { setting.ActionsHooks.FailOpen = false }()
+	errs, err = preflightRunHook(ctx, run, jobs)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	setting.ActionsHooks.Secret = "secret"
+
+	// the message answered by the post-run hook is commented on the run
+	run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+	runJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	require.NoError(t, err)
+	answer = `{"message": "deployed to staging"}`
+	require.NoError(t, callPostRunHook(ctx, run, runJobs))
+	assert.Equal(t, RunHookPostRun, payload.Hook)
+	assert.Equal(t, run.ID, payload.RunID)
+	assert.Equal(t, run.Status.String(), payload.Status)
+	assert.Len(t, payload.Jobs, len(runJobs))
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunComment{RunID: run.ID, PosterID: user_model.ActionsUserID, Content: "deployed to staging"})
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// runIssueLogLines is the number of the last log lines of a failed job quoted in the issue
+const runIssueLogLines = 20
+
+// CreateIssueFromRun opens an issue to track the failure of the run, with the link of the run,
+// the failed jobs and their last log lines in the content.
+// If autoClose is true, the issue will be closed when the workflow passes later on the same ref.
+func CreateIssueFromRun(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, autoClose bool) (*issues_model.Issue, error) {
+	if run.Status != actions_model.StatusFailure {
+		return nil, util.NewInvalidArgumentErrorf("run %d hasn't failed", run.ID)
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	if !run.Repo.UnitEnabled(ctx, unit.TypeIssues) {
+		return nil, util.NewInvalidArgumentErrorf("issues are disabled in repo %d", run.RepoID)
+	}
+	if _, err := actions_model.GetRunIssueByRunID(ctx, run.ID); err == nil {
+		return nil, util.NewAlreadyExistErrorf("an issue has been opened for run %d", run.ID)
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+
+	content, err := generateRunIssueContent(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &issues_model.Issue{
+		RepoID:   run.RepoID,
+		Repo:     run.Repo,
+		Title:    fmt.Sprintf("Workflow %s failed on %s", run.WorkflowID, run.PrettyRef()),
+		PosterID: doer.ID,
+		Poster:   doer,
+		Content:  content,
+	}
+	if err := issue_service.NewIssue(ctx, run.Repo, issue, nil, nil, nil, 0); err != nil {
+		return nil, err
+	}
+
+	if err := actions_model.InsertRunIssue(ctx, &actions_model.ActionRunIssue{
+		RepoID:     run.RepoID,
+		WorkflowID: run.WorkflowID,
+		Ref:        run.Ref,
+		RunID:      run.ID,
+		IssueID:    issue.ID,
+		AutoClose:  autoClose,
+	}); err != nil {
+		return nil, err
+	}
+
+	return issue, nil
+}
+
+// generateRunIssueContent generates the markdown content of the issue tracking the failure of the run
+func generateRunIssueContent(ctx context.Context, run *actions_model.ActionRun) (string, error) {
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return "", err
+	}
+
+	// TODO: if we want support content in different languages, we need to support i18n of it
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Run [#%d](%s) of workflow `%s` failed on `%s`", run.Index, run.HTMLURL(), run.WorkflowID, run.PrettyRef())
+	if run.CommitSHA != "" {
+		fmt.Fprintf(sb, " at commit %s", run.CommitSHA)
+	}
+	sb.WriteString(".\n")
+
+	for i, job := range jobs {
+		if job.Status != actions_model.StatusFailure {
+			continue
+		}
+		fmt.Fprintf(sb, "\n### Job [%s](%s)\n", job.Name, run.JobHTMLURL(i))
+		lines, err := readLastLogLines(ctx, job.TaskID, runIssueLogLines)
+		if err != nil {
+			// the logs are helpful but not necessary, so don't fail the issue creation
+			log.Warn("Failed to read the logs of job %d: %v", job.ID, err)
+			continue
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(sb, "\n```\n%s\n```\n", strings.Join(lines, "\n"))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// readLastLogLines returns at most n last log lines of the task
+func readLastLogLines(ctx context.Context, taskID int64, n int64) ([]string, error) {
+	if taskID == 0 {
+		return nil, nil
+	}
+	task, err := actions_model.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.LogExpired || task.LogLength == 0 || int64(len(task.LogIndexes)) < task.LogLength {
+		return nil, nil
+	}
+
+	start := max(task.LogLength-n, 0)
+	rows, err := actions.ReadLogs(ctx, task.LogInStorage, task.LogFilename, task.LogIndexes[start], task.LogLength-start)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		// avoid closing the code block of the content early
+		lines = append(lines, strings.ReplaceAll(row.Content, "```", "'''"))
+	}
+	return lines, nil
+}
+
+// closeRunIssuesIfPassed closes the issues opened for the failures of the workflow on the ref,
+// if the run of the workflow has passed.
+// It's called when the outbox event of the run being done is processed, so it will be retried if it fails.
+func closeRunIssuesIfPassed(ctx context.Context, runID int64) error {
+	// load the run again, the status of it is aggregated from the jobs when updating them
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run.Status != actions_model.StatusSuccess {
+		return nil
+	}
+	ris, err := actions_model.FindRunIssuesToAutoClose(ctx, run.RepoID, run.WorkflowID, run.Ref)
+	if err != nil {
+		return fmt.Errorf("find the issues to close for run %d: %w", run.ID, err)
+	}
+	if len(ris) == 0 {
+		return nil
+	}
+	if err := run.LoadRepo(ctx); err != nil {
+		return err
+	}
+
+	doer := user_model.NewActionsUser()
+	for _, ri := range ris {
+		// a run of an older commit could finish after the failed run, it doesn't mean the failure has been fixed,
+		// but a rerun of the failed run itself which has passed does
+		if ri.RunID > run.ID {
+			continue
+		}
+		if err := closeRunIssue(ctx, doer, run, ri); err != nil {
+			return fmt.Errorf("close issue %d by run %d: %w", ri.IssueID, run.ID, err)
+		}
+	}
+	return nil
+}
+
+func closeRunIssue(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, ri *actions_model.ActionRunIssue) error {
+	issue, err := issues_model.GetIssueByID(ctx, ri.IssueID)
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	// the issue could have been closed manually, then don't touch it
+	if !issue.IsClosed {
+		content := fmt.Sprintf("Workflow `%s` has passed on `%s` in run [#%d](%s).", run.WorkflowID, run.PrettyRef(), run.Index, run.HTMLURL())
+		if _, err := issue_service.CreateIssueComment(ctx, doer, run.Repo, issue, content, nil); err != nil {
+			return fmt.Errorf("CreateIssueComment: %w", err)
+		}
+		if err := issue_service.ChangeStatus(ctx, issue, doer, run.CommitSHA, true); err != nil {
+			return fmt.Errorf("ChangeStatus: %w", err)
+		}
+	}
+	return actions_model.SetRunIssueClosedBy(ctx, ri, run.ID)
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+)
+
+const (
+	// MaxRunsWait is the longest time the requests could wait for the changes of runs,
+	// it's less than the timeouts of the common reverse proxies
+	MaxRunsWait = time.Minute
+
+	// runsWatchInterval is how often the waiters check the runs even if they're not woken up,
+	// the changes made by other instances sharing the database are only noticed this way
+	runsWatchInterval = 2 * time.Second
+
+	// runsWatchDelay is how long the woken waiters wait before checking the runs,
+	// the changes are notified before their transactions are committed, and they usually come in bursts
+	runsWatchDelay = 100 * time.Millisecond
+)
+
+// runsWatchers are the channels closed when the runs of the repositories change, keyed by repository ids
+var runsWatchers = struct {
+	sync.Mutex
+	chans map[int64]chan struct{}
+}{chans: map[int64]chan struct{}{}}
+
+// watchRuns returns a channel which is closed when a run of the repository or one of its jobs changes
+func watchRuns(repoID int64) <-chan struct{} {
+	runsWatchers.Lock()
+	defer runsWatchers.Unlock()
+	ch, ok := runsWatchers.chans[repoID]
+	if !ok {
+		ch = make(chan struct{})
+		runsWatchers.chans[repoID] = ch
+	}
+	return ch
+}
+
+// notifyRunsChanged wakes up the waiters of the runs of the repository
+func notifyRunsChanged(repoID int64) {
+	runsWatchers.Lock()
+	defer runsWatchers.Unlock()
+	if ch, ok := runsWatchers.chans[repoID]; ok {
+		close(ch)
+		delete(runsWatchers.chans, repoID)
+	}
+}
+
+// WaitRunsChange blocks until changed returns true, the timeout passes or the context is done, so the clients
+// could long-poll the runs of the repository instead of requesting them again and again.
+// The changes are checked when the runs or their jobs of the repository change their statuses in this instance,
+// and periodically for the other instances.
+func WaitRunsChange(ctx context.Context, repoID int64, timeout time.Duration, changed func(ctx context.Context) (bool, error)) error {
+	timeout = min(timeout, MaxRunsWait)
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(runsWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		// watch before checking, so the changes made while checking aren't missed
+		watched := watchRuns(repoID)
+		if ok, err := changed(ctx); err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+		case <-watched:
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(runsWatchDelay):
+			}
+		}
+	}
+}
+
+// RunETag returns the ETag of the run with its jobs and their latest tasks keyed by ids,
+// it changes when any of them is updated, including the progress of the steps reported by the runners
+func RunETag(run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob, tasks map[int64]*actions_model.ActionTask) string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d:%d:%d", run.ID, run.Version, run.Status)
+	for _, job := range jobs {
+		_, _ = fmt.Fprintf(h, ";%d:%d:%d:%d", job.ID, job.Version, job.Status, job.Attempt)
+		if task := tasks[job.TaskID]; task != nil {
+			_, _ = fmt.Fprintf(h, ":%d:%d:%d", task.ID, task.Status, task.Updated)
+		}
+	}
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+// RunsETag returns the ETag of a page of runs, it changes when runs are added or any of them is updated
+func RunsETag(runs []*actions_model.ActionRun, total int64) string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d", total)
+	for _, run := range runs {
+		_, _ = fmt.Fprintf(h, ";%d:%d:%d", run.ID, run.Version, run.Status)
+	}
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+func init() {
+	actions_model.OnStatusTransition(func(ctx context.Context, transition *actions_model.StatusTransition) error {
+		switch {
+		case transition.Run != nil:
+			notifyRunsChanged(transition.Run.RepoID)
+		case transition.Job != nil:
+			notifyRunsChanged(transition.Job.RepoID)
+		case transition.Task != nil:
+			notifyRunsChanged(transition.Task.RepoID)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitRunsChange(t *testing.T) {
+	t.Run("changed", func(t *testing.T) {
+		var changes atomic.Int32
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			changes.Add(1)
+			notifyRunsChanged(1)
+		}()
+		start := time.Now()
+		assert.NoError(t, WaitRunsChange(context.Background(), 1, time.Minute, func(ctx context.Context) (bool, error) {
+			return changes.Load() > 0, nil
+		}))
+		assert.Less(t, time.Since(start), runsWatchInterval)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		checks := 0
+		assert.NoError(t, WaitRunsChange(context.Background(), 1, 100*time.Millisecond, func(ctx context.Context) (bool, error) {
+			checks++
+			return false, nil
+		}))
+		assert.Equal(t, 1, checks)
+	})
+
+	t.Run("other repository", func(t *testing.T) {
+		checks := 0
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			notifyRunsChanged(2)
+		}()
+		assert.NoError(t, WaitRunsChange(context.Background(), 1, 100*time.Millisecond, func(ctx context.Context) (bool, error) {
+			checks++
+			return false, nil
+		}))
+		assert.Equal(t, 1, checks)
+	})
+}
+
+func TestRunETag(t *testing.T) {
+	run := &actions_model.ActionRun{ID: 1, Version: 1, Status: actions_model.StatusRunning}
+	jobs := []*actions_model.ActionRunJob{{ID: 1, Version: 1, Status: actions_model.StatusRunning, TaskID: 1}}
+	tasks := map[int64]*actions_model.ActionTask{1: {ID: 1, Status: actions_model.StatusRunning, Updated: 100}}
+	etag := RunETag(run, jobs, tasks)
+	assert.Equal(t, etag, RunETag(run, jobs, tasks))
+
+	tasks[1].Updated = 101
+	assert.NotEqual(t, etag, RunETag(run, jobs, tasks))
+	etag = RunETag(run, jobs, tasks)
+
+	jobs[0].Status = actions_model.StatusSuccess
+	jobs[0].Version++
+	assert.NotEqual(t, etag, RunETag(run, jobs, tasks))
+
+	runs := []*actions_model.ActionRun{run}
+	etag = RunsETag(runs, 1)
+	assert.NotEqual(t, etag, RunsETag(runs, 2))
+	run.Status = actions_model.StatusSuccess
+	assert.NotEqual(t, etag, RunsETag(runs, 1))
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+)
+
+// RotateRunnerToken replaces the token the runner authenticates with, the old token stops working immediately.
+// The new token is returned, it must be written to the state file of the runner (.runner by default).
+func RotateRunnerToken(ctx context.Context, runner *actions_model.ActionRunner) (string, error) {
+	if err := runner.GenerateToken(); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	if err := actions_model.UpdateRunner(ctx, runner, "token_hash", "token_salt"); err != nil {
+		return "", err
+	}
+	return runner.Token, nil
+}
+
+// RevokeRunner locks out a runner which might have been compromised.
+// Its token is replaced with one nobody knows, so it can't fetch tasks or report anymore,
+// and the tasks it's running are failed. The runner is kept, so it could be inspected before being deleted.
+func RevokeRunner(ctx context.Context, runner *actions_model.ActionRunner) error {
+	if _, err := RotateRunnerToken(ctx, runner); err != nil {
+		return err
+	}
+	runner.Token = ""
+
+	return stopTasks(ctx, actions_model.FindTaskOptions{
+		RunnerID: runner.ID,
+		Status:   actions_model.StatusRunning,
+	}, actions_model.TaskErrorClassInfrastructure)
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateAndRevokeRunner(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	// the fixture task 47 is running on the runner 1
+	runner := &actions_model.ActionRunner{
+		ID:   1,
+		UUID: "6d6b4d36-2f0e-4f4b-8a4e-6c3f1f0d8a11",
+		Name: "suspected",
+	}
+	require.NoError(t, runner.GenerateToken())
+	require.NoError(t, actions_model.CreateRunner(ctx, runner))
+	oldHash := runner.TokenHash
+
+	token, err := RotateRunnerToken(ctx, runner)
+	require.NoError(t, err)
+	runner = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{ID: 1})
+	assert.NotEqual(t, oldHash, runner.TokenHash)
+	assert.Equal(t, runner.TokenHash, auth_model.HashToken(token, runner.TokenSalt))
+
+	require.NoError(t, RevokeRunner(ctx, runner))
+	runner = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{ID: 1})
+	assert.NotEqual(t, runner.TokenHash, auth_model.HashToken(token, runner.TokenSalt))
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	assert.Equal(t, actions_model.StatusFailure, task.Status)
+}
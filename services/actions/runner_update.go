@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// PickRunnerUpdate returns the target version if the runner should update itself to it instead of picking a task, or empty if it shouldn't.
+// A runner is signaled once for a target version, when it's idle, and the runners driven by the executors are never signaled.
+func PickRunnerUpdate(ctx context.Context, runner *actions_model.ActionRunner) (string, error) {
+	if runner.Executor != "" {
+		return "", nil
+	}
+	target, err := actions_model.GetRunnerTargetVersion(ctx)
+	if err != nil || target == "" {
+		return "", err
+	}
+	if runner.UpdateStatus(target) != actions_model.RunnerUpdatePending {
+		return "", nil
+	}
+	if busy, err := actions_model.IsRunnerBusy(ctx, runner.ID); err != nil || busy {
+		return "", err
+	}
+	if err := actions_model.SignalRunnerUpdate(ctx, runner, target); err != nil {
+		return "", fmt.Errorf("SignalRunnerUpdate: %w", err)
+	}
+	return target, nil
+}
+
+// GetRunnerUpdateRollout returns the statuses of all runners in the rollout of the target version,
+// and how many runners with each label are in each status
+func GetRunnerUpdateRollout(ctx context.Context) (*api.ActionRunnerUpdateRollout, error) {
+	target, err := actions_model.GetRunnerTargetVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetRunnerTargetVersion: %w", err)
+	}
+	rollout := &api.ActionRunnerUpdateRollout{
+		TargetVersion: target,
+		Runners:       []*api.ActionRunnerUpdate{},
+		Labels:        []*api.ActionRunnerUpdateLabel{},
+	}
+	if target == "" {
+		return rollout, nil
+	}
+
+	runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{Sort: "alphabetically"})
+	if err != nil {
+		return nil, fmt.Errorf("FindRunners: %w", err)
+	}
+	labels := make(map[string]*api.ActionRunnerUpdateLabel)
+	for _, runner := range runners {
+		status := runner.UpdateStatus(target)
+		update := &api.ActionRunnerUpdate{
+			ID:      runner.ID,
+			Name:    runner.Name,
+			Version: runner.Version,
+			Labels:  runner.AgentLabels,
+			Status:  status,
+		}
+		if runner.UpdateVersion == target && runner.UpdateSignaled > 0 {
+			signaled := runner.UpdateSignaled.AsTime()
+			update.Signaled = &signaled
+		}
+		rollout.Runners = append(rollout.Runners, update)
+
+		for _, label := range runner.AgentLabels {
+			l, ok := labels[label]
+			if !ok {
+				l = &api.ActionRunnerUpdateLabel{Label: label}
+				labels[label] = l
+				rollout.Labels = append(rollout.Labels, l)
+			}
+			l.Total++
+			switch status {
+			case actions_model.RunnerUpdateUpToDate:
+				l.UpToDate++
+			case actions_model.RunnerUpdatePending:
+				l.Pending++
+			case actions_model.RunnerUpdateSignaled:
+				l.Signaled++
+			case actions_model.RunnerUpdateFailed:
+				l.Failed++
+			case actions_model.RunnerUpdateUnsupported:
+				l.Unsupported++
+			}
+		}
+	}
+	slices.SortFunc(rollout.Labels, func(a, b *api.ActionRunnerUpdateLabel) int {
+		return cmp.Compare(a.Label, b.Label)
+	})
+	return rollout, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickRunnerUpdate(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	idle := &actions_model.ActionRunner{ID: 1901, UUID: "idle-runner", Name: "idle", TokenHash: "idle-runner", Version: "v0.2.10", AgentLabels: []string{"rollout"}}
+	busy := &actions_model.ActionRunner{ID: 1902, UUID: "busy-runner", Name: "busy", TokenHash: "busy-runner", Version: "v0.2.10", AgentLabels: []string{"rollout", "rollout-gpu"}}
+	executor := &actions_model.ActionRunner{ID: 1903, UUID: "executor-runner", Name: "executor", TokenHash: "executor-runner", Version: "v0.2.10", AgentLabels: []string{"rollout"}, Executor: "kubernetes"}
+	require.NoError(t, db.Insert(ctx, idle, busy, executor))
+	_, err := db.GetEngine(ctx).Exec("UPDATE action_task SET runner_id = ? WHERE id = 47", busy.ID)
+	require.NoError(t, err)
+
+	// no runners are signaled without a target version
+	version, err := PickRunnerUpdate(ctx, idle)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+
+	require.NoError(t, actions_model.SetRunnerTargetVersion(ctx, "v0.2.11"))
+
+	version, err = PickRunnerUpdate(ctx, idle)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.11", version)
+	// it's signaled once
+	version, err = PickRunnerUpdate(ctx, idle)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+	// the busy runner and the runner driven by the executor aren't signaled
+	version, err = PickRunnerUpdate(ctx, busy)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+	version, err = PickRunnerUpdate(ctx, executor)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+
+	rollout, err := GetRunnerUpdateRollout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.11", rollout.TargetVersion)
+	statuses := make(map[int64]string)
+	for _, r := range rollout.Runners {
+		statuses[r.ID] = r.Status
+		assert.Equal(t, r.ID == idle.ID, r.Signaled != nil)
+	}
+	assert.Equal(t, actions_model.RunnerUpdateSignaled, statuses[idle.ID])
+	assert.Equal(t, actions_model.RunnerUpdatePending, statuses[busy.ID])
+	assert.Equal(t, actions_model.RunnerUpdateUnsupported, statuses[executor.ID])
+	labels := make(map[string]*api.ActionRunnerUpdateLabel)
+	for _, l := range rollout.Labels {
+		labels[l.Label] = l
+	}
+	require.NotNil(t, labels["rollout-gpu"])
+	assert.EqualValues(t, 1, labels["rollout-gpu"].Pending)
+	require.NotNil(t, labels["rollout"])
+	assert.EqualValues(t, 3, labels["rollout"].Total)
+	assert.EqualValues(t, 1, labels["rollout"].Signaled)
+	assert.EqualValues(t, 1, labels["rollout"].Unsupported)
+
+	// stop the rollout
+	require.NoError(t, actions_model.SetRunnerTargetVersion(ctx, ""))
+	rollout, err = GetRunnerUpdateRollout(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rollout.TargetVersion)
+	assert.Empty(t, rollout.Runners)
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// MaxUpcomingScheduledRuns is the max number of the upcoming runs listed for a cron schedule
+const MaxUpcomingScheduledRuns = 100
+
+// UpcomingSchedule is a cron schedule of a workflow with its runs in a time window
+type UpcomingSchedule struct {
+	Spec *actions_model.ActionScheduleSpec
+	Runs []time.Time
+}
+
+// FindUpcomingSchedules returns the cron schedules of the workflows with their runs from now until the time.
+// The schedules of the workflows which are disabled, or whose repositories have disabled Actions, are excluded.
+func FindUpcomingSchedules(ctx context.Context, opts actions_model.FindSpecOptions, now, until time.Time) ([]*UpcomingSchedule, error) {
+	opts.ListOptions = db.ListOptionsAll
+	specs, _, err := actions_model.FindSpecs(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*UpcomingSchedule, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Repo == nil || spec.Schedule == nil {
+			continue
+		}
+		actionsUnit, err := spec.Repo.GetUnit(ctx, unit_model.TypeActions)
+		if err != nil {
+			if repo_model.IsErrUnitTypeNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if actionsUnit.ActionsConfig().IsWorkflowDisabled(spec.Schedule.WorkflowID) {
+			continue
+		}
+		cronSchedule, err := spec.Parse()
+		if err != nil {
+			log.Trace("invalid cron spec %q of schedule %d: %v", spec.Spec, spec.ScheduleID, err)
+			continue
+		}
+
+		schedule := &UpcomingSchedule{Spec: spec, Runs: []time.Time{}}
+		// a run which is overdue will be triggered by the next tick of the scheduler
+		next := spec.Next.AsTime()
+		if next.Before(now) {
+			next = now
+		}
+		for !next.After(until) && len(schedule.Runs) < MaxUpcomingScheduledRuns {
+			schedule.Runs = append(schedule.Runs, next.UTC())
+			next = cronSchedule.Next(next)
+			if next.IsZero() {
+				break
+			}
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// WriteSchedulesICalendar writes the upcoming runs of the schedules as the events of an iCalendar (RFC 5545)
+func WriteSchedulesICalendar(w io.Writer, name string, schedules []*UpcomingSchedule, now time.Time) error {
+	const timeFormat = "20060102T150405Z"
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Gitea//Actions Schedules//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escapeICalendarText(name),
+	}
+	for _, schedule := range schedules {
+		spec := schedule.Spec
+		title := spec.Schedule.Title
+		if title == "" {
+			title = spec.Schedule.WorkflowID
+		}
+		link := spec.Repo.HTMLURL() + actions_model.WorkflowPath(spec.Schedule.WorkflowID)
+		for _, run := range schedule.Runs {
+			lines = append(lines,
+				"BEGIN:VEVENT",
+				fmt.Sprintf("UID:schedule-%d-%d@%s", spec.ID, run.Unix(), setting.Domain),
+				"DTSTAMP:"+now.UTC().Format(timeFormat),
+				"DTSTART:"+run.UTC().Format(timeFormat),
+				"SUMMARY:"+escapeICalendarText(fmt.Sprintf("%s: %s", spec.Repo.FullName(), title)),
+				"DESCRIPTION:"+escapeICalendarText(fmt.Sprintf("Workflow %s scheduled by cron %q", spec.Schedule.WorkflowID, spec.Spec)),
+				"URL:"+link,
+				"END:VEVENT",
+			)
+		}
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICalendarLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var iCalendarTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalendarText(s string) string {
+	return iCalendarTextEscaper.Replace(s)
+}
+
+// foldICalendarLine splits the line into lines of at most 75 octets, the continuation lines start with a space
+func foldICalendarLine(line string) string {
+	const maxOctets = 75
+	if len(line) <= maxOctets {
+		return line
+	}
+	var sb strings.Builder
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > maxOctets {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += size
+	}
+	return sb.String()
+}
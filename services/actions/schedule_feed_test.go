@@ -0,0 +1,106 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUpcomingSchedules(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	for _, schedule := range []*actions_model.ActionSchedule{
+		{Title: "nightly", WorkflowID: "nightly.yml", Specs: []string{"0 2 * * *"}},
+		{Title: "disabled", WorkflowID: "disabled.yml", Specs: []string{"0 3 * * *"}},
+		{Title: "weekly", WorkflowID: "weekly.yml", Specs: []string{"0 0 * * 0"}},
+	} {
+		schedule.RepoID = repo.ID
+		schedule.OwnerID = repo.OwnerID
+		require.NoError(t, db.Insert(ctx, schedule))
+		spec := &actions_model.ActionScheduleSpec{RepoID: repo.ID, ScheduleID: schedule.ID, Spec: schedule.Specs[0]}
+		parsed, err := spec.Parse()
+		require.NoError(t, err)
+		spec.Next = timeutil.TimeStamp(parsed.Next(now).Unix())
+		if schedule.WorkflowID == "weekly.yml" {
+			// overdue, it will run at the next tick of the scheduler
+			spec.Next = timeutil.TimeStamp(now.Add(-time.Minute).Unix())
+		}
+		require.NoError(t, db.Insert(ctx, spec))
+	}
+
+	actionsUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	require.NoError(t, err)
+	actionsUnit.ActionsConfig().DisableWorkflow("disabled.yml")
+	require.NoError(t, repo_model.UpdateRepoUnit(ctx, actionsUnit))
+
+	schedules, err := FindUpcomingSchedules(ctx, actions_model.FindSpecOptions{OwnerID: repo.OwnerID}, now, now.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	runs := make(map[string][]time.Time, len(schedules))
+	for _, schedule := range schedules {
+		runs[schedule.Spec.Schedule.WorkflowID] = schedule.Runs
+	}
+	assert.Equal(t, map[string][]time.Time{
+		"nightly.yml": {
+			time.Date(2024, 5, 2, 2, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 3, 2, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 4, 2, 0, 0, 0, time.UTC),
+		},
+		"weekly.yml": {
+			now,
+		},
+	}, runs)
+
+	schedules, err = FindUpcomingSchedules(ctx, actions_model.FindSpecOptions{RepoID: 2}, now, now.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+}
+
+func TestWriteSchedulesICalendar(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	schedules := []*UpcomingSchedule{{
+		Spec: &actions_model.ActionScheduleSpec{
+			ID:   3,
+			Spec: "0 2 * * *",
+			Repo: &repo_model.Repository{OwnerName: "user2", Name: "repo1"},
+			Schedule: &actions_model.ActionSchedule{
+				Title:      "nightly, with a very long title; which has to be folded in the calendar",
+				WorkflowID: "nightly.yml",
+			},
+		},
+		Runs: []time.Time{time.Date(2024, 5, 2, 2, 0, 0, 0, time.UTC)},
+	}}
+
+	var sb strings.Builder
+	require.NoError(t, WriteSchedulesICalendar(&sb, "user2/repo1", schedules, now))
+	ics := sb.String()
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "X-WR-CALNAME:user2/repo1\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20240501T103000Z\r\n")
+	assert.Contains(t, ics, "DTSTART:20240502T020000Z\r\n")
+	assert.Contains(t, ics, "UID:schedule-3-1714615200@")
+	assert.Contains(t, ics, "URL:"+setting.AppURL+"user2/repo1/actions?workflow=nightly.yml\r\n")
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, `SUMMARY:user2/repo1: nightly\, with a very long title\; which has to be folded in the calendar`+"\r\n")
+}
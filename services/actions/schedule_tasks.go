@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
@@ -52,6 +53,23 @@ func startTasks(ctx context.Context) error {
 
 		// Loop through each spec and create a schedule task for it
 		for _, row := range specs {
+			// Parse the spec
+			schedule, err := row.Parse()
+			if err != nil {
+				log.Error("Parse: %v", err)
+				return err
+			}
+
+			// Claim the spec by updating its next run time and previous run time before triggering it,
+			// it could have been claimed by another instance sharing the database
+			next := timeutil.TimeStamp(schedule.Next(now.Add(1 * time.Minute)).Unix())
+			if claimed, err := actions_model.ClaimScheduleSpec(ctx, row, next); err != nil {
+				log.Error("ClaimScheduleSpec: %v", err)
+				return err
+			} else if !claimed {
+				continue
+			}
+
 			// cancel running jobs if the event is push
 			if row.Schedule.Event == webhook_module.HookEventPush {
 				// cancel running jobs of the same workflow
@@ -70,6 +88,10 @@ func startTasks(ctx context.Context) error {
 				// Skip if the repo is archived
 				continue
 			}
+			if actions_module.IsEventBlocked(string(webhook_module.HookEventSchedule)) {
+				// Skip if the schedules are blocked by the instance, the specs are kept to be triggered once they're unblocked
+				continue
+			}
 
 			cfg, err := row.Repo.GetUnit(ctx, unit.TypeActions)
 			if err != nil {
@@ -87,21 +109,6 @@ func startTasks(ctx context.Context) error {
 				log.Error("CreateScheduleTask: %v", err)
 				return err
 			}
-
-			// Parse the spec
-			schedule, err := row.Parse()
-			if err != nil {
-				log.Error("Parse: %v", err)
-				return err
-			}
-
-			// Update the spec's next run time and previous run time
-			row.Prev = row.Next
-			row.Next = timeutil.TimeStamp(schedule.Next(now.Add(1 * time.Minute)).Unix())
-			if err := actions_model.UpdateScheduleSpec(ctx, row, "prev", "next"); err != nil {
-				log.Error("UpdateScheduleSpec: %v", err)
-				return err
-			}
 		}
 
 		// Stop if all specs have been retrieved
@@ -118,18 +125,20 @@ func startTasks(ctx context.Context) error {
 func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule) error {
 	// Create a new action run based on the schedule
 	run := &actions_model.ActionRun{
-		Title:         cron.Title,
-		RepoID:        cron.RepoID,
-		OwnerID:       cron.OwnerID,
-		WorkflowID:    cron.WorkflowID,
-		TriggerUserID: cron.TriggerUserID,
-		Ref:           cron.Ref,
-		CommitSHA:     cron.CommitSHA,
-		Event:         cron.Event,
-		EventPayload:  cron.EventPayload,
-		TriggerEvent:  string(webhook_module.HookEventSchedule),
-		ScheduleID:    cron.ID,
-		Status:        actions_model.StatusWaiting,
+		Title:               cron.Title,
+		RepoID:              cron.RepoID,
+		OwnerID:             cron.OwnerID,
+		WorkflowID:          cron.WorkflowID,
+		TriggerUserID:       cron.TriggerUserID,
+		Ref:                 cron.Ref,
+		CommitSHA:           cron.CommitSHA,
+		Event:               cron.Event,
+		EventPayload:        cron.EventPayload,
+		EventPayloadVersion: cron.EventPayloadVersion,
+		TriggerEvent:        string(webhook_module.HookEventSchedule),
+		ScheduleID:          cron.ID,
+		Component:           actions_module.ParseWorkflowComponent(cron.Content, nil),
+		Status:              actions_model.StatusWaiting,
 	}
 
 	vars, err := actions_model.GetVariablesOfRun(ctx, run)
@@ -144,10 +153,43 @@ func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule)
 		return err
 	}
 
+	if err := checkRequirements(ctx, run, cron.Content, vars); err != nil {
+		return err
+	}
+
+	if err := prepareConcurrency(ctx, run, cron.Content, vars); err != nil {
+		return err
+	}
+
+	preflightErrs, err := preflight(ctx, run, cron.Content, workflows)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := actions_module.ParseJobPermissions(cron.Content)
+	if err != nil {
+		return err
+	}
+
+	environments, err := actions_module.ParseJobEnvironments(cron.Content)
+	if err != nil {
+		return err
+	}
+
+	if err := prepareCheckout(run, cron.Content); err != nil {
+		return err
+	}
+	if err := prepareEgress(ctx, run, cron.Content); err != nil {
+		return err
+	}
+
 	// Insert the action run and its associated jobs into the database
-	if err := actions_model.InsertRun(ctx, run, workflows); err != nil {
+	if err := actions_model.InsertRunWithJobs(ctx, &actions_model.RunWithJobs{Run: run, Jobs: workflows, PreflightErrors: preflightErrs, TokenPermissions: permissions, Environments: environments}); err != nil {
 		return err
 	}
+	if err := EmitOutboxEvents(ctx, run.ID); err != nil {
+		log.Error("EmitOutboxEvents: %v", err)
+	}
 
 	// Return nil if no errors occurred
 	return nil
@@ -0,0 +1,130 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	secret_model "code.gitea.io/gitea/models/secret"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+// minLeakedSecretLength is the shortest value, or line of a multi-line value, searched in the logs,
+// the shorter ones like "true" or "1" would be found everywhere.
+const minLeakedSecretLength = 8
+
+// detectLeakedSecrets searches the values of the secrets provided to the task in the log rows, the runners mask them,
+// so they only appear verbatim if the masking failed. The leaks are recorded and handled by setting.Actions.LeakedSecrets,
+// and the admins of the repository are alerted, never with the values.
+func detectLeakedSecrets(ctx context.Context, task *actions_model.ActionTask, rows []*runnerv1.LogRow) error {
+	if setting.Actions.LeakedSecrets == setting.LeakedSecretsIgnore || len(rows) == 0 {
+		return nil
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		return err
+	}
+	if err := task.Job.LoadAttributes(ctx); err != nil {
+		return err
+	}
+	run := task.Job.Run
+	if !canAccessSecrets(run) {
+		return nil
+	}
+
+	refProtected, err := isRunRefProtected(ctx, run)
+	if err != nil {
+		return err
+	}
+	secrets, err := secret_model.FindSecretsOfRun(ctx, run, refProtected)
+	if err != nil {
+		return err
+	}
+
+	var leaked []string
+	for _, secret := range secrets {
+		value, err := secret.Decrypt()
+		if err != nil {
+			return err
+		}
+		if !isSecretInLogs(value, rows) {
+			continue
+		}
+		recorded, err := secret_model.RecordSecretLeak(ctx, secret, task)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			continue
+		}
+		log.Warn("The value of secret %q was found in the logs of task %d of repo %s", secret.Name, task.ID, run.Repo.FullName())
+		if setting.Actions.LeakedSecrets == setting.LeakedSecretsDelete {
+			if _, err := db.DeleteByID[secret_model.Secret](ctx, secret.ID); err != nil {
+				return err
+			}
+		}
+		leaked = append(leaked, secret.Name)
+	}
+	if len(leaked) == 0 {
+		return nil
+	}
+
+	admins, err := getRepoAdmins(ctx, run.Repo)
+	if err != nil {
+		return err
+	}
+	return mailer.MailActionsSecretsLeaked(ctx, task, leaked, setting.Actions.LeakedSecrets == setting.LeakedSecretsDelete, admins)
+}
+
+// isSecretInLogs returns whether the value of the secret appears verbatim in the log rows,
+// the lines of multi-line values are searched separately, except the boundaries of PEM blocks.
+func isSecretInLogs(value string, rows []*runnerv1.LogRow) bool {
+	var needles []string
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < minLeakedSecretLength || strings.HasPrefix(line, "-----") {
+			continue
+		}
+		needles = append(needles, line)
+	}
+	for _, row := range rows {
+		for _, needle := range needles {
+			if strings.Contains(row.Content, needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getRepoAdmins returns the active users who could administrate the repository
+func getRepoAdmins(ctx context.Context, repo *repo_model.Repository) ([]*user_model.User, error) {
+	users, err := repo_model.GetRepoAssignees(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	admins := make([]*user_model.User, 0, len(users))
+	for _, u := range users {
+		if !u.IsActive || u.ProhibitLogin {
+			continue
+		}
+		isAdmin, err := access_model.IsUserRepoAdmin(ctx, repo, u)
+		if err != nil {
+			return nil, err
+		}
+		if isAdmin {
+			admins = append(admins, u)
+		}
+	}
+	return admins, nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+
+	"github.com/nektos/act/pkg/jobparser"
+)
+
+// SimulateOptions is the event to simulate
+type SimulateOptions struct {
+	Event   webhook_module.HookEventType
+	Ref     string // the ref of the event, a branch or tag name is also accepted
+	Payload string // the JSON payload of the event, a push event of Ref is synthesized if it's empty
+}
+
+// SimulatedJob is a job which would be created by the simulated event
+type SimulatedJob struct {
+	JobID  string
+	Name   string
+	Needs  []string
+	RunsOn []string
+}
+
+// SimulatedWorkflow is a workflow which would be triggered by the simulated event
+type SimulatedWorkflow struct {
+	WorkflowID   string
+	TriggerEvent string
+	Error        string // the workflow would be triggered but its jobs couldn't be parsed
+	Jobs         []*SimulatedJob
+}
+
+// SimulatedTrigger is the result of a simulated event
+type SimulatedTrigger struct {
+	Ref       string
+	CommitID  string
+	Skipped   bool // all workflows are skipped because of a skip string in the commit message or the pull request title
+	Workflows []*SimulatedWorkflow
+}
+
+// SimulateTrigger reports which workflows the event would trigger and which jobs would be created, without creating any runs.
+// The workflows are detected in the same way as a real event, so it's useful to debug the branch and path filters of the workflows.
+func SimulateTrigger(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, opts *SimulateOptions) (*SimulatedTrigger, error) {
+	payload := newPayloadOfEvent(opts.Event)
+	if payload == nil {
+		return nil, util.NewInvalidArgumentErrorf("unsupported event %q", opts.Event)
+	}
+	if opts.Payload == "" && opts.Event != webhook_module.HookEventPush {
+		return nil, util.NewInvalidArgumentErrorf("the payload of event %q is required", opts.Event)
+	}
+	if repo.IsEmpty {
+		return nil, util.NewInvalidArgumentErrorf("repository %s is empty", repo.FullName())
+	}
+	if err := repo.LoadUnits(ctx); err != nil {
+		return nil, err
+	}
+	if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return nil, util.NewInvalidArgumentErrorf("actions of repository %s are disabled", repo.FullName())
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	input := newNotifyInput(repo, doer, opts.Event)
+	if opts.Payload != "" {
+		if err := json.Unmarshal([]byte(opts.Payload), payload); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid payload of event %q: %v", opts.Event, err)
+		}
+		input.WithPayload(payload)
+		if p, ok := payload.(*api.PushPayload); ok {
+			input.WithRef(p.Ref)
+			if p.After != "" && !git.IsEmptyCommitID(p.After) {
+				input.WithCommitID(p.After)
+			}
+		}
+		pr, err := pullRequestOfPayload(ctx, payload)
+		if err != nil && !issues_model.IsErrPullRequestNotExist(err) {
+			return nil, fmt.Errorf("get pull request of payload: %w", err)
+		}
+		if pr != nil {
+			input.WithPullRequest(pr)
+		}
+	}
+	if opts.Ref != "" {
+		input.WithRef(fullRefName(gitRepo, opts.Ref))
+	}
+
+	ref := input.workflowRef()
+	commitID := input.CommitID
+	if commitID == "" {
+		commitID = ref
+	}
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, util.NewNotExistErrorf("commit %s doesn't exist", commitID)
+		}
+		return nil, fmt.Errorf("GetCommit: %w", err)
+	}
+
+	if opts.Payload == "" {
+		// a push of the commit
+		before := commit.ID.Type().EmptyObjectID().String()
+		if parent, err := commit.ParentID(0); err == nil {
+			before = parent.String()
+		}
+		if input.Ref == "" {
+			input.WithRef(git.RefNameFromBranch(repo.DefaultBranch).String())
+		}
+		pusher := convert.ToUser(ctx, doer, nil)
+		input.WithPayload(&api.PushPayload{
+			Ref:    input.Ref,
+			Before: before,
+			After:  commit.ID.String(),
+			Repo:   convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm_model.AccessModeOwner}),
+			Pusher: pusher,
+			Sender: pusher,
+		})
+	}
+
+	result := &SimulatedTrigger{
+		Ref:       ref,
+		CommitID:  commit.ID.String(),
+		Workflows: []*SimulatedWorkflow{},
+	}
+	if skipWorkflows(input, commit) {
+		result.Skipped = true
+		return result, nil
+	}
+
+	workflows, _, _, err := detectTriggeredWorkflows(ctx, gitRepo, commit, input, false)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := actions_model.GetVariablesOfRun(ctx, &actions_model.ActionRun{RepoID: repo.ID, OwnerID: repo.OwnerID, Repo: repo})
+	if err != nil {
+		return nil, fmt.Errorf("GetVariablesOfRun: %w", err)
+	}
+	for _, wf := range workflows {
+		simulated := &SimulatedWorkflow{
+			WorkflowID:   wf.EntryName,
+			TriggerEvent: wf.TriggerEvent.Name,
+			Jobs:         []*SimulatedJob{},
+		}
+		result.Workflows = append(result.Workflows, simulated)
+
+		jobs, err := jobparser.Parse(wf.Content, jobparser.WithVars(vars))
+		if err != nil {
+			simulated.Error = err.Error()
+			continue
+		}
+		for _, v := range jobs {
+			id, job := v.Job()
+			simulated.Jobs = append(simulated.Jobs, &SimulatedJob{
+				JobID:  id,
+				Name:   job.Name,
+				Needs:  job.Needs(),
+				RunsOn: job.RunsOn(),
+			})
+		}
+	}
+	return result, nil
+}
+
+// fullRefName returns the full name of the ref, the name of a branch or tag is expanded
+func fullRefName(gitRepo *git.Repository, ref string) string {
+	switch {
+	case strings.HasPrefix(ref, "refs/"):
+		return ref
+	case gitRepo.IsBranchExist(ref):
+		return git.RefNameFromBranch(ref).String()
+	case gitRepo.IsTagExist(ref):
+		return git.RefNameFromTag(ref).String()
+	}
+	return ref
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateTrigger(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	// a push of the branch is synthesized, the name of the branch is expanded
+	result, err := SimulateTrigger(ctx, repo, doer, &SimulateOptions{Event: webhook_module.HookEventPush, Ref: "branch2"})
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/branch2", result.Ref)
+	assert.Equal(t, "985f0301dba5e7b34be866819cd15ad3d8f508ee", result.CommitID)
+	assert.False(t, result.Skipped)
+	assert.Empty(t, result.Workflows)
+
+	// the commit of the push payload is used
+	result, err = SimulateTrigger(ctx, repo, doer, &SimulateOptions{
+		Event:   webhook_module.HookEventPush,
+		Payload: `{"ref":"refs/tags/v1.1","after":"65f1bf27bc3bf70f64657658635e66094edbcb4d"}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "refs/tags/v1.1", result.Ref)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", result.CommitID)
+
+	// the events only triggering the workflows of the default branch
+	result, err = SimulateTrigger(ctx, repo, doer, &SimulateOptions{
+		Event:   webhook_module.HookEventIssues,
+		Ref:     "branch2",
+		Payload: `{"action":"opened","issue":{"id":1}}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, repo.DefaultBranch, result.Ref)
+
+	_, err = SimulateTrigger(ctx, repo, doer, &SimulateOptions{Event: webhook_module.HookEventSchedule})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = SimulateTrigger(ctx, repo, doer, &SimulateOptions{Event: webhook_module.HookEventPullRequest})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = SimulateTrigger(ctx, repo, doer, &SimulateOptions{Event: webhook_module.HookEventPush, Payload: `{`})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = SimulateTrigger(ctx, repo, doer, &SimulateOptions{Event: webhook_module.HookEventPush, Ref: "no-such-branch"})
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
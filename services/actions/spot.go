@@ -0,0 +1,39 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// retryJobLostWithSpotRunner retries the job of the lost task on the other runners if the task was run by a spot runner,
+// unless the job requires the spot runners. It returns whether the job is retried, the job of the task must be loaded.
+func retryJobLostWithSpotRunner(ctx context.Context, task *actions_model.ActionTask) (bool, error) {
+	job := task.Job
+	if job.RequiresSpotRunners() {
+		return false, nil
+	}
+	if isSpot, err := actions_model.IsTaskRunBySpotRunner(ctx, task); err != nil {
+		return false, fmt.Errorf("IsTaskRunBySpotRunner: %w", err)
+	} else if !isSpot {
+		return false, nil
+	}
+
+	if err := job.LoadRun(ctx); err != nil {
+		return false, fmt.Errorf("LoadRun: %w", err)
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, job.RunID)
+	if err != nil {
+		return false, fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+	if err := RerunJobs(ctx, job.Run, jobs, job, &RerunOptions{AvoidSpotRunners: true}); err != nil {
+		return false, fmt.Errorf("RerunJobs: %w", err)
+	}
+	log.Info("Job %d is retried on the non-spot runners since task %d was lost with spot runner %d", job.ID, task.ID, task.RunnerID)
+	return true, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryJobLostWithSpotRunner(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	const sha = "c7cd3cd144e6d23c9d6f3d07e52b2c1a956e0338" // the head of repo 4
+
+	// task 48 of job 193 is run by a spot runner which has been deleted since it disappeared, task 47 of job 192 by runner 1 which is unknown
+	spot := &actions_model.ActionRunner{ID: 1801, UUID: "spot-runner", Name: "spot", TokenHash: "spot-runner", AgentLabels: []string{"linux", "spot"}}
+	require.NoError(t, db.Insert(ctx, spot))
+	_, err := db.DeleteByID[actions_model.ActionRunner](ctx, spot.ID)
+	require.NoError(t, err)
+	_, err = db.GetEngine(ctx).Exec("UPDATE action_task SET runner_id = ? WHERE id = 48", spot.ID)
+	require.NoError(t, err)
+	_, err = db.GetEngine(ctx).Exec("UPDATE action_run_job SET status = ? WHERE id IN (192, 193)", actions_model.StatusRunning)
+	require.NoError(t, err)
+	_, err = db.GetEngine(ctx).Exec("UPDATE action_run SET status = ?, event_payload = ? WHERE id IN (791, 792)", actions_model.StatusRunning, `{"head_commit":{"id":"`+sha+`"}}`)
+	require.NoError(t, err)
+	// the jobs are named differently, so their commit statuses don't share the context
+	_, err = db.GetEngine(ctx).Exec("UPDATE action_run_job SET name = 'job_1' WHERE id = 192")
+	require.NoError(t, err)
+
+	require.NoError(t, stopTasks(ctx, actions_model.FindTaskOptions{Status: actions_model.StatusRunning}, actions_model.TaskErrorClassInfrastructure))
+
+	// the job lost with the spot runner is retried on the other runners
+	job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 193})
+	assert.Equal(t, actions_model.StatusWaiting, job.Status)
+	assert.EqualValues(t, 0, job.TaskID)
+	assert.True(t, job.AvoidSpotRunners)
+	// the job lost with the other runner fails
+	job = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+	assert.Equal(t, actions_model.StatusFailure, job.Status)
+	assert.False(t, job.AvoidSpotRunners)
+
+	// the commit status of the retried job is pending instead of the failure of the lost task
+	statuses, _, err := git_model.GetLatestCommitStatus(ctx, 4, sha, db.ListOptionsAll)
+	require.NoError(t, err)
+	states := make(map[string]api.CommitStatusState, len(statuses))
+	for _, status := range statuses {
+		states[status.Context] = status.State
+	}
+	assert.Equal(t, api.CommitStatusPending, states["artifact.yaml / job_2 (push)"])
+	assert.Equal(t, api.CommitStatusFailure, states["artifact.yaml / job_1 (push)"])
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+)
+
+// The names of the storages of Actions, which are the names of their sections in the config
+const (
+	StorageNameLogs      = "actions_log"
+	StorageNameArtifacts = "actions_artifacts"
+)
+
+var (
+	unhealthyStorages   = map[string]bool{}
+	unhealthyStoragesMu sync.Mutex
+)
+
+// checkStorages checks the health of the storages of the logs and the artifacts
+func checkStorages() []*storage.HealthStatus {
+	return []*storage.HealthStatus{
+		storage.CheckHealth(StorageNameLogs, storage.Actions),
+		storage.CheckHealth(StorageNameArtifacts, storage.ActionsArtifacts),
+	}
+}
+
+func storageDescription(name string) string {
+	cfg := setting.Actions.LogStorage
+	if name == StorageNameArtifacts {
+		cfg = setting.Actions.ArtifactStorage
+	}
+	if cfg.Type == setting.MinioStorageType {
+		return fmt.Sprintf("%s storage (minio %s/%s)", name, cfg.MinioConfig.Endpoint, cfg.MinioConfig.Bucket)
+	}
+	return fmt.Sprintf("%s storage (%s %s)", name, cfg.Type, cfg.Path)
+}
+
+// checkStoragesAtStart fails if the storages of Actions are unavailable when Gitea starts,
+// so they are fixed before the runs break when they upload their logs or artifacts.
+func checkStoragesAtStart() error {
+	var errs []error
+	for _, status := range checkStorages() {
+		if !status.Healthy {
+			errs = append(errs, fmt.Errorf("%s is unavailable: %s", storageDescription(status.Name), status.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckStorageHealth checks the health of the storages of Actions, the admins are notified by system notices
+// when a storage becomes unavailable and when it recovers.
+func CheckStorageHealth(ctx context.Context) error {
+	unhealthyStoragesMu.Lock()
+	defer unhealthyStoragesMu.Unlock()
+
+	var errs []error
+	for _, status := range checkStorages() {
+		desc := storageDescription(status.Name)
+		if !status.Healthy {
+			errs = append(errs, fmt.Errorf("%s is unavailable: %s", desc, status.Error))
+		}
+		if status.Healthy == !unhealthyStorages[status.Name] {
+			continue
+		}
+
+		var msg string
+		if status.Healthy {
+			delete(unhealthyStorages, status.Name)
+			msg = fmt.Sprintf("Actions %s has recovered", desc)
+			log.Info("%s", msg)
+		} else {
+			unhealthyStorages[status.Name] = true
+			msg = fmt.Sprintf("Actions %s is unavailable: %s", desc, status.Error)
+			log.Error("%s", msg)
+		}
+		if err := system_model.CreateNotice(ctx, system_model.NoticeTask, msg); err != nil {
+			log.Error("CreateNotice: %v", err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetStorageHealth returns the latest health checks of the storages of Actions by this instance
+func GetStorageHealth() []*storage.HealthStatus {
+	var statuses []*storage.HealthStatus
+	for _, status := range storage.GetHealthStatuses() {
+		if status.Name == StorageNameLogs || status.Name == StorageNameArtifacts {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStorageHealth(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	logs, err := storage.NewLocalStorage(context.Background(), &setting.Storage{Path: dir})
+	require.NoError(t, err)
+	artifacts, err := storage.NewLocalStorage(context.Background(), &setting.Storage{Path: t.TempDir()})
+	require.NoError(t, err)
+	defer test.MockVariableValue(&storage.Actions, logs)()
+	defer test.MockVariableValue(&storage.ActionsArtifacts, artifacts)()
+	defer test.MockVariableValue(&setting.Actions.LogStorage, &setting.Storage{Type: setting.LocalStorageType, Path: dir})()
+
+	notices := unittest.GetCount(t, &system_model.Notice{})
+	require.NoError(t, checkStoragesAtStart())
+	require.NoError(t, CheckStorageHealth(ctx))
+	unittest.AssertCount(t, &system_model.Notice{}, notices)
+
+	// the directory of the logs becomes a file, so nothing could be saved
+	require.NoError(t, os.RemoveAll(dir))
+	require.NoError(t, os.WriteFile(dir, nil, 0o644))
+	assert.ErrorContains(t, CheckStorageHealth(ctx), "actions_log storage (local "+dir+") is unavailable")
+	unittest.AssertCount(t, &system_model.Notice{}, notices+1)
+	statuses := GetStorageHealth()
+	if assert.Len(t, statuses, 2) {
+		assert.Equal(t, StorageNameArtifacts, statuses[0].Name)
+		assert.True(t, statuses[0].Healthy)
+		assert.Equal(t, StorageNameLogs, statuses[1].Name)
+		assert.False(t, statuses[1].Healthy)
+	}
+
+	// the admins are notified once
+	assert.Error(t, CheckStorageHealth(ctx))
+	unittest.AssertCount(t, &system_model.Notice{}, notices+1)
+
+	require.NoError(t, os.Remove(dir))
+	require.NoError(t, CheckStorageHealth(ctx))
+	unittest.AssertCount(t, &system_model.Notice{}, notices+2)
+	unittest.AssertExistsAndLoadBean(t, &system_model.Notice{Description: "Actions actions_log storage (local " + dir + ") has recovered"})
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bufio"
+	"context"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// maxGitmodulesSize is the largest .gitmodules file which is parsed for the submodules
+const maxGitmodulesSize = 64 * 1024
+
+// CanTaskReadSubmodule returns whether the token of the task could read the repository as a submodule of the repository of its run.
+// It's allowed when the submodule token scope of the repository of the run covers the repository, the commit of the run declares it
+// as a submodule, and the user triggering the run could read its code, so the token never reads more than the user could.
+func CanTaskReadSubmodule(ctx context.Context, task *actions_model.ActionTask, repo *repo_model.Repository) (bool, error) {
+	if err := task.LoadJob(ctx); err != nil {
+		return false, err
+	}
+	if err := task.Job.LoadAttributes(ctx); err != nil {
+		return false, err
+	}
+	run := task.Job.Run
+	if run.IsExternal() {
+		return false, nil
+	}
+
+	cfgUnit, err := run.Repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch cfgUnit.ActionsConfig().GetSubmoduleTokenScope() {
+	case repo_model.ActionsSubmoduleTokenScopeInstance:
+	case repo_model.ActionsSubmoduleTokenScopeOwner:
+		if repo.OwnerID != run.Repo.OwnerID {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+
+	gitmodules, err := readGitmodules(ctx, run.Repo, run.CommitSHA)
+	if err != nil || gitmodules == "" {
+		return false, err
+	}
+	if !isSubmoduleRepo(gitmodules, run.Repo.FullName(), repo) {
+		return false, nil
+	}
+
+	triggerUser, err := user_model.GetPossibleUserByID(ctx, run.TriggerUserID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, triggerUser)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanRead(unit.TypeCode), nil
+}
+
+// readGitmodules returns the content of the .gitmodules file of the commit, it's empty if there isn't one
+func readGitmodules(ctx context.Context, repo *repo_model.Repository, commitSHA string) (string, error) {
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(commitSHA)
+	if err != nil {
+		return "", err
+	}
+	content, err := commit.GetFileContent(".gitmodules", maxGitmodulesSize)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return content, nil
+}
+
+// isSubmoduleRepo returns whether the .gitmodules content of the repository declares a submodule hosted by the repository on this instance,
+// the relative URLs are resolved against the repository declaring them
+func isSubmoduleRepo(gitmodules, repoFullName string, repo *repo_model.Repository) bool {
+	target := strings.ToLower(strings.TrimSuffix(repo.HTMLURL(), "/"))
+	for _, u := range submoduleURLs(gitmodules) {
+		ref := git.NewSubModuleFile(nil, u, "").RefURL(setting.AppURL, repoFullName, setting.SSH.Domain)
+		if strings.ToLower(strings.TrimSuffix(ref, "/")) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// submoduleURLs returns the URLs of the submodules declared by the .gitmodules content
+func submoduleURLs(gitmodules string) []string {
+	var urls []string
+	inSubmodule := false
+	scanner := bufio.NewScanner(strings.NewReader(gitmodules))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSubmodule = strings.HasPrefix(line, "[submodule")
+			continue
+		}
+		if !inSubmodule {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "url" {
+			if value = strings.Trim(strings.TrimSpace(value), `"`); value != "" {
+				urls = append(urls, value)
+			}
+		}
+	}
+	return urls
+}
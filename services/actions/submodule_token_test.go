@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmoduleURLs(t *testing.T) {
+	gitmodules := `[core]
+	url = https://example.com/ignored.git
+[submodule "lib"]
+	path = lib
+	url = ../lib.git
+[submodule "vendor/tool"]
+	path = vendor/tool
+	url = "https://example.com/org/tool.git"
+	branch = main
+`
+	assert.Equal(t, []string{"../lib.git", "https://example.com/org/tool.git"}, submoduleURLs(gitmodules))
+	assert.Empty(t, submoduleURLs(""))
+}
+
+func TestIsSubmoduleRepo(t *testing.T) {
+	repo := &repo_model.Repository{OwnerName: "org3", Name: "repo3"}
+
+	assert.True(t, isSubmoduleRepo("[submodule \"a\"]\n\tpath = a\n\turl = ../../org3/repo3.git\n", "user2/repo1", repo))
+	assert.True(t, isSubmoduleRepo("[submodule \"a\"]\n\tpath = a\n\turl = "+setting.AppURL+"org3/Repo3.git\n", "user2/repo1", repo))
+	assert.False(t, isSubmoduleRepo("[submodule \"a\"]\n\tpath = a\n\turl = ../repo3.git\n", "user2/repo1", repo))
+	assert.False(t, isSubmoduleRepo("[submodule \"a\"]\n\tpath = a\n\turl = https://example.com/org3/repo3.git\n", "user2/repo1", repo))
+}
+
+func TestCanTaskReadSubmodule(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	// the tokens don't read the submodules unless the repository of the run allows
+	ok, err := CanTaskReadSubmodule(db.DefaultContext, task, repo)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
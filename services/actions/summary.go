@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/container"
+)
+
+// GetRunSummary aggregates the summaries of the latest tasks of the jobs of the run into one markdown document,
+// every job which has written a summary gets a section headed by its name, in the order of the jobs.
+// It's empty if no job has written a summary.
+func GetRunSummary(ctx context.Context, run *actions_model.ActionRun) (string, error) {
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return "", err
+	}
+	taskIDs := container.FilterSlice(jobs, func(job *actions_model.ActionRunJob) (int64, bool) {
+		return job.TaskID, job.TaskID > 0
+	})
+	summaries, err := actions_model.GetTaskSummariesByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return "", err
+	}
+
+	sections := make([]string, 0, len(summaries))
+	for _, job := range jobs {
+		summary := summaries[job.TaskID]
+		if summary == nil {
+			continue
+		}
+		content := strings.TrimSpace(summary.Content)
+		if content == "" {
+			continue
+		}
+		sections = append(sections, fmt.Sprintf("## %s\n\n%s\n", job.Name, content))
+	}
+	return strings.Join(sections, "\n"), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRunSummary(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	run := &actions_model.ActionRun{
+		Index:         1003,
+		Title:         "summary",
+		RepoID:        4,
+		OwnerID:       1,
+		WorkflowID:    "test.yml",
+		TriggerUserID: 1,
+		Ref:           "refs/heads/master",
+		CommitSHA:     "c2d72f548424103f01ee1dc02889c1e2bff816b0",
+		Event:         "push",
+		Status:        actions_model.StatusSuccess,
+	}
+	require.NoError(t, db.Insert(ctx, run))
+
+	summary, err := GetRunSummary(ctx, run)
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+
+	for i, job := range []struct {
+		name    string
+		taskID  int64
+		summary string
+	}{
+		{name: "build", taskID: 2001, summary: "Built `app`\n"},
+		{name: "lint", taskID: 2002},
+		{name: "test", taskID: 2003, summary: "| passed | failed |\n|---|---|\n| 10 | 0 |\n"},
+		{name: "deploy", taskID: 2004, summary: "\n  \n"},
+		{name: "notify"},
+	} {
+		require.NoError(t, db.Insert(ctx, &actions_model.ActionRunJob{
+			RunID:     run.ID,
+			RepoID:    run.RepoID,
+			OwnerID:   run.OwnerID,
+			CommitSHA: run.CommitSHA,
+			Name:      job.name,
+			JobID:     job.name,
+			TaskID:    job.taskID,
+			Status:    actions_model.StatusSuccess,
+		}), i)
+		if job.summary != "" {
+			require.NoError(t, db.Insert(ctx, &actions_model.ActionTaskSummary{
+				TaskID:  job.taskID,
+				RunID:   run.ID,
+				RepoID:  run.RepoID,
+				Content: job.summary,
+			}))
+		}
+	}
+	// the summary of a previous attempt of the job isn't included
+	require.NoError(t, db.Insert(ctx, &actions_model.ActionTaskSummary{
+		TaskID:  2000,
+		RunID:   run.ID,
+		RepoID:  run.RepoID,
+		Content: "Build failed",
+	}))
+
+	summary, err = GetRunSummary(ctx, run)
+	require.NoError(t, err)
+	assert.Equal(t, "## build\n\nBuilt `app`\n\n## test\n\n| passed | failed |\n|---|---|\n| 10 | 0 |\n", summary)
+}
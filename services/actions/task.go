@@ -1,14 +1,18 @@
 // Copyright 2022 The Gitea Authors. All rights reserved.
 // SPDX-License-Identifier: MIT
 
-package runner
+package actions
 
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	container_model "code.gitea.io/gitea/models/packages/container"
 	secret_model "code.gitea.io/gitea/models/secret"
 	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/container"
@@ -16,13 +20,14 @@ import (
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/modules/util"
 
 	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
-func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv1.Task, bool, error) {
+// PickTask assigns a waiting job to the runner, and returns the definition of the created task
+func PickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv1.Task, bool, error) {
 	t, ok, err := actions_model.CreateTaskForRunner(ctx, runner)
 	if err != nil {
 		return nil, false, fmt.Errorf("CreateTaskForRunner: %w", err)
@@ -31,7 +36,11 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 		return nil, false, nil
 	}
 
-	secrets, err := secret_model.GetSecretsOfTask(ctx, t)
+	refProtected, err := isRunRefProtected(ctx, t.Job.Run)
+	if err != nil {
+		return nil, false, fmt.Errorf("isRunRefProtected: %w", err)
+	}
+	secrets, err := secret_model.GetSecretsOfTask(ctx, t, refProtected)
 	if err != nil {
 		return nil, false, fmt.Errorf("GetSecretsOfTask: %w", err)
 	}
@@ -40,13 +49,26 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 	if err != nil {
 		return nil, false, fmt.Errorf("GetVariablesOfRun: %w", err)
 	}
+	vars = t.Overrides.ApplyVars(vars)
 
-	actions.CreateCommitStatus(ctx, t.Job)
+	payload, err := actions_model.TranslateShellDefaults(t.Job.WorkflowPayload)
+	if err != nil {
+		log.Error("Cannot translate shell defaults for task %v: %v", t.ID, err)
+		payload = t.Job.WorkflowPayload
+	}
+	taskContext := generateTaskContext(ctx, runner, t)
+	if withEnv, err := actions_module.ApplyDefaultEnv(payload, taskContext.AsMap()); err != nil {
+		log.Error("Cannot apply default env for task %v: %v", t.ID, err)
+	} else {
+		payload = withEnv
+	}
+
+	CreateCommitStatus(ctx, t.Job)
 
 	task := &runnerv1.Task{
 		Id:              t.ID,
-		WorkflowPayload: t.Job.WorkflowPayload,
-		Context:         generateTaskContext(t),
+		WorkflowPayload: payload,
+		Context:         taskContext,
 		Secrets:         secrets,
 		Vars:            vars,
 	}
@@ -64,9 +86,149 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 	return task, true, nil
 }
 
-func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
+// isRunRefProtected returns whether the run is triggered by a protected branch or tag.
+// The runs triggered by pull requests, including pull_request_target, have refs like "refs/pull/1/head",
+// so they are never treated as protected, since the code of the pull requests may be checked out and executed by them.
+func isRunRefProtected(ctx context.Context, run *actions_model.ActionRun) (bool, error) {
+	return git_model.IsRefProtected(ctx, run.RepoID, git.RefName(run.Ref))
+}
+
+// UpdateTaskByState updates the task with the state and outputs reported by the executor,
+// it returns the updated task and the keys of the outputs which have been saved.
+func UpdateTaskByState(ctx context.Context, state *runnerv1.TaskState, outputs map[string]string) (*actions_model.ActionTask, []string, error) {
+	task, err := actions_model.UpdateTaskByState(ctx, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for k, v := range outputs {
+		if len(k) > 255 {
+			log.Warn("Ignore the output of task %d because the key is too long: %q", task.ID, k)
+			continue
+		}
+		// The value can be a maximum of 1 MB
+		if l := len(v); l > 1024*1024 {
+			log.Warn("Ignore the output %q of task %d because the value is too long: %v", k, task.ID, l)
+			continue
+		}
+		// There's another limitation on GitHub that the total of all outputs in a workflow run can be a maximum of 50 MB.
+		// We don't check the total size here because it's not easy to do, and it doesn't really worth it.
+		// See https://docs.github.com/en/actions/using-jobs/defining-outputs-for-jobs
+
+		if err := actions_model.InsertTaskOutputIfNotExist(ctx, task.ID, k, v); err != nil {
+			log.Warn("Failed to insert the output %q of task %d: %v", k, task.ID, err)
+			// It's ok not to return errors, the runner will resend the outputs.
+		}
+	}
+	sentOutputs, err := actions_model.FindTaskOutputKeyByTaskID(ctx, task.ID)
+	if err != nil {
+		log.Warn("Failed to find the sent outputs of task %d: %v", task.ID, err)
+		// It's not to return errors, it can be handled when the runner resends sent outputs.
+	}
+
+	if err := task.LoadJob(ctx); err != nil {
+		return nil, nil, fmt.Errorf("load job: %w", err)
+	}
+	if err := task.Job.LoadRun(ctx); err != nil {
+		return nil, nil, fmt.Errorf("load run: %w", err)
+	}
+
+	// don't create commit status for cron job
+	if task.Job.Run.ScheduleID == 0 {
+		CreateCommitStatus(ctx, task.Job)
+	}
+
+	if state.Result != runnerv1.Result_RESULT_UNSPECIFIED {
+		if err := EmitJobsIfReady(task.Job.RunID); err != nil {
+			log.Error("Emit ready jobs of run %d: %v", task.Job.RunID, err)
+		}
+		if task.Job.Run.Status.IsDone() {
+			if err := EmitOutboxEvents(ctx, task.Job.RunID); err != nil {
+				log.Error("EmitOutboxEvents: %v", err)
+			}
+		}
+	}
+
+	return task, sentOutputs, nil
+}
+
+// AppendTaskLogs appends the log rows starting at index to the logs of the task, and returns the acknowledged index.
+// If noMore is true, the logs will be transferred to the storage, and no more rows can be appended.
+func AppendTaskLogs(ctx context.Context, taskID, index int64, rows []*runnerv1.LogRow, noMore bool) (int64, error) {
+	task, err := actions_model.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return 0, fmt.Errorf("get task: %w", err)
+	}
+	ack := task.LogLength
+
+	if len(rows) == 0 || index > ack || int64(len(rows))+index <= ack {
+		return ack, nil
+	}
+
+	if task.LogInStorage {
+		return 0, util.NewAlreadyExistErrorf("log file has been archived")
+	}
+
+	rows = rows[ack-index:]
+	if limit := setting.Actions.LogUploadMaxRows; limit > 0 && int64(len(rows)) > limit {
+		// the rows beyond the limit aren't acknowledged, the runner uploads them again with the next request
+		rows = rows[:limit]
+		noMore = false
+	}
+	ns, err := actions_module.WriteLogs(ctx, task.LogFilename, task.LogSize, rows)
+	if err != nil {
+		return 0, fmt.Errorf("write logs: %w", err)
+	}
+	task.LogLength += int64(len(rows))
+	for _, n := range ns {
+		task.LogIndexes = append(task.LogIndexes, task.LogSize)
+		task.LogSize += int64(n)
+	}
+
+	var remove func()
+	if noMore {
+		task.LogInStorage = true
+		remove, err = actions_module.TransferLogs(ctx, task.LogFilename)
+		if err != nil {
+			return 0, fmt.Errorf("transfer logs: %w", err)
+		}
+	}
+
+	if err := actions_model.UpdateTask(ctx, task, "log_indexes", "log_length", "log_size", "log_in_storage"); err != nil {
+		return 0, fmt.Errorf("update task: %w", err)
+	}
+	if remove != nil {
+		remove()
+		// the structured logs are parsed from the logs when they're read if they fail to be saved
+		if err := saveStructuredTaskLogs(ctx, task); err != nil {
+			log.Error("Failed to save the structured logs of task %d: %v", task.ID, err)
+		}
+	}
+
+	for _, row := range rows {
+		if actions_module.IsInfrastructureErrorLog(row.Content) {
+			if err := actions_model.SetTaskErrorClassHint(ctx, task, actions_model.TaskErrorClassInfrastructure); err != nil {
+				log.Warn("Failed to set the error class of task %d: %v", task.ID, err)
+			}
+			break
+		}
+	}
+
+	if err := detectLeakedSecrets(ctx, task, rows); err != nil {
+		log.Error("Failed to detect the leaked secrets in the logs of task %d: %v", task.ID, err)
+	}
+
+	return task.LogLength, nil
+}
+
+func generateTaskContext(ctx context.Context, runner *actions_model.ActionRunner, t *actions_model.ActionTask) *structpb.Struct {
 	event := map[string]any{}
-	_ = json.Unmarshal([]byte(t.Job.Run.EventPayload), &event)
+	if payload, err := t.Job.Run.GetEventPayload(); err != nil {
+		log.Error("GetEventPayload of run %d: %v", t.Job.Run.ID, err)
+	} else {
+		_ = json.Unmarshal([]byte(payload), &event)
+	}
+	t.Overrides.ApplyInputs(event)
 
 	// TriggerEvent is added in https://github.com/go-gitea/gitea/pull/25229
 	// This fallback is for the old ActionRun that doesn't have the TriggerEvent field
@@ -95,9 +257,14 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 
 	refName := git.RefName(ref)
 
-	giteaRuntimeToken, err := actions.CreateAuthorizationToken(t.ID, t.Job.RunID, t.JobID)
+	protectionRules, err := getRefProtectionRules(ctx, t.Job.Run.RepoID, refName)
 	if err != nil {
-		log.Error("actions.CreateAuthorizationToken failed: %v", err)
+		log.Error("getRefProtectionRules failed: %v", err)
+	}
+
+	giteaRuntimeToken, err := CreateAuthorizationToken(t.ID, t.Job.RunID, t.JobID)
+	if err != nil {
+		log.Error("CreateAuthorizationToken failed: %v", err)
 	}
 
 	taskContext, err := structpb.NewStruct(map[string]any{
@@ -119,7 +286,7 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 		"job":               fmt.Sprint(t.JobID),                                  // string, The job_id of the current job.
 		"ref":               ref,                                                  // string, The fully-formed ref of the branch or tag that triggered the workflow run. For workflows triggered by push, this is the branch or tag ref that was pushed. For workflows triggered by pull_request, this is the pull request merge branch. For workflows triggered by release, this is the release tag created. For other triggers, this is the branch or tag ref that triggered the workflow run. This is only set if a branch or tag is available for the event type. The ref given is fully-formed, meaning that for branches the format is refs/heads/<branch_name>, for pull requests it is refs/pull/<pr_number>/merge, and for tags it is refs/tags/<tag_name>. For example, refs/heads/feature-branch-1.
 		"ref_name":          refName.ShortName(),                                  // string, The short ref name of the branch or tag that triggered the workflow run. This value matches the branch or tag name shown on GitHub. For example, feature-branch-1.
-		"ref_protected":     false,                                                // boolean, true if branch protections are configured for the ref that triggered the workflow run.
+		"ref_protected":     len(protectionRules) > 0,                             // boolean, true if branch protections are configured for the ref that triggered the workflow run.
 		"ref_type":          refName.RefType(),                                    // string, The type of ref that triggered the workflow run. Valid values are branch or tag.
 		"path":              "",                                                   // string, Path on the runner to the file that sets system PATH variables from workflow commands. This file is unique to the current step and is a different file for each step in a job. For more information, see "Workflow commands for GitHub Actions."
 		"repository":        t.Job.Run.Repo.OwnerName + "/" + t.Job.Run.Repo.Name, // string, The owner and repository name. For example, Codertocat/Hello-World.
@@ -133,13 +300,24 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 		"server_url":        setting.AppURL,                                       // string, The URL of the GitHub server. For example: https://github.com.
 		"sha":               sha,                                                  // string, The commit SHA that triggered the workflow. The value of this commit SHA depends on the event that triggered the workflow. For more information, see "Events that trigger workflows." For example, ffac537e6cbbf934b08745a378932722df287a53.
 		"token":             t.Token,                                              // string, A token to authenticate on behalf of the GitHub App installed on your repository. This is functionally equivalent to the GITHUB_TOKEN secret. For more information, see "Automatic token authentication."
-		"triggering_actor":  "",                                                   // string, The username of the user that initiated the workflow run. If the workflow run is a re-run, this value may differ from github.actor. Any workflow re-runs will use the privileges of github.actor, even if the actor initiating the re-run (github.triggering_actor) has different privileges.
+		"triggering_actor":  t.Job.Run.TriggerUser.Name,                           // string, The username of the user that initiated the workflow run. If the workflow run is a re-run, this value may differ from github.actor. Any workflow re-runs will use the privileges of github.actor, even if the actor initiating the re-run (github.triggering_actor) has different privileges.
 		"workflow":          t.Job.Run.WorkflowID,                                 // string, The name of the workflow. If the workflow file doesn't specify a name, the value of this property is the full path of the workflow file in the repository.
 		"workspace":         "",                                                   // string, The default working directory on the runner for steps, and the default location of your repository when using the checkout action.
 
+		// documented by GitHub but unknown to the runners, only used by the default env, see actions_module.ApplyDefaultEnv
+		"actor_id":            fmt.Sprint(t.Job.Run.TriggerUserID),
+		"repository_id":       fmt.Sprint(t.Job.Run.RepoID),
+		"repository_owner_id": fmt.Sprint(t.Job.Run.Repo.OwnerID),
+
 		// additional contexts
-		"gitea_default_actions_url": setting.Actions.DefaultActionsURL.URL(),
-		"gitea_runtime_token":       giteaRuntimeToken,
+		"gitea_default_actions_url":  setting.Actions.DefaultActionsURL.URL(),
+		"gitea_runtime_token":        giteaRuntimeToken,
+		"gitea_ref_protection_rules": protectionRules,            // the names of the branch protection rule or the patterns of the protected tags which apply to the ref
+		"gitea_checkout":             checkoutContext(t.Job.Run), // the checkout hints of the workflow with the links to download the commit, see checkoutContext
+		"gitea_lfs":                  lfsContext(ctx, t),         // the LFS server of the repository with the authorization header of the task, see lfsContext
+		"gitea_run_registry":         runRegistry(t.Job.Run),     // the scratch namespace of the run in the container registry, see runRegistry
+		"gitea_sandbox_policy":       sandboxContext(runner),     // the restrictions of the sandbox of the runner, see ActionRunner.SandboxPolicy
+		"gitea_egress":               egressContext(t.Job.Run),   // the egress policy of the run with the url to report the blocked connections, see egressContext
 	})
 	if err != nil {
 		log.Error("structpb.NewStruct failed: %v", err)
@@ -148,6 +326,50 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 	return taskContext
 }
 
+// sandboxContext returns the restrictions of the sandbox policy of the runner as a list of the task context
+func sandboxContext(runner *actions_model.ActionRunner) []any {
+	restrictions := runner.SandboxPolicy()
+	policy := make([]any, 0, len(restrictions))
+	for _, restriction := range restrictions {
+		policy = append(policy, restriction)
+	}
+	return policy
+}
+
+// runRegistry returns the scratch namespace of the run in the container registry like "gitea.com/owner/actions-run-1",
+// where the jobs could push the temporary images which are removed when the run is done, or empty if the packages are disabled
+func runRegistry(run *actions_model.ActionRun) string {
+	if !setting.Packages.Enabled {
+		return ""
+	}
+	return path.Join(setting.Packages.RegistryHost, strings.ToLower(run.Repo.OwnerName), strings.TrimSuffix(container_model.RunScratchImagePrefix(run.ID), "/"))
+}
+
+// getRefProtectionRules returns the names of the protection rules which apply to the ref,
+// it's the matched branch protection rule for a branch, or the patterns of the matched protected tags for a tag.
+func getRefProtectionRules(ctx context.Context, repoID int64, ref git.RefName) ([]any, error) {
+	rules := make([]any, 0, 1)
+	switch {
+	case ref.IsBranch():
+		rule, err := git_model.GetFirstMatchProtectedBranchRule(ctx, repoID, ref.BranchName())
+		if err != nil {
+			return rules, err
+		}
+		if rule != nil {
+			rules = append(rules, rule.RuleName)
+		}
+	case ref.IsTag():
+		tags, err := git_model.GetMatchedProtectedTags(ctx, repoID, ref.TagName())
+		if err != nil {
+			return rules, err
+		}
+		for _, tag := range tags {
+			rules = append(rules, tag.NamePattern)
+		}
+	}
+	return rules, nil
+}
+
 func findTaskNeeds(ctx context.Context, task *actions_model.ActionTask) (map[string]*runnerv1.TaskNeed, error) {
 	if err := task.LoadAttributes(ctx); err != nil {
 		return nil, fmt.Errorf("LoadAttributes: %w", err)
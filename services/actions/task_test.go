@@ -0,0 +1,56 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestAppendTaskLogsMaxRows(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.Actions.LogUploadMaxRows, 3)()
+	ctx := db.DefaultContext
+
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 48})
+	task.LogFilename = "max-rows-test/48.log"
+	task.LogInStorage = false
+	task.LogLength, task.LogSize, task.LogIndexes = 0, 0, nil
+	require.NoError(t, actions_model.UpdateTask(ctx, task, "log_filename", "log_in_storage", "log_length", "log_size", "log_indexes"))
+
+	rows := make([]*runnerv1.LogRow, 0, 5)
+	for i := 0; i < 5; i++ {
+		rows = append(rows, &runnerv1.LogRow{Time: timestamppb.New(time.Now()), Content: fmt.Sprintf("line %d", i)})
+	}
+
+	// the rows beyond the limit aren't acknowledged, and the logs aren't transferred even if the runner has no more rows
+	ack, err := AppendTaskLogs(ctx, task.ID, 0, rows, true)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, ack)
+	task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 48})
+	assert.False(t, task.LogInStorage)
+
+	// the runner uploads the rest again
+	ack, err = AppendTaskLogs(ctx, task.ID, ack, rows[ack:], true)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, ack)
+	task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 48})
+	assert.True(t, task.LogInStorage)
+	assert.EqualValues(t, 5, task.LogLength)
+
+	require.NoError(t, actions_module.RemoveLogs(ctx, true, task.LogFilename))
+}
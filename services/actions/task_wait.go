@@ -0,0 +1,59 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var (
+	// waitTasksRecheckInterval is how often a waiting runner rechecks the tasks version,
+	// for the increases by the other instances which aren't notified in this process
+	waitTasksRecheckInterval = 5 * time.Second
+	// waitTasksSettleDelay is how soon a waiting runner rechecks the tasks version again if it hasn't changed when notified,
+	// since the transaction increasing it could be committed after the notification
+	waitTasksSettleDelay = 500 * time.Millisecond
+)
+
+// FetchTaskWait returns how long a FetchTask request could be held open for the wait the runner asks for, 0 if it shouldn't wait
+func FetchTaskWait(asked time.Duration) time.Duration {
+	return max(min(asked, setting.Actions.FetchTaskMaxWait), 0)
+}
+
+// WaitTasksVersion waits until the tasks version of the scope of the runner differs from the one the runner has seen,
+// then a task could be assigned to it, or until the wait elapses, the request is cancelled or the server starts shutting down.
+// It returns the latest tasks version.
+func WaitTasksVersion(ctx context.Context, runner *actions_model.ActionRunner, tasksVersion int64, wait time.Duration) (int64, error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	recheck := waitTasksRecheckInterval
+	for {
+		// get the channel before reading the version, so an increase between them isn't missed
+		changed := actions_model.TasksVersionChanged()
+		latestVersion, err := actions_model.GetTasksVersionByScope(ctx, runner.OwnerID, runner.RepoID)
+		if err != nil || latestVersion != tasksVersion || IsDrainingRunners() {
+			return latestVersion, err
+		}
+
+		select {
+		case <-changed:
+			recheck = waitTasksSettleDelay
+			continue
+		case <-time.After(recheck):
+		case <-timer.C:
+			return latestVersion, nil
+		case <-ctx.Done():
+			return latestVersion, nil
+		case <-graceful.GetManager().IsShutdown():
+			return latestVersion, nil
+		}
+		recheck = waitTasksRecheckInterval
+	}
+}
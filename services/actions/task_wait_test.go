@@ -0,0 +1,59 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTaskWait(t *testing.T) {
+	defer test.MockVariableValue(&setting.Actions.FetchTaskMaxWait, 30*time.Second)()
+	assert.Equal(t, 10*time.Second, FetchTaskWait(10*time.Second))
+	assert.Equal(t, 30*time.Second, FetchTaskWait(time.Minute))
+	assert.Zero(t, FetchTaskWait(-time.Second))
+
+	setting.Actions.FetchTaskMaxWait = 0
+	assert.Zero(t, FetchTaskWait(10*time.Second))
+}
+
+func TestWaitTasksVersion(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	runner := &actions_model.ActionRunner{RepoID: 4}
+	require.NoError(t, actions_model.IncreaseTaskVersion(ctx, 0, runner.RepoID))
+	version, err := actions_model.GetTasksVersionByScope(ctx, 0, runner.RepoID)
+	require.NoError(t, err)
+
+	// the version has changed since the runner saw it
+	latest, err := WaitTasksVersion(ctx, runner, version-1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, version, latest)
+
+	// nothing is queued during the wait
+	latest, err = WaitTasksVersion(ctx, runner, version, 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, version, latest)
+
+	// the waiting runner is woken up once a job is queued
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, actions_model.IncreaseTaskVersion(ctx, 0, runner.RepoID))
+	}()
+	start := time.Now()
+	latest, err = WaitTasksVersion(ctx, runner, version, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, version+1, latest)
+	assert.Less(t, time.Since(start), waitTasksRecheckInterval)
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottleWorkflows(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	workflows := []*actions_module.DetectedWorkflow{{EntryName: "a.yml"}, {EntryName: "b.yml"}, {EntryName: "c.yml"}}
+
+	// unlimited
+	got, err := throttleWorkflows(ctx, repo, workflows)
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+
+	defer test.MockVariableValue(&setting.Actions.MaxRunsPerMinute, 2)()
+	got, err = throttleWorkflows(ctx, repo, workflows)
+	require.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "a.yml", got[0].EntryName)
+		assert.Equal(t, "b.yml", got[1].EntryName)
+	}
+
+	require.NoError(t, db.Insert(ctx, &actions_model.ActionRun{RepoID: repo.ID, OwnerID: repo.OwnerID, Index: 1000, WorkflowID: "a.yml"}))
+	got, err = throttleWorkflows(ctx, repo, workflows)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	require.NoError(t, db.Insert(ctx, &actions_model.ActionRun{RepoID: repo.ID, OwnerID: repo.OwnerID, Index: 1001, WorkflowID: "b.yml"}))
+	got, err = throttleWorkflows(ctx, repo, workflows)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// tokenEnumerationThreshold is the number of the other repositories requested with the token of a task,
+// from which the requests to other repositories are treated as an enumeration
+const tokenEnumerationThreshold = 5
+
+// repoListingPattern matches the API paths listing or searching repositories
+var repoListingPattern = regexp.MustCompile(`/api/v1/(repos/search|user/repos|(orgs|users)/[^/]+/repos|repositories/\d+)/?$`)
+
+// IsTokenRateLimited returns whether the token of the task has made more requests than setting.Actions.TokenRateLimit in the last minute
+func IsTokenRateLimited(ctx context.Context, taskID int64) (bool, error) {
+	if setting.Actions.TokenRateLimit <= 0 {
+		return false, nil
+	}
+	count, err := actions_model.CountTokenActivitiesSince(ctx, taskID, timeutil.TimeStampNow()-60)
+	if err != nil {
+		return false, err
+	}
+	return count >= setting.Actions.TokenRateLimit, nil
+}
+
+// RecordTokenActivity records a request made with the token of the task to the trail of its run, targetRepoID is the repository requested.
+// The anomalies which may be the misuses of the token are detected unless the anomaly is given, and they are logged as warnings.
+func RecordTokenActivity(ctx context.Context, taskID int64, req *http.Request, targetRepoID int64, status int, anomaly string) error {
+	task, err := actions_model.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		return err
+	}
+
+	if anomaly == "" {
+		anomaly, err = detectTokenAnomaly(ctx, task, req, targetRepoID)
+		if err != nil {
+			return err
+		}
+	}
+	if anomaly != "" {
+		log.Warn("Possible misuse of the token of task %d of repo %d: %s %s %s", task.ID, task.RepoID, anomaly, req.Method, req.URL.Path)
+	}
+
+	return actions_model.InsertTokenActivity(ctx, &actions_model.ActionTokenActivity{
+		TaskID:       task.ID,
+		RunID:        task.Job.RunID,
+		RepoID:       task.RepoID,
+		TargetRepoID: targetRepoID,
+		Method:       req.Method,
+		Path:         req.URL.Path,
+		Status:       status,
+		Anomaly:      anomaly,
+	})
+}
+
+// detectTokenAnomaly returns the anomaly of the request made with the token of the task, or empty if it looks normal
+func detectTokenAnomaly(ctx context.Context, task *actions_model.ActionTask, req *http.Request, targetRepoID int64) (string, error) {
+	if targetRepoID == 0 || targetRepoID == task.RepoID {
+		if repoListingPattern.MatchString(req.URL.Path) {
+			return actions_model.TokenAnomalyEnumeration, nil
+		}
+		return "", nil
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return actions_model.TokenAnomalyWriteOtherRepo, nil
+	}
+	others, err := actions_model.CountTokenOtherRepos(ctx, task.ID)
+	if err != nil {
+		return "", err
+	}
+	if others+1 >= tokenEnumerationThreshold {
+		return actions_model.TokenAnomalyEnumeration, nil
+	}
+	return actions_model.TokenAnomalyOtherRepo, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordTokenActivity(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	require.NoError(t, task.LoadJob(ctx))
+
+	record := func(method, path string, targetRepoID int64) string {
+		req := httptest.NewRequest(method, path, nil)
+		require.NoError(t, RecordTokenActivity(ctx, task.ID, req, targetRepoID, http.StatusOK, ""))
+		activities, err := db.Find[actions_model.ActionTokenActivity](ctx, actions_model.FindTokenActivitiesOptions{TaskID: task.ID})
+		require.NoError(t, err)
+		last := activities[len(activities)-1]
+		assert.Equal(t, task.Job.RunID, last.RunID)
+		assert.Equal(t, path, last.Path)
+		return last.Anomaly
+	}
+
+	assert.Empty(t, record("GET", "/api/v1/repos/user5/repo4/issues", task.RepoID))
+	assert.Empty(t, record("POST", "/api/v1/repos/user5/repo4/issues", task.RepoID))
+	assert.Empty(t, record("GET", "/api/v1/version", 0))
+	assert.Equal(t, actions_model.TokenAnomalyEnumeration, record("GET", "/api/v1/repos/search", 0))
+	assert.Equal(t, actions_model.TokenAnomalyEnumeration, record("GET", "/api/v1/orgs/org3/repos", 0))
+	assert.Equal(t, actions_model.TokenAnomalyWriteOtherRepo, record("DELETE", "/api/v1/repos/user2/repo1", 1))
+	for id := int64(2); id <= 4; id++ {
+		assert.Equal(t, actions_model.TokenAnomalyOtherRepo, record("GET", "/api/v1/repos/user2/repo", id+10))
+	}
+	// the requests to many other repositories are an enumeration
+	assert.Equal(t, actions_model.TokenAnomalyEnumeration, record("GET", "/api/v1/repos/user2/repo", 20))
+
+	anomalous, err := db.Count[actions_model.ActionTokenActivity](ctx, actions_model.FindTokenActivitiesOptions{RunID: task.Job.RunID, AnomalousOnly: true})
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, anomalous)
+
+	defer test.MockVariableValue(&setting.Actions.TokenRateLimit, 11)()
+	limited, err := IsTokenRateLimited(ctx, task.ID)
+	require.NoError(t, err)
+	assert.False(t, limited)
+	record("GET", "/api/v1/version", 0)
+	limited, err = IsTokenRateLimited(ctx, task.ID)
+	require.NoError(t, err)
+	assert.True(t, limited)
+
+	setting.Actions.TokenRateLimit = 0
+	limited, err = IsTokenRateLimited(ctx, task.ID)
+	require.NoError(t, err)
+	assert.False(t, limited)
+}
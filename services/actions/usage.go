@@ -0,0 +1,163 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/system"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// listActionUsages returns the remote actions and reusable workflows used by the workflows in the commit
+func listActionUsages(commit *git.Commit) ([]*actions_model.ActionUsage, error) {
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []*actions_model.ActionUsage
+	for _, entry := range entries {
+		content, err := actions_module.GetContentFromEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		wf, err := model.ReadWorkflow(bytes.NewReader(content))
+		if err != nil {
+			// invalid workflows never run, so they use nothing
+			log.Trace("ignore invalid workflow %s: %v", entry.Name(), err)
+			continue
+		}
+
+		seen := make(container.Set[string])
+		add := func(uses string) {
+			if !actions_module.IsRemoteAction(uses) || !seen.Add(uses) {
+				return
+			}
+			name, ref := actions_module.SplitActionUses(uses)
+			usages = append(usages, &actions_model.ActionUsage{
+				WorkflowID: entry.Name(),
+				Action:     name,
+				Ref:        ref,
+			})
+		}
+		for _, job := range wf.Jobs {
+			if job == nil {
+				continue
+			}
+			add(job.Uses)
+			for _, step := range job.Steps {
+				add(step.Uses)
+			}
+		}
+	}
+	return usages, nil
+}
+
+// indexActionUsages indexes the actions used by the workflows in the commit of the default branch of the repository.
+// It won't return an error, but will log it, because it's not critical.
+func indexActionUsages(ctx context.Context, repo *repo_model.Repository, commit *git.Commit) {
+	usages, err := listActionUsages(commit)
+	if err == nil {
+		err = actions_model.ReplaceActionUsages(ctx, repo.ID, usages)
+	}
+	if err != nil {
+		log.Error("Failed to index action usages of repo %d: %v", repo.ID, err)
+	}
+}
+
+// IndexActionUsages indexes the actions used by the workflows of the default branch of the repository,
+// the caller should check whether the repository has Actions enabled.
+func IndexActionUsages(ctx context.Context, repo *repo_model.Repository) error {
+	if repo.IsEmpty || repo.IsArchived {
+		return actions_model.ReplaceActionUsages(ctx, repo.ID, nil)
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return fmt.Errorf("GetBranchCommit %s: %w", repo.DefaultBranch, err)
+	}
+	indexActionUsages(ctx, repo, commit)
+	return nil
+}
+
+// RebuildActionUsagesIndex rebuilds the index of the actions used by the workflows of all repositories with Actions enabled
+func RebuildActionUsagesIndex(ctx context.Context) error {
+	return db.Iterate(ctx, nil, func(ctx context.Context, repo *repo_model.Repository) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before indexing action usages of %s", repo.FullName())
+		default:
+		}
+
+		if err := repo.LoadUnits(ctx); err != nil {
+			return fmt.Errorf("LoadUnits: %w", err)
+		}
+		if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+			return actions_model.ReplaceActionUsages(ctx, repo.ID, nil)
+		}
+		if err := IndexActionUsages(ctx, repo); err != nil {
+			log.Error("IndexActionUsages %s: %v", repo.FullName(), err)
+		}
+		return nil
+	})
+}
+
+// actionUsagesIndexState records whether the index of the action usages is up to date,
+// it's outdated after upgrading from a version without the index or running with Actions disabled,
+// since the index is only updated by the pushes when Actions are enabled.
+type actionUsagesIndexState struct {
+	Built bool
+}
+
+// Name returns the name of the state item of the action usages index
+func (s *actionUsagesIndexState) Name() string {
+	return "actions-usages-index"
+}
+
+// rebuildActionUsagesIndexIfOutdated rebuilds the index of the action usages if it's outdated
+func rebuildActionUsagesIndexIfOutdated(ctx context.Context) {
+	state := &actionUsagesIndexState{}
+	if err := system.AppState.Get(ctx, state); err != nil {
+		log.Error("Failed to get the state of the action usages index: %v", err)
+		return
+	}
+	if state.Built {
+		return
+	}
+
+	log.Info("Rebuilding the index of the action usages")
+	if err := RebuildActionUsagesIndex(ctx); err != nil {
+		log.Error("RebuildActionUsagesIndex: %v", err)
+		return
+	}
+	state.Built = true
+	if err := system.AppState.Set(ctx, state); err != nil {
+		log.Error("Failed to set the state of the action usages index: %v", err)
+	}
+}
+
+// markActionUsagesIndexOutdated marks the index of the action usages as outdated, it will be rebuilt once Actions are enabled
+func markActionUsagesIndexOutdated(ctx context.Context) {
+	if err := system.AppState.Set(ctx, &actionUsagesIndexState{Built: false}); err != nil {
+		log.Error("Failed to set the state of the action usages index: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/system"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildActionUsagesIndexIfOutdated(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue[system.StateStore](&system.AppState, &system.DBStore{})()
+
+	isBuilt := func() bool {
+		state := &actionUsagesIndexState{}
+		assert.NoError(t, system.AppState.Get(db.DefaultContext, state))
+		return state.Built
+	}
+
+	// a stale usage of a repository without Actions enabled is removed by the rebuild
+	assert.NoError(t, actions_model.ReplaceActionUsages(db.DefaultContext, 2, []*actions_model.ActionUsage{
+		{WorkflowID: "ci.yml", Action: "actions/checkout", Ref: "v4"},
+	}))
+
+	assert.False(t, isBuilt())
+	rebuildActionUsagesIndexIfOutdated(db.DefaultContext)
+	assert.True(t, isBuilt())
+	unittest.AssertNotExistsBean(t, &actions_model.ActionUsage{RepoID: 2})
+
+	// it's built only once
+	assert.NoError(t, actions_model.ReplaceActionUsages(db.DefaultContext, 2, []*actions_model.ActionUsage{
+		{WorkflowID: "ci.yml", Action: "actions/checkout", Ref: "v4"},
+	}))
+	rebuildActionUsagesIndexIfOutdated(db.DefaultContext)
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionUsage{RepoID: 2})
+
+	// until Actions have been disabled
+	markActionUsagesIndexOutdated(db.DefaultContext)
+	assert.False(t, isBuilt())
+	rebuildActionUsagesIndexIfOutdated(db.DefaultContext)
+	assert.True(t, isBuilt())
+	unittest.AssertNotExistsBean(t, &actions_model.ActionUsage{RepoID: 2})
+}
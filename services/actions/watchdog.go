@@ -0,0 +1,98 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ReconcileStuckJobs repairs the jobs which have waiting or blocked status without updates for a long time,
+// because some state transitions have been missed, e.g. the process crashed between updating a task and its job:
+//   - the jobs which have been picked follow the status of their tasks
+//   - the jobs of the runs which have stopped are cancelled
+//   - the blocked jobs whose needed jobs have stopped are resolved like the job emitter does
+//
+// The corrections are logged, the jobs which are just waiting for runners are left to CancelAbandonedJobs.
+func ReconcileStuckJobs(ctx context.Context) error {
+	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		Statuses:      []actions_model.Status{actions_model.StatusWaiting, actions_model.StatusBlocked},
+		UpdatedBefore: timeutil.TimeStamp(time.Now().Add(-setting.Actions.StuckJobTimeout).Unix()),
+	})
+	if err != nil {
+		return err
+	}
+
+	blockedRunIDs := make(container.Set[int64])
+	var repaired []*actions_model.ActionRunJob
+	for _, job := range jobs {
+		if err := job.LoadRun(ctx); err != nil {
+			log.Warn("Actions watchdog: load run of job %d: %v", job.ID, err)
+			continue
+		}
+
+		var status actions_model.Status
+		var stopped timeutil.TimeStamp
+		switch {
+		case job.TaskID > 0:
+			task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+			if err != nil {
+				log.Warn("Actions watchdog: get task %d of job %d: %v", job.TaskID, job.ID, err)
+				continue
+			}
+			status, stopped = task.Status, task.Stopped
+		case job.Run.Status.IsDone():
+			status, stopped = actions_model.StatusCancelled, timeutil.TimeStampNow()
+		case job.Status.IsBlocked() && !job.Run.NeedApproval:
+			blockedRunIDs.Add(job.RunID)
+			continue
+		default:
+			continue
+		}
+		if status == job.Status {
+			continue
+		}
+
+		log.Warn("Actions watchdog: job %d of run %d is stuck in %s, repaired to %s", job.ID, job.RunID, job.Status, status)
+		job.Status, job.Stopped = status, stopped
+		if err := db.WithTx(ctx, func(ctx context.Context) error {
+			_, err := actions_model.UpdateRunJob(ctx, job,
+				builder.In("status", actions_model.StatusWaiting, actions_model.StatusBlocked), "status", "stopped")
+			return err
+		}); err != nil {
+			log.Warn("Actions watchdog: repair job %d: %v", job.ID, err)
+			continue
+		}
+		repaired = append(repaired, job)
+	}
+	CreateCommitStatus(ctx, repaired...)
+
+	for runID := range blockedRunIDs {
+		runJobs, err := actions_model.GetRunJobsByRunID(ctx, runID)
+		if err != nil {
+			log.Warn("Actions watchdog: get jobs of run %d: %v", runID, err)
+			continue
+		}
+		updates := newJobStatusResolver(runJobs).Resolve()
+		if len(updates) == 0 {
+			continue
+		}
+		for jobID, status := range updates {
+			log.Warn("Actions watchdog: job %d of run %d is stuck in blocked, repaired to %s", jobID, runID, status)
+		}
+		if err := checkJobsOfRun(ctx, runID); err != nil {
+			log.Warn("Actions watchdog: resolve jobs of run %d: %v", runID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileStuckJobs(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	// every job counts as stuck
+	defer test.MockVariableValue(&setting.Actions.StuckJobTimeout, -time.Hour)()
+	ctx := db.DefaultContext
+
+	newRun := func(index int64, status actions_model.Status) *actions_model.ActionRun {
+		run := &actions_model.ActionRun{
+			Index:         index,
+			Title:         "watchdog",
+			RepoID:        4,
+			OwnerID:       1,
+			WorkflowID:    "test.yml",
+			TriggerUserID: 1,
+			Ref:           "refs/heads/master",
+			CommitSHA:     "c2d72f548424103f01ee1dc02889c1e2bff816b0",
+			Event:         "push",
+			Status:        status,
+		}
+		require.NoError(t, db.Insert(ctx, run))
+		return run
+	}
+	newJob := func(run *actions_model.ActionRun, jobID string, needs []string, taskID int64, status actions_model.Status) *actions_model.ActionRunJob {
+		job := &actions_model.ActionRunJob{
+			RunID:     run.ID,
+			RepoID:    run.RepoID,
+			OwnerID:   run.OwnerID,
+			CommitSHA: run.CommitSHA,
+			Name:      jobID,
+			JobID:     jobID,
+			Needs:     needs,
+			TaskID:    taskID,
+			Status:    status,
+		}
+		require.NoError(t, db.Insert(ctx, job))
+		return job
+	}
+
+	// the process crashed after the task of "build" succeeded, before updating the job
+	run := newRun(1001, actions_model.StatusRunning)
+	build := newJob(run, "build", nil, 1001, actions_model.StatusWaiting)
+	deploy := newJob(run, "deploy", []string{"build"}, 0, actions_model.StatusBlocked)
+	require.NoError(t, db.Insert(ctx, &actions_model.ActionTask{
+		ID: 1001, JobID: build.ID, RepoID: 4, OwnerID: 1, Status: actions_model.StatusSuccess, Started: 100, Stopped: 200, TokenHash: "watchdog-test",
+	}))
+
+	// the jobs of a run which needs approval are expected to be blocked
+	approvalRun := newRun(1002, actions_model.StatusBlocked)
+	approvalRun.NeedApproval = true
+	require.NoError(t, actions_model.UpdateRun(ctx, approvalRun, "need_approval"))
+	pending := newJob(approvalRun, "test", nil, 0, actions_model.StatusBlocked)
+
+	require.NoError(t, ReconcileStuckJobs(ctx))
+
+	build = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: build.ID})
+	assert.Equal(t, actions_model.StatusSuccess, build.Status)
+	assert.EqualValues(t, 200, build.Stopped)
+	assert.Equal(t, actions_model.StatusWaiting, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: deploy.ID}).Status)
+	assert.Equal(t, actions_model.StatusBlocked, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: pending.ID}).Status)
+}
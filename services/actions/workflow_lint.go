@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
+)
+
+// WorkflowLintCommitStatusContext is the context of the commit status reporting the lint findings of the workflows
+const WorkflowLintCommitStatusContext = "Gitea Actions / workflow lint"
+
+// WorkflowLintFinding is a problem found in a workflow file of a repository
+type WorkflowLintFinding struct {
+	Path string
+	*actions_module.WorkflowLintFinding
+}
+
+// LintWorkflows lints all the workflows in the commit
+func LintWorkflows(commit *git.Commit) ([]*WorkflowLintFinding, error) {
+	dir, err := actions_module.GetWorkflowsDir(commit)
+	if err != nil || dir == "" {
+		return nil, err
+	}
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []*WorkflowLintFinding
+	for _, entry := range entries {
+		content, err := actions_module.GetContentFromEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range actions_module.LintWorkflow(content) {
+			findings = append(findings, &WorkflowLintFinding{
+				Path:                path.Join(dir, entry.Name()),
+				WorkflowLintFinding: f,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// pushChangesWorkflows returns whether any commit of the push adds or modifies a workflow file
+func pushChangesWorkflows(payload *api.PushPayload) bool {
+	for _, commit := range payload.Commits {
+		for _, files := range [][]string{commit.Added, commit.Modified} {
+			for _, file := range files {
+				if actions_module.IsWorkflow(file) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// lintWorkflowsOfPush lints the workflows of the pushed commit if the push changes them,
+// and reports the findings as a commit status, so they will be shown where the commit is.
+// It won't return an error, but will log it, because it's not critical.
+func lintWorkflowsOfPush(ctx context.Context, repo *repo_model.Repository, payload *api.PushPayload, commit *git.Commit) {
+	if !pushChangesWorkflows(payload) {
+		return
+	}
+	if err := createWorkflowLintCommitStatus(ctx, repo, commit); err != nil {
+		log.Error("Failed to create workflow lint commit status for commit %s of repo %d: %v", commit.ID, repo.ID, err)
+	}
+}
+
+func createWorkflowLintCommitStatus(ctx context.Context, repo *repo_model.Repository, commit *git.Commit) error {
+	findings, err := LintWorkflows(commit)
+	if err != nil {
+		return fmt.Errorf("LintWorkflows: %w", err)
+	}
+
+	state := api.CommitStatusSuccess
+	description := "No problems found in workflows"
+	if len(findings) > 0 {
+		state = api.CommitStatusWarning
+		files := make(map[string]struct{}, len(findings))
+		for _, f := range findings {
+			files[f.Path] = struct{}{}
+		}
+		// TODO: if we want support description in different languages, we need to support i18n placeholders in it
+		description = fmt.Sprintf("%d problems found in %d workflows", len(findings), len(files))
+	}
+
+	creator := user_model.NewActionsUser()
+	if err := commitstatus_service.CreateCommitStatus(ctx, repo, creator, commit.ID.String(), &git_model.CommitStatus{
+		SHA:         commit.ID.String(),
+		TargetURL:   fmt.Sprintf("%s/actions", repo.Link()),
+		Description: description,
+		Context:     WorkflowLintCommitStatusContext,
+		CreatorID:   creator.ID,
+		State:       state,
+	}); err != nil {
+		return fmt.Errorf("NewCommitStatus: %w", err)
+	}
+	return nil
+}
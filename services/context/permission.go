@@ -22,6 +22,16 @@ func RequireRepoAdmin() func(ctx *Context) {
 	}
 }
 
+// RequireRepoActionsAdmin returns a middleware for requiring the administration of the Actions of the repository
+func RequireRepoActionsAdmin() func(ctx *Context) {
+	return func(ctx *Context) {
+		if !ctx.IsSigned || !ctx.Repo.Permission.CanManageActions() {
+			ctx.NotFound(ctx.Req.URL.RequestURI(), nil)
+			return
+		}
+	}
+}
+
 // RequireRepoWriter returns a middleware for requiring repository write to the specify unitType
 func RequireRepoWriter(unitType unit.Type) func(ctx *Context) {
 	return func(ctx *Context) {
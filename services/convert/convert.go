@@ -6,6 +6,7 @@ package convert
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -22,11 +23,14 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/gitdiff"
 )
@@ -204,22 +208,702 @@ func ToActionTask(ctx context.Context, t *actions_model.ActionTask) (*api.Action
 	url := strings.TrimSuffix(setting.AppURL, "/") + t.GetRunLink()
 
 	return &api.ActionTask{
-		ID:           t.ID,
-		Name:         t.Job.Name,
-		HeadBranch:   t.Job.Run.PrettyRef(),
-		HeadSHA:      t.Job.CommitSHA,
-		RunNumber:    t.Job.Run.Index,
-		Event:        t.Job.Run.TriggerEvent,
-		DisplayTitle: t.Job.Run.Title,
-		Status:       t.Status.String(),
-		WorkflowID:   t.Job.Run.WorkflowID,
-		URL:          url,
-		CreatedAt:    t.Created.AsLocalTime(),
-		UpdatedAt:    t.Updated.AsLocalTime(),
-		RunStartedAt: t.Started.AsLocalTime(),
+		ID:            t.ID,
+		Name:          t.Job.Name,
+		HeadBranch:    t.Job.Run.PrettyRef(),
+		HeadSHA:       t.Job.CommitSHA,
+		RunNumber:     t.Job.Run.Index,
+		Event:         t.Job.Run.TriggerEvent,
+		DisplayTitle:  t.Job.Run.Title,
+		Status:        t.Status.String(),
+		Conclusion:    toActionConclusion(t.Status),
+		StatusDisplay: t.Status.LocaleString(localeFromContext(ctx)),
+		WorkflowID:    t.Job.Run.WorkflowID,
+		JobID:         t.JobID,
+		Attempt:       t.Attempt,
+		URL:           url,
+		LogsURL:       toActionRunJobLogsURL(t.Job.Run, t.Job),
+		CreatedAt:     t.Created.AsLocalTime(),
+		UpdatedAt:     t.Updated.AsLocalTime(),
+		RunStartedAt:  t.Started.AsLocalTime(),
+		StoppedAt:     t.Stopped.AsLocalTime(),
+		Environment:   t.Environment,
 	}, nil
 }
 
+// ToRepoActionsSettings convert the Actions config of a repository to an api.RepoActionsSettings,
+// cfg is nil if Actions are disabled in the repository
+func ToRepoActionsSettings(cfg *repo_model.ActionsConfig) *api.RepoActionsSettings {
+	enabled := cfg != nil
+	if !enabled {
+		cfg = &repo_model.ActionsConfig{}
+	}
+	settings := &api.RepoActionsSettings{
+		Enabled:                        enabled,
+		DefaultTokenPermissions:        string(cfg.GetDefaultTokenPermissions()),
+		ForkPullRequestApproval:        string(cfg.GetForkPullRequestApproval()),
+		ArtifactRetentionDays:          cfg.ArtifactRetentionDays,
+		LogRetentionDays:               cfg.LogRetentionDays,
+		RunRetentionDays:               cfg.RunRetentionDays,
+		AllowedActions:                 cfg.AllowedActions,
+		DisabledWorkflows:              cfg.DisabledWorkflows,
+		StatusExcludedWorkflows:        cfg.StatusExcludedWorkflows,
+		FeedRuns:                       string(cfg.GetFeedRuns()),
+		FeedRunsDefaultBranchOnly:      cfg.FeedRunsDefaultBranchOnly,
+		AnonymousRunAccess:             string(cfg.GetAnonymousRunAccess()),
+		SupersedePullRequestRuns:       string(cfg.GetSupersedePullRequestRuns()),
+		ClosedPullRequestRuns:          string(cfg.GetClosedPullRequestRuns()),
+		ClosedPullRequestRetentionDays: cfg.ClosedPullRequestRetentionDays,
+		SubmoduleTokenScope:            string(cfg.GetSubmoduleTokenScope()),
+		BotIdentity:                    ToActionBotIdentity(cfg.BotIdentity),
+		ReleaseAutomation:              ToRepoActionsReleaseAutomation(cfg.ReleaseAutomation),
+		PackageRetention:               ToRepoActionsPackageRetention(cfg.PackageRetention),
+	}
+	if settings.AllowedActions == nil {
+		settings.AllowedActions = []string{}
+	}
+	if settings.DisabledWorkflows == nil {
+		settings.DisabledWorkflows = []string{}
+	}
+	if settings.StatusExcludedWorkflows == nil {
+		settings.StatusExcludedWorkflows = []string{}
+	}
+	settings.DisabledJobs = make([]*api.RepoActionsDisabledJob, 0, len(cfg.DisabledJobs))
+	for _, j := range cfg.DisabledJobs {
+		settings.DisabledJobs = append(settings.DisabledJobs, &api.RepoActionsDisabledJob{
+			WorkflowID: j.WorkflowID,
+			Job:        j.Job,
+		})
+	}
+	settings.RunsOnOverrides = ToActionRunsOnOverrides(cfg.RunsOnOverrides)
+	settings.ChatOpsCommands = make([]*api.RepoActionsChatOpsCommand, 0, len(cfg.ChatOpsCommands))
+	for _, c := range cfg.ChatOpsCommands {
+		settings.ChatOpsCommands = append(settings.ChatOpsCommands, &api.RepoActionsChatOpsCommand{
+			Name:         c.Name,
+			WorkflowID:   c.WorkflowID,
+			ArgsInput:    c.ArgsInput,
+			RequireAdmin: c.RequireAdmin,
+		})
+	}
+	return settings
+}
+
+// ToActionsConfigBundleSettings converts the Actions config of a repository to the settings of an Actions configuration bundle.
+// Every setting is set, so importing them makes the settings of the repository the same,
+// except whether Actions are enabled and the federation, which are bound to the repository and its secrets.
+func ToActionsConfigBundleSettings(cfg *repo_model.ActionsConfig) *api.EditRepoActionsSettingsOption {
+	settings := ToRepoActionsSettings(cfg)
+	opts := &api.EditRepoActionsSettingsOption{
+		DefaultTokenPermissions:        util.ToPointer(settings.DefaultTokenPermissions),
+		ForkPullRequestApproval:        util.ToPointer(settings.ForkPullRequestApproval),
+		ArtifactRetentionDays:          util.ToPointer(settings.ArtifactRetentionDays),
+		LogRetentionDays:               util.ToPointer(settings.LogRetentionDays),
+		RunRetentionDays:               util.ToPointer(settings.RunRetentionDays),
+		AllowedActions:                 settings.AllowedActions,
+		DisabledWorkflows:              settings.DisabledWorkflows,
+		ChatOpsCommands:                settings.ChatOpsCommands,
+		StatusExcludedWorkflows:        settings.StatusExcludedWorkflows,
+		DisabledJobs:                   settings.DisabledJobs,
+		RunsOnOverrides:                settings.RunsOnOverrides,
+		FeedRuns:                       util.ToPointer(settings.FeedRuns),
+		FeedRunsDefaultBranchOnly:      util.ToPointer(settings.FeedRunsDefaultBranchOnly),
+		AnonymousRunAccess:             util.ToPointer(settings.AnonymousRunAccess),
+		SupersedePullRequestRuns:       util.ToPointer(settings.SupersedePullRequestRuns),
+		ClosedPullRequestRuns:          util.ToPointer(settings.ClosedPullRequestRuns),
+		ClosedPullRequestRetentionDays: util.ToPointer(settings.ClosedPullRequestRetentionDays),
+		SubmoduleTokenScope:            util.ToPointer(settings.SubmoduleTokenScope),
+		// the empty ones remove the settings of the repository importing them
+		BotIdentity:       &api.ActionBotIdentity{},
+		ReleaseAutomation: &api.RepoActionsReleaseAutomation{Artifacts: []string{}},
+		PackageRetention:  &api.RepoActionsPackageRetention{Rules: []*api.RepoActionsPackageRetentionRule{}},
+		BadgeTheme:        &api.ActionBadgeTheme{Colors: map[string]string{}},
+		BadgeLabels:       map[string]string{},
+	}
+	if settings.BotIdentity != nil {
+		opts.BotIdentity = settings.BotIdentity
+	}
+	if settings.ReleaseAutomation != nil {
+		opts.ReleaseAutomation = settings.ReleaseAutomation
+	}
+	if settings.PackageRetention != nil {
+		opts.PackageRetention = settings.PackageRetention
+	}
+	if cfg != nil && cfg.BadgeTheme != nil {
+		opts.BadgeTheme = ToActionBadgeTheme(cfg.BadgeTheme)
+	}
+	if cfg != nil && len(cfg.BadgeLabels) > 0 {
+		opts.BadgeLabels = cfg.BadgeLabels
+	}
+	return opts
+}
+
+// ToActionDispatchPreset converts a actions_model.ActionDispatchPreset to an api.ActionDispatchPreset
+func ToActionDispatchPreset(p *actions_model.ActionDispatchPreset) *api.ActionDispatchPreset {
+	inputs := p.Inputs
+	if inputs == nil {
+		inputs = map[string]string{}
+	}
+	return &api.ActionDispatchPreset{
+		Name:       p.Name,
+		WorkflowID: p.WorkflowID,
+		Ref:        p.Ref,
+		Inputs:     inputs,
+		Created:    p.Created.AsTime(),
+		Updated:    p.Updated.AsTime(),
+	}
+}
+
+// ToActionFreezeWindow converts a actions_model.ActionFreezeWindow to an api.ActionFreezeWindow
+func ToActionFreezeWindow(w *actions_model.ActionFreezeWindow) *api.ActionFreezeWindow {
+	environments, labels := w.Environments, w.Labels
+	if environments == nil {
+		environments = []string{}
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	return &api.ActionFreezeWindow{
+		ID:           w.ID,
+		Name:         w.Name,
+		Reason:       w.Reason,
+		Environments: environments,
+		Labels:       labels,
+		Start:        w.StartUnix.AsTime(),
+		End:          w.EndUnix.AsTime(),
+		Created:      w.Created.AsTime(),
+	}
+}
+
+// ToActionRunFilter converts a actions_model.ActionRunFilter to an api.ActionRunFilter
+func ToActionRunFilter(ctx context.Context, f *actions_model.ActionRunFilter) (*api.ActionRunFilter, error) {
+	filter := &api.ActionRunFilter{
+		Name:       f.Name,
+		WorkflowID: f.WorkflowID,
+		Branch:     f.Branch,
+		Created:    f.Created.AsTime(),
+		Updated:    f.Updated.AsTime(),
+	}
+	if f.ActorID != 0 {
+		actor, err := user_model.GetPossibleUserByID(ctx, f.ActorID)
+		if err != nil && !user_model.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		if actor == nil {
+			actor = user_model.NewGhostUser()
+		}
+		filter.Actor = actor.Name
+	}
+	if f.Status != actions_model.StatusUnknown {
+		filter.Status = f.Status.String()
+	}
+	return filter, nil
+}
+
+// ToActionRunsOnOverrides converts the runs-on overrides to a list of api.ActionRunsOnOverride
+func ToActionRunsOnOverrides(overrides []*repo_model.ActionsRunsOnOverride) []*api.ActionRunsOnOverride {
+	ret := make([]*api.ActionRunsOnOverride, 0, len(overrides))
+	for _, o := range overrides {
+		ret = append(ret, &api.ActionRunsOnOverride{
+			Job:    o.Job,
+			RunsOn: o.RunsOn,
+			Labels: o.Labels,
+		})
+	}
+	return ret
+}
+
+// ToRepoActionsReleaseAutomation converts a release automation to API format, nil means it's disabled
+func ToRepoActionsReleaseAutomation(automation *repo_model.ActionsReleaseAutomation) *api.RepoActionsReleaseAutomation {
+	if automation == nil {
+		return nil
+	}
+	artifacts := automation.Artifacts
+	if artifacts == nil {
+		artifacts = []string{}
+	}
+	return &api.RepoActionsReleaseAutomation{
+		WorkflowID:    automation.WorkflowID,
+		TagPattern:    automation.TagPattern,
+		Artifacts:     artifacts,
+		GenerateNotes: automation.GenerateNotes,
+		Draft:         automation.Draft,
+	}
+}
+
+// ToRepoActionsPackageRetention converts a package retention to API format, nil means it's disabled
+func ToRepoActionsPackageRetention(retention *repo_model.ActionsPackageRetention) *api.RepoActionsPackageRetention {
+	if retention == nil {
+		return nil
+	}
+	rules := make([]*api.RepoActionsPackageRetentionRule, 0, len(retention.Rules))
+	for _, r := range retention.Rules {
+		rules = append(rules, &api.RepoActionsPackageRetentionRule{
+			Type:           r.Type,
+			PackagePattern: r.PackagePattern,
+			RefPattern:     r.RefPattern,
+			KeepCount:      r.KeepCount,
+			KeepDays:       r.KeepDays,
+		})
+	}
+	return &api.RepoActionsPackageRetention{
+		Rules:  rules,
+		DryRun: retention.DryRun,
+	}
+}
+
+// ToActionBotIdentity converts a bot identity to API format, nil means the global actions user
+func ToActionBotIdentity(identity *repo_model.ActionsBotIdentity) *api.ActionBotIdentity {
+	if identity == nil {
+		return nil
+	}
+	return &api.ActionBotIdentity{
+		Name:        identity.Name,
+		Email:       identity.Email,
+		AvatarEmail: identity.AvatarEmail,
+	}
+}
+
+// ToActionBadgeTheme converts a repo_model.ActionsBadgeTheme to an api.ActionBadgeTheme
+func ToActionBadgeTheme(theme *repo_model.ActionsBadgeTheme) *api.ActionBadgeTheme {
+	if theme == nil {
+		return nil
+	}
+	ret := &api.ActionBadgeTheme{
+		Style:      theme.Style,
+		LabelColor: theme.LabelColor,
+		Colors:     theme.Colors,
+		Logo:       theme.Logo,
+	}
+	if ret.Colors == nil {
+		ret.Colors = map[string]string{}
+	}
+	return ret
+}
+
+// ToActionRun convert a actions_model.ActionRun with its jobs to an api.ActionRun
+func ToActionRun(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) (*api.ActionRun, error) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]int64, 0, len(jobs))
+	for _, job := range jobs {
+		if job.TaskID > 0 {
+			taskIDs = append(taskIDs, job.TaskID)
+		}
+	}
+	stepsMap, err := actions_model.GetTaskStepsByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	runnersMap, err := actions_model.GetRunnersByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := actions_model.GetTasksByIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	freezeWindows, err := actions_model.GetActiveFreezeWindows(ctx, timeutil.TimeStampNow())
+	if err != nil {
+		return nil, err
+	}
+
+	locale := localeFromContext(ctx)
+	now := timeutil.TimeStampNow()
+	apiJobs := make([]*api.ActionRunJob, 0, len(jobs))
+	for _, job := range jobs {
+		// the execution starts when the first step starts, the time before it is spent on setting up the job
+		var executionStarted timeutil.TimeStamp
+		for _, step := range stepsMap[job.TaskID] {
+			if step.Started > 0 {
+				executionStarted = step.Started
+				break
+			}
+		}
+		apiJob := toActionRunJob(job, executionStarted, now)
+		apiJob.LogsURL = toActionRunJobLogsURL(run, job)
+		if job.Status == actions_model.StatusWaiting {
+			if w := actions_model.FreezeWindowOfJob(freezeWindows, job); w != nil {
+				apiJob.FrozenBy = ToActionFreezeWindow(w)
+			}
+		}
+		if runner, ok := runnersMap[job.TaskID]; ok {
+			apiJob.Runner = toActionRunJobRunner(runner)
+		}
+		if task, ok := tasks[job.TaskID]; ok {
+			apiJob.ErrorClass = task.ErrorClass
+			apiJob.FailureReason = task.ErrorClass
+			if !task.Overrides.IsEmpty() {
+				apiJob.Overrides = &api.ActionRunOverrides{
+					Vars:   task.Overrides.Vars,
+					Inputs: task.Overrides.Inputs,
+				}
+			}
+			if usage := task.ResourceUsage; usage != nil {
+				apiJob.ResourceUsage = &api.ActionRunJobResourceUsage{
+					Samples:        usage.Samples,
+					CPUPercentAvg:  usage.CPUPercentAvg(),
+					CPUPercentMax:  usage.CPUPercentMax,
+					MemoryBytesAvg: usage.MemoryBytesAvg(),
+					MemoryBytesMax: usage.MemoryBytesMax,
+					DiskBytesMax:   usage.DiskBytesMax,
+				}
+			}
+		}
+		if job.PreflightError != "" {
+			apiJob.FailureReason = "preflight"
+			apiJob.FailureReasonDisplay = locale.TrString("actions.runs.preflight_failed", job.PreflightError)
+		} else if apiJob.FailureReason != "" {
+			apiJob.FailureReasonDisplay = locale.TrString("actions.runs.failure_reason." + apiJob.FailureReason)
+		}
+		apiJob.StatusDisplay = job.Status.LocaleString(locale)
+		apiJobs = append(apiJobs, apiJob)
+	}
+
+	var acknowledgement *api.ActionRunAcknowledgement
+	if run.IsAcknowledged() {
+		acknowledger, err := user_model.GetPossibleUserByID(ctx, run.AcknowledgedBy)
+		if err != nil {
+			if !errors.Is(err, util.ErrNotExist) {
+				return nil, err
+			}
+			acknowledger = user_model.NewGhostUser()
+		}
+		acknowledgement = &api.ActionRunAcknowledgement{
+			User:    ToUser(ctx, acknowledger, nil),
+			Comment: run.AcknowledgedComment,
+			Created: run.Acknowledged.AsLocalTime(),
+		}
+	}
+
+	return &api.ActionRun{
+		ID:             run.ID,
+		RunNumber:      run.Index,
+		Title:          run.Title,
+		WorkflowID:     run.WorkflowID,
+		Component:      run.Component,
+		Event:          run.TriggerEvent,
+		Status:         run.Status.String(),
+		Conclusion:     toActionConclusion(run.Status),
+		StatusDisplay:  run.Status.LocaleString(locale),
+		HeadBranch:     run.PrettyRef(),
+		HeadSHA:        run.CommitSHA,
+		ExternalSystem: run.ExternalSystem,
+		ExternalURL:    run.ExternalURL,
+		HTMLURL:        run.HTMLURL(),
+		URL:            run.APIURL(),
+		Repo: &api.RepositoryMeta{
+			ID:       run.Repo.ID,
+			Name:     run.Repo.Name,
+			Owner:    run.Repo.OwnerName,
+			FullName: run.Repo.FullName(),
+		},
+		Jobs:                apiJobs,
+		Acknowledgement:     acknowledgement,
+		DuplicateDeliveries: run.DuplicateDeliveries,
+		Started:             run.Started.AsLocalTime(),
+		Stopped:             run.Stopped.AsLocalTime(),
+		Created:             run.Created.AsLocalTime(),
+		Updated:             run.Updated.AsLocalTime(),
+	}, nil
+}
+
+// toActionConclusion returns the conclusion of a run or a job with the status, it's empty if the status isn't done
+func toActionConclusion(status actions_model.Status) string {
+	if !status.IsDone() {
+		return ""
+	}
+	return status.String()
+}
+
+// localeFromContext returns the locale of the request of the context, or the default locale if it's not from a request
+func localeFromContext(ctx context.Context) translation.Locale {
+	if locale, ok := ctx.Value(translation.ContextKey).(translation.Locale); ok {
+		return locale
+	}
+	return translation.NewLocale("en-US")
+}
+
+// ToActionRunSteps converts the steps of a task to api.ActionRunStep
+func ToActionRunSteps(steps []*actions_model.ActionTaskStep) []*api.ActionRunStep {
+	apiSteps := make([]*api.ActionRunStep, 0, len(steps))
+	for _, step := range steps {
+		apiSteps = append(apiSteps, &api.ActionRunStep{
+			Number:  step.Index + 1,
+			Name:    step.Name,
+			Status:  step.Status.String(),
+			Started: step.Started.AsLocalTime(),
+			Stopped: step.Stopped.AsLocalTime(),
+		})
+	}
+	return apiSteps
+}
+
+// ToActionLogLines converts the structured lines of the logs of a job to api.ActionLogLine,
+// the lines in the private sections are redacted if redact is true
+func ToActionLogLines(lines []*actions_module.StructuredLogLine, redact bool) []*api.ActionLogLine {
+	apiLines := make([]*api.ActionLogLine, 0, len(lines))
+	for _, line := range lines {
+		segments := make([]*api.ActionLogSegment, 0, len(line.Segments))
+		if redact && line.Private {
+			segments = append(segments, &api.ActionLogSegment{Text: actions_module.RedactedLogLine})
+		} else {
+			for _, s := range line.Segments {
+				segments = append(segments, &api.ActionLogSegment{
+					Text:       s.Text,
+					Foreground: s.Foreground,
+					Background: s.Background,
+					Bold:       s.Bold,
+					Italic:     s.Italic,
+					Underline:  s.Underline,
+				})
+			}
+		}
+		apiLines = append(apiLines, &api.ActionLogLine{
+			Index:    line.Index,
+			Time:     line.Time,
+			Type:     line.Type,
+			Level:    line.Level,
+			Group:    line.Group,
+			Segments: segments,
+		})
+	}
+	return apiLines
+}
+
+// ToActionRunComment convert a actions_model.ActionRunComment to an api.ActionRunComment
+func ToActionRunComment(ctx context.Context, c *actions_model.ActionRunComment, doer *user_model.User) (*api.ActionRunComment, error) {
+	if err := c.LoadPoster(ctx); err != nil {
+		return nil, err
+	}
+	return &api.ActionRunComment{
+		ID:      c.ID,
+		JobID:   c.JobID,
+		User:    ToUser(ctx, c.Poster, doer),
+		Body:    c.Content,
+		Created: c.Created.AsLocalTime(),
+		Updated: c.Updated.AsLocalTime(),
+	}, nil
+}
+
+// ToActionTokenActivity converts ActionTokenActivity to API format
+func ToActionTokenActivity(activity *actions_model.ActionTokenActivity) *api.ActionTokenActivity {
+	return &api.ActionTokenActivity{
+		ID:           activity.ID,
+		TaskID:       activity.TaskID,
+		TargetRepoID: activity.TargetRepoID,
+		Method:       activity.Method,
+		Path:         activity.Path,
+		Status:       activity.Status,
+		Anomaly:      activity.Anomaly,
+		Created:      activity.Created.AsLocalTime(),
+	}
+}
+
+// ToActionCodeScanningAlert convert actions_model.ActionCodeScanningAlert to api.ActionCodeScanningAlert
+func ToActionCodeScanningAlert(alert *actions_model.ActionCodeScanningAlert) *api.ActionCodeScanningAlert {
+	res := &api.ActionCodeScanningAlert{
+		ID:             alert.ID,
+		Ref:            alert.Ref,
+		Fingerprint:    alert.Fingerprint,
+		Tool:           alert.Tool,
+		RuleID:         alert.RuleID,
+		Severity:       alert.Severity,
+		Message:        alert.Message,
+		Path:           alert.Path,
+		StartLine:      alert.StartLine,
+		EndLine:        alert.EndLine,
+		State:          alert.State,
+		FirstRunNumber: alert.FirstRunIndex,
+		LastRunNumber:  alert.LastRunIndex,
+		FixedRunNumber: alert.FixedRunIndex,
+		Created:        alert.Created.AsLocalTime(),
+	}
+	if !alert.IsOpen() {
+		fixed := alert.Fixed.AsLocalTime()
+		res.Fixed = &fixed
+	}
+	return res
+}
+
+// ToActionCodeScanningAlerts convert a list of actions_model.ActionCodeScanningAlert to a list of api.ActionCodeScanningAlert
+func ToActionCodeScanningAlerts(alerts []*actions_model.ActionCodeScanningAlert) []*api.ActionCodeScanningAlert {
+	res := make([]*api.ActionCodeScanningAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		res = append(res, ToActionCodeScanningAlert(alert))
+	}
+	return res
+}
+
+// ToActionDependencyManifests convert a list of actions_model.DependencyManifest to a list of api.ActionDependencyManifest
+func ToActionDependencyManifests(manifests []*actions_model.DependencyManifest) []*api.ActionDependencyManifest {
+	res := make([]*api.ActionDependencyManifest, 0, len(manifests))
+	for _, m := range manifests {
+		deps := make([]*api.ActionDependency, 0, len(m.Dependencies))
+		for _, dep := range m.Dependencies {
+			deps = append(deps, &api.ActionDependency{Name: dep.Name, Version: dep.Version, Scope: dep.Scope})
+		}
+		res = append(res, &api.ActionDependencyManifest{Path: m.Path, Ecosystem: m.Ecosystem, Dependencies: deps})
+	}
+	return res
+}
+
+// ToActionDependencySnapshot convert actions_model.ActionDependencySnapshot to api.ActionDependencySnapshot
+func ToActionDependencySnapshot(snapshot *actions_model.ActionDependencySnapshot) *api.ActionDependencySnapshot {
+	return &api.ActionDependencySnapshot{
+		Ref:       snapshot.Ref,
+		CommitSHA: snapshot.CommitSHA,
+		RunNumber: snapshot.RunIndex,
+		Manifests: ToActionDependencyManifests(snapshot.Manifests),
+		Updated:   snapshot.Updated.AsLocalTime(),
+	}
+}
+
+// ToActionDependencyChanges convert a list of actions_model.DependencyChange to a list of api.ActionDependencyChange
+func ToActionDependencyChanges(changes []*actions_model.DependencyChange) []*api.ActionDependencyChange {
+	res := make([]*api.ActionDependencyChange, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, &api.ActionDependencyChange{
+			ChangeType:      c.ChangeType,
+			Manifest:        c.Manifest,
+			Ecosystem:       c.Ecosystem,
+			Name:            c.Name,
+			Version:         c.Version,
+			PreviousVersion: c.PreviousVersion,
+			Scope:           c.Scope,
+		})
+	}
+	return res
+}
+
+// ToActionLicensePolicy convert actions_model.LicensePolicy to api.ActionLicensePolicy, it's nil if there isn't a policy
+func ToActionLicensePolicy(policy *actions_model.LicensePolicy) *api.ActionLicensePolicy {
+	if policy.IsEmpty() {
+		return nil
+	}
+	res := &api.ActionLicensePolicy{Allowed: policy.Allowed, Denied: policy.Denied}
+	if res.Allowed == nil {
+		res.Allowed = []string{}
+	}
+	if res.Denied == nil {
+		res.Denied = []string{}
+	}
+	return res
+}
+
+// ToActionLicenseScan convert actions_model.ActionLicenseScan to api.ActionLicenseScan
+func ToActionLicenseScan(scan *actions_model.ActionLicenseScan) *api.ActionLicenseScan {
+	violations := make([]*api.ActionLicenseViolation, 0, len(scan.Violations))
+	for _, v := range scan.Violations {
+		violations = append(violations, &api.ActionLicenseViolation{
+			Name:    v.Name,
+			Version: v.Version,
+			License: v.License,
+			Reason:  v.Reason,
+		})
+	}
+	return &api.ActionLicenseScan{
+		ID:         scan.ID,
+		TaskID:     scan.TaskID,
+		Passed:     scan.Passed,
+		Packages:   scan.Packages,
+		Violations: violations,
+		Created:    scan.Created.AsLocalTime(),
+	}
+}
+
+// ToActionEgressViolation convert actions_model.ActionEgressViolation to api.ActionEgressViolation
+func ToActionEgressViolation(v *actions_model.ActionEgressViolation) *api.ActionEgressViolation {
+	return &api.ActionEgressViolation{
+		ID:      v.ID,
+		TaskID:  v.TaskID,
+		Host:    v.Host,
+		Port:    v.Port,
+		Count:   v.Count,
+		Created: v.Created.AsLocalTime(),
+		Updated: v.Updated.AsLocalTime(),
+	}
+}
+
+func toActionRunJobRunner(runner *actions_model.ActionRunner) *api.ActionRunJobRunner {
+	if runner.OwnerID != 0 && runner.Owner == nil {
+		runner.Owner = user_model.NewGhostUser()
+	}
+	labels := runner.AgentLabels
+	if labels == nil {
+		labels = []string{}
+	}
+	return &api.ActionRunJobRunner{
+		ID:      runner.ID,
+		Name:    runner.Name,
+		Labels:  labels,
+		Group:   string(runner.BelongsToOwnerType()),
+		Deleted: runner.Deleted > 0,
+	}
+}
+
+// toActionRunJobLogsURL returns the url of the logs of the latest attempt of the job in the API,
+// it's empty for the jobs of the external CI systems since their logs are not stored
+func toActionRunJobLogsURL(run *actions_model.ActionRun, job *actions_model.ActionRunJob) string {
+	if job.IsExternal {
+		return ""
+	}
+	return fmt.Sprintf("%s/jobs/%d/logs", run.APIURL(), job.ID)
+}
+
+func toActionRunJob(job *actions_model.ActionRunJob, executionStarted, now timeutil.TimeStamp) *api.ActionRunJob {
+	apiJob := &api.ActionRunJob{
+		ID:          job.ID,
+		RunID:       job.RunID,
+		Name:        job.Name,
+		Component:   job.Component,
+		Status:      job.Status.String(),
+		Conclusion:  toActionConclusion(job.Status),
+		ExternalURL: job.ExternalURL,
+		Attempt:     job.Attempt,
+		Started:     job.Started.AsLocalTime(),
+		Stopped:     job.Stopped.AsLocalTime(),
+		Created:     job.Created.AsLocalTime(),
+		Environment: job.Environment,
+	}
+
+	if executionStarted > 0 {
+		t := executionStarted.AsLocalTime()
+		apiJob.ExecutionStarted = &t
+	}
+	// the phases can't be told apart without the queue time, either the job isn't ready yet
+	// or it was created before the queue time was recorded
+	if job.Queued == 0 {
+		return apiJob
+	}
+	t := job.Queued.AsLocalTime()
+	apiJob.Queued = &t
+
+	// every phase lasts until the next one starts, or the job stops, or now if the job is still running
+	phaseDuration := func(start timeutil.TimeStamp, ends ...timeutil.TimeStamp) int64 {
+		if start == 0 {
+			return 0
+		}
+		end := now
+		for _, v := range append(ends, job.Stopped) {
+			if v > 0 {
+				end = v
+				break
+			}
+		}
+		return max(int64(end-start), 0)
+	}
+	apiJob.Timings = &api.ActionRunJobTimings{
+		Blocked:   phaseDuration(job.Created, job.Queued),
+		Queued:    phaseDuration(job.Queued, job.Started),
+		Setup:     phaseDuration(job.Started, executionStarted),
+		Execution: phaseDuration(executionStarted),
+	}
+	return apiJob
+}
+
 // ToVerification convert a git.Commit.Signature to an api.PayloadCommitVerification
 func ToVerification(ctx context.Context, c *git.Commit) *api.PayloadCommitVerification {
 	verif := asymkey_model.ParseCommitWithSignature(ctx, c)
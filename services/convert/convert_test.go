@@ -0,0 +1,126 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToActionRunJobTimings(t *testing.T) {
+	const now timeutil.TimeStamp = 1000
+
+	cases := []struct {
+		name             string
+		job              *actions_model.ActionRunJob
+		executionStarted timeutil.TimeStamp
+		expected         *api.ActionRunJobTimings
+	}{
+		{
+			name: "blocked",
+			job:  &actions_model.ActionRunJob{Created: 100, Status: actions_model.StatusBlocked},
+		},
+		{
+			name: "created before the queue time was recorded",
+			job:  &actions_model.ActionRunJob{Created: 100, Started: 200, Stopped: 300, Status: actions_model.StatusSuccess},
+			// the execution start is still reported, but not the timings
+			executionStarted: 250,
+		},
+		{
+			name:     "waiting",
+			job:      &actions_model.ActionRunJob{Created: 100, Queued: 150, Status: actions_model.StatusWaiting},
+			expected: &api.ActionRunJobTimings{Blocked: 50, Queued: 850},
+		},
+		{
+			name:     "setting up",
+			job:      &actions_model.ActionRunJob{Created: 100, Queued: 150, Started: 400, Status: actions_model.StatusRunning},
+			expected: &api.ActionRunJobTimings{Blocked: 50, Queued: 250, Setup: 600},
+		},
+		{
+			name:             "running",
+			job:              &actions_model.ActionRunJob{Created: 100, Queued: 150, Started: 400, Status: actions_model.StatusRunning},
+			executionStarted: 450,
+			expected:         &api.ActionRunJobTimings{Blocked: 50, Queued: 250, Setup: 50, Execution: 550},
+		},
+		{
+			name:             "done",
+			job:              &actions_model.ActionRunJob{Created: 100, Queued: 150, Started: 400, Stopped: 700, Status: actions_model.StatusSuccess},
+			executionStarted: 450,
+			expected:         &api.ActionRunJobTimings{Blocked: 50, Queued: 250, Setup: 50, Execution: 250},
+		},
+		{
+			name:     "cancelled while waiting",
+			job:      &actions_model.ActionRunJob{Created: 100, Queued: 150, Stopped: 300, Status: actions_model.StatusCancelled},
+			expected: &api.ActionRunJobTimings{Blocked: 50, Queued: 150},
+		},
+		{
+			name:     "failed before any step",
+			job:      &actions_model.ActionRunJob{Created: 100, Queued: 150, Started: 400, Stopped: 500, Status: actions_model.StatusFailure},
+			expected: &api.ActionRunJobTimings{Blocked: 50, Queued: 250, Setup: 100},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			apiJob := toActionRunJob(c.job, c.executionStarted, now)
+			assert.Equal(t, c.expected, apiJob.Timings)
+			assert.Equal(t, c.job.Queued > 0, apiJob.Queued != nil)
+			assert.Equal(t, c.executionStarted > 0, apiJob.ExecutionStarted != nil)
+		})
+	}
+}
+
+func TestToActionRunSteps(t *testing.T) {
+	steps := ToActionRunSteps([]*actions_model.ActionTaskStep{
+		{Index: 0, Name: "Set up job", Status: actions_model.StatusSuccess, Started: 100, Stopped: 110},
+		{Index: 1, Name: "Build", Status: actions_model.StatusRunning, Started: 110},
+	})
+	if assert.Len(t, steps, 2) {
+		assert.EqualValues(t, 1, steps[0].Number)
+		assert.Equal(t, "success", steps[0].Status)
+		assert.EqualValues(t, 2, steps[1].Number)
+		assert.Equal(t, "Build", steps[1].Name)
+		assert.Equal(t, "running", steps[1].Status)
+	}
+}
+
+func TestToActionConclusion(t *testing.T) {
+	assert.Equal(t, "", toActionConclusion(actions_model.StatusRunning))
+	assert.Equal(t, "", toActionConclusion(actions_model.StatusBlocked))
+	assert.Equal(t, "failure", toActionConclusion(actions_model.StatusFailure))
+	assert.Equal(t, "cancelled", toActionConclusion(actions_model.StatusCancelled))
+
+	// every status has a name in the enums of the API, and only the final ones are conclusions
+	conclusions := []string{"success", "failure", "cancelled", "skipped"}
+	for s := actions_model.StatusUnknown; s <= actions_model.StatusBlocked; s++ {
+		assert.NotEmpty(t, s.String())
+		if s.IsDone() {
+			assert.Contains(t, conclusions, toActionConclusion(s))
+		} else {
+			assert.Empty(t, toActionConclusion(s))
+		}
+	}
+}
+
+func TestToActionTask(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	apiTask, err := ToActionTask(db.DefaultContext, task)
+	require.NoError(t, err)
+	assert.Equal(t, "running", apiTask.Status)
+	assert.Empty(t, apiTask.Conclusion)
+	assert.EqualValues(t, 192, apiTask.JobID)
+	assert.EqualValues(t, 3, apiTask.Attempt)
+	assert.Equal(t, setting.AppURL+"api/v1/repos/user5/repo4/actions/runs/187/jobs/192/logs", apiTask.LogsURL)
+	assert.Equal(t, setting.AppURL+"user5/repo4/actions/runs/187", apiTask.URL)
+}
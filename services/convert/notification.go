@@ -83,6 +83,13 @@ func ToNotificationThread(ctx context.Context, n *activities_model.Notification)
 			URL:     n.Repository.Link(),
 			HTMLURL: n.Repository.HTMLURL(),
 		}
+	case activities_model.NotificationSourceActionRun:
+		result.Subject = &api.NotificationSubject{Type: api.NotifySubjectActionRun}
+		if n.Run != nil {
+			result.Subject.Title = n.Run.Title
+			result.Subject.URL = n.Run.HTMLURL()
+			result.Subject.HTMLURL = n.Run.HTMLURL()
+		}
 	}
 
 	return result
@@ -11,7 +11,13 @@ import (
 // ToSecret converts Secret to API format
 func ToSecret(secret *secret_model.Secret) *api.Secret {
 	result := &api.Secret{
-		Name: secret.Name,
+		Name:              secret.Name,
+		ProtectedRefsOnly: secret.ProtectedRefsOnly,
+		Created:           secret.CreatedUnix.AsTime(),
+	}
+	if secret.IsLeaked() {
+		leaked := secret.LeakedUnix.AsTime()
+		result.Leaked = &leaked
 	}
 
 	return result
@@ -6,6 +6,7 @@ package convert
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	git_model "code.gitea.io/gitea/models/git"
 	user_model "code.gitea.io/gitea/models/user"
 	api "code.gitea.io/gitea/modules/structs"
@@ -24,7 +25,9 @@ func ToCommitStatus(ctx context.Context, status *git_model.CommitStatus) *api.Co
 		Context:     status.Context,
 	}
 
-	if status.CreatorID != 0 {
+	if status.CreatorID == user_model.ActionsUserID {
+		apiStatus.Creator = ToUser(ctx, actions_model.ApplyBotIdentity(ctx, status.Repo, user_model.NewActionsUser()), nil)
+	} else if status.CreatorID != 0 {
 		creator, _ := user_model.GetUserByID(ctx, status.CreatorID)
 		apiStatus.Creator = ToUser(ctx, creator, nil)
 	}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	actions_service "code.gitea.io/gitea/services/actions"
 )
@@ -18,7 +19,36 @@ func initActionsTasks() {
 	registerStopZombieTasks()
 	registerStopEndlessTasks()
 	registerCancelAbandonedJobs()
+	registerReconcileStuckJobs()
 	registerScheduleTasks()
+	registerDispatchExecutorTasks()
+	registerEmitPendingOutboxEvents()
+	registerRebuildActionUsagesIndex()
+	registerSendActionsFailureDigests()
+	registerCheckActionsQueueAlerts()
+	registerCheckActionsStorageHealth()
+	registerReleaseActionsFreezeWindows()
+}
+
+// leaderOnly makes the scheduled task run only on the leader of the instances sharing the database
+func leaderOnly(fn func(ctx context.Context) error) func(ctx context.Context, doer *user_model.User, cfg Config) error {
+	return func(ctx context.Context, doer *user_model.User, _ Config) error {
+		if !shouldRunActionsTask(doer) {
+			return nil
+		}
+		return fn(ctx)
+	}
+}
+
+// shouldRunActionsTask returns whether the Actions task should run on this instance,
+// the scheduled ones only run on the leader, so they don't race with the other instances,
+// while the ones triggered by admins run on any instance.
+func shouldRunActionsTask(doer *user_model.User) bool {
+	if doer.ID == -1 && !actions_service.IsLeader() {
+		log.Trace("Skip the scheduled task since this instance isn't the leader of Actions")
+		return false
+	}
+	return true
 }
 
 func registerStopZombieTasks() {
@@ -26,9 +56,7 @@ func registerStopZombieTasks() {
 		Enabled:    true,
 		RunAtStart: true,
 		Schedule:   "@every 5m",
-	}, func(ctx context.Context, _ *user_model.User, cfg Config) error {
-		return actions_service.StopZombieTasks(ctx)
-	})
+	}, leaderOnly(actions_service.StopZombieTasks))
 }
 
 func registerStopEndlessTasks() {
@@ -36,9 +64,7 @@ func registerStopEndlessTasks() {
 		Enabled:    true,
 		RunAtStart: true,
 		Schedule:   "@every 30m",
-	}, func(ctx context.Context, _ *user_model.User, cfg Config) error {
-		return actions_service.StopEndlessTasks(ctx)
-	})
+	}, leaderOnly(actions_service.StopEndlessTasks))
 }
 
 func registerCancelAbandonedJobs() {
@@ -46,9 +72,15 @@ func registerCancelAbandonedJobs() {
 		Enabled:    true,
 		RunAtStart: true,
 		Schedule:   "@every 6h",
-	}, func(ctx context.Context, _ *user_model.User, cfg Config) error {
-		return actions_service.CancelAbandonedJobs(ctx)
-	})
+	}, leaderOnly(actions_service.CancelAbandonedJobs))
+}
+
+func registerReconcileStuckJobs() {
+	RegisterTaskFatal("reconcile_stuck_jobs", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 30m",
+	}, leaderOnly(actions_service.ReconcileStuckJobs))
 }
 
 // registerScheduleTasks registers a scheduled task that runs every minute to start any due schedule tasks.
@@ -58,8 +90,64 @@ func registerScheduleTasks() {
 		Enabled:    true,
 		RunAtStart: false,
 		Schedule:   "@every 1m",
-	}, func(ctx context.Context, _ *user_model.User, cfg Config) error {
-		// Call the function to start schedule tasks and pass the context.
-		return actions_service.StartScheduleTasks(ctx)
+	}, leaderOnly(actions_service.StartScheduleTasks))
+}
+
+func registerDispatchExecutorTasks() {
+	RegisterTaskFatal("dispatch_executor_tasks", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 10s",
+	}, leaderOnly(actions_service.DispatchExecutorTasks))
+}
+
+func registerEmitPendingOutboxEvents() {
+	RegisterTaskFatal("emit_pending_actions_outbox_events", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 5m",
+	}, leaderOnly(actions_service.EmitPendingOutboxEvents))
+}
+
+func registerRebuildActionUsagesIndex() {
+	RegisterTaskFatal("rebuild_action_usages_index", &BaseConfig{
+		Enabled:    false,
+		RunAtStart: false,
+		Schedule:   "@annually",
+	}, leaderOnly(actions_service.RebuildActionUsagesIndex))
+}
+
+func registerSendActionsFailureDigests() {
+	RegisterTaskFatal("send_actions_failure_digests", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, leaderOnly(actions_service.SendFailureDigests))
+}
+
+func registerCheckActionsQueueAlerts() {
+	RegisterTaskFatal("check_actions_queue_alerts", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 5m",
+	}, leaderOnly(actions_service.CheckQueueAlerts))
+}
+
+// registerCheckActionsStorageHealth registers the health check of the storages, it runs on every instance since they could reach the storages differently
+func registerCheckActionsStorageHealth() {
+	RegisterTaskFatal("check_actions_storage_health", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return actions_service.CheckStorageHealth(ctx)
 	})
 }
+
+func registerReleaseActionsFreezeWindows() {
+	RegisterTaskFatal("release_actions_freeze_windows", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1m",
+	}, leaderOnly(actions_service.ReleaseFreezeWindows))
+}
@@ -165,7 +165,10 @@ func registerActionsCleanup() {
 			Schedule:   "@midnight",
 		},
 		OlderThan: 24 * time.Hour,
-	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+	}, func(ctx context.Context, doer *user_model.User, config Config) error {
+		if !shouldRunActionsTask(doer) {
+			return nil
+		}
 		realConfig := config.(*OlderThanConfig)
 		return actions.Cleanup(ctx, realConfig.OlderThan)
 	})
@@ -8,12 +8,15 @@ import (
 	"errors"
 	"io/fs"
 	"strings"
+	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/setting"
@@ -87,6 +90,7 @@ type checkStorageOptions struct {
 	RepoAvatars  bool
 	RepoArchives bool
 	Packages     bool
+	Actions      bool
 }
 
 // checkStorage will return a doctor check function to check the requested storage types for "orphaned" stored object/files and optionally delete them
@@ -114,9 +118,7 @@ func checkStorage(opts *checkStorageOptions) func(ctx context.Context, logger lo
 		if opts.LFS || opts.All {
 			if !setting.LFS.StartServer {
 				logger.Info("LFS isn't enabled (skipped)")
-				return nil
-			}
-			if err := commonCheckStorage(logger, autofix,
+			} else if err := commonCheckStorage(logger, autofix,
 				&commonStorageCheckOptions{
 					storer: storage.LFS,
 					isOrphaned: func(path string, obj storage.Object, stat fs.FileInfo) (bool, error) {
@@ -180,9 +182,7 @@ func checkStorage(opts *checkStorageOptions) func(ctx context.Context, logger lo
 		if opts.Packages || opts.All {
 			if !setting.Packages.Enabled {
 				logger.Info("Packages isn't enabled (skipped)")
-				return nil
-			}
-			if err := commonCheckStorage(logger, autofix,
+			} else if err := commonCheckStorage(logger, autofix,
 				&commonStorageCheckOptions{
 					storer: storage.Packages,
 					isOrphaned: func(path string, obj storage.Object, stat fs.FileInfo) (bool, error) {
@@ -203,8 +203,105 @@ func checkStorage(opts *checkStorageOptions) func(ctx context.Context, logger lo
 			}
 		}
 
+		if opts.Actions || opts.All {
+			if !setting.Actions.Enabled {
+				logger.Info("Actions isn't enabled (skipped)")
+			} else if err := checkActionsStorage(ctx, logger, autofix); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// actionsStorageGracePeriod is how long the objects of Actions aren't considered orphaned after they are written,
+// since the logs and the artifacts are saved to the storages before their records are updated.
+const actionsStorageGracePeriod = 24 * time.Hour
+
+// checkActionsStorage checks the storages of the logs and the artifacts of Actions for the orphaned objects without records,
+// and for the dangling records whose objects are missing, which are marked as expired if autofix is set.
+func checkActionsStorage(ctx context.Context, logger log.Logger, autofix bool) error {
+	tasks, err := actions_model.FindTasksWithStoredLogs(ctx)
+	if err != nil {
+		logger.Error("FindTasksWithStoredLogs: %v", err)
+		return err
+	}
+	logFilenames := make(container.Set[string], len(tasks))
+	for _, task := range tasks {
+		logFilenames.Add(task.LogFilename)
+	}
+	existingLogs := make(container.Set[string])
+	if err := commonCheckStorage(logger, autofix,
+		&commonStorageCheckOptions{
+			storer: storage.Actions,
+			isOrphaned: func(path string, obj storage.Object, stat fs.FileInfo) (bool, error) {
+				existingLogs.Add(path)
+				return !logFilenames.Contains(path) && time.Since(stat.ModTime()) > actionsStorageGracePeriod, nil
+			},
+			name: "actions log",
+		}); err != nil {
+		return err
+	}
+	var danglingTaskIDs []int64
+	for _, task := range tasks {
+		if !existingLogs.Contains(task.LogFilename) {
+			danglingTaskIDs = append(danglingTaskIDs, task.ID)
+		}
+	}
+	if err := fixDanglingActionsRecords(logger, autofix, "actions log", danglingTaskIDs, func() error {
+		return actions_model.SetTasksLogExpired(ctx, danglingTaskIDs)
+	}); err != nil {
+		return err
+	}
+
+	artifacts, err := actions_model.FindStoredArtifacts(ctx)
+	if err != nil {
+		logger.Error("FindStoredArtifacts: %v", err)
+		return err
+	}
+	artifactPaths := make(container.Set[string], len(artifacts))
+	for _, artifact := range artifacts {
+		artifactPaths.Add(artifact.StoragePath)
+	}
+	existingArtifacts := make(container.Set[string])
+	if err := commonCheckStorage(logger, autofix,
+		&commonStorageCheckOptions{
+			storer: storage.ActionsArtifacts,
+			isOrphaned: func(path string, obj storage.Object, stat fs.FileInfo) (bool, error) {
+				existingArtifacts.Add(path)
+				return !artifactPaths.Contains(path) && time.Since(stat.ModTime()) > actionsStorageGracePeriod, nil
+			},
+			name: "actions artifact",
+		}); err != nil {
+		return err
+	}
+	var danglingArtifactIDs []int64
+	for _, artifact := range artifacts {
+		// the artifacts pending deletion are going to be deleted anyway
+		if artifact.Status == int64(actions_model.ArtifactStatusUploadConfirmed) && !existingArtifacts.Contains(artifact.StoragePath) {
+			danglingArtifactIDs = append(danglingArtifactIDs, artifact.ID)
+		}
+	}
+	return fixDanglingActionsRecords(logger, autofix, "actions artifact", danglingArtifactIDs, func() error {
+		return actions_model.SetArtifactsExpired(ctx, danglingArtifactIDs)
+	})
+}
+
+func fixDanglingActionsRecords(logger log.Logger, autofix bool, name string, ids []int64, fix func() error) error {
+	if len(ids) == 0 {
 		return nil
 	}
+	if !autofix {
+		logger.Warn("Found %d dangling %s record(s) whose files are missing: %v", len(ids), name, ids)
+		return nil
+	}
+	if err := fix(); err != nil {
+		logger.Error("Error whilst marking the dangling %s records as expired: %v", name, err)
+		return err
+	}
+	logger.Info("Marked %d dangling %s record(s) as expired", len(ids), name)
+	return nil
 }
 
 func init() {
@@ -267,4 +364,14 @@ func init() {
 		SkipDatabaseInitialization: false,
 		Priority:                   1,
 	})
+
+	Register(&Check{
+		Title:                      "Check if there are orphaned actions logs and artifacts in storage, or records whose files are missing",
+		Name:                       "storage-actions",
+		IsDefault:                  false,
+		Run:                        checkStorage(&checkStorageOptions{Actions: true}),
+		AbortIfFailed:              false,
+		SkipDatabaseInitialization: false,
+		Priority:                   1,
+	})
 }
@@ -0,0 +1,81 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckActionsStorage(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	logsDir, artifactsDir := t.TempDir(), t.TempDir()
+	logs, err := storage.NewLocalStorage(context.Background(), &setting.Storage{Path: logsDir})
+	require.NoError(t, err)
+	artifacts, err := storage.NewLocalStorage(context.Background(), &setting.Storage{Path: artifactsDir})
+	require.NoError(t, err)
+	defer test.MockVariableValue(&storage.Actions, logs)()
+	defer test.MockVariableValue(&storage.ActionsArtifacts, artifacts)()
+
+	save := func(s storage.ObjectStorage, dir, path string, modTime time.Time) {
+		_, err := s.Save(path, strings.NewReader("content"), -1)
+		require.NoError(t, err)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, path), modTime, modTime))
+	}
+	old := time.Now().Add(-2 * actionsStorageGracePeriod)
+
+	// the logs of the tasks 47 and 48 are in the storage
+	save(logs, logsDir, "artifact-test2/2f/47.log", old)
+	save(logs, logsDir, "orphaned.log", old)
+	save(logs, logsDir, "transferring.log", time.Now())
+	// the logs of the task 48 are lost
+	_, err = db.GetEngine(ctx).ID(48).Cols("log_filename").Update(&actions_model.ActionTask{LogFilename: "lost.log"})
+	require.NoError(t, err)
+
+	save(artifacts, artifactsDir, "1/1/uploaded.zip", old)
+	save(artifacts, artifactsDir, "tmp1/1-1-0-7.chunk", old)
+	uploaded := &actions_model.ActionArtifact{RunID: 791, ArtifactName: "uploaded", ArtifactPath: "uploaded.zip", StoragePath: "1/1/uploaded.zip", Status: int64(actions_model.ArtifactStatusUploadConfirmed)}
+	lost := &actions_model.ActionArtifact{RunID: 791, ArtifactName: "lost", ArtifactPath: "lost.zip", StoragePath: "1/2/lost.zip", Status: int64(actions_model.ArtifactStatusUploadConfirmed)}
+	require.NoError(t, db.Insert(ctx, uploaded, lost))
+
+	exists := func(dir, path string) bool {
+		_, err := os.Stat(filepath.Join(dir, path))
+		return err == nil
+	}
+
+	// dry run
+	require.NoError(t, checkActionsStorage(ctx, log.GetManager().GetLogger(log.DEFAULT), false))
+	assert.True(t, exists(logsDir, "orphaned.log"))
+	assert.True(t, exists(artifactsDir, "tmp1/1-1-0-7.chunk"))
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 48}).LogExpired)
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{ID: lost.ID, Status: int64(actions_model.ArtifactStatusUploadConfirmed)})
+
+	require.NoError(t, checkActionsStorage(ctx, log.GetManager().GetLogger(log.DEFAULT), true))
+	assert.True(t, exists(logsDir, "artifact-test2/2f/47.log"))
+	assert.False(t, exists(logsDir, "orphaned.log"))
+	assert.True(t, exists(logsDir, "transferring.log"))
+	assert.True(t, exists(artifactsDir, "1/1/uploaded.zip"))
+	assert.False(t, exists(artifactsDir, "tmp1/1-1-0-7.chunk"))
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47}).LogExpired)
+	assert.True(t, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 48}).LogExpired)
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{ID: uploaded.ID, Status: int64(actions_model.ArtifactStatusUploadConfirmed)})
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{ID: lost.ID, Status: int64(actions_model.ArtifactStatusExpired)})
+}
@@ -43,6 +43,8 @@ type CreateRepoForm struct {
 	Avatar          bool
 	Labels          bool
 	ProtectedBranch bool
+	// WorkflowSchedules registers the schedules of the workflows in the git content
+	WorkflowSchedules bool
 
 	ForkSingleBranch string
 	ObjectFormatName string
@@ -251,6 +253,7 @@ type WebhookForm struct {
 	Wiki                     bool
 	Repository               bool
 	Package                  bool
+	WorkflowRun              bool
 	Active                   bool
 	BranchFilter             string `binding:"GlobPattern"`
 	AuthorizationHeader      string
@@ -324,8 +324,10 @@ func (f *AddKeyForm) Validate(req *http.Request, errs binding.Errors) binding.Er
 
 // AddSecretForm for adding secrets
 type AddSecretForm struct {
-	Name string `binding:"Required;MaxSize(255)"`
-	Data string `binding:"Required;MaxSize(65535)"`
+	Name              string `binding:"Required;MaxSize(255)"`
+	Data              string `binding:"Required;MaxSize(65535)"`
+	ProtectedRefsOnly bool
+	Edit              bool // the form is submitted to edit an existing secret, and shows its current restriction
 }
 
 // Validate validates the fields
@@ -41,33 +41,77 @@ type ReviewRequestNotifier struct {
 }
 
 func PullRequestCodeOwnersReview(ctx context.Context, issue *issues_model.Issue, pr *issues_model.PullRequest) ([]*ReviewRequestNotifier, error) {
-	files := []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitea/CODEOWNERS"}
-
 	if pr.IsWorkInProgress(ctx) {
 		return nil, nil
 	}
 
-	if err := pr.LoadHeadRepo(ctx); err != nil {
+	uniqUsers, uniqTeams, err := GetPullRequestCodeOwners(ctx, pr)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := pr.LoadBaseRepo(ctx); err != nil {
+	notifiers := make([]*ReviewRequestNotifier, 0, len(uniqUsers)+len(uniqTeams))
+
+	if err := issue.LoadPoster(ctx); err != nil {
 		return nil, err
 	}
 
+	for _, u := range uniqUsers {
+		if u.ID != issue.Poster.ID {
+			comment, err := issues_model.AddReviewRequest(ctx, issue, u, issue.Poster)
+			if err != nil {
+				log.Warn("Failed add assignee user: %s to PR review: %s#%d, error: %s", u.Name, pr.BaseRepo.Name, pr.ID, err)
+				return nil, err
+			}
+			notifiers = append(notifiers, &ReviewRequestNotifier{
+				Comment:  comment,
+				IsAdd:    true,
+				Reviewer: u,
+			})
+		}
+	}
+	for _, t := range uniqTeams {
+		comment, err := issues_model.AddTeamReviewRequest(ctx, issue, t, issue.Poster)
+		if err != nil {
+			log.Warn("Failed add assignee team: %s to PR review: %s#%d, error: %s", t.Name, pr.BaseRepo.Name, pr.ID, err)
+			return nil, err
+		}
+		notifiers = append(notifiers, &ReviewRequestNotifier{
+			Comment:    comment,
+			IsAdd:      true,
+			ReviewTeam: t,
+		})
+	}
+
+	return notifiers, nil
+}
+
+// GetPullRequestCodeOwners returns the users and the teams owning the files changed by the pull request,
+// by the CODEOWNERS file of the default branch of the base repository
+func GetPullRequestCodeOwners(ctx context.Context, pr *issues_model.PullRequest) (map[int64]*user_model.User, map[string]*org_model.Team, error) {
+	files := []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitea/CODEOWNERS"}
+
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	if pr.BaseRepo.IsFork {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	repo, err := gitrepo.OpenRepository(ctx, pr.BaseRepo)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer repo.Close()
 
 	commit, err := repo.GetBranchCommit(pr.BaseRepo.DefaultBranch)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var data string
@@ -85,14 +129,14 @@ func PullRequestCodeOwnersReview(ctx context.Context, issue *issues_model.Issue,
 	// get the mergebase
 	mergeBase, err := getMergeBase(repo, pr, git.BranchPrefix+pr.BaseBranch, pr.GetGitRefName())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// https://github.com/go-gitea/gitea/issues/29763, we need to get the files changed
 	// between the merge base and the head commit but not the base branch and the head commit
 	changedFiles, err := repo.GetFilesChangedBetween(mergeBase, pr.GetGitRefName())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	uniqUsers := make(map[int64]*user_model.User)
@@ -110,38 +154,5 @@ func PullRequestCodeOwnersReview(ctx context.Context, issue *issues_model.Issue,
 		}
 	}
 
-	notifiers := make([]*ReviewRequestNotifier, 0, len(uniqUsers)+len(uniqTeams))
-
-	if err := issue.LoadPoster(ctx); err != nil {
-		return nil, err
-	}
-
-	for _, u := range uniqUsers {
-		if u.ID != issue.Poster.ID {
-			comment, err := issues_model.AddReviewRequest(ctx, issue, u, issue.Poster)
-			if err != nil {
-				log.Warn("Failed add assignee user: %s to PR review: %s#%d, error: %s", u.Name, pr.BaseRepo.Name, pr.ID, err)
-				return nil, err
-			}
-			notifiers = append(notifiers, &ReviewRequestNotifier{
-				Comment:  comment,
-				IsAdd:    true,
-				Reviewer: u,
-			})
-		}
-	}
-	for _, t := range uniqTeams {
-		comment, err := issues_model.AddTeamReviewRequest(ctx, issue, t, issue.Poster)
-		if err != nil {
-			log.Warn("Failed add assignee team: %s to PR review: %s#%d, error: %s", t.Name, pr.BaseRepo.Name, pr.ID, err)
-			return nil, err
-		}
-		notifiers = append(notifiers, &ReviewRequestNotifier{
-			Comment:    comment,
-			IsAdd:      true,
-			ReviewTeam: t,
-		})
-	}
-
-	return notifiers, nil
+	return uniqUsers, uniqTeams, nil
 }
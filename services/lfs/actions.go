@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package lfs
+
+import (
+	stdCtx "context"
+	"errors"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/perm"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// CreateActionsTaskToken creates a token of the LFS of the repository of the task, so the jobs could fetch and push the LFS objects
+// without storing the access tokens of users as secrets. It's only accepted by the LFS server while the task is running,
+// and it could upload objects only if the token of the task could write the repository.
+func CreateActionsTaskToken(ctx stdCtx.Context, task *actions_model.ActionTask) (string, error) {
+	mode, err := task.TokenAccessMode(ctx)
+	if err != nil {
+		return "", err
+	}
+	op := "download"
+	if mode >= perm.AccessModeWrite {
+		op = "upload"
+	}
+
+	// the tasks running longer than the endless task timeout are stopped
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(setting.Actions.EndlessTaskTimeout)),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		RepoID: task.RepoID,
+		Op:     op,
+		UserID: user_model.ActionsUserID,
+		TaskID: task.ID,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(setting.LFS.JWTSecretBytes)
+}
+
+// handleActionsTaskToken returns the actions user if the task of the token is still running in the repository
+func handleActionsTaskToken(ctx stdCtx.Context, claims *Claims) (*user_model.User, error) {
+	task, err := actions_model.GetTaskByID(ctx, claims.TaskID)
+	if err != nil {
+		if !errors.Is(err, util.ErrNotExist) {
+			log.Error("Unable to GetTaskByID[%d]: Error: %v", claims.TaskID, err)
+			return nil, err
+		}
+		return nil, fmt.Errorf("invalid token claim")
+	}
+	if task.RepoID != claims.RepoID || task.Status.IsDone() {
+		return nil, fmt.Errorf("invalid token claim")
+	}
+	return user_model.NewActionsUser(), nil
+}
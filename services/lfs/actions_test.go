@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package lfs
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsTaskToken(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 47})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: task.RepoID})
+	token, err := CreateActionsTaskToken(db.DefaultContext, task)
+	require.NoError(t, err)
+
+	u, err := parseToken(db.DefaultContext, "Bearer "+token, repo, perm.AccessModeRead)
+	require.NoError(t, err)
+	assert.True(t, u.IsActions())
+
+	// the token is only valid for the repository of the task
+	other := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	_, err = parseToken(db.DefaultContext, "Bearer "+token, other, perm.AccessModeRead)
+	assert.Error(t, err)
+
+	// the token is invalid after the task is done
+	task.Status = actions_model.StatusSuccess
+	_, err = db.GetEngine(db.DefaultContext).ID(task.ID).Cols("status").Update(task)
+	require.NoError(t, err)
+	_, err = parseToken(db.DefaultContext, "Bearer "+token, repo, perm.AccessModeRead)
+	assert.Error(t, err)
+}
@@ -48,6 +48,7 @@ type Claims struct {
 	RepoID int64
 	Op     string
 	UserID int64
+	TaskID int64 `json:",omitempty"` // the task of Actions which the token is created for, see CreateActionsTaskToken
 	jwt.RegisteredClaims
 }
 
@@ -520,10 +521,12 @@ func authenticate(ctx *context.Context, repository *repo_model.Repository, autho
 			return false
 		}
 
-		if task.IsForkPullRequest {
-			return accessMode <= perm.AccessModeRead
+		taskAccessMode, err := task.TokenAccessMode(ctx)
+		if err != nil {
+			log.Error("Unable to get the access mode of task[%d] Error: %v", taskID, err)
+			return false
 		}
-		return accessMode <= perm.AccessModeWrite
+		return accessMode <= taskAccessMode
 	}
 
 	// ctx.IsSigned is unnecessary here, this will be checked in perm.CanAccess
@@ -575,6 +578,10 @@ func handleLFSToken(ctx stdCtx.Context, tokenSHA string, target *repo_model.Repo
 		return nil, fmt.Errorf("invalid token claim")
 	}
 
+	if claims.TaskID > 0 {
+		return handleActionsTaskToken(ctx, claims)
+	}
+
 	u, err := user_model.GetUserByID(ctx, claims.UserID)
 	if err != nil {
 		log.Error("Unable to GetUserById[%d]: Error: %v", claims.UserID, err)
@@ -0,0 +1,330 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const (
+	tplActionRunCommentMail         base.TplName = "actions/run_comment"
+	tplActionsFailureDigestMail     base.TplName = "actions/failure_digest"
+	tplActionRunApprovalRequestMail base.TplName = "actions/approval_request"
+	tplActionsSecretsLeakedMail     base.TplName = "actions/secrets_leaked"
+)
+
+// MailActionRunComment sends the comment of a run to the mentioned users and the participants of the run,
+// who are the user triggering the run and the users having commented on it.
+func MailActionRunComment(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, comment *actions_model.ActionRunComment, mentions []*user_model.User) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+
+	recipients, err := actionRunCommentRecipients(ctx, doer, run, mentions)
+	if err != nil {
+		return err
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+	for lang, tos := range langMap {
+		mailActionRunComment(ctx, lang, tos, doer, run, comment)
+	}
+	return nil
+}
+
+// actionRunCommentRecipients returns the users who should receive the mail of a comment on the run,
+// the mentioned users are notified unless they disabled all mails, the participants only if they enabled them.
+func actionRunCommentRecipients(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, mentions []*user_model.User) ([]*user_model.User, error) {
+	participantIDs := container.SetOf(run.TriggerUserID)
+	posterIDs, err := actions_model.FindRunCommentsPosterIDs(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	participantIDs.AddMultiple(posterIDs...)
+
+	mentionIDs := make(container.Set[int64], len(mentions))
+	for _, u := range mentions {
+		mentionIDs.Add(u.ID)
+		participantIDs.Remove(u.ID)
+	}
+	participantIDs.Remove(doer.ID)
+	mentionIDs.Remove(doer.ID)
+
+	visited := make(container.Set[int64])
+	recipients := make([]*user_model.User, 0, len(mentionIDs)+len(participantIDs))
+	for _, v := range []struct {
+		ids       container.Set[int64]
+		isMention bool
+	}{{mentionIDs, true}, {participantIDs, false}} {
+		users, err := user_model.GetMaileableUsersByIDs(ctx, v.ids.Values(), v.isMention)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if !visited.Add(user.ID) {
+				continue
+			}
+			if !access_model.CheckRepoUnitUser(ctx, run.Repo, user, unit.TypeActions) {
+				continue
+			}
+			recipients = append(recipients, user)
+		}
+	}
+	return recipients, nil
+}
+
+func mailActionRunComment(ctx context.Context, lang string, tos []string, doer *user_model.User, run *actions_model.ActionRun, comment *actions_model.ActionRunComment) {
+	locale := translation.NewLocale(lang)
+
+	rendered, err := markdown.RenderString(&markup.RenderContext{
+		Ctx: ctx,
+		Links: markup.Links{
+			Base: run.Repo.HTMLURL(),
+		},
+		Metas: run.Repo.ComposeMetas(ctx),
+	}, comment.Content)
+	if err != nil {
+		log.Error("markdown.RenderString(%d): %v", run.RepoID, err)
+		return
+	}
+
+	subject := locale.TrString("mail.actions.run_comment.subject", run.WorkflowID, run.Index, run.Repo.FullName())
+	mailMeta := map[string]any{
+		"locale":          locale,
+		"Run":             run,
+		"Doer":            doer,
+		"RenderedContent": rendered,
+		"Subject":         subject,
+		"Language":        locale.Language(),
+		"Link":            run.HTMLURL(),
+	}
+
+	var mailBody bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplActionRunCommentMail), mailMeta); err != nil {
+		log.Error("ExecuteTemplate [%s]: %v", string(tplActionRunCommentMail)+"/body", err)
+		return
+	}
+
+	msgs := make([]*Message, 0, len(tos))
+	for _, to := range tos {
+		msg := NewMessageFrom(to, doer.DisplayName(), setting.MailService.FromEmail, subject, mailBody.String())
+		msg.Info = subject
+		msg.SetHeader("Message-ID", generateMessageIDForActionRunComment(run, comment))
+		msg.SetHeader("In-Reply-To", generateMessageIDForActionRun(run))
+		msg.SetHeader("References", generateMessageIDForActionRun(run))
+		msgs = append(msgs, msg)
+	}
+
+	SendAsync(msgs...)
+}
+
+// MailActionRunApprovalRequest asks the approvers of the run to approve it, they are notified unless they disabled all mails.
+// isCodeOwners is whether they are asked as the code owners of the files changed by the pull request of the run.
+func MailActionRunApprovalRequest(ctx context.Context, run *actions_model.ActionRun, approvers []*user_model.User, isCodeOwners bool) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(approvers))
+	for _, u := range approvers {
+		ids = append(ids, u.ID)
+	}
+	recipients, err := user_model.GetMaileableUsersByIDs(ctx, ids, true)
+	if err != nil {
+		return err
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+	for lang, tos := range langMap {
+		mailActionRunApprovalRequest(lang, tos, run, isCodeOwners)
+	}
+	return nil
+}
+
+func mailActionRunApprovalRequest(lang string, tos []string, run *actions_model.ActionRun, isCodeOwners bool) {
+	locale := translation.NewLocale(lang)
+
+	subject := locale.TrString("mail.actions.approval_request.subject", run.WorkflowID, run.Index, run.Repo.FullName())
+	mailMeta := map[string]any{
+		"locale":       locale,
+		"Run":          run,
+		"IsCodeOwners": isCodeOwners,
+		"Subject":      subject,
+		"Language":     locale.Language(),
+		"Link":         run.HTMLURL(),
+	}
+
+	var mailBody bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplActionRunApprovalRequestMail), mailMeta); err != nil {
+		log.Error("ExecuteTemplate [%s]: %v", string(tplActionRunApprovalRequestMail)+"/body", err)
+		return
+	}
+
+	msgs := make([]*Message, 0, len(tos))
+	for _, to := range tos {
+		msg := NewMessageFrom(to, run.TriggerUser.DisplayName(), setting.MailService.FromEmail, subject, mailBody.String())
+		msg.Info = subject
+		msg.SetHeader("Message-ID", generateMessageIDForActionRun(run))
+		msgs = append(msgs, msg)
+	}
+
+	SendAsync(msgs...)
+}
+
+// MailActionsSecretsLeaked alerts the admins of the repository that the values of the secrets were found in the logs of the task,
+// the mails only have the names of the secrets. deleted is whether the secrets have been deleted.
+func MailActionsSecretsLeaked(ctx context.Context, task *actions_model.ActionTask, names []string, deleted bool, admins []*user_model.User) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		return err
+	}
+	if err := task.Job.LoadAttributes(ctx); err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(admins))
+	for _, u := range admins {
+		ids = append(ids, u.ID)
+	}
+	recipients, err := user_model.GetMaileableUsersByIDs(ctx, ids, true)
+	if err != nil {
+		return err
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+	for lang, tos := range langMap {
+		mailActionsSecretsLeaked(lang, tos, task, names, deleted)
+	}
+	return nil
+}
+
+func mailActionsSecretsLeaked(lang string, tos []string, task *actions_model.ActionTask, names []string, deleted bool) {
+	locale := translation.NewLocale(lang)
+	run := task.Job.Run
+
+	subject := locale.TrString("mail.actions.secrets_leaked.subject", run.WorkflowID, run.Index, run.Repo.FullName())
+	mailMeta := map[string]any{
+		"locale":      locale,
+		"Run":         run,
+		"Job":         task.Job,
+		"Names":       names,
+		"Deleted":     deleted,
+		"Subject":     subject,
+		"Language":    locale.Language(),
+		"Link":        run.HTMLURL(),
+		"SecretsLink": run.Repo.HTMLURL() + "/settings/actions/secrets",
+	}
+
+	var mailBody bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplActionsSecretsLeakedMail), mailMeta); err != nil {
+		log.Error("ExecuteTemplate [%s]: %v", string(tplActionsSecretsLeakedMail)+"/body", err)
+		return
+	}
+
+	msgs := make([]*Message, 0, len(tos))
+	for _, to := range tos {
+		msg := NewMessage(to, subject, mailBody.String())
+		msg.Info = subject
+		msg.SetHeader("Message-ID", generateMessageIDForActionRun(run))
+		msgs = append(msgs, msg)
+	}
+
+	SendAsync(msgs...)
+}
+
+func generateMessageIDForActionRun(run *actions_model.ActionRun) string {
+	return fmt.Sprintf("<%s/actions/runs/%d@%s>", run.Repo.FullName(), run.Index, setting.Domain)
+}
+
+func generateMessageIDForActionRunComment(run *actions_model.ActionRun, comment *actions_model.ActionRunComment) string {
+	return fmt.Sprintf("<%s/actions/runs/%d/comment/%d@%s>", run.Repo.FullName(), run.Index, comment.ID, setting.Domain)
+}
+
+// ActionsFailureDigestItem is a workflow of a repository in the digest of the failed and flaky workflows
+type ActionsFailureDigestItem struct {
+	Repo     *repo_model.Repository
+	Failures *actions_model.WorkflowFailures
+}
+
+// WorkflowLink returns the link to the runs of the workflow
+func (item *ActionsFailureDigestItem) WorkflowLink() string {
+	return item.Repo.HTMLURL() + actions_model.WorkflowPath(item.Failures.WorkflowID)
+}
+
+// LatestFailedRunLink returns the link to the latest failed run of the workflow, empty if no run failed
+func (item *ActionsFailureDigestItem) LatestFailedRunLink() string {
+	if item.Failures.LatestFailedRunIndex == 0 {
+		return ""
+	}
+	return item.Repo.HTMLURL() + actions_model.RunPath(item.Failures.LatestFailedRunIndex)
+}
+
+// SendActionsFailureDigestMail sends the digest of the failed and flaky workflows of the period to the user
+func SendActionsFailureDigestMail(u *user_model.User, weekly bool, items []*ActionsFailureDigestItem) {
+	if setting.MailService == nil {
+		// No mail service configured
+		return
+	}
+	locale := translation.NewLocale(u.Language)
+
+	subject := locale.TrString("mail.actions.failure_digest.daily_subject", len(items))
+	if weekly {
+		subject = locale.TrString("mail.actions.failure_digest.weekly_subject", len(items))
+	}
+	data := map[string]any{
+		"locale":      locale,
+		"Subject":     subject,
+		"Language":    locale.Language(),
+		"DisplayName": u.DisplayName(),
+		"Weekly":      weekly,
+		"Items":       items,
+		"SettingLink": setting.AppURL + "user/settings/account",
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(tplActionsFailureDigestMail), data); err != nil {
+		log.Error("ExecuteTemplate [%s]: %v", string(tplActionsFailureDigestMail), err)
+		return
+	}
+
+	msg := NewMessage(u.Email, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, actions failure digest", u.ID)
+
+	SendAsync(msg)
+}
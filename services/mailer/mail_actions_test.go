@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRunCommentRecipients(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	run := &actions_model.ActionRun{RepoID: repo.ID, OwnerID: repo.OwnerID, Index: 1, TriggerUserID: 8, Status: actions_model.StatusSuccess}
+	assert.NoError(t, db.Insert(db.DefaultContext, run))
+	run.Repo = repo
+	// user 4 only wants mails when mentioned, user 9 is inactive
+	for _, posterID := range []int64{2, 4, 9, 10} {
+		assert.NoError(t, db.Insert(db.DefaultContext, &actions_model.ActionRunComment{RepoID: repo.ID, RunID: run.ID, PosterID: posterID, Content: "comment"}))
+	}
+
+	recipientIDs := func(mentions ...int64) []int64 {
+		users := make([]*user_model.User, 0, len(mentions))
+		for _, id := range mentions {
+			users = append(users, unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: id}))
+		}
+		recipients, err := actionRunCommentRecipients(db.DefaultContext, doer, run, users)
+		assert.NoError(t, err)
+		ids := make([]int64, 0, len(recipients))
+		for _, u := range recipients {
+			ids = append(ids, u.ID)
+		}
+		return ids
+	}
+
+	// the trigger user and the commenters enabling all mails, without the doer
+	assert.ElementsMatch(t, []int64{8, 10}, recipientIDs())
+	// mentioned users receive the mail unless they disabled all mails, the doer never does
+	assert.ElementsMatch(t, []int64{4, 8, 10}, recipientIDs(2, 4))
+	// a user mentioned and participating receives one mail
+	assert.ElementsMatch(t, []int64{8, 10}, recipientIDs(10))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -202,3 +203,9 @@ func (m *mailNotifier) RepoPendingTransfer(ctx context.Context, doer, newOwner *
 		log.Error("SendRepoTransferNotifyMail: %v", err)
 	}
 }
+
+func (m *mailNotifier) CreateActionRunComment(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, comment *actions_model.ActionRunComment, mentions []*user_model.User) {
+	if err := MailActionRunComment(ctx, doer, run, comment, mentions); err != nil {
+		log.Error("MailActionRunComment: %v", err)
+	}
+}
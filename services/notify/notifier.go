@@ -6,6 +6,7 @@ package notify
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -74,4 +75,7 @@ type Notifier interface {
 	PackageDelete(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor)
 
 	ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository)
+
+	CreateActionRunComment(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, comment *actions_model.ActionRunComment, mentions []*user_model.User)
+	ActionRunCompleted(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob)
 }
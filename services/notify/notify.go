@@ -6,6 +6,7 @@ package notify
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -367,3 +368,17 @@ func ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 		notifier.ChangeDefaultBranch(ctx, repo)
 	}
 }
+
+// CreateActionRunComment notifies a comment of an Actions run to notifiers
+func CreateActionRunComment(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, comment *actions_model.ActionRunComment, mentions []*user_model.User) {
+	for _, notifier := range notifiers {
+		notifier.CreateActionRunComment(ctx, doer, run, comment, mentions)
+	}
+}
+
+// ActionRunCompleted notifies the completion of an Actions run to notifiers
+func ActionRunCompleted(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) {
+	for _, notifier := range notifiers {
+		notifier.ActionRunCompleted(ctx, run, jobs)
+	}
+}
@@ -6,6 +6,7 @@ package notify
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -208,3 +209,11 @@ func (*NullNotifier) PackageDelete(ctx context.Context, doer *user_model.User, p
 // ChangeDefaultBranch places a place holder function
 func (*NullNotifier) ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 }
+
+// CreateActionRunComment places a place holder function
+func (*NullNotifier) CreateActionRunComment(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, comment *actions_model.ActionRunComment, mentions []*user_model.User) {
+}
+
+// ActionRunCompleted places a place holder function
+func (*NullNotifier) ActionRunCompleted(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) {
+}
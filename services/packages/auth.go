@@ -18,10 +18,20 @@ import (
 
 type packageClaims struct {
 	jwt.RegisteredClaims
-	UserID int64
+	UserID        int64
+	ActionsTaskID int64 `json:",omitempty"`
 }
 
 func CreateAuthorizationToken(u *user_model.User) (string, error) {
+	return createAuthorizationToken(u.ID, 0)
+}
+
+// CreateActionsAuthorizationToken creates a token of the actions user on behalf of a task
+func CreateActionsAuthorizationToken(taskID int64) (string, error) {
+	return createAuthorizationToken(user_model.ActionsUserID, taskID)
+}
+
+func createAuthorizationToken(userID, actionsTaskID int64) (string, error) {
 	now := time.Now()
 
 	claims := packageClaims{
@@ -29,7 +39,8 @@ func CreateAuthorizationToken(u *user_model.User) (string, error) {
 			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
 			NotBefore: jwt.NewNumericDate(now),
 		},
-		UserID: u.ID,
+		UserID:        userID,
+		ActionsTaskID: actionsTaskID,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -42,15 +53,21 @@ func CreateAuthorizationToken(u *user_model.User) (string, error) {
 }
 
 func ParseAuthorizationToken(req *http.Request) (int64, error) {
+	userID, _, err := ParseActionsAuthorizationToken(req)
+	return userID, err
+}
+
+// ParseActionsAuthorizationToken returns the user and the actions task of the token, the task is 0 if the token isn't created for a task
+func ParseActionsAuthorizationToken(req *http.Request) (userID, actionsTaskID int64, err error) {
 	h := req.Header.Get("Authorization")
 	if h == "" {
-		return 0, nil
+		return 0, 0, nil
 	}
 
 	parts := strings.SplitN(h, " ", 2)
 	if len(parts) != 2 {
 		log.Error("split token failed: %s", h)
-		return 0, fmt.Errorf("split token failed")
+		return 0, 0, fmt.Errorf("split token failed")
 	}
 
 	token, err := jwt.ParseWithClaims(parts[1], &packageClaims{}, func(t *jwt.Token) (any, error) {
@@ -60,13 +77,13 @@ func ParseAuthorizationToken(req *http.Request) (int64, error) {
 		return setting.GetGeneralTokenSigningSecret(), nil
 	})
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	c, ok := token.Claims.(*packageClaims)
 	if !token.Valid || !ok {
-		return 0, fmt.Errorf("invalid token claim")
+		return 0, 0, fmt.Errorf("invalid token claim")
 	}
 
-	return c.UserID, nil
+	return c.UserID, c.ActionsTaskID, nil
 }
@@ -0,0 +1,37 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"net/http"
+	"testing"
+
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizationToken(t *testing.T) {
+	parse := func(token string) (int64, int64) {
+		req, err := http.NewRequest(http.MethodGet, "/v2/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		userID, taskID, err := ParseActionsAuthorizationToken(req)
+		require.NoError(t, err)
+		return userID, taskID
+	}
+
+	token, err := CreateAuthorizationToken(&user_model.User{ID: 2})
+	require.NoError(t, err)
+	userID, taskID := parse(token)
+	assert.EqualValues(t, 2, userID)
+	assert.EqualValues(t, 0, taskID)
+
+	token, err = CreateActionsAuthorizationToken(47)
+	require.NoError(t, err)
+	userID, taskID = parse(token)
+	assert.EqualValues(t, user_model.ActionsUserID, userID)
+	assert.EqualValues(t, 47, taskID)
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	packages_service "code.gitea.io/gitea/services/packages"
+	container_service "code.gitea.io/gitea/services/packages/container"
+)
+
+// ActionsRetentionRemoval is a package version published by a run which is removed by the package retention of the repository
+type ActionsRetentionRemoval struct {
+	Package *packages_model.Package
+	Version *packages_model.PackageVersion
+	RunID   int64
+	Ref     string
+}
+
+// FindActionsRetentionRemovals returns the package versions published by the runs of the repository which are removed by the package retention,
+// the latest ones go first. A version is kept by the first rule matching it, and the versions matching no rules are kept.
+func FindActionsRetentionRemovals(ctx context.Context, repo *repo_model.Repository, retention *repo_model.ActionsPackageRetention) ([]*ActionsRetentionRemoval, error) {
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		OwnerID:    repo.OwnerID,
+		Properties: map[string]string{packages_model.PropertyActionsRepoID: strconv.FormatInt(repo.ID, 10)},
+		IsInternal: optional.Some(false),
+		Sort:       packages_model.SortCreatedDesc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SearchVersions: %w", err)
+	}
+
+	now := time.Now()
+	packages := make(map[int64]*packages_model.Package)
+	kept := make(map[string]int) // the numbers of the kept versions by the packages and the refs
+	var removals []*ActionsRetentionRemoval
+	for _, pv := range pvs {
+		p, ok := packages[pv.PackageID]
+		if !ok {
+			if p, err = packages_model.GetPackageByID(ctx, pv.PackageID); err != nil {
+				return nil, fmt.Errorf("GetPackageByID: %w", err)
+			}
+			packages[p.ID] = p
+		}
+		pps, err := packages_model.GetProperties(ctx, packages_model.PropertyTypeVersion, pv.ID)
+		if err != nil {
+			return nil, fmt.Errorf("GetProperties: %w", err)
+		}
+		removal := &ActionsRetentionRemoval{Package: p, Version: pv}
+		for _, pp := range pps {
+			switch pp.Name {
+			case packages_model.PropertyActionsRunID:
+				removal.RunID, _ = strconv.ParseInt(pp.Value, 10, 64)
+			case packages_model.PropertyActionsRef:
+				removal.Ref = pp.Value
+			}
+		}
+
+		var rule *repo_model.ActionsPackageRetentionRule
+		for _, r := range retention.Rules {
+			if r.Match(string(p.Type), p.LowerName, removal.Ref) {
+				rule = r
+				break
+			}
+		}
+		if rule == nil {
+			continue
+		}
+		key := fmt.Sprintf("%d:%s", p.ID, removal.Ref)
+		if kept[key] < rule.KeepCount || (rule.KeepDays > 0 && pv.CreatedUnix.AsTime().After(now.AddDate(0, 0, -rule.KeepDays))) {
+			kept[key]++
+			continue
+		}
+		if p.Type == packages_model.TypeContainer {
+			if skip, err := container_service.ShouldBeSkipped(ctx, nil, p, pv); err != nil {
+				return nil, fmt.Errorf("container.ShouldBeSkipped: %w", err)
+			} else if skip {
+				continue
+			}
+		}
+		removals = append(removals, removal)
+	}
+	return removals, nil
+}
+
+// ExecuteActionsRetentions removes the package versions published by the runs by the package retentions of the repositories,
+// or only logs them if the retentions are dry runs
+func ExecuteActionsRetentions(outerCtx context.Context) error {
+	retentions, err := repo_model.GetActionsPackageRetentions(outerCtx)
+	if err != nil {
+		return fmt.Errorf("GetActionsPackageRetentions: %w", err)
+	}
+
+	for repoID, retention := range retentions {
+		select {
+		case <-outerCtx.Done():
+			return db.ErrCancelledf("While processing the package retentions of Actions")
+		default:
+		}
+
+		repo, err := repo_model.GetRepositoryByID(outerCtx, repoID)
+		if err != nil {
+			log.Error("Cannot get repository %d of package retention: %v", repoID, err)
+			continue
+		}
+		if err := executeActionsRetention(outerCtx, repo, retention); err != nil {
+			return fmt.Errorf("PackageRetention of repository [%d]: %w", repoID, err)
+		}
+	}
+	return nil
+}
+
+func executeActionsRetention(ctx context.Context, repo *repo_model.Repository, retention *repo_model.ActionsPackageRetention) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		removals, err := FindActionsRetentionRemovals(ctx, repo, retention)
+		if err != nil {
+			return err
+		}
+
+		updated := make(map[int64]*packages_model.Package)
+		types := make(container.Set[packages_model.Type])
+		for _, r := range removals {
+			if retention.DryRun {
+				log.Info("PackageRetention of repository [%d]: would remove '%s/%s' published by run %d of %s (dry run)", repo.ID, r.Package.Name, r.Version.Version, r.RunID, r.Ref)
+				continue
+			}
+			log.Debug("PackageRetention of repository [%d]: remove '%s/%s' published by run %d of %s", repo.ID, r.Package.Name, r.Version.Version, r.RunID, r.Ref)
+			if err := packages_service.DeletePackageVersionAndReferences(ctx, r.Version); err != nil {
+				return fmt.Errorf("DeletePackageVersionAndReferences failed: %w", err)
+			}
+			updated[r.Package.ID] = r.Package
+			types.Add(r.Package.Type)
+		}
+		for _, p := range updated {
+			if err := updatePackageIndex(ctx, p); err != nil {
+				return err
+			}
+		}
+		for packageType := range types {
+			if err := buildRepositoryFiles(ctx, repo.OwnerID, packageType); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
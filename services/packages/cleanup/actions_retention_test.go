@@ -0,0 +1,107 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindActionsRetentionRemovals(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	publish := func(name, version string, runID int64, ref string, age time.Duration) *packages_model.PackageVersion {
+		p, err := packages_model.TryInsertPackage(db.DefaultContext, &packages_model.Package{
+			OwnerID:   repo.OwnerID,
+			RepoID:    repo.ID,
+			Type:      packages_model.TypeGeneric,
+			Name:      name,
+			LowerName: name,
+		})
+		if err == packages_model.ErrDuplicatePackage {
+			err = nil
+		}
+		require.NoError(t, err)
+		pv, err := packages_model.GetOrInsertVersion(db.DefaultContext, &packages_model.PackageVersion{
+			PackageID:    p.ID,
+			CreatorID:    repo.OwnerID,
+			Version:      version,
+			LowerVersion: version,
+			MetadataJSON: "null",
+		})
+		require.NoError(t, err)
+		_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE package_version SET created_unix = ? WHERE id = ?",
+			timeutil.TimeStamp(time.Now().Add(-age).Unix()), pv.ID)
+		require.NoError(t, err)
+		for k, v := range map[string]string{
+			packages_model.PropertyActionsRepoID: strconv.FormatInt(repo.ID, 10),
+			packages_model.PropertyActionsRunID:  strconv.FormatInt(runID, 10),
+			packages_model.PropertyActionsRef:    ref,
+		} {
+			_, err := packages_model.InsertProperty(db.DefaultContext, packages_model.PropertyTypeVersion, pv.ID, k, v)
+			require.NoError(t, err)
+		}
+		return pv
+	}
+
+	const day = 24 * time.Hour
+	main1 := publish("app", "main-1", 1, "refs/heads/main", 6*day)
+	main2 := publish("app", "main-2", 2, "refs/heads/main", 4*day)
+	publish("app", "main-3", 3, "refs/heads/main", 3*day)
+	feature1 := publish("app", "feature-1", 4, "refs/heads/feature", 2*day)
+	publish("app", "feature-2", 5, "refs/heads/feature", day)
+	publish("app", "v1.0.0", 6, "refs/tags/v1.0.0", 10*day)
+
+	removalIDs := func(retention *repo_model.ActionsPackageRetention) []int64 {
+		removals, err := FindActionsRetentionRemovals(db.DefaultContext, repo, retention)
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(removals))
+		for _, r := range removals {
+			ids = append(ids, r.Version.ID)
+		}
+		return ids
+	}
+
+	// the tags match no rules, so they're kept
+	assert.Equal(t, []int64{feature1.ID, main2.ID, main1.ID}, removalIDs(&repo_model.ActionsPackageRetention{
+		Rules: []*repo_model.ActionsPackageRetentionRule{{RefPattern: "refs/heads/*", KeepCount: 1}},
+	}))
+	// the first matching rule applies
+	assert.Equal(t, []int64{main1.ID}, removalIDs(&repo_model.ActionsPackageRetention{
+		Rules: []*repo_model.ActionsPackageRetentionRule{
+			{RefPattern: "refs/heads/main", KeepCount: 2},
+			{RefPattern: "refs/heads/*", KeepCount: 5},
+		},
+	}))
+	// the recent versions are kept
+	assert.Equal(t, []int64{main1.ID}, removalIDs(&repo_model.ActionsPackageRetention{
+		Rules: []*repo_model.ActionsPackageRetentionRule{{Type: "generic", KeepDays: 5, KeepCount: 1}},
+	}))
+
+	require.NoError(t, executeActionsRetention(db.DefaultContext, repo, &repo_model.ActionsPackageRetention{
+		Rules:  []*repo_model.ActionsPackageRetentionRule{{PackagePattern: "app", KeepCount: 1}},
+		DryRun: true,
+	}))
+	_, err := packages_model.GetVersionByID(db.DefaultContext, main1.ID)
+	assert.NoError(t, err)
+
+	require.NoError(t, executeActionsRetention(db.DefaultContext, repo, &repo_model.ActionsPackageRetention{
+		Rules: []*repo_model.ActionsPackageRetentionRule{{RefPattern: "refs/heads/main", KeepCount: 2}},
+	}))
+	_, err = packages_model.GetVersionByID(db.DefaultContext, main1.ID)
+	assert.ErrorIs(t, err, packages_model.ErrPackageNotExist)
+	_, err = packages_model.GetVersionByID(db.DefaultContext, main2.ID)
+	assert.NoError(t, err)
+}
@@ -24,6 +24,10 @@ import (
 
 // Task method to execute cleanup rules and cleanup expired package data
 func CleanupTask(ctx context.Context, olderThan time.Duration) error {
+	if err := ExecuteActionsRetentions(ctx); err != nil {
+		return err
+	}
+
 	if err := ExecuteCleanupRules(ctx); err != nil {
 		return err
 	}
@@ -107,31 +111,15 @@ func ExecuteCleanupRules(outerCtx context.Context) error {
 			}
 
 			if versionDeleted {
-				if pcr.Type == packages_model.TypeCargo {
-					owner, err := user_model.GetUserByID(ctx, pcr.OwnerID)
-					if err != nil {
-						return fmt.Errorf("GetUserByID failed: %w", err)
-					}
-					if err := cargo_service.UpdatePackageIndexIfExists(ctx, owner, owner, p.ID); err != nil {
-						return fmt.Errorf("CleanupRule [%d]: cargo.UpdatePackageIndexIfExists failed: %w", pcr.ID, err)
-					}
+				if err := updatePackageIndex(ctx, p); err != nil {
+					return fmt.Errorf("CleanupRule [%d]: %w", pcr.ID, err)
 				}
 			}
 		}
 
 		if anyVersionDeleted {
-			if pcr.Type == packages_model.TypeDebian {
-				if err := debian_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-					return fmt.Errorf("CleanupRule [%d]: debian.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-				}
-			} else if pcr.Type == packages_model.TypeAlpine {
-				if err := alpine_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-					return fmt.Errorf("CleanupRule [%d]: alpine.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-				}
-			} else if pcr.Type == packages_model.TypeRpm {
-				if err := rpm_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-					return fmt.Errorf("CleanupRule [%d]: rpm.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-				}
+			if err := buildRepositoryFiles(ctx, pcr.OwnerID, pcr.Type); err != nil {
+				return fmt.Errorf("CleanupRule [%d]: %w", pcr.ID, err)
 			}
 		}
 		return nil
@@ -143,6 +131,39 @@ func ExecuteCleanupRules(outerCtx context.Context) error {
 	return committer.Commit()
 }
 
+// updatePackageIndex updates the index of the package after some of its versions are removed, if the type has one
+func updatePackageIndex(ctx context.Context, p *packages_model.Package) error {
+	if p.Type == packages_model.TypeCargo {
+		owner, err := user_model.GetUserByID(ctx, p.OwnerID)
+		if err != nil {
+			return fmt.Errorf("GetUserByID failed: %w", err)
+		}
+		if err := cargo_service.UpdatePackageIndexIfExists(ctx, owner, owner, p.ID); err != nil {
+			return fmt.Errorf("cargo.UpdatePackageIndexIfExists failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// buildRepositoryFiles builds the repository files of the owner after some package versions of the type are removed, if the type has them
+func buildRepositoryFiles(ctx context.Context, ownerID int64, packageType packages_model.Type) error {
+	switch packageType {
+	case packages_model.TypeDebian:
+		if err := debian_service.BuildAllRepositoryFiles(ctx, ownerID); err != nil {
+			return fmt.Errorf("debian.BuildAllRepositoryFiles failed: %w", err)
+		}
+	case packages_model.TypeAlpine:
+		if err := alpine_service.BuildAllRepositoryFiles(ctx, ownerID); err != nil {
+			return fmt.Errorf("alpine.BuildAllRepositoryFiles failed: %w", err)
+		}
+	case packages_model.TypeRpm:
+		if err := rpm_service.BuildAllRepositoryFiles(ctx, ownerID); err != nil {
+			return fmt.Errorf("rpm.BuildAllRepositoryFiles failed: %w", err)
+		}
+	}
+	return nil
+}
+
 func CleanupExpiredData(outerCtx context.Context, olderThan time.Duration) error {
 	ctx, committer, err := db.TxContext(outerCtx)
 	if err != nil {
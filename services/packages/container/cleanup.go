@@ -5,8 +5,10 @@ package container
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	container_model "code.gitea.io/gitea/models/packages/container"
 	"code.gitea.io/gitea/modules/optional"
@@ -24,6 +26,36 @@ func Cleanup(ctx context.Context, olderThan time.Duration) error {
 	return cleanupExpiredUploadedBlobs(ctx, olderThan)
 }
 
+// RemoveRunScratchImages removes the images in the scratch namespace of an actions run, see container_model.RunScratchImagePrefix,
+// the packages and the blobs left are removed by the cleanup task
+func RemoveRunScratchImages(ctx context.Context, ownerID, runID int64) error {
+	ps, err := packages_model.GetPackagesByType(ctx, ownerID, packages_model.TypeContainer)
+	if err != nil {
+		return err
+	}
+	prefix := container_model.RunScratchImagePrefix(runID)
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		for _, p := range ps {
+			if !strings.HasPrefix(p.LowerName, prefix) {
+				continue
+			}
+			pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+				PackageID:  p.ID,
+				IsInternal: optional.None[bool](),
+			})
+			if err != nil {
+				return err
+			}
+			for _, pv := range pvs {
+				if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
 // cleanupExpiredBlobUploads removes expired blob uploads
 func cleanupExpiredBlobUploads(ctx context.Context, olderThan time.Duration) error {
 	pbus, err := packages_model.FindExpiredBlobUploads(ctx, olderThan)
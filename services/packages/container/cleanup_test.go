@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveRunScratchImages(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	createImage := func(ownerID int64, name string) *packages_model.PackageVersion {
+		p, err := packages_model.TryInsertPackage(db.DefaultContext, &packages_model.Package{
+			OwnerID:   ownerID,
+			Type:      packages_model.TypeContainer,
+			Name:      name,
+			LowerName: name,
+		})
+		require.NoError(t, err)
+		pv, err := packages_model.GetOrInsertVersion(db.DefaultContext, &packages_model.PackageVersion{
+			PackageID:    p.ID,
+			CreatorID:    ownerID,
+			Version:      "latest",
+			LowerVersion: "latest",
+			MetadataJSON: "null",
+		})
+		require.NoError(t, err)
+		return pv
+	}
+
+	scratch := createImage(2, container_model.RunScratchImagePrefix(1)+"app")
+	otherRun := createImage(2, container_model.RunScratchImagePrefix(11)+"app")
+	otherOwner := createImage(3, container_model.RunScratchImagePrefix(1)+"app")
+	regular := createImage(2, "app")
+
+	require.NoError(t, RemoveRunScratchImages(db.DefaultContext, 2, 1))
+
+	_, err := packages_model.GetVersionByID(db.DefaultContext, scratch.ID)
+	assert.ErrorIs(t, err, packages_model.ErrPackageNotExist)
+	for _, pv := range []*packages_model.PackageVersion{otherRun, otherOwner, regular} {
+		_, err := packages_model.GetVersionByID(db.DefaultContext, pv.ID)
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package release
+
+import (
+	"io"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxReleaseConfigSize is the max size of the configuration file of the releases
+const maxReleaseConfigSize = 64 * 1024
+
+// releaseConfigCandidates are the paths of the configuration of the releases in the default branch, the first one found is used
+var releaseConfigCandidates = []string{
+	".gitea/release.yml",
+	".gitea/release.yaml",
+	".github/release.yml",
+	".github/release.yaml",
+}
+
+// ReleaseConfig is the configuration of the releases of a repository, its changelog is compatible with the one of GitHub,
+// and the template of the notes and the versioning of the tags could be customized additionally
+type ReleaseConfig struct {
+	Changelog struct {
+		// Exclude excludes the pull requests from the notes
+		Exclude ReleaseNotesFilter `yaml:"exclude"`
+		// Categories group the pull requests by their labels, a pull request belongs to the first category matching it
+		Categories []*ReleaseNotesCategory `yaml:"categories"`
+		// Template is the text/template rendering the ReleaseNotes, the default one is used if it's empty
+		Template string `yaml:"template"`
+	} `yaml:"changelog"`
+	Versioning VersioningConfig `yaml:"versioning"`
+}
+
+// LoadReleaseConfig loads the configuration of the releases from the default branch, it's the default one if there isn't a file.
+// The error is util.ErrNotExist if the configPath doesn't exist, or util.ErrInvalidArgument if the configuration is invalid.
+func LoadReleaseConfig(repo *repo_model.Repository, gitRepo *git.Repository, configPath string) (*ReleaseConfig, error) {
+	config := &ReleaseConfig{}
+	if repo.IsEmpty {
+		return config, nil
+	}
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := releaseConfigCandidates
+	if configPath != "" {
+		candidates = []string{configPath}
+	}
+	for _, candidate := range candidates {
+		entry, err := commit.GetTreeEntryByPath(candidate)
+		if git.IsErrNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		reader, err := entry.Blob().DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(io.LimitReader(reader, maxReleaseConfigSize))
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(content, config); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid configuration %s of the releases: %v", candidate, err)
+		}
+		return config, nil
+	}
+	if configPath != "" {
+		return nil, util.NewNotExistErrorf("configuration %s of the releases doesn't exist", configPath)
+	}
+	return config, nil
+}
@@ -0,0 +1,275 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	// maxReleaseNotesCommits is how many commits since the previous tag are looked up for the merged pull requests
+	maxReleaseNotesCommits = 1000
+	// maxPreviousTagCandidates is how many latest tags are checked for the previous tag
+	maxPreviousTagCandidates = 50
+)
+
+// releaseNotesOtherCategory is the title of the pull requests which don't belong to any category
+const releaseNotesOtherCategory = "Other Changes"
+
+// defaultReleaseNotesTemplate renders the notes like "* title by @author in #1" grouped by the categories
+const defaultReleaseNotesTemplate = `## What's Changed
+{{range .Sections}}{{if .Title}}
+### {{.Title}}
+{{end}}
+{{range .PullRequests}}* {{.Title}} by @{{.Author}} in #{{.Index}}
+{{end}}{{else}}
+No pull requests have been merged.
+{{end}}{{if .Contributors}}
+**Contributors**: {{range $i, $c := .Contributors}}{{if $i}}, {{end}}@{{$c}}{{end}}
+{{end}}{{if .CompareURL}}
+**Full Changelog**: {{.CompareURL}}
+{{end}}`
+
+// ReleaseNotesFilter matches the pull requests by their labels or their authors, "*" matches all
+type ReleaseNotesFilter struct {
+	Labels  []string `yaml:"labels"`
+	Authors []string `yaml:"authors"`
+}
+
+func (f *ReleaseNotesFilter) matchLabels(pr *ReleaseNotesPullRequest) bool {
+	return slices.ContainsFunc(f.Labels, func(label string) bool {
+		return label == "*" || slices.Contains(pr.Labels, label)
+	})
+}
+
+func (f *ReleaseNotesFilter) match(pr *ReleaseNotesPullRequest) bool {
+	return f.matchLabels(pr) || slices.ContainsFunc(f.Authors, func(author string) bool {
+		return author == "*" || strings.EqualFold(author, pr.Author)
+	})
+}
+
+// ReleaseNotesCategory is a section of the release notes with the pull requests having any of the labels
+type ReleaseNotesCategory struct {
+	Title   string             `yaml:"title"`
+	Labels  []string           `yaml:"labels"`
+	Exclude ReleaseNotesFilter `yaml:"exclude"`
+}
+
+// ReleaseNotes is the data rendered by the template of the release notes
+type ReleaseNotes struct {
+	TagName         string
+	PreviousTagName string // empty if there isn't a previous tag
+	CompareURL      string // empty if there isn't a previous tag
+	Sections        []*ReleaseNotesSection
+	Contributors    []string // the names of the authors of the pull requests
+	Body            string   // the rendered notes
+}
+
+// ReleaseNotesSection is a category of the release notes with its pull requests, the title is empty if there aren't categories
+type ReleaseNotesSection struct {
+	Title        string
+	PullRequests []*ReleaseNotesPullRequest
+}
+
+// ReleaseNotesPullRequest is a merged pull request of the release notes
+type ReleaseNotesPullRequest struct {
+	Index  int64
+	Title  string
+	Author string
+	Labels []string
+	URL    string
+}
+
+// GenerateReleaseNotesOptions are the options to generate the release notes
+type GenerateReleaseNotesOptions struct {
+	// TagName is the tag of the release, the notes are generated for Target if it doesn't exist
+	TagName string
+	// Target is the branch or the commit of the release if the tag doesn't exist, the default branch if it's empty
+	Target string
+	// PreviousTagName is the tag which the notes start from, it's the latest tag whose commit is an ancestor of the release if it's empty
+	PreviousTagName string
+	// ConfigPath is the path of the configuration file in the default branch, the default candidates are tried if it's empty
+	ConfigPath string
+}
+
+// GenerateReleaseNotes generates the notes of a release from the pull requests merged since the previous tag.
+// The error is util.ErrNotExist if the refs or the configuration file don't exist, or util.ErrInvalidArgument if the configuration is invalid.
+func GenerateReleaseNotes(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, opts GenerateReleaseNotesOptions) (*ReleaseNotes, error) {
+	config, err := LoadReleaseConfig(repo, gitRepo, opts.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	tmplContent := config.Changelog.Template
+	if tmplContent == "" {
+		tmplContent = defaultReleaseNotesTemplate
+	}
+	tmpl, err := template.New("release-notes").Parse(tmplContent)
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid template of the release notes: %v", err)
+	}
+
+	var commit *git.Commit
+	if gitRepo.IsTagExist(opts.TagName) {
+		commit, err = gitRepo.GetTagCommit(opts.TagName)
+	} else {
+		target := opts.Target
+		if target == "" {
+			target = repo.DefaultBranch
+		}
+		commit, err = gitRepo.GetCommit(target)
+	}
+	if git.IsErrNotExist(err) {
+		return nil, util.NewNotExistErrorf("the target of the release doesn't exist")
+	} else if err != nil {
+		return nil, err
+	}
+
+	notes := &ReleaseNotes{TagName: opts.TagName, PreviousTagName: opts.PreviousTagName}
+	if notes.PreviousTagName == "" {
+		if notes.PreviousTagName, err = findPreviousTag(ctx, repo, opts.TagName, commit); err != nil {
+			return nil, err
+		}
+	}
+	var before *git.Commit
+	if notes.PreviousTagName != "" {
+		if before, err = gitRepo.GetTagCommit(notes.PreviousTagName); git.IsErrNotExist(err) {
+			return nil, util.NewNotExistErrorf("tag %s doesn't exist", notes.PreviousTagName)
+		} else if err != nil {
+			return nil, err
+		}
+		notes.CompareURL = fmt.Sprintf("%s/compare/%s...%s", repo.HTMLURL(), util.PathEscapeSegments(notes.PreviousTagName), util.PathEscapeSegments(opts.TagName))
+	}
+
+	prs, err := findReleasePullRequests(ctx, repo, gitRepo, commit, before)
+	if err != nil {
+		return nil, err
+	}
+	notes.Sections, notes.Contributors = groupReleasePullRequests(config, prs)
+
+	var body strings.Builder
+	if err := tmpl.Execute(&body, notes); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("failed to render the release notes: %v", err)
+	}
+	notes.Body = body.String()
+	return notes, nil
+}
+
+// findReleasePullRequests returns the pull requests merged by the commits in (before, commit], ordered by their merge times
+func findReleasePullRequests(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, commit, before *git.Commit) ([]*ReleaseNotesPullRequest, error) {
+	commits, err := gitRepo.CommitsBetweenLimit(commit, before, maxReleaseNotesCommits, 0)
+	if err != nil {
+		return nil, fmt.Errorf("CommitsBetweenLimit: %w", err)
+	}
+	commitIDs := make([]string, 0, len(commits))
+	for _, c := range commits {
+		commitIDs = append(commitIDs, c.ID.String())
+	}
+	prs, err := issues_model.GetMergedPullRequestsByMergedCommitIDs(ctx, repo.ID, commitIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := prs.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*ReleaseNotesPullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if err := pr.Issue.LoadPoster(ctx); err != nil {
+			return nil, err
+		}
+		if err := pr.Issue.LoadLabels(ctx); err != nil {
+			return nil, err
+		}
+		labels := make([]string, 0, len(pr.Issue.Labels))
+		for _, label := range pr.Issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		ret = append(ret, &ReleaseNotesPullRequest{
+			Index:  pr.Issue.Index,
+			Title:  pr.Issue.Title,
+			Author: pr.Issue.Poster.Name,
+			Labels: labels,
+			URL:    fmt.Sprintf("%s/pulls/%d", repo.HTMLURL(), pr.Issue.Index),
+		})
+	}
+	return ret, nil
+}
+
+// groupReleasePullRequests groups the pull requests which aren't excluded by the categories, and returns their authors
+func groupReleasePullRequests(config *ReleaseConfig, prs []*ReleaseNotesPullRequest) ([]*ReleaseNotesSection, []string) {
+	categories := config.Changelog.Categories
+	sections := make([]*ReleaseNotesSection, len(categories))
+	for i, category := range categories {
+		sections[i] = &ReleaseNotesSection{Title: category.Title}
+	}
+	others := &ReleaseNotesSection{}
+	if len(categories) > 0 {
+		others.Title = releaseNotesOtherCategory
+	}
+
+	var contributors []string
+	for _, pr := range prs {
+		if config.Changelog.Exclude.match(pr) {
+			continue
+		}
+		if !slices.Contains(contributors, pr.Author) {
+			contributors = append(contributors, pr.Author)
+		}
+		section := others
+		for i, category := range categories {
+			if (&ReleaseNotesFilter{Labels: category.Labels}).matchLabels(pr) && !category.Exclude.match(pr) {
+				section = sections[i]
+				break
+			}
+		}
+		section.PullRequests = append(section.PullRequests, pr)
+	}
+
+	ret := make([]*ReleaseNotesSection, 0, len(sections)+1)
+	for _, section := range append(sections, others) {
+		if len(section.PullRequests) > 0 {
+			ret = append(ret, section)
+		}
+	}
+	return ret, contributors
+}
+
+// findPreviousTag returns the latest tag whose commit is an ancestor of the commit, or empty if there isn't one
+func findPreviousTag(ctx context.Context, repo *repo_model.Repository, tagName string, commit *git.Commit) (string, error) {
+	releases, err := db.Find[repo_model.Release](ctx, repo_model.FindReleasesOptions{
+		ListOptions: db.ListOptions{PageSize: maxPreviousTagCandidates},
+		RepoID:      repo.ID,
+		IncludeTags: true,
+		HasSha1:     optional.Some(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, rel := range releases {
+		if rel.TagName == tagName || rel.Sha1 == commit.ID.String() {
+			continue
+		}
+		id, err := git.NewIDFromString(rel.Sha1)
+		if err != nil {
+			continue
+		}
+		if isAncestor, err := commit.HasPreviousCommit(id); err != nil {
+			return "", err
+		} else if isAncestor {
+			return rel.TagName, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package release
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGroupReleasePullRequests(t *testing.T) {
+	config := &ReleaseConfig{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+changelog:
+  exclude:
+    labels: [ignore-for-release]
+    authors: [renovate]
+  categories:
+    - title: Breaking Changes
+      labels: [breaking]
+    - title: Features
+      labels: [feature]
+      exclude:
+        labels: [internal]
+`), config))
+
+	prs := []*ReleaseNotesPullRequest{
+		{Index: 1, Title: "Remove the old API", Author: "alice", Labels: []string{"breaking", "feature"}},
+		{Index: 2, Title: "Add a new API", Author: "bob", Labels: []string{"feature"}},
+		{Index: 3, Title: "Update dependencies", Author: "renovate", Labels: []string{"dependencies"}},
+		{Index: 4, Title: "Refactor the tests", Author: "alice", Labels: []string{"feature", "internal"}},
+		{Index: 5, Title: "Fix a typo", Author: "carol", Labels: []string{"ignore-for-release"}},
+	}
+	sections, contributors := groupReleasePullRequests(config, prs)
+	require.Len(t, sections, 3)
+	assert.Equal(t, "Breaking Changes", sections[0].Title)
+	assert.Equal(t, []*ReleaseNotesPullRequest{prs[0]}, sections[0].PullRequests)
+	assert.Equal(t, "Features", sections[1].Title)
+	assert.Equal(t, []*ReleaseNotesPullRequest{prs[1]}, sections[1].PullRequests)
+	assert.Equal(t, releaseNotesOtherCategory, sections[2].Title)
+	assert.Equal(t, []*ReleaseNotesPullRequest{prs[3]}, sections[2].PullRequests)
+	assert.Equal(t, []string{"alice", "bob"}, contributors)
+
+	// the pull requests aren't grouped without categories
+	sections, _ = groupReleasePullRequests(&ReleaseConfig{}, prs)
+	require.Len(t, sections, 1)
+	assert.Empty(t, sections[0].Title)
+	assert.Len(t, sections[0].PullRequests, 5)
+}
+
+func TestDefaultReleaseNotesTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(defaultReleaseNotesTemplate))
+	var body strings.Builder
+	require.NoError(t, tmpl.Execute(&body, &ReleaseNotes{
+		TagName:         "v1.1",
+		PreviousTagName: "v1.0",
+		CompareURL:      "https://gitea.example.com/user2/repo1/compare/v1.0...v1.1",
+		Sections: []*ReleaseNotesSection{
+			{Title: "Features", PullRequests: []*ReleaseNotesPullRequest{{Index: 2, Title: "Add a new API", Author: "bob"}}},
+			{Title: "Other Changes", PullRequests: []*ReleaseNotesPullRequest{{Index: 3, Title: "Fix a bug", Author: "alice"}}},
+		},
+		Contributors: []string{"bob", "alice"},
+	}))
+	assert.Equal(t, `## What's Changed
+
+### Features
+
+* Add a new API by @bob in #2
+
+### Other Changes
+
+* Fix a bug by @alice in #3
+
+**Contributors**: @bob, @alice
+
+**Full Changelog**: https://gitea.example.com/user2/repo1/compare/v1.0...v1.1
+`, body.String())
+}
+
+func TestGenerateReleaseNotes(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo)
+	require.NoError(t, err)
+	defer gitRepo.Close()
+
+	notes, err := GenerateReleaseNotes(db.DefaultContext, repo, gitRepo, GenerateReleaseNotesOptions{TagName: "v1.2"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2", notes.TagName)
+	assert.Empty(t, notes.PreviousTagName)
+	assert.Equal(t, "## What's Changed\n\nNo pull requests have been merged.\n", notes.Body)
+
+	_, err = GenerateReleaseNotes(db.DefaultContext, repo, gitRepo, GenerateReleaseNotesOptions{TagName: "v1.2", PreviousTagName: "v0.1"})
+	assert.ErrorIs(t, err, util.ErrNotExist)
+	_, err = GenerateReleaseNotes(db.DefaultContext, repo, gitRepo, GenerateReleaseNotesOptions{TagName: "v1.2", ConfigPath: ".gitea/release.yml"})
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
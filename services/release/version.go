@@ -0,0 +1,259 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// The parts of the semantic versions to bump
+const (
+	VersionBumpMajor      = "major"
+	VersionBumpMinor      = "minor"
+	VersionBumpPatch      = "patch"
+	VersionBumpPrerelease = "prerelease"
+)
+
+const (
+	defaultVersionPrefix     = "v"
+	defaultInitialVersion    = "0.1.0"
+	defaultPrereleaseVersion = "rc"
+	// maxVersionBumpAttempts is how many times the next version is computed again if its tag is created concurrently
+	maxVersionBumpAttempts = 3
+)
+
+// versionBumpPool serializes the version bumps of the same repository, so the concurrent bumps don't compute the same version
+var versionBumpPool = sync.NewExclusivePool()
+
+// VersioningConfig is the scheme of the semantic version tags of a repository
+type VersioningConfig struct {
+	// Prefix is the prefix of the tags, "v" if it isn't set, it could be set to empty for the tags like "1.2.3"
+	Prefix *string `yaml:"prefix"`
+	// Initial is the version of the first tag, "0.1.0" if it's empty
+	Initial string `yaml:"initial"`
+	// Prerelease is the identifier of the prerelease versions like "1.2.3-rc.1", "rc" if it's empty
+	Prerelease string `yaml:"prerelease"`
+}
+
+func (c *VersioningConfig) prefix() string {
+	if c.Prefix == nil {
+		return defaultVersionPrefix
+	}
+	return *c.Prefix
+}
+
+func (c *VersioningConfig) prerelease() string {
+	if c.Prerelease == "" {
+		return defaultPrereleaseVersion
+	}
+	return c.Prerelease
+}
+
+var semVersionPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+// semVersion is a semantic version without its build metadata
+type semVersion struct {
+	major, minor, patch int64
+	prerelease          []string
+}
+
+// parseSemVersion returns the semantic version, or nil if it isn't valid
+func parseSemVersion(s string) *semVersion {
+	m := semVersionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	v := &semVersion{}
+	var err error
+	for i, n := range []*int64{&v.major, &v.minor, &v.patch} {
+		if *n, err = strconv.ParseInt(m[i+1], 10, 64); err != nil {
+			return nil
+		}
+	}
+	if m[4] != "" {
+		v.prerelease = strings.Split(m[4], ".")
+	}
+	return v
+}
+
+func (v *semVersion) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if len(v.prerelease) > 0 {
+		s += "-" + strings.Join(v.prerelease, ".")
+	}
+	return s
+}
+
+// compare returns -1, 0 or 1 if the version is lower than, equal to or higher than the other by the precedence of semantic versioning
+func (v *semVersion) compare(o *semVersion) int {
+	for _, d := range []int64{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	// a normal version is higher than its prereleases
+	if len(v.prerelease) == 0 || len(o.prerelease) == 0 {
+		return sign(int64(len(o.prerelease) - len(v.prerelease)))
+	}
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		a, b := v.prerelease[i], o.prerelease[i]
+		if a == b {
+			continue
+		}
+		an, aErr := strconv.ParseInt(a, 10, 64)
+		bn, bErr := strconv.ParseInt(b, 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			return sign(an - bn)
+		case aErr == nil: // numeric identifiers are lower than the alphanumeric ones
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	}
+	return sign(int64(len(v.prerelease) - len(o.prerelease)))
+}
+
+func sign(n int64) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// bump returns the next version, a prerelease is released by bumping the part it's a prerelease of,
+// e.g. "1.0.0-rc.2" becomes "1.0.0" by bumping the major, while "1.0.1-rc.2" becomes "2.0.0"
+func (v *semVersion) bump(kind, identifier string) *semVersion {
+	next := &semVersion{major: v.major, minor: v.minor, patch: v.patch}
+	isPrerelease := len(v.prerelease) > 0
+	switch kind {
+	case VersionBumpMajor:
+		if !isPrerelease || v.minor != 0 || v.patch != 0 {
+			next.major, next.minor, next.patch = v.major+1, 0, 0
+		}
+	case VersionBumpMinor:
+		if !isPrerelease || v.patch != 0 {
+			next.minor, next.patch = v.minor+1, 0
+		}
+	case VersionBumpPatch:
+		if !isPrerelease {
+			next.patch = v.patch + 1
+		}
+	case VersionBumpPrerelease:
+		if !isPrerelease {
+			next.patch = v.patch + 1
+		}
+		number := int64(1)
+		if isPrerelease && len(v.prerelease) == 2 && v.prerelease[0] == identifier {
+			if n, err := strconv.ParseInt(v.prerelease[1], 10, 64); err == nil {
+				number = n + 1
+			}
+		}
+		next.prerelease = []string{identifier, strconv.FormatInt(number, 10)}
+	}
+	return next
+}
+
+// nextVersion returns the next version of the latest one of the tags with the prefix, or the initial version if there isn't one
+func nextVersion(config *VersioningConfig, tags []string, kind string) (next, latestTag string, err error) {
+	prefix := config.prefix()
+	var latest *semVersion
+	for _, tag := range tags {
+		s, ok := strings.CutPrefix(tag, prefix)
+		if !ok {
+			continue
+		}
+		if v := parseSemVersion(s); v != nil && (latest == nil || v.compare(latest) > 0) {
+			latest, latestTag = v, tag
+		}
+	}
+
+	if latest == nil {
+		initial := config.Initial
+		if initial == "" {
+			initial = defaultInitialVersion
+		}
+		v := parseSemVersion(initial)
+		if v == nil || len(v.prerelease) > 0 {
+			return "", "", util.NewInvalidArgumentErrorf("invalid initial version %q", initial)
+		}
+		if kind == VersionBumpPrerelease {
+			v.prerelease = []string{config.prerelease(), "1"}
+		}
+		return prefix + v.String(), "", nil
+	}
+	return prefix + latest.bump(kind, config.prerelease()).String(), latestTag, nil
+}
+
+// BumpVersionTagOptions are the options to create the tag of the next version
+type BumpVersionTagOptions struct {
+	// Bump is the part of the version to bump, one of major, minor, patch and prerelease
+	Bump string
+	// Target is the branch or the commit of the tag, the default branch if it's empty
+	Target string
+	// Message is the message of the annotated tag, a lightweight tag is created if it's empty
+	Message string
+	// ConfigPath is the path of the configuration file in the default branch, the default candidates are tried if it's empty
+	ConfigPath string
+}
+
+// BumpVersionTag creates the tag of the next semantic version of the repository by the versioning of its configuration,
+// it returns the created tag and the tag of the previous version, which is empty if there isn't one.
+// The tag is created by the doer, so the protected tags are respected. The bumps of the same repository are serialized,
+// and the next version is computed again if its tag has been created by another instance meanwhile.
+func BumpVersionTag(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, opts BumpVersionTagOptions) (tagName, previousTagName string, err error) {
+	switch opts.Bump {
+	case VersionBumpMajor, VersionBumpMinor, VersionBumpPatch, VersionBumpPrerelease:
+	default:
+		return "", "", util.NewInvalidArgumentErrorf("invalid bump %q", opts.Bump)
+	}
+	config, err := LoadReleaseConfig(repo, gitRepo, opts.ConfigPath)
+	if err != nil {
+		return "", "", err
+	}
+	target := opts.Target
+	if target == "" {
+		target = repo.DefaultBranch
+	}
+	commit, err := gitRepo.GetCommit(target)
+	if git.IsErrNotExist(err) {
+		return "", "", util.NewNotExistErrorf("target %s doesn't exist", target)
+	} else if err != nil {
+		return "", "", err
+	}
+
+	versionBumpPool.CheckIn(strconv.FormatInt(repo.ID, 10))
+	defer versionBumpPool.CheckOut(strconv.FormatInt(repo.ID, 10))
+
+	for attempt := 1; ; attempt++ {
+		tags, err := gitRepo.GetTags(0, 0)
+		if err != nil {
+			return "", "", err
+		}
+		if tagName, previousTagName, err = nextVersion(&config.Versioning, tags, opts.Bump); err != nil {
+			return "", "", err
+		}
+
+		err = CreateNewTag(ctx, doer, repo, commit.ID.String(), tagName, opts.Message)
+		if err == nil || !models.IsErrTagAlreadyExists(err) || attempt == maxVersionBumpAttempts {
+			return tagName, previousTagName, err
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package release
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemVersion_Compare(t *testing.T) {
+	ordered := []string{"1.0.0-2", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		a, b := parseSemVersion(ordered[i-1]), parseSemVersion(ordered[i])
+		require.NotNil(t, a, ordered[i-1])
+		require.NotNil(t, b, ordered[i])
+		assert.Equal(t, -1, a.compare(b), "%s < %s", ordered[i-1], ordered[i])
+		assert.Equal(t, 1, b.compare(a), "%s > %s", ordered[i], ordered[i-1])
+	}
+	assert.Equal(t, 0, parseSemVersion("1.0.0+build.1").compare(parseSemVersion("1.0.0")))
+
+	for _, s := range []string{"1.0", "01.0.0", "1.0.0-", "v1.0.0", "1.0.0-rc..1"} {
+		assert.Nil(t, parseSemVersion(s), s)
+	}
+}
+
+func TestSemVersion_Bump(t *testing.T) {
+	cases := []struct {
+		version, kind, expected string
+	}{
+		{"1.2.3", VersionBumpMajor, "2.0.0"},
+		{"1.2.3", VersionBumpMinor, "1.3.0"},
+		{"1.2.3", VersionBumpPatch, "1.2.4"},
+		{"1.2.3", VersionBumpPrerelease, "1.2.4-rc.1"},
+		{"1.2.4-rc.1", VersionBumpPrerelease, "1.2.4-rc.2"},
+		{"1.2.4-beta.3", VersionBumpPrerelease, "1.2.4-rc.1"},
+		{"1.2.4-rc.2", VersionBumpPatch, "1.2.4"},
+		{"1.2.4-rc.2", VersionBumpMinor, "1.3.0"},
+		{"1.3.0-rc.2", VersionBumpMinor, "1.3.0"},
+		{"2.0.0-rc.2", VersionBumpMajor, "2.0.0"},
+		{"2.0.1-rc.2", VersionBumpMajor, "3.0.0"},
+		{"1.2.3+build.1", VersionBumpPatch, "1.2.4"},
+	}
+	for _, c := range cases {
+		v := parseSemVersion(c.version)
+		require.NotNil(t, v, c.version)
+		assert.Equal(t, c.expected, v.bump(c.kind, "rc").String(), "%s %s", c.kind, c.version)
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tags := []string{"v1.2.3", "v1.10.0-rc.1", "v1.9.0", "release-5.0.0", "v2.0", "latest"}
+
+	next, latest, err := nextVersion(&VersioningConfig{}, tags, VersionBumpMinor)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.10.0", next)
+	assert.Equal(t, "v1.10.0-rc.1", latest)
+
+	prefix := "release-"
+	next, latest, err = nextVersion(&VersioningConfig{Prefix: &prefix}, tags, VersionBumpMajor)
+	require.NoError(t, err)
+	assert.Equal(t, "release-6.0.0", next)
+	assert.Equal(t, "release-5.0.0", latest)
+
+	next, latest, err = nextVersion(&VersioningConfig{Initial: "1.0.0", Prerelease: "beta"}, []string{"latest"}, VersionBumpPrerelease)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0-beta.1", next)
+	assert.Empty(t, latest)
+
+	next, _, err = nextVersion(&VersioningConfig{}, nil, VersionBumpMajor)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.1.0", next)
+
+	_, _, err = nextVersion(&VersioningConfig{Initial: "1.0"}, nil, VersionBumpPatch)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestBumpVersionTag(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo)
+	require.NoError(t, err)
+	defer gitRepo.Close()
+
+	tagName, previousTagName, err := BumpVersionTag(db.DefaultContext, user, repo, gitRepo, BumpVersionTagOptions{Bump: VersionBumpPatch, Message: "next patch"})
+	require.NoError(t, err)
+	assert.True(t, gitRepo.IsTagExist(tagName))
+	if previousTagName != "" {
+		// the other tests may have created the semantic version tags
+		assert.Equal(t, parseSemVersion(previousTagName[1:]).bump(VersionBumpPatch, "rc").String(), tagName[1:])
+	}
+
+	nextTagName, nextPreviousTagName, err := BumpVersionTag(db.DefaultContext, user, repo, gitRepo, BumpVersionTagOptions{Bump: VersionBumpMinor})
+	require.NoError(t, err)
+	assert.Equal(t, tagName, nextPreviousTagName)
+	assert.Equal(t, "v"+parseSemVersion(tagName[1:]).bump(VersionBumpMinor, "rc").String(), nextTagName)
+
+	_, _, err = BumpVersionTag(db.DefaultContext, user, repo, gitRepo, BumpVersionTagOptions{Bump: "build"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, _, err = BumpVersionTag(db.DefaultContext, user, repo, gitRepo, BumpVersionTagOptions{Bump: VersionBumpPatch, Target: "not-exist"})
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
@@ -131,6 +131,11 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 		}
 	}
 
+	// default workflows
+	if err = writeDefaultWorkflows(tmpDir, repo); err != nil {
+		return fmt.Errorf("writeDefaultWorkflows: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,107 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/options"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// DefaultWorkflow is a workflow template of setting.Actions.DefaultWorkflows expanded for a repository
+type DefaultWorkflow struct {
+	Name    string
+	Content string
+}
+
+// TreePath returns the path of the workflow in the repository
+func (w *DefaultWorkflow) TreePath() string {
+	return ".gitea/workflows/" + w.Name
+}
+
+// newWorkflowPlaceholderReplacer returns a replacer of the placeholders like $REPO_NAME in the default workflows.
+// Unlike the expansion of template repositories, the unknown placeholders are kept as they are,
+// so the expressions like ${{ github.sha }} in workflows aren't broken.
+func newWorkflowPlaceholderReplacer(repo *repo_model.Repository) *strings.Replacer {
+	expansionMap := getExpansionMap(nil, repo)
+
+	// the longer names go first, so $REPO_NAME_SNAKE isn't replaced as $REPO_NAME
+	names := util.KeysOfMap(expansionMap)
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	oldnew := make([]string, 0, len(names)*4)
+	for _, name := range names {
+		oldnew = append(oldnew, "${"+name+"}", expansionMap[name], "$"+name, expansionMap[name])
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// GetDefaultWorkflows returns the workflows of setting.Actions.DefaultWorkflows with the placeholders expanded for the repository
+func GetDefaultWorkflows(repo *repo_model.Repository) []*DefaultWorkflow {
+	if !setting.Actions.Enabled || len(setting.Actions.DefaultWorkflows) == 0 {
+		return nil
+	}
+
+	replacer := newWorkflowPlaceholderReplacer(repo)
+	workflows := make([]*DefaultWorkflow, 0, len(setting.Actions.DefaultWorkflows))
+	for _, name := range setting.Actions.DefaultWorkflows {
+		data, err := options.Workflow(name)
+		if err != nil {
+			// a broken template shouldn't fail the creation of repositories
+			log.Error("Unable to read workflow template %s: %v", name, err)
+			continue
+		}
+		workflows = append(workflows, &DefaultWorkflow{
+			Name:    name,
+			Content: replacer.Replace(string(data)),
+		})
+	}
+	return workflows
+}
+
+// isWorkflowFile returns whether the file is a workflow by its path relative to the root of the repository
+func isWorkflowFile(treePath string) bool {
+	dir, name := path.Split(treePath)
+	return (dir == ".gitea/workflows/" || dir == ".github/workflows/") &&
+		(strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml"))
+}
+
+// writeDefaultWorkflows writes the default workflows into the working tree of a new repository
+// if they should be committed, the workflows with the same names in the tree are kept.
+func writeDefaultWorkflows(tmpDir string, repo *repo_model.Repository) error {
+	if setting.Actions.DefaultWorkflowsMode != setting.DefaultWorkflowsModeCommit {
+		return nil
+	}
+
+	for _, workflow := range GetDefaultWorkflows(repo) {
+		exists := false
+		for _, dir := range []string{".gitea/workflows", ".github/workflows"} {
+			isExist, err := util.IsExist(filepath.Join(tmpDir, filepath.FromSlash(dir), workflow.Name))
+			if err != nil {
+				return err
+			}
+			exists = exists || isExist
+		}
+		if exists {
+			continue
+		}
+
+		p := filepath.Join(tmpDir, filepath.FromSlash(workflow.TreePath()))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return fmt.Errorf("create workflows dir: %w", err)
+		}
+		if err := os.WriteFile(p, []byte(workflow.Content), 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", workflow.TreePath(), err)
+		}
+	}
+	return nil
+}
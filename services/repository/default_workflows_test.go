@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowPlaceholderReplacer(t *testing.T) {
+	repo := &repo_model.Repository{
+		Name:          "my-repo",
+		OwnerName:     "my-org",
+		DefaultBranch: "main",
+	}
+	replacer := newWorkflowPlaceholderReplacer(repo)
+
+	assert.Equal(t, "name: build my-repo of my-org", replacer.Replace("name: build $REPO_NAME of ${REPO_OWNER}"))
+	assert.Equal(t, "image: my_repo", replacer.Replace("image: $REPO_NAME_SNAKE"))
+	assert.Equal(t, "branches: [main]", replacer.Replace("branches: [$REPO_DEFAULT_BRANCH]"))
+	assert.Equal(t, "run: echo ${{ github.sha }} $HOME", replacer.Replace("run: echo ${{ github.sha }} $HOME"))
+}
+
+func TestIsWorkflowFile(t *testing.T) {
+	assert.True(t, isWorkflowFile(".gitea/workflows/build.yml"))
+	assert.True(t, isWorkflowFile(".github/workflows/build.yaml"))
+	assert.False(t, isWorkflowFile(".gitea/workflows/README.md"))
+	assert.False(t, isWorkflowFile(".gitea/workflows/sub/build.yml"))
+	assert.False(t, isWorkflowFile("build.yml"))
+}
@@ -66,6 +66,12 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		return fmt.Errorf("list actions artifacts of repo %v: %w", repoID, err)
 	}
 
+	// Query the handoff blobs of this repo, they will be needed after they have been deleted to remove their files
+	handoffBlobs, err := db.Find[actions_model.ActionHandoffBlob](ctx, actions_model.FindHandoffBlobOptions{RepoID: repoID})
+	if err != nil {
+		return fmt.Errorf("list actions handoff blobs of repo %v: %w", repoID, err)
+	}
+
 	// In case owner is a organization, we have to change repo specific teams
 	// if ignoreOrgTeams is not true
 	var org *user_model.User
@@ -164,6 +170,18 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		&actions_model.ActionSchedule{RepoID: repoID},
 		&actions_model.ActionArtifact{RepoID: repoID},
 		&actions_model.ActionRunnerToken{RepoID: repoID},
+		&actions_model.ActionUsage{RepoID: repoID},
+		&actions_model.ActionRunComment{RepoID: repoID},
+		&actions_model.ActionRunIssue{RepoID: repoID},
+		&actions_model.ActionHandoffBlob{RepoID: repoID},
+		&actions_model.ActionOutboxEvent{RepoID: repoID},
+		&actions_model.ActionTaskSummary{RepoID: repoID},
+		&actions_model.ActionDispatchPreset{RepoID: repoID},
+		&actions_model.ActionRunFilter{RepoID: repoID},
+		&actions_model.ActionEgressViolation{RepoID: repoID},
+		&actions_model.ActionConfigRevision{RepoID: repoID},
+		&actions_model.ActionDeadLetter{RepoID: repoID},
+		&actions_model.ActionRunCreationFailure{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %w", err)
 	}
@@ -329,6 +347,10 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 
 	// Finally, delete action logs after the actions have already been deleted to avoid new log files
 	for _, task := range tasks {
+		if task.LogExpired {
+			// the logs have been removed by the log retention
+			continue
+		}
 		err := actions_module.RemoveLogs(ctx, task.LogInStorage, task.LogFilename)
 		if err != nil {
 			log.Error("remove log file %q: %v", task.LogFilename, err)
@@ -344,6 +366,14 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		}
 	}
 
+	// delete actions handoff blobs in ObjectStorage after the repo have already been deleted
+	for _, blob := range handoffBlobs {
+		if err := storage.ActionsArtifacts.Delete(blob.StoragePath); err != nil {
+			log.Error("remove handoff blob file %q: %v", blob.StoragePath, err)
+			// go on
+		}
+	}
+
 	return nil
 }
 
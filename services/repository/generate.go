@@ -51,20 +51,30 @@ var defaultTransformers = []transformer{
 	{Name: "TITLE", Transform: util.ToTitleCase},
 }
 
-func generateExpansion(src string, templateRepo, generateRepo *repo_model.Repository, sanitizeFileName bool) string {
+// getExpansionMap returns the values of the variables of the repository, templateRepo is nil if it's not generated from a template
+func getExpansionMap(templateRepo, generateRepo *repo_model.Repository) map[string]string {
+	defaultBranch := generateRepo.DefaultBranch
+	if strings.TrimSpace(defaultBranch) == "" && templateRepo != nil {
+		defaultBranch = templateRepo.DefaultBranch
+	}
 	expansions := []expansion{
 		{Name: "REPO_NAME", Value: generateRepo.Name, Transformers: defaultTransformers},
-		{Name: "TEMPLATE_NAME", Value: templateRepo.Name, Transformers: defaultTransformers},
 		{Name: "REPO_DESCRIPTION", Value: generateRepo.Description, Transformers: nil},
-		{Name: "TEMPLATE_DESCRIPTION", Value: templateRepo.Description, Transformers: nil},
 		{Name: "REPO_OWNER", Value: generateRepo.OwnerName, Transformers: defaultTransformers},
-		{Name: "TEMPLATE_OWNER", Value: templateRepo.OwnerName, Transformers: defaultTransformers},
+		{Name: "REPO_DEFAULT_BRANCH", Value: defaultBranch, Transformers: nil},
 		{Name: "REPO_LINK", Value: generateRepo.Link(), Transformers: nil},
-		{Name: "TEMPLATE_LINK", Value: templateRepo.Link(), Transformers: nil},
 		{Name: "REPO_HTTPS_URL", Value: generateRepo.CloneLink().HTTPS, Transformers: nil},
-		{Name: "TEMPLATE_HTTPS_URL", Value: templateRepo.CloneLink().HTTPS, Transformers: nil},
 		{Name: "REPO_SSH_URL", Value: generateRepo.CloneLink().SSH, Transformers: nil},
-		{Name: "TEMPLATE_SSH_URL", Value: templateRepo.CloneLink().SSH, Transformers: nil},
+	}
+	if templateRepo != nil {
+		expansions = append(expansions,
+			expansion{Name: "TEMPLATE_NAME", Value: templateRepo.Name, Transformers: defaultTransformers},
+			expansion{Name: "TEMPLATE_DESCRIPTION", Value: templateRepo.Description, Transformers: nil},
+			expansion{Name: "TEMPLATE_OWNER", Value: templateRepo.OwnerName, Transformers: defaultTransformers},
+			expansion{Name: "TEMPLATE_LINK", Value: templateRepo.Link(), Transformers: nil},
+			expansion{Name: "TEMPLATE_HTTPS_URL", Value: templateRepo.CloneLink().HTTPS, Transformers: nil},
+			expansion{Name: "TEMPLATE_SSH_URL", Value: templateRepo.CloneLink().SSH, Transformers: nil},
+		)
 	}
 
 	expansionMap := make(map[string]string)
@@ -74,7 +84,33 @@ func generateExpansion(src string, templateRepo, generateRepo *repo_model.Reposi
 			expansionMap[fmt.Sprintf("%s_%s", e.Name, tr.Name)] = tr.Transform(e.Value)
 		}
 	}
+	return expansionMap
+}
+
+func generateExpansion(src string, templateRepo, generateRepo *repo_model.Repository, sanitizeFileName bool) string {
+	return expandWithMap(src, getExpansionMap(templateRepo, generateRepo), sanitizeFileName)
+}
+
+// workflowExpressionPattern matches the expressions like ${{ github.sha }} in workflows
+var workflowExpressionPattern = regexp.MustCompile(`\$\{\{.*?\}\}`)
+
+// generateWorkflowExpansion expands the variables in a workflow like generateExpansion,
+// but keeps the expressions like ${{ github.sha }} as they are, since they would be broken by the expansion.
+func generateWorkflowExpansion(src string, templateRepo, generateRepo *repo_model.Repository) string {
+	expansionMap := getExpansionMap(templateRepo, generateRepo)
+
+	sb := &strings.Builder{}
+	last := 0
+	for _, loc := range workflowExpressionPattern.FindAllStringIndex(src, -1) {
+		sb.WriteString(expandWithMap(src[last:loc[0]], expansionMap, false))
+		sb.WriteString(src[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(expandWithMap(src[last:], expansionMap, false))
+	return sb.String()
+}
 
+func expandWithMap(src string, expansionMap map[string]string, sanitizeFileName bool) string {
 	return os.Expand(src, func(key string) string {
 		if expansion, ok := expansionMap[key]; ok {
 			if sanitizeFileName {
@@ -196,9 +232,11 @@ func generateRepoCommit(ctx context.Context, repo, templateRepo, generateRepo *r
 							return err
 						}
 
-						if err := os.WriteFile(path,
-							[]byte(generateExpansion(string(content), templateRepo, generateRepo, false)),
-							0o644); err != nil {
+						expanded := generateExpansion(string(content), templateRepo, generateRepo, false)
+						if isWorkflowFile(base) {
+							expanded = generateWorkflowExpansion(string(content), templateRepo, generateRepo)
+						}
+						if err := os.WriteFile(path, []byte(expanded), 0o644); err != nil {
 							return err
 						}
 
@@ -225,6 +263,10 @@ func generateRepoCommit(ctx context.Context, repo, templateRepo, generateRepo *r
 		}
 	}
 
+	if err := writeDefaultWorkflows(tmpDir, generateRepo); err != nil {
+		return fmt.Errorf("writeDefaultWorkflows: %w", err)
+	}
+
 	if err := git.InitRepository(ctx, tmpDir, false, templateRepo.ObjectFormatName); err != nil {
 		return err
 	}
@@ -311,6 +353,9 @@ type GenerateRepoOptions struct {
 	Avatar          bool
 	IssueLabels     bool
 	ProtectedBranch bool
+	// WorkflowSchedules registers the schedules of the workflows in the git content immediately,
+	// it's not an item to generate since it requires GitContent
+	WorkflowSchedules bool
 }
 
 // IsValid checks whether at least one option is chosen for generation
@@ -6,6 +6,8 @@ package repository
 import (
 	"testing"
 
+	repo_model "code.gitea.io/gitea/models/repo"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,3 +67,12 @@ func TestFileNameSanitize(t *testing.T) {
 	assert.Equal(t, "_", fileNameSanitize("\u0000"))
 	assert.Equal(t, "目标", fileNameSanitize("目标"))
 }
+
+func TestGenerateWorkflowExpansion(t *testing.T) {
+	templateRepo := &repo_model.Repository{Name: "template", OwnerName: "my-org", DefaultBranch: "main"}
+	generateRepo := &repo_model.Repository{Name: "my-repo", OwnerName: "my-org"}
+
+	assert.Equal(t, "name: build my-repo on main", generateWorkflowExpansion("name: build $REPO_NAME on ${REPO_DEFAULT_BRANCH}", templateRepo, generateRepo))
+	assert.Equal(t, "run: echo ${{ github.sha }} my_repo", generateWorkflowExpansion("run: echo ${{ github.sha }} $REPO_NAME_SNAKE", templateRepo, generateRepo))
+	assert.Equal(t, "run: echo $HOME", generateWorkflowExpansion("run: echo $$HOME", templateRepo, generateRepo))
+}
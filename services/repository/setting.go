@@ -32,6 +32,9 @@ func UpdateRepositoryUnits(ctx context.Context, repo *repo_model.Repository, uni
 		if err := actions_model.CleanRepoScheduleTasks(ctx, repo); err != nil {
 			log.Error("CleanRepoScheduleTasks: %v", err)
 		}
+		if err := actions_model.ReplaceActionUsages(ctx, repo.ID, nil); err != nil {
+			log.Error("ReplaceActionUsages: %v", err)
+		}
 	}
 
 	for _, u := range units {
@@ -39,6 +42,9 @@ func UpdateRepositoryUnits(ctx context.Context, repo *repo_model.Repository, uni
 			if err := actions_service.DetectAndHandleSchedules(ctx, repo); err != nil {
 				log.Error("DetectAndHandleSchedules: %v", err)
 			}
+			if err := actions_service.IndexActionUsages(ctx, repo); err != nil {
+				log.Error("IndexActionUsages: %v", err)
+			}
 			break
 		}
 	}
@@ -53,5 +59,20 @@ func UpdateRepositoryUnits(ctx context.Context, repo *repo_model.Repository, uni
 		}
 	}
 
+	if slices.Contains(deleteUnitTypes, unit.TypeActions) {
+		var cfg *repo_model.ActionsConfig
+		for _, u := range units {
+			if u.Type == unit.TypeActions {
+				cfg, _ = u.Config.(*repo_model.ActionsConfig)
+				if cfg == nil {
+					cfg = &repo_model.ActionsConfig{}
+				}
+			}
+		}
+		if err := actions_model.RecordConfigRevision(ctx, repo.ID, cfg); err != nil {
+			return err
+		}
+	}
+
 	return committer.Commit()
 }
@@ -10,7 +10,10 @@ import (
 	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	actions_service "code.gitea.io/gitea/services/actions"
 	notify_service "code.gitea.io/gitea/services/notify"
 )
 
@@ -131,5 +134,17 @@ func GenerateRepository(ctx context.Context, doer, owner *user_model.User, templ
 
 	notify_service.CreateRepository(ctx, doer, owner, generateRepo)
 
+	if opts.WorkflowSchedules && opts.GitContent && !templateRepo.IsEmpty {
+		// re-fetch the repo since its default branch could be changed by the git content
+		repo, err := repo_model.GetRepositoryByID(ctx, generateRepo.ID)
+		if err != nil {
+			log.Error("GetRepositoryByID: %v", err)
+		} else if repo.UnitEnabled(ctx, unit.TypeActions) {
+			if err := actions_service.DetectAndHandleSchedules(ctx, repo); err != nil {
+				log.Error("DetectAndHandleSchedules for generated repo %s: %v", repo.FullName(), err)
+			}
+		}
+	}
+
 	return generateRepo, nil
 }
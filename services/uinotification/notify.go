@@ -6,6 +6,7 @@ package uinotification
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -27,6 +28,7 @@ type (
 	issueNotificationOpts struct {
 		IssueID              int64
 		CommentID            int64
+		RunID                int64 // the notification is about an actions run instead of an issue
 		NotificationAuthorID int64
 		ReceiverID           int64 // 0 -- ALL Watcher
 	}
@@ -52,6 +54,12 @@ func NewNotifier() notify_service.Notifier {
 
 func handler(items ...issueNotificationOpts) []issueNotificationOpts {
 	for _, opts := range items {
+		if opts.RunID != 0 {
+			if err := activities_model.CreateOrUpdateActionRunNotifications(db.DefaultContext, opts.RunID, opts.NotificationAuthorID, opts.ReceiverID); err != nil {
+				log.Error("Was unable to create actions run notification: %v", err)
+			}
+			continue
+		}
 		if err := activities_model.CreateOrUpdateIssueNotifications(db.DefaultContext, opts.IssueID, opts.CommentID, opts.NotificationAuthorID, opts.ReceiverID); err != nil {
 			log.Error("Was unable to create issue notification: %v", err)
 		}
@@ -259,3 +267,17 @@ func (ns *notificationService) RepoPendingTransfer(ctx context.Context, doer, ne
 		log.Error("CreateRepoTransferNotification: %v", err)
 	}
 }
+
+func (ns *notificationService) CreateActionRunComment(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, comment *actions_model.ActionRunComment, mentions []*user_model.User) {
+	_ = ns.issueQueue.Push(issueNotificationOpts{
+		RunID:                run.ID,
+		NotificationAuthorID: doer.ID,
+	})
+	for _, mention := range mentions {
+		_ = ns.issueQueue.Push(issueNotificationOpts{
+			RunID:                run.ID,
+			NotificationAuthorID: doer.ID,
+			ReceiverID:           mention.ID,
+		})
+	}
+}
@@ -170,6 +170,13 @@ func (dc dingtalkConvertor) Package(p *api.PackagePayload) (DingtalkPayload, err
 	return createDingtalkPayload(text, text, "view package", p.Package.HTMLURL), nil
 }
 
+func (dc dingtalkConvertor) WorkflowRun(p *api.WorkflowRunPayload) (DingtalkPayload, error) {
+	title, _ := getWorkflowRunPayloadInfo(p, noneLinkFormatter, true)
+	text, _ := getWorkflowRunPayloadText(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(title, text, "view workflow run", p.WorkflowRun.HTMLURL), nil
+}
+
 func createDingtalkPayload(title, text, singleTitle, singleURL string) DingtalkPayload {
 	return DingtalkPayload{
 		MsgType: "actionCard",
@@ -253,6 +253,24 @@ func (d discordConvertor) Package(p *api.PackagePayload) (DiscordPayload, error)
 	return d.createPayload(p.Sender, text, "", p.Package.HTMLURL, color), nil
 }
 
+func (d discordConvertor) WorkflowRun(p *api.WorkflowRunPayload) (DiscordPayload, error) {
+	title, color := getWorkflowRunPayloadInfo(p, noneLinkFormatter, false)
+
+	payload := d.createPayload(p.Sender, title, "", p.WorkflowRun.HTMLURL, color)
+	if jobs := getWorkflowRunFailedJobs(p, discordLinkFormatter); len(jobs) > 0 {
+		payload.Embeds[0].Fields = append(payload.Embeds[0].Fields, DiscordEmbedField{
+			Name:  "Failed jobs",
+			Value: strings.Join(jobs, "\n"),
+		})
+	}
+	return payload, nil
+}
+
+// discordLinkFormatter creates a markdown link
+func discordLinkFormatter(url, text string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
 type discordConvertor struct {
 	Username  string
 	AvatarURL string
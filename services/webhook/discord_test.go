@@ -199,6 +199,22 @@ func TestDiscordPayload(t *testing.T) {
 		assert.Equal(t, p.Sender.AvatarURL, pl.Embeds[0].Author.IconURL)
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := dc.WorkflowRun(p)
+		require.NoError(t, err)
+
+		assert.Len(t, pl.Embeds, 1)
+		assert.Equal(t, "[test/repo] Workflow run ci.yml #12 failed in 1m30s: Fix the build", pl.Embeds[0].Title)
+		assert.Equal(t, "http://localhost:3000/test/repo/actions/runs/12", pl.Embeds[0].URL)
+		assert.Equal(t, redColor, pl.Embeds[0].Color)
+		require.Len(t, pl.Embeds[0].Fields, 1)
+		assert.Equal(t, "Failed jobs", pl.Embeds[0].Fields[0].Name)
+		assert.Equal(t, "[test](http://localhost:3000/test/repo/actions/runs/12/jobs/1)\n[deploy](http://localhost:3000/test/repo/actions/runs/12/jobs/2)", pl.Embeds[0].Fields[0].Value)
+		assert.Equal(t, p.Sender.UserName, pl.Embeds[0].Author.Name)
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
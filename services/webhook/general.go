@@ -8,7 +8,9 @@ import (
 	"html"
 	"net/url"
 	"strings"
+	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	webhook_model "code.gitea.io/gitea/models/webhook"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -311,6 +313,72 @@ func getPackagePayloadInfo(p *api.PackagePayload, linkFormatter linkFormatter, w
 	return text, color
 }
 
+func getWorkflowRunPayloadInfo(p *api.WorkflowRunPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	run := p.WorkflowRun
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	runLink := linkFormatter(run.HTMLURL, fmt.Sprintf("%s #%d", run.WorkflowID, run.RunNumber))
+
+	var outcome string
+	switch run.Status {
+	case "success":
+		outcome = "succeeded"
+		color = greenColor
+	case "failure":
+		outcome = "failed"
+		color = redColor
+	case "cancelled", "skipped":
+		outcome = "was " + run.Status
+		color = greyColor
+	default:
+		outcome = run.Status
+		color = yellowColor
+	}
+	text = fmt.Sprintf("[%s] Workflow run %s %s", repoLink, runLink, outcome)
+	if duration := getWorkflowRunDuration(p); duration > 0 {
+		text += " in " + duration.String()
+	}
+	text += ": " + run.Title
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+// getWorkflowRunDuration returns how long the workflow run took, zero if it never started
+func getWorkflowRunDuration(p *api.WorkflowRunPayload) time.Duration {
+	run := p.WorkflowRun
+	if run.Started.IsZero() || run.Stopped.Before(run.Started) {
+		return 0
+	}
+	return run.Stopped.Sub(run.Started).Round(time.Second)
+}
+
+// getWorkflowRunFailedJobs returns the links to the logs of the jobs which failed or were cancelled
+func getWorkflowRunFailedJobs(p *api.WorkflowRunPayload, linkFormatter linkFormatter) []string {
+	var jobs []string
+	for i, job := range p.WorkflowRun.Jobs {
+		if job.Status != "failure" && job.Status != "cancelled" {
+			continue
+		}
+		link := p.WorkflowRun.HTMLURL + actions_model.JobPath(i)
+		if job.ExternalURL != "" {
+			link = job.ExternalURL
+		}
+		jobs = append(jobs, linkFormatter(link, job.Name))
+	}
+	return jobs
+}
+
+// getWorkflowRunPayloadText returns the outcome of the workflow run with its failed jobs in lines
+func getWorkflowRunPayloadText(p *api.WorkflowRunPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	text, color = getWorkflowRunPayloadInfo(p, linkFormatter, withSender)
+	if jobs := getWorkflowRunFailedJobs(p, linkFormatter); len(jobs) > 0 {
+		text += "\nFailed jobs: " + strings.Join(jobs, ", ")
+	}
+	return text, color
+}
+
 // ToHook convert models.Webhook to api.Hook
 // This function is not part of the convert package to prevent an import cycle
 func ToHook(repoLink string, w *webhook_model.Webhook) (*api.Hook, error) {
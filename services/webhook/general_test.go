@@ -5,6 +5,7 @@ package webhook
 
 import (
 	"testing"
+	"time"
 
 	api "code.gitea.io/gitea/modules/structs"
 
@@ -333,6 +334,58 @@ func packageTestPayload() *api.PackagePayload {
 	}
 }
 
+func workflowRunTestPayload() *api.WorkflowRunPayload {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return &api.WorkflowRunPayload{
+		Action: api.HookWorkflowRunCompleted,
+		WorkflowRun: &api.ActionRun{
+			ID:         1,
+			RunNumber:  12,
+			Title:      "Fix the build",
+			WorkflowID: "ci.yml",
+			Status:     "failure",
+			HTMLURL:    "http://localhost:3000/test/repo/actions/runs/12",
+			Jobs: []*api.ActionRunJob{
+				{ID: 1, Name: "lint", Status: "success"},
+				{ID: 2, Name: "test", Status: "failure"},
+				{ID: 3, Name: "deploy", Status: "cancelled"},
+			},
+			Started: started,
+			Stopped: started.Add(90 * time.Second),
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+	}
+}
+
+func TestGetWorkflowRunPayloadInfo(t *testing.T) {
+	p := workflowRunTestPayload()
+
+	text, color := getWorkflowRunPayloadText(p, noneLinkFormatter, true)
+	assert.Equal(t, "[test/repo] Workflow run ci.yml #12 failed in 1m30s: Fix the build by user1\nFailed jobs: test, deploy", text)
+	assert.Equal(t, redColor, color)
+
+	p.WorkflowRun.Status = "success"
+	p.WorkflowRun.Jobs = p.WorkflowRun.Jobs[:1]
+	text, color = getWorkflowRunPayloadText(p, noneLinkFormatter, false)
+	assert.Equal(t, "[test/repo] Workflow run ci.yml #12 succeeded in 1m30s: Fix the build", text)
+	assert.Equal(t, greenColor, color)
+
+	// the run is cancelled before starting
+	p.WorkflowRun.Status = "cancelled"
+	p.WorkflowRun.Started = time.Time{}
+	text, color = getWorkflowRunPayloadText(p, noneLinkFormatter, false)
+	assert.Equal(t, "[test/repo] Workflow run ci.yml #12 was cancelled: Fix the build", text)
+	assert.Equal(t, greyColor, color)
+}
+
 func TestGetIssuesPayloadInfo(t *testing.T) {
 	p := issueTestPayload()
 
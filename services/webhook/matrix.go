@@ -227,6 +227,16 @@ func (m matrixConvertor) Repository(p *api.RepositoryPayload) (MatrixPayload, er
 	return m.newPayload(text)
 }
 
+// WorkflowRun implements payloadConvertor WorkflowRun method
+func (m matrixConvertor) WorkflowRun(p *api.WorkflowRunPayload) (MatrixPayload, error) {
+	text, _ := getWorkflowRunPayloadInfo(p, htmlLinkFormatter, true)
+	if jobs := getWorkflowRunFailedJobs(p, htmlLinkFormatter); len(jobs) > 0 {
+		text += "<br>Failed jobs: " + strings.Join(jobs, ", ")
+	}
+
+	return m.newPayload(text)
+}
+
 func (m matrixConvertor) Package(p *api.PackagePayload) (MatrixPayload, error) {
 	senderLink := htmlLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
 	packageLink := htmlLinkFormatter(p.Package.HTMLURL, p.Package.Name)
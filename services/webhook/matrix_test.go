@@ -152,6 +152,17 @@ func TestMatrixPayload(t *testing.T) {
 		assert.Equal(t, `[<a href="http://localhost:3000/user1/-/packages/container/GiteaContainer/latest">GiteaContainer</a>] Package published by <a href="https://try.gitea.io/user1">user1</a>`, pl.FormattedBody)
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := mc.WorkflowRun(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+
+		assert.Equal(t, "[[test/repo](http://localhost:3000/test/repo)] Workflow run [ci.yml #12](http://localhost:3000/test/repo/actions/runs/12) failed in 1m30s: Fix the build by [user1](https://try.gitea.io/user1)\nFailed jobs: [test](http://localhost:3000/test/repo/actions/runs/12/jobs/1), [deploy](http://localhost:3000/test/repo/actions/runs/12/jobs/2)", pl.Body)
+		assert.Equal(t, `[<a href="http://localhost:3000/test/repo">test/repo</a>] Workflow run <a href="http://localhost:3000/test/repo/actions/runs/12">ci.yml #12</a> failed in 1m30s: Fix the build by <a href="https://try.gitea.io/user1">user1</a><br>Failed jobs: <a href="http://localhost:3000/test/repo/actions/runs/12/jobs/1">test</a>, <a href="http://localhost:3000/test/repo/actions/runs/12/jobs/2">deploy</a>`, pl.FormattedBody)
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
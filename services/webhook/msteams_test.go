@@ -5,6 +5,7 @@ package webhook
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	webhook_model "code.gitea.io/gitea/models/webhook"
@@ -325,6 +326,32 @@ func TestMSTeamsPayload(t *testing.T) {
 		assert.Equal(t, "http://localhost:3000/user1/-/packages/container/GiteaContainer/latest", pl.PotentialAction[0].Targets[0].URI)
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := mc.WorkflowRun(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, "[test/repo] Workflow run ci.yml #12 failed in 1m30s: Fix the build", pl.Title)
+		assert.Equal(t, "[test/repo] Workflow run ci.yml #12 failed in 1m30s: Fix the build", pl.Summary)
+		assert.Equal(t, fmt.Sprintf("%x", redColor), pl.ThemeColor)
+		assert.Len(t, pl.Sections, 1)
+		assert.Equal(t, "user1", pl.Sections[0].ActivitySubtitle)
+		assert.Len(t, pl.Sections[0].Facts, 2)
+		for _, fact := range pl.Sections[0].Facts {
+			if fact.Name == "Repository:" {
+				assert.Equal(t, p.Repository.FullName, fact.Value)
+			} else if fact.Name == "Failed jobs:" {
+				assert.Equal(t, "test, deploy", fact.Value)
+			} else {
+				t.Fail()
+			}
+		}
+		assert.Len(t, pl.PotentialAction, 1)
+		assert.Len(t, pl.PotentialAction[0].Targets, 1)
+		assert.Equal(t, "http://localhost:3000/test/repo/actions/runs/12", pl.PotentialAction[0].Targets[0].URI)
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
@@ -6,6 +6,7 @@ package webhook
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
@@ -887,3 +888,20 @@ func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_mo
 		log.Error("PrepareWebhooks: %v", err)
 	}
 }
+
+func (m *webhookNotifier) ActionRunCompleted(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) {
+	apiRun, err := convert.ToActionRun(ctx, run, jobs)
+	if err != nil {
+		log.Error("ToActionRun: %v", err)
+		return
+	}
+
+	if err := PrepareWebhooks(ctx, EventSource{Repository: run.Repo}, webhook_module.HookEventWorkflowRun, &api.WorkflowRunPayload{
+		Action:      api.HookWorkflowRunCompleted,
+		WorkflowRun: apiRun,
+		Repository:  convert.ToRepo(ctx, run.Repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Sender:      convert.ToUser(ctx, run.TriggerUser, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
@@ -167,6 +167,22 @@ func (s slackConvertor) Package(p *api.PackagePayload) (SlackPayload, error) {
 	return s.createPayload(text, nil), nil
 }
 
+// WorkflowRun implements payloadConvertor WorkflowRun method
+func (s slackConvertor) WorkflowRun(p *api.WorkflowRunPayload) (SlackPayload, error) {
+	text, color := getWorkflowRunPayloadInfo(p, SlackLinkFormatter, true)
+
+	var attachments []SlackAttachment
+	if jobs := getWorkflowRunFailedJobs(p, SlackLinkFormatter); len(jobs) > 0 {
+		attachments = append(attachments, SlackAttachment{
+			Color: fmt.Sprintf("%x", color),
+			Title: "Failed jobs",
+			Text:  strings.Join(jobs, "\n"),
+		})
+	}
+
+	return s.createPayload(text, attachments), nil
+}
+
 // Push implements payloadConvertor Push method
 func (s slackConvertor) Push(p *api.PushPayload) (SlackPayload, error) {
 	// n new commits
@@ -126,6 +126,18 @@ func TestSlackPayload(t *testing.T) {
 		assert.Equal(t, "Package created: <http://localhost:3000/user1/-/packages/container/GiteaContainer/latest|GiteaContainer:latest> by <https://try.gitea.io/user1|user1>", pl.Text)
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := sc.WorkflowRun(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Workflow run <http://localhost:3000/test/repo/actions/runs/12|ci.yml #12> failed in 1m30s: Fix the build by <https://try.gitea.io/user1|user1>", pl.Text)
+		require.Len(t, pl.Attachments, 1)
+		assert.Equal(t, "Failed jobs", pl.Attachments[0].Title)
+		assert.Equal(t, "<http://localhost:3000/test/repo/actions/runs/12/jobs/1|test>\n<http://localhost:3000/test/repo/actions/runs/12/jobs/2|deploy>", pl.Attachments[0].Text)
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
@@ -9,10 +9,13 @@ import (
 	"encoding/hex"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/routers/api/actions"
 	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/tests"
@@ -222,3 +225,96 @@ func TestActionsArtifactV4Delete(t *testing.T) {
 	protojson.Unmarshal(resp.Body.Bytes(), &deleteResp)
 	assert.True(t, deleteResp.Ok)
 }
+
+func TestActionsArtifactV4CreateInvalidName(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	assert.NoError(t, err)
+
+	for _, name := range []string{"", "..", "dir/artifact", "artifact?"} {
+		req := NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/CreateArtifact", toProtoJSON(&actions.CreateArtifactRequest{
+			Version:                 4,
+			Name:                    name,
+			WorkflowRunBackendId:    "792",
+			WorkflowJobRunBackendId: "193",
+		})).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusBadRequest)
+		var twirpErr struct {
+			Code string `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		DecodeJSON(t, resp, &twirpErr)
+		assert.Equal(t, "invalid_argument", twirpErr.Code)
+		assert.Contains(t, twirpErr.Msg, "artifact name")
+	}
+}
+
+func TestActionsArtifactV4NameCollision(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	assert.NoError(t, err)
+
+	upload := func(name string, status int) *httptest.ResponseRecorder {
+		req := NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/CreateArtifact", toProtoJSON(&actions.CreateArtifactRequest{
+			Version:                 4,
+			Name:                    name,
+			WorkflowRunBackendId:    "792",
+			WorkflowJobRunBackendId: "193",
+		})).AddTokenAuth(token)
+		resp := MakeRequest(t, req, status)
+		if status != http.StatusOK {
+			return resp
+		}
+		var uploadResp actions.CreateArtifactResponse
+		protojson.Unmarshal(resp.Body.Bytes(), &uploadResp)
+		idx := strings.Index(uploadResp.SignedUploadUrl, "/twirp/")
+		body := strings.Repeat("C", 1024)
+		req = NewRequestWithBody(t, "PUT", uploadResp.SignedUploadUrl[idx:]+"&comp=block", strings.NewReader(body))
+		MakeRequest(t, req, http.StatusCreated)
+		sha := sha256.Sum256([]byte(body))
+		req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/FinalizeArtifact", toProtoJSON(&actions.FinalizeArtifactRequest{
+			Name:                    name,
+			Size:                    1024,
+			Hash:                    wrapperspb.String("sha256:" + hex.EncodeToString(sha[:])),
+			WorkflowRunBackendId:    "792",
+			WorkflowJobRunBackendId: "193",
+		})).AddTokenAuth(token)
+		return MakeRequest(t, req, http.StatusOK)
+	}
+	listNames := func(name string) []string {
+		req := NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/ListArtifacts", toProtoJSON(&actions.ListArtifactsRequest{
+			WorkflowRunBackendId:    "792",
+			WorkflowJobRunBackendId: "193",
+		})).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var listResp actions.ListArtifactsResponse
+		protojson.Unmarshal(resp.Body.Bytes(), &listResp)
+		var names []string
+		for _, a := range listResp.Artifacts {
+			if strings.HasPrefix(a.Name, name) {
+				names = append(names, a.Name)
+			}
+		}
+		return names
+	}
+
+	// rejected by default
+	upload("collision", http.StatusOK)
+	resp := upload("collision", http.StatusConflict)
+	assert.Contains(t, resp.Body.String(), "already_exists")
+
+	t.Run("Overwrite", func(t *testing.T) {
+		defer test.MockVariableValue(&setting.Actions.ArtifactNameCollision, setting.ArtifactNameCollisionOverwrite)()
+		upload("collision", http.StatusOK)
+		assert.Equal(t, []string{"collision"}, listNames("collision"))
+	})
+
+	t.Run("Suffix", func(t *testing.T) {
+		defer test.MockVariableValue(&setting.Actions.ArtifactNameCollision, setting.ArtifactNameCollisionSuffix)()
+		upload("collision", http.StatusOK)
+		upload("collision", http.StatusOK)
+		assert.ElementsMatch(t, []string{"collision", "collision-2", "collision-3"}, listNames("collision"))
+	})
+}
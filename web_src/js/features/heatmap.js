@@ -3,9 +3,12 @@ import ActivityHeatmap from '../components/ActivityHeatmap.vue';
 import {translateMonth, translateDay} from '../utils.js';
 
 export function initHeatmap() {
-  const el = document.getElementById('user-heatmap');
-  if (!el) return;
+  for (const el of document.querySelectorAll('.activity-heatmap')) {
+    initHeatmapElement(el);
+  }
+}
 
+function initHeatmapElement(el) {
   try {
     const heatmap = {};
     for (const {contributions, timestamp} of JSON.parse(el.getAttribute('data-heatmap-data'))) {
@@ -27,7 +30,7 @@ export function initHeatmap() {
         more: el.getAttribute('data-locale-more'),
         less: el.getAttribute('data-locale-less'),
       },
-      tooltipUnit: 'contributions',
+      tooltipUnit: el.getAttribute('data-tooltip-unit') || 'contributions',
       textTotalContributions: el.getAttribute('data-locale-total-contributions'),
       noDataText: el.getAttribute('data-locale-no-contributions'),
     };